@@ -110,6 +110,10 @@ func (a *Aggregator) Start(ctx context.Context) error {
 
 	metrics.Register()
 
+	if a.cfg.DebugUseBatchWitness {
+		log.Warn("DebugUseBatchWitness is enabled, the batches are executed again to generate the witness sent to the prover")
+	}
+
 	// process monitored batch verifications before starting
 	a.EthTxManager.ProcessPendingMonitoredTxs(ctx, ethTxManagerOwner, func(result ethtxmanager.MonitoredTxResult, dbTx pgx.Tx) {
 		a.handleMonitoredTxResult(result)
@@ -425,6 +429,15 @@ func (a *Aggregator) buildInputProver(ctx context.Context, batchToVerify *state.
 		}
 	}
 
+	db, contractsBytecode := map[string]string{}, map[string]string{}
+	if a.cfg.DebugUseBatchWitness {
+		witness, err := a.State.GetBatchWitness(ctx, batchToVerify.BatchNumber, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the witness of batch %d: %w", batchToVerify.BatchNumber, err)
+		}
+		db, contractsBytecode = witness.Db, witness.ContractsBytecode
+	}
+
 	inputProver := &prover.InputProver{
 		PublicInputs: &prover.PublicInputs{
 			OldStateRoot:      previousBatch.StateRoot.Bytes(),
@@ -440,8 +453,8 @@ func (a *Aggregator) buildInputProver(ctx context.Context, batchToVerify *state.
 			L1InfoTreeData:    l1InfoTreeData,
 			ForcedBlockhashL1: forcedBlockhashL1.Bytes(),
 		},
-		Db:                map[string]string{},
-		ContractsBytecode: map[string]string{},
+		Db:                db,
+		ContractsBytecode: contractsBytecode,
 	}
 
 	printInputProver(inputProver)
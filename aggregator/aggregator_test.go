@@ -1456,3 +1456,37 @@ func TestIsSynced(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildInputProverWithBatchWitness(t *testing.T) {
+	ctx := context.Background()
+	cfg := Config{
+		TxProfitabilityCheckerType: ProfitabilityAcceptAll,
+		DebugUseBatchWitness:       true,
+	}
+	stateMock := mocks.NewStateMock(t)
+	a, err := New(cfg, stateMock, mocks.NewEthTxManager(t), mocks.NewEtherman(t))
+	require.NoError(t, err)
+
+	l1InfoRoot := common.HexToHash("0x27ae5ba08d7291c96c8cbddcc148bf48a6d68c7974b94356f53754ef6171d757")
+	timestamp := time.Now()
+	batchToProve := state.Batch{BatchNumber: 23}
+	witness := &state.BatchWitness{
+		BatchNumber:       23,
+		Db:                map[string]string{"0a": "0b"},
+		ContractsBytecode: map[string]string{"0c": "6080"},
+	}
+	stateMock.On("GetBatchByNumber", ctx, uint64(22), nil).Return(&state.Batch{BatchNumber: 22}, nil)
+	stateMock.On("GetVirtualBatch", ctx, uint64(23), nil).Return(&state.VirtualBatch{BatchNumber: 23, L1InfoRoot: &l1InfoRoot, TimestampBatchEtrog: &timestamp}, nil)
+	stateMock.On("GetLeavesByL1InfoRoot", ctx, l1InfoRoot, nil).Return([]state.L1InfoTreeExitRootStorageEntry{}, nil)
+	stateMock.On("GetBatchWitness", ctx, uint64(23), nil).Return(witness, nil).Once()
+
+	// the prover gets the partial tree of the witness
+	inputProver, err := a.buildInputProver(ctx, &batchToProve)
+	require.NoError(t, err)
+	assert.Equal(t, witness.Db, inputProver.Db)
+	assert.Equal(t, witness.ContractsBytecode, inputProver.ContractsBytecode)
+
+	stateMock.On("GetBatchWitness", ctx, uint64(23), nil).Return(nil, errors.New("batch 23 is not closed")).Once()
+	_, err = a.buildInputProver(ctx, &batchToProve)
+	assert.ErrorContains(t, err, "failed to get the witness of batch 23")
+}
@@ -91,4 +91,9 @@ type Config struct {
 
 	// BatchProofL1BlockConfirmations is number of L1 blocks to consider we can generate the proof for a virtual batch
 	BatchProofL1BlockConfirmations uint64 `mapstructure:"BatchProofL1BlockConfirmations"`
+
+	// DebugUseBatchWitness sends to the prover the witness of the batches to prove, so it executes
+	// them statelessly without reading the state from the HashDB. Each batch is executed again to
+	// generate its witness, so it's only meant to debug the stateless execution of the prover
+	DebugUseBatchWitness bool `mapstructure:"DebugUseBatchWitness"`
 }
@@ -246,6 +246,36 @@ func (_m *StateMock) GetBatchProofsToAggregate(ctx context.Context, dbTx pgx.Tx)
 	return r0, r1, r2
 }
 
+// GetBatchWitness provides a mock function with given fields: ctx, batchNumber, dbTx
+func (_m *StateMock) GetBatchWitness(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.BatchWitness, error) {
+	ret := _m.Called(ctx, batchNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBatchWitness")
+	}
+
+	var r0 *state.BatchWitness
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (*state.BatchWitness, error)); ok {
+		return rf(ctx, batchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) *state.BatchWitness); ok {
+		r0 = rf(ctx, batchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.BatchWitness)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetForcedBatchParentHash provides a mock function with given fields: ctx, forcedBatchNumber, dbTx
 func (_m *StateMock) GetForcedBatchParentHash(ctx context.Context, forcedBatchNumber uint64, dbTx pgx.Tx) (common.Hash, error) {
 	ret := _m.Called(ctx, forcedBatchNumber, dbTx)
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/snapshot"
+)
+
+// bootstrap downloads the snapshot published in snapshotURL into dir, checks its last verified
+// batch against the rollup contract and restores it. The last verified batch of the restored
+// state DB is checked against the manifest and the rollup contract again, since the manifest
+// only describes the dumps and doesn't prove their content
+func bootstrap(ctx context.Context, c *config.Config, snapshotURL, dir string) error {
+	log.Infof("Bootstrapping node from snapshot %s", snapshotURL)
+	manifestFile, err := snapshot.Download(ctx, http.DefaultClient, snapshotURL, dir)
+	if err != nil {
+		log.Error("error downloading snapshot. Error: ", err)
+		return err
+	}
+	chain, err := snapshot.LoadChain(manifestFile)
+	if err != nil {
+		log.Error("error verifying snapshot. Error: ", err)
+		return err
+	}
+	manifest := chain[len(chain)-1]
+
+	etherman, err := newEtherman(*c)
+	if err != nil {
+		return err
+	}
+	if manifest.ChainID != 0 {
+		l2ChainID, err := etherman.GetL2ChainID()
+		if err != nil {
+			return err
+		}
+		if l2ChainID != manifest.ChainID {
+			return fmt.Errorf("snapshot chain ID %d doesn't match the rollup chain ID %d", manifest.ChainID, l2ChainID)
+		}
+	}
+	err = manifest.CheckVerifiedStateRoot(etherman)
+	if err != nil {
+		log.Error("error checking snapshot against L1. Error: ", err)
+		return err
+	}
+	log.Infof("Snapshot verified batch %d state root %s matches the rollup contract", manifest.VerifiedBatchNumber, manifest.VerifiedStateRoot)
+
+	return restoreFromManifest(ctx, c, filepath.Dir(manifestFile), chain, etherman)
+}
+
+// isStateDBEmpty returns true if the state DB has not synced any L1 block yet
+func isStateDBEmpty(ctx context.Context, c db.Config) (bool, error) {
+	// The genesis block is added by the synchronizer when the state is empty
+	count, err := countStateDBBlocks(ctx, c)
+	return count <= 1, err
+}
+
+// countStateDBBlocks returns the number of L1 blocks stored in the state DB, up to 2
+func countStateDBBlocks(ctx context.Context, c db.Config) (int, error) {
+	sqlDB, err := db.NewSQLDB(c)
+	if err != nil {
+		return 0, err
+	}
+	defer sqlDB.Close()
+	var exists bool
+	err = sqlDB.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = 'state' AND table_name = 'block')").Scan(&exists)
+	if err != nil || !exists {
+		return 0, err
+	}
+	var count int
+	err = sqlDB.QueryRow(ctx, "SELECT COUNT(*) FROM (SELECT 1 FROM state.block LIMIT 2) AS b").Scan(&count)
+	return count, err
+}
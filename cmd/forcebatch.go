@@ -0,0 +1,384 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	forceBatchTxFlag          = "tx"
+	forceBatchSequenceFlag    = "sequence"
+	forceBatchFromBlockFlag   = "from-block"
+	forceBatchL2RPCFlag       = "l2-rpc"
+	forceBatchNoWaitFlag      = "no-wait"
+	forceBatchWaitTimeoutFlag = "wait-timeout"
+
+	// maxForcedBatchBytes is the max length of the transactions of a forced batch allowed by the rollup smc
+	maxForcedBatchBytes = 5000
+	// forceBatchCheckInterval is the time interval to check if the forced batch has been sequenced
+	forceBatchCheckInterval = 15 * time.Second
+	// forceBatchMinedTimeout is the time to wait for the L1 txs sent to be mined
+	forceBatchMinedTimeout = 5 * time.Minute
+	// forceBatchMaxScannedBatches is the max number of L2 batches scanned to find the batch of the forced batch
+	forceBatchMaxScannedBatches = 1000
+)
+
+var forceBatchFlags = []cli.Flag{
+	&configFileFlag,
+	&yesFlag,
+	&networkFlag,
+	&customNetworkFlag,
+	&cli.StringFlag{
+		Name:     config.FlagKeyStorePath,
+		Usage:    "the path of the key store file containing the private key of the account going to sign the L1 txs",
+		Required: true,
+	},
+	&cli.StringFlag{
+		Name:     config.FlagPassword,
+		Aliases:  []string{"pw"},
+		Usage:    "the password do decrypt the key store file",
+		Required: true,
+	},
+	&cli.StringSliceFlag{
+		Name:     forceBatchTxFlag,
+		Usage:    "Raw signed L2 tx in hex to include in the forced batch, it can be repeated",
+		Required: false,
+	},
+	&cli.Uint64Flag{
+		Name:     forceBatchSequenceFlag,
+		Usage:    "Number of a forced batch to sequence on L1 with sequenceForceBatches, together with the previous ones not sequenced yet, once its force batch timeout has expired",
+		Required: false,
+	},
+	&cli.Uint64Flag{
+		Name:     forceBatchFromBlockFlag,
+		Usage:    "L1 block from which the forced batches are looked for when sequencing them",
+		Required: false,
+	},
+	&cli.StringFlag{
+		Name:     forceBatchL2RPCFlag,
+		Usage:    "URL of the L2 RPC used to report the L2 blocks of the forced batch. If it's empty the URL of the trusted sequencer is read from the smc",
+		Required: false,
+	},
+	&cli.BoolFlag{
+		Name:     forceBatchNoWaitFlag,
+		Usage:    "Don't wait for the forced batch to be sequenced",
+		Required: false,
+	},
+	&cli.DurationFlag{
+		Name:     forceBatchWaitTimeoutFlag,
+		Usage:    "Max time to wait for the forced batch to be sequenced, 0 to wait without limit",
+		Required: false,
+	},
+}
+
+func forceBatch(cliCtx *cli.Context) error {
+	c, err := config.Load(cliCtx, true)
+	if err != nil {
+		return err
+	}
+	setupLog(c.Log)
+
+	ethMan, err := newEtherman(*c)
+	if err != nil {
+		log.Fatal(err)
+		return err
+	}
+
+	// load auth from keystore file
+	auth, err := ethMan.LoadAuthFromKeyStore(cliCtx.String(config.FlagKeyStorePath), cliCtx.String(config.FlagPassword))
+	if err != nil {
+		log.Fatal(err)
+		return err
+	}
+
+	forceBatchAddress, err := ethMan.GetForceBatchAddress()
+	if err != nil {
+		return fmt.Errorf("failed to get force batch address, error: %w", err)
+	}
+	if forceBatchAddress != (common.Address{}) && forceBatchAddress != auth.From {
+		return fmt.Errorf("only %s is allowed to force and sequence forced batches in this rollup", forceBatchAddress)
+	}
+
+	if cliCtx.IsSet(forceBatchSequenceFlag) {
+		return sequenceForcedBatches(cliCtx, c, ethMan, auth.From)
+	}
+
+	return sendForcedBatch(cliCtx, c, ethMan, auth.From)
+}
+
+// sendForcedBatch forces a batch with the txs of the flags, approving the POL fee if needed, and waits until it's sequenced
+func sendForcedBatch(cliCtx *cli.Context, c *config.Config, ethMan *etherman.Client, account common.Address) error {
+	ctx := cliCtx.Context
+
+	rawTxs := cliCtx.StringSlice(forceBatchTxFlag)
+	if len(rawTxs) == 0 {
+		fmt.Println("Please, introduce the signed txs to force with --" + forceBatchTxFlag)
+		return nil
+	}
+
+	forcedBatchRaw := state.ForcedBatchRawV2{}
+	for _, rawTx := range rawTxs {
+		txBytes, err := hex.DecodeHex(rawTx)
+		if err != nil {
+			return fmt.Errorf("invalid tx %s, error: %w", rawTx, err)
+		}
+		tx := types.Transaction{}
+		err = tx.UnmarshalBinary(txBytes)
+		if err != nil {
+			return fmt.Errorf("invalid tx %s, error: %w", rawTx, err)
+		}
+		sender, err := state.GetSender(tx)
+		if err != nil {
+			return fmt.Errorf("invalid signature of tx %s, error: %w", tx.Hash(), err)
+		}
+		fmt.Printf("Tx %s from %s, nonce: %d\n", tx.Hash(), sender, tx.Nonce())
+		forcedBatchRaw.Transactions = append(forcedBatchRaw.Transactions, state.L2TxRaw{
+			Tx:                   tx,
+			EfficiencyPercentage: state.MaxEffectivePercentage,
+		})
+	}
+
+	transactions, err := state.EncodeForcedBatchV2(&forcedBatchRaw)
+	if err != nil {
+		return fmt.Errorf("failed to encode forced batch, error: %w", err)
+	}
+	if len(transactions) > maxForcedBatchBytes {
+		return fmt.Errorf("forced batch size %d exceeds the max size %d allowed by the smc", len(transactions), maxForcedBatchBytes)
+	}
+
+	fee, err := ethMan.GetForcedBatchFee()
+	if err != nil {
+		return fmt.Errorf("failed to get forced batch fee, error: %w", err)
+	}
+
+	if !cliCtx.Bool(config.FlagYes) {
+		fmt.Print("*WARNING* Are you sure you want to force a batch with ", len(forcedBatchRaw.Transactions), " txs paying ", fee.String(),
+			" tokens (in wei) to the smc <Name: PoE. Address: "+c.NetworkConfig.L1Config.ZkEVMAddr.String()+">? [y/N]: ")
+		if !askConfirmation() {
+			return nil
+		}
+	}
+
+	allowance, err := ethMan.GetPolAllowance(account)
+	if err != nil {
+		return fmt.Errorf("failed to get POL allowance, error: %w", err)
+	}
+	if allowance.Cmp(fee) < 0 {
+		fmt.Println("Approving " + fee.String() + " tokens (in wei) to pay the forced batch fee")
+		tx, err := ethMan.ApprovePol(ctx, account, fee, c.NetworkConfig.L1Config.ZkEVMAddr)
+		if err != nil {
+			return err
+		}
+		_, err = waitL1TxMined(ctx, ethMan, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	tx, err := ethMan.ForceBatch(ctx, account, transactions, fee)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Forcing batch. Tx Hash: " + tx.Hash().String())
+	receipt, err := waitL1TxMined(ctx, ethMan, tx)
+	if err != nil {
+		return err
+	}
+
+	forcedBatchNumber, err := ethMan.GetForcedBatchNumberFromReceipt(receipt)
+	if err != nil {
+		return fmt.Errorf("failed to get forced batch number from receipt of tx %s, error: %w", tx.Hash(), err)
+	}
+	forcedBatch, err := ethMan.GetForcedBatchByNumber(ctx, forcedBatchNumber, receipt.BlockNumber.Uint64())
+	if err != nil {
+		return fmt.Errorf("failed to get forced batch %d, error: %w", forcedBatchNumber, err)
+	}
+	timeout, err := ethMan.GetForceBatchTimeout()
+	if err != nil {
+		return fmt.Errorf("failed to get force batch timeout, error: %w", err)
+	}
+	deadline := forcedBatch.ForcedAt.Add(timeout)
+	fmt.Printf("Forced batch %d in L1 block %d. If it's not sequenced before %v, anyone can sequence it with --%s %d\n",
+		forcedBatchNumber, forcedBatch.BlockNumber, deadline, forceBatchSequenceFlag, forcedBatchNumber)
+
+	if cliCtx.Bool(forceBatchNoWaitFlag) {
+		return nil
+	}
+
+	return waitForcedBatchSequenced(cliCtx, ethMan, forcedBatchNumber, deadline)
+}
+
+// sequenceForcedBatches sequences on L1 the forced batches not sequenced yet until the one of the flag, once its
+// force batch timeout has expired
+func sequenceForcedBatches(cliCtx *cli.Context, c *config.Config, ethMan *etherman.Client, account common.Address) error {
+	ctx := cliCtx.Context
+	forcedBatchNumber := cliCtx.Uint64(forceBatchSequenceFlag)
+	fromBlock := cliCtx.Uint64(forceBatchFromBlockFlag)
+
+	lastForceBatchSequenced, err := ethMan.GetLastForceBatchSequenced()
+	if err != nil {
+		return fmt.Errorf("failed to get last forced batch sequenced, error: %w", err)
+	}
+	if forcedBatchNumber <= lastForceBatchSequenced {
+		fmt.Printf("Forced batch %d is already sequenced, last forced batch sequenced: %d\n", forcedBatchNumber, lastForceBatchSequenced)
+		return nil
+	}
+
+	// The forced batches must be sequenced in order, so the previous ones not sequenced yet are sequenced too
+	forcedBatches := make([]etherman.ForcedBatch, 0, forcedBatchNumber-lastForceBatchSequenced)
+	for number := lastForceBatchSequenced + 1; number <= forcedBatchNumber; number++ {
+		forcedBatch, err := ethMan.GetForcedBatchByNumber(ctx, number, fromBlock)
+		if err != nil {
+			return fmt.Errorf("failed to get forced batch %d since L1 block %d, error: %w", number, fromBlock, err)
+		}
+		forcedBatches = append(forcedBatches, *forcedBatch)
+		fromBlock = forcedBatch.BlockNumber
+	}
+
+	timeout, err := ethMan.GetForceBatchTimeout()
+	if err != nil {
+		return fmt.Errorf("failed to get force batch timeout, error: %w", err)
+	}
+	lastBlock, err := ethMan.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get last L1 block, error: %w", err)
+	}
+	deadline := forcedBatches[len(forcedBatches)-1].ForcedAt.Add(timeout)
+	if time.Unix(int64(lastBlock.Time), 0).Before(deadline) {
+		fmt.Printf("The force batch timeout of forced batch %d has not expired yet, it can be sequenced after %v\n", forcedBatchNumber, deadline)
+		return nil
+	}
+
+	if !cliCtx.Bool(config.FlagYes) {
+		fmt.Printf("*WARNING* Are you sure you want to sequence the forced batches %d to %d in the smc <Name: PoE. Address: %s>? [y/N]: ",
+			lastForceBatchSequenced+1, forcedBatchNumber, c.NetworkConfig.L1Config.ZkEVMAddr.String())
+		if !askConfirmation() {
+			return nil
+		}
+	}
+
+	tx, err := ethMan.SequenceForceBatches(ctx, account, forcedBatches)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Sequencing forced batches. Tx Hash: " + tx.Hash().String())
+	_, err = waitL1TxMined(ctx, ethMan, tx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Forced batches %d to %d sequenced\n", lastForceBatchSequenced+1, forcedBatchNumber)
+
+	return reportForcedBatchL2Blocks(cliCtx, ethMan, forcedBatchNumber)
+}
+
+// waitForcedBatchSequenced waits until the forced batch is sequenced on L1, reporting when its force batch timeout expires
+func waitForcedBatchSequenced(cliCtx *cli.Context, ethMan *etherman.Client, forcedBatchNumber uint64, deadline time.Time) error {
+	ctx := cliCtx.Context
+	if waitTimeout := cliCtx.Duration(forceBatchWaitTimeoutFlag); waitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, waitTimeout)
+		defer cancel()
+	}
+
+	fmt.Printf("Waiting for forced batch %d to be sequenced\n", forcedBatchNumber)
+	expiredReported := false
+	for {
+		lastForceBatchSequenced, err := ethMan.GetLastForceBatchSequenced()
+		if err != nil {
+			log.Warnf("failed to get last forced batch sequenced, error: %v", err)
+		} else if lastForceBatchSequenced >= forcedBatchNumber {
+			fmt.Printf("Forced batch %d sequenced\n", forcedBatchNumber)
+			return reportForcedBatchL2Blocks(cliCtx, ethMan, forcedBatchNumber)
+		}
+
+		if !expiredReported && !time.Now().Before(deadline) {
+			fmt.Printf("The force batch timeout of forced batch %d has expired, anyone can sequence it with --%s %d\n",
+				forcedBatchNumber, forceBatchSequenceFlag, forcedBatchNumber)
+			expiredReported = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("forced batch %d not sequenced yet, error: %w", forcedBatchNumber, ctx.Err())
+		case <-time.After(forceBatchCheckInterval):
+		}
+	}
+}
+
+// reportForcedBatchL2Blocks looks for the L2 batch of the forced batch in the L2 RPC and reports its L2 blocks
+func reportForcedBatchL2Blocks(cliCtx *cli.Context, ethMan *etherman.Client, forcedBatchNumber uint64) error {
+	ctx := cliCtx.Context
+	l2RPC := cliCtx.String(forceBatchL2RPCFlag)
+	if l2RPC == "" {
+		var err error
+		l2RPC, err = ethMan.GetTrustedSequencerURL()
+		if err != nil {
+			return fmt.Errorf("failed to get trusted sequencer URL, error: %w", err)
+		}
+	}
+	l2Client := client.NewClient(l2RPC)
+
+	lastBatchNumber, err := l2Client.BatchNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get last L2 batch number from %s, error: %w", l2RPC, err)
+	}
+	for batchNumber := lastBatchNumber; batchNumber > 0 && lastBatchNumber-batchNumber < forceBatchMaxScannedBatches; batchNumber-- {
+		batch, err := l2Client.BatchByNumber(ctx, new(big.Int).SetUint64(batchNumber))
+		if err != nil {
+			return fmt.Errorf("failed to get L2 batch %d from %s, error: %w", batchNumber, l2RPC, err)
+		}
+		if batch.ForcedBatchNumber == nil || uint64(*batch.ForcedBatchNumber) != forcedBatchNumber {
+			continue
+		}
+
+		l2Blocks := make([]string, 0, len(batch.Blocks))
+		for _, l2Block := range batch.Blocks {
+			if l2Block.Block != nil {
+				l2Blocks = append(l2Blocks, fmt.Sprintf("%d", uint64(l2Block.Block.Number)))
+			} else if l2Block.Hash != nil {
+				l2Blocks = append(l2Blocks, l2Block.Hash.String())
+			}
+		}
+		fmt.Printf("Forced batch %d included in L2 batch %d, L2 blocks: [%s]\n", forcedBatchNumber, batchNumber, strings.Join(l2Blocks, ", "))
+		return nil
+	}
+
+	fmt.Printf("L2 batch of forced batch %d not found in the last %d batches of %s\n", forcedBatchNumber, forceBatchMaxScannedBatches, l2RPC)
+	return nil
+}
+
+// waitL1TxMined waits for the L1 tx to be mined, returning its receipt
+func waitL1TxMined(ctx context.Context, ethMan *etherman.Client, tx *types.Transaction) (*types.Receipt, error) {
+	mined, err := ethMan.WaitTxToBeMined(ctx, tx, forceBatchMinedTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("tx %s failed, error: %w", tx.Hash(), err)
+	}
+	if !mined {
+		return nil, errors.New("tx " + tx.Hash().String() + " not mined yet")
+	}
+	return ethMan.GetTxReceipt(ctx, tx.Hash())
+}
+
+// askConfirmation reads the answer of the user to a confirmation question
+func askConfirmation() bool {
+	var input string
+	if _, err := fmt.Scanln(&input); err != nil {
+		return false
+	}
+	input = strings.ToLower(input)
+	return input == "y" || input == "yes"
+}
@@ -28,6 +28,8 @@ const (
 	L2GASPRICER = "l2gaspricer"
 	// SEQUENCE_SENDER is the sequence sender component identifier
 	SEQUENCE_SENDER = "sequence-sender"
+	// STREAM_RELAY is the data stream relay component identifier
+	STREAM_RELAY = "stream-relay"
 )
 
 const (
@@ -50,6 +52,12 @@ var (
 		Usage:    "Load default network configuration. Supported values: [`mainnet`, `testnet`, `cardona`, `custom`]",
 		Required: true,
 	}
+	optionalNetworkFlag = cli.StringFlag{
+		Name:     config.FlagNetwork,
+		Aliases:  []string{"net"},
+		Usage:    "Load default network configuration. Supported values: [`mainnet`, `testnet`, `cardona`, `custom`]. Required with --bootstrap-from",
+		Required: false,
+	}
 	customNetworkFlag = cli.StringFlag{
 		Name:     config.FlagCustomNetwork,
 		Aliases:  []string{"net-file"},
@@ -72,7 +80,7 @@ var (
 	httpAPIFlag = cli.StringSliceFlag{
 		Name:     config.FlagHTTPAPI,
 		Aliases:  []string{"ha"},
-		Usage:    fmt.Sprintf("List of JSON RPC apis to be exposed by the server: --http.api=%v,%v,%v,%v,%v,%v", jsonrpc.APIEth, jsonrpc.APINet, jsonrpc.APIDebug, jsonrpc.APIZKEVM, jsonrpc.APITxPool, jsonrpc.APIWeb3),
+		Usage:    fmt.Sprintf("List of JSON RPC apis to be exposed by the server: --http.api=%v,%v,%v,%v,%v,%v,%v,%v", jsonrpc.APIEth, jsonrpc.APINet, jsonrpc.APIDebug, jsonrpc.APIZKEVM, jsonrpc.APITxPool, jsonrpc.APIWeb3, jsonrpc.APITrace, jsonrpc.APIAdmin),
 		Required: false,
 		Value:    cli.NewStringSlice(jsonrpc.APIEth, jsonrpc.APINet, jsonrpc.APIZKEVM, jsonrpc.APITxPool, jsonrpc.APIWeb3),
 	}
@@ -87,6 +95,17 @@ var (
 		Usage:    "Indicate the output file",
 		Required: true,
 	}
+	bootstrapFromFlag = cli.StringFlag{
+		Name:     config.FlagBootstrapFrom,
+		Usage:    "URL of a snapshot manifest. The snapshot is downloaded, verified against L1 and restored before starting. On `run` it is only used if the stateDB is empty",
+		Required: false,
+	}
+	bootstrapDirFlag = cli.StringFlag{
+		Name:     config.FlagBootstrapDir,
+		Usage:    "Folder where the snapshot used to bootstrap the node is downloaded",
+		Required: false,
+		Value:    "./bootstrap",
+	}
 	documentationFileTypeFlag = cli.StringFlag{
 		Name:     config.FlagDocumentationFileType,
 		Usage:    fmt.Sprintf("Indicate the type of file to generate json-schema: %v,%v ", NODE_CONFIGFILE, NETWORK_CONFIGFILE),
@@ -116,7 +135,7 @@ func main() {
 			Aliases: []string{},
 			Usage:   "Run the zkevm-node",
 			Action:  start,
-			Flags:   append(flags, &networkFlag, &customNetworkFlag, &migrationsFlag),
+			Flags:   append(flags, &networkFlag, &customNetworkFlag, &migrationsFlag, &bootstrapFromFlag, &bootstrapDirFlag),
 		},
 		{
 			Name:    "approve",
@@ -152,6 +171,13 @@ func main() {
 				&customNetworkFlag,
 			),
 		},
+		{
+			Name:    "forceBatch",
+			Aliases: []string{"fb"},
+			Usage:   "Force a batch with signed L2 txs on L1 and wait for it to be sequenced, or sequence forced batches once their timeout has expired",
+			Action:  forceBatch,
+			Flags:   forceBatchFlags,
+		},
 		{
 			Name:    "encryptKey",
 			Aliases: []string{},
@@ -175,8 +201,8 @@ func main() {
 		{
 			Name:    "snapshot",
 			Aliases: []string{"snap"},
-			Usage:   "Snapshot the state db",
-			Action:  snapshot,
+			Usage:   "Snapshot the state, hash and pool dbs and the data stream file",
+			Action:  snapshotCmd,
 			Flags:   snapshotFlags,
 		},
 		{
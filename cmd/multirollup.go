@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/ethtxmanager"
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/0xPolygonHermez/zkevm-node/synchronizer"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// runAdditionalRollupSynchronizers starts a synchronizer for every rollup of the multi rollup config.
+// It returns the reader that shares the L1 logs between all the rollups, including the one of the
+// network config, or nil if there are no additional rollups
+func runAdditionalRollupSynchronizers(ctx context.Context, c *config.Config, mainEtherman *etherman.Client, eventLog *event.EventLog) *etherman.SharedL1Reader {
+	if len(c.Synchronizer.MultiRollup.Rollups) == 0 {
+		return nil
+	}
+	sharedL1Reader := etherman.NewSharedL1Reader(mainEtherman.EthClient, c.Synchronizer.MultiRollup.MaxCachedL1Blocks)
+	for i, rollupCfg := range c.Synchronizer.MultiRollup.Rollups {
+		cfg, err := newRollupConfig(c, rollupCfg)
+		if err != nil {
+			log.Fatalf("error loading the config of the rollup %d: %v", i, err)
+		}
+		log.Infof("Running DB migrations of the rollup %d host: %s:%s db:%s user:%s", i, cfg.State.DB.Host, cfg.State.DB.Port, cfg.State.DB.Name, cfg.State.DB.User)
+		runStateMigrations(cfg.State.DB)
+
+		rollupEtherman, err := newEtherman(*cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		l2ChainID, err := rollupEtherman.GetL2ChainID()
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Infof("Synchronizing rollup %d with chain ID %d", i, l2ChainID)
+		stateSqlDB, err := db.NewSQLDB(cfg.State.DB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		st, _ := newState(ctx, cfg, rollupEtherman, l2ChainID, stateSqlDB, eventLog, executor.RoleSynchronizer, true, true, false)
+		ethTxManagerStorage, err := ethtxmanager.NewPostgresStorage(cfg.State.DB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go runSynchronizer(*cfg, rollupEtherman, ethTxManagerStorage, st, &noPool{}, eventLog, sharedL1Reader)
+	}
+	return sharedL1Reader
+}
+
+// newRollupConfig returns the config used to synchronize an additional rollup: the config of the node
+// with the network, state DB and trusted sequencer of the rollup
+func newRollupConfig(c *config.Config, rollupCfg synchronizer.RollupConfig) (*config.Config, error) {
+	genesis, err := config.LoadGenesisFileAsString(rollupCfg.NetworkConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	networkConfig, err := config.LoadGenesisFromJSONString(genesis)
+	if err != nil {
+		return nil, err
+	}
+	if networkConfig.L1Config.RollupManagerAddr != c.NetworkConfig.L1Config.RollupManagerAddr ||
+		networkConfig.L1Config.L1ChainID != c.NetworkConfig.L1Config.L1ChainID {
+		return nil, fmt.Errorf("the rollup %s is not attached to the rollup manager %s of L1 %d",
+			networkConfig.L1Config.ZkEVMAddr, c.NetworkConfig.L1Config.RollupManagerAddr, c.NetworkConfig.L1Config.L1ChainID)
+	}
+	if rollupCfg.StateDB.Name == c.State.DB.Name && rollupCfg.StateDB.Host == c.State.DB.Host && rollupCfg.StateDB.Port == c.State.DB.Port {
+		return nil, fmt.Errorf("the state DB %s of the rollup %s is already used", rollupCfg.StateDB.Name, networkConfig.L1Config.ZkEVMAddr)
+	}
+	cfg := *c
+	cfg.NetworkConfig = networkConfig
+	cfg.State.DB = rollupCfg.StateDB
+	cfg.IsTrustedSequencer = false
+	cfg.Synchronizer.TrustedSequencerURL = rollupCfg.TrustedSequencerURL
+	cfg.Synchronizer.MultiRollup = synchronizer.MultiRollupConfig{}
+	// The record file can't be shared by several rollups
+	cfg.Synchronizer.L1RecordFile = ""
+	return &cfg, nil
+}
+
+// noPool is used by the synchronizers of the additional rollups, this node doesn't have their pool
+type noPool struct{}
+
+func (p *noPool) DeleteReorgedTransactions(ctx context.Context, txs []*ethTypes.Transaction) error {
+	log.Debugf("ignoring %d reorged txs, the pool of the rollup is not managed by this node", len(txs))
+	return nil
+}
+
+func (p *noPool) StoreTx(ctx context.Context, tx ethTypes.Transaction, ip string, isWIP bool) error {
+	return nil
+}
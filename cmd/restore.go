@@ -2,24 +2,31 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 
+	"github.com/0xPolygonHermez/zkevm-data-streamer/datastreamer"
 	"github.com/0xPolygonHermez/zkevm-node/config"
 	"github.com/0xPolygonHermez/zkevm-node/db"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/snapshot"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/pgstatestorage"
 	pg "github.com/habx/pg-commands"
 	"github.com/urfave/cli/v2"
 )
 
 const (
-	restorestateDbFlag = "inputfilestate"
-	restoreHashDbFlag  = "inputfileHash"
+	restorestateDbFlag  = "inputfilestate"
+	restoreHashDbFlag   = "inputfileHash"
+	restoreManifestFlag = "manifest"
 )
 
 var restoreFlags = []cli.Flag{
@@ -27,108 +34,244 @@ var restoreFlags = []cli.Flag{
 		Name:     restorestateDbFlag,
 		Aliases:  []string{"is"},
 		Usage:    "Input file stateDB",
-		Required: true,
+		Required: false,
 	},
 	&cli.StringFlag{
 		Name:     restoreHashDbFlag,
 		Aliases:  []string{"ih"},
 		Usage:    "Input file hashDB",
-		Required: true,
+		Required: false,
 	},
+	&cli.StringFlag{
+		Name:     restoreManifestFlag,
+		Aliases:  []string{"m"},
+		Usage:    "Snapshot manifest. All the parts of the snapshot are verified and restored",
+		Required: false,
+	},
+	&bootstrapFromFlag,
+	&bootstrapDirFlag,
+	&optionalNetworkFlag,
+	&customNetworkFlag,
 	&configFileFlag,
 }
 
+const (
+	dropStateDBSQL  = "DROP SCHEMA IF EXISTS state CASCADE; DROP TABLE IF EXISTS gorp_migrations;"
+	dropHashDBSQL   = "DROP SCHEMA IF EXISTS state CASCADE;"
+	dropPoolDBSQL   = "DROP SCHEMA IF EXISTS pool CASCADE; DROP TABLE IF EXISTS gorp_migrations;"
+	snapshotFileExt = ".sql.tar.gz"
+)
+
 func restore(ctx *cli.Context) error {
-	// Load config
-	c, err := config.Load(ctx, false)
+	// Load config. The network config is needed to check the snapshot against L1 when bootstrapping
+	c, err := config.Load(ctx, ctx.String(config.FlagBootstrapFrom) != "")
 	if err != nil {
 		return err
 	}
 	setupLog(c.Log)
+
+	if snapshotURL := ctx.String(config.FlagBootstrapFrom); snapshotURL != "" {
+		return bootstrap(ctx.Context, c, snapshotURL, ctx.String(config.FlagBootstrapDir))
+	}
+
+	if manifestFile := ctx.String(restoreManifestFlag); manifestFile != "" {
+		log.Info("Verifying snapshot ", manifestFile)
+		chain, err := snapshot.LoadChain(manifestFile)
+		if err != nil {
+			log.Error("error verifying snapshot. Error: ", err)
+			return err
+		}
+		// The network config is not loaded, so the restored state can't be checked against L1
+		return restoreFromManifest(ctx.Context, c, filepath.Dir(manifestFile), chain, nil)
+	}
+
 	inputFileStateDB := ctx.String(restorestateDbFlag)
-	if !strings.Contains(inputFileStateDB, ".sql.tar.gz") {
+	if !strings.Contains(inputFileStateDB, snapshotFileExt) {
 		return errors.New("stateDB input file must end in .sql.tar.gz")
 	}
+	inputFileHashDB := ctx.String(restoreHashDbFlag)
+	if !strings.Contains(inputFileHashDB, snapshotFileExt) {
+		return errors.New("hashDb input file must end in .sql.tar.gz")
+	}
 
-	d, err := db.NewSQLDB(c.State.DB)
+	log.Info("Restore stateDB snapshot started, please wait...")
+	err = restoreDB(ctx.Context, c.State.DB, dropStateDBSQL, inputFileStateDB)
 	if err != nil {
-		log.Error("error conecting to stateDB. Error: ", err)
+		log.Error("error restoring stateDB snapshot. Error: ", err)
 		return err
 	}
-	_, err = d.Exec(ctx.Context, "DROP SCHEMA IF EXISTS state CASCADE; DROP TABLE IF EXISTS gorp_migrations;")
+	log.Info("Restore stateDB snapshot success")
+
+	log.Info("Restore HashDB snapshot started, please wait...")
+	err = restoreDB(ctx.Context, c.HashDB, dropHashDBSQL, inputFileHashDB)
 	if err != nil {
-		log.Error("error dropping state schema or migration table. Error: ", err)
+		log.Error("error restoring hashDB snapshot. Error: ", err)
 		return err
 	}
-	port, err := strconv.Atoi(c.State.DB.Port)
+	log.Info("Restore HashDB snapshot success")
+	return nil
+}
+
+// restoreFromManifest restores the parts of a verified chain of snapshots stored in dir, and checks
+// the restored state DB matches the cut recorded in the last manifest. If l1 is set, the last verified
+// batch of the restored state DB is also checked against the rollup contract. The restored databases
+// are dropped if any check fails, so the node doesn't start from an unverified state
+func restoreFromManifest(ctx context.Context, c *config.Config, dir string, chain []*snapshot.Manifest, l1 snapshot.L1Verifier) error {
+	var err error
+	manifest := chain[len(chain)-1]
+	log.Infof("Snapshot verified: batch %d, L2 block %d, L1 block %d, %d manifest(s)", manifest.BatchNumber, manifest.L2BlockNumber, manifest.L1BlockNumber, len(chain))
+
+	if manifest.DataStream != nil && c.Sequencer.StreamServer.Filename == "" {
+		return errors.New("the snapshot contains the data stream but Sequencer.StreamServer.Filename is not configured")
+	}
+
+	dbParts := []struct {
+		kind    snapshot.PartKind
+		cfg     db.Config
+		dropSQL string
+	}{
+		{kind: snapshot.PartStateDB, cfg: c.State.DB, dropSQL: dropStateDBSQL},
+		{kind: snapshot.PartHashDB, cfg: c.HashDB, dropSQL: dropHashDBSQL},
+		{kind: snapshot.PartPoolDB, cfg: c.Pool.DB, dropSQL: dropPoolDBSQL},
+	}
+	for _, p := range dbParts {
+		part, err := manifest.GetPart(p.kind)
+		if errors.Is(err, snapshot.ErrPartNotFound) {
+			log.Infof("Snapshot doesn't contain %s, skipping", p.kind)
+			continue
+		}
+		log.Infof("Restore %s snapshot started, please wait...", p.kind)
+		err = restoreDB(ctx, p.cfg, p.dropSQL, filepath.Join(dir, part.File))
+		if err != nil {
+			log.Errorf("error restoring %s snapshot. Error: %v", p.kind, err)
+			return err
+		}
+		log.Infof("Restore %s snapshot success", p.kind)
+	}
+
+	if manifest.DataStream != nil {
+		log.Info("Restore data stream snapshot started, please wait...")
+		err = restoreDataStream(c.Sequencer.StreamServer.Filename, dir, chain)
+		if err != nil {
+			log.Error("error restoring data stream snapshot. Error: ", err)
+			return err
+		}
+		log.Info("Restore data stream snapshot success")
+	}
+
+	err = checkRestoredState(ctx, c.State.DB, manifest, l1)
 	if err != nil {
-		log.Error("error converting port to int. Error: ", err)
+		log.Error("error checking restored stateDB. Error: ", err)
+		for _, p := range dbParts {
+			if _, partErr := manifest.GetPart(p.kind); partErr != nil {
+				continue
+			}
+			if dropErr := dropDB(ctx, p.cfg, p.dropSQL); dropErr != nil {
+				log.Errorf("error dropping restored %s. Error: %v", p.kind, dropErr)
+			}
+		}
 		return err
 	}
-	restore, err := pg.NewRestore(&pg.Postgres{
-		Host:     c.State.DB.Host,
-		Port:     port,
-		DB:       c.State.DB.Name,
-		Username: c.State.DB.User,
-		Password: c.State.DB.Password,
-	})
+	log.Info("Restored stateDB matches the snapshot manifest")
+	return nil
+}
+
+// checkRestoredState reads the cut of the restored state DB and checks it matches the manifest and,
+// if l1 is set, that its last verified batch has been verified in L1 with the same state root
+func checkRestoredState(ctx context.Context, c db.Config, manifest *snapshot.Manifest, l1 snapshot.L1Verifier) error {
+	sqlDB, err := db.NewSQLDB(c)
 	if err != nil {
-		log.Error("error: ", err)
 		return err
 	}
-	params := []string{"--no-owner", "--no-acl", "--format=c"}
-	log.Info("Restore stateDB snapshot started, please wait...")
-	restoreExec := execCommand(restore, inputFileStateDB, pg.ExecOptions{StreamPrint: false}, params)
-	if restoreExec.Error != nil {
-		log.Error("error restoring stateDB snapshot. Error: ", restoreExec.Error.Err)
-		log.Debug("restoreExec.Output: ", restoreExec.Output)
+	defer sqlDB.Close()
+	restored := &snapshot.Manifest{}
+	err = readSnapshotCut(ctx, pgstatestorage.NewPostgresStorage(state.Config{}, sqlDB), restored, nil)
+	if err != nil {
 		return err
 	}
-	log.Info("Restore stateDB snapshot success")
+	err = manifest.CheckRestoredCut(restored)
+	if err != nil {
+		return err
+	}
+	if l1 == nil {
+		return nil
+	}
+	err = restored.CheckVerifiedStateRoot(l1)
+	if err != nil {
+		return err
+	}
+	log.Infof("Restored verified batch %d state root %s matches the rollup contract", restored.VerifiedBatchNumber, restored.VerifiedStateRoot)
+	return nil
+}
 
-	inputFileHashDB := ctx.String(restoreHashDbFlag)
-	if !strings.Contains(inputFileHashDB, ".sql.tar.gz") {
-		return errors.New("hashDb input file must end in .sql.tar.gz")
+// restoreDataStream rebuilds the data stream file applying the data stream parts of all
+// the snapshots of the chain, oldest first
+func restoreDataStream(fileName, dir string, chain []*snapshot.Manifest) error {
+	err := os.Remove(fileName)
+	if err != nil && !os.IsNotExist(err) {
+		return err
 	}
-	port, err = strconv.Atoi(c.HashDB.Port)
+	var header datastreamer.HeaderEntry
+	for _, m := range chain {
+		part, err := m.GetPart(snapshot.PartDataStream)
+		if errors.Is(err, snapshot.ErrPartNotFound) {
+			continue
+		}
+		header, err = snapshot.ApplyStreamPart(fileName, filepath.Join(dir, part.File), part.Offset, part.BaseSHA256)
+		if err != nil {
+			return err
+		}
+	}
+	expected := chain[len(chain)-1].DataStream
+	if header.TotalEntries != expected.TotalEntries || header.TotalLength != expected.TotalLength {
+		return fmt.Errorf("restored data stream has %d entries, expected %d", header.TotalEntries, expected.TotalEntries)
+	}
+	bookmarks, err := snapshot.RebuildStreamBookmarks(fileName)
 	if err != nil {
-		log.Error("error converting port to int. Error: ", err)
 		return err
 	}
-	d, err = db.NewSQLDB(c.HashDB)
+	log.Infof("Data stream restored with %d entries and %d bookmarks", header.TotalEntries, bookmarks)
+	return nil
+}
+
+func restoreDB(ctx context.Context, c db.Config, dropSQL, inputFile string) error {
+	err := dropDB(ctx, c, dropSQL)
 	if err != nil {
-		log.Error("error conecting to hashdb. Error: ", err)
 		return err
 	}
-	_, err = d.Exec(ctx.Context, "DROP SCHEMA IF EXISTS state CASCADE;")
+	port, err := strconv.Atoi(c.Port)
 	if err != nil {
-		log.Error("error dropping and creating state schema. Error: ", err)
 		return err
 	}
-	restore, err = pg.NewRestore(&pg.Postgres{
-		Host:     c.HashDB.Host,
+	restore, err := pg.NewRestore(&pg.Postgres{
+		Host:     c.Host,
 		Port:     port,
-		DB:       c.HashDB.Name,
-		Username: c.HashDB.User,
-		Password: c.HashDB.Password,
+		DB:       c.Name,
+		Username: c.User,
+		Password: c.Password,
 	})
 	if err != nil {
-		log.Error("error: ", err)
 		return err
 	}
-
-	log.Info("Restore HashDB snapshot started, please wait...")
-	restoreExec = execCommand(restore, inputFileHashDB, pg.ExecOptions{StreamPrint: false}, params)
+	params := []string{"--no-owner", "--no-acl", "--format=c"}
+	restoreExec := execCommand(restore, inputFile, pg.ExecOptions{StreamPrint: false}, params)
 	if restoreExec.Error != nil {
-		log.Error("error restoring hashDB snapshot. Error: ", restoreExec.Error.Err)
 		log.Debug("restoreExec.Output: ", restoreExec.Output)
-		return err
+		return restoreExec.Error.Err
 	}
-
-	log.Info("Restore HashDB snapshot success")
 	return nil
 }
 
+func dropDB(ctx context.Context, c db.Config, dropSQL string) error {
+	d, err := db.NewSQLDB(c)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	_, err = d.Exec(ctx, dropSQL)
+	return err
+}
+
 func execCommand(x *pg.Restore, filename string, opts pg.ExecOptions, params []string) pg.Result {
 	result := pg.Result{}
 	options := append(params, x.Postgres.Parse()...)
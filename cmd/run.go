@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/pprof"
 	"os"
 	"os/signal"
 	"runtime"
+	"sync"
 	"time"
 
 	datastreamerlog "github.com/0xPolygonHermez/zkevm-data-streamer/log"
@@ -20,8 +22,10 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/etherman"
 	"github.com/0xPolygonHermez/zkevm-node/ethtxmanager"
 	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/event/ndjsoneventstorage"
 	"github.com/0xPolygonHermez/zkevm-node/event/nileventstorage"
 	"github.com/0xPolygonHermez/zkevm-node/event/pgeventstorage"
+	"github.com/0xPolygonHermez/zkevm-node/event/webhookeventstorage"
 	"github.com/0xPolygonHermez/zkevm-node/gasprice"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
@@ -35,8 +39,11 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/pgstatestorage"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor/reference"
+	"github.com/0xPolygonHermez/zkevm-node/streamrelay"
 	"github.com/0xPolygonHermez/zkevm-node/synchronizer"
 	"github.com/0xPolygonHermez/zkevm-node/synchronizer/common/syncinterfaces"
+	"github.com/0xPolygonHermez/zkevm-node/synchronizer/l1_replay"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -62,6 +69,33 @@ func start(cliCtx *cli.Context) error {
 	}
 	components := cliCtx.StringSlice(config.FlagComponents)
 
+	if snapshotURL := cliCtx.String(config.FlagBootstrapFrom); snapshotURL != "" {
+		empty, err := isStateDBEmpty(cliCtx.Context, c.State.DB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if empty {
+			err = bootstrap(cliCtx.Context, c, snapshotURL, cliCtx.String(config.FlagBootstrapDir))
+			if err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			log.Info("StateDB is not empty, skipping bootstrap from snapshot")
+		}
+	}
+
+	// The state tree of the in process executor is kept in memory, so the state synced in previous
+	// runs can't be used: even the genesis block would point to a state root the tree doesn't have
+	if c.Executor.InProcess {
+		blocks, err := countStateDBBlocks(cliCtx.Context, c.State.DB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if blocks > 0 {
+			log.Fatal("Executor.InProcess keeps the state tree in memory and can't start on a non empty stateDB, drop the stateDB or use the executor of the prover")
+		}
+	}
+
 	// Only runs migration if the component is the synchronizer and if the flag is deactivated
 	if !cliCtx.Bool(config.FlagMigrations) {
 		for _, comp := range components {
@@ -100,6 +134,12 @@ func start(cliCtx *cli.Context) error {
 			log.Fatal(err)
 		}
 	}
+	if len(c.EventLog.Sinks) > 0 {
+		eventStorage, err = addEventSinks(eventStorage, c.EventLog.Sinks)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 	eventLog = event.NewEventLog(c.EventLog, eventStorage)
 
 	// Core State DB
@@ -119,7 +159,7 @@ func start(cliCtx *cli.Context) error {
 		log.Fatal(err)
 	}
 
-	st, currentForkID := newState(cliCtx.Context, c, etherman, l2ChainID, stateSqlDB, eventLog, needsExecutor, needsStateTree, false)
+	st, currentForkID := newState(cliCtx.Context, c, etherman, l2ChainID, stateSqlDB, eventLog, executor.RoleSynchronizer, needsExecutor, needsStateTree, false)
 
 	c.Aggregator.ChainID = l2ChainID
 	c.Sequencer.StreamServer.ChainID = l2ChainID
@@ -173,7 +213,7 @@ func start(cliCtx *cli.Context) error {
 				poolInstance = createPool(c.Pool, c.State.Batch.Constraints, l2ChainID, st, eventLog)
 			}
 			seq := createSequencer(*c, poolInstance, st, etherman, eventLog)
-			go seq.Start(cliCtx.Context)
+			go seq.Start(executor.WithRole(cliCtx.Context, executor.RoleSequencer))
 		case SEQUENCE_SENDER:
 			ev.Component = event.Component_Sequence_Sender
 			ev.Description = "Running sequence sender"
@@ -201,11 +241,12 @@ func start(cliCtx *cli.Context) error {
 				poolInstance.StartPollingMinSuggestedGasPrice(cliCtx.Context)
 			}
 			poolInstance.StartRefreshingBlockedAddressesPeriodically()
+			poolInstance.StartRefreshingSequencerHaltPeriodically()
 			apis := map[string]bool{}
 			for _, a := range cliCtx.StringSlice(config.FlagHTTPAPI) {
 				apis[a] = true
 			}
-			st, _ := newState(cliCtx.Context, c, etherman, l2ChainID, stateSqlDB, eventLog, needsExecutor, needsStateTree, true)
+			st, _ := newState(cliCtx.Context, c, etherman, l2ChainID, stateSqlDB, eventLog, executor.RoleRPC, needsExecutor, needsStateTree, true)
 			go runJSONRPCServer(*c, etherman, l2ChainID, poolInstance, st, apis)
 		case SYNCHRONIZER:
 			ev.Component = event.Component_Synchronizer
@@ -217,7 +258,10 @@ func start(cliCtx *cli.Context) error {
 			if poolInstance == nil {
 				poolInstance = createPool(c.Pool, c.State.Batch.Constraints, l2ChainID, st, eventLog)
 			}
-			go runSynchronizer(*c, etherman, ethTxManagerStorage, st, poolInstance, eventLog)
+			sharedL1Reader := runAdditionalRollupSynchronizers(cliCtx.Context, c, etherman, eventLog)
+			go runSynchronizer(*c, etherman, ethTxManagerStorage, st, poolInstance, eventLog, sharedL1Reader)
+			go st.StartHistoryPruning(cliCtx.Context)
+			go st.StartTraceIndexer(cliCtx.Context)
 		case ETHTXMANAGER:
 			ev.Component = event.Component_EthTxManager
 			ev.Description = "Running eth tx manager service"
@@ -237,7 +281,20 @@ func start(cliCtx *cli.Context) error {
 			if poolInstance == nil {
 				poolInstance = createPool(c.Pool, c.State.Batch.Constraints, l2ChainID, st, eventLog)
 			}
-			go runL2GasPriceSuggester(c.L2GasPriceSuggester, st, poolInstance, etherman)
+			go runL2GasPriceSuggester(c.L2GasPriceSuggester, c.State.Batch.Constraints, st, poolInstance, etherman)
+		case STREAM_RELAY:
+			c.StreamRelay.Log = datastreamerlog.Config{
+				Environment: datastreamerlog.LogEnvironment(c.Log.Environment),
+				Level:       c.Log.Level,
+				Outputs:     c.Log.Outputs,
+			}
+			ev.Component = event.Component_StreamRelay
+			ev.Description = "Running data stream relay"
+			err := eventLog.LogEvent(cliCtx.Context, ev)
+			if err != nil {
+				log.Fatal(err)
+			}
+			go runStreamRelay(cliCtx.Context, c.StreamRelay)
 		}
 	}
 
@@ -245,11 +302,49 @@ func start(cliCtx *cli.Context) error {
 		go startMetricsHttpServer(c.Metrics)
 	}
 
+	// The event storage is closed after stopping the components, so the events buffered by the sinks are written
+	if closer, ok := eventStorage.(io.Closer); ok {
+		cancelFuncs = append(cancelFuncs, func() {
+			if err := closer.Close(); err != nil {
+				log.Errorf("error closing the event storage: %v", err)
+			}
+		})
+	}
+
 	waitSignal(cancelFuncs)
 
 	return nil
 }
 
+// addEventSinks returns an event storage that logs the events in the storage and in the sinks
+func addEventSinks(storage event.Storage, sinks []event.SinkConfig) (event.Storage, error) {
+	storages := []event.Storage{storage}
+	for _, sinkCfg := range sinks {
+		var (
+			sink event.Storage
+			err  error
+		)
+		switch sinkCfg.Type {
+		case event.SinkTypeStdout:
+			sink = ndjsoneventstorage.NewNDJSONEventStorage(os.Stdout)
+		case event.SinkTypeFile:
+			sink, err = ndjsoneventstorage.NewFileEventStorage(sinkCfg.Path)
+		case event.SinkTypeWebhook:
+			sink, err = webhookeventstorage.NewWebhookEventStorage(sinkCfg.Webhook)
+		default:
+			err = fmt.Errorf("unknown event sink type %q", sinkCfg.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !sinkCfg.Filter.IsEmpty() {
+			sink = event.NewFilteredStorage(sink, sinkCfg.Filter)
+		}
+		storages = append(storages, sink)
+	}
+	return event.NewFanOutStorage(storages...), nil
+}
+
 func setupLog(c log.Config) {
 	log.Init(c)
 }
@@ -290,7 +385,10 @@ func newL2EthClient(url string) (*ethclient.Client, error) {
 	return ethClient, nil
 }
 
-func runSynchronizer(cfg config.Config, etherman *etherman.Client, ethTxManagerStorage *ethtxmanager.PostgresStorage, st *state.State, pool *pool.Pool, eventLog *event.EventLog) {
+func runSynchronizer(cfg config.Config, etherman *etherman.Client, ethTxManagerStorage *ethtxmanager.PostgresStorage, st *state.State, pool syncinterfaces.PoolInterface, eventLog *event.EventLog, sharedL1Reader *etherman.SharedL1Reader) {
+	if sharedL1Reader != nil {
+		etherman.SetSharedL1Reader(sharedL1Reader)
+	}
 	var trustedSequencerURL string
 	var err error
 	if !cfg.IsTrustedSequencer {
@@ -317,6 +415,19 @@ func runSynchronizer(cfg config.Config, etherman *etherman.Client, ethTxManagerS
 		log.Infof("skipping creating L2 ethereum client because URL is empty")
 	}
 	zkEVMClient := client.NewClient(trustedSequencerURL)
+	var (
+		ethMan   syncinterfaces.EthermanFullInterface = etherman
+		l1Writer *l1_replay.Writer
+	)
+	if cfg.Synchronizer.L1RecordFile != "" {
+		log.Infof("Recording L1 data read by the synchronizer into %s", cfg.Synchronizer.L1RecordFile)
+		l1Writer, err = l1_replay.NewWriter(cfg.Synchronizer.L1RecordFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer l1Writer.Close() //nolint:errcheck
+		ethMan = l1_replay.NewRecorder(etherman, l1Writer)
+	}
 	etherManForL1 := []syncinterfaces.EthermanFullInterface{}
 	// If synchronizer are using sequential mode, we only need one etherman client
 	if cfg.Synchronizer.L1SynchronizationMode == synchronizer.ParallelMode {
@@ -325,12 +436,18 @@ func runSynchronizer(cfg config.Config, etherman *etherman.Client, ethTxManagerS
 			if err != nil {
 				log.Fatal(err)
 			}
+			// The shared L1 reader is only used by the sequential path, the parallel workers read
+			// their own ranges from L1
+			if l1Writer != nil {
+				etherManForL1 = append(etherManForL1, l1_replay.NewRecorder(eth, l1Writer))
+				continue
+			}
 			etherManForL1 = append(etherManForL1, eth)
 		}
 	}
 	etm := ethtxmanager.New(cfg.EthTxManager, etherman, ethTxManagerStorage, st)
 	sy, err := synchronizer.NewSynchronizer(
-		cfg.IsTrustedSequencer, etherman, etherManForL1, st, pool, etm,
+		cfg.IsTrustedSequencer, ethMan, etherManForL1, st, pool, etm,
 		zkEVMClient, ethClientForL2, eventLog, cfg.NetworkConfig.Genesis, cfg.Synchronizer, cfg.Log.Environment == "development",
 	)
 	if err != nil {
@@ -403,6 +520,20 @@ func runJSONRPCServer(c config.Config, etherman *etherman.Client, chainID uint64
 		})
 	}
 
+	if _, ok := apis[jsonrpc.APITrace]; ok {
+		services = append(services, jsonrpc.Service{
+			Name:    jsonrpc.APITrace,
+			Service: jsonrpc.NewTraceEndpoints(c.RPC, st, etherman),
+		})
+	}
+
+	if _, ok := apis[jsonrpc.APIAdmin]; ok {
+		services = append(services, jsonrpc.Service{
+			Name:    jsonrpc.APIAdmin,
+			Service: jsonrpc.NewAdminEndpoints(c.RPC, st, c.Pool.EffectiveGasPrice),
+		})
+	}
+
 	if _, ok := apis[jsonrpc.APIWeb3]; ok {
 		services = append(services, jsonrpc.Service{
 			Name:    jsonrpc.APIWeb3,
@@ -415,6 +546,17 @@ func runJSONRPCServer(c config.Config, etherman *etherman.Client, chainID uint64
 	}
 }
 
+func runStreamRelay(ctx context.Context, cfg streamrelay.Config) {
+	relay, err := streamrelay.New(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = relay.Start(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
 func createSequencer(cfg config.Config, pool *pool.Pool, st *state.State, etherman *etherman.Client, eventLog *event.EventLog) *sequencer.Sequencer {
 	cfg.Sequencer.L2Coinbase = cfg.SequenceSender.L2Coinbase
 
@@ -461,9 +603,9 @@ func runAggregator(ctx context.Context, c aggregator.Config, etherman *etherman.
 }
 
 // runL2GasPriceSuggester init gas price gasPriceEstimator based on type in config.
-func runL2GasPriceSuggester(cfg gasprice.Config, state *state.State, pool *pool.Pool, etherman *etherman.Client) {
+func runL2GasPriceSuggester(cfg gasprice.Config, batchConstraints state.BatchConstraintsCfg, state *state.State, pool *pool.Pool, etherman *etherman.Client) {
 	ctx := context.Background()
-	gasprice.NewL2GasPriceSuggester(ctx, cfg, pool, etherman, state)
+	gasprice.NewL2GasPriceSuggester(ctx, cfg, batchConstraints, pool, etherman, state)
 }
 
 func waitSignal(cancelFuncs []context.CancelFunc) {
@@ -484,16 +626,60 @@ func waitSignal(cancelFuncs []context.CancelFunc) {
 	}
 }
 
-func newState(ctx context.Context, c *config.Config, etherman *etherman.Client, l2ChainID uint64, sqlDB *pgxpool.Pool, eventLog *event.EventLog, needsExecutor, needsStateTree, avoidForkIDInMemory bool) (*state.State, uint64) {
+var (
+	executorPool      *executor.ClientPool
+	executorPoolMutex sync.Mutex
+
+	// inProcessHashDB is the state tree shared by the state and the reference executor when the
+	// batches are executed in process
+	inProcessHashDB     *merkletree.MemoryHashDB
+	inProcessHashDBOnce sync.Once
+)
+
+func getInProcessHashDB() *merkletree.MemoryHashDB {
+	inProcessHashDBOnce.Do(func() {
+		log.Warn("executing the batches with the in process reference executor, the ZK counters are estimations")
+		inProcessHashDB = merkletree.NewMemoryHashDB()
+	})
+	return inProcessHashDB
+}
+
+// newExecutorClient returns a client of the executor, or of the executor pool if several executors
+// are configured. The pool is shared by all the states, sending the requests with the default role
+// if their context doesn't set another one
+func newExecutorClient(ctx context.Context, c executor.Config, defaultRole executor.Role) executor.ExecutorServiceClient {
+	if c.InProcess {
+		return reference.NewExecutor(getInProcessHashDB())
+	}
+	if len(c.Endpoints) == 0 {
+		executorClient, _, _ := executor.NewExecutorClient(ctx, c)
+		return executorClient
+	}
+
+	executorPoolMutex.Lock()
+	defer executorPoolMutex.Unlock()
+	if executorPool == nil {
+		var err error
+		executorPool, err = executor.NewClientPool(ctx, c)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	return executorPool.Client(defaultRole)
+}
+
+func newState(ctx context.Context, c *config.Config, etherman *etherman.Client, l2ChainID uint64, sqlDB *pgxpool.Pool, eventLog *event.EventLog, executorRole executor.Role, needsExecutor, needsStateTree, avoidForkIDInMemory bool) (*state.State, uint64) {
 	// Executor
 	var executorClient executor.ExecutorServiceClient
 	if needsExecutor {
-		executorClient, _, _ = executor.NewExecutorClient(ctx, c.Executor)
+		executorClient = newExecutorClient(ctx, c.Executor, executorRole)
 	}
 
 	// State Tree
 	var stateTree *merkletree.StateTree
-	if needsStateTree {
+	if needsStateTree && c.Executor.InProcess {
+		stateTree = merkletree.NewStateTree(getInProcessHashDB())
+	} else if needsStateTree {
 		stateDBClient, _, _ := merkletree.NewMTDBServiceClient(ctx, c.MTClient)
 		stateTree = merkletree.NewStateTree(stateDBClient)
 	}
@@ -510,6 +696,10 @@ func newState(ctx context.Context, c *config.Config, etherman *etherman.Client,
 		MaxLogsBlockRange:            c.RPC.MaxLogsBlockRange,
 		MaxNativeBlockHashBlockRange: c.RPC.MaxNativeBlockHashBlockRange,
 		AvoidForkIDInMemory:          avoidForkIDInMemory,
+		Pruning:                      c.State.Pruning,
+		TraceIndex:                   c.State.TraceIndex,
+		TraceCache:                   c.State.TraceCache,
+		WitnessCache:                 c.State.WitnessCache,
 	}
 	stateDb := pgstatestorage.NewPostgresStorage(stateCfg, sqlDB)
 
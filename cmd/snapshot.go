@@ -1,23 +1,49 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-data-streamer/datastreamer"
 	"github.com/0xPolygonHermez/zkevm-node"
 	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/db"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/snapshot"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/pgstatestorage"
 	pg "github.com/habx/pg-commands"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/urfave/cli/v2"
 )
 
+const (
+	snapshotDataStreamBaseFlag = "datastream-base"
+	snapshotSkipPoolDBFlag     = "skip-pooldb"
+)
+
 var snapshotFlags = []cli.Flag{
 	&configFileFlag,
 	&outputFileFlag,
+	&cli.StringFlag{
+		Name:     snapshotDataStreamBaseFlag,
+		Usage:    "Manifest of a previous snapshot stored in the output folder. If set and the data stream starts with the one of that snapshot, only the data stream entries added since then are stored. The databases are always dumped in full",
+		Required: false,
+	},
+	&cli.BoolFlag{
+		Name:     snapshotSkipPoolDBFlag,
+		Usage:    "Don't include the pool DB in the snapshot",
+		Required: false,
+	},
 }
 
-func snapshot(ctx *cli.Context) error {
+func snapshotCmd(ctx *cli.Context) error {
 	// Load config
 	c, err := config.Load(ctx, false)
 	if err != nil {
@@ -26,62 +52,302 @@ func snapshot(ctx *cli.Context) error {
 	}
 	setupLog(c.Log)
 
-	port, err := strconv.Atoi(c.State.DB.Port)
+	outputDir := ctx.String(config.FlagOutputFile)
+	var (
+		base     *snapshot.Manifest
+		baseFile string
+	)
+	if ctx.String(snapshotDataStreamBaseFlag) != "" {
+		// The parts of the base snapshot are referenced by name, so it must be in the output folder
+		baseFile = filepath.Base(ctx.String(snapshotDataStreamBaseFlag))
+		chain, err := snapshot.LoadChain(filepath.Join(outputDir, baseFile))
+		if err != nil {
+			log.Error("error loading base snapshot manifest. Error: ", err)
+			return err
+		}
+		base = chain[len(chain)-1]
+	}
+
+	manifest, err := createSnapshot(ctx.Context, c, outputDir, baseFile, base, ctx.Bool(snapshotSkipPoolDBFlag))
 	if err != nil {
-		log.Error("error converting port to int. Error: ", err)
 		return err
 	}
-	dump, err := pg.NewDump(&pg.Postgres{
-		Host:     c.State.DB.Host,
-		Port:     port,
-		DB:       c.State.DB.Name,
-		Username: c.State.DB.User,
-		Password: c.State.DB.Password,
-	})
+	fileName, err := manifest.Save(outputDir)
 	if err != nil {
-		log.Error("error: ", err)
+		log.Error("error saving snapshot manifest. Error: ", err)
 		return err
 	}
-	dump.Options = append(dump.Options, "-Z 9")
+	log.Infof("Snapshot of batch %d, L2 block %d, L1 block %d success. Manifest saved in %s",
+		manifest.BatchNumber, manifest.L2BlockNumber, manifest.L1BlockNumber, filepath.Join(outputDir, fileName))
+	return nil
+}
+
+// createSnapshot dumps all the node data into outputDir. The state DB is dumped from an exported
+// postgres snapshot, that is the cut recorded in the manifest. The pool DB is dumped from a postgres
+// snapshot exported just before: the sequencer stores the txs in the state before updating their status
+// in the pool, so a tx selected in the pool dump is always in the state dump. The data stream is truncated
+// to the same cut and the hashDB is dumped afterwards: it only grows, so it contains all the nodes
+// required by the state roots of the cut. If base is set, only the data stream entries added since the
+// base snapshot are stored, the databases are always dumped in full
+func createSnapshot(ctx context.Context, c *config.Config, outputDir, baseFile string, base *snapshot.Manifest, skipPoolDB bool) (*snapshot.Manifest, error) {
+	manifest := snapshot.NewManifest(zkevm.Version, zkevm.GitRev)
+	prefix := fmt.Sprintf("%v_%v_%v", manifest.CreatedAt.Unix(), zkevm.Version, zkevm.GitRev)
+
+	var poolSnapshot *exportedDBSnapshot
+	if !skipPoolDB {
+		var err error
+		poolSnapshot, err = exportDBSnapshot(ctx, c.Pool.DB)
+		if err != nil {
+			log.Error("error exporting poolDB snapshot. Error: ", err)
+			return nil, err
+		}
+		defer poolSnapshot.close(ctx)
+	}
+	stateSnapshot, err := exportDBSnapshot(ctx, c.State.DB)
+	if err != nil {
+		log.Error("error exporting stateDB snapshot. Error: ", err)
+		return nil, err
+	}
+	defer stateSnapshot.close(ctx)
+
+	err = readSnapshotCut(ctx, pgstatestorage.NewPostgresStorage(state.Config{}, stateSnapshot.sqlDB), manifest, stateSnapshot.dbTx)
+	if err != nil {
+		log.Error("error reading the state of the snapshot. Error: ", err)
+		return nil, err
+	}
+	if base != nil && base.BatchNumber > manifest.BatchNumber {
+		return nil, fmt.Errorf("%w: base batch %d is greater than current batch %d", snapshot.ErrBaseManifestMismatch, base.BatchNumber, manifest.BatchNumber)
+	}
+
 	log.Info("StateDB snapshot is being created...")
-	dump.Path = ctx.String(config.FlagOutputFile)
-	dump.SetFileName(fmt.Sprintf(`%v_%v_%v_%v.sql.tar.gz`, dump.DB, time.Now().Unix(), zkevm.Version, zkevm.GitRev))
-	dumpExec := dump.Exec(pg.ExecOptions{StreamPrint: false})
-	if dumpExec.Error != nil {
-		log.Error("error dumping statedb. Error: ", dumpExec.Error.Err)
-		log.Debug("dumpExec.Output: ", dumpExec.Output)
+	file, err := dumpDB(c.State.DB, outputDir, prefix, "--snapshot="+stateSnapshot.id)
+	if err != nil {
+		log.Error("error dumping statedb. Error: ", err)
+		return nil, err
+	}
+	if err = manifest.AddPart(snapshot.PartStateDB, outputDir, file, 0); err != nil {
+		return nil, err
+	}
+	// The exported snapshot is only needed while pg_dump is attaching to it
+	stateSnapshot.close(ctx)
+	log.Info("StateDB snapshot success. Saved in ", file)
+
+	if poolSnapshot != nil {
+		log.Info("PoolDB snapshot is being created...")
+		file, err = dumpDB(c.Pool.DB, outputDir, prefix, "--snapshot="+poolSnapshot.id)
+		if err != nil {
+			log.Error("error dumping pooldb. Error: ", err)
+			return nil, err
+		}
+		if err = manifest.AddPart(snapshot.PartPoolDB, outputDir, file, 0); err != nil {
+			return nil, err
+		}
+		poolSnapshot.close(ctx)
+		log.Info("PoolDB snapshot success. Saved in ", file)
+	}
+
+	if c.Sequencer.StreamServer.Filename != "" {
+		log.Info("Data stream snapshot is being created...")
+		file, err = snapshotDataStream(c.Sequencer.StreamServer.Filename, outputDir, prefix, manifest, baseFile, base)
+		if err != nil {
+			log.Error("error copying data stream. Error: ", err)
+			return nil, err
+		}
+		log.Info("Data stream snapshot success. Saved in ", file)
+	}
+
+	log.Info("HashDB snapshot is being created...")
+	file, err = dumpDB(c.HashDB, outputDir, prefix)
+	if err != nil {
+		log.Error("error dumping hashdb. Error: ", err)
+		return nil, err
+	}
+	if err = manifest.AddPart(snapshot.PartHashDB, outputDir, file, 0); err != nil {
+		return nil, err
+	}
+	log.Info("HashDB snapshot success. Saved in ", file)
+
+	return manifest, nil
+}
+
+// exportedDBSnapshot is a postgres snapshot kept open by a read only transaction, so pg_dump can dump it
+type exportedDBSnapshot struct {
+	id    string
+	sqlDB *pgxpool.Pool
+	dbTx  pgx.Tx
+}
+
+// exportDBSnapshot starts a repeatable read transaction and exports its snapshot
+func exportDBSnapshot(ctx context.Context, c db.Config) (*exportedDBSnapshot, error) {
+	sqlDB, err := db.NewSQLDB(c)
+	if err != nil {
+		return nil, err
+	}
+	dbTx, err := sqlDB.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	s := &exportedDBSnapshot{sqlDB: sqlDB, dbTx: dbTx}
+	err = dbTx.QueryRow(ctx, "SELECT pg_export_snapshot()").Scan(&s.id)
+	if err != nil {
+		s.close(ctx)
+		return nil, err
+	}
+	return s, nil
+}
+
+// close releases the exported snapshot. It can be called more than once
+func (s *exportedDBSnapshot) close(ctx context.Context) {
+	if s.sqlDB == nil {
+		return
+	}
+	_ = s.dbTx.Rollback(ctx)
+	s.sqlDB.Close()
+	s.sqlDB = nil
+}
+
+func readSnapshotCut(ctx context.Context, st *pgstatestorage.PostgresStorage, manifest *snapshot.Manifest, dbTx pgx.Tx) error {
+	batchNumber, err := st.GetLastBatchNumber(ctx, dbTx)
+	if err != nil {
+		return err
+	}
+	l2Header, err := st.GetLastL2BlockHeader(ctx, dbTx)
+	if err != nil {
+		return err
+	}
+	l1Block, err := st.GetLastBlock(ctx, dbTx)
+	if err != nil {
+		return err
+	}
+	manifest.BatchNumber = batchNumber
+	manifest.L2BlockNumber = l2Header.Number.Uint64()
+	manifest.L2BlockStateRoot = l2Header.Root
+	manifest.L1BlockNumber = l1Block.BlockNumber
+
+	verifiedBatch, err := st.GetLastVerifiedBatch(ctx, dbTx)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	batch, err := st.GetBatchByNumber(ctx, verifiedBatch.BatchNumber, dbTx)
+	if err != nil {
 		return err
 	}
+	manifest.VerifiedBatchNumber = verifiedBatch.BatchNumber
+	manifest.VerifiedStateRoot = batch.StateRoot
+	return nil
+}
 
-	log.Info("StateDB snapshot success. Saved in ", dumpExec.File)
+// snapshotDataStream copies the data stream file, cut at the same point as the state DB.
+// If the data stream of base is the beginning of the data stream, only the entries added since
+// then are stored and the snapshot references base. Otherwise the full data stream is stored
+func snapshotDataStream(streamFile, outputDir, prefix string, manifest *snapshot.Manifest, baseFile string, base *snapshot.Manifest) (string, error) {
+	tmpDir, err := os.MkdirTemp(outputDir, "datastream")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir) //nolint:errcheck
 
-	port, err = strconv.Atoi(c.HashDB.Port)
+	tmpFile := filepath.Join(tmpDir, "datastream.bin")
+	_, err = snapshot.CopyStream(streamFile, tmpFile)
+	if err != nil {
+		return "", err
+	}
+	header, err := snapshot.TruncateStreamAfter(tmpFile, manifest.BatchNumber, manifest.L2BlockNumber)
+	if err != nil {
+		return "", err
+	}
+	sum, err := snapshot.StreamDataChecksum(tmpFile, header.TotalLength)
+	if err != nil {
+		return "", err
+	}
+	manifest.ChainID = header.SystemID
+	manifest.DataStream = &snapshot.DataStreamInfo{
+		TotalLength:  header.TotalLength,
+		TotalEntries: header.TotalEntries,
+		SHA256:       sum,
+	}
+
+	file := fmt.Sprintf("datastream_%v.part", prefix)
+	incremental, err := isStreamBase(tmpFile, header, base)
+	if err != nil {
+		return "", err
+	}
+	if !incremental {
+		_, err = snapshot.CreateStreamPart(tmpFile, filepath.Join(outputDir, file), 0)
+		if err != nil {
+			return "", err
+		}
+		return file, manifest.AddPart(snapshot.PartDataStream, outputDir, file, 0)
+	}
+
+	baseSum, _, err := snapshot.FileChecksum(filepath.Join(outputDir, baseFile))
+	if err != nil {
+		return "", err
+	}
+	manifest.Base = &snapshot.BaseRef{File: baseFile, SHA256: baseSum}
+	offset := base.DataStream.TotalLength
+	_, err = snapshot.CreateStreamPart(tmpFile, filepath.Join(outputDir, file), offset)
+	if err != nil {
+		return "", err
+	}
+	return file, manifest.AddStreamDeltaPart(outputDir, file, offset, base.DataStream.SHA256)
+}
+
+// isStreamBase returns true if the data stream of the base snapshot is the beginning of the data stream
+// file, checking the checksum of its data. The data stream can differ if it has been truncated or
+// rebuilt since the base snapshot was created
+func isStreamBase(streamFile string, header datastreamer.HeaderEntry, base *snapshot.Manifest) (bool, error) {
+	if base == nil {
+		return false, nil
+	}
+	if base.DataStream == nil || base.DataStream.SHA256 == "" {
+		log.Warn("the base snapshot doesn't contain the checksum of the data stream, storing the full data stream")
+		return false, nil
+	}
+	if base.DataStream.TotalEntries > header.TotalEntries || base.DataStream.TotalLength > header.TotalLength {
+		log.Warnf("the data stream has %d entries, the base snapshot %d, storing the full data stream", header.TotalEntries, base.DataStream.TotalEntries)
+		return false, nil
+	}
+	sum, err := snapshot.StreamDataChecksum(streamFile, base.DataStream.TotalLength)
+	if err != nil {
+		return false, err
+	}
+	if sum != base.DataStream.SHA256 {
+		log.Warn("the beginning of the data stream doesn't match the data stream of the base snapshot, storing the full data stream")
+		return false, nil
+	}
+	return true, nil
+}
+
+func dumpDB(c db.Config, outputDir, prefix string, options ...string) (string, error) {
+	port, err := strconv.Atoi(c.Port)
 	if err != nil {
 		log.Error("error converting port to int. Error: ", err)
-		return err
+		return "", err
 	}
-	dump, err = pg.NewDump(&pg.Postgres{
-		Host:     c.HashDB.Host,
+	dump, err := pg.NewDump(&pg.Postgres{
+		Host:     c.Host,
 		Port:     port,
-		DB:       c.HashDB.Name,
-		Username: c.HashDB.User,
-		Password: c.HashDB.Password,
+		DB:       c.Name,
+		Username: c.User,
+		Password: c.Password,
 	})
 	if err != nil {
-		log.Error("error: ", err)
-		return err
+		return "", err
 	}
 	dump.Options = append(dump.Options, "-Z 9")
-	log.Info("HashDB snapshot is being created...")
-	dump.Path = ctx.String(config.FlagOutputFile)
-	dump.SetFileName(fmt.Sprintf(`%v_%v_%v_%v.sql.tar.gz`, dump.DB, time.Now().Unix(), zkevm.Version, zkevm.GitRev))
-	dumpExec = dump.Exec(pg.ExecOptions{StreamPrint: false})
+	dump.Options = append(dump.Options, options...)
+	dump.Path = outputDir + string(filepath.Separator)
+	dump.SetFileName(fmt.Sprintf(`%v_%v.sql.tar.gz`, dump.DB, prefix))
+	start := time.Now()
+	dumpExec := dump.Exec(pg.ExecOptions{StreamPrint: false})
 	if dumpExec.Error != nil {
-		log.Error("error dumping hashdb. Error: ", dumpExec.Error.Err)
 		log.Debug("dumpExec.Output: ", dumpExec.Output)
-		return err
+		return "", dumpExec.Error.Err
 	}
-
-	log.Info("HashDB snapshot success. Saved in ", dumpExec.File)
-	return nil
+	log.Debugf("%s dumped in %v", c.Name, time.Since(start))
+	return dumpExec.File, nil
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/sequencesender"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/0xPolygonHermez/zkevm-node/streamrelay"
 	"github.com/0xPolygonHermez/zkevm-node/synchronizer"
 	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
@@ -55,6 +56,10 @@ const (
 	FlagMaxAmount = "max-amount"
 	// FlagDocumentationFileType is the flag for the choose which file generate json-schema
 	FlagDocumentationFileType = "config-file"
+	// FlagBootstrapFrom is the flag for the URL of the snapshot manifest used to bootstrap the node
+	FlagBootstrapFrom = "bootstrap-from"
+	// FlagBootstrapDir is the flag for the folder where the bootstrap snapshot is downloaded
+	FlagBootstrapDir = "bootstrap-dir"
 )
 
 /*
@@ -97,6 +102,8 @@ type Config struct {
 	Synchronizer synchronizer.Config
 	// Configuration of the sequencer service
 	Sequencer sequencer.Config
+	// Configuration of the data stream relay service
+	StreamRelay streamrelay.Config
 	// Configuration of the sequence sender service
 	SequenceSender sequencesender.Config
 	// Configuration of the aggregator service
@@ -13,6 +13,8 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/config"
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -64,6 +66,14 @@ func Test_Defaults(t *testing.T) {
 			path:          "Synchronizer.L2Synchronization.CheckLastL2BlockHashOnCloseBatch",
 			expectedValue: true,
 		},
+		{
+			path:          "Synchronizer.L1RecordFile",
+			expectedValue: "",
+		},
+		{
+			path:          "Synchronizer.MultiRollup.MaxCachedL1Blocks",
+			expectedValue: uint64(10000),
+		},
 		{
 			path:          "Synchronizer.L1BlockCheck.Enabled",
 			expectedValue: true,
@@ -95,7 +105,11 @@ func Test_Defaults(t *testing.T) {
 		},
 		{
 			path:          "Sequencer.LoadPoolTxsCheckInterval",
-			expectedValue: types.NewDuration(500 * time.Millisecond),
+			expectedValue: types.NewDuration(5 * time.Second),
+		},
+		{
+			path:          "Sequencer.ListenPoolTxsNotifications",
+			expectedValue: true,
 		},
 		{
 			path:          "Sequencer.StateConsistencyCheckInterval",
@@ -141,6 +155,10 @@ func Test_Defaults(t *testing.T) {
 			path:          "Sequencer.Finalizer.HaltOnBatchNumber",
 			expectedValue: uint64(0),
 		},
+		{
+			path:          "Sequencer.Finalizer.ScheduledHaltCheckInterval",
+			expectedValue: types.NewDuration(5 * time.Second),
+		},
 		{
 			path:          "Sequencer.Finalizer.BatchMaxDeltaTimestamp",
 			expectedValue: types.NewDuration(1800 * time.Second),
@@ -157,6 +175,22 @@ func Test_Defaults(t *testing.T) {
 			path:          "Sequencer.Finalizer.Metrics.EnableLog",
 			expectedValue: true,
 		},
+		{
+			path:          "Sequencer.Finalizer.Metrics.EGPAnalyticsWindow",
+			expectedValue: types.NewDuration(time.Hour),
+		},
+		{
+			path:          "Sequencer.Finalizer.ForcedBatchesTracker.CheckInterval",
+			expectedValue: types.NewDuration(time.Minute),
+		},
+		{
+			path:          "Sequencer.Finalizer.ForcedBatchesTracker.WarningThresholdPct",
+			expectedValue: uint32(50),
+		},
+		{
+			path:          "Sequencer.Finalizer.ForcedBatchesTracker.CriticalThresholdPct",
+			expectedValue: uint32(80),
+		},
 		{
 			path:          "Sequencer.StreamServer.Port",
 			expectedValue: uint16(0),
@@ -185,6 +219,54 @@ func Test_Defaults(t *testing.T) {
 			path:          "Sequencer.StreamServer.Enabled",
 			expectedValue: false,
 		},
+		{
+			path:          "Sequencer.StreamServer.VerifyBatches",
+			expectedValue: uint64(10),
+		},
+		{
+			path:          "Sequencer.HA.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "Sequencer.HA.NodeID",
+			expectedValue: "",
+		},
+		{
+			path:          "Sequencer.HA.LeaseDuration",
+			expectedValue: types.NewDuration(15 * time.Second),
+		},
+		{
+			path:          "Sequencer.HA.RenewInterval",
+			expectedValue: types.NewDuration(5 * time.Second),
+		},
+		{
+			path:          "Sequencer.HA.StandbyCheckInterval",
+			expectedValue: types.NewDuration(2 * time.Second),
+		},
+		{
+			path:          "StreamRelay.Upstream",
+			expectedValue: "",
+		},
+		{
+			path:          "StreamRelay.Port",
+			expectedValue: uint16(0),
+		},
+		{
+			path:          "StreamRelay.Filename",
+			expectedValue: "",
+		},
+		{
+			path:          "StreamRelay.WriteTimeout",
+			expectedValue: types.NewDuration(5 * time.Second),
+		},
+		{
+			path:          "StreamRelay.InactivityTimeout",
+			expectedValue: types.NewDuration(120 * time.Second),
+		},
+		{
+			path:          "StreamRelay.InactivityCheckInterval",
+			expectedValue: types.NewDuration(5 * time.Second),
+		},
 		{
 			path:          "SequenceSender.WaitPeriodSendSequence",
 			expectedValue: types.NewDuration(5 * time.Second),
@@ -265,6 +347,42 @@ func Test_Defaults(t *testing.T) {
 			path:          "L2GasPriceSuggester.MaxGasPriceWei",
 			expectedValue: uint64(0),
 		},
+		{
+			path:          "L2GasPriceSuggester.CleanHistoryTimeRetention",
+			expectedValue: types.NewDuration(time.Hour),
+		},
+		{
+			path:          "L2GasPriceSuggester.Congestion.TargetPendingTxs",
+			expectedValue: uint64(1000),
+		},
+		{
+			path:          "L2GasPriceSuggester.Congestion.TargetBatchFullness",
+			expectedValue: 0.5,
+		},
+		{
+			path:          "L2GasPriceSuggester.Congestion.TargetResourcesUtilisation",
+			expectedValue: 0.5,
+		},
+		{
+			path:          "L2GasPriceSuggester.Congestion.CheckBatches",
+			expectedValue: uint(10),
+		},
+		{
+			path:          "L2GasPriceSuggester.Congestion.MaxChangePerPeriod",
+			expectedValue: 0.125,
+		},
+		{
+			path:          "L2GasPriceSuggester.L1CostModel.Type",
+			expectedValue: pool.CalldataL1CostModel,
+		},
+		{
+			path:          "L2GasPriceSuggester.L1CostModel.BlobBytesPerBatch",
+			expectedValue: uint64(126976),
+		},
+		{
+			path:          "L2GasPriceSuggester.L1CostModel.BlobWeight",
+			expectedValue: 0.5,
+		},
 		{
 			path:          "MTClient.URI",
 			expectedValue: "zkevm-prover:50061",
@@ -297,10 +415,66 @@ func Test_Defaults(t *testing.T) {
 			path:          "State.DB.MaxConns",
 			expectedValue: 200,
 		},
+		{
+			path:          "State.Pruning.Mode",
+			expectedValue: state.PruningModeArchive,
+		},
+		{
+			path:          "State.Pruning.KeepVerifiedBatches",
+			expectedValue: uint64(1000),
+		},
+		{
+			path:          "State.Pruning.Interval",
+			expectedValue: types.NewDuration(10 * time.Minute),
+		},
+		{
+			path:          "State.Pruning.MaxL2BlocksPerRun",
+			expectedValue: uint64(1000),
+		},
+		{
+			path:          "State.TraceIndex.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "State.TraceIndex.Interval",
+			expectedValue: types.NewDuration(5 * time.Second),
+		},
+		{
+			path:          "State.TraceIndex.MaxL2BlocksPerRun",
+			expectedValue: uint64(100),
+		},
+		{
+			path:          "State.TraceCache.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "State.TraceCache.MaxEntries",
+			expectedValue: 1000,
+		},
+		{
+			path:          "State.TraceCache.MaxTraceSize",
+			expectedValue: uint64(1048576),
+		},
+		{
+			path:          "State.TraceCache.Persist",
+			expectedValue: false,
+		},
+		{
+			path:          "State.WitnessCache.Enabled",
+			expectedValue: true,
+		},
+		{
+			path:          "State.WitnessCache.MaxEntries",
+			expectedValue: 16,
+		},
 		{
 			path:          "Pool.IntervalToRefreshGasPrices",
 			expectedValue: types.NewDuration(5 * time.Second),
 		},
+		{
+			path:          "Pool.IntervalToRefreshSequencerHalt",
+			expectedValue: types.NewDuration(1 * time.Second),
+		},
 		{
 			path:          "Pool.MaxTxBytesSize",
 			expectedValue: uint64(100132),
@@ -338,6 +512,18 @@ func Test_Defaults(t *testing.T) {
 			path:          "Pool.EffectiveGasPrice.Enabled",
 			expectedValue: false,
 		},
+		{
+			path:          "Pool.PrivateMempool.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "Pool.PrivateMempool.ExposeTxHashes",
+			expectedValue: false,
+		},
+		{
+			path:          "Pool.PrivateMempool.SlotDuration",
+			expectedValue: types.NewDuration(1 * time.Second),
+		},
 		{
 			path:          "Pool.EffectiveGasPrice.L1GasPriceFactor",
 			expectedValue: float64(0.25),
@@ -370,6 +556,18 @@ func Test_Defaults(t *testing.T) {
 			path:          "Pool.EffectiveGasPrice.EthTransferL1GasPriceFactor",
 			expectedValue: float64(0),
 		},
+		{
+			path:          "Pool.EffectiveGasPrice.L1CostModel.Type",
+			expectedValue: pool.CalldataL1CostModel,
+		},
+		{
+			path:          "Pool.EffectiveGasPrice.L1CostModel.BlobBytesPerBatch",
+			expectedValue: uint64(126976),
+		},
+		{
+			path:          "Pool.EffectiveGasPrice.L1CostModel.BlobWeight",
+			expectedValue: 0.5,
+		},
 		{
 			path:          "Pool.DB.User",
 			expectedValue: "pool_user",
@@ -446,6 +644,14 @@ func Test_Defaults(t *testing.T) {
 			path:          "RPC.MaxNativeBlockHashBlockRange",
 			expectedValue: uint64(60000),
 		},
+		{
+			path:          "RPC.MaxTraceFilterBlockRange",
+			expectedValue: uint64(1000),
+		},
+		{
+			path:          "RPC.MaxEGPSimulationBlockRange",
+			expectedValue: uint64(1000),
+		},
 		{
 			path:          "RPC.EnableHttpLog",
 			expectedValue: true,
@@ -466,6 +672,14 @@ func Test_Defaults(t *testing.T) {
 			path:          "RPC.WebSockets.ReadLimit",
 			expectedValue: int64(104857600),
 		},
+		{
+			path:          "RPC.Admin.Host",
+			expectedValue: "127.0.0.1",
+		},
+		{
+			path:          "RPC.Admin.Port",
+			expectedValue: int(8547),
+		},
 		{
 			path:          "Executor.URI",
 			expectedValue: "zkevm-prover:50071",
@@ -482,6 +696,22 @@ func Test_Defaults(t *testing.T) {
 			path:          "Executor.MaxGRPCMessageSize",
 			expectedValue: int(100000000),
 		},
+		{
+			path:          "Executor.HealthCheckInterval",
+			expectedValue: types.NewDuration(5 * time.Second),
+		},
+		{
+			path:          "Executor.CircuitBreakerFailures",
+			expectedValue: 3,
+		},
+		{
+			path:          "Executor.CircuitBreakerTimeout",
+			expectedValue: types.NewDuration(30 * time.Second),
+		},
+		{
+			path:          "Executor.InProcess",
+			expectedValue: false,
+		},
 		{
 			path:          "Metrics.Host",
 			expectedValue: "0.0.0.0",
@@ -542,6 +772,10 @@ func Test_Defaults(t *testing.T) {
 			path:          "Aggregator.BatchProofL1BlockConfirmations",
 			expectedValue: uint64(2),
 		},
+		{
+			path:          "Aggregator.DebugUseBatchWitness",
+			expectedValue: false,
+		},
 		{
 			path:          "State.Batch.Constraints.MaxTxsPerBatch",
 			expectedValue: uint64(300),
@@ -20,6 +20,23 @@ Outputs = ["stderr"]
 	Port = "5432"
 	EnableLog = false	
 	MaxConns = 200
+	[State.Pruning]
+	Mode = "archive"
+	KeepVerifiedBatches = 1000
+	Interval = "10m"
+	MaxL2BlocksPerRun = 1000
+	[State.TraceIndex]
+	Enabled = false
+	Interval = "5s"
+	MaxL2BlocksPerRun = 100
+	[State.TraceCache]
+	Enabled = false
+	MaxEntries = 1000
+	MaxTraceSize = 1048576
+	Persist = false
+	[State.WitnessCache]
+	Enabled = true
+	MaxEntries = 16
 	[State.Batch]
 		[State.Batch.Constraints]
 		MaxTxsPerBatch = 300
@@ -37,6 +54,7 @@ Outputs = ["stderr"]
 [Pool]
 IntervalToRefreshBlockedAddresses = "5m"
 IntervalToRefreshGasPrices = "5s"
+IntervalToRefreshSequencerHalt = "1s"
 MaxTxBytesSize=100132
 MaxTxDataBytesSize=100000
 DefaultMinGasPriceAllowed = 1000000000
@@ -56,6 +74,14 @@ TxFeeCap = 1.0
 	EthTransferGasPrice = 0
 	EthTransferL1GasPriceFactor = 0	
 	L2GasPriceSuggesterFactor = 0.5
+		[Pool.EffectiveGasPrice.L1CostModel]
+		Type = "calldata"
+		BlobBytesPerBatch = 126976
+		BlobWeight = 0.5
+    [Pool.PrivateMempool]
+	Enabled = false
+	ExposeTxHashes = false
+	SlotDuration = "1s"
     [Pool.DB]
 	User = "pool_user"
 	Password = "pool_password"
@@ -92,12 +118,17 @@ BatchRequestsLimit = 20
 MaxLogsCount = 10000
 MaxLogsBlockRange = 10000
 MaxNativeBlockHashBlockRange = 60000
+MaxTraceFilterBlockRange = 1000
+MaxEGPSimulationBlockRange = 1000
 EnableHttpLog = true
 	[RPC.WebSockets]
 		Enabled = true
 		Host = "0.0.0.0"
 		Port = 8546
 		ReadLimit = 104857600
+	[RPC.Admin]
+		Host = "127.0.0.1"
+		Port = 8547
 
 [Synchronizer]
 SyncInterval = "1s"
@@ -107,6 +138,7 @@ SyncBlockProtection = "safe" # latest, finalized, safe
 L1SynchronizationMode = "sequential"
 L1SyncCheckL2BlockHash = true
 L1SyncCheckL2BlockNumberModulus = 600
+L1RecordFile = ""
 	[Synchronizer.L1BlockCheck]
 		Enabled = true
 		L1SafeBlockPoint = "finalized"
@@ -133,13 +165,17 @@ L1SyncCheckL2BlockNumberModulus = 600
 		AcceptEmptyClosedBatches = false
 		ReprocessFullBatchOnClose = true
 		CheckLastL2BlockHashOnCloseBatch = true
+	[Synchronizer.MultiRollup]
+		Rollups = []
+		MaxCachedL1Blocks = 10000
 
 [Sequencer]
 DeletePoolTxsL1BlockConfirmations = 100
 DeletePoolTxsCheckInterval = "12h"
 TxLifetimeCheckInterval = "10m"
 TxLifetimeMax = "3h"
-LoadPoolTxsCheckInterval = "500ms"
+LoadPoolTxsCheckInterval = "5s"
+ListenPoolTxsNotifications = true
 StateConsistencyCheckInterval = "5s"
 	[Sequencer.Finalizer]
 		NewTxsWaitInterval = "100ms"
@@ -154,11 +190,17 @@ StateConsistencyCheckInterval = "5s"
 		StateRootSyncInterval = "3600s"
 		FlushIdCheckInterval = "50ms"
 		HaltOnBatchNumber = 0
+		ScheduledHaltCheckInterval = "5s"
 		SequentialBatchSanityCheck = false
 		SequentialProcessL2Block = false
 	[Sequencer.Finalizer.Metrics]
 		Interval = "60m"
 		EnableLog = true
+		EGPAnalyticsWindow = "1h"
+	[Sequencer.Finalizer.ForcedBatchesTracker]
+		CheckInterval = "1m"
+		WarningThresholdPct = 50
+		CriticalThresholdPct = 80
 	[Sequencer.StreamServer]
 		Port = 0
 		Filename = ""
@@ -167,6 +209,21 @@ StateConsistencyCheckInterval = "5s"
 		InactivityTimeout = "120s"
 		InactivityCheckInterval = "5s"
 		Enabled = false
+		VerifyBatches = 10
+	[Sequencer.HA]
+		Enabled = false
+		NodeID = ""
+		LeaseDuration = "15s"
+		RenewInterval = "5s"
+		StandbyCheckInterval = "2s"
+
+[StreamRelay]
+Upstream = ""
+Port = 0
+Filename = ""
+WriteTimeout = "5s"
+InactivityTimeout = "120s"
+InactivityCheckInterval = "5s"
 
 [SequenceSender]
 WaitPeriodSendSequence = "5s"
@@ -191,6 +248,7 @@ GeneratingProofCleanupThreshold = "10m"
 GasOffset = 0
 UpgradeEtrogBatchNumber = 0
 BatchProofL1BlockConfirmations = 2
+DebugUseBatchWitness = false
 
 [L2GasPriceSuggester]
 Type = "follower"
@@ -199,7 +257,17 @@ Factor = 0.15
 DefaultGasPriceWei = 2000000000
 MaxGasPriceWei = 0
 CleanHistoryPeriod = "1h"
-CleanHistoryTimeRetention = "5m"
+CleanHistoryTimeRetention = "1h"
+	[L2GasPriceSuggester.Congestion]
+	TargetPendingTxs = 1000
+	TargetBatchFullness = 0.5
+	TargetResourcesUtilisation = 0.5
+	CheckBatches = 10
+	MaxChangePerPeriod = 0.125
+	[L2GasPriceSuggester.L1CostModel]
+	Type = "calldata"
+	BlobBytesPerBatch = 126976
+	BlobWeight = 0.5
 
 [MTClient]
 URI = "zkevm-prover:50061"
@@ -209,6 +277,10 @@ URI = "zkevm-prover:50071"
 MaxResourceExhaustedAttempts = 3
 WaitOnResourceExhaustion = "1s"
 MaxGRPCMessageSize = 100000000
+HealthCheckInterval = "5s"
+CircuitBreakerFailures = 3
+CircuitBreakerTimeout = "30s"
+InProcess = false
 
 [Metrics]
 Host = "0.0.0.0"
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE pool.gas_price ADD COLUMN inputs JSONB;
+
+-- +migrate Down
+ALTER TABLE pool.gas_price DROP COLUMN inputs;
@@ -0,0 +1,37 @@
+package pool_migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// this migration adds the inputs used to calculate the gas prices to the gas price history
+type migrationTest0014 struct{}
+
+func (m migrationTest0014) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0014) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const insertGasPrice = `
+		INSERT INTO pool.gas_price (price, l1_price, timestamp, inputs)
+		VALUES (1000, 2000, '2023-12-07', '{"pricer":"follower","factor":0.5}')`
+
+	_, err := db.Exec(insertGasPrice)
+	require.NoError(t, err)
+}
+
+func (m migrationTest0014) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const insertGasPrice = `
+		INSERT INTO pool.gas_price (price, l1_price, timestamp, inputs)
+		VALUES (1000, 2000, '2023-12-07', '{"pricer":"follower","factor":0.5}')`
+
+	_, err := db.Exec(insertGasPrice)
+	require.Error(t, err)
+}
+
+func TestMigration0014(t *testing.T) {
+	runMigrationTest(t, 14, migrationTest0014{})
+}
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE pool.gas_price ADD COLUMN l1_blob_base_fee DECIMAL(78, 0);
+
+-- +migrate Down
+ALTER TABLE pool.gas_price DROP COLUMN l1_blob_base_fee;
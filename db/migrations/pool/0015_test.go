@@ -0,0 +1,37 @@
+package pool_migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// this migration adds the l1 blob base fee to the gas prices
+type migrationTest0015 struct{}
+
+func (m migrationTest0015) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0015) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const insertGasPrice = `
+		INSERT INTO pool.gas_price (price, l1_price, l1_blob_base_fee, timestamp)
+		VALUES (1000, 2000, 3000, '2023-12-07')`
+
+	_, err := db.Exec(insertGasPrice)
+	require.NoError(t, err)
+}
+
+func (m migrationTest0015) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const insertGasPrice = `
+		INSERT INTO pool.gas_price (price, l1_price, l1_blob_base_fee, timestamp)
+		VALUES (1000, 2000, 3000, '2023-12-07')`
+
+	_, err := db.Exec(insertGasPrice)
+	require.Error(t, err)
+}
+
+func TestMigration0015(t *testing.T) {
+	runMigrationTest(t, 15, migrationTest0015{})
+}
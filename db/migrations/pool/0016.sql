@@ -0,0 +1,24 @@
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION pool.notify_new_tx() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('pool_new_txs', NEW.hash);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER notify_new_tx_on_insert
+    AFTER INSERT ON pool.transaction
+    FOR EACH ROW WHEN (NEW.status = 'pending' AND NEW.is_wip IS NOT TRUE)
+    EXECUTE FUNCTION pool.notify_new_tx();
+
+CREATE TRIGGER notify_new_tx_on_update
+    AFTER UPDATE OF status, is_wip ON pool.transaction
+    FOR EACH ROW WHEN (NEW.status = 'pending' AND NEW.is_wip IS NOT TRUE AND (OLD.status IS DISTINCT FROM NEW.status OR OLD.is_wip IS TRUE))
+    EXECUTE FUNCTION pool.notify_new_tx();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS notify_new_tx_on_update ON pool.transaction;
+DROP TRIGGER IF EXISTS notify_new_tx_on_insert ON pool.transaction;
+DROP FUNCTION IF EXISTS pool.notify_new_tx();
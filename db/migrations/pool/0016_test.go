@@ -0,0 +1,36 @@
+package pool_migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// this migration adds the triggers that notify the new pending txs of the pool
+type migrationTest0016 struct{}
+
+func (m migrationTest0016) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0016) countTriggers(t *testing.T, db *sql.DB) int {
+	const countTriggersSQL = "SELECT COUNT(*) FROM pg_trigger WHERE tgname IN ('notify_new_tx_on_insert', 'notify_new_tx_on_update')"
+
+	var count int
+	require.NoError(t, db.QueryRow(countTriggersSQL).Scan(&count))
+	return count
+}
+
+func (m migrationTest0016) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	assert.Equal(t, 2, m.countTriggers(t, db))
+}
+
+func (m migrationTest0016) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	assert.Equal(t, 0, m.countTriggers(t, db))
+}
+
+func TestMigration0016(t *testing.T) {
+	runMigrationTest(t, 16, migrationTest0016{})
+}
@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS state.pruned_history
+(
+    first_kept_l2_block_num BIGINT NOT NULL
+);
+
+comment on column state.pruned_history.first_kept_l2_block_num is 'receipts, logs and transaction bodies of the L2 blocks below this number have been pruned';
+
+INSERT INTO state.pruned_history (first_kept_l2_block_num) VALUES (0);
+
+-- +migrate Down
+DROP TABLE IF EXISTS state.pruned_history;
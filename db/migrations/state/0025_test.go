@@ -0,0 +1,40 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type migrationTest0025 struct {
+	migrationBase
+}
+
+func (m migrationTest0025) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0025) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	m.AssertNewAndRemovedItemsAfterMigrationUp(t, db)
+
+	var firstKeptL2BlockNum uint64
+	err := db.QueryRow("SELECT first_kept_l2_block_num FROM state.pruned_history").Scan(&firstKeptL2BlockNum)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), firstKeptL2BlockNum)
+}
+
+func (m migrationTest0025) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	m.AssertNewAndRemovedItemsAfterMigrationDown(t, db)
+}
+
+func TestMigration0025(t *testing.T) {
+	m := migrationTest0025{
+		migrationBase: migrationBase{
+			newTables: []tableMetadata{
+				{"state", "pruned_history"},
+			},
+		},
+	}
+	runMigrationTest(t, 25, m)
+}
@@ -0,0 +1,28 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS state.trace
+(
+    l2_block_num BIGINT  NOT NULL REFERENCES state.l2block (block_num) ON DELETE CASCADE,
+    tx_index     INTEGER NOT NULL,
+    trace_index  INTEGER NOT NULL,
+    tx_hash      VARCHAR NOT NULL,
+    from_addr    VARCHAR,
+    to_addr      VARCHAR,
+    trace        JSONB   NOT NULL,
+    PRIMARY KEY (l2_block_num, tx_index, trace_index)
+);
+
+CREATE INDEX IF NOT EXISTS trace_tx_hash_idx ON state.trace (tx_hash);
+CREATE INDEX IF NOT EXISTS trace_from_addr_idx ON state.trace (from_addr, l2_block_num);
+CREATE INDEX IF NOT EXISTS trace_to_addr_idx ON state.trace (to_addr, l2_block_num);
+
+CREATE TABLE IF NOT EXISTS state.trace_indexed_l2block
+(
+    l2_block_num BIGINT PRIMARY KEY REFERENCES state.l2block (block_num) ON DELETE CASCADE
+);
+
+comment on table state.trace is 'flat call traces of the transactions, used by trace_filter';
+comment on table state.trace_indexed_l2block is 'L2 blocks whose traces are stored in state.trace';
+
+-- +migrate Down
+DROP TABLE IF EXISTS state.trace_indexed_l2block;
+DROP TABLE IF EXISTS state.trace;
@@ -0,0 +1,39 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+)
+
+type migrationTest0026 struct {
+	migrationBase
+}
+
+func (m migrationTest0026) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0026) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	m.AssertNewAndRemovedItemsAfterMigrationUp(t, db)
+}
+
+func (m migrationTest0026) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	m.AssertNewAndRemovedItemsAfterMigrationDown(t, db)
+}
+
+func TestMigration0026(t *testing.T) {
+	m := migrationTest0026{
+		migrationBase: migrationBase{
+			newIndexes: []string{
+				"trace_tx_hash_idx",
+				"trace_from_addr_idx",
+				"trace_to_addr_idx",
+			},
+			newTables: []tableMetadata{
+				{"state", "trace"},
+				{"state", "trace_indexed_l2block"},
+			},
+		},
+	}
+	runMigrationTest(t, 26, m)
+}
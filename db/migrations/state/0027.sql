@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS state.trace_cache
+(
+    cache_key    VARCHAR PRIMARY KEY,
+    l2_block_num BIGINT    NOT NULL REFERENCES state.l2block (block_num) ON DELETE CASCADE,
+    block_hash   VARCHAR   NOT NULL,
+    trace        BYTEA     NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS trace_cache_l2_block_num_idx ON state.trace_cache (l2_block_num);
+
+comment on table state.trace_cache is 'traces generated by the debug endpoints, keyed by tx hash, tracer and tracer config';
+
+-- +migrate Down
+DROP TABLE IF EXISTS state.trace_cache;
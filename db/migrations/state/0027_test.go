@@ -0,0 +1,36 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+)
+
+type migrationTest0027 struct {
+	migrationBase
+}
+
+func (m migrationTest0027) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0027) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	m.AssertNewAndRemovedItemsAfterMigrationUp(t, db)
+}
+
+func (m migrationTest0027) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	m.AssertNewAndRemovedItemsAfterMigrationDown(t, db)
+}
+
+func TestMigration0027(t *testing.T) {
+	m := migrationTest0027{
+		migrationBase: migrationBase{
+			newIndexes: []string{
+				"trace_cache_l2_block_num_idx",
+			},
+			newTables: []tableMetadata{
+				{"state", "trace_cache"},
+			},
+		},
+	}
+	runMigrationTest(t, 27, m)
+}
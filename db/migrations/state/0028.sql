@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS state.sequencer_lease
+(
+    name       VARCHAR PRIMARY KEY,
+    holder     VARCHAR NOT NULL,
+    term       BIGINT  NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+comment on table state.sequencer_lease is 'lease held by the active sequencer, the term is increased each time the lease changes of holder';
+
+-- +migrate Down
+DROP TABLE IF EXISTS state.sequencer_lease;
@@ -0,0 +1,33 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+)
+
+type migrationTest0028 struct {
+	migrationBase
+}
+
+func (m migrationTest0028) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0028) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	m.AssertNewAndRemovedItemsAfterMigrationUp(t, db)
+}
+
+func (m migrationTest0028) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	m.AssertNewAndRemovedItemsAfterMigrationDown(t, db)
+}
+
+func TestMigration0028(t *testing.T) {
+	m := migrationTest0028{
+		migrationBase: migrationBase{
+			newTables: []tableMetadata{
+				{"state", "sequencer_lease"},
+			},
+		},
+	}
+	runMigrationTest(t, 28, m)
+}
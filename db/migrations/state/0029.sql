@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS state.sequencer_halt
+(
+    name         VARCHAR PRIMARY KEY,
+    batch_num    BIGINT  NOT NULL,
+    halt_at      TIMESTAMP WITH TIME ZONE,
+    status       VARCHAR NOT NULL,
+    reason       VARCHAR NOT NULL,
+    updated_at   TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+comment on table state.sequencer_halt is 'halt of the sequencer scheduled for maintenance, the reason is replaced by the reason of the resume once it is resumed';
+
+-- +migrate Down
+DROP TABLE IF EXISTS state.sequencer_halt;
@@ -0,0 +1,33 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+)
+
+type migrationTest0029 struct {
+	migrationBase
+}
+
+func (m migrationTest0029) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0029) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	m.AssertNewAndRemovedItemsAfterMigrationUp(t, db)
+}
+
+func (m migrationTest0029) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	m.AssertNewAndRemovedItemsAfterMigrationDown(t, db)
+}
+
+func TestMigration0029(t *testing.T) {
+	m := migrationTest0029{
+		migrationBase: migrationBase{
+			newTables: []tableMetadata{
+				{"state", "sequencer_halt"},
+			},
+		},
+	}
+	runMigrationTest(t, 29, m)
+}
@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS state.force_batch_timeout
+(
+    id           SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    timeout      BIGINT NOT NULL,
+    updated_at   TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+comment on table state.force_batch_timeout is 'force batch timeout of the rollup contract in seconds, read from L1 by the forced batches tracker of the sequencer';
+
+-- +migrate Down
+DROP TABLE IF EXISTS state.force_batch_timeout;
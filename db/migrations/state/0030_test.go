@@ -0,0 +1,33 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+)
+
+type migrationTest0030 struct {
+	migrationBase
+}
+
+func (m migrationTest0030) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0030) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	m.AssertNewAndRemovedItemsAfterMigrationUp(t, db)
+}
+
+func (m migrationTest0030) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	m.AssertNewAndRemovedItemsAfterMigrationDown(t, db)
+}
+
+func TestMigration0030(t *testing.T) {
+	m := migrationTest0030{
+		migrationBase: migrationBase{
+			newTables: []tableMetadata{
+				{"state", "force_batch_timeout"},
+			},
+		},
+	}
+	runMigrationTest(t, 30, m)
+}
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE state.transaction
+    ADD COLUMN IF NOT EXISTS tx_index BIGINT DEFAULT NULL;
+
+comment on column state.transaction.tx_index is 'index of the transaction in its L2 block, only set when the receipt of the transaction has been pruned';
+
+-- +migrate Down
+ALTER TABLE state.transaction
+    DROP COLUMN IF EXISTS tx_index;
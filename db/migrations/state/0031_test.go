@@ -0,0 +1,33 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+)
+
+type migrationTest0031 struct {
+	migrationBase
+}
+
+func (m migrationTest0031) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0031) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	m.AssertNewAndRemovedItemsAfterMigrationUp(t, db)
+}
+
+func (m migrationTest0031) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	m.AssertNewAndRemovedItemsAfterMigrationDown(t, db)
+}
+
+func TestMigration0031(t *testing.T) {
+	m := migrationTest0031{
+		migrationBase: migrationBase{
+			newColumns: []columnMetadata{
+				{"state", "transaction", "tx_index"},
+			},
+		},
+	}
+	runMigrationTest(t, 31, m)
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
+	gethEIP4844 "github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -209,6 +210,7 @@ type Client struct {
 	auth               map[common.Address]bind.TransactOpts // empty in case of read-only client
 	EIP4844            *eip4844.EthermanEIP4844
 	eventFeijoaManager *EventManager
+	sharedL1Reader     *SharedL1Reader
 }
 
 // NewClient creates a new etherman.
@@ -516,6 +518,15 @@ func (etherMan *Client) GetForks(ctx context.Context, genBlockNumber uint64, las
 // GetRollupInfoByBlockRange function retrieves the Rollup information that are included in all this ethereum blocks
 // from block x to block y.
 func (etherMan *Client) GetRollupInfoByBlockRange(ctx context.Context, fromBlock uint64, toBlock *uint64) ([]Block, map[common.Hash][]Order, error) {
+	if etherMan.sharedL1Reader != nil && toBlock != nil {
+		start := time.Now()
+		logs, err := etherMan.sharedL1Reader.FilterLogs(ctx, fromBlock, *toBlock)
+		metrics.GetEventsTime(time.Since(start))
+		if err != nil {
+			return nil, nil, err
+		}
+		return etherMan.processLogs(ctx, logs, start)
+	}
 	// Filter query
 	query := ethereum.FilterQuery{
 		FromBlock: new(big.Int).SetUint64(fromBlock),
@@ -563,10 +574,19 @@ func (etherMan *Client) readEvents(ctx context.Context, query ethereum.FilterQue
 	if err != nil {
 		return nil, nil, err
 	}
+	return etherMan.processLogs(ctx, logs, start)
+}
+
+// processLogs decodes the logs read from L1 ignoring the ones emitted by smart contracts
+// of other rollups, that are read when the logs are shared with other rollups
+func (etherMan *Client) processLogs(ctx context.Context, logs []types.Log, start time.Time) ([]Block, map[common.Hash][]Order, error) {
 	var blocks []Block
 	blocksOrder := make(map[common.Hash][]Order)
 	startProcess := time.Now()
 	for _, vLog := range logs {
+		if !containsAddress(etherMan.SCAddresses, vLog.Address) {
+			continue
+		}
 		startProcessSingleEvent := time.Now()
 		err := etherMan.processEvent(ctx, vLog, &blocks, &blocksOrder)
 		metrics.ProcessSingleEventTime(time.Since(startProcessSingleEvent))
@@ -1201,11 +1221,35 @@ func (etherMan *Client) TrustedSequencer() (common.Address, error) {
 
 func (etherMan *Client) forcedBatchEvent(ctx context.Context, vLog types.Log, blocks *[]Block, blocksOrder *map[common.Hash][]Order) error {
 	log.Debug("ForceBatch event detected")
-	fb, err := etherMan.EtrogZkEVM.ParseForceBatch(vLog)
+	forcedBatch, fullBlock, err := etherMan.readForcedBatch(ctx, vLog)
 	if err != nil {
 		return err
 	}
+	if len(*blocks) == 0 || ((*blocks)[len(*blocks)-1].BlockHash != vLog.BlockHash || (*blocks)[len(*blocks)-1].BlockNumber != vLog.BlockNumber) {
+		block := prepareBlock(vLog, forcedBatch.ForcedAt, fullBlock)
+		block.ForcedBatches = append(block.ForcedBatches, forcedBatch)
+		*blocks = append(*blocks, block)
+	} else if (*blocks)[len(*blocks)-1].BlockHash == vLog.BlockHash && (*blocks)[len(*blocks)-1].BlockNumber == vLog.BlockNumber {
+		(*blocks)[len(*blocks)-1].ForcedBatches = append((*blocks)[len(*blocks)-1].ForcedBatches, forcedBatch)
+	} else {
+		log.Error("Error processing ForceBatch event. BlockHash:", vLog.BlockHash, ". BlockNumber: ", vLog.BlockNumber)
+		return fmt.Errorf("error processing ForceBatch event")
+	}
+	or := Order{
+		Name: ForcedBatchesOrder,
+		Pos:  len((*blocks)[len(*blocks)-1].ForcedBatches) - 1,
+	}
+	(*blocksOrder)[(*blocks)[len(*blocks)-1].BlockHash] = append((*blocksOrder)[(*blocks)[len(*blocks)-1].BlockHash], or)
+	return nil
+}
+
+// readForcedBatch reads the forced batch of a ForceBatch event, returning it with the L1 block where it was forced
+func (etherMan *Client) readForcedBatch(ctx context.Context, vLog types.Log) (ForcedBatch, *types.Block, error) {
 	var forcedBatch ForcedBatch
+	fb, err := etherMan.EtrogZkEVM.ParseForceBatch(vLog)
+	if err != nil {
+		return forcedBatch, nil, err
+	}
 	forcedBatch.BlockNumber = vLog.BlockNumber
 	forcedBatch.ForcedBatchNumber = fb.ForceBatchNum
 	forcedBatch.GlobalExitRoot = fb.LastGlobalExitRoot
@@ -1213,15 +1257,15 @@ func (etherMan *Client) forcedBatchEvent(ctx context.Context, vLog types.Log, bl
 	// Read the tx for this batch.
 	tx, err := etherMan.EthClient.TransactionInBlock(ctx, vLog.BlockHash, vLog.TxIndex)
 	if err != nil {
-		return err
+		return forcedBatch, nil, err
 	}
 	if tx.Hash() != vLog.TxHash {
-		return fmt.Errorf("error: tx hash mismatch. want: %s have: %s", vLog.TxHash, tx.Hash().String())
+		return forcedBatch, nil, fmt.Errorf("error: tx hash mismatch. want: %s have: %s", vLog.TxHash, tx.Hash().String())
 	}
 
 	msg, err := core.TransactionToMessage(tx, types.NewLondonSigner(tx.ChainId()), big.NewInt(0))
 	if err != nil {
-		return err
+		return forcedBatch, nil, err
 	}
 	if fb.Sequencer == msg.From {
 		txData := tx.Data()
@@ -1229,19 +1273,19 @@ func (etherMan *Client) forcedBatchEvent(ctx context.Context, vLog types.Log, bl
 		// Load contract ABI
 		abi, err := abi.JSON(strings.NewReader(etrogpolygonzkevm.EtrogpolygonzkevmABI))
 		if err != nil {
-			return err
+			return forcedBatch, nil, err
 		}
 
 		// Recover Method from signature and ABI
 		method, err := abi.MethodById(txData[:4])
 		if err != nil {
-			return err
+			return forcedBatch, nil, err
 		}
 
 		// Unpack method inputs
 		data, err := method.Inputs.Unpack(txData[4:])
 		if err != nil {
-			return err
+			return forcedBatch, nil, err
 		}
 		bytedata := data[0].([]byte)
 		forcedBatch.RawTxsData = bytedata
@@ -1251,26 +1295,12 @@ func (etherMan *Client) forcedBatchEvent(ctx context.Context, vLog types.Log, bl
 	forcedBatch.Sequencer = fb.Sequencer
 	fullBlock, err := etherMan.EthClient.BlockByHash(ctx, vLog.BlockHash)
 	if err != nil {
-		return fmt.Errorf("error getting hashParent. BlockNumber: %d. Error: %w", vLog.BlockNumber, err)
-	}
-	t := time.Unix(int64(fullBlock.Time()), 0)
-	forcedBatch.ForcedAt = t
-	if len(*blocks) == 0 || ((*blocks)[len(*blocks)-1].BlockHash != vLog.BlockHash || (*blocks)[len(*blocks)-1].BlockNumber != vLog.BlockNumber) {
-		block := prepareBlock(vLog, t, fullBlock)
-		block.ForcedBatches = append(block.ForcedBatches, forcedBatch)
-		*blocks = append(*blocks, block)
-	} else if (*blocks)[len(*blocks)-1].BlockHash == vLog.BlockHash && (*blocks)[len(*blocks)-1].BlockNumber == vLog.BlockNumber {
-		(*blocks)[len(*blocks)-1].ForcedBatches = append((*blocks)[len(*blocks)-1].ForcedBatches, forcedBatch)
-	} else {
-		log.Error("Error processing ForceBatch event. BlockHash:", vLog.BlockHash, ". BlockNumber: ", vLog.BlockNumber)
-		return fmt.Errorf("error processing ForceBatch event")
-	}
-	or := Order{
-		Name: ForcedBatchesOrder,
-		Pos:  len((*blocks)[len(*blocks)-1].ForcedBatches) - 1,
+		return forcedBatch, nil, fmt.Errorf("error getting hashParent. BlockNumber: %d. Error: %w", vLog.BlockNumber, err)
 	}
-	(*blocksOrder)[(*blocks)[len(*blocks)-1].BlockHash] = append((*blocksOrder)[(*blocks)[len(*blocks)-1].BlockHash], or)
-	return nil
+	forcedBatch.ForcedAt = time.Unix(int64(fullBlock.Time()), 0)
+	forcedBatch.ForcedBlockHashL1 = fullBlock.ParentHash()
+
+	return forcedBatch, fullBlock, nil
 }
 
 func (etherMan *Client) sequencedBatchesEvent(ctx context.Context, vLog types.Log, blocks *[]Block, blocksOrder *map[common.Hash][]Order) error {
@@ -1786,6 +1816,15 @@ func (etherMan *Client) GetLatestVerifiedBatchNum() (uint64, error) {
 	return rollupData.LastVerifiedBatch, nil
 }
 
+// GetBatchStateRoot gets the state root of a verified batch from the rollup manager smc
+func (etherMan *Client) GetBatchStateRoot(batchNumber uint64) (common.Hash, error) {
+	stateRoot, err := etherMan.EtrogRollupManager.GetRollupBatchNumToStateRoot(&bind.CallOpts{Pending: false}, etherMan.RollupID, batchNumber)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.Hash(stateRoot), nil
+}
+
 // GetTx function get ethereum tx
 func (etherMan *Client) GetTx(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
 	return etherMan.EthClient.TransactionByHash(ctx, txHash)
@@ -1821,6 +1860,125 @@ func (etherMan *Client) GetTrustedSequencerURL() (string, error) {
 	return etherMan.EtrogZkEVM.TrustedSequencerURL(&bind.CallOpts{Pending: false})
 }
 
+// GetForceBatchTimeout returns the time after which anyone can sequence a forced batch that the trusted sequencer
+// has not sequenced yet
+func (etherMan *Client) GetForceBatchTimeout() (time.Duration, error) {
+	timeout, err := etherMan.EtrogZkEVM.ForceBatchTimeout(&bind.CallOpts{Pending: false})
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(timeout) * time.Second, nil
+}
+
+// GetForceBatchAddress returns the only address allowed to force and sequence forced batches, or the zero
+// address if anyone can do it
+func (etherMan *Client) GetForceBatchAddress() (common.Address, error) {
+	return etherMan.EtrogZkEVM.ForceBatchAddress(&bind.CallOpts{Pending: false})
+}
+
+// GetForcedBatchFee returns the POL fee that must be paid to force a batch
+func (etherMan *Client) GetForcedBatchFee() (*big.Int, error) {
+	return etherMan.EtrogRollupManager.GetForcedBatchFee(&bind.CallOpts{Pending: false})
+}
+
+// GetPolAllowance returns the POL that the rollup smc is allowed to spend from the account
+func (etherMan *Client) GetPolAllowance(account common.Address) (*big.Int, error) {
+	return etherMan.Pol.Allowance(&bind.CallOpts{Pending: false}, account, etherMan.l1Cfg.ZkEVMAddr)
+}
+
+// GetLastForceBatchSequenced returns the number of the last forced batch sequenced on L1
+func (etherMan *Client) GetLastForceBatchSequenced() (uint64, error) {
+	return etherMan.EtrogZkEVM.LastForceBatchSequenced(&bind.CallOpts{Pending: false})
+}
+
+// ForceBatch sends a forceBatch tx to the rollup smc with the encoded transactions of the forced batch,
+// paying polAmount as fee
+func (etherMan *Client) ForceBatch(ctx context.Context, account common.Address, transactions []byte, polAmount *big.Int) (*types.Transaction, error) {
+	opts, err := etherMan.getAuthByAddress(account)
+	if err == ErrNotFound {
+		return nil, errors.New("can't find account private key to sign tx")
+	}
+	if etherMan.GasProviders.MultiGasProvider {
+		opts.GasPrice = etherMan.GetL1GasPrice(ctx)
+	}
+	tx, err := etherMan.EtrogZkEVM.ForceBatch(&opts, transactions, polAmount)
+	if err != nil {
+		if parsedErr, ok := tryParseError(err); ok {
+			err = parsedErr
+		}
+		return nil, fmt.Errorf("error forcing batch. Error: %w", err)
+	}
+
+	return tx, nil
+}
+
+// SequenceForceBatches sends a sequenceForceBatches tx to the rollup smc with the forced batches, that must
+// follow the last forced batch sequenced. It's allowed once the force batch timeout of the last one has expired
+func (etherMan *Client) SequenceForceBatches(ctx context.Context, account common.Address, forcedBatches []ForcedBatch) (*types.Transaction, error) {
+	opts, err := etherMan.getAuthByAddress(account)
+	if err == ErrNotFound {
+		return nil, errors.New("can't find account private key to sign tx")
+	}
+	if etherMan.GasProviders.MultiGasProvider {
+		opts.GasPrice = etherMan.GetL1GasPrice(ctx)
+	}
+	batches := make([]etrogpolygonzkevm.PolygonRollupBaseEtrogBatchData, 0, len(forcedBatches))
+	for _, forcedBatch := range forcedBatches {
+		batches = append(batches, etrogpolygonzkevm.PolygonRollupBaseEtrogBatchData{
+			Transactions:         forcedBatch.RawTxsData,
+			ForcedGlobalExitRoot: forcedBatch.GlobalExitRoot,
+			ForcedTimestamp:      uint64(forcedBatch.ForcedAt.Unix()),
+			ForcedBlockHashL1:    forcedBatch.ForcedBlockHashL1,
+		})
+	}
+	tx, err := etherMan.EtrogZkEVM.SequenceForceBatches(&opts, batches)
+	if err != nil {
+		if parsedErr, ok := tryParseError(err); ok {
+			err = parsedErr
+		}
+		return nil, fmt.Errorf("error sequencing forced batches. Error: %w", err)
+	}
+
+	return tx, nil
+}
+
+// GetForcedBatchNumberFromReceipt returns the number of the forced batch of a mined forceBatch tx
+func (etherMan *Client) GetForcedBatchNumberFromReceipt(receipt *types.Receipt) (uint64, error) {
+	for _, vLog := range receipt.Logs {
+		if len(vLog.Topics) == 0 || vLog.Topics[0] != forceBatchSignatureHash {
+			continue
+		}
+		fb, err := etherMan.EtrogZkEVM.ParseForceBatch(*vLog)
+		if err != nil {
+			return 0, err
+		}
+		return fb.ForceBatchNum, nil
+	}
+	return 0, ErrNotFound
+}
+
+// GetForcedBatchByNumber reads from L1 the forced batch with the number provided, looking for its ForceBatch
+// event since the L1 block fromBlock
+func (etherMan *Client) GetForcedBatchByNumber(ctx context.Context, forcedBatchNumber uint64, fromBlock uint64) (*ForcedBatch, error) {
+	iter, err := etherMan.EtrogZkEVM.FilterForceBatch(&bind.FilterOpts{Start: fromBlock, Context: ctx}, []uint64{forcedBatchNumber})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	if !iter.Next() {
+		if iter.Error() != nil {
+			return nil, iter.Error()
+		}
+		return nil, ErrNotFound
+	}
+	forcedBatch, _, err := etherMan.readForcedBatch(ctx, iter.Event.Raw)
+	if err != nil {
+		return nil, err
+	}
+	return &forcedBatch, nil
+}
+
 // GetL2ChainID returns L2 Chain ID
 func (etherMan *Client) GetL2ChainID() (uint64, error) {
 	chainID, err := etherMan.PreEtrogZkEVM.ChainID(&bind.CallOpts{Pending: false})
@@ -1856,6 +2014,22 @@ func (etherMan *Client) GetL1GasPrice(ctx context.Context) *big.Int {
 	return gasPrice
 }
 
+// GetL1BlobBaseFee gets the l1 blob base fee from the excess blob gas of the latest l1 block. It returns
+// 0 if it can't be got or if the l1 doesn't support blobs yet
+func (etherMan *Client) GetL1BlobBaseFee(ctx context.Context) *big.Int {
+	header, err := etherMan.EthClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		log.Warnf("error getting the latest l1 header to calculate the blob base fee. Error: %s", err.Error())
+		return big.NewInt(0)
+	}
+	if header.ExcessBlobGas == nil {
+		return big.NewInt(0)
+	}
+	blobBaseFee := gethEIP4844.CalcBlobFee(*header.ExcessBlobGas)
+	log.Debug("blobBaseFee: ", blobBaseFee)
+	return blobBaseFee
+}
+
 // SendTx sends a tx to L1
 func (etherMan *Client) SendTx(ctx context.Context, tx *types.Transaction) error {
 	return etherMan.EthClient.SendTransaction(ctx, tx)
@@ -0,0 +1,276 @@
+package etherman
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/sync/singleflight"
+)
+
+const finalizedBlockRefreshInterval = 30 * time.Second
+
+// SharedL1Reader reads with a single query the L1 logs of several rollups attached to the same
+// rollup manager, so they can be synchronized by the same instance without reading L1 once per rollup.
+// Only the logs of finalized blocks are cached, so the cache is never invalidated by a L1 reorg.
+// L1 is read without holding the lock, the concurrent reads of the same range share a single query
+type SharedL1Reader struct {
+	client          ethereumClient
+	maxCachedBlocks uint64
+	reads           singleflight.Group
+
+	mutex     sync.Mutex
+	addresses []common.Address
+	logs      map[uint64][]types.Log
+	// cached are the sorted and disjoint ranges of blocks whose logs are in the cache
+	cached []blockRange
+	// generation changes every time the cache is reset, so the reads started before are not cached
+	generation         uint64
+	finalizedBlock     uint64
+	finalizedUpdatedAt time.Time
+}
+
+// blockRange is a range of L1 blocks, both included
+type blockRange struct {
+	fromBlock uint64
+	toBlock   uint64
+}
+
+// segment is a part of the range requested to FilterLogs with its logs if they are cached
+type segment struct {
+	blockRange
+	cached bool
+	logs   []types.Log
+}
+
+// NewSharedL1Reader creates a new SharedL1Reader that keeps in memory the logs of up to maxCachedBlocks L1 blocks
+func NewSharedL1Reader(client ethereumClient, maxCachedBlocks uint64) *SharedL1Reader {
+	r := &SharedL1Reader{
+		client:          client,
+		maxCachedBlocks: maxCachedBlocks,
+	}
+	r.reset()
+	return r
+}
+
+// SetSharedL1Reader makes the client read the rollup info from the logs read by r. The addresses of
+// the smart contracts of the client are added to the ones queried by r
+func (etherMan *Client) SetSharedL1Reader(r *SharedL1Reader) {
+	r.addAddresses(etherMan.SCAddresses)
+	etherMan.sharedL1Reader = r
+}
+
+func (r *SharedL1Reader) addAddresses(addresses []common.Address) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	changed := false
+	for _, address := range addresses {
+		if !containsAddress(r.addresses, address) {
+			r.addresses = append(r.addresses, address)
+			changed = true
+		}
+	}
+	// The logs cached don't include the new addresses
+	if changed {
+		r.reset()
+	}
+}
+
+func (r *SharedL1Reader) reset() {
+	r.logs = map[uint64][]types.Log{}
+	r.cached = nil
+	r.generation++
+}
+
+// FilterLogs returns the logs of all the rollups emitted between fromBlock and toBlock, both included
+func (r *SharedL1Reader) FilterLogs(ctx context.Context, fromBlock, toBlock uint64) ([]types.Log, error) {
+	cachedToBlock := min(toBlock, r.getFinalizedBlock(ctx, toBlock))
+
+	r.mutex.Lock()
+	generation, addresses := r.generation, r.addresses
+	segments := r.segments(fromBlock, cachedToBlock)
+	r.mutex.Unlock()
+
+	for i := range segments {
+		if segments[i].cached {
+			log.Debugf("shared L1 reader: blocks %d to %d read from cache", segments[i].fromBlock, segments[i].toBlock)
+			continue
+		}
+		logs, err := r.filterLogs(ctx, generation, addresses, segments[i].blockRange)
+		if err != nil {
+			return nil, err
+		}
+		segments[i].logs = logs
+	}
+	r.cacheLogs(generation, segments)
+
+	logs := []types.Log{}
+	for _, s := range segments {
+		logs = append(logs, s.logs...)
+	}
+	// Ranges that are not finalized yet are read without caching them
+	if toBlock > cachedToBlock {
+		notFinalizedFromBlock := max(fromBlock, cachedToBlock+1)
+		notFinalizedLogs, err := r.filterLogs(ctx, generation, addresses, blockRange{fromBlock: notFinalizedFromBlock, toBlock: toBlock})
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, notFinalizedLogs...)
+	}
+	return logs, nil
+}
+
+// segments splits the range from fromBlock to toBlock into the ranges that are cached, with their logs,
+// and the ones that have to be read from L1
+func (r *SharedL1Reader) segments(fromBlock, toBlock uint64) []segment {
+	segments := []segment{}
+	next := fromBlock
+	for _, c := range r.cached {
+		if next > toBlock || c.fromBlock > toBlock {
+			break
+		}
+		if c.toBlock < next {
+			continue
+		}
+		if c.fromBlock > next {
+			segments = append(segments, segment{blockRange: blockRange{fromBlock: next, toBlock: c.fromBlock - 1}})
+		}
+		s := segment{blockRange: blockRange{fromBlock: max(next, c.fromBlock), toBlock: min(toBlock, c.toBlock)}, cached: true}
+		for blockNumber := s.fromBlock; blockNumber <= s.toBlock; blockNumber++ {
+			s.logs = append(s.logs, r.logs[blockNumber]...)
+		}
+		segments = append(segments, s)
+		next = s.toBlock + 1
+	}
+	if next <= toBlock {
+		segments = append(segments, segment{blockRange: blockRange{fromBlock: next, toBlock: toBlock}})
+	}
+	return segments
+}
+
+// cacheLogs adds the logs of the segments read from L1 to the cache, unless it has been reset since they were read
+func (r *SharedL1Reader) cacheLogs(generation uint64, segments []segment) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if generation != r.generation {
+		return
+	}
+	for _, s := range segments {
+		if s.cached || r.isCached(s.blockRange) {
+			continue
+		}
+		for _, l := range s.logs {
+			r.logs[l.BlockNumber] = append(r.logs[l.BlockNumber], l)
+		}
+		r.cached = append(r.cached, s.blockRange)
+	}
+	r.mergeCachedRanges()
+	r.trim()
+}
+
+// isCached returns true if any block of br is already cached, which happens when another rollup has
+// read an overlapping range at the same time
+func (r *SharedL1Reader) isCached(br blockRange) bool {
+	for _, c := range r.cached {
+		if c.fromBlock <= br.toBlock && br.fromBlock <= c.toBlock {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeCachedRanges sorts the cached ranges and joins the ones that are contiguous
+func (r *SharedL1Reader) mergeCachedRanges() {
+	sort.Slice(r.cached, func(i, j int) bool { return r.cached[i].fromBlock < r.cached[j].fromBlock })
+	merged := []blockRange{}
+	for _, c := range r.cached {
+		if len(merged) > 0 && c.fromBlock <= merged[len(merged)-1].toBlock+1 {
+			merged[len(merged)-1].toBlock = max(merged[len(merged)-1].toBlock, c.toBlock)
+			continue
+		}
+		merged = append(merged, c)
+	}
+	r.cached = merged
+}
+
+// filterLogs reads the logs of br from L1. The concurrent calls for the same range share the query
+func (r *SharedL1Reader) filterLogs(ctx context.Context, generation uint64, addresses []common.Address, br blockRange) ([]types.Log, error) {
+	key := fmt.Sprintf("logs:%d:%d:%d", generation, br.fromBlock, br.toBlock)
+	logs, err, _ := r.reads.Do(key, func() (interface{}, error) {
+		query := ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(br.fromBlock),
+			ToBlock:   new(big.Int).SetUint64(br.toBlock),
+			Addresses: addresses,
+		}
+		return r.client.FilterLogs(ctx, query)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return logs.([]types.Log), nil
+}
+
+// getFinalizedBlock returns the finalized block, refreshing it if the requested block is beyond it.
+// If the finalized block can't be read, the logs are not cached
+func (r *SharedL1Reader) getFinalizedBlock(ctx context.Context, blockNumber uint64) uint64 {
+	r.mutex.Lock()
+	finalizedBlock, updatedAt := r.finalizedBlock, r.finalizedUpdatedAt
+	r.mutex.Unlock()
+	if blockNumber <= finalizedBlock || time.Since(updatedAt) < finalizedBlockRefreshInterval {
+		return finalizedBlock
+	}
+	header, err, _ := r.reads.Do("finalized", func() (interface{}, error) {
+		return r.client.HeaderByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+	})
+	if err != nil {
+		log.Warnf("shared L1 reader: error getting the finalized block, logs are not cached. Error: %v", err)
+		return finalizedBlock
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.finalizedBlock = max(r.finalizedBlock, header.(*types.Header).Number.Uint64())
+	r.finalizedUpdatedAt = time.Now()
+	return r.finalizedBlock
+}
+
+// trim removes the oldest blocks of the cache to keep at most maxCachedBlocks
+func (r *SharedL1Reader) trim() {
+	cachedBlocks := uint64(0)
+	for _, c := range r.cached {
+		cachedBlocks += c.toBlock - c.fromBlock + 1
+	}
+	for cachedBlocks > r.maxCachedBlocks {
+		excess := cachedBlocks - r.maxCachedBlocks
+		oldest := &r.cached[0]
+		if oldest.toBlock-oldest.fromBlock+1 <= excess {
+			cachedBlocks -= oldest.toBlock - oldest.fromBlock + 1
+			r.cached = r.cached[1:]
+			continue
+		}
+		oldest.fromBlock += excess
+		cachedBlocks -= excess
+	}
+	for blockNumber := range r.logs {
+		if len(r.cached) == 0 || blockNumber < r.cached[0].fromBlock {
+			delete(r.logs, blockNumber)
+		}
+	}
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
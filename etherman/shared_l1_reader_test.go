@@ -0,0 +1,146 @@
+package etherman
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeL1Client returns a log per block and address and records the ranges queried. If blocked is set,
+// the queries wait until it's closed
+type fakeL1Client struct {
+	ethereumClient
+	finalizedBlock uint64
+	blocked        chan struct{}
+
+	mutex    sync.Mutex
+	queries  [][2]uint64
+	inFlight int
+}
+
+func (c *fakeL1Client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	c.mutex.Lock()
+	c.queries = append(c.queries, [2]uint64{q.FromBlock.Uint64(), q.ToBlock.Uint64()})
+	c.inFlight++
+	c.mutex.Unlock()
+	if c.blocked != nil {
+		<-c.blocked
+	}
+	logs := []types.Log{}
+	for blockNumber := q.FromBlock.Uint64(); blockNumber <= q.ToBlock.Uint64(); blockNumber++ {
+		for _, address := range q.Addresses {
+			logs = append(logs, types.Log{Address: address, BlockNumber: blockNumber})
+		}
+	}
+	return logs, nil
+}
+
+func (c *fakeL1Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: new(big.Int).SetUint64(c.finalizedBlock)}, nil
+}
+
+func TestSharedL1Reader(t *testing.T) {
+	ctx := context.Background()
+	rollup1 := common.HexToAddress("0x1")
+	rollup2 := common.HexToAddress("0x2")
+	client := &fakeL1Client{finalizedBlock: 100}
+	reader := NewSharedL1Reader(client, 50)
+	reader.addAddresses([]common.Address{rollup1})
+	reader.addAddresses([]common.Address{rollup1, rollup2})
+
+	logs, err := reader.FilterLogs(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, logs, 20)
+	require.Equal(t, [][2]uint64{{1, 10}}, client.queries)
+
+	// The second rollup reads the same range from the cache
+	logs, err = reader.FilterLogs(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, logs, 20)
+	require.Len(t, client.queries, 1)
+
+	// Only the new blocks are read and the not finalized ones are not cached
+	logs, err = reader.FilterLogs(ctx, 5, 110)
+	require.NoError(t, err)
+	require.Len(t, logs, 2*106)
+	require.Equal(t, [][2]uint64{{1, 10}, {11, 100}, {101, 110}}, client.queries)
+	require.Equal(t, []blockRange{{fromBlock: 51, toBlock: 100}}, reader.cached)
+
+	logs, err = reader.FilterLogs(ctx, 101, 110)
+	require.NoError(t, err)
+	require.Len(t, logs, 20)
+	require.Equal(t, [2]uint64{101, 110}, client.queries[3])
+
+	// Only the blocks removed from the cache are read again
+	client.queries = nil
+	logs, err = reader.FilterLogs(ctx, 40, 60)
+	require.NoError(t, err)
+	require.Len(t, logs, 42)
+	require.Equal(t, [][2]uint64{{40, 50}}, client.queries)
+	require.Equal(t, []blockRange{{fromBlock: 51, toBlock: 100}}, reader.cached)
+}
+
+func TestSharedL1ReaderNotContiguousRanges(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeL1Client{finalizedBlock: 300}
+	reader := NewSharedL1Reader(client, 50)
+	reader.addAddresses([]common.Address{common.HexToAddress("0x1")})
+
+	_, err := reader.FilterLogs(ctx, 61, 100)
+	require.NoError(t, err)
+	_, err = reader.FilterLogs(ctx, 201, 220)
+	require.NoError(t, err)
+	require.Equal(t, []blockRange{{fromBlock: 71, toBlock: 100}, {fromBlock: 201, toBlock: 220}}, reader.cached)
+
+	// The blocks cached before the gap are kept
+	client.queries = nil
+	logs, err := reader.FilterLogs(ctx, 71, 100)
+	require.NoError(t, err)
+	require.Len(t, logs, 30)
+	require.Empty(t, client.queries)
+
+	logs, err = reader.FilterLogs(ctx, 91, 230)
+	require.NoError(t, err)
+	require.Len(t, logs, 140)
+	require.Equal(t, [][2]uint64{{101, 200}, {221, 230}}, client.queries)
+	for i, l := range logs {
+		require.Equal(t, uint64(91+i), l.BlockNumber)
+	}
+}
+
+func TestSharedL1ReaderConcurrentReads(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeL1Client{finalizedBlock: 100, blocked: make(chan struct{})}
+	reader := NewSharedL1Reader(client, 50)
+	reader.addAddresses([]common.Address{common.HexToAddress("0x1")})
+
+	var wg sync.WaitGroup
+	for _, r := range [][2]uint64{{1, 10}, {1, 10}, {21, 30}} {
+		wg.Add(1)
+		go func(fromBlock, toBlock uint64) {
+			defer wg.Done()
+			logs, err := reader.FilterLogs(ctx, fromBlock, toBlock)
+			require.NoError(t, err)
+			require.Len(t, logs, 10)
+		}(r[0], r[1])
+	}
+	// The different ranges are read from L1 at the same time
+	require.Eventually(t, func() bool {
+		client.mutex.Lock()
+		defer client.mutex.Unlock()
+		return client.inFlight == 2
+	}, time.Second, time.Millisecond)
+	close(client.blocked)
+	wg.Wait()
+
+	// The rollups reading the same range share the query
+	require.Len(t, client.queries, 2)
+	require.Equal(t, []blockRange{{fromBlock: 1, toBlock: 10}, {fromBlock: 21, toBlock: 30}}, reader.cached)
+}
@@ -199,6 +199,13 @@ func NewSimulatedEtherman(cfg Config, auth *bind.TransactOpts) (*Client, *simula
 		SCAddresses:                []common.Address{zkevmAddr, mockRollupManagerAddr, exitManagerAddr},
 		auth:                       map[common.Address]bind.TransactOpts{},
 		cfg:                        cfg,
+		l1Cfg: L1Config{
+			L1ChainID:                 1337, //nolint:gomnd
+			ZkEVMAddr:                 zkevmAddr,
+			RollupManagerAddr:         mockRollupManagerAddr,
+			PolAddr:                   polAddr,
+			GlobalExitRootManagerAddr: exitManagerAddr,
+		},
 	}
 	err = c.AddOrReplaceAuth(*auth)
 	if err != nil {
@@ -75,6 +75,8 @@ type ForcedBatch struct {
 	GlobalExitRoot    common.Hash
 	RawTxsData        []byte
 	ForcedAt          time.Time
+	// ForcedBlockHashL1 is the parent hash of the L1 block where the batch was forced
+	ForcedBlockHashL1 common.Hash
 }
 
 // VerifiedBatch represents a VerifiedBatch
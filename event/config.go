@@ -1,9 +1,69 @@
 package event
 
-import "github.com/0xPolygonHermez/zkevm-node/db"
+import (
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/db"
+)
+
+// SinkType is the type of a sink where the events are written besides the event storage
+type SinkType string
+
+const (
+	// SinkTypeStdout writes the events to the stdout as newline-delimited JSON
+	SinkTypeStdout SinkType = "stdout"
+	// SinkTypeFile appends the events to a file as newline-delimited JSON
+	SinkTypeFile SinkType = "file"
+	// SinkTypeWebhook posts the events in batches to a webhook as a JSON array
+	SinkTypeWebhook SinkType = "webhook"
+)
 
 // Config for event
 type Config struct {
 	// DB is the database configuration
 	DB db.Config `mapstructure:"DB"`
+
+	// Sinks are the sinks where the events are written besides the database
+	Sinks []SinkConfig `mapstructure:"Sinks"`
+}
+
+// SinkConfig is the configuration of a sink of the events
+type SinkConfig struct {
+	// Type is the type of the sink: stdout, file or webhook
+	Type SinkType `mapstructure:"Type"`
+
+	// Filter selects the events written to the sink, all of them if it's empty
+	Filter Filter `mapstructure:"Filter"`
+
+	// Path is the file where the events are appended by the file sink
+	Path string `mapstructure:"Path"`
+
+	// Webhook is the configuration of the webhook sink
+	Webhook WebhookConfig `mapstructure:"Webhook"`
+}
+
+// WebhookConfig is the configuration of a webhook sink. The parameters set to 0 use their default value
+type WebhookConfig struct {
+	// URL is the endpoint where the batches of events are posted
+	URL string `mapstructure:"URL"`
+
+	// Headers are added to the requests, e.g. to authenticate them
+	Headers map[string]string `mapstructure:"Headers"`
+
+	// BatchSize is the max number of events posted in a request, 100 by default
+	BatchSize int `mapstructure:"BatchSize"`
+
+	// FlushInterval is the max time an event waits to be posted while the batch is not full, 1s by default
+	FlushInterval types.Duration `mapstructure:"FlushInterval"`
+
+	// QueueSize is the max number of events waiting to be posted, the new events are dropped when it's full, 1000 by default
+	QueueSize int `mapstructure:"QueueSize"`
+
+	// MaxRetries is the number of times a failed request is retried before dropping its events, 3 by default
+	MaxRetries int `mapstructure:"MaxRetries"`
+
+	// RetryInterval is the time waited before the first retry, doubled on each retry, 500ms by default
+	RetryInterval types.Duration `mapstructure:"RetryInterval"`
+
+	// Timeout is the timeout of each request, and the max time waited to post a critical event, 5s by default
+	Timeout types.Duration `mapstructure:"Timeout"`
 }
@@ -52,6 +52,16 @@ const (
 	EventID_InvalidInfoRoot EventID = "INVALID INFOROOT"
 	// EventID_L2BlockReorg is triggered when a L2 block reorg has happened in the sequencer
 	EventID_L2BlockReorg EventID = "L2 BLOCK REORG"
+	// EventID_SequencerLeaseAcquired is triggered when a sequencer in standby acquires the sequencer lease and becomes active
+	EventID_SequencerLeaseAcquired EventID = "SEQUENCER LEASE ACQUIRED"
+	// EventID_SequencerLeaseLost is triggered when the active sequencer loses the sequencer lease
+	EventID_SequencerLeaseLost EventID = "SEQUENCER LEASE LOST"
+	// EventID_DataStreamMismatch is triggered when an entry of the data stream file doesn't match the state
+	EventID_DataStreamMismatch EventID = "DATA STREAM MISMATCH"
+	// EventID_ForcedBatchInclusionDelayed is triggered when a forced batch has waited to be included in a trusted batch more than the warning or critical threshold of the force batch timeout
+	EventID_ForcedBatchInclusionDelayed EventID = "FORCED BATCH INCLUSION DELAYED"
+	// EventID_ForcedBatchTimeoutExpired is triggered when the force batch timeout of a forced batch has expired before it has been included in a trusted batch
+	EventID_ForcedBatchTimeoutExpired EventID = "FORCED BATCH TIMEOUT EXPIRED"
 	// Source_Node is the source of the event
 	Source_Node Source = "node"
 
@@ -75,6 +85,8 @@ const (
 	Component_Broadcast Component = "broadcast"
 	// Component_Sequence_Sender is the component that triggered the event
 	Component_Sequence_Sender = "seqsender"
+	// Component_StreamRelay is the component that triggered the event
+	Component_StreamRelay Component = "streamrelay"
 
 	// Level_Emergency is the most severe level
 	Level_Emergency Level = "emerg"
@@ -3,29 +3,54 @@ package event
 import (
 	"context"
 	"encoding/json"
+	"sync"
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
 )
 
+// EventHandler is called when an event is logged
+type EventHandler func(ctx context.Context, event *Event)
+
 // EventLog is the main struct for the event log
 type EventLog struct {
 	cfg     Config
 	storage Storage
+
+	handlersMutex sync.RWMutex
+	handlers      map[EventID][]EventHandler
 }
 
 // NewEventLog creates and initializes an instance of EventLog
 func NewEventLog(cfg Config, storage Storage) *EventLog {
 	return &EventLog{
-		cfg:     cfg,
-		storage: storage,
+		cfg:      cfg,
+		storage:  storage,
+		handlers: map[EventID][]EventHandler{},
 	}
 }
 
+// AddEventHandler registers a handler called every time an event with the given ID is logged
+// in this process, so other components can react to it
+func (e *EventLog) AddEventHandler(eventID EventID, handler EventHandler) {
+	e.handlersMutex.Lock()
+	defer e.handlersMutex.Unlock()
+	e.handlers[eventID] = append(e.handlers[eventID], handler)
+}
+
 // LogEvent is used to store an event for runtime debugging
 func (e *EventLog) LogEvent(ctx context.Context, event *Event) error {
-	return e.storage.LogEvent(ctx, event)
+	err := e.storage.LogEvent(ctx, event)
+
+	e.handlersMutex.RLock()
+	handlers := e.handlers[event.EventID]
+	e.handlersMutex.RUnlock()
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+
+	return err
 }
 
 // LogExecutorError is used to store Executor error for runtime debugging
@@ -5,8 +5,10 @@ import (
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/event/nileventstorage"
 	"github.com/0xPolygonHermez/zkevm-node/event/pgeventstorage"
 	"github.com/0xPolygonHermez/zkevm-node/test/dbutils"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
 )
@@ -36,3 +38,22 @@ func TestStoreEvent(t *testing.T) {
 	err = eventLog.LogEvent(ctx, ev)
 	require.NoError(t, err)
 }
+
+func TestEventHandler(t *testing.T) {
+	ctx := context.Background()
+
+	eventStorage, err := nileventstorage.NewNilEventStorage()
+	require.NoError(t, err)
+	eventLog := event.NewEventLog(event.Config{}, eventStorage)
+
+	handled := []*event.Event{}
+	eventLog.AddEventHandler(event.EventID_L2BlockReorg, func(ctx context.Context, ev *event.Event) {
+		handled = append(handled, ev)
+	})
+
+	reorg := &event.Event{EventID: event.EventID_L2BlockReorg}
+	require.NoError(t, eventLog.LogEvent(ctx, reorg))
+	require.NoError(t, eventLog.LogEvent(ctx, &event.Event{EventID: event.EventID_FinalizerHalt}))
+	require.Len(t, handled, 1)
+	assert.Equal(t, reorg, handled[0])
+}
@@ -0,0 +1,60 @@
+package ndjsoneventstorage
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-node/event"
+)
+
+// NDJSONEventStorage is an implementation of the event storage interface
+// that writes the events to a writer as newline-delimited JSON
+type NDJSONEventStorage struct {
+	w   io.Writer
+	mux sync.Mutex
+}
+
+// NewNDJSONEventStorage creates and initializes an instance of NDJSONEventStorage
+// that writes the events to w
+func NewNDJSONEventStorage(w io.Writer) *NDJSONEventStorage {
+	return &NDJSONEventStorage{
+		w: w,
+	}
+}
+
+// NewFileEventStorage creates and initializes an instance of NDJSONEventStorage
+// that appends the events to the file in path, creating it if it doesn't exist
+func NewFileEventStorage(path string) (*NDJSONEventStorage, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gomnd
+	if err != nil {
+		return nil, err
+	}
+	return NewNDJSONEventStorage(file), nil
+}
+
+// Close closes the writer if it's a file
+func (p *NDJSONEventStorage) Close() error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if file, ok := p.w.(*os.File); ok && file != os.Stdout && file != os.Stderr {
+		return file.Close()
+	}
+	return nil
+}
+
+// LogEvent writes an event as a JSON line following the defined interface
+func (p *NDJSONEventStorage) LogEvent(ctx context.Context, ev *event.Event) error {
+	line, err := json.Marshal(event.NewRecord(ev))
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	_, err = p.w.Write(line)
+	return err
+}
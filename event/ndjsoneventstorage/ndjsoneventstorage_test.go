@@ -0,0 +1,65 @@
+package ndjsoneventstorage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogEvent(t *testing.T) {
+	ctx := context.Background()
+	receivedAt := time.Unix(1700000000, 0).UTC()
+	ev := &event.Event{
+		ReceivedAt:  receivedAt,
+		Source:      event.Source_Node,
+		Component:   event.Component_Sequencer,
+		Level:       event.Level_Critical,
+		EventID:     event.EventID_FinalizerHalt,
+		Description: "finalizer halted",
+		Json:        map[string]interface{}{"batch": 10},
+	}
+
+	var buf bytes.Buffer
+	s := NewNDJSONEventStorage(&buf)
+	require.NoError(t, s.LogEvent(ctx, ev))
+	require.NoError(t, s.LogEvent(ctx, &event.Event{ReceivedAt: receivedAt, EventID: event.EventID_NodeOOC}))
+
+	expected := `{"receivedAt":"2023-11-14T22:13:20Z","source":"node","component":"sequencer","level":"crit","eventId":"FINALIZER HALT","description":"finalizer halted","json":{"batch":10}}` + "\n" +
+		`{"receivedAt":"2023-11-14T22:13:20Z","source":"","component":"","level":"","eventId":"NODE OOC","description":""}` + "\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestFileEventStorage(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+
+	// the events are appended to the file
+	for i := 0; i < 2; i++ {
+		s, err := NewFileEventStorage(path)
+		require.NoError(t, err)
+		require.NoError(t, s.LogEvent(ctx, &event.Event{EventID: event.EventID_SynchronizerHalt}))
+		require.NoError(t, s.Close())
+	}
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	lines := 0
+	for scanner.Scan() {
+		var record event.Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		assert.Equal(t, event.EventID_SynchronizerHalt, record.EventID)
+		lines++
+	}
+	assert.Equal(t, 2, lines)
+}
@@ -0,0 +1,141 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// Filter selects events by their level, component and ID. An empty list matches any value
+type Filter struct {
+	// Levels are the levels of the events selected
+	Levels []Level `mapstructure:"Levels"`
+
+	// Components are the components of the events selected
+	Components []Component `mapstructure:"Components"`
+
+	// EventIDs are the IDs of the events selected
+	EventIDs []EventID `mapstructure:"EventIDs"`
+}
+
+// Match returns if the event is selected by the filter
+func (f Filter) Match(ev *Event) bool {
+	return matchAny(f.Levels, ev.Level) && matchAny(f.Components, ev.Component) && matchAny(f.EventIDs, ev.EventID)
+}
+
+// IsEmpty returns if the filter selects all the events
+func (f Filter) IsEmpty() bool {
+	return len(f.Levels) == 0 && len(f.Components) == 0 && len(f.EventIDs) == 0
+}
+
+func matchAny[T comparable](values []T, value T) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// FilteredStorage is an implementation of the event storage interface that
+// only logs the events selected by a filter in another storage
+type FilteredStorage struct {
+	storage Storage
+	filter  Filter
+}
+
+// NewFilteredStorage creates and initializes an instance of FilteredStorage
+func NewFilteredStorage(storage Storage, filter Filter) *FilteredStorage {
+	return &FilteredStorage{
+		storage: storage,
+		filter:  filter,
+	}
+}
+
+// LogEvent logs the event in the storage if it's selected by the filter
+func (s *FilteredStorage) LogEvent(ctx context.Context, ev *Event) error {
+	if !s.filter.Match(ev) {
+		return nil
+	}
+	return s.storage.LogEvent(ctx, ev)
+}
+
+// Close closes the storage if it can be closed
+func (s *FilteredStorage) Close() error {
+	return closeStorage(s.storage)
+}
+
+// FanOutStorage is an implementation of the event storage interface that
+// logs the events in several storages
+type FanOutStorage struct {
+	storages []Storage
+}
+
+// NewFanOutStorage creates and initializes an instance of FanOutStorage
+func NewFanOutStorage(storages ...Storage) *FanOutStorage {
+	return &FanOutStorage{
+		storages: storages,
+	}
+}
+
+// LogEvent logs the event in all the storages, even if some of them fail, and
+// returns the errors of the storages that failed
+func (s *FanOutStorage) LogEvent(ctx context.Context, ev *Event) error {
+	var errs []error
+	for _, storage := range s.storages {
+		if err := storage.LogEvent(ctx, ev); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes the storages that can be closed, so the events they buffer are written
+func (s *FanOutStorage) Close() error {
+	var errs []error
+	for _, storage := range s.storages {
+		if err := closeStorage(storage); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func closeStorage(storage Storage) error {
+	if closer, ok := storage.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Record is the JSON representation of an event written by the sinks
+type Record struct {
+	ReceivedAt  time.Time   `json:"receivedAt"`
+	IPAddress   string      `json:"ipAddress,omitempty"`
+	Source      Source      `json:"source"`
+	Component   Component   `json:"component"`
+	Level       Level       `json:"level"`
+	EventID     EventID     `json:"eventId"`
+	Description string      `json:"description"`
+	Data        []byte      `json:"data,omitempty"`
+	Json        interface{} `json:"json,omitempty"`
+}
+
+// NewRecord creates the Record of an event
+func NewRecord(ev *Event) Record {
+	return Record{
+		ReceivedAt:  ev.ReceivedAt,
+		IPAddress:   ev.IPAddress,
+		Source:      ev.Source,
+		Component:   ev.Component,
+		Level:       ev.Level,
+		EventID:     ev.EventID,
+		Description: ev.Description,
+		Data:        ev.Data,
+		Json:        ev.Json,
+	}
+}
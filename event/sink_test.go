@@ -0,0 +1,97 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type storageMock struct {
+	events []*event.Event
+	err    error
+}
+
+func (s *storageMock) LogEvent(ctx context.Context, ev *event.Event) error {
+	s.events = append(s.events, ev)
+	return s.err
+}
+
+func TestFilter(t *testing.T) {
+	halt := &event.Event{Level: event.Level_Critical, Component: event.Component_Sequencer, EventID: event.EventID_FinalizerHalt}
+	ooc := &event.Event{Level: event.Level_Info, Component: event.Component_Sequencer, EventID: event.EventID_NodeOOC}
+	reorg := &event.Event{Level: event.Level_Warning, Component: event.Component_Synchronizer, EventID: event.EventID_L2BlockReorg}
+
+	testCases := []struct {
+		name     string
+		filter   event.Filter
+		expected []bool
+	}{
+		{
+			name:     "empty filter",
+			expected: []bool{true, true, true},
+		},
+		{
+			name:     "by level",
+			filter:   event.Filter{Levels: []event.Level{event.Level_Critical, event.Level_Warning}},
+			expected: []bool{true, false, true},
+		},
+		{
+			name:     "by component",
+			filter:   event.Filter{Components: []event.Component{event.Component_Sequencer}},
+			expected: []bool{true, true, false},
+		},
+		{
+			name:     "by event id",
+			filter:   event.Filter{EventIDs: []event.EventID{event.EventID_FinalizerHalt, event.EventID_NodeOOC}},
+			expected: []bool{true, true, false},
+		},
+		{
+			name: "all the fields must match",
+			filter: event.Filter{
+				Levels:     []event.Level{event.Level_Info},
+				Components: []event.Component{event.Component_Sequencer},
+				EventIDs:   []event.EventID{event.EventID_FinalizerHalt, event.EventID_NodeOOC},
+			},
+			expected: []bool{false, true, false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, []bool{tc.filter.Match(halt), tc.filter.Match(ooc), tc.filter.Match(reorg)})
+			assert.Equal(t, tc.name == "empty filter", tc.filter.IsEmpty())
+		})
+	}
+}
+
+func TestFanOutStorage(t *testing.T) {
+	ctx := context.Background()
+	all := &storageMock{}
+	failing := &storageMock{err: errors.New("storage error")}
+	halts := &storageMock{}
+
+	eventLog := event.NewEventLog(event.Config{}, event.NewFanOutStorage(
+		all,
+		failing,
+		event.NewFilteredStorage(halts, event.Filter{EventIDs: []event.EventID{event.EventID_FinalizerHalt, event.EventID_SynchronizerHalt}}),
+	))
+
+	halt := &event.Event{EventID: event.EventID_FinalizerHalt}
+	reorg := &event.Event{EventID: event.EventID_L2BlockReorg}
+	err := eventLog.LogEvent(ctx, halt)
+	require.ErrorIs(t, err, failing.err)
+	err = eventLog.LogEvent(ctx, reorg)
+	require.ErrorIs(t, err, failing.err)
+
+	// the failing storage doesn't stop the event to reach the other storages
+	assert.Equal(t, []*event.Event{halt, reorg}, all.events)
+	assert.Equal(t, []*event.Event{halt, reorg}, failing.events)
+	assert.Equal(t, []*event.Event{halt}, halts.events)
+
+	failing.err = nil
+	require.NoError(t, eventLog.LogEvent(ctx, reorg))
+}
@@ -0,0 +1,211 @@
+package webhookeventstorage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+const (
+	defaultBatchSize     = 100
+	defaultFlushInterval = time.Second
+	defaultQueueSize     = 1000
+	defaultMaxRetries    = 3
+	defaultRetryInterval = 500 * time.Millisecond
+	defaultTimeout       = 5 * time.Second
+)
+
+var (
+	// ErrQueueFull is returned when an event is dropped because the queue of events waiting to be posted is full
+	ErrQueueFull = errors.New("webhook event queue is full")
+	// ErrClosed is returned when an event is logged after closing the storage
+	ErrClosed = errors.New("webhook event storage is closed")
+)
+
+// WebhookEventStorage is an implementation of the event storage interface that
+// posts the events in batches to a webhook. A batch is posted when it's full or
+// when its first event has waited FlushInterval, and retried when it fails. The
+// critical events are posted at once, since the node usually stops after them
+type WebhookEventStorage struct {
+	cfg    event.WebhookConfig
+	client *http.Client
+
+	queue     chan *event.Event
+	done      chan struct{}
+	closeOnce sync.Once
+	closeMux  sync.RWMutex
+	closed    bool
+}
+
+// NewWebhookEventStorage creates and initializes an instance of WebhookEventStorage,
+// using the default values for the parameters of the configuration not set
+func NewWebhookEventStorage(cfg event.WebhookConfig) (*WebhookEventStorage, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.FlushInterval.Duration <= 0 {
+		cfg.FlushInterval.Duration = defaultFlushInterval
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	if cfg.RetryInterval.Duration <= 0 {
+		cfg.RetryInterval.Duration = defaultRetryInterval
+	}
+	if cfg.Timeout.Duration <= 0 {
+		cfg.Timeout.Duration = defaultTimeout
+	}
+
+	s := &WebhookEventStorage{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout.Duration},
+		queue:  make(chan *event.Event, cfg.QueueSize),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// LogEvent queues an event to be posted following the defined interface. The events with
+// a critical or more severe level are posted before returning, waiting up to Timeout
+func (s *WebhookEventStorage) LogEvent(ctx context.Context, ev *event.Event) error {
+	s.closeMux.RLock()
+	defer s.closeMux.RUnlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	if isCritical(ev.Level) {
+		ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout.Duration)
+		defer cancel()
+		return s.post(ctx, []event.Record{event.NewRecord(ev)})
+	}
+
+	select {
+	case s.queue <- ev:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func isCritical(level event.Level) bool {
+	return level == event.Level_Critical || level == event.Level_Alert || level == event.Level_Emergency
+}
+
+// Close posts the queued events and stops the storage
+func (s *WebhookEventStorage) Close() error {
+	s.closeOnce.Do(func() {
+		s.closeMux.Lock()
+		s.closed = true
+		close(s.queue)
+		s.closeMux.Unlock()
+		<-s.done
+	})
+	return nil
+}
+
+func (s *WebhookEventStorage) run() {
+	defer close(s.done)
+
+	batch := make([]event.Record, 0, s.cfg.BatchSize)
+	timer := time.NewTimer(s.cfg.FlushInterval.Duration)
+	timer.Stop()
+	flush := func() {
+		if !timer.Stop() {
+			// drain the timer if it fired while the batch was being filled
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.post(context.Background(), batch); err != nil {
+			log.Errorf("%v, dropping them", err)
+		}
+		batch = make([]event.Record, 0, s.cfg.BatchSize)
+	}
+
+	for {
+		select {
+		case ev, ok := <-s.queue:
+			if !ok {
+				flush()
+				return
+			}
+			if len(batch) == 0 {
+				timer.Reset(s.cfg.FlushInterval.Duration)
+			}
+			batch = append(batch, event.NewRecord(ev))
+			if len(batch) >= s.cfg.BatchSize {
+				flush()
+			}
+		case <-timer.C:
+			flush()
+		}
+	}
+}
+
+// post sends a batch of events to the webhook, retrying with an exponential backoff
+// when it fails, until all the retries fail or the context is done
+func (s *WebhookEventStorage) post(ctx context.Context, batch []event.Record) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to encode %d events for the webhook: %w", len(batch), err)
+	}
+
+	retryInterval := s.cfg.RetryInterval.Duration
+	for attempt := 0; ; attempt++ {
+		err = s.send(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if attempt >= s.cfg.MaxRetries || ctx.Err() != nil {
+			return fmt.Errorf("failed to post %d events to the webhook after %d attempts: %w", len(batch), attempt+1, err)
+		}
+		log.Warnf("failed to post %d events to the webhook, retrying in %v, error: %v", len(batch), retryInterval, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to post %d events to the webhook after %d attempts: %w", len(batch), attempt+1, ctx.Err())
+		case <-time.After(retryInterval):
+		}
+		retryInterval *= 2
+	}
+}
+
+func (s *WebhookEventStorage) send(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.cfg.Headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package webhookeventstorage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type webhookServer struct {
+	*httptest.Server
+	mux      sync.Mutex
+	batches  [][]event.Record
+	failures int
+}
+
+func newWebhookServer(t *testing.T, failures int) *webhookServer {
+	s := &webhookServer{failures: failures}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		s.mux.Lock()
+		defer s.mux.Unlock()
+		if s.failures > 0 {
+			s.failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var batch []event.Record
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		s.batches = append(s.batches, batch)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *webhookServer) getBatches() [][]event.Record {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.batches
+}
+
+func TestWebhookEventStorage(t *testing.T) {
+	ctx := context.Background()
+	server := newWebhookServer(t, 1)
+
+	s, err := NewWebhookEventStorage(event.WebhookConfig{
+		URL:           server.URL,
+		Headers:       map[string]string{"Authorization": "Bearer token"},
+		BatchSize:     2,
+		FlushInterval: types.NewDuration(50 * time.Millisecond),
+		RetryInterval: types.NewDuration(time.Millisecond),
+	})
+	require.NoError(t, err)
+
+	// the full batch is posted at once, after retrying the failed request
+	require.NoError(t, s.LogEvent(ctx, &event.Event{EventID: event.EventID_FinalizerHalt}))
+	require.NoError(t, s.LogEvent(ctx, &event.Event{EventID: event.EventID_NodeOOC}))
+	require.Eventually(t, func() bool { return len(server.getBatches()) == 1 }, time.Second, time.Millisecond)
+	batch := server.getBatches()[0]
+	require.Len(t, batch, 2)
+	assert.Equal(t, event.EventID_FinalizerHalt, batch[0].EventID)
+	assert.Equal(t, event.EventID_NodeOOC, batch[1].EventID)
+
+	// the batch that is not full is posted after the flush interval
+	require.NoError(t, s.LogEvent(ctx, &event.Event{EventID: event.EventID_SynchronizerHalt}))
+	require.Eventually(t, func() bool { return len(server.getBatches()) == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, event.EventID_SynchronizerHalt, server.getBatches()[1][0].EventID)
+
+	// the queued events are posted when closing
+	require.NoError(t, s.LogEvent(ctx, &event.Event{EventID: event.EventID_L2BlockReorg}))
+	require.NoError(t, s.Close())
+	require.Len(t, server.getBatches(), 3)
+	assert.ErrorIs(t, s.LogEvent(ctx, &event.Event{}), ErrClosed)
+}
+
+func TestWebhookEventStorageDropsEvents(t *testing.T) {
+	ctx := context.Background()
+	server := newWebhookServer(t, 10)
+
+	s, err := NewWebhookEventStorage(event.WebhookConfig{
+		URL:           server.URL,
+		Headers:       map[string]string{"Authorization": "Bearer token"},
+		BatchSize:     1,
+		QueueSize:     1,
+		MaxRetries:    1,
+		RetryInterval: types.NewDuration(100 * time.Millisecond),
+	})
+	require.NoError(t, err)
+
+	// the first event is being posted while the second one waits in the queue
+	require.NoError(t, s.LogEvent(ctx, &event.Event{EventID: event.EventID_FinalizerHalt}))
+	require.Eventually(t, func() bool { return len(s.queue) == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, s.LogEvent(ctx, &event.Event{EventID: event.EventID_NodeOOC}))
+	assert.ErrorIs(t, s.LogEvent(ctx, &event.Event{EventID: event.EventID_L2BlockReorg}), ErrQueueFull)
+
+	// the events are dropped after failing all the retries
+	require.NoError(t, s.Close())
+	assert.Empty(t, server.getBatches())
+
+	_, err = NewWebhookEventStorage(event.WebhookConfig{})
+	assert.Error(t, err)
+}
+
+func TestWebhookEventStoragePostsCriticalEvents(t *testing.T) {
+	ctx := context.Background()
+	server := newWebhookServer(t, 1)
+
+	s, err := NewWebhookEventStorage(event.WebhookConfig{
+		URL:           server.URL,
+		Headers:       map[string]string{"Authorization": "Bearer token"},
+		FlushInterval: types.NewDuration(time.Hour),
+		RetryInterval: types.NewDuration(time.Millisecond),
+	})
+	require.NoError(t, err)
+	eventLog := event.NewEventLog(event.Config{}, event.NewFanOutStorage(
+		event.NewFilteredStorage(s, event.Filter{Levels: []event.Level{event.Level_Critical, event.Level_Warning}})))
+
+	// the halt event has been posted, after retrying the failed request, when the finalizer calls log.Fatal
+	require.NoError(t, eventLog.LogEvent(ctx, &event.Event{Level: event.Level_Warning, EventID: event.EventID_L2BlockReorg}))
+	require.NoError(t, eventLog.LogEvent(ctx, &event.Event{Level: event.Level_Critical, EventID: event.EventID_FinalizerHalt}))
+	require.Len(t, server.getBatches(), 1)
+	require.Len(t, server.getBatches()[0], 1)
+	assert.Equal(t, event.EventID_FinalizerHalt, server.getBatches()[0][0].EventID)
+
+	// the queued events are posted when closing the sinks
+	require.NoError(t, event.NewFanOutStorage(s).Close())
+	require.Len(t, server.getBatches(), 2)
+	assert.Equal(t, event.EventID_L2BlockReorg, server.getBatches()[1][0].EventID)
+}
+
+func TestWebhookEventStorageCriticalEventTimeout(t *testing.T) {
+	ctx := context.Background()
+	server := newWebhookServer(t, 1000)
+
+	s, err := NewWebhookEventStorage(event.WebhookConfig{
+		URL:           server.URL,
+		Headers:       map[string]string{"Authorization": "Bearer token"},
+		MaxRetries:    1000,
+		RetryInterval: types.NewDuration(10 * time.Millisecond),
+		Timeout:       types.NewDuration(100 * time.Millisecond),
+	})
+	require.NoError(t, err)
+	defer s.Close() //nolint:errcheck
+
+	// the retries stop when the timeout expires
+	start := time.Now()
+	err = s.LogEvent(ctx, &event.Event{Level: event.Level_Critical, EventID: event.EventID_FinalizerHalt})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Empty(t, server.getBatches())
+}
@@ -4,6 +4,7 @@ import (
 	"math/big"
 
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 )
 
 // EstimatorType different gas estimator types.
@@ -16,6 +17,8 @@ const (
 	LastNBatchesType EstimatorType = "lastnbatches"
 	// FollowerType calculate the gas price basing on the L1 gasPrice.
 	FollowerType EstimatorType = "follower"
+	// CongestionType calculate the gas price basing on the L2 congestion.
+	CongestionType EstimatorType = "congestion"
 )
 
 // Config for gas price estimator.
@@ -25,14 +28,41 @@ type Config struct {
 	// DefaultGasPriceWei is used to set the gas price to be used by the default gas pricer or as minimim gas price by the follower gas pricer.
 	DefaultGasPriceWei uint64 `mapstructure:"DefaultGasPriceWei"`
 	// MaxGasPriceWei is used to limit the gas price returned by the follower gas pricer to a maximum value. It is ignored if 0.
-	MaxGasPriceWei            uint64         `mapstructure:"MaxGasPriceWei"`
-	MaxPrice                  *big.Int       `mapstructure:"MaxPrice"`
-	IgnorePrice               *big.Int       `mapstructure:"IgnorePrice"`
-	CheckBlocks               int            `mapstructure:"CheckBlocks"`
-	Percentile                int            `mapstructure:"Percentile"`
-	UpdatePeriod              types.Duration `mapstructure:"UpdatePeriod"`
-	CleanHistoryPeriod        types.Duration `mapstructure:"CleanHistoryPeriod"`
+	MaxGasPriceWei     uint64         `mapstructure:"MaxGasPriceWei"`
+	MaxPrice           *big.Int       `mapstructure:"MaxPrice"`
+	IgnorePrice        *big.Int       `mapstructure:"IgnorePrice"`
+	CheckBlocks        int            `mapstructure:"CheckBlocks"`
+	Percentile         int            `mapstructure:"Percentile"`
+	UpdatePeriod       types.Duration `mapstructure:"UpdatePeriod"`
+	CleanHistoryPeriod types.Duration `mapstructure:"CleanHistoryPeriod"`
+	// CleanHistoryTimeRetention is how long the gas prices are kept. It must be at least the range returned by
+	// default by zkevm_getGasPriceHistory, one hour, or the history returned by the endpoint is truncated
 	CleanHistoryTimeRetention types.Duration `mapstructure:"CleanHistoryTimeRetention"`
 
 	Factor float64 `mapstructure:"Factor"`
+
+	// L1CostModel is the model used by the follower gas pricer to price the L1 data. The L1 blob base
+	// fee is stored along the gas prices when the model uses it, so the effective gas price can use it
+	L1CostModel pool.L1CostModelCfg `mapstructure:"L1CostModel"`
+
+	// Congestion is the configuration of the congestion gas pricer
+	Congestion CongestionConfig `mapstructure:"Congestion"`
+}
+
+// CongestionConfig is the configuration of the congestion gas pricer. The L2 gas price starts at
+// DefaultGasPriceWei and it's limited by DefaultGasPriceWei and MaxGasPriceWei.
+type CongestionConfig struct {
+	// TargetPendingTxs is the number of pending txs in the pool above which the gas price raises. It's ignored if 0.
+	TargetPendingTxs uint64 `mapstructure:"TargetPendingTxs"`
+	// TargetBatchFullness is the average usage of the most used ZK counter of the last batches,
+	// from 0 to 1, above which the gas price raises. It's ignored if 0.
+	TargetBatchFullness float64 `mapstructure:"TargetBatchFullness"`
+	// TargetResourcesUtilisation is the average usage of the bytes or the most reserved ZK counter
+	// of the last batches, from 0 to 1, above which the gas price raises. It's ignored if 0.
+	TargetResourcesUtilisation float64 `mapstructure:"TargetResourcesUtilisation"`
+	// CheckBatches is the number of last closed batches used to calculate their fullness and utilisation
+	CheckBatches uint `mapstructure:"CheckBatches"`
+	// MaxChangePerPeriod is the max ratio the gas price raises or decays every UpdatePeriod,
+	// e.g. 0.125 limits the changes to 12.5%
+	MaxChangePerPeriod float64 `mapstructure:"MaxChangePerPeriod"`
 }
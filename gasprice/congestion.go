@@ -0,0 +1,185 @@
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+// CongestionGasPrice struct for the gas price suggester that follows the L2 demand. The L2 gas
+// price raises while the pool depth, the fullness of the last batches or the utilisation of their
+// resources are over their targets, and decays while they are below them.
+type CongestionGasPrice struct {
+	cfg              Config
+	batchConstraints state.BatchConstraintsCfg
+	pool             poolInterface
+	ctx              context.Context
+	eth              ethermanInterface
+	state            stateInterface
+
+	// lastPrice is the last L2 gas price before being truncated, so small changes accumulate
+	lastPrice  *big.Float
+	l1GasPrice *big.Int
+}
+
+// newCongestionGasPriceSuggester inits the l2 gas price suggester based on the l2 congestion.
+func newCongestionGasPriceSuggester(ctx context.Context, cfg Config, batchConstraints state.BatchConstraintsCfg, pool poolInterface, ethMan ethermanInterface, state stateInterface) *CongestionGasPrice {
+	c := &CongestionGasPrice{
+		cfg:              cfg,
+		batchConstraints: batchConstraints,
+		pool:             pool,
+		ctx:              ctx,
+		eth:              ethMan,
+		state:            state,
+		lastPrice:        new(big.Float).SetUint64(cfg.DefaultGasPriceWei),
+		l1GasPrice:       big.NewInt(0),
+	}
+	c.loadLastPrice()
+	c.UpdateGasPriceAvg()
+	return c
+}
+
+// loadLastPrice sets the last L2 gas price stored in the pool as the starting price, so a restart
+// doesn't reset the price to the default one
+func (c *CongestionGasPrice) loadLastPrice() {
+	gasPrices, err := c.pool.GetGasPrices(c.ctx)
+	if errors.Is(err, state.ErrNotFound) || (err == nil && gasPrices.L2GasPrice == 0) {
+		return
+	} else if err != nil {
+		log.Errorf("failed to get the last l2 gas price, starting from the default one, err: %v", err)
+		return
+	}
+	c.lastPrice = new(big.Float).SetUint64(gasPrices.L2GasPrice)
+}
+
+// UpdateGasPriceAvg updates the gas price.
+func (c *CongestionGasPrice) UpdateGasPriceAvg() {
+	l1GasPrice := c.eth.GetL1GasPrice(c.ctx)
+	if big.NewInt(0).Cmp(l1GasPrice) == 0 {
+		log.Warn("gas price 0 received. Skipping update...")
+		return
+	}
+
+	l1BlobBaseFee := getL1BlobBaseFee(c.ctx, c.eth, c.cfg.L1CostModel)
+
+	congestion, err := c.congestion()
+	if err != nil {
+		log.Errorf("failed to calculate the l2 congestion, err: %v", err)
+		return
+	}
+
+	// congestion 1 means that the l2 is at its targets, the price changes proportionally to the
+	// distance to them, up to MaxChangePerPeriod
+	change := congestion - 1
+	change = math.Max(change, -c.cfg.Congestion.MaxChangePerPeriod)
+	change = math.Min(change, c.cfg.Congestion.MaxChangePerPeriod)
+	price := new(big.Float).Mul(c.lastPrice, big.NewFloat(1+change))
+
+	result := new(big.Int)
+	price.Int(result)
+	inputs := pool.GasPriceInputs{
+		Pricer:           string(CongestionType),
+		L1GasPriceSource: L1GasPriceSourceL1,
+		Congestion:       congestion,
+		RawL2GasPrice:    result.Uint64(),
+	}
+	minGasPrice := new(big.Int).SetUint64(c.cfg.DefaultGasPriceWei)
+	if minGasPrice.Cmp(result) == 1 { // minGasPrice > result
+		result = minGasPrice
+		inputs.Clamp = ClampMin
+	}
+	maxGasPrice := new(big.Int).SetUint64(c.cfg.MaxGasPriceWei)
+	if c.cfg.MaxGasPriceWei > 0 && result.Cmp(maxGasPrice) == 1 { // result > maxGasPrice
+		result = maxGasPrice
+		inputs.Clamp = ClampMax
+	}
+	c.lastPrice = new(big.Float).SetInt(result)
+
+	truncateValue := truncateGasPrice(result)
+	if truncateValue == nil {
+		log.Error("nil value detected. Skipping...")
+		return
+	}
+	inputs.Truncated = truncateValue.Cmp(result) != 0
+	log.Debugf("Congestion %f, storing L2 gas price: %v", congestion, truncateValue)
+	gasPrices := pool.GasPrices{L2GasPrice: truncateValue.Uint64(), L1GasPrice: l1GasPrice.Uint64(), L1BlobBaseFee: l1BlobBaseFee}
+	err = setGasPrices(c.ctx, c.pool, gasPrices, inputs)
+	if err != nil {
+		log.Errorf("failed to update gas price in poolDB, err: %v", err)
+	}
+}
+
+// congestion returns the highest ratio between the pool depth, the fullness of the last batches
+// and the utilisation of their resources, and their targets. The targets set to 0 are ignored.
+func (c *CongestionGasPrice) congestion() (float64, error) {
+	var congestion float64
+	cfg := c.cfg.Congestion
+	if cfg.TargetPendingTxs > 0 {
+		pendingTxs, err := c.pool.CountPendingTransactions(c.ctx)
+		if err != nil {
+			return 0, err
+		}
+		congestion = math.Max(congestion, float64(pendingTxs)/float64(cfg.TargetPendingTxs))
+	}
+
+	if cfg.CheckBatches == 0 || (cfg.TargetBatchFullness == 0 && cfg.TargetResourcesUtilisation == 0) {
+		return congestion, nil
+	}
+	batches, err := c.state.GetLastNBatches(c.ctx, cfg.CheckBatches+1, nil)
+	if err != nil {
+		return 0, err
+	}
+	var fullness, utilisation float64
+	var closedBatches int
+	for _, batch := range batches {
+		if batch.WIP || uint(closedBatches) == cfg.CheckBatches {
+			continue
+		}
+		closedBatches++
+		fullness += countersUsage(batch.Resources.ZKCounters, c.batchConstraints)
+		utilisation += resourcesUsage(batch, c.batchConstraints)
+	}
+	if closedBatches == 0 {
+		return congestion, nil
+	}
+	if cfg.TargetBatchFullness > 0 {
+		congestion = math.Max(congestion, fullness/float64(closedBatches)/cfg.TargetBatchFullness)
+	}
+	if cfg.TargetResourcesUtilisation > 0 {
+		congestion = math.Max(congestion, utilisation/float64(closedBatches)/cfg.TargetResourcesUtilisation)
+	}
+	return congestion, nil
+}
+
+// countersUsage returns the usage of the most used ZK counter of the batch, from 0 to 1
+func countersUsage(counters state.ZKCounters, constraints state.BatchConstraintsCfg) float64 {
+	usage := ratio(counters.GasUsed, constraints.MaxCumulativeGasUsed)
+	usage = math.Max(usage, ratio(uint64(counters.KeccakHashes), uint64(constraints.MaxKeccakHashes)))
+	usage = math.Max(usage, ratio(uint64(counters.PoseidonHashes), uint64(constraints.MaxPoseidonHashes)))
+	usage = math.Max(usage, ratio(uint64(counters.PoseidonPaddings), uint64(constraints.MaxPoseidonPaddings)))
+	usage = math.Max(usage, ratio(uint64(counters.MemAligns), uint64(constraints.MaxMemAligns)))
+	usage = math.Max(usage, ratio(uint64(counters.Arithmetics), uint64(constraints.MaxArithmetics)))
+	usage = math.Max(usage, ratio(uint64(counters.Binaries), uint64(constraints.MaxBinaries)))
+	usage = math.Max(usage, ratio(uint64(counters.Steps), uint64(constraints.MaxSteps)))
+	usage = math.Max(usage, ratio(uint64(counters.Sha256Hashes_V2), uint64(constraints.MaxSHA256Hashes)))
+	return usage
+}
+
+// resourcesUsage returns the usage of the most used resource of the batch, from 0 to 1: its
+// bytes or the ZK counters reserved while it was being built
+func resourcesUsage(batch *state.Batch, constraints state.BatchConstraintsCfg) float64 {
+	usage := ratio(batch.Resources.Bytes, constraints.MaxBatchBytesSize)
+	return math.Max(usage, countersUsage(batch.HighReservedZKCounters, constraints))
+}
+
+func ratio(used, limit uint64) float64 {
+	if limit == 0 {
+		return 0
+	}
+	return float64(used) / float64(limit)
+}
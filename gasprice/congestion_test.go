@@ -0,0 +1,177 @@
+package gasprice
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateGasPriceCongestion(t *testing.T) {
+	ctx := context.Background()
+	l1GasPrice := big.NewInt(10000000000)
+	batchConstraints := state.BatchConstraintsCfg{
+		MaxBatchBytesSize: 100,
+		MaxSteps:          100,
+	}
+
+	testCases := []struct {
+		name               string
+		pendingTxs         uint64
+		batches            []*state.Batch
+		expectedL2GasPrice uint64
+		expectedInputs     pool.GasPriceInputs
+	}{
+		{
+			name:               "idle decays to the min gas price",
+			expectedL2GasPrice: 1000000000,
+			expectedInputs: pool.GasPriceInputs{
+				RawL2GasPrice: 500000000,
+				Clamp:         ClampMin,
+			},
+		},
+		{
+			name:               "pool depth over its target",
+			pendingTxs:         1100,
+			expectedL2GasPrice: 1100000000,
+			expectedInputs: pool.GasPriceInputs{
+				Congestion:    1.1,
+				RawL2GasPrice: 1100000000,
+			},
+		},
+		{
+			name:       "batch fullness over its target, the change is limited",
+			pendingTxs: 100,
+			batches: []*state.Batch{
+				{WIP: true, Resources: state.BatchResources{ZKCounters: state.ZKCounters{Steps: 10}}},
+				{Resources: state.BatchResources{ZKCounters: state.ZKCounters{Steps: 80}}},
+				{Resources: state.BatchResources{ZKCounters: state.ZKCounters{Steps: 80}}},
+				{Resources: state.BatchResources{ZKCounters: state.ZKCounters{Steps: 10}}},
+			},
+			expectedL2GasPrice: 1500000000,
+			expectedInputs: pool.GasPriceInputs{
+				Congestion:    1.6,
+				RawL2GasPrice: 1500000000,
+			},
+		},
+		{
+			name: "resources utilisation over its target, the change is limited",
+			batches: []*state.Batch{
+				{Resources: state.BatchResources{Bytes: 90}},
+				{HighReservedZKCounters: state.ZKCounters{Steps: 90}},
+			},
+			expectedL2GasPrice: 1500000000,
+			expectedInputs: pool.GasPriceInputs{
+				Congestion:    2,
+				RawL2GasPrice: 1500000000,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{
+				Type:               CongestionType,
+				DefaultGasPriceWei: 1000000000,
+				Congestion: CongestionConfig{
+					TargetPendingTxs:           1000,
+					TargetBatchFullness:        0.5,
+					TargetResourcesUtilisation: 0.45,
+					CheckBatches:               2,
+					MaxChangePerPeriod:         0.5,
+				},
+			}
+			tc.expectedInputs.Pricer = string(CongestionType)
+			tc.expectedInputs.L1GasPriceSource = L1GasPriceSourceL1
+
+			poolM := new(poolMock)
+			ethM := new(ethermanMock)
+			stateM := new(stateMock)
+			ethM.On("GetL1GasPrice", ctx).Return(l1GasPrice)
+			poolM.On("GetGasPrices", ctx).Return(pool.GasPrices{}, state.ErrNotFound).Once()
+			poolM.On("CountPendingTransactions", ctx).Return(tc.pendingTxs, nil).Once()
+			stateM.On("GetLastNBatches", ctx, uint(3), nil).Return(tc.batches, nil).Once()
+			poolM.On("SetGasPricesWithInputs", ctx, pool.GasPrices{L2GasPrice: tc.expectedL2GasPrice, L1GasPrice: l1GasPrice.Uint64()}, tc.expectedInputs).Return(nil).Once()
+
+			newCongestionGasPriceSuggester(ctx, cfg, batchConstraints, poolM, ethM, stateM)
+			poolM.AssertExpectations(t)
+			stateM.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateGasPriceCongestionAccumulatesTruncatedChanges(t *testing.T) {
+	ctx := context.Background()
+	l1GasPrice := big.NewInt(10000000000)
+	cfg := Config{
+		Type:               CongestionType,
+		DefaultGasPriceWei: 1000000000,
+		MaxGasPriceWei:     1300000000,
+		Congestion: CongestionConfig{
+			TargetPendingTxs:   1000,
+			MaxChangePerPeriod: 0.125,
+		},
+	}
+	poolM := new(poolMock)
+	ethM := new(ethermanMock)
+	ethM.On("GetL1GasPrice", ctx).Return(l1GasPrice)
+	poolM.On("GetGasPrices", ctx).Return(pool.GasPrices{}, state.ErrNotFound).Once()
+	poolM.On("CountPendingTransactions", ctx).Return(uint64(5000), nil)
+
+	inputs := pool.GasPriceInputs{
+		Pricer:           string(CongestionType),
+		L1GasPriceSource: L1GasPriceSourceL1,
+		Congestion:       5,
+		RawL2GasPrice:    1125000000,
+		Truncated:        true,
+	}
+	poolM.On("SetGasPricesWithInputs", ctx, pool.GasPrices{L2GasPrice: 1120000000, L1GasPrice: l1GasPrice.Uint64()}, inputs).Return(nil).Once()
+	c := newCongestionGasPriceSuggester(ctx, cfg, state.BatchConstraintsCfg{}, poolM, ethM, nil)
+
+	// the change is applied to the untruncated price, and the max gas price limits it
+	inputs.RawL2GasPrice = 1265625000
+	poolM.On("SetGasPricesWithInputs", ctx, pool.GasPrices{L2GasPrice: 1260000000, L1GasPrice: l1GasPrice.Uint64()}, inputs).Return(nil).Once()
+	c.UpdateGasPriceAvg()
+
+	inputs.RawL2GasPrice = 1423828125
+	inputs.Clamp = ClampMax
+	inputs.Truncated = false
+	poolM.On("SetGasPricesWithInputs", ctx, pool.GasPrices{L2GasPrice: 1300000000, L1GasPrice: l1GasPrice.Uint64()}, inputs).Return(nil).Once()
+	c.UpdateGasPriceAvg()
+
+	poolM.AssertExpectations(t)
+	assert.Equal(t, big.NewFloat(1300000000).String(), c.lastPrice.String())
+}
+
+func TestUpdateGasPriceCongestionStartsFromLastStoredPrice(t *testing.T) {
+	ctx := context.Background()
+	l1GasPrice := big.NewInt(10000000000)
+	cfg := Config{
+		Type:               CongestionType,
+		DefaultGasPriceWei: 1000000000,
+		Congestion: CongestionConfig{
+			TargetPendingTxs:   1000,
+			MaxChangePerPeriod: 0.125,
+		},
+	}
+	poolM := new(poolMock)
+	ethM := new(ethermanMock)
+	ethM.On("GetL1GasPrice", ctx).Return(l1GasPrice)
+	poolM.On("GetGasPrices", ctx).Return(pool.GasPrices{L2GasPrice: 2000000000, L1GasPrice: l1GasPrice.Uint64()}, nil).Once()
+	poolM.On("CountPendingTransactions", ctx).Return(uint64(1000), nil).Once()
+
+	// at its targets the price keeps the one stored before the restart instead of the default one
+	inputs := pool.GasPriceInputs{
+		Pricer:           string(CongestionType),
+		L1GasPriceSource: L1GasPriceSourceL1,
+		Congestion:       1,
+		RawL2GasPrice:    2000000000,
+	}
+	poolM.On("SetGasPricesWithInputs", ctx, pool.GasPrices{L2GasPrice: 2000000000, L1GasPrice: l1GasPrice.Uint64()}, inputs).Return(nil).Once()
+	newCongestionGasPriceSuggester(ctx, cfg, state.BatchConstraintsCfg{}, poolM, ethM, nil)
+
+	poolM.AssertExpectations(t)
+}
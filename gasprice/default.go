@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 )
 
 // DefaultGasPricer gas price from config is set.
@@ -33,14 +35,17 @@ func newDefaultGasPriceSuggester(ctx context.Context, cfg Config, pool poolInter
 
 // UpdateGasPriceAvg not needed for default strategy.
 func (d *DefaultGasPricer) UpdateGasPriceAvg() {
-	err := d.pool.SetGasPrices(d.ctx, d.cfg.DefaultGasPriceWei, d.l1GasPrice)
-	if err != nil {
-		panic(fmt.Errorf("failed to set default gas price, err: %v", err))
-	}
+	d.setDefaultGasPrice()
 }
 
 func (d *DefaultGasPricer) setDefaultGasPrice() {
-	err := d.pool.SetGasPrices(d.ctx, d.cfg.DefaultGasPriceWei, d.l1GasPrice)
+	inputs := pool.GasPriceInputs{
+		Pricer:           string(DefaultType),
+		L1GasPriceSource: L1GasPriceSourceConfig,
+		Factor:           d.cfg.Factor,
+		RawL2GasPrice:    d.cfg.DefaultGasPriceWei,
+	}
+	err := setGasPrices(d.ctx, d.pool, pool.GasPrices{L2GasPrice: d.cfg.DefaultGasPriceWei, L1GasPrice: d.l1GasPrice}, inputs)
 	if err != nil {
 		panic(fmt.Errorf("failed to set default gas price, err: %v", err))
 	}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 )
 
 func init() {
@@ -28,7 +29,14 @@ func TestUpdateGasPriceDefault(t *testing.T) {
 	l1GasPrice := new(big.Int).Mul(defaultGasPriceDivByFactor, big.NewInt(100)).Uint64() // nolint:gomnd
 
 	poolM := new(poolMock)
-	poolM.On("SetGasPrices", ctx, cfg.DefaultGasPriceWei, l1GasPrice).Return(nil).Twice()
+	inputs := pool.GasPriceInputs{
+		Pricer:           string(DefaultType),
+		L1GasPriceSource: L1GasPriceSourceConfig,
+		Factor:           cfg.Factor,
+		RawL2GasPrice:    cfg.DefaultGasPriceWei,
+	}
+	poolM.On("SetGasPricesWithInputs", ctx, pool.GasPrices{L2GasPrice: cfg.DefaultGasPriceWei, L1GasPrice: l1GasPrice}, inputs).Return(nil).Twice()
 	dge := newDefaultGasPriceSuggester(ctx, cfg, poolM)
 	dge.UpdateGasPriceAvg()
+	poolM.AssertExpectations(t)
 }
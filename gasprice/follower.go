@@ -8,6 +8,7 @@ import (
 
 	"github.com/0xPolygonHermez/zkevm-node/encoding"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 )
 
 // FollowerGasPrice struct.
@@ -40,40 +41,44 @@ func (f *FollowerGasPrice) UpdateGasPriceAvg() {
 		return
 	}
 
+	// Get the L1 gas price that prices the L1 data with the L1 cost model
+	l1BlobBaseFee := getL1BlobBaseFee(f.ctx, f.eth, f.cfg.L1CostModel)
+	l1CostModel := f.cfg.L1CostModel.Model(l1BlobBaseFee)
+	l1DataGasPrice := new(big.Int).SetUint64(f.cfg.L1CostModel.L1GasPrice(l1GasPrice.Uint64(), l1BlobBaseFee))
+
 	// Apply factor to calculate l2 gasPrice
 	factor := big.NewFloat(0).SetFloat64(f.cfg.Factor)
-	res := new(big.Float).Mul(factor, big.NewFloat(0).SetInt(l1GasPrice))
+	res := new(big.Float).Mul(factor, big.NewFloat(0).SetInt(l1DataGasPrice))
 
 	// Store l2 gasPrice calculated
 	result := new(big.Int)
 	res.Int(result)
+	inputs := pool.GasPriceInputs{
+		Pricer:           string(FollowerType),
+		L1GasPriceSource: L1GasPriceSourceL1,
+		L1CostModel:      string(l1CostModel),
+		Factor:           f.cfg.Factor,
+		RawL2GasPrice:    result.Uint64(),
+	}
 	minGasPrice := big.NewInt(0).SetUint64(f.cfg.DefaultGasPriceWei)
 	if minGasPrice.Cmp(result) == 1 { // minGasPrice > result
 		log.Warn("setting DefaultGasPriceWei for L2")
 		result = minGasPrice
+		inputs.Clamp = ClampMin
 	}
 	maxGasPrice := new(big.Int).SetUint64(f.cfg.MaxGasPriceWei)
 	if f.cfg.MaxGasPriceWei > 0 && result.Cmp(maxGasPrice) == 1 { // result > maxGasPrice
 		log.Warn("setting MaxGasPriceWei for L2")
 		result = maxGasPrice
+		inputs.Clamp = ClampMax
 	}
-	var truncateValue *big.Int
 	log.Debug("Full L2 gas price value: ", result, ". Length: ", len(result.String()))
-	numLength := len(result.String())
-	if numLength > 3 { //nolint:gomnd
-		aux := "%0" + strconv.Itoa(numLength-3) + "d" //nolint:gomnd
-		var ok bool
-		value := result.String()[:3] + fmt.Sprintf(aux, 0)
-		truncateValue, ok = new(big.Int).SetString(value, encoding.Base10)
-		if !ok {
-			log.Error("error converting: ", truncateValue)
-		}
-	} else {
-		truncateValue = result
-	}
+	truncateValue := truncateGasPrice(result)
 	log.Debug("Storing truncated L2 gas price: ", truncateValue)
 	if truncateValue != nil {
-		err := f.pool.SetGasPrices(ctx, truncateValue.Uint64(), l1GasPrice.Uint64())
+		inputs.Truncated = truncateValue.Cmp(result) != 0
+		gasPrices := pool.GasPrices{L2GasPrice: truncateValue.Uint64(), L1GasPrice: l1GasPrice.Uint64(), L1BlobBaseFee: l1BlobBaseFee}
+		err := setGasPrices(ctx, f.pool, gasPrices, inputs)
 		if err != nil {
 			log.Errorf("failed to update gas price in poolDB, err: %v", err)
 		}
@@ -81,3 +86,20 @@ func (f *FollowerGasPrice) UpdateGasPriceAvg() {
 		log.Error("nil value detected. Skipping...")
 	}
 }
+
+// truncateGasPrice keeps the 3 most significant digits of the gas price, setting the rest to 0.
+// It returns nil if the truncated value can't be converted
+func truncateGasPrice(gasPrice *big.Int) *big.Int {
+	numLength := len(gasPrice.String())
+	if numLength <= 3 { //nolint:gomnd
+		return gasPrice
+	}
+	aux := "%0" + strconv.Itoa(numLength-3) + "d" //nolint:gomnd
+	value := gasPrice.String()[:3] + fmt.Sprintf(aux, 0)
+	truncateValue, ok := new(big.Int).SetString(value, encoding.Base10)
+	if !ok {
+		log.Error("error converting: ", value)
+		return nil
+	}
+	return truncateValue
+}
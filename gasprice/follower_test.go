@@ -8,6 +8,7 @@ import (
 
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 )
 
 func init() {
@@ -31,12 +32,19 @@ func TestUpdateGasPriceFollower(t *testing.T) {
 	l2GasPrice := uint64(5000000000)
 	poolM := new(poolMock)
 	ethM := new(ethermanMock)
+	inputs := pool.GasPriceInputs{
+		Pricer:           string(FollowerType),
+		L1GasPriceSource: L1GasPriceSourceL1,
+		L1CostModel:      string(pool.CalldataL1CostModel),
+		Factor:           cfg.Factor,
+		RawL2GasPrice:    l2GasPrice,
+	}
 	ethM.On("GetL1GasPrice", ctx).Return(l1GasPrice).Once()
-	poolM.On("SetGasPrices", ctx, l2GasPrice, l1GasPrice.Uint64()).Return(nil).Once()
+	poolM.On("SetGasPricesWithInputs", ctx, pool.GasPrices{L2GasPrice: l2GasPrice, L1GasPrice: l1GasPrice.Uint64()}, inputs).Return(nil).Once()
 	f := newFollowerGasPriceSuggester(ctx, cfg, poolM, ethM)
 
 	ethM.On("GetL1GasPrice", ctx).Return(l1GasPrice, l1GasPrice).Once()
-	poolM.On("SetGasPrices", ctx, l2GasPrice, l1GasPrice.Uint64()).Return(nil).Once()
+	poolM.On("SetGasPricesWithInputs", ctx, pool.GasPrices{L2GasPrice: l2GasPrice, L1GasPrice: l1GasPrice.Uint64()}, inputs).Return(nil).Once()
 	f.UpdateGasPriceAvg()
 }
 
@@ -55,8 +63,129 @@ func TestLimitMasGasPrice(t *testing.T) {
 	poolM := new(poolMock)
 	ethM := new(ethermanMock)
 	ethM.On("GetL1GasPrice", ctx).Return(l1GasPrice)
-	// Ensure SetGasPrices is called with the MaxGasPriceWei
-	poolM.On("SetGasPrices", ctx, cfg.MaxGasPriceWei, l1GasPrice.Uint64()).Return(nil)
+	// Ensure SetGasPricesWithInputs is called with the MaxGasPriceWei
+	inputs := pool.GasPriceInputs{
+		Pricer:           string(FollowerType),
+		L1GasPriceSource: L1GasPriceSourceL1,
+		L1CostModel:      string(pool.CalldataL1CostModel),
+		Factor:           cfg.Factor,
+		RawL2GasPrice:    500000000,
+		Clamp:            ClampMax,
+	}
+	poolM.On("SetGasPricesWithInputs", ctx, pool.GasPrices{L2GasPrice: cfg.MaxGasPriceWei, L1GasPrice: l1GasPrice.Uint64()}, inputs).Return(nil)
 	f := newFollowerGasPriceSuggester(ctx, cfg, poolM, ethM)
 	f.UpdateGasPriceAvg()
 }
+
+func TestUpdateGasPriceFollowerInputs(t *testing.T) {
+	ctx := context.Background()
+	var d time.Duration = 1000000000
+
+	testCases := []struct {
+		name               string
+		defaultGasPriceWei uint64
+		l1CostModel        pool.L1CostModelCfg
+		l1GasPrice         *big.Int
+		l1BlobBaseFee      *big.Int
+		expectedL2GasPrice uint64
+		expectedInputs     pool.GasPriceInputs
+	}{
+		{
+			name:               "truncated",
+			defaultGasPriceWei: 1000000000,
+			l1GasPrice:         big.NewInt(12345678900),
+			expectedL2GasPrice: 3080000000,
+			expectedInputs: pool.GasPriceInputs{
+				Pricer:           string(FollowerType),
+				L1GasPriceSource: L1GasPriceSourceL1,
+				L1CostModel:      string(pool.CalldataL1CostModel),
+				Factor:           0.25,
+				RawL2GasPrice:    3086419725,
+				Truncated:        true,
+			},
+		},
+		{
+			name:               "clamped to min",
+			defaultGasPriceWei: 1000000000,
+			l1GasPrice:         big.NewInt(2000000000),
+			expectedL2GasPrice: 1000000000,
+			expectedInputs: pool.GasPriceInputs{
+				Pricer:           string(FollowerType),
+				L1GasPriceSource: L1GasPriceSourceL1,
+				L1CostModel:      string(pool.CalldataL1CostModel),
+				Factor:           0.25,
+				RawL2GasPrice:    500000000,
+				Clamp:            ClampMin,
+			},
+		},
+		{
+			name:               "blob l1 cost model",
+			defaultGasPriceWei: 100000000,
+			l1CostModel:        pool.L1CostModelCfg{Type: pool.BlobL1CostModel, BlobBytesPerBatch: 65536},
+			l1GasPrice:         big.NewInt(10000000000),
+			l1BlobBaseFee:      big.NewInt(8000000000),
+			expectedL2GasPrice: 250000000,
+			expectedInputs: pool.GasPriceInputs{
+				Pricer:           string(FollowerType),
+				L1GasPriceSource: L1GasPriceSourceL1,
+				L1CostModel:      string(pool.BlobL1CostModel),
+				Factor:           0.25,
+				RawL2GasPrice:    250000000,
+			},
+		},
+		{
+			name:               "mix l1 cost model",
+			defaultGasPriceWei: 100000000,
+			l1CostModel:        pool.L1CostModelCfg{Type: pool.MixL1CostModel, BlobBytesPerBatch: 65536, BlobWeight: 0.5},
+			l1GasPrice:         big.NewInt(10000000000),
+			l1BlobBaseFee:      big.NewInt(8000000000),
+			expectedL2GasPrice: 1370000000,
+			expectedInputs: pool.GasPriceInputs{
+				Pricer:           string(FollowerType),
+				L1GasPriceSource: L1GasPriceSourceL1,
+				L1CostModel:      string(pool.MixL1CostModel),
+				Factor:           0.25,
+				RawL2GasPrice:    1375000000,
+				Truncated:        true,
+			},
+		},
+		{
+			name:               "blob l1 cost model with unknown blob base fee",
+			defaultGasPriceWei: 100000000,
+			l1CostModel:        pool.L1CostModelCfg{Type: pool.BlobL1CostModel, BlobBytesPerBatch: 65536},
+			l1GasPrice:         big.NewInt(10000000000),
+			l1BlobBaseFee:      big.NewInt(0),
+			expectedL2GasPrice: 2500000000,
+			expectedInputs: pool.GasPriceInputs{
+				Pricer:           string(FollowerType),
+				L1GasPriceSource: L1GasPriceSourceL1,
+				L1CostModel:      string(pool.CalldataL1CostModel),
+				Factor:           0.25,
+				RawL2GasPrice:    2500000000,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{
+				Type:               FollowerType,
+				DefaultGasPriceWei: tc.defaultGasPriceWei,
+				UpdatePeriod:       types.NewDuration(d),
+				Factor:             0.25,
+				L1CostModel:        tc.l1CostModel,
+			}
+			gasPrices := pool.GasPrices{L2GasPrice: tc.expectedL2GasPrice, L1GasPrice: tc.l1GasPrice.Uint64()}
+			poolM := new(poolMock)
+			ethM := new(ethermanMock)
+			ethM.On("GetL1GasPrice", ctx).Return(tc.l1GasPrice)
+			if tc.l1BlobBaseFee != nil {
+				ethM.On("GetL1BlobBaseFee", ctx).Return(tc.l1BlobBaseFee)
+				gasPrices.L1BlobBaseFee = tc.l1BlobBaseFee.Uint64()
+			}
+			poolM.On("SetGasPricesWithInputs", ctx, gasPrices, tc.expectedInputs).Return(nil).Once()
+			newFollowerGasPriceSuggester(ctx, cfg, poolM, ethM)
+			poolM.AssertExpectations(t)
+		})
+	}
+}
@@ -5,17 +5,35 @@ import (
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/gasprice/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 )
 
+const (
+	// L1GasPriceSourceL1 is used when the L1 gas price is read from L1
+	L1GasPriceSourceL1 = "l1"
+	// L1GasPriceSourceConfig is used when the L1 gas price is derived from DefaultGasPriceWei
+	L1GasPriceSourceConfig = "config"
+	// L1GasPriceSourceL2Txs is used when the L1 gas price is derived from the gas price of the L2 txs
+	L1GasPriceSourceL2Txs = "l2txs"
+
+	// ClampMin is used when the L2 gas price is raised to the min gas price
+	ClampMin = "min"
+	// ClampMax is used when the L2 gas price is lowered to the max gas price
+	ClampMax = "max"
+)
+
 // L2GasPricer interface for gas price suggester.
 type L2GasPricer interface {
 	UpdateGasPriceAvg()
 }
 
 // NewL2GasPriceSuggester init.
-func NewL2GasPriceSuggester(ctx context.Context, cfg Config, pool poolInterface, ethMan *etherman.Client, state *state.State) {
+func NewL2GasPriceSuggester(ctx context.Context, cfg Config, batchConstraints state.BatchConstraintsCfg, pool poolInterface, ethMan *etherman.Client, state *state.State) {
+	metrics.Register()
+
 	var gpricer L2GasPricer
 	switch cfg.Type {
 	case LastNBatchesType:
@@ -27,8 +45,11 @@ func NewL2GasPriceSuggester(ctx context.Context, cfg Config, pool poolInterface,
 	case DefaultType:
 		log.Info("Default type selected")
 		gpricer = newDefaultGasPriceSuggester(ctx, cfg, pool)
+	case CongestionType:
+		log.Info("Congestion type selected")
+		gpricer = newCongestionGasPriceSuggester(ctx, cfg, batchConstraints, pool, ethMan, state)
 	default:
-		log.Fatal("unknown l2 gas price suggester type ", cfg.Type, ". Please specify a valid one: 'lastnbatches', 'follower' or 'default'")
+		log.Fatal("unknown l2 gas price suggester type ", cfg.Type, ". Please specify a valid one: 'lastnbatches', 'follower', 'congestion' or 'default'")
 	}
 
 	updateTimer := time.NewTimer(cfg.UpdatePeriod.Duration)
@@ -55,3 +76,23 @@ func cleanGasPriceHistory(pool poolInterface, timeRetention time.Duration) {
 		log.Errorf("failed to delete pool gas price history: %v", err)
 	}
 }
+
+// getL1BlobBaseFee returns the L1 blob base fee if the L1 cost model uses it, and 0 otherwise
+func getL1BlobBaseFee(ctx context.Context, eth ethermanInterface, cfg pool.L1CostModelCfg) uint64 {
+	if !cfg.UsesBlobBaseFee() {
+		return 0
+	}
+	return eth.GetL1BlobBaseFee(ctx).Uint64()
+}
+
+// setGasPrices stores the gas prices with the inputs used to calculate them in the gas price
+// history, and exports them as metrics
+func setGasPrices(ctx context.Context, p poolInterface, gasPrices pool.GasPrices, inputs pool.GasPriceInputs) error {
+	err := p.SetGasPricesWithInputs(ctx, gasPrices, inputs)
+	if err != nil {
+		return err
+	}
+	metrics.GasPrices(gasPrices.L2GasPrice, gasPrices.L1GasPrice, gasPrices.L1BlobBaseFee)
+	metrics.Inputs(inputs.RawL2GasPrice, inputs.Factor, inputs.Congestion, inputs.Clamp == ClampMin, inputs.Clamp == ClampMax, inputs.Truncated)
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/jackc/pgx/v4"
 )
@@ -14,18 +15,21 @@ import (
 
 // poolInterface contains methods to interact with the tx poolInterface.
 type poolInterface interface {
-	SetGasPrices(ctx context.Context, l2GasPrice uint64, l1GasPrice uint64) error
+	SetGasPricesWithInputs(ctx context.Context, gasPrices pool.GasPrices, inputs pool.GasPriceInputs) error
 	GetGasPrices(ctx context.Context) (pool.GasPrices, error)
 	DeleteGasPricesHistoryOlderThan(ctx context.Context, date time.Time) error
+	CountPendingTransactions(ctx context.Context) (uint64, error)
 }
 
 // stateInterface gathers the methods required to interact with the state.
 type stateInterface interface {
 	GetLastL2BlockNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
 	GetTxsByBlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]*types.Transaction, error)
+	GetLastNBatches(ctx context.Context, numBatches uint, dbTx pgx.Tx) ([]*state.Batch, error)
 }
 
 // ethermanInterface contains the methods required to interact with ethereum.
 type ethermanInterface interface {
 	GetL1GasPrice(ctx context.Context) *big.Int
+	GetL1BlobBaseFee(ctx context.Context) *big.Int
 }
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 )
 
 const sampleNumber = 3 // Number of transactions sampled in a batch.
@@ -87,8 +88,15 @@ func (g *LastNL2BlocksGasPrice) UpdateGasPriceAvg() {
 		sort.Sort(bigIntArray(results))
 		price = results[(len(results)-1)*g.cfg.Percentile/100]
 	}
+	inputs := pool.GasPriceInputs{
+		Pricer:           string(LastNBatchesType),
+		L1GasPriceSource: L1GasPriceSourceL2Txs,
+		Factor:           g.cfg.Factor,
+		RawL2GasPrice:    price.Uint64(),
+	}
 	if price.Cmp(g.cfg.MaxPrice) > 0 {
 		price = g.cfg.MaxPrice
+		inputs.Clamp = ClampMax
 	}
 
 	g.cacheLock.Lock()
@@ -101,7 +109,7 @@ func (g *LastNL2BlocksGasPrice) UpdateGasPriceAvg() {
 	factor := big.NewInt(factorAsPercentage)
 	l1GasPriceDivBy100 := new(big.Int).Div(g.lastPrice, factor)
 	l1GasPrice := l1GasPriceDivBy100.Mul(l1GasPriceDivBy100, big.NewInt(100)) // nolint:gomnd
-	err = g.pool.SetGasPrices(g.ctx, g.lastPrice.Uint64(), l1GasPrice.Uint64())
+	err = setGasPrices(g.ctx, g.pool, pool.GasPrices{L2GasPrice: g.lastPrice.Uint64(), L1GasPrice: l1GasPrice.Uint64()}, inputs)
 	if err != nil {
 		log.Errorf("failed to update gas price in poolDB, err: %v", err)
 	}
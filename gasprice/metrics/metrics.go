@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"github.com/0xPolygonHermez/zkevm-node/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// Prefix for the metrics of the gasprice package.
+	Prefix = "gasprice_"
+	// L2GasPriceName is the name of the metric that shows the L2 gas price set.
+	L2GasPriceName = Prefix + "l2_gas_price"
+	// L1GasPriceName is the name of the metric that shows the L1 gas price set.
+	L1GasPriceName = Prefix + "l1_gas_price"
+	// L1BlobBaseFeeName is the name of the metric that shows the L1 blob base fee set.
+	L1BlobBaseFeeName = Prefix + "l1_blob_base_fee"
+	// RawL2GasPriceName is the name of the metric that shows the L2 gas price before being clamped and truncated.
+	RawL2GasPriceName = Prefix + "raw_l2_gas_price"
+	// FactorName is the name of the metric that shows the factor applied to the L1 gas price.
+	FactorName = Prefix + "factor"
+	// CongestionName is the name of the metric that shows the L2 congestion used by the congestion gas pricer.
+	CongestionName = Prefix + "congestion"
+	// ClampedToMinName is the name of the metric that shows if the L2 gas price was raised to the min gas price.
+	ClampedToMinName = Prefix + "clamped_to_min"
+	// ClampedToMaxName is the name of the metric that shows if the L2 gas price was lowered to the max gas price.
+	ClampedToMaxName = Prefix + "clamped_to_max"
+	// TruncatedName is the name of the metric that shows if the L2 gas price was truncated.
+	TruncatedName = Prefix + "truncated"
+)
+
+// Register the metrics for the gasprice package.
+func Register() {
+	gauges := []prometheus.GaugeOpts{
+		{
+			Name: L2GasPriceName,
+			Help: "[GASPRICE] L2 gas price set, in wei",
+		},
+		{
+			Name: L1GasPriceName,
+			Help: "[GASPRICE] L1 gas price set, in wei",
+		},
+		{
+			Name: L1BlobBaseFeeName,
+			Help: "[GASPRICE] L1 blob base fee set, in wei. 0 if the L1 cost model doesn't use it",
+		},
+		{
+			Name: RawL2GasPriceName,
+			Help: "[GASPRICE] L2 gas price before being clamped and truncated, in wei",
+		},
+		{
+			Name: FactorName,
+			Help: "[GASPRICE] factor applied to the L1 gas price to calculate the L2 gas price",
+		},
+		{
+			Name: CongestionName,
+			Help: "[GASPRICE] L2 congestion used by the congestion gas pricer, 1 when the L2 is at its targets",
+		},
+		{
+			Name: ClampedToMinName,
+			Help: "[GASPRICE] 1 if the last L2 gas price was raised to the min gas price, 0 otherwise",
+		},
+		{
+			Name: ClampedToMaxName,
+			Help: "[GASPRICE] 1 if the last L2 gas price was lowered to the max gas price, 0 otherwise",
+		},
+		{
+			Name: TruncatedName,
+			Help: "[GASPRICE] 1 if the last L2 gas price was truncated to its 3 most significant digits, 0 otherwise",
+		},
+	}
+
+	metrics.RegisterGauges(gauges...)
+}
+
+// GasPrices sets the gauges of the gas prices set.
+func GasPrices(l2GasPrice, l1GasPrice, l1BlobBaseFee uint64) {
+	metrics.GaugeSet(L2GasPriceName, float64(l2GasPrice))
+	metrics.GaugeSet(L1GasPriceName, float64(l1GasPrice))
+	metrics.GaugeSet(L1BlobBaseFeeName, float64(l1BlobBaseFee))
+}
+
+// Inputs sets the gauges of the inputs used to calculate the gas prices.
+func Inputs(rawL2GasPrice uint64, factor, congestion float64, clampedToMin, clampedToMax, truncated bool) {
+	metrics.GaugeSet(RawL2GasPriceName, float64(rawL2GasPrice))
+	metrics.GaugeSet(FactorName, factor)
+	metrics.GaugeSet(CongestionName, congestion)
+	metrics.GaugeSet(ClampedToMinName, boolToFloat(clampedToMin))
+	metrics.GaugeSet(ClampedToMaxName, boolToFloat(clampedToMax))
+	metrics.GaugeSet(TruncatedName, boolToFloat(truncated))
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -22,6 +22,54 @@ func (_m *ethermanMock) EXPECT() *ethermanMock_Expecter {
 	return &ethermanMock_Expecter{mock: &_m.Mock}
 }
 
+// GetL1BlobBaseFee provides a mock function with given fields: ctx
+func (_m *ethermanMock) GetL1BlobBaseFee(ctx context.Context) *big.Int {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetL1BlobBaseFee")
+	}
+
+	var r0 *big.Int
+	if rf, ok := ret.Get(0).(func(context.Context) *big.Int); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	return r0
+}
+
+// ethermanMock_GetL1BlobBaseFee_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetL1BlobBaseFee'
+type ethermanMock_GetL1BlobBaseFee_Call struct {
+	*mock.Call
+}
+
+// GetL1BlobBaseFee is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *ethermanMock_Expecter) GetL1BlobBaseFee(ctx interface{}) *ethermanMock_GetL1BlobBaseFee_Call {
+	return &ethermanMock_GetL1BlobBaseFee_Call{Call: _e.mock.On("GetL1BlobBaseFee", ctx)}
+}
+
+func (_c *ethermanMock_GetL1BlobBaseFee_Call) Run(run func(ctx context.Context)) *ethermanMock_GetL1BlobBaseFee_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *ethermanMock_GetL1BlobBaseFee_Call) Return(_a0 *big.Int) *ethermanMock_GetL1BlobBaseFee_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ethermanMock_GetL1BlobBaseFee_Call) RunAndReturn(run func(context.Context) *big.Int) *ethermanMock_GetL1BlobBaseFee_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetL1GasPrice provides a mock function with given fields: ctx
 func (_m *ethermanMock) GetL1GasPrice(ctx context.Context) *big.Int {
 	ret := _m.Called(ctx)
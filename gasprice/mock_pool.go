@@ -25,6 +25,62 @@ func (_m *poolMock) EXPECT() *poolMock_Expecter {
 	return &poolMock_Expecter{mock: &_m.Mock}
 }
 
+// CountPendingTransactions provides a mock function with given fields: ctx
+func (_m *poolMock) CountPendingTransactions(ctx context.Context) (uint64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountPendingTransactions")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (uint64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) uint64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// poolMock_CountPendingTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountPendingTransactions'
+type poolMock_CountPendingTransactions_Call struct {
+	*mock.Call
+}
+
+// CountPendingTransactions is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *poolMock_Expecter) CountPendingTransactions(ctx interface{}) *poolMock_CountPendingTransactions_Call {
+	return &poolMock_CountPendingTransactions_Call{Call: _e.mock.On("CountPendingTransactions", ctx)}
+}
+
+func (_c *poolMock_CountPendingTransactions_Call) Run(run func(ctx context.Context)) *poolMock_CountPendingTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *poolMock_CountPendingTransactions_Call) Return(_a0 uint64, _a1 error) *poolMock_CountPendingTransactions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *poolMock_CountPendingTransactions_Call) RunAndReturn(run func(context.Context) (uint64, error)) *poolMock_CountPendingTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteGasPricesHistoryOlderThan provides a mock function with given fields: ctx, date
 func (_m *poolMock) DeleteGasPricesHistoryOlderThan(ctx context.Context, date time.Time) error {
 	ret := _m.Called(ctx, date)
@@ -128,17 +184,17 @@ func (_c *poolMock_GetGasPrices_Call) RunAndReturn(run func(context.Context) (po
 	return _c
 }
 
-// SetGasPrices provides a mock function with given fields: ctx, l2GasPrice, l1GasPrice
-func (_m *poolMock) SetGasPrices(ctx context.Context, l2GasPrice uint64, l1GasPrice uint64) error {
-	ret := _m.Called(ctx, l2GasPrice, l1GasPrice)
+// SetGasPricesWithInputs provides a mock function with given fields: ctx, gasPrices, inputs
+func (_m *poolMock) SetGasPricesWithInputs(ctx context.Context, gasPrices pool.GasPrices, inputs pool.GasPriceInputs) error {
+	ret := _m.Called(ctx, gasPrices, inputs)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SetGasPrices")
+		panic("no return value specified for SetGasPricesWithInputs")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
-		r0 = rf(ctx, l2GasPrice, l1GasPrice)
+	if rf, ok := ret.Get(0).(func(context.Context, pool.GasPrices, pool.GasPriceInputs) error); ok {
+		r0 = rf(ctx, gasPrices, inputs)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -146,32 +202,32 @@ func (_m *poolMock) SetGasPrices(ctx context.Context, l2GasPrice uint64, l1GasPr
 	return r0
 }
 
-// poolMock_SetGasPrices_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetGasPrices'
-type poolMock_SetGasPrices_Call struct {
+// poolMock_SetGasPricesWithInputs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetGasPricesWithInputs'
+type poolMock_SetGasPricesWithInputs_Call struct {
 	*mock.Call
 }
 
-// SetGasPrices is a helper method to define mock.On call
+// SetGasPricesWithInputs is a helper method to define mock.On call
 //   - ctx context.Context
-//   - l2GasPrice uint64
-//   - l1GasPrice uint64
-func (_e *poolMock_Expecter) SetGasPrices(ctx interface{}, l2GasPrice interface{}, l1GasPrice interface{}) *poolMock_SetGasPrices_Call {
-	return &poolMock_SetGasPrices_Call{Call: _e.mock.On("SetGasPrices", ctx, l2GasPrice, l1GasPrice)}
+//   - gasPrices pool.GasPrices
+//   - inputs pool.GasPriceInputs
+func (_e *poolMock_Expecter) SetGasPricesWithInputs(ctx interface{}, gasPrices interface{}, inputs interface{}) *poolMock_SetGasPricesWithInputs_Call {
+	return &poolMock_SetGasPricesWithInputs_Call{Call: _e.mock.On("SetGasPricesWithInputs", ctx, gasPrices, inputs)}
 }
 
-func (_c *poolMock_SetGasPrices_Call) Run(run func(ctx context.Context, l2GasPrice uint64, l1GasPrice uint64)) *poolMock_SetGasPrices_Call {
+func (_c *poolMock_SetGasPricesWithInputs_Call) Run(run func(ctx context.Context, gasPrices pool.GasPrices, inputs pool.GasPriceInputs)) *poolMock_SetGasPricesWithInputs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uint64), args[2].(uint64))
+		run(args[0].(context.Context), args[1].(pool.GasPrices), args[2].(pool.GasPriceInputs))
 	})
 	return _c
 }
 
-func (_c *poolMock_SetGasPrices_Call) Return(_a0 error) *poolMock_SetGasPrices_Call {
+func (_c *poolMock_SetGasPricesWithInputs_Call) Return(_a0 error) *poolMock_SetGasPricesWithInputs_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *poolMock_SetGasPrices_Call) RunAndReturn(run func(context.Context, uint64, uint64) error) *poolMock_SetGasPrices_Call {
+func (_c *poolMock_SetGasPricesWithInputs_Call) RunAndReturn(run func(context.Context, pool.GasPrices, pool.GasPriceInputs) error) *poolMock_SetGasPricesWithInputs_Call {
 	_c.Call.Return(run)
 	return _c
 }
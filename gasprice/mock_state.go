@@ -0,0 +1,219 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package gasprice
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	pgx "github.com/jackc/pgx/v4"
+
+	state "github.com/0xPolygonHermez/zkevm-node/state"
+
+	types "github.com/ethereum/go-ethereum/core/types"
+)
+
+// stateMock is an autogenerated mock type for the stateInterface type
+type stateMock struct {
+	mock.Mock
+}
+
+type stateMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *stateMock) EXPECT() *stateMock_Expecter {
+	return &stateMock_Expecter{mock: &_m.Mock}
+}
+
+// GetLastL2BlockNumber provides a mock function with given fields: ctx, dbTx
+func (_m *stateMock) GetLastL2BlockNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastL2BlockNumber")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (uint64, error)); ok {
+		return rf(ctx, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) uint64); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// stateMock_GetLastL2BlockNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLastL2BlockNumber'
+type stateMock_GetLastL2BlockNumber_Call struct {
+	*mock.Call
+}
+
+// GetLastL2BlockNumber is a helper method to define mock.On call
+//   - ctx context.Context
+//   - dbTx pgx.Tx
+func (_e *stateMock_Expecter) GetLastL2BlockNumber(ctx interface{}, dbTx interface{}) *stateMock_GetLastL2BlockNumber_Call {
+	return &stateMock_GetLastL2BlockNumber_Call{Call: _e.mock.On("GetLastL2BlockNumber", ctx, dbTx)}
+}
+
+func (_c *stateMock_GetLastL2BlockNumber_Call) Run(run func(ctx context.Context, dbTx pgx.Tx)) *stateMock_GetLastL2BlockNumber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *stateMock_GetLastL2BlockNumber_Call) Return(_a0 uint64, _a1 error) *stateMock_GetLastL2BlockNumber_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *stateMock_GetLastL2BlockNumber_Call) RunAndReturn(run func(context.Context, pgx.Tx) (uint64, error)) *stateMock_GetLastL2BlockNumber_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLastNBatches provides a mock function with given fields: ctx, numBatches, dbTx
+func (_m *stateMock) GetLastNBatches(ctx context.Context, numBatches uint, dbTx pgx.Tx) ([]*state.Batch, error) {
+	ret := _m.Called(ctx, numBatches, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastNBatches")
+	}
+
+	var r0 []*state.Batch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, pgx.Tx) ([]*state.Batch, error)); ok {
+		return rf(ctx, numBatches, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, pgx.Tx) []*state.Batch); ok {
+		r0 = rf(ctx, numBatches, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*state.Batch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, pgx.Tx) error); ok {
+		r1 = rf(ctx, numBatches, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// stateMock_GetLastNBatches_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLastNBatches'
+type stateMock_GetLastNBatches_Call struct {
+	*mock.Call
+}
+
+// GetLastNBatches is a helper method to define mock.On call
+//   - ctx context.Context
+//   - numBatches uint
+//   - dbTx pgx.Tx
+func (_e *stateMock_Expecter) GetLastNBatches(ctx interface{}, numBatches interface{}, dbTx interface{}) *stateMock_GetLastNBatches_Call {
+	return &stateMock_GetLastNBatches_Call{Call: _e.mock.On("GetLastNBatches", ctx, numBatches, dbTx)}
+}
+
+func (_c *stateMock_GetLastNBatches_Call) Run(run func(ctx context.Context, numBatches uint, dbTx pgx.Tx)) *stateMock_GetLastNBatches_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *stateMock_GetLastNBatches_Call) Return(_a0 []*state.Batch, _a1 error) *stateMock_GetLastNBatches_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *stateMock_GetLastNBatches_Call) RunAndReturn(run func(context.Context, uint, pgx.Tx) ([]*state.Batch, error)) *stateMock_GetLastNBatches_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTxsByBlockNumber provides a mock function with given fields: ctx, blockNumber, dbTx
+func (_m *stateMock) GetTxsByBlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]*types.Transaction, error) {
+	ret := _m.Called(ctx, blockNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTxsByBlockNumber")
+	}
+
+	var r0 []*types.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) ([]*types.Transaction, error)); ok {
+		return rf(ctx, blockNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) []*types.Transaction); ok {
+		r0 = rf(ctx, blockNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, blockNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// stateMock_GetTxsByBlockNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxsByBlockNumber'
+type stateMock_GetTxsByBlockNumber_Call struct {
+	*mock.Call
+}
+
+// GetTxsByBlockNumber is a helper method to define mock.On call
+//   - ctx context.Context
+//   - blockNumber uint64
+//   - dbTx pgx.Tx
+func (_e *stateMock_Expecter) GetTxsByBlockNumber(ctx interface{}, blockNumber interface{}, dbTx interface{}) *stateMock_GetTxsByBlockNumber_Call {
+	return &stateMock_GetTxsByBlockNumber_Call{Call: _e.mock.On("GetTxsByBlockNumber", ctx, blockNumber, dbTx)}
+}
+
+func (_c *stateMock_GetTxsByBlockNumber_Call) Run(run func(ctx context.Context, blockNumber uint64, dbTx pgx.Tx)) *stateMock_GetTxsByBlockNumber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *stateMock_GetTxsByBlockNumber_Call) Return(_a0 []*types.Transaction, _a1 error) *stateMock_GetTxsByBlockNumber_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *stateMock_GetTxsByBlockNumber_Call) RunAndReturn(run func(context.Context, uint64, pgx.Tx) ([]*types.Transaction, error)) *stateMock_GetTxsByBlockNumber_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newStateMock creates a new instance of stateMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newStateMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *stateMock {
+	mock := &stateMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
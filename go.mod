@@ -164,7 +164,7 @@ require (
 require (
 	github.com/gorilla/websocket v1.5.3
 	github.com/holiman/uint256 v1.3.0
-	github.com/syndtr/goleveldb v1.0.1-0.20220614013038-64ee5596c38a // indirect
+	github.com/syndtr/goleveldb v1.0.1-0.20220614013038-64ee5596c38a
 )
 
 require (
@@ -59,12 +59,23 @@ type Config struct {
 	// native block hashes in a single call to the state, if zero it means no limit
 	MaxNativeBlockHashBlockRange uint64 `mapstructure:"MaxNativeBlockHashBlockRange"`
 
+	// MaxTraceFilterBlockRange is a configuration to set the max range for block number when filtering
+	// traces in a single call to the state, if zero it means no limit
+	MaxTraceFilterBlockRange uint64 `mapstructure:"MaxTraceFilterBlockRange"`
+
+	// MaxEGPSimulationBlockRange is a configuration to set the max number of blocks used to simulate
+	// the effective gas price in a single call to the admin API, if zero it means no limit
+	MaxEGPSimulationBlockRange uint64 `mapstructure:"MaxEGPSimulationBlockRange"`
+
 	// EnableHttpLog allows the user to enable or disable the logs related to the HTTP
 	// requests to be captured by the server.
 	EnableHttpLog bool `mapstructure:"EnableHttpLog"`
 
 	// ZKCountersLimits defines the ZK Counter limits
 	ZKCountersLimits ZKCountersLimits
+
+	// Admin configuration of the HTTP server of the admin API, which is not served by the HTTP and WebSockets servers above
+	Admin AdminConfig `mapstructure:"Admin"`
 }
 
 // ZKCountersLimits defines the ZK Counter limits
@@ -93,3 +104,13 @@ type WebSocketsConfig struct {
 	// ReadLimit defines the maximum size of a message read from the client (in bytes)
 	ReadLimit int64 `mapstructure:"ReadLimit"`
 }
+
+// AdminConfig has parameters to config the listener of the admin API
+type AdminConfig struct {
+	// Host defines the network adapter that will be used to serve the admin API requests. The admin API allows
+	// to halt the sequencer and has no authentication, so it should only be reachable from trusted hosts
+	Host string `mapstructure:"Host"`
+
+	// Port defines the port to serve the admin API via HTTP
+	Port int `mapstructure:"Port"`
+}
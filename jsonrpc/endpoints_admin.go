@@ -0,0 +1,156 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+const (
+	// defaultEGPSimulationBlocks is the number of L2 blocks used to simulate the effective gas price if not provided
+	defaultEGPSimulationBlocks = 100
+)
+
+// AdminEndpoints contains implementations for the "admin" RPC endpoints
+type AdminEndpoints struct {
+	cfg    Config
+	state  types.StateInterface
+	egpCfg pool.EffectiveGasPriceCfg
+}
+
+// NewAdminEndpoints returns AdminEndpoints
+func NewAdminEndpoints(cfg Config, state types.StateInterface, egpCfg pool.EffectiveGasPriceCfg) *AdminEndpoints {
+	return &AdminEndpoints{
+		cfg:    cfg,
+		state:  state,
+		egpCfg: egpCfg,
+	}
+}
+
+// SimulateEffectiveGasPrice recalculates the effective gas price of the txs of the last numBlocks
+// L2 blocks with the configuration of the node overridden by params, and returns the statistics
+// of the effective gas price as the txs were processed and as they would be with the simulation
+func (a *AdminEndpoints) SimulateEffectiveGasPrice(params types.EGPSimulationParams, numBlocks *types.ArgUint64) (interface{}, types.Error) {
+	ctx := context.Background()
+	blocks := uint64(defaultEGPSimulationBlocks)
+	if numBlocks != nil {
+		blocks = uint64(*numBlocks)
+	}
+	if blocks == 0 {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "the number of blocks must be greater than 0", nil, false)
+	}
+	if a.cfg.MaxEGPSimulationBlockRange > 0 && blocks > a.cfg.MaxEGPSimulationBlockRange {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("the number of blocks is limited to %d", a.cfg.MaxEGPSimulationBlockRange), nil, false)
+	}
+
+	toBlock, err := a.state.GetLastL2BlockNumber(ctx, nil)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get the last block number from state", err, true)
+	}
+	fromBlock := uint64(0)
+	if toBlock >= blocks {
+		fromBlock = toBlock - blocks + 1
+	}
+
+	txs, err := a.state.GetTransactionsEGPLogByL2BlockRange(ctx, fromBlock, toBlock, nil)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get the effective gas price logs from state", err, true)
+	}
+
+	egp := pool.NewEffectiveGasPrice(params.Apply(a.egpCfg))
+	realStats, simulatedStats := pool.EGPStats{}, pool.EGPStats{}
+	for _, tx := range txs {
+		realStats.Add(pool.NewEGPStats(tx.EGPLog))
+		if tx.EGPLog == nil {
+			simulatedStats.Add(pool.NewEGPStats(nil))
+			continue
+		}
+		simLog, err := egp.SimulateEffectiveGasPrice(*tx.Tx, *tx.EGPLog)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to simulate the effective gas price of tx %s", tx.Tx.Hash().String()), err, true)
+		}
+		simulatedStats.Add(pool.NewEGPStats(&simLog))
+	}
+
+	return types.EGPSimulationResult{
+		FromBlock: types.ArgUint64(fromBlock),
+		ToBlock:   types.ArgUint64(toBlock),
+		Real:      types.NewEGPStats(realStats),
+		Simulated: types.NewEGPStats(simulatedStats),
+	}, nil
+}
+
+// ScheduleSequencerHalt schedules a halt of the sequencer for maintenance, replacing the previous one if it's
+// still scheduled. The sequencer closes the current batch and halts before opening the batch number given or
+// when the timestamp given is reached, and the pool doesn't accept new txs until it's resumed
+func (a *AdminEndpoints) ScheduleSequencerHalt(params types.SequencerHaltParams) (interface{}, types.Error) {
+	ctx := context.Background()
+	if params.BatchNumber == nil && params.Timestamp == nil {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "the batch number or the timestamp of the halt must be provided", nil, false)
+	}
+	if params.BatchNumber != nil && *params.BatchNumber == 0 {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "the batch number of the halt must be greater than 0", nil, false)
+	}
+	if params.Reason == "" {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "the reason of the halt must be provided", nil, false)
+	}
+
+	halt, err := a.state.GetSequencerHalt(ctx, nil)
+	if err != nil && !errors.Is(err, state.ErrNotFound) {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get the sequencer halt from state", err, true)
+	}
+	if halt != nil && halt.Status == state.SequencerHaltHalted {
+		return RPCErrorResponse(types.DefaultErrorCode, "the sequencer is already halted, it must be resumed before scheduling a new halt", nil, false)
+	}
+
+	batchNumber := uint64(0)
+	if params.BatchNumber != nil {
+		batchNumber = uint64(*params.BatchNumber)
+	}
+	var haltAt *time.Time
+	if params.Timestamp != nil {
+		t := time.Unix(int64(*params.Timestamp), 0)
+		haltAt = &t
+	}
+
+	err = a.state.ScheduleSequencerHalt(ctx, batchNumber, haltAt, params.Reason, nil)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to schedule the sequencer halt", err, true)
+	}
+
+	return nil, nil
+}
+
+// ResumeSequencer resumes the sequencer if it's halted for maintenance, or cancels the halt if it's still scheduled
+func (a *AdminEndpoints) ResumeSequencer(reason string) (interface{}, types.Error) {
+	if reason == "" {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "the reason of the resume must be provided", nil, false)
+	}
+
+	resumed, err := a.state.ResumeSequencer(context.Background(), reason, nil)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to resume the sequencer", err, true)
+	}
+	if !resumed {
+		return RPCErrorResponse(types.DefaultErrorCode, "there is no sequencer halt scheduled or halted to resume", nil, false)
+	}
+
+	return nil, nil
+}
+
+// GetSequencerHalt returns the last halt of the sequencer scheduled for maintenance, or null if no halt has ever been scheduled
+func (a *AdminEndpoints) GetSequencerHalt() (interface{}, types.Error) {
+	halt, err := a.state.GetSequencerHalt(context.Background(), nil)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get the sequencer halt from state", err, true)
+	}
+
+	return types.NewSequencerHalt(*halt), nil
+}
@@ -0,0 +1,169 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var egpCfg = pool.EffectiveGasPriceCfg{
+	L1GasPriceFactor:          0.25,
+	ByteGasCost:               16,
+	ZeroByteGasCost:           4,
+	NetProfit:                 1,
+	BreakEvenFactor:           1.1,
+	FinalDeviationPct:         10,
+	L2GasPriceSuggesterFactor: 0.5,
+	L1CostModel:               pool.L1CostModelCfg{Type: pool.CalldataL1CostModel},
+}
+
+func TestSimulateEffectiveGasPrice(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	to := common.HexToAddress("0x1")
+	tx := ethTypes.NewTx(&ethTypes.LegacyTx{Nonce: 1, To: &to, Value: big.NewInt(1), Gas: 50000, GasPrice: big.NewInt(10000000000)})
+	egpLog := &state.EffectiveGasPriceLog{
+		ValueFinal:     big.NewInt(10000000000),
+		ValueFirst:     big.NewInt(1000000000),
+		ValueSecond:    big.NewInt(1000000000),
+		FinalDeviation: big.NewInt(0),
+		MaxDeviation:   big.NewInt(100000000),
+		GasPrice:       big.NewInt(10000000000),
+		GasUsedFirst:   30000,
+		GasUsedSecond:  30000,
+		L1GasPrice:     1000000000,
+		L2GasPrice:     10000000000,
+		Percentage:     255,
+	}
+	txs := []state.TransactionEGPLog{
+		{L2BlockNumber: 51, Tx: tx, EGPLog: egpLog},
+		{L2BlockNumber: 150, Tx: tx},
+	}
+
+	m.State.On("GetLastL2BlockNumber", context.Background(), nil).Return(uint64(150), nil).Once()
+	m.State.On("GetTransactionsEGPLogByL2BlockRange", context.Background(), uint64(51), uint64(150), nil).Return(txs, nil).Once()
+
+	params := map[string]interface{}{
+		"enabled":   true,
+		"netProfit": 2,
+	}
+	res, err := s.AdminJSONRPCCall("admin_simulateEffectiveGasPrice", params)
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var result types.EGPSimulationResult
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, types.ArgUint64(51), result.FromBlock)
+	assert.Equal(t, types.ArgUint64(150), result.ToBlock)
+
+	// the real txs were processed with the effective gas price disabled, charging the user gas price
+	assert.Equal(t, types.ArgUint64(2), result.Real.Txs)
+	assert.Equal(t, types.ArgUint64(1), result.Real.NoInfo)
+	assert.Equal(t, types.ArgUint64(0), result.Real.Enabled)
+	assert.Equal(t, types.ArgUint64(1), result.Real.UsedUser)
+	assert.InDelta(t, 9, result.Real.ProfitMargin, 0.0001)
+
+	enabled, netProfit := true, float64(2)
+	simCfg := types.EGPSimulationParams{Enabled: &enabled, NetProfit: &netProfit}.Apply(egpCfg)
+	assert.True(t, simCfg.Enabled)
+	assert.Equal(t, float64(2), simCfg.NetProfit)
+	assert.Equal(t, egpCfg.FinalDeviationPct, simCfg.FinalDeviationPct)
+	simEGP := pool.NewEffectiveGasPrice(simCfg)
+	simLog, err := simEGP.SimulateEffectiveGasPrice(*tx, *egpLog)
+	require.NoError(t, err)
+	expected := pool.NewEGPStats(&simLog)
+	expected.Add(pool.NewEGPStats(nil))
+	assert.Equal(t, types.NewEGPStats(expected).Fee.Hex(), result.Simulated.Fee.Hex())
+	assert.Equal(t, types.NewEGPStats(expected).EffectiveFee.Hex(), result.Simulated.EffectiveFee.Hex())
+	assert.Equal(t, "0x0", result.Simulated.Loss.Hex())
+	assert.Equal(t, types.ArgUint64(1), result.Simulated.Enabled)
+	assert.Equal(t, types.ArgUint64(1), result.Simulated.UsedFirst)
+	assert.Equal(t, types.ArgUint64(0), result.Simulated.Reprocessed)
+
+	// the number of blocks is limited
+	res, err = s.AdminJSONRPCCall("admin_simulateEffectiveGasPrice", params, hex.EncodeUint64(1001))
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.InvalidParamsErrorCode, res.Error.Code)
+	assert.Equal(t, "the number of blocks is limited to 1000", res.Error.Message)
+
+	// the range starts at the genesis if there are less blocks
+	m.State.On("GetLastL2BlockNumber", context.Background(), nil).Return(uint64(5), nil).Once()
+	m.State.On("GetTransactionsEGPLogByL2BlockRange", context.Background(), uint64(0), uint64(5), nil).Return(nil, errors.New("db error")).Once()
+	res, err = s.AdminJSONRPCCall("admin_simulateEffectiveGasPrice", params, hex.EncodeUint64(10))
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, "failed to get the effective gas price logs from state", res.Error.Message)
+}
+
+func TestScheduleSequencerHalt(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	// the batch number or the timestamp must be provided
+	res, err := s.AdminJSONRPCCall("admin_scheduleSequencerHalt", map[string]interface{}{"reason": "fork upgrade"})
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.InvalidParamsErrorCode, res.Error.Code)
+	assert.Equal(t, "the batch number or the timestamp of the halt must be provided", res.Error.Message)
+
+	// a new halt can't be scheduled while the sequencer is halted
+	m.State.On("GetSequencerHalt", context.Background(), nil).Return(&state.SequencerHalt{BatchNumber: 10, Status: state.SequencerHaltHalted}, nil).Once()
+	res, err = s.AdminJSONRPCCall("admin_scheduleSequencerHalt", map[string]interface{}{"batchNumber": hex.EncodeUint64(20), "reason": "fork upgrade"})
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, "the sequencer is already halted, it must be resumed before scheduling a new halt", res.Error.Message)
+
+	haltAt := time.Unix(1700000000, 0)
+	m.State.On("GetSequencerHalt", context.Background(), nil).Return(nil, state.ErrNotFound).Once()
+	m.State.On("ScheduleSequencerHalt", context.Background(), uint64(20), &haltAt, "fork upgrade", nil).Return(nil).Once()
+	res, err = s.AdminJSONRPCCall("admin_scheduleSequencerHalt", map[string]interface{}{"batchNumber": hex.EncodeUint64(20), "timestamp": hex.EncodeUint64(1700000000), "reason": "fork upgrade"})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	m.State.On("GetSequencerHalt", context.Background(), nil).Return(&state.SequencerHalt{BatchNumber: 20, HaltAt: &haltAt, Status: state.SequencerHaltScheduled, Reason: "fork upgrade", UpdatedAt: haltAt}, nil).Once()
+	res, err = s.AdminJSONRPCCall("admin_getSequencerHalt")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var halt types.SequencerHalt
+	require.NoError(t, json.Unmarshal(res.Result, &halt))
+	assert.Equal(t, types.ArgUint64(20), *halt.BatchNumber)
+	assert.Equal(t, types.ArgUint64(1700000000), *halt.Timestamp)
+	assert.Equal(t, string(state.SequencerHaltScheduled), halt.Status)
+	assert.Equal(t, "fork upgrade", halt.Reason)
+}
+
+func TestResumeSequencer(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	// the admin API is not served by the public server
+	res, err := s.JSONRPCCall("admin_resumeSequencer", "upgrade done")
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.NotFoundErrorCode, res.Error.Code)
+
+	m.State.On("ResumeSequencer", context.Background(), "upgrade done", nil).Return(true, nil).Once()
+	res, err = s.AdminJSONRPCCall("admin_resumeSequencer", "upgrade done")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	m.State.On("ResumeSequencer", context.Background(), "upgrade done", nil).Return(false, nil).Once()
+	res, err = s.AdminJSONRPCCall("admin_resumeSequencer", "upgrade done")
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, "there is no sequencer halt scheduled or halted to resume", res.Error.Message)
+}
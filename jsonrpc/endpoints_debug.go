@@ -51,6 +51,17 @@ type traceConfig struct {
 	TracerConfig     json.RawMessage `json:"tracerConfig"`
 }
 
+func (c *traceConfig) toStateTraceConfig() state.TraceConfig {
+	return state.TraceConfig{
+		DisableStack:     c.DisableStack,
+		DisableStorage:   c.DisableStorage,
+		EnableMemory:     c.EnableMemory,
+		EnableReturnData: c.EnableReturnData,
+		Tracer:           c.Tracer,
+		TracerConfig:     c.TracerConfig,
+	}
+}
+
 type traceBlockTransactionResponse struct {
 	Result interface{} `json:"result"`
 }
@@ -67,6 +78,61 @@ func (d *DebugEndpoints) TraceTransaction(hash types.ArgHash, cfg *traceConfig)
 	return d.buildTraceTransaction(ctx, hash.Hash(), cfg, nil)
 }
 
+// TraceCall creates a response for debug_traceCall request, tracing the execution of
+// a call on top of the state of the given block.
+// See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debugtracecall
+func (d *DebugEndpoints) TraceCall(arg *types.TxArgs, blockArg *types.BlockNumberOrHash, cfg *traceConfig) (interface{}, types.Error) {
+	ctx := context.Background()
+	if arg == nil {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 0", nil, false)
+	}
+	// the block is resolved like in the eth endpoints
+	eth := &EthEndpoints{state: d.state, etherman: d.etherman}
+	block, respErr := eth.getBlockByArg(ctx, blockArg, nil)
+	if respErr != nil {
+		return nil, respErr
+	}
+	var blockToProcess *uint64
+	if blockArg != nil {
+		blockNumArg := blockArg.Number()
+		if blockNumArg != nil && (*blockArg.Number() == types.LatestBlockNumber || *blockArg.Number() == types.PendingBlockNumber) {
+			blockToProcess = nil
+		} else {
+			n := block.NumberU64()
+			blockToProcess = &n
+		}
+	}
+
+	// If the caller didn't supply the gas limit in the message, then we set it to maximum possible => block gas limit
+	if arg.Gas == nil || uint64(*arg.Gas) <= 0 {
+		header, err := d.state.GetL2BlockHeaderByNumber(ctx, block.NumberU64(), nil)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get block header", err, true)
+		}
+
+		gas := types.ArgUint64(header.GasLimit)
+		arg.Gas = &gas
+	}
+
+	defaultSenderAddress := common.HexToAddress(state.DefaultSenderAddress)
+	sender, tx, err := arg.ToTransaction(ctx, d.state, state.MaxTxGasLimit, block.Root(), defaultSenderAddress, nil)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction", err, false)
+	}
+
+	traceCfg := cfg
+	if traceCfg == nil {
+		traceCfg = defaultTraceConfig
+	}
+	result, err := d.state.DebugUnsignedTransaction(ctx, tx, sender, blockToProcess, traceCfg.toStateTraceConfig(), nil)
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to get trace: %v", err.Error())
+		return nil, types.NewRPCError(types.DefaultErrorCode, errorMessage)
+	}
+
+	return result.TraceResult, nil
+}
+
 // TraceBlockByNumber creates a response for debug_traceBlockByNumber request.
 // See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debugtraceblockbynumber
 func (d *DebugEndpoints) TraceBlockByNumber(number types.BlockNumber, cfg *traceConfig) (interface{}, types.Error) {
@@ -271,15 +337,7 @@ func (d *DebugEndpoints) buildTraceTransaction(ctx context.Context, hash common.
 		traceCfg = defaultTraceConfig
 	}
 
-	stateTraceConfig := state.TraceConfig{
-		DisableStack:     traceCfg.DisableStack,
-		DisableStorage:   traceCfg.DisableStorage,
-		EnableMemory:     traceCfg.EnableMemory,
-		EnableReturnData: traceCfg.EnableReturnData,
-		Tracer:           traceCfg.Tracer,
-		TracerConfig:     traceCfg.TracerConfig,
-	}
-	result, err := d.state.DebugTransaction(ctx, hash, stateTraceConfig, dbTx)
+	result, err := d.state.DebugTransaction(ctx, hash, traceCfg.toStateTraceConfig(), dbTx)
 	if errors.Is(err, state.ErrNotFound) {
 		return RPCErrorResponse(types.DefaultErrorCode, "transaction not found", nil, false)
 	} else if err != nil {
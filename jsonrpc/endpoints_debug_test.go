@@ -0,0 +1,55 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceCall(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	to := common.HexToAddress("0x1")
+	gas := types.ArgUint64(50000)
+	txArgs := types.TxArgs{To: &to, Gas: &gas}
+	blockNumber := uint64(1)
+	block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1)}))
+	tracer := "zkCounterTracer"
+	trace := json.RawMessage(`{"estimated":true,"counters":{"steps":100}}`)
+
+	m.State.
+		On("GetL2BlockByNumber", context.Background(), blockNumber, nil).
+		Return(block, nil).
+		Twice()
+	m.State.
+		On("DebugUnsignedTransaction", context.Background(), mock.IsType(&ethTypes.Transaction{}), common.HexToAddress(state.DefaultSenderAddress), &blockNumber, state.TraceConfig{Tracer: &tracer}, nil).
+		Return(&runtime.ExecutionResult{TraceResult: trace}, nil).
+		Once()
+
+	res, err := s.JSONRPCCall("debug_traceCall", txArgs, "0x1", map[string]interface{}{"tracer": tracer})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.JSONEq(t, string(trace), string(res.Result))
+
+	m.State.
+		On("DebugUnsignedTransaction", context.Background(), mock.IsType(&ethTypes.Transaction{}), common.HexToAddress(state.DefaultSenderAddress), &blockNumber, state.TraceConfig{Tracer: &tracer}, nil).
+		Return(nil, errors.New("executor error")).
+		Once()
+
+	res, err = s.JSONRPCCall("debug_traceCall", txArgs, "0x1", map[string]interface{}{"tracer": tracer})
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, "failed to get trace: executor error", res.Error.Message)
+}
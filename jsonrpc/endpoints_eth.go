@@ -280,6 +280,8 @@ func (e *EthEndpoints) getBlockByArg(ctx context.Context, blockArg *types.BlockN
 		block, err := e.state.GetL2BlockByHash(ctx, blockArg.Hash().Hash(), dbTx)
 		if errors.Is(err, state.ErrNotFound) {
 			return nil, types.NewRPCError(types.DefaultErrorCode, "header for hash not found")
+		} else if errors.Is(err, state.ErrHistoryPruned) {
+			return nil, types.NewRPCError(types.HistoryPrunedErrorCode, err.Error())
 		} else if err != nil {
 			return nil, types.NewRPCError(types.DefaultErrorCode, fmt.Sprintf("failed to get block by hash %v", blockArg.Hash().Hash()))
 		}
@@ -292,7 +294,9 @@ func (e *EthEndpoints) getBlockByArg(ctx context.Context, blockArg *types.BlockN
 		return nil, rpcErr
 	}
 	block, err := e.state.GetL2BlockByNumber(context.Background(), blockNum, dbTx)
-	if errors.Is(err, state.ErrNotFound) || block == nil {
+	if errors.Is(err, state.ErrHistoryPruned) {
+		return nil, types.NewRPCError(types.HistoryPrunedErrorCode, err.Error())
+	} else if errors.Is(err, state.ErrNotFound) || block == nil {
 		return nil, types.NewRPCError(types.DefaultErrorCode, "header not found")
 	} else if err != nil {
 		return nil, types.NewRPCError(types.DefaultErrorCode, fmt.Sprintf("failed to get block by number %v", blockNum))
@@ -440,6 +444,14 @@ func (e *EthEndpoints) GetFilterChanges(filterID string) (interface{}, types.Err
 		}
 	case FilterTypePendingTx:
 		{
+			// in private mempool mode the hashes of the pending txs are only exposed if allowed
+			if !e.pool.PendingTxHashesExposed() {
+				rpcErr := e.updateFilterLastPoll(filter.ID)
+				if rpcErr != nil {
+					return nil, rpcErr
+				}
+				return nil, nil
+			}
 			res, err := e.pool.GetPendingTxHashesSince(context.Background(), filter.LastPoll)
 			if err != nil {
 				return RPCErrorResponse(types.DefaultErrorCode, "failed to get pending transaction hashes", err, true)
@@ -675,6 +687,10 @@ func (e *EthEndpoints) GetTransactionByHash(hash types.ArgHash, includeExtraInfo
 	if e.cfg.SequencerNodeURI != "" {
 		return e.getTransactionByHashFromSequencerNode(hash.Hash(), includeExtraInfo)
 	}
+	// in private mempool mode the pending txs are hidden until they are stored in an L2 block
+	if e.pool.PrivateMempoolEnabled() {
+		return nil, nil
+	}
 	poolTx, err := e.pool.GetTransactionByHash(ctx, hash.Hash())
 	if errors.Is(err, pool.ErrNotFound) {
 		return nil, nil
@@ -730,7 +746,8 @@ func (e *EthEndpoints) GetTransactionCount(address types.ArgAddress, blockArg *t
 
 	if blockArg != nil {
 		blockNumArg := blockArg.Number()
-		if blockNumArg != nil && *blockNumArg == types.PendingBlockNumber {
+		// in private mempool mode the pending txs are hidden, so the pending nonce is the nonce of the latest block
+		if blockNumArg != nil && *blockNumArg == types.PendingBlockNumber && !e.pool.PrivateMempoolEnabled() {
 			if e.cfg.SequencerNodeURI != "" {
 				return e.getTransactionCountFromSequencerNode(address.Address(), blockArg.Number())
 			}
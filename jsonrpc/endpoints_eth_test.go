@@ -1335,6 +1335,31 @@ func TestGetL2BlockByNumber(t *testing.T) {
 	}
 }
 
+func TestGetL2BlockByNumberPruned(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	txHash := common.HexToHash("0x1")
+	l2Block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1)}))
+	l2Block.PrunedTxHashes = []common.Hash{txHash}
+	m.State.On("GetL2BlockByNumber", context.Background(), uint64(1), nil).Return(l2Block, nil).Twice()
+
+	// the hashes of the pruned transactions are returned
+	res, err := s.JSONRPCCall("eth_getBlockByNumber", "0x1", false)
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var block types.Block
+	require.NoError(t, json.Unmarshal(res.Result, &block))
+	require.Len(t, block.Transactions, 1)
+	assert.Equal(t, txHash, *block.Transactions[0].Hash)
+
+	// the bodies of the pruned transactions can't be returned
+	res, err = s.JSONRPCCall("eth_getBlockByNumber", "0x1", true)
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.HistoryPrunedErrorCode, res.Error.Code)
+}
+
 func TestGetUncleByBlockHashAndIndex(t *testing.T) {
 	s, _, _ := newSequencerMockedServer(t)
 	defer s.Stop()
@@ -1436,6 +1461,19 @@ func TestGetCode(t *testing.T) {
 					Once()
 			},
 		},
+		{
+			Name: "block history pruned",
+			Params: []interface{}{
+				addressArg.String(),
+				map[string]interface{}{types.BlockNumberKey: hex.EncodeBig(blockNumOne)},
+			},
+			ExpectedResult: nil,
+			ExpectedError:  types.NewRPCError(types.HistoryPrunedErrorCode, state.ErrHistoryPruned.Error()),
+
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOne.Uint64(), nil).Return(nil, state.ErrHistoryPruned).Once()
+			},
+		},
 		{
 			Name: "failed to get code",
 			Params: []interface{}{
@@ -2190,6 +2228,11 @@ func TestGetTransactionByHash(t *testing.T) {
 					Return(nil, state.ErrNotFound).
 					Once()
 
+				m.Pool.
+					On("PrivateMempoolEnabled").
+					Return(false).
+					Once()
+
 				m.Pool.
 					On("GetTransactionByHash", context.Background(), tc.Hash).
 					Return(&pool.Transaction{Transaction: *tc.ExpectedResult, Status: pool.TxStatusPending}, nil).
@@ -2208,12 +2251,35 @@ func TestGetTransactionByHash(t *testing.T) {
 					Return(nil, state.ErrNotFound).
 					Once()
 
+				m.Pool.
+					On("PrivateMempoolEnabled").
+					Return(false).
+					Once()
+
 				m.Pool.
 					On("GetTransactionByHash", context.Background(), tc.Hash).
 					Return(nil, pool.ErrNotFound).
 					Once()
 			},
 		},
+		{
+			Name:            "TX pending in the pool hidden in private mempool mode",
+			Hash:            common.HexToHash("0x123"),
+			ExpectedPending: false,
+			ExpectedResult:  nil,
+			ExpectedError:   ethereum.NotFound,
+			SetupMocks: func(m *mocksWrapper, tc testCase) {
+				m.State.
+					On("GetTransactionByHash", context.Background(), tc.Hash, nil).
+					Return(nil, state.ErrNotFound).
+					Once()
+
+				m.Pool.
+					On("PrivateMempoolEnabled").
+					Return(true).
+					Once()
+			},
+		},
 		{
 			Name:            "TX failed to load from the state",
 			Hash:            common.HexToHash("0x123"),
@@ -2239,6 +2305,11 @@ func TestGetTransactionByHash(t *testing.T) {
 					Return(nil, state.ErrNotFound).
 					Once()
 
+				m.Pool.
+					On("PrivateMempoolEnabled").
+					Return(false).
+					Once()
+
 				m.Pool.
 					On("GetTransactionByHash", context.Background(), tc.Hash).
 					Return(nil, errors.New("failed to load transaction by hash from pool")).
@@ -2573,6 +2644,67 @@ func TestGetTransactionCount(t *testing.T) {
 					Once()
 			},
 		},
+		{
+			Name: "Count pending txs successfully",
+			Params: []interface{}{
+				addressArg.String(),
+				"pending",
+			},
+			ExpectedResult: uint(12),
+			ExpectedError:  nil,
+			SetupMocks: func(m *mocksWrapper, tc testCase) {
+				m.State.
+					On("GetLastL2BlockNumber", context.Background(), nil).
+					Return(blockNumTen.Uint64(), nil).
+					Once()
+
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumTen, Root: blockRoot}))
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumTenUint64, nil).Return(block, nil).Once()
+
+				m.Pool.
+					On("PrivateMempoolEnabled").
+					Return(false).
+					Once()
+
+				m.Pool.
+					On("GetNonce", context.Background(), addressArg).
+					Return(uint64(12), nil).
+					Once()
+
+				m.State.
+					On("GetNonce", context.Background(), addressArg, blockRoot).
+					Return(uint64(10), nil).
+					Once()
+			},
+		},
+		{
+			Name: "Count pending txs in private mempool mode returns the latest nonce",
+			Params: []interface{}{
+				addressArg.String(),
+				"pending",
+			},
+			ExpectedResult: uint(10),
+			ExpectedError:  nil,
+			SetupMocks: func(m *mocksWrapper, tc testCase) {
+				m.State.
+					On("GetLastL2BlockNumber", context.Background(), nil).
+					Return(blockNumTen.Uint64(), nil).
+					Once()
+
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumTen, Root: blockRoot}))
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumTenUint64, nil).Return(block, nil).Once()
+
+				m.Pool.
+					On("PrivateMempoolEnabled").
+					Return(true).
+					Once()
+
+				m.State.
+					On("GetNonce", context.Background(), addressArg, blockRoot).
+					Return(uint64(10), nil).
+					Once()
+			},
+		},
 		{
 			Name: "failed to get nonce",
 			Params: []interface{}{
@@ -2750,6 +2882,18 @@ func TestGetTransactionReceipt(t *testing.T) {
 					Once()
 			},
 		},
+		{
+			Name:           "TX receipt history pruned",
+			Hash:           common.HexToHash("0x123"),
+			ExpectedResult: nil,
+			ExpectedError:  types.NewRPCError(types.HistoryPrunedErrorCode, "history pruned"),
+			SetupMocks: func(m *mocksWrapper, tc testCase) {
+				m.State.
+					On("GetTransactionByHash", context.Background(), tc.Hash, nil).
+					Return(nil, state.ErrHistoryPruned).
+					Once()
+			},
+		},
 		{
 			Name:           "TX receipt failed to load",
 			Hash:           common.HexToHash("0x123"),
@@ -3865,6 +4009,11 @@ func TestGetFilterChanges(t *testing.T) {
 					Return(filter, nil).
 					Once()
 
+				m.Pool.
+					On("PendingTxHashesExposed").
+					Return(true).
+					Once()
+
 				m.Pool.
 					On("GetPendingTxHashesSince", context.Background(), filter.LastPoll).
 					Return(tc.ExpectedResults[0].([]common.Hash), nil).
@@ -3880,6 +4029,11 @@ func TestGetFilterChanges(t *testing.T) {
 							Return(filter, nil).
 							Once()
 
+						m.Pool.
+							On("PendingTxHashesExposed").
+							Return(true).
+							Once()
+
 						m.Pool.
 							On("GetPendingTxHashesSince", context.Background(), filter.LastPoll).
 							Return(tc.ExpectedResults[1].([]common.Hash), nil).
@@ -3895,6 +4049,11 @@ func TestGetFilterChanges(t *testing.T) {
 									Return(filter, nil).
 									Once()
 
+								m.Pool.
+									On("PendingTxHashesExposed").
+									Return(true).
+									Once()
+
 								m.Pool.
 									On("GetPendingTxHashesSince", context.Background(), filter.LastPoll).
 									Return(tc.ExpectedResults[2].([]common.Hash), nil).
@@ -4129,6 +4288,11 @@ func TestGetFilterChanges(t *testing.T) {
 					Return(filter, nil).
 					Once()
 
+				m.Pool.
+					On("PendingTxHashesExposed").
+					Return(true).
+					Once()
+
 				m.Pool.
 					On("GetPendingTxHashesSince", context.Background(), filter.LastPoll).
 					Return([]common.Hash{}, errors.New("failed to get pending tx hashes")).
@@ -4155,6 +4319,11 @@ func TestGetFilterChanges(t *testing.T) {
 					Return(filter, nil).
 					Once()
 
+				m.Pool.
+					On("PendingTxHashesExposed").
+					Return(true).
+					Once()
+
 				m.Pool.
 					On("GetPendingTxHashesSince", context.Background(), filter.LastPoll).
 					Return([]common.Hash{}, nil).
@@ -0,0 +1,241 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// traceTypeTrace is the only trace type supported by trace_replayBlockTransactions
+	traceTypeTrace = "trace"
+)
+
+// TraceEndpoints is the parity style trace jsonrpc endpoint
+type TraceEndpoints struct {
+	cfg      Config
+	state    types.StateInterface
+	etherman types.EthermanInterface
+}
+
+// NewTraceEndpoints returns TraceEndpoints
+func NewTraceEndpoints(cfg Config, state types.StateInterface, etherman types.EthermanInterface) *TraceEndpoints {
+	return &TraceEndpoints{
+		cfg:      cfg,
+		state:    state,
+		etherman: etherman,
+	}
+}
+
+type traceFilterRequest struct {
+	FromBlock   *types.BlockNumber `json:"fromBlock"`
+	ToBlock     *types.BlockNumber `json:"toBlock"`
+	FromAddress []common.Address   `json:"fromAddress"`
+	ToAddress   []common.Address   `json:"toAddress"`
+	After       *types.ArgUint64   `json:"after"`
+	Count       *types.ArgUint64   `json:"count"`
+}
+
+type traceReplayResponse struct {
+	Output          types.ArgBytes    `json:"output"`
+	StateDiff       interface{}       `json:"stateDiff"`
+	Trace           []json.RawMessage `json:"trace"`
+	TransactionHash common.Hash       `json:"transactionHash"`
+	VMTrace         interface{}       `json:"vmTrace"`
+}
+
+// flatTraceLocation contains the fields of a flat call trace used to find it
+type flatTraceLocation struct {
+	TraceAddress []uint64 `json:"traceAddress"`
+	Result       *struct {
+		Output types.ArgBytes `json:"output"`
+	} `json:"result"`
+}
+
+// Block creates a response for trace_block request.
+// See https://openethereum.github.io/JSONRPC-trace-module#trace_block
+func (t *TraceEndpoints) Block(number types.BlockNumber) (interface{}, types.Error) {
+	ctx := context.Background()
+	block, rpcErr := t.getL2Block(ctx, number)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	traces := []json.RawMessage{}
+	for _, tx := range block.Transactions() {
+		txTraces, rpcErr := t.traceTransaction(ctx, tx.Hash())
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		traces = append(traces, txTraces...)
+	}
+
+	return traces, nil
+}
+
+// Transaction creates a response for trace_transaction request.
+// See https://openethereum.github.io/JSONRPC-trace-module#trace_transaction
+func (t *TraceEndpoints) Transaction(hash types.ArgHash) (interface{}, types.Error) {
+	traces, rpcErr := t.traceTransaction(context.Background(), hash.Hash())
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	return traces, nil
+}
+
+// Get creates a response for trace_get request, returning the trace of the transaction
+// found at the provided trace address.
+// See https://openethereum.github.io/JSONRPC-trace-module#trace_get
+func (t *TraceEndpoints) Get(hash types.ArgHash, indices []types.ArgUint64) (interface{}, types.Error) {
+	traces, rpcErr := t.traceTransaction(context.Background(), hash.Hash())
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	for _, trace := range traces {
+		var location flatTraceLocation
+		if err := json.Unmarshal(trace, &location); err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to read trace", err, true)
+		}
+		if traceAddressEquals(location.TraceAddress, indices) {
+			return trace, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ReplayBlockTransactions creates a response for trace_replayBlockTransactions request.
+// Only the trace type "trace" is supported.
+// See https://openethereum.github.io/JSONRPC-trace-module#trace_replayblocktransactions
+func (t *TraceEndpoints) ReplayBlockTransactions(number types.BlockNumber, traceTypes []string) (interface{}, types.Error) {
+	for _, traceType := range traceTypes {
+		if traceType != traceTypeTrace {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("trace type %s is not supported", traceType), nil, false)
+		}
+	}
+
+	ctx := context.Background()
+	block, rpcErr := t.getL2Block(ctx, number)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	responses := make([]traceReplayResponse, 0, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		traces, rpcErr := t.traceTransaction(ctx, tx.Hash())
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		response := traceReplayResponse{TransactionHash: tx.Hash(), Output: types.ArgBytes{}}
+		if len(traceTypes) > 0 {
+			response.Trace = traces
+		}
+		// the output of the transaction is the output of the top level call
+		for _, trace := range traces {
+			var location flatTraceLocation
+			if err := json.Unmarshal(trace, &location); err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to read trace", err, true)
+			}
+			if len(location.TraceAddress) == 0 && location.Result != nil {
+				response.Output = location.Result.Output
+				break
+			}
+		}
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+// Filter creates a response for trace_filter request. The traces are read from the trace index,
+// so the requested range must have been indexed already.
+// See https://openethereum.github.io/JSONRPC-trace-module#trace_filter
+func (t *TraceEndpoints) Filter(filter traceFilterRequest) (interface{}, types.Error) {
+	ctx := context.Background()
+	fromBlock, toBlock, rpcErr := getNumericBlockNumbers(ctx, t.state, t.etherman, filter.FromBlock, filter.ToBlock, t.cfg.MaxTraceFilterBlockRange, state.ErrMaxTraceFilterBlockRangeLimitExceeded, nil)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	lastIndexed, err := t.state.GetLastIndexedTraceL2BlockNumber(ctx, nil)
+	if errors.Is(err, state.ErrNotFound) {
+		return RPCErrorResponse(types.DefaultErrorCode, "traces are not indexed yet", nil, false)
+	} else if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get the last indexed block", err, true)
+	}
+	if toBlock > lastIndexed {
+		return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("traces are indexed up to block #%d", lastIndexed), nil, false)
+	}
+
+	stateFilter := state.TraceFilter{
+		FromBlock:     fromBlock,
+		ToBlock:       toBlock,
+		FromAddresses: filter.FromAddress,
+		ToAddresses:   filter.ToAddress,
+	}
+	if filter.After != nil {
+		stateFilter.After = uint64(*filter.After)
+	}
+	if filter.Count != nil {
+		count := uint64(*filter.Count)
+		stateFilter.Count = &count
+	}
+
+	traces, err := t.state.GetTraces(ctx, stateFilter, nil)
+	if errors.Is(err, state.ErrHistoryPruned) {
+		return nil, types.NewRPCError(types.HistoryPrunedErrorCode, fmt.Sprintf("traces of the range #%d to #%d have been pruned", fromBlock, toBlock))
+	} else if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get traces from state", err, true)
+	}
+
+	return traces, nil
+}
+
+func (t *TraceEndpoints) getL2Block(ctx context.Context, number types.BlockNumber) (*state.L2Block, types.Error) {
+	blockNumber, rpcErr := number.GetNumericBlockNumber(ctx, t.state, t.etherman, nil)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	block, err := t.state.GetL2BlockByNumber(ctx, blockNumber, nil)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, types.NewRPCError(types.DefaultErrorCode, fmt.Sprintf("block #%d not found", blockNumber))
+	} else if err != nil {
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to get block by number", err, true)
+		return nil, rpcErr
+	}
+
+	return block, nil
+}
+
+func (t *TraceEndpoints) traceTransaction(ctx context.Context, hash common.Hash) ([]json.RawMessage, types.Error) {
+	traces, err := t.state.FlatTraceTransaction(ctx, hash, nil)
+	if errors.Is(err, state.ErrNotFound) {
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "transaction not found", nil, false)
+		return nil, rpcErr
+	} else if err != nil {
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to get trace: %v", err.Error()), err, true)
+		return nil, rpcErr
+	}
+
+	return traces, nil
+}
+
+func traceAddressEquals(traceAddress []uint64, indices []types.ArgUint64) bool {
+	if len(traceAddress) != len(indices) {
+		return false
+	}
+	for i, index := range indices {
+		if traceAddress[i] != uint64(index) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,159 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func flatTraces(t *testing.T, traces ...string) []json.RawMessage {
+	result := make([]json.RawMessage, 0, len(traces))
+	for _, trace := range traces {
+		require.True(t, json.Valid([]byte(trace)))
+		result = append(result, json.RawMessage(trace))
+	}
+	return result
+}
+
+func TestTraceTransaction(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	txHash := common.HexToHash("0x1")
+	traces := flatTraces(t,
+		`{"type":"call","action":{"from":"0x0000000000000000000000000000000000000001"},"result":{"output":"0x01"},"traceAddress":[]}`,
+		`{"type":"call","action":{"from":"0x0000000000000000000000000000000000000002"},"result":{"output":"0x"},"traceAddress":[0]}`,
+	)
+
+	m.State.
+		On("FlatTraceTransaction", context.Background(), txHash, nil).
+		Return(traces, nil).
+		Once()
+	res, err := s.JSONRPCCall("trace_transaction", txHash.String())
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var result []json.RawMessage
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Len(t, result, 2)
+
+	m.State.
+		On("FlatTraceTransaction", context.Background(), txHash, nil).
+		Return(traces, nil).
+		Once()
+	res, err = s.JSONRPCCall("trace_get", txHash.String(), []string{"0x0"})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.JSONEq(t, string(traces[1]), string(res.Result))
+
+	m.State.
+		On("FlatTraceTransaction", context.Background(), txHash, nil).
+		Return(nil, state.ErrNotFound).
+		Once()
+	res, err = s.JSONRPCCall("trace_transaction", txHash.String())
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, "transaction not found", res.Error.Message)
+}
+
+func TestTraceReplayBlockTransactions(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	tx := ethTypes.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), nil)
+	header := state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1)})
+	block := state.NewL2Block(header, []*ethTypes.Transaction{tx}, nil, []*ethTypes.Receipt{ethTypes.NewReceipt([]byte{}, false, 0)}, trie.NewStackTrie(nil))
+	traces := flatTraces(t, `{"type":"call","action":{},"result":{"output":"0x0102"},"traceAddress":[]}`)
+
+	m.State.
+		On("GetL2BlockByNumber", context.Background(), uint64(1), nil).
+		Return(block, nil).
+		Once()
+	m.State.
+		On("FlatTraceTransaction", context.Background(), tx.Hash(), nil).
+		Return(traces, nil).
+		Once()
+	res, err := s.JSONRPCCall("trace_replayBlockTransactions", "0x1", []string{"trace"})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var result []traceReplayResponse
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	require.Len(t, result, 1)
+	assert.Equal(t, tx.Hash(), result[0].TransactionHash)
+	assert.Equal(t, types.ArgBytes{0x01, 0x02}, result[0].Output)
+	assert.Len(t, result[0].Trace, 1)
+
+	res, err = s.JSONRPCCall("trace_replayBlockTransactions", "0x1", []string{"trace", "vmTrace"})
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.InvalidParamsErrorCode, res.Error.Code)
+}
+
+func TestTraceFilter(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	from := common.HexToAddress("0x1")
+	traces := flatTraces(t, `{"type":"call","action":{"from":"0x0000000000000000000000000000000000000001"},"traceAddress":[]}`)
+	count := uint64(10)
+
+	m.State.
+		On("GetLastIndexedTraceL2BlockNumber", context.Background(), nil).
+		Return(uint64(20), nil).
+		Once()
+	m.State.
+		On("GetTraces", context.Background(), state.TraceFilter{FromBlock: 5, ToBlock: 10, FromAddresses: []common.Address{from}, After: 1, Count: &count}, nil).
+		Return(traces, nil).
+		Once()
+	res, err := s.JSONRPCCall("trace_filter", map[string]interface{}{
+		"fromBlock":   "0x5",
+		"toBlock":     "0xa",
+		"fromAddress": []string{from.String()},
+		"after":       "0x1",
+		"count":       "0xa",
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var result []json.RawMessage
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Len(t, result, 1)
+
+	// the range has been pruned
+	m.State.
+		On("GetLastIndexedTraceL2BlockNumber", context.Background(), nil).
+		Return(uint64(20), nil).
+		Once()
+	m.State.
+		On("GetTraces", context.Background(), state.TraceFilter{FromBlock: 1, ToBlock: 3}, nil).
+		Return(nil, state.ErrHistoryPruned).
+		Once()
+	res, err = s.JSONRPCCall("trace_filter", map[string]interface{}{"fromBlock": "0x1", "toBlock": "0x3"})
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.HistoryPrunedErrorCode, res.Error.Code)
+	assert.Equal(t, "traces of the range #1 to #3 have been pruned", res.Error.Message)
+
+	// the range is not indexed yet
+	m.State.
+		On("GetLastIndexedTraceL2BlockNumber", context.Background(), nil).
+		Return(uint64(8), nil).
+		Once()
+	res, err = s.JSONRPCCall("trace_filter", map[string]interface{}{"fromBlock": "0x5", "toBlock": "0xa"})
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, "traces are indexed up to block #8", res.Error.Message)
+
+	res, err = s.JSONRPCCall("trace_filter", map[string]interface{}{"fromBlock": "0x0", "toBlock": "0x3e9"})
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.InvalidParamsErrorCode, res.Error.Code)
+	assert.Equal(t, "traces are limited to a 1000 block range", res.Error.Message)
+}
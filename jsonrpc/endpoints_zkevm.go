@@ -195,6 +195,25 @@ func (z *ZKEVMEndpoints) GetBatchByNumber(batchNumber types.BatchNumber, fullTx
 	return rpcBatch, nil
 }
 
+// GetBatchWitness returns the witness of a closed batch, with its inputs and the partial state
+// tree needed to execute it without the state
+func (z *ZKEVMEndpoints) GetBatchWitness(batchNumber types.BatchNumber) (interface{}, types.Error) {
+	ctx := context.Background()
+	numericBatchNumber, rpcErr := batchNumber.GetNumericBatchNumber(ctx, z.state, z.etherman, nil)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	witness, err := z.state.GetBatchWitness(ctx, numericBatchNumber, nil)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't get the witness of the batch %v", numericBatchNumber), err, true)
+	}
+
+	return types.NewBatchWitness(witness), nil
+}
+
 // GetFullBlockByNumber returns information about a block by block number
 func (z *ZKEVMEndpoints) GetFullBlockByNumber(number types.BlockNumber, fullTx bool) (interface{}, types.Error) {
 	ctx := context.Background()
@@ -336,6 +355,10 @@ func (z *ZKEVMEndpoints) GetTransactionByL2Hash(hash types.ArgHash) (interface{}
 	if z.cfg.SequencerNodeURI != "" {
 		return z.getTransactionByL2HashFromSequencerNode(hash.Hash())
 	}
+	// in private mempool mode the pending txs are hidden until they are stored in an L2 block
+	if z.pool.PrivateMempoolEnabled() {
+		return nil, nil
+	}
 	poolTx, err := z.pool.GetTransactionByL2Hash(ctx, hash.Hash())
 	if errors.Is(err, pool.ErrNotFound) {
 		return nil, nil
@@ -492,7 +515,7 @@ func (z *ZKEVMEndpoints) internalEstimateGasPriceAndFee(ctx context.Context, arg
 			return nil, nil, types.NewRPCError(types.DefaultErrorCode, "failed to encode tx", err, false)
 		}
 
-		txEGP, err := z.pool.CalculateEffectiveGasPrice(rawTx, txGasPrice, gasEstimation, gasPrices.L1GasPrice, gasPrices.L2GasPrice)
+		txEGP, err := z.pool.CalculateEffectiveGasPrice(rawTx, txGasPrice, gasEstimation, gasPrices.L1GasPrice, gasPrices.L1BlobBaseFee, gasPrices.L2GasPrice)
 		if err != nil {
 			return nil, nil, types.NewRPCError(types.DefaultErrorCode, "failed to calculate effective gas price", err, false)
 		}
@@ -521,8 +544,8 @@ func (z *ZKEVMEndpoints) internalEstimateGasPriceAndFee(ctx context.Context, arg
 }
 
 // EstimateCounters returns an estimation of the counters that are going to be used while executing
-// this transaction.
-func (z *ZKEVMEndpoints) EstimateCounters(arg *types.TxArgs, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+// this transaction. If a tracer is provided, the trace of the transaction is included in the response.
+func (z *ZKEVMEndpoints) EstimateCounters(arg *types.TxArgs, blockArg *types.BlockNumberOrHash, cfg *traceConfig) (interface{}, types.Error) {
 	ctx := context.Background()
 	if arg == nil {
 		return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 0", nil, false)
@@ -587,7 +610,20 @@ func (z *ZKEVMEndpoints) EstimateCounters(arg *types.TxArgs, blockArg *types.Blo
 		MaxSteps:            types.ArgUint64(z.cfg.ZKCountersLimits.MaxSteps),
 		MaxSHA256Hashes:     types.ArgUint64(z.cfg.ZKCountersLimits.MaxSHA256Hashes),
 	}
-	return types.NewZKCountersResponse(processBatchResponse.UsedZkCounters, limits, revert, oocErr), nil
+	response := types.NewZKCountersResponse(processBatchResponse.UsedZkCounters, limits, revert, oocErr)
+
+	// the tx is traced only when a tracer is requested, e.g. the zkCounterTracer to estimate
+	// which calls and opcodes consume the counters
+	if cfg != nil && cfg.Tracer != nil {
+		result, err := z.state.DebugUnsignedTransaction(ctx, tx, sender, blockToProcess, cfg.toStateTraceConfig(), nil)
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to get trace: %v", err.Error())
+			return nil, types.NewRPCError(types.DefaultErrorCode, errMsg)
+		}
+		response.Trace = result.TraceResult
+	}
+
+	return response, nil
 }
 
 func (z *ZKEVMEndpoints) getBlockByArg(ctx context.Context, blockArg *types.BlockNumberOrHash, dbTx pgx.Tx) (*state.L2Block, types.Error) {
@@ -626,6 +662,65 @@ func (z *ZKEVMEndpoints) getBlockByArg(ctx context.Context, blockArg *types.Bloc
 	return block, nil
 }
 
+// GetGasPriceHistory returns the updates of the gas prices set by the L2 gas pricer in the time
+// range of the filter, the most recent first, with the inputs used to calculate them
+func (z *ZKEVMEndpoints) GetGasPriceHistory(filter GasPriceHistoryFilter) (interface{}, types.Error) {
+	ctx := context.Background()
+	from, to, limit, rpcErr := filter.GetTimeRangeAndLimit()
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	history, err := z.pool.GetGasPriceHistory(ctx, from, to, limit)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get gas price history from pool", err, true)
+	}
+
+	result := make([]types.GasPriceHistoryEntry, 0, len(history))
+	for _, entry := range history {
+		result = append(result, types.NewGasPriceHistoryEntry(entry))
+	}
+	return result, nil
+}
+
+// GetPendingForcedBatches returns the forced batches stored on L1 that are pending to be included in a trusted
+// batch, with the deadline after which anyone can sequence them on L1 if the trusted sequencer has not done it.
+// The force batch timeout is the one stored in the state by the forced batches tracker of the sequencer, it's
+// only read from L1 if it isn't stored, e.g. when the node doesn't share the state DB with the sequencer
+func (z *ZKEVMEndpoints) GetPendingForcedBatches() (interface{}, types.Error) {
+	ctx := context.Background()
+
+	lastTrustedForcedBatchNumber, err := z.state.GetLastTrustedForcedBatchNumber(ctx, nil)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get last trusted forced batch number from state", err, true)
+	}
+
+	lastBlock, err := z.state.GetLastBlock(ctx, nil)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get last L1 block from state", err, true)
+	}
+
+	forcedBatches, err := z.state.GetForcedBatchesSince(ctx, lastTrustedForcedBatchNumber, lastBlock.BlockNumber, nil)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get forced batches from state", err, true)
+	}
+
+	timeout, err := z.state.GetForceBatchTimeout(ctx, nil)
+	if errors.Is(err, state.ErrNotFound) {
+		timeout, err = z.etherman.GetForceBatchTimeout()
+	}
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get force batch timeout", err, true)
+	}
+
+	now := time.Now()
+	result := make([]types.PendingForcedBatch, 0, len(forcedBatches))
+	for _, forcedBatch := range forcedBatches {
+		result = append(result, types.NewPendingForcedBatch(*forcedBatch, timeout, now))
+	}
+	return result, nil
+}
+
 // GetLatestGlobalExitRoot returns the last global exit root used by l2
 func (z *ZKEVMEndpoints) GetLatestGlobalExitRoot() (interface{}, types.Error) {
 	ctx := context.Background()
@@ -15,6 +15,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
 	"github.com/0xPolygonHermez/zkevm-node/test/operations"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -1656,6 +1657,11 @@ func TestGetTransactionByL2Hash(t *testing.T) {
 					Return(nil, state.ErrNotFound).
 					Once()
 
+				m.Pool.
+					On("PrivateMempoolEnabled").
+					Return(false).
+					Once()
+
 				m.Pool.
 					On("GetTransactionByL2Hash", context.Background(), tc.Hash).
 					Return(&pool.Transaction{Transaction: *signedTx, Status: pool.TxStatusPending}, nil).
@@ -1674,12 +1680,35 @@ func TestGetTransactionByL2Hash(t *testing.T) {
 					Return(nil, state.ErrNotFound).
 					Once()
 
+				m.Pool.
+					On("PrivateMempoolEnabled").
+					Return(false).
+					Once()
+
 				m.Pool.
 					On("GetTransactionByL2Hash", context.Background(), tc.Hash).
 					Return(nil, pool.ErrNotFound).
 					Once()
 			},
 		},
+		{
+			Name:            "TX pending in the pool hidden in private mempool mode",
+			Hash:            common.HexToHash("0x123"),
+			ExpectedPending: false,
+			ExpectedResult:  nil,
+			ExpectedError:   nil,
+			SetupMocks: func(m *mocksWrapper, tc testCase) {
+				m.State.
+					On("GetTransactionByL2Hash", context.Background(), tc.Hash, nil).
+					Return(nil, state.ErrNotFound).
+					Once()
+
+				m.Pool.
+					On("PrivateMempoolEnabled").
+					Return(true).
+					Once()
+			},
+		},
 		{
 			Name:            "TX failed to load from the state",
 			Hash:            common.HexToHash("0x123"),
@@ -1705,6 +1734,11 @@ func TestGetTransactionByL2Hash(t *testing.T) {
 					Return(nil, state.ErrNotFound).
 					Once()
 
+				m.Pool.
+					On("PrivateMempoolEnabled").
+					Return(false).
+					Once()
+
 				m.Pool.
 					On("GetTransactionByL2Hash", context.Background(), tc.Hash).
 					Return(nil, errors.New("failed to load transaction by l2 hash from pool")).
@@ -2180,3 +2214,222 @@ func TestGetLatestGlobalExitRoot(t *testing.T) {
 		})
 	}
 }
+
+func TestGetGasPriceHistory(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	from := time.Unix(1700000000, 0).UTC()
+	to := from.Add(time.Minute)
+	history := []pool.GasPriceHistoryEntry{
+		{
+			GasPrices: pool.GasPrices{L2GasPrice: 1500, L1GasPrice: 10020, L1BlobBaseFee: 7},
+			Inputs: pool.GasPriceInputs{
+				Pricer:           "follower",
+				L1GasPriceSource: "l1",
+				L1CostModel:      "mix",
+				Factor:           0.15,
+				RawL2GasPrice:    1503,
+				Truncated:        true,
+			},
+			Timestamp: to,
+		},
+		{
+			GasPrices: pool.GasPrices{L2GasPrice: 1, L1GasPrice: 2},
+			Timestamp: from,
+		},
+	}
+
+	m.Pool.
+		On("GetGasPriceHistory", context.Background(), from, to, uint64(10)).
+		Return(history, nil).
+		Once()
+
+	filter := map[string]interface{}{
+		"fromTimestamp": hex.EncodeUint64(uint64(from.Unix())),
+		"toTimestamp":   hex.EncodeUint64(uint64(to.Unix())),
+		"limit":         hex.EncodeUint64(10),
+	}
+	res, err := s.JSONRPCCall("zkevm_getGasPriceHistory", filter)
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var result []types.GasPriceHistoryEntry
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	require.Len(t, result, 2)
+	assert.Equal(t, types.NewGasPriceHistoryEntry(history[0]), result[0])
+	assert.Equal(t, types.ArgUint64(to.Unix()), result[0].Timestamp)
+	assert.Equal(t, types.ArgUint64(1503), result[0].RawL2GasPrice)
+	assert.Equal(t, types.ArgUint64(7), result[0].L1BlobBaseFee)
+	assert.Equal(t, types.NewGasPriceHistoryEntry(history[1]), result[1])
+
+	m.Pool.
+		On("GetGasPriceHistory", context.Background(), from, to, uint64(maxGasPriceHistoryEntries)).
+		Return(nil, errors.New("db error")).
+		Once()
+
+	delete(filter, "limit")
+	res, err = s.JSONRPCCall("zkevm_getGasPriceHistory", filter)
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, "failed to get gas price history from pool", res.Error.Message)
+
+	filter["fromTimestamp"] = hex.EncodeUint64(uint64(to.Unix() + 1))
+	res, err = s.JSONRPCCall("zkevm_getGasPriceHistory", filter)
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.InvalidParamsErrorCode, res.Error.Code)
+}
+
+func TestGetPendingForcedBatches(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	now := time.Now()
+	forcedBatches := []*state.ForcedBatch{
+		{
+			ForcedBatchNumber: 3,
+			BlockNumber:       90,
+			Sequencer:         common.HexToAddress("0x1"),
+			GlobalExitRoot:    common.HexToHash("0x2"),
+			ForcedAt:          now.Add(-2 * time.Hour),
+		},
+		{
+			ForcedBatchNumber: 4,
+			BlockNumber:       95,
+			ForcedAt:          now.Add(-time.Hour),
+		},
+	}
+
+	m.State.On("GetLastTrustedForcedBatchNumber", context.Background(), nil).Return(uint64(2), nil)
+	m.State.On("GetLastBlock", context.Background(), nil).Return(&state.Block{BlockNumber: 100}, nil)
+	m.State.On("GetForcedBatchesSince", context.Background(), uint64(2), uint64(100), nil).Return(forcedBatches, nil)
+	m.State.On("GetForceBatchTimeout", context.Background(), nil).Return(90*time.Minute, nil).Once()
+
+	res, err := s.JSONRPCCall("zkevm_getPendingForcedBatches")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var result []types.PendingForcedBatch
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	require.Len(t, result, 2)
+	assert.Equal(t, types.ArgUint64(3), result[0].ForcedBatchNumber)
+	assert.Equal(t, types.ArgUint64(90), result[0].BlockNumber)
+	assert.Equal(t, common.HexToAddress("0x1"), result[0].Sequencer)
+	assert.Equal(t, common.HexToHash("0x2"), result[0].GlobalExitRoot)
+	assert.Equal(t, types.ArgUint64(forcedBatches[0].ForcedAt.Unix()), result[0].ForcedAt)
+	assert.Equal(t, types.ArgUint64(forcedBatches[0].ForcedAt.Add(90*time.Minute).Unix()), result[0].Deadline)
+	assert.True(t, result[0].Expired)
+	assert.Equal(t, types.ArgUint64(4), result[1].ForcedBatchNumber)
+	assert.False(t, result[1].Expired)
+
+	// the force batch timeout is read from L1 when the sequencer has not stored it
+	m.State.On("GetForceBatchTimeout", context.Background(), nil).Return(time.Duration(0), state.ErrNotFound).Twice()
+	m.Etherman.On("GetForceBatchTimeout").Return(90*time.Minute, nil).Once()
+	res, err = s.JSONRPCCall("zkevm_getPendingForcedBatches")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, types.ArgUint64(forcedBatches[0].ForcedAt.Add(90*time.Minute).Unix()), result[0].Deadline)
+
+	m.Etherman.On("GetForceBatchTimeout").Return(time.Duration(0), errors.New("L1 error")).Once()
+	res, err = s.JSONRPCCall("zkevm_getPendingForcedBatches")
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, "failed to get force batch timeout", res.Error.Message)
+}
+
+func TestGetBatchWitness(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	witness := &state.BatchWitness{
+		BatchNumber:     2,
+		ChainID:         1000,
+		ForkID:          state.FORKID_ETROG,
+		OldStateRoot:    common.HexToHash("0x1"),
+		NewStateRoot:    common.HexToHash("0x2"),
+		OldAccInputHash: common.HexToHash("0x3"),
+		Coinbase:        common.HexToAddress("0x4"),
+		BatchL2Data:     []byte{0x0b, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01},
+		L1InfoRoot:      common.HexToHash("0x5"),
+		L1InfoTreeData: map[uint32]state.L1DataV2{
+			1: {GlobalExitRoot: common.HexToHash("0x6"), BlockHashL1: common.HexToHash("0x7"), MinTimestamp: 100},
+		},
+		TimestampLimit:    200,
+		Db:                map[string]string{"0a": "0b"},
+		ContractsBytecode: map[string]string{"0c": "6080"},
+	}
+
+	m.State.
+		On("GetBatchWitness", context.Background(), uint64(2), nil).
+		Return(witness, nil).
+		Once()
+
+	res, err := s.JSONRPCCall("zkevm_getBatchWitness", "0x2")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var result types.BatchWitness
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, types.ArgUint64(2), result.BatchNumber)
+	assert.Equal(t, types.ArgUint64(100), result.L1InfoTreeData[1].MinTimestamp)
+	assert.Equal(t, witness, result.ToState())
+
+	m.State.
+		On("GetBatchWitness", context.Background(), uint64(3), nil).
+		Return(nil, state.ErrNotFound).
+		Once()
+
+	res, err = s.JSONRPCCall("zkevm_getBatchWitness", "0x3")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.Equal(t, "null", string(res.Result))
+
+	m.State.
+		On("GetBatchWitness", context.Background(), uint64(4), nil).
+		Return(nil, errors.New("batch 4 is not closed")).
+		Once()
+
+	res, err = s.JSONRPCCall("zkevm_getBatchWitness", "0x4")
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, "couldn't get the witness of the batch 4", res.Error.Message)
+}
+
+func TestEstimateCountersWithTracer(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	to := common.HexToAddress("0x1")
+	block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1)}))
+	txArgs := types.TxArgs{To: &to}
+	tracer := "zkCounterTracer"
+	trace := json.RawMessage(`{"estimated":false,"attribution":"estimated","counters":{"steps":100}}`)
+
+	m.State.
+		On("GetLastL2BlockNumber", context.Background(), nil).
+		Return(uint64(1), nil).
+		Once()
+	m.State.
+		On("GetL2BlockByNumber", context.Background(), uint64(1), nil).
+		Return(block, nil).
+		Once()
+	m.State.
+		On("PreProcessUnsignedTransaction", context.Background(), mock.IsType(&ethTypes.Transaction{}), common.HexToAddress(state.DefaultSenderAddress), (*uint64)(nil), nil).
+		Return(&state.ProcessBatchResponse{UsedZkCounters: state.ZKCounters{Steps: 100}}, nil).
+		Once()
+	m.State.
+		On("DebugUnsignedTransaction", context.Background(), mock.IsType(&ethTypes.Transaction{}), common.HexToAddress(state.DefaultSenderAddress), (*uint64)(nil), state.TraceConfig{Tracer: &tracer}, nil).
+		Return(&runtime.ExecutionResult{TraceResult: trace}, nil).
+		Once()
+
+	res, err := s.JSONRPCCall("zkevm_estimateCounters", txArgs, "latest", map[string]interface{}{"tracer": tracer})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var result types.ZKCountersResponse
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, types.ArgUint64(100), result.CountersUsed.UsedSteps)
+	assert.JSONEq(t, string(trace), string(result.Trace))
+}
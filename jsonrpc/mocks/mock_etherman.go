@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -41,6 +42,34 @@ func (_m *EthermanMock) GetFinalizedBlockNumber(ctx context.Context) (uint64, er
 	return r0, r1
 }
 
+// GetForceBatchTimeout provides a mock function with given fields:
+func (_m *EthermanMock) GetForceBatchTimeout() (time.Duration, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForceBatchTimeout")
+	}
+
+	var r0 time.Duration
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (time.Duration, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSafeBlockNumber provides a mock function with given fields: ctx
 func (_m *EthermanMock) GetSafeBlockNumber(ctx context.Context) (uint64, error) {
 	ret := _m.Called(ctx)
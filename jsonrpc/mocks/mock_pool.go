@@ -40,9 +40,9 @@ func (_m *PoolMock) AddTx(ctx context.Context, tx types.Transaction, ip string)
 	return r0
 }
 
-// CalculateEffectiveGasPrice provides a mock function with given fields: rawTx, txGasPrice, txGasUsed, l1GasPrice, l2GasPrice
-func (_m *PoolMock) CalculateEffectiveGasPrice(rawTx []byte, txGasPrice *big.Int, txGasUsed uint64, l1GasPrice uint64, l2GasPrice uint64) (*big.Int, error) {
-	ret := _m.Called(rawTx, txGasPrice, txGasUsed, l1GasPrice, l2GasPrice)
+// CalculateEffectiveGasPrice provides a mock function with given fields: rawTx, txGasPrice, txGasUsed, l1GasPrice, l1BlobBaseFee, l2GasPrice
+func (_m *PoolMock) CalculateEffectiveGasPrice(rawTx []byte, txGasPrice *big.Int, txGasUsed uint64, l1GasPrice uint64, l1BlobBaseFee uint64, l2GasPrice uint64) (*big.Int, error) {
+	ret := _m.Called(rawTx, txGasPrice, txGasUsed, l1GasPrice, l1BlobBaseFee, l2GasPrice)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CalculateEffectiveGasPrice")
@@ -50,19 +50,19 @@ func (_m *PoolMock) CalculateEffectiveGasPrice(rawTx []byte, txGasPrice *big.Int
 
 	var r0 *big.Int
 	var r1 error
-	if rf, ok := ret.Get(0).(func([]byte, *big.Int, uint64, uint64, uint64) (*big.Int, error)); ok {
-		return rf(rawTx, txGasPrice, txGasUsed, l1GasPrice, l2GasPrice)
+	if rf, ok := ret.Get(0).(func([]byte, *big.Int, uint64, uint64, uint64, uint64) (*big.Int, error)); ok {
+		return rf(rawTx, txGasPrice, txGasUsed, l1GasPrice, l1BlobBaseFee, l2GasPrice)
 	}
-	if rf, ok := ret.Get(0).(func([]byte, *big.Int, uint64, uint64, uint64) *big.Int); ok {
-		r0 = rf(rawTx, txGasPrice, txGasUsed, l1GasPrice, l2GasPrice)
+	if rf, ok := ret.Get(0).(func([]byte, *big.Int, uint64, uint64, uint64, uint64) *big.Int); ok {
+		r0 = rf(rawTx, txGasPrice, txGasUsed, l1GasPrice, l1BlobBaseFee, l2GasPrice)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*big.Int)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func([]byte, *big.Int, uint64, uint64, uint64) error); ok {
-		r1 = rf(rawTx, txGasPrice, txGasUsed, l1GasPrice, l2GasPrice)
+	if rf, ok := ret.Get(1).(func([]byte, *big.Int, uint64, uint64, uint64, uint64) error); ok {
+		r1 = rf(rawTx, txGasPrice, txGasUsed, l1GasPrice, l1BlobBaseFee, l2GasPrice)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -144,6 +144,36 @@ func (_m *PoolMock) EffectiveGasPriceEnabled() bool {
 	return r0
 }
 
+// GetGasPriceHistory provides a mock function with given fields: ctx, from, to, limit
+func (_m *PoolMock) GetGasPriceHistory(ctx context.Context, from time.Time, to time.Time, limit uint64) ([]pool.GasPriceHistoryEntry, error) {
+	ret := _m.Called(ctx, from, to, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGasPriceHistory")
+	}
+
+	var r0 []pool.GasPriceHistoryEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, uint64) ([]pool.GasPriceHistoryEntry, error)); ok {
+		return rf(ctx, from, to, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, uint64) []pool.GasPriceHistoryEntry); ok {
+		r0 = rf(ctx, from, to, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pool.GasPriceHistoryEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, uint64) error); ok {
+		r1 = rf(ctx, from, to, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetGasPrices provides a mock function with given fields: ctx
 func (_m *PoolMock) GetGasPrices(ctx context.Context) (pool.GasPrices, error) {
 	ret := _m.Called(ctx)
@@ -320,6 +350,42 @@ func (_m *PoolMock) GetTransactionByL2Hash(ctx context.Context, hash common.Hash
 	return r0, r1
 }
 
+// PendingTxHashesExposed provides a mock function with given fields:
+func (_m *PoolMock) PendingTxHashesExposed() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PendingTxHashesExposed")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// PrivateMempoolEnabled provides a mock function with given fields:
+func (_m *PoolMock) PrivateMempoolEnabled() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PrivateMempoolEnabled")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // NewPoolMock creates a new instance of PoolMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewPoolMock(t interface {
@@ -10,6 +10,8 @@ import (
 
 	coretypes "github.com/ethereum/go-ethereum/core/types"
 
+	json "encoding/json"
+
 	mock "github.com/stretchr/testify/mock"
 
 	pgx "github.com/jackc/pgx/v4"
@@ -114,6 +116,36 @@ func (_m *StateMock) DebugTransaction(ctx context.Context, transactionHash commo
 	return r0, r1
 }
 
+// DebugUnsignedTransaction provides a mock function with given fields: ctx, tx, senderAddress, l2BlockNumber, traceConfig, dbTx
+func (_m *StateMock) DebugUnsignedTransaction(ctx context.Context, tx *coretypes.Transaction, senderAddress common.Address, l2BlockNumber *uint64, traceConfig state.TraceConfig, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
+	ret := _m.Called(ctx, tx, senderAddress, l2BlockNumber, traceConfig, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DebugUnsignedTransaction")
+	}
+
+	var r0 *runtime.ExecutionResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, state.TraceConfig, pgx.Tx) (*runtime.ExecutionResult, error)); ok {
+		return rf(ctx, tx, senderAddress, l2BlockNumber, traceConfig, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, state.TraceConfig, pgx.Tx) *runtime.ExecutionResult); ok {
+		r0 = rf(ctx, tx, senderAddress, l2BlockNumber, traceConfig, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*runtime.ExecutionResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, state.TraceConfig, pgx.Tx) error); ok {
+		r1 = rf(ctx, tx, senderAddress, l2BlockNumber, traceConfig, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EstimateGas provides a mock function with given fields: transaction, senderAddress, l2BlockNumber, dbTx
 func (_m *StateMock) EstimateGas(transaction *coretypes.Transaction, senderAddress common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (uint64, []byte, error) {
 	ret := _m.Called(transaction, senderAddress, l2BlockNumber, dbTx)
@@ -151,6 +183,36 @@ func (_m *StateMock) EstimateGas(transaction *coretypes.Transaction, senderAddre
 	return r0, r1, r2
 }
 
+// FlatTraceTransaction provides a mock function with given fields: ctx, transactionHash, dbTx
+func (_m *StateMock) FlatTraceTransaction(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) ([]json.RawMessage, error) {
+	ret := _m.Called(ctx, transactionHash, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FlatTraceTransaction")
+	}
+
+	var r0 []json.RawMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pgx.Tx) ([]json.RawMessage, error)); ok {
+		return rf(ctx, transactionHash, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pgx.Tx) []json.RawMessage); ok {
+		r0 = rf(ctx, transactionHash, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]json.RawMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Hash, pgx.Tx) error); ok {
+		r1 = rf(ctx, transactionHash, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBalance provides a mock function with given fields: ctx, address, root
 func (_m *StateMock) GetBalance(ctx context.Context, address common.Address, root common.Hash) (*big.Int, error) {
 	ret := _m.Called(ctx, address, root)
@@ -241,6 +303,36 @@ func (_m *StateMock) GetBatchTimestamp(ctx context.Context, batchNumber uint64,
 	return r0, r1
 }
 
+// GetBatchWitness provides a mock function with given fields: ctx, batchNumber, dbTx
+func (_m *StateMock) GetBatchWitness(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.BatchWitness, error) {
+	ret := _m.Called(ctx, batchNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBatchWitness")
+	}
+
+	var r0 *state.BatchWitness
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (*state.BatchWitness, error)); ok {
+		return rf(ctx, batchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) *state.BatchWitness); ok {
+		r0 = rf(ctx, batchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.BatchWitness)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCode provides a mock function with given fields: ctx, address, root
 func (_m *StateMock) GetCode(ctx context.Context, address common.Address, root common.Hash) ([]byte, error) {
 	ret := _m.Called(ctx, address, root)
@@ -301,6 +393,64 @@ func (_m *StateMock) GetExitRootByGlobalExitRoot(ctx context.Context, ger common
 	return r0, r1
 }
 
+// GetForceBatchTimeout provides a mock function with given fields: ctx, dbTx
+func (_m *StateMock) GetForceBatchTimeout(ctx context.Context, dbTx pgx.Tx) (time.Duration, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForceBatchTimeout")
+	}
+
+	var r0 time.Duration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (time.Duration, error)); ok {
+		return rf(ctx, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) time.Duration); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetForcedBatchesSince provides a mock function with given fields: ctx, forcedBatchNumber, maxBlockNumber, dbTx
+func (_m *StateMock) GetForcedBatchesSince(ctx context.Context, forcedBatchNumber uint64, maxBlockNumber uint64, dbTx pgx.Tx) ([]*state.ForcedBatch, error) {
+	ret := _m.Called(ctx, forcedBatchNumber, maxBlockNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForcedBatchesSince")
+	}
+
+	var r0 []*state.ForcedBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) ([]*state.ForcedBatch, error)); ok {
+		return rf(ctx, forcedBatchNumber, maxBlockNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) []*state.ForcedBatch); ok {
+		r0 = rf(ctx, forcedBatchNumber, maxBlockNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*state.ForcedBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, forcedBatchNumber, maxBlockNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetL2BlockByHash provides a mock function with given fields: ctx, hash, dbTx
 func (_m *StateMock) GetL2BlockByHash(ctx context.Context, hash common.Hash, dbTx pgx.Tx) (*state.L2Block, error) {
 	ret := _m.Called(ctx, hash, dbTx)
@@ -565,6 +715,36 @@ func (_m *StateMock) GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint6
 	return r0, r1
 }
 
+// GetLastBlock provides a mock function with given fields: ctx, dbTx
+func (_m *StateMock) GetLastBlock(ctx context.Context, dbTx pgx.Tx) (*state.Block, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastBlock")
+	}
+
+	var r0 *state.Block
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (*state.Block, error)); ok {
+		return rf(ctx, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) *state.Block); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.Block)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastClosedBatchNumber provides a mock function with given fields: ctx, dbTx
 func (_m *StateMock) GetLastClosedBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
 	ret := _m.Called(ctx, dbTx)
@@ -621,6 +801,34 @@ func (_m *StateMock) GetLastConsolidatedL2BlockNumber(ctx context.Context, dbTx
 	return r0, r1
 }
 
+// GetLastIndexedTraceL2BlockNumber provides a mock function with given fields: ctx, dbTx
+func (_m *StateMock) GetLastIndexedTraceL2BlockNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastIndexedTraceL2BlockNumber")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (uint64, error)); ok {
+		return rf(ctx, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) uint64); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastL2Block provides a mock function with given fields: ctx, dbTx
 func (_m *StateMock) GetLastL2Block(ctx context.Context, dbTx pgx.Tx) (*state.L2Block, error) {
 	ret := _m.Called(ctx, dbTx)
@@ -679,6 +887,34 @@ func (_m *StateMock) GetLastL2BlockNumber(ctx context.Context, dbTx pgx.Tx) (uin
 	return r0, r1
 }
 
+// GetLastTrustedForcedBatchNumber provides a mock function with given fields: ctx, dbTx
+func (_m *StateMock) GetLastTrustedForcedBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastTrustedForcedBatchNumber")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (uint64, error)); ok {
+		return rf(ctx, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) uint64); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastVerifiedBatch provides a mock function with given fields: ctx, dbTx
 func (_m *StateMock) GetLastVerifiedBatch(ctx context.Context, dbTx pgx.Tx) (*state.VerifiedBatch, error) {
 	ret := _m.Called(ctx, dbTx)
@@ -939,6 +1175,36 @@ func (_m *StateMock) GetNonce(ctx context.Context, address common.Address, root
 	return r0, r1
 }
 
+// GetSequencerHalt provides a mock function with given fields: ctx, dbTx
+func (_m *StateMock) GetSequencerHalt(ctx context.Context, dbTx pgx.Tx) (*state.SequencerHalt, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSequencerHalt")
+	}
+
+	var r0 *state.SequencerHalt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (*state.SequencerHalt, error)); ok {
+		return rf(ctx, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) *state.SequencerHalt); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.SequencerHalt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStorageAt provides a mock function with given fields: ctx, address, position, root
 func (_m *StateMock) GetStorageAt(ctx context.Context, address common.Address, position *big.Int, root common.Hash) (*big.Int, error) {
 	ret := _m.Called(ctx, address, position, root)
@@ -997,6 +1263,36 @@ func (_m *StateMock) GetSyncingInfo(ctx context.Context, dbTx pgx.Tx) (state.Syn
 	return r0, r1
 }
 
+// GetTraces provides a mock function with given fields: ctx, filter, dbTx
+func (_m *StateMock) GetTraces(ctx context.Context, filter state.TraceFilter, dbTx pgx.Tx) ([]json.RawMessage, error) {
+	ret := _m.Called(ctx, filter, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTraces")
+	}
+
+	var r0 []json.RawMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, state.TraceFilter, pgx.Tx) ([]json.RawMessage, error)); ok {
+		return rf(ctx, filter, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, state.TraceFilter, pgx.Tx) []json.RawMessage); ok {
+		r0 = rf(ctx, filter, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]json.RawMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, state.TraceFilter, pgx.Tx) error); ok {
+		r1 = rf(ctx, filter, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTransactionByHash provides a mock function with given fields: ctx, transactionHash, dbTx
 func (_m *StateMock) GetTransactionByHash(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*coretypes.Transaction, error) {
 	ret := _m.Called(ctx, transactionHash, dbTx)
@@ -1186,6 +1482,36 @@ func (_m *StateMock) GetTransactionsByBatchNumber(ctx context.Context, batchNumb
 	return r0, r1, r2
 }
 
+// GetTransactionsEGPLogByL2BlockRange provides a mock function with given fields: ctx, fromBlockNumber, toBlockNumber, dbTx
+func (_m *StateMock) GetTransactionsEGPLogByL2BlockRange(ctx context.Context, fromBlockNumber uint64, toBlockNumber uint64, dbTx pgx.Tx) ([]state.TransactionEGPLog, error) {
+	ret := _m.Called(ctx, fromBlockNumber, toBlockNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionsEGPLogByL2BlockRange")
+	}
+
+	var r0 []state.TransactionEGPLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) ([]state.TransactionEGPLog, error)); ok {
+		return rf(ctx, fromBlockNumber, toBlockNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) []state.TransactionEGPLog); ok {
+		r0 = rf(ctx, fromBlockNumber, toBlockNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]state.TransactionEGPLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, fromBlockNumber, toBlockNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetVerifiedBatch provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StateMock) GetVerifiedBatch(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.VerifiedBatch, error) {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -1367,6 +1693,52 @@ func (_m *StateMock) RegisterNewL2BlockEventHandler(h state.NewL2BlockEventHandl
 	_m.Called(h)
 }
 
+// ResumeSequencer provides a mock function with given fields: ctx, reason, dbTx
+func (_m *StateMock) ResumeSequencer(ctx context.Context, reason string, dbTx pgx.Tx) (bool, error) {
+	ret := _m.Called(ctx, reason, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResumeSequencer")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, pgx.Tx) (bool, error)); ok {
+		return rf(ctx, reason, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, pgx.Tx) bool); ok {
+		r0 = rf(ctx, reason, dbTx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, pgx.Tx) error); ok {
+		r1 = rf(ctx, reason, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ScheduleSequencerHalt provides a mock function with given fields: ctx, batchNumber, haltAt, reason, dbTx
+func (_m *StateMock) ScheduleSequencerHalt(ctx context.Context, batchNumber uint64, haltAt *time.Time, reason string, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, batchNumber, haltAt, reason, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScheduleSequencerHalt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *time.Time, string, pgx.Tx) error); ok {
+		r0 = rf(ctx, batchNumber, haltAt, reason, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // StartToMonitorNewL2Blocks provides a mock function with given fields:
 func (_m *StateMock) StartToMonitorNewL2Blocks() {
 	_m.Called()
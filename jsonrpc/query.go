@@ -17,6 +17,12 @@ import (
 )
 
 const (
+	// defaultGasPriceHistoryRange is the time range of the gas price history returned when the
+	// filter doesn't set the start
+	defaultGasPriceHistoryRange = time.Hour
+	// maxGasPriceHistoryEntries is the max number of updates of the gas price history returned
+	maxGasPriceHistoryEntries = 1000
+
 	// FilterTypeLog represents a filter of type log.
 	FilterTypeLog = "log"
 	// FilterTypeBlock represents a filter of type block.
@@ -358,6 +364,34 @@ func (f *LogFilter) Validate() error {
 	return nil
 }
 
+// GasPriceHistoryFilter is a filter of the updates of the gas prices by their unix timestamp.
+// By default the updates of the last hour are returned, up to maxGasPriceHistoryEntries
+type GasPriceHistoryFilter struct {
+	FromTimestamp *types.ArgUint64 `json:"fromTimestamp"`
+	ToTimestamp   *types.ArgUint64 `json:"toTimestamp"`
+	Limit         *types.ArgUint64 `json:"limit"`
+}
+
+// GetTimeRangeAndLimit returns the time range and the max number of updates to return
+func (f *GasPriceHistoryFilter) GetTimeRangeAndLimit() (time.Time, time.Time, uint64, types.Error) {
+	to := time.Now().UTC()
+	if f.ToTimestamp != nil {
+		to = time.Unix(int64(*f.ToTimestamp), 0).UTC()
+	}
+	from := to.Add(-defaultGasPriceHistoryRange)
+	if f.FromTimestamp != nil {
+		from = time.Unix(int64(*f.FromTimestamp), 0).UTC()
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, 0, types.NewRPCError(types.InvalidParamsErrorCode, "fromTimestamp must be before toTimestamp")
+	}
+	limit := uint64(maxGasPriceHistoryEntries)
+	if f.Limit != nil && uint64(*f.Limit) < limit {
+		limit = uint64(*f.Limit)
+	}
+	return from, to, limit, nil
+}
+
 // NativeBlockHashBlockRangeFilter is a filter to filter native block hash by block by number
 type NativeBlockHashBlockRangeFilter struct {
 	FromBlock types.BlockNumber `json:"fromBlock"`
@@ -16,6 +16,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/didip/tollbooth/v6"
 	"github.com/gorilla/websocket"
 )
@@ -33,6 +34,10 @@ const (
 	APITxPool = "txpool"
 	// APIWeb3 represents the web3 API prefix.
 	APIWeb3 = "web3"
+	// APITrace represents the trace API prefix.
+	APITrace = "trace"
+	// APIAdmin represents the admin API prefix.
+	APIAdmin = "admin"
 
 	wsBufferSizeLimitInBytes = 1024
 	maxRequestContentLength  = 1024 * 1024 * 5
@@ -50,6 +55,8 @@ type Server struct {
 	srv        *http.Server
 	wsSrv      *http.Server
 	wsUpgrader websocket.Upgrader
+	// admin serves the admin API in its own listener, nil if the admin API is not enabled
+	admin *Server
 }
 
 // Service defines a struct that will provide public methods to be exposed
@@ -79,8 +86,15 @@ func NewServer(
 	}
 
 	handler := newJSONRpcHandler()
+	var adminHandler *Handler
 
 	for _, service := range services {
+		// The admin API allows to halt the sequencer, so it's not served by the public HTTP and WebSockets servers
+		if service.Name == APIAdmin {
+			adminHandler = newJSONRpcHandler()
+			adminHandler.registerService(service)
+			continue
+		}
 		handler.registerService(service)
 	}
 
@@ -89,6 +103,17 @@ func NewServer(
 		handler: handler,
 		chainID: chainID,
 	}
+	if adminHandler != nil {
+		adminCfg := cfg
+		adminCfg.Host = cfg.Admin.Host
+		adminCfg.Port = cfg.Admin.Port
+		adminCfg.WebSockets.Enabled = false
+		srv.admin = &Server{
+			config:  adminCfg,
+			handler: adminHandler,
+			chainID: chainID,
+		}
+	}
 	return srv
 }
 
@@ -100,6 +125,14 @@ func (s *Server) Start() error {
 		go s.startWS()
 	}
 
+	if s.admin != nil {
+		go func() {
+			if err := s.admin.startHTTP(); err != nil {
+				log.Errorf("failed to start admin http server: %v", err)
+			}
+		}()
+	}
+
 	return s.startHTTP()
 }
 
@@ -205,6 +238,12 @@ func (s *Server) Stop() error {
 		s.wsSrv = nil
 	}
 
+	if s.admin != nil {
+		if err := s.admin.Stop(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -479,6 +518,9 @@ func RPCErrorResponseWithData(code int, message string, data []byte, err error,
 			log.Debug(message)
 		}
 	}
+	if errors.Is(err, state.ErrHistoryPruned) {
+		return nil, types.NewRPCError(types.HistoryPrunedErrorCode, state.ErrHistoryPruned.Error())
+	}
 	return nil, types.NewRPCErrorWithData(code, message, data)
 }
 
@@ -35,6 +35,7 @@ type mockedServer struct {
 	Server              *Server
 	ServerURL           string
 	ServerWebSocketsURL string
+	AdminURL            string
 }
 
 type mocksWrapper struct {
@@ -56,6 +57,8 @@ func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *e
 		APIZKEVM:  true,
 		APITxPool: true,
 		APIWeb3:   true,
+		APITrace:  true,
+		APIAdmin:  true,
 	}
 
 	var newL2BlockEventHandler state.NewL2BlockEventHandler = func(e state.NewL2BlockEvent) {}
@@ -104,6 +107,20 @@ func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *e
 			Service: &Web3Endpoints{},
 		})
 	}
+
+	if _, ok := apis[APITrace]; ok {
+		services = append(services, Service{
+			Name:    APITrace,
+			Service: NewTraceEndpoints(cfg, st, etherman),
+		})
+	}
+
+	if _, ok := apis[APIAdmin]; ok {
+		services = append(services, Service{
+			Name:    APIAdmin,
+			Service: NewAdminEndpoints(cfg, st, egpCfg),
+		})
+	}
 	server := NewServer(cfg, chainID, pool, st, storage, services)
 
 	go func() {
@@ -114,14 +131,17 @@ func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *e
 	}()
 
 	serverURL := fmt.Sprintf("http://%s:%d", cfg.Host, cfg.Port)
-	for {
-		fmt.Println("waiting server to get ready...") // fmt is used here to avoid race condition with logs
-		res, err := http.Get(serverURL)               //nolint:gosec
-		if err == nil && res.StatusCode == http.StatusOK {
-			fmt.Println("server ready!") // fmt is used here to avoid race condition with logs
-			break
+	adminURL := fmt.Sprintf("http://%s:%d", cfg.Admin.Host, cfg.Admin.Port)
+	for _, url := range []string{serverURL, adminURL} {
+		for {
+			fmt.Println("waiting server to get ready...") // fmt is used here to avoid race condition with logs
+			res, err := http.Get(url)                     //nolint:gosec
+			if err == nil && res.StatusCode == http.StatusOK {
+				fmt.Println("server ready!") // fmt is used here to avoid race condition with logs
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
 		}
-		time.Sleep(10 * time.Millisecond)
 	}
 
 	ethClient, err := ethclient.Dial(serverURL)
@@ -134,6 +154,7 @@ func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *e
 		Server:              server,
 		ServerURL:           serverURL,
 		ServerWebSocketsURL: serverWebSocketsURL,
+		AdminURL:            adminURL,
 	}
 
 	mks := &mocksWrapper{
@@ -156,12 +177,18 @@ func getSequencerDefaultConfig() Config {
 		MaxLogsCount:                 10000,
 		MaxLogsBlockRange:            10000,
 		MaxNativeBlockHashBlockRange: 60000,
+		MaxTraceFilterBlockRange:     1000,
+		MaxEGPSimulationBlockRange:   1000,
 		WebSockets: WebSocketsConfig{
 			Enabled:   true,
 			Host:      "0.0.0.0",
 			Port:      9133,
 			ReadLimit: 0,
 		},
+		Admin: AdminConfig{
+			Host: "127.0.0.1",
+			Port: 9125,
+		},
 	}
 	return cfg
 }
@@ -169,6 +196,7 @@ func getSequencerDefaultConfig() Config {
 func getNonSequencerDefaultConfig(sequencerNodeURI string) Config {
 	cfg := getSequencerDefaultConfig()
 	cfg.Port = 9124
+	cfg.Admin.Port = 9126
 	cfg.SequencerNodeURI = sequencerNodeURI
 	return cfg
 }
@@ -207,6 +235,10 @@ func (s *mockedServer) JSONRPCCall(method string, parameters ...interface{}) (ty
 	return client.JSONRPCCall(s.ServerURL, method, parameters...)
 }
 
+func (s *mockedServer) AdminJSONRPCCall(method string, parameters ...interface{}) (types.Response, error) {
+	return client.JSONRPCCall(s.AdminURL, method, parameters...)
+}
+
 func (s *mockedServer) JSONRPCBatchCall(calls ...client.BatchCall) ([]types.Response, error) {
 	return client.JSONRPCBatchCall(s.ServerURL, calls...)
 }
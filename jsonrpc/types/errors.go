@@ -15,6 +15,8 @@ const (
 	InvalidParamsErrorCode = -32602
 	// ParserErrorCode error code for parsing errors
 	ParserErrorCode = -32700
+	// HistoryPrunedErrorCode error code for requests of data that has been pruned
+	HistoryPrunedErrorCode = 4444
 )
 
 var (
@@ -2,6 +2,7 @@ package types
 
 import (
 	"context"
+	"encoding/json"
 	"math/big"
 	"time"
 
@@ -17,15 +18,18 @@ import (
 type PoolInterface interface {
 	AddTx(ctx context.Context, tx types.Transaction, ip string) error
 	GetGasPrices(ctx context.Context) (pool.GasPrices, error)
+	GetGasPriceHistory(ctx context.Context, from, to time.Time, limit uint64) ([]pool.GasPriceHistoryEntry, error)
 	GetNonce(ctx context.Context, address common.Address) (uint64, error)
 	GetPendingTxHashesSince(ctx context.Context, since time.Time) ([]common.Hash, error)
 	GetPendingTxs(ctx context.Context, limit uint64) ([]pool.Transaction, error)
 	CountPendingTransactions(ctx context.Context) (uint64, error)
 	GetTransactionByHash(ctx context.Context, hash common.Hash) (*pool.Transaction, error)
 	GetTransactionByL2Hash(ctx context.Context, hash common.Hash) (*pool.Transaction, error)
-	CalculateEffectiveGasPrice(rawTx []byte, txGasPrice *big.Int, txGasUsed uint64, l1GasPrice uint64, l2GasPrice uint64) (*big.Int, error)
+	CalculateEffectiveGasPrice(rawTx []byte, txGasPrice *big.Int, txGasUsed uint64, l1GasPrice uint64, l1BlobBaseFee uint64, l2GasPrice uint64) (*big.Int, error)
 	CalculateEffectiveGasPricePercentage(gasPrice *big.Int, effectiveGasPrice *big.Int) (uint8, error)
 	EffectiveGasPriceEnabled() bool
+	PrivateMempoolEnabled() bool
+	PendingTxHashesExposed() bool
 }
 
 // StateInterface gathers the methods required to interact with the state.
@@ -33,6 +37,10 @@ type StateInterface interface {
 	StartToMonitorNewL2Blocks()
 	BeginStateTransaction(ctx context.Context) (pgx.Tx, error)
 	DebugTransaction(ctx context.Context, transactionHash common.Hash, traceConfig state.TraceConfig, dbTx pgx.Tx) (*runtime.ExecutionResult, error)
+	DebugUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, traceConfig state.TraceConfig, dbTx pgx.Tx) (*runtime.ExecutionResult, error)
+	FlatTraceTransaction(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) ([]json.RawMessage, error)
+	GetTraces(ctx context.Context, filter state.TraceFilter, dbTx pgx.Tx) ([]json.RawMessage, error)
+	GetLastIndexedTraceL2BlockNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
 	EstimateGas(transaction *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (uint64, []byte, error)
 	GetBalance(ctx context.Context, address common.Address, root common.Hash) (*big.Int, error)
 	GetCode(ctx context.Context, address common.Address, root common.Hash) ([]byte, error)
@@ -52,6 +60,7 @@ type StateInterface interface {
 	GetStorageAt(ctx context.Context, address common.Address, position *big.Int, root common.Hash) (*big.Int, error)
 	GetSyncingInfo(ctx context.Context, dbTx pgx.Tx) (state.SyncingInfo, error)
 	GetTransactionByHash(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*types.Transaction, error)
+	GetTransactionsEGPLogByL2BlockRange(ctx context.Context, fromBlockNumber, toBlockNumber uint64, dbTx pgx.Tx) ([]state.TransactionEGPLog, error)
 	GetTransactionByL2Hash(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*types.Transaction, error)
 	GetTransactionByL2BlockHashAndIndex(ctx context.Context, blockHash common.Hash, index uint64, dbTx pgx.Tx) (*types.Transaction, error)
 	GetTransactionByL2BlockNumberAndIndex(ctx context.Context, blockNumber uint64, index uint64, dbTx pgx.Tx) (*types.Transaction, error)
@@ -64,6 +73,7 @@ type StateInterface interface {
 	GetLastVerifiedBatch(ctx context.Context, dbTx pgx.Tx) (*state.VerifiedBatch, error)
 	GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
 	GetBatchByNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.Batch, error)
+	GetBatchWitness(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.BatchWitness, error)
 	GetTransactionsByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (txs []types.Transaction, effectivePercentages []uint8, err error)
 	GetVirtualBatch(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.VirtualBatch, error)
 	GetVerifiedBatch(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.VerifiedBatch, error)
@@ -77,10 +87,18 @@ type StateInterface interface {
 	GetLatestBatchGlobalExitRoot(ctx context.Context, dbTx pgx.Tx) (common.Hash, error)
 	GetL2TxHashByTxHash(ctx context.Context, hash common.Hash, dbTx pgx.Tx) (*common.Hash, error)
 	PreProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, sender common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (*state.ProcessBatchResponse, error)
+	GetLastBlock(ctx context.Context, dbTx pgx.Tx) (*state.Block, error)
+	GetLastTrustedForcedBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
+	GetForcedBatchesSince(ctx context.Context, forcedBatchNumber, maxBlockNumber uint64, dbTx pgx.Tx) ([]*state.ForcedBatch, error)
+	ScheduleSequencerHalt(ctx context.Context, batchNumber uint64, haltAt *time.Time, reason string, dbTx pgx.Tx) error
+	ResumeSequencer(ctx context.Context, reason string, dbTx pgx.Tx) (bool, error)
+	GetSequencerHalt(ctx context.Context, dbTx pgx.Tx) (*state.SequencerHalt, error)
+	GetForceBatchTimeout(ctx context.Context, dbTx pgx.Tx) (time.Duration, error)
 }
 
 // EthermanInterface provides integration with L1
 type EthermanInterface interface {
 	GetSafeBlockNumber(ctx context.Context) (uint64, error)
 	GetFinalizedBlockNumber(ctx context.Context) (uint64, error)
+	GetForceBatchTimeout() (time.Duration, error)
 }
@@ -7,8 +7,10 @@ import (
 	"math/big"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -265,6 +267,11 @@ type Block struct {
 
 // NewBlock creates a Block instance
 func NewBlock(ctx context.Context, st StateInterface, hash *common.Hash, b *state.L2Block, receipts []types.Receipt, fullTx, includeReceipts bool, includeExtraInfo *bool, dbTx pgx.Tx) (*Block, error) {
+	// Only the hashes of the transactions are kept once their bodies have been pruned
+	if fullTx && len(b.PrunedTxHashes) > 0 {
+		return nil, state.ErrHistoryPruned
+	}
+
 	h := b.Header()
 
 	n := big.NewInt(0).SetUint64(h.Nonce.Uint64())
@@ -345,6 +352,11 @@ func NewBlock(ctx context.Context, st StateInterface, hash *common.Hash, b *stat
 		}
 	}
 
+	for _, txHash := range b.PrunedTxHashes {
+		h := txHash
+		res.Transactions = append(res.Transactions, TransactionOrHash{Hash: &h})
+	}
+
 	for _, uncle := range b.Uncles() {
 		res.Uncles = append(res.Uncles, uncle.Hash())
 	}
@@ -707,6 +719,212 @@ type ExitRoots struct {
 	RollupExitRoot  common.Hash `json:"rollupExitRoot"`
 }
 
+// GasPriceHistoryEntry is an update of the gas prices with the inputs used to calculate them
+type GasPriceHistoryEntry struct {
+	Timestamp        ArgUint64 `json:"timestamp"`
+	L2GasPrice       ArgUint64 `json:"l2GasPrice"`
+	L1GasPrice       ArgUint64 `json:"l1GasPrice"`
+	L1BlobBaseFee    ArgUint64 `json:"l1BlobBaseFee"`
+	Pricer           string    `json:"pricer"`
+	L1GasPriceSource string    `json:"l1GasPriceSource"`
+	L1CostModel      string    `json:"l1CostModel,omitempty"`
+	Factor           float64   `json:"factor"`
+	Congestion       float64   `json:"congestion,omitempty"`
+	RawL2GasPrice    ArgUint64 `json:"rawL2GasPrice"`
+	Clamp            string    `json:"clamp,omitempty"`
+	Truncated        bool      `json:"truncated"`
+}
+
+// NewGasPriceHistoryEntry creates a GasPriceHistoryEntry from an update of the gas prices of the pool
+func NewGasPriceHistoryEntry(entry pool.GasPriceHistoryEntry) GasPriceHistoryEntry {
+	return GasPriceHistoryEntry{
+		Timestamp:        ArgUint64(entry.Timestamp.Unix()),
+		L2GasPrice:       ArgUint64(entry.L2GasPrice),
+		L1GasPrice:       ArgUint64(entry.L1GasPrice),
+		L1BlobBaseFee:    ArgUint64(entry.L1BlobBaseFee),
+		Pricer:           entry.Inputs.Pricer,
+		L1GasPriceSource: entry.Inputs.L1GasPriceSource,
+		L1CostModel:      entry.Inputs.L1CostModel,
+		Factor:           entry.Inputs.Factor,
+		Congestion:       entry.Inputs.Congestion,
+		RawL2GasPrice:    ArgUint64(entry.Inputs.RawL2GasPrice),
+		Clamp:            entry.Inputs.Clamp,
+		Truncated:        entry.Inputs.Truncated,
+	}
+}
+
+// PendingForcedBatch is a forced batch stored on L1 that is pending to be included in a trusted batch
+type PendingForcedBatch struct {
+	ForcedBatchNumber ArgUint64      `json:"forcedBatchNumber"`
+	BlockNumber       ArgUint64      `json:"blockNumber"`
+	Sequencer         common.Address `json:"sequencer"`
+	GlobalExitRoot    common.Hash    `json:"globalExitRoot"`
+	ForcedAt          ArgUint64      `json:"forcedAt"`
+	Deadline          ArgUint64      `json:"deadline"`
+	Expired           bool           `json:"expired"`
+}
+
+// NewPendingForcedBatch creates a PendingForcedBatch from a forced batch of the state and the force batch
+// timeout of the rollup contract, after which anyone can sequence the forced batch on L1
+func NewPendingForcedBatch(forcedBatch state.ForcedBatch, timeout time.Duration, now time.Time) PendingForcedBatch {
+	deadline := forcedBatch.ForcedAt.Add(timeout)
+	return PendingForcedBatch{
+		ForcedBatchNumber: ArgUint64(forcedBatch.ForcedBatchNumber),
+		BlockNumber:       ArgUint64(forcedBatch.BlockNumber),
+		Sequencer:         forcedBatch.Sequencer,
+		GlobalExitRoot:    forcedBatch.GlobalExitRoot,
+		ForcedAt:          ArgUint64(forcedBatch.ForcedAt.Unix()),
+		Deadline:          ArgUint64(deadline.Unix()),
+		Expired:           !now.Before(deadline),
+	}
+}
+
+// EGPSimulationParams are the parameters of the effective gas price used to simulate it. The
+// parameters not provided keep the value of the configuration of the node
+type EGPSimulationParams struct {
+	Enabled                     *bool      `json:"enabled"`
+	L1GasPriceFactor            *float64   `json:"l1GasPriceFactor"`
+	ByteGasCost                 *ArgUint64 `json:"byteGasCost"`
+	ZeroByteGasCost             *ArgUint64 `json:"zeroByteGasCost"`
+	NetProfit                   *float64   `json:"netProfit"`
+	FinalDeviationPct           *ArgUint64 `json:"finalDeviationPct"`
+	EthTransferGasPrice         *ArgUint64 `json:"ethTransferGasPrice"`
+	EthTransferL1GasPriceFactor *float64   `json:"ethTransferL1GasPriceFactor"`
+	L2GasPriceSuggesterFactor   *float64   `json:"l2GasPriceSuggesterFactor"`
+	L1CostModel                 *string    `json:"l1CostModel"`
+	BlobBytesPerBatch           *ArgUint64 `json:"blobBytesPerBatch"`
+	BlobWeight                  *float64   `json:"blobWeight"`
+}
+
+// Apply returns the configuration of the effective gas price with the parameters overridden
+func (p EGPSimulationParams) Apply(cfg pool.EffectiveGasPriceCfg) pool.EffectiveGasPriceCfg {
+	if p.Enabled != nil {
+		cfg.Enabled = *p.Enabled
+	}
+	if p.L1GasPriceFactor != nil {
+		cfg.L1GasPriceFactor = *p.L1GasPriceFactor
+	}
+	if p.ByteGasCost != nil {
+		cfg.ByteGasCost = uint64(*p.ByteGasCost)
+	}
+	if p.ZeroByteGasCost != nil {
+		cfg.ZeroByteGasCost = uint64(*p.ZeroByteGasCost)
+	}
+	if p.NetProfit != nil {
+		cfg.NetProfit = *p.NetProfit
+	}
+	if p.FinalDeviationPct != nil {
+		cfg.FinalDeviationPct = uint64(*p.FinalDeviationPct)
+	}
+	if p.EthTransferGasPrice != nil {
+		cfg.EthTransferGasPrice = uint64(*p.EthTransferGasPrice)
+	}
+	if p.EthTransferL1GasPriceFactor != nil {
+		cfg.EthTransferL1GasPriceFactor = *p.EthTransferL1GasPriceFactor
+	}
+	if p.L2GasPriceSuggesterFactor != nil {
+		cfg.L2GasPriceSuggesterFactor = *p.L2GasPriceSuggesterFactor
+	}
+	if p.L1CostModel != nil {
+		cfg.L1CostModel.Type = pool.L1CostModelType(*p.L1CostModel)
+	}
+	if p.BlobBytesPerBatch != nil {
+		cfg.L1CostModel.BlobBytesPerBatch = uint64(*p.BlobBytesPerBatch)
+	}
+	if p.BlobWeight != nil {
+		cfg.L1CostModel.BlobWeight = *p.BlobWeight
+	}
+	return cfg
+}
+
+// EGPStats are the statistics of the effective gas price of a set of txs
+type EGPStats struct {
+	Txs              ArgUint64 `json:"txs"`
+	NoInfo           ArgUint64 `json:"noInfo"`
+	Errors           ArgUint64 `json:"errors"`
+	Enabled          ArgUint64 `json:"enabled"`
+	Reprocessed      ArgUint64 `json:"reprocessed"`
+	Suspicious       ArgUint64 `json:"suspicious"`
+	UsedFirst        ArgUint64 `json:"usedFirst"`
+	UsedSecond       ArgUint64 `json:"usedSecond"`
+	UsedUser         ArgUint64 `json:"usedUser"`
+	UsedOther        ArgUint64 `json:"usedOther"`
+	Losses           ArgUint64 `json:"losses"`
+	Loss             ArgBig    `json:"loss"`
+	Fee              ArgBig    `json:"fee"`
+	EffectiveFee     ArgBig    `json:"effectiveFee"`
+	ReprocessedRatio float64   `json:"reprocessedRatio"`
+	ProfitMargin     float64   `json:"profitMargin"`
+}
+
+// NewEGPStats creates EGPStats from the statistics of the effective gas price of the pool
+func NewEGPStats(stats pool.EGPStats) EGPStats {
+	// the copy has the amounts initialized even if there are no txs
+	stats = stats.Copy()
+	return EGPStats{
+		Txs:              ArgUint64(stats.Txs),
+		NoInfo:           ArgUint64(stats.NoInfo),
+		Errors:           ArgUint64(stats.Errors),
+		Enabled:          ArgUint64(stats.Enabled),
+		Reprocessed:      ArgUint64(stats.Reprocessed),
+		Suspicious:       ArgUint64(stats.Suspicious),
+		UsedFirst:        ArgUint64(stats.UsedFirst),
+		UsedSecond:       ArgUint64(stats.UsedSecond),
+		UsedUser:         ArgUint64(stats.UsedUser),
+		UsedOther:        ArgUint64(stats.UsedOther),
+		Losses:           ArgUint64(stats.Losses),
+		Loss:             ArgBig(*stats.Loss),
+		Fee:              ArgBig(*stats.Fee),
+		EffectiveFee:     ArgBig(*stats.EffectiveFee),
+		ReprocessedRatio: stats.Ratio(stats.Reprocessed),
+		ProfitMargin:     stats.ProfitMargin(),
+	}
+}
+
+// EGPSimulationResult contains the statistics of the effective gas price of the txs of a range of
+// L2 blocks, as they were processed and as they would be processed with the simulated parameters
+type EGPSimulationResult struct {
+	FromBlock ArgUint64 `json:"fromBlock"`
+	ToBlock   ArgUint64 `json:"toBlock"`
+	Real      EGPStats  `json:"real"`
+	Simulated EGPStats  `json:"simulated"`
+}
+
+// SequencerHaltParams are the parameters to schedule a halt of the sequencer for maintenance. The sequencer
+// halts before opening the batch BatchNumber or at the unix Timestamp, whatever happens first
+type SequencerHaltParams struct {
+	BatchNumber *ArgUint64 `json:"batchNumber"`
+	Timestamp   *ArgUint64 `json:"timestamp"`
+	Reason      string     `json:"reason"`
+}
+
+// SequencerHalt is the halt of the sequencer scheduled for maintenance
+type SequencerHalt struct {
+	BatchNumber *ArgUint64 `json:"batchNumber"`
+	Timestamp   *ArgUint64 `json:"timestamp"`
+	Status      string     `json:"status"`
+	Reason      string     `json:"reason"`
+	UpdatedAt   ArgUint64  `json:"updatedAt"`
+}
+
+// NewSequencerHalt creates a SequencerHalt instance
+func NewSequencerHalt(halt state.SequencerHalt) SequencerHalt {
+	res := SequencerHalt{
+		Status:    string(halt.Status),
+		Reason:    halt.Reason,
+		UpdatedAt: ArgUint64(halt.UpdatedAt.Unix()),
+	}
+	if halt.BatchNumber != 0 {
+		batchNumber := ArgUint64(halt.BatchNumber)
+		res.BatchNumber = &batchNumber
+	}
+	if halt.HaltAt != nil {
+		timestamp := ArgUint64(halt.HaltAt.Unix())
+		res.Timestamp = &timestamp
+	}
+	return res
+}
+
 // ZKCounters counters for the tx
 type ZKCounters struct {
 	GasUsed              ArgUint64 `json:"gasUsed"`
@@ -746,6 +964,7 @@ type ZKCountersResponse struct {
 	CountersLimits ZKCountersLimits `json:"countersLimit"`
 	Revert         *RevertInfo      `json:"revert,omitempty"`
 	OOCError       *string          `json:"oocError,omitempty"`
+	Trace          json.RawMessage  `json:"trace,omitempty"`
 }
 
 // NewZKCountersResponse creates an instance of ZKCounters to be returned
@@ -773,3 +992,85 @@ func NewZKCountersResponse(zkCounters state.ZKCounters, limits ZKCountersLimits,
 		OOCError:       oocErrMsg,
 	}
 }
+
+// BatchWitness is the witness of a closed batch, with its inputs and the partial state tree
+// needed to execute it without the state
+type BatchWitness struct {
+	BatchNumber       ArgUint64                         `json:"batchNumber"`
+	ChainID           ArgUint64                         `json:"chainId"`
+	ForkID            ArgUint64                         `json:"forkId"`
+	OldStateRoot      common.Hash                       `json:"oldStateRoot"`
+	NewStateRoot      common.Hash                       `json:"newStateRoot"`
+	OldAccInputHash   common.Hash                       `json:"oldAccInputHash"`
+	Coinbase          common.Address                    `json:"coinbase"`
+	BatchL2Data       ArgBytes                          `json:"batchL2Data"`
+	L1InfoRoot        common.Hash                       `json:"l1InfoRoot"`
+	L1InfoTreeData    map[uint32]BatchWitnessL1InfoData `json:"l1InfoTreeData"`
+	TimestampLimit    ArgUint64                         `json:"timestampLimit"`
+	ForcedBlockHashL1 common.Hash                       `json:"forcedBlockHashL1"`
+	Db                map[string]string                 `json:"db"`
+	ContractsBytecode map[string]string                 `json:"contractsBytecode"`
+}
+
+// BatchWitnessL1InfoData is the data of a leaf of the L1 info tree used by the batch of a witness
+type BatchWitnessL1InfoData struct {
+	GlobalExitRoot common.Hash `json:"globalExitRoot"`
+	BlockHashL1    common.Hash `json:"blockHashL1"`
+	MinTimestamp   ArgUint64   `json:"minTimestamp"`
+}
+
+// NewBatchWitness creates a BatchWitness from the witness of a batch of the state
+func NewBatchWitness(witness *state.BatchWitness) *BatchWitness {
+	res := &BatchWitness{
+		BatchNumber:       ArgUint64(witness.BatchNumber),
+		ChainID:           ArgUint64(witness.ChainID),
+		ForkID:            ArgUint64(witness.ForkID),
+		OldStateRoot:      witness.OldStateRoot,
+		NewStateRoot:      witness.NewStateRoot,
+		OldAccInputHash:   witness.OldAccInputHash,
+		Coinbase:          witness.Coinbase,
+		BatchL2Data:       witness.BatchL2Data,
+		L1InfoRoot:        witness.L1InfoRoot,
+		L1InfoTreeData:    make(map[uint32]BatchWitnessL1InfoData, len(witness.L1InfoTreeData)),
+		TimestampLimit:    ArgUint64(witness.TimestampLimit),
+		ForcedBlockHashL1: witness.ForcedBlockHashL1,
+		Db:                witness.Db,
+		ContractsBytecode: witness.ContractsBytecode,
+	}
+	for i, data := range witness.L1InfoTreeData {
+		res.L1InfoTreeData[i] = BatchWitnessL1InfoData{
+			GlobalExitRoot: data.GlobalExitRoot,
+			BlockHashL1:    data.BlockHashL1,
+			MinTimestamp:   ArgUint64(data.MinTimestamp),
+		}
+	}
+	return res
+}
+
+// ToState returns the witness of the batch used by the state to execute it
+func (w *BatchWitness) ToState() *state.BatchWitness {
+	res := &state.BatchWitness{
+		BatchNumber:       uint64(w.BatchNumber),
+		ChainID:           uint64(w.ChainID),
+		ForkID:            uint64(w.ForkID),
+		OldStateRoot:      w.OldStateRoot,
+		NewStateRoot:      w.NewStateRoot,
+		OldAccInputHash:   w.OldAccInputHash,
+		Coinbase:          w.Coinbase,
+		BatchL2Data:       w.BatchL2Data,
+		L1InfoRoot:        w.L1InfoRoot,
+		L1InfoTreeData:    make(map[uint32]state.L1DataV2, len(w.L1InfoTreeData)),
+		TimestampLimit:    uint64(w.TimestampLimit),
+		ForcedBlockHashL1: w.ForcedBlockHashL1,
+		Db:                w.Db,
+		ContractsBytecode: w.ContractsBytecode,
+	}
+	for i, data := range w.L1InfoTreeData {
+		res.L1InfoTreeData[i] = state.L1DataV2{
+			GlobalExitRoot: data.GlobalExitRoot,
+			BlockHashL1:    data.BlockHashL1,
+			MinTimestamp:   uint64(data.MinTimestamp),
+		}
+	}
+	return res
+}
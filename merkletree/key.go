@@ -19,15 +19,20 @@ const (
 
 // keyEthAddr is the common code for all the keys related to ethereum addresses.
 func keyEthAddr(ethAddr common.Address, leafType leafType, key1Capacity [4]uint64) ([]byte, error) {
-	ethAddrBI := new(big.Int).SetBytes(ethAddr.Bytes())
-	ethAddrArr := scalar2fea(ethAddrBI)
+	return keyScalar(new(big.Int).SetBytes(ethAddr.Bytes()), leafType, key1Capacity)
+}
+
+// keyScalar is the common code for all the keys whose first 160 bits are a scalar, like an
+// address or an index of the block info tree.
+func keyScalar(value *big.Int, leafType leafType, key1Capacity [4]uint64) ([]byte, error) {
+	valueArr := scalar2fea(value)
 
 	key1 := [8]uint64{
-		ethAddrArr[0],
-		ethAddrArr[1],
-		ethAddrArr[2],
-		ethAddrArr[3],
-		ethAddrArr[4],
+		valueArr[0],
+		valueArr[1],
+		valueArr[2],
+		valueArr[3],
+		valueArr[4],
 		0,
 		uint64(leafType),
 		0,
@@ -199,3 +204,86 @@ func KeyCodeLength(ethAddr common.Address) ([]byte, error) {
 
 	return keyEthAddr(ethAddr, LeafTypeSCLength, capIn)
 }
+
+// KeyBlockHeaderParams returns the key of a param of the header of a L2 block in the block info tree:
+// hk0: H([0, 0, 0, 0, 0, 0, 0, 0], [0, 0, 0, 0])
+// key: H([param[0:4], param[4:8], param[8:12], param[12:16], param[16:20], 0, 7, 0], [hk0[0], hk0[1], hk0[2], hk0[3]]
+func KeyBlockHeaderParams(param *big.Int) ([]byte, error) {
+	capIn, err := defaultCapIn()
+	if err != nil {
+		return nil, err
+	}
+
+	return keyScalar(param, LeafTypeBlockHeader, capIn)
+}
+
+// KeyTxHash returns the key of the L2 hash of a tx in the block info tree:
+// hk0: H([0, 0, 0, 0, 0, 0, 0, 0], [0, 0, 0, 0])
+// key: H([txIndex[0:4], txIndex[4:8], txIndex[8:12], txIndex[12:16], txIndex[16:20], 0, 8, 0], [hk0[0], hk0[1], hk0[2], hk0[3]]
+func KeyTxHash(txIndex *big.Int) ([]byte, error) {
+	capIn, err := defaultCapIn()
+	if err != nil {
+		return nil, err
+	}
+
+	return keyScalar(txIndex, LeafTypeTxHash, capIn)
+}
+
+// KeyTxStatus returns the key of the status of a tx in the block info tree:
+// hk0: H([0, 0, 0, 0, 0, 0, 0, 0], [0, 0, 0, 0])
+// key: H([txIndex[0:4], txIndex[4:8], txIndex[8:12], txIndex[12:16], txIndex[16:20], 0, 9, 0], [hk0[0], hk0[1], hk0[2], hk0[3]]
+func KeyTxStatus(txIndex *big.Int) ([]byte, error) {
+	capIn, err := defaultCapIn()
+	if err != nil {
+		return nil, err
+	}
+
+	return keyScalar(txIndex, LeafTypeTxStatus, capIn)
+}
+
+// KeyTxCumulativeGasUsed returns the key of the cumulative gas used by a tx in the block info tree:
+// hk0: H([0, 0, 0, 0, 0, 0, 0, 0], [0, 0, 0, 0])
+// key: H([txIndex[0:4], txIndex[4:8], txIndex[8:12], txIndex[12:16], txIndex[16:20], 0, 10, 0], [hk0[0], hk0[1], hk0[2], hk0[3]]
+func KeyTxCumulativeGasUsed(txIndex *big.Int) ([]byte, error) {
+	capIn, err := defaultCapIn()
+	if err != nil {
+		return nil, err
+	}
+
+	return keyScalar(txIndex, LeafTypeTxCumulativeGasUsed, capIn)
+}
+
+// KeyTxLogs returns the key of a log of a tx in the block info tree:
+// hk0: H([logIndex[0:4], logIndex[4:8], logIndex[8:12], logIndex[12:16], logIndex[16:20], logIndex[20:24], logIndex[24:28], logIndex[28:32], [0, 0, 0, 0])
+// key: H([txIndex[0:4], txIndex[4:8], txIndex[8:12], txIndex[12:16], txIndex[16:20], 0, 11, 0], [hk0[0], hk0[1], hk0[2], hk0[3]]
+func KeyTxLogs(txIndex *big.Int, logIndex *big.Int) ([]byte, error) {
+	logIndexArr := scalar2fea(logIndex)
+
+	hk0, err := poseidon.Hash([8]uint64{
+		logIndexArr[0],
+		logIndexArr[1],
+		logIndexArr[2],
+		logIndexArr[3],
+		logIndexArr[4],
+		logIndexArr[5],
+		logIndexArr[6],
+		logIndexArr[7],
+	}, [4]uint64{})
+	if err != nil {
+		return nil, err
+	}
+
+	return keyScalar(txIndex, LeafTypeTxLog, hk0)
+}
+
+// KeyTxEffectivePercentage returns the key of the effective percentage of a tx in the block info tree:
+// hk0: H([0, 0, 0, 0, 0, 0, 0, 0], [0, 0, 0, 0])
+// key: H([txIndex[0:4], txIndex[4:8], txIndex[8:12], txIndex[12:16], txIndex[16:20], 0, 12, 0], [hk0[0], hk0[1], hk0[2], hk0[3]]
+func KeyTxEffectivePercentage(txIndex *big.Int) ([]byte, error) {
+	capIn, err := defaultCapIn()
+	if err != nil {
+		return nil, err
+	}
+
+	return keyScalar(txIndex, LeafTypeTxEffectivePercentage, capIn)
+}
@@ -14,4 +14,16 @@ const (
 	LeafTypeStorage leafType = 3
 	// LeafTypeSCLength specifies that leaf stores Storage Value
 	LeafTypeSCLength leafType = 4
+	// LeafTypeBlockHeader specifies that leaf stores a param of the header of a L2 block
+	LeafTypeBlockHeader leafType = 7
+	// LeafTypeTxHash specifies that leaf stores the L2 hash of a tx of a L2 block
+	LeafTypeTxHash leafType = 8
+	// LeafTypeTxStatus specifies that leaf stores the status of a tx of a L2 block
+	LeafTypeTxStatus leafType = 9
+	// LeafTypeTxCumulativeGasUsed specifies that leaf stores the cumulative gas used by a tx of a L2 block
+	LeafTypeTxCumulativeGasUsed leafType = 10
+	// LeafTypeTxLog specifies that leaf stores the hash of a log of a tx of a L2 block
+	LeafTypeTxLog leafType = 11
+	// LeafTypeTxEffectivePercentage specifies that leaf stores the effective percentage of a tx of a L2 block
+	LeafTypeTxEffectivePercentage leafType = 12
 )
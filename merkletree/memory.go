@@ -0,0 +1,506 @@
+package merkletree
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree/hashdb"
+	poseidon "github.com/iden3/go-iden3-crypto/goldenposeidon"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// maxLevel is the depth of the sparse merkle tree, one level per bit of the key
+const maxLevel = 256
+
+// memoryNode is a node of the in memory tree. Like in the prover, leaves store the remaining key,
+// the bits of the key not used by the path to reach them
+type memoryNode struct {
+	leaf     bool
+	rKey     [4]uint64
+	value    [8]uint64
+	children [2][4]uint64
+}
+
+// MemoryHashDB is an in memory implementation of the HashDB service of the prover, hashing the
+// nodes like the prover so it computes the same state roots. Nodes are never deleted, so all the
+// roots returned by Set can be read afterwards. It's meant for tests and the reference executor,
+// the tree is lost when the process stops
+type MemoryHashDB struct {
+	mutex      sync.RWMutex
+	nodes      map[[4]uint64]*memoryNode
+	programs   map[[4]uint64][]byte
+	latestRoot [4]uint64
+}
+
+// NewMemoryHashDB creates an empty in memory HashDB
+func NewMemoryHashDB() *MemoryHashDB {
+	return &MemoryHashDB{
+		nodes:    map[[4]uint64]*memoryNode{},
+		programs: map[[4]uint64][]byte{},
+	}
+}
+
+// GetLatestStateRoot returns the last root returned by Set
+func (db *MemoryHashDB) GetLatestStateRoot(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*hashdb.GetLatestStateRootResponse, error) {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+	return &hashdb.GetLatestStateRootResponse{LatestRoot: feaFromH4(db.latestRoot), Result: successResult()}, nil
+}
+
+// Set sets the value of a key in the tree with the given root
+func (db *MemoryHashDB) Set(ctx context.Context, in *hashdb.SetRequest, opts ...grpc.CallOption) (*hashdb.SetResponse, error) {
+	value := [8]uint64{}
+	if in.Value != "" {
+		fea, err := string2fea(in.Value)
+		if err != nil {
+			return nil, err
+		}
+		copy(value[:], fea)
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	newRoot, err := db.set(h4FromFea(in.OldRoot), h4FromFea(in.Key), value, 0)
+	if err != nil {
+		return nil, err
+	}
+	db.latestRoot = newRoot
+	return &hashdb.SetResponse{
+		OldRoot:  in.OldRoot,
+		NewRoot:  feaFromH4(newRoot),
+		Key:      in.Key,
+		NewValue: in.Value,
+		Result:   successResult(),
+	}, nil
+}
+
+// Get returns the value of a key in the tree with the given root, zero if it's not set
+func (db *MemoryHashDB) Get(ctx context.Context, in *hashdb.GetRequest, opts ...grpc.CallOption) (*hashdb.GetResponse, error) {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+	value, err := db.get(h4FromFea(in.Root), h4FromFea(in.Key))
+	if err != nil {
+		return nil, err
+	}
+	return &hashdb.GetResponse{
+		Root:   in.Root,
+		Key:    in.Key,
+		Value:  hex.EncodeToString(ScalarToFilledByteSlice(fea2scalar(value[:]))),
+		Result: successResult(),
+	}, nil
+}
+
+// SetProgram stores a bytecode by its hash
+func (db *MemoryHashDB) SetProgram(ctx context.Context, in *hashdb.SetProgramRequest, opts ...grpc.CallOption) (*hashdb.SetProgramResponse, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	db.programs[h4FromFea(in.Key)] = append([]byte{}, in.Data...)
+	return &hashdb.SetProgramResponse{Result: successResult()}, nil
+}
+
+// GetProgram returns a bytecode by its hash
+func (db *MemoryHashDB) GetProgram(ctx context.Context, in *hashdb.GetProgramRequest, opts ...grpc.CallOption) (*hashdb.GetProgramResponse, error) {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+	data, found := db.programs[h4FromFea(in.Key)]
+	if !found {
+		return &hashdb.GetProgramResponse{Result: &hashdb.ResultCode{Code: hashdb.ResultCode_CODE_DB_KEY_NOT_FOUND}}, nil
+	}
+	return &hashdb.GetProgramResponse{Data: data, Result: successResult()}, nil
+}
+
+// LoadDB stores the nodes of a partial tree indexed by their hash, like the ones returned by
+// ReadTree, checking that each node hashes to its index. The value of each leaf is read from its
+// value node, so both must be loaded together
+func (db *MemoryHashDB) LoadDB(ctx context.Context, in *hashdb.LoadDBRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	nodes := make(map[[4]uint64][12]uint64, len(in.InputDb))
+	for key, fe := range in.InputDb {
+		h4, err := StringToh4(key)
+		if err != nil {
+			return nil, err
+		}
+		if fe == nil || len(fe.Fe) != 12 { //nolint:gomnd
+			return nil, fmt.Errorf("node %s doesn't have 12 field elements", key)
+		}
+		v := [12]uint64{}
+		copy(v[:], fe.Fe)
+		hash, err := poseidon.Hash([8]uint64{v[0], v[1], v[2], v[3], v[4], v[5], v[6], v[7]}, [4]uint64{v[8], v[9], v[10], v[11]})
+		if err != nil {
+			return nil, err
+		}
+		if hash != [4]uint64{h4[0], h4[1], h4[2], h4[3]} {
+			return nil, fmt.Errorf("node %s doesn't match its hash %s", key, H4ToString(hash[:]))
+		}
+		nodes[hash] = v
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	for hash, v := range nodes {
+		if _, found := db.nodes[hash]; found {
+			continue
+		}
+		// the leaves are hashed with capacity [1, 0, 0, 0], the intermediate and value nodes with zeros
+		if v[8] == 1 {
+			valueHash := [4]uint64{v[4], v[5], v[6], v[7]}
+			value, found := nodes[valueHash]
+			if !found {
+				return nil, fmt.Errorf("value %s of leaf %s not found", H4ToString(valueHash[:]), H4ToString(hash[:]))
+			}
+			db.nodes[hash] = &memoryNode{
+				leaf:  true,
+				rKey:  [4]uint64{v[0], v[1], v[2], v[3]},
+				value: [8]uint64{value[0], value[1], value[2], value[3], value[4], value[5], value[6], value[7]},
+			}
+			continue
+		}
+		db.nodes[hash] = &memoryNode{children: [2][4]uint64{{v[0], v[1], v[2], v[3]}, {v[4], v[5], v[6], v[7]}}}
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// LoadProgramDB stores the bytecodes indexed by their hash, checking that each bytecode hashes
+// to its index
+func (db *MemoryHashDB) LoadProgramDB(ctx context.Context, in *hashdb.LoadProgramDBRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	programs := make(map[[4]uint64][]byte, len(in.InputProgramDb))
+	for key, data := range in.InputProgramDb {
+		b, err := hex.DecodeHex(key)
+		if err != nil {
+			return nil, err
+		}
+		code := append([]byte{}, data...)
+		hash, err := HashContractBytecode(code)
+		if err != nil {
+			return nil, err
+		}
+		h4 := h4FromBytes(b)
+		if h4 != [4]uint64{hash[0], hash[1], hash[2], hash[3]} {
+			return nil, fmt.Errorf("bytecode %s doesn't match its hash %s", key, H4ToString(hash))
+		}
+		programs[h4] = code
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	for hash, data := range programs {
+		db.programs[hash] = data
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// FinishTx does nothing, the changes are applied by Set
+func (db *MemoryHashDB) FinishTx(ctx context.Context, in *hashdb.FinishTxRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+// StartBlock does nothing, the changes are applied by Set
+func (db *MemoryHashDB) StartBlock(ctx context.Context, in *hashdb.StartBlockRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+// FinishBlock does nothing, the changes are applied by Set
+func (db *MemoryHashDB) FinishBlock(ctx context.Context, in *hashdb.FinishBlockRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+// Flush does nothing, there is no persistent storage
+func (db *MemoryHashDB) Flush(ctx context.Context, in *hashdb.FlushRequest, opts ...grpc.CallOption) (*hashdb.FlushResponse, error) {
+	return &hashdb.FlushResponse{Result: successResult()}, nil
+}
+
+// GetFlushStatus returns an empty status, there is no persistent storage
+func (db *MemoryHashDB) GetFlushStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*hashdb.GetFlushStatusResponse, error) {
+	return &hashdb.GetFlushStatusResponse{}, nil
+}
+
+// GetFlushData returns no data, there is no persistent storage
+func (db *MemoryHashDB) GetFlushData(ctx context.Context, in *hashdb.GetFlushDataRequest, opts ...grpc.CallOption) (*hashdb.GetFlushDataResponse, error) {
+	return &hashdb.GetFlushDataResponse{Result: successResult()}, nil
+}
+
+// ConsolidateState is not supported
+func (db *MemoryHashDB) ConsolidateState(ctx context.Context, in *hashdb.ConsolidateStateRequest, opts ...grpc.CallOption) (*hashdb.ConsolidateStateResponse, error) {
+	return nil, fmt.Errorf("ConsolidateState is not supported by the in memory hashdb")
+}
+
+// Purge is not supported
+func (db *MemoryHashDB) Purge(ctx context.Context, in *hashdb.PurgeRequest, opts ...grpc.CallOption) (*hashdb.PurgeResponse, error) {
+	return nil, fmt.Errorf("Purge is not supported by the in memory hashdb")
+}
+
+// ReadTree returns the values of the keys in the tree with the given root and all the nodes read
+// to get them, including the values of the leaves stored by their hash like the prover does
+func (db *MemoryHashDB) ReadTree(ctx context.Context, in *hashdb.ReadTreeRequest, opts ...grpc.CallOption) (*hashdb.ReadTreeResponse, error) {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	res := &hashdb.ReadTreeResponse{Result: successResult()}
+	read := map[[4]uint64]bool{}
+	addNode := func(hash [4]uint64, value [12]uint64) {
+		if read[hash] {
+			return
+		}
+		read[hash] = true
+		res.HashValue = append(res.HashValue, &hashdb.HashValueGL{Hash: feaFromH4(hash), Value: &hashdb.Fea12{
+			Fe0: value[0], Fe1: value[1], Fe2: value[2], Fe3: value[3], Fe4: value[4], Fe5: value[5],
+			Fe6: value[6], Fe7: value[7], Fe8: value[8], Fe9: value[9], Fe10: value[10], Fe11: value[11],
+		}})
+	}
+
+	root := h4FromFea(in.StateRoot)
+	for _, fea := range in.Keys {
+		key := h4FromFea(fea)
+		value := [8]uint64{}
+		hash := root
+		for level := 0; level < maxLevel && hash != ([4]uint64{}); level++ {
+			node, found := db.nodes[hash]
+			if !found {
+				return nil, fmt.Errorf("node %s not found", H4ToString(hash[:]))
+			}
+			if node.leaf {
+				valueHash, err := poseidon.Hash(node.value, [4]uint64{})
+				if err != nil {
+					return nil, err
+				}
+				r, v := node.rKey, node.value
+				addNode(hash, [12]uint64{r[0], r[1], r[2], r[3], valueHash[0], valueHash[1], valueHash[2], valueHash[3], 1})
+				addNode(valueHash, [12]uint64{v[0], v[1], v[2], v[3], v[4], v[5], v[6], v[7]})
+				if node.rKey == remainingKey(key, level) {
+					value = node.value
+				}
+				break
+			}
+			l, r := node.children[0], node.children[1]
+			addNode(hash, [12]uint64{l[0], l[1], l[2], l[3], r[0], r[1], r[2], r[3]})
+			hash = node.children[keyBit(key, level)]
+		}
+		res.KeyValue = append(res.KeyValue, &hashdb.KeyValue{
+			Key:   fea,
+			Value: hex.EncodeToString(ScalarToFilledByteSlice(fea2scalar(value[:]))),
+		})
+	}
+	return res, nil
+}
+
+// CancelBatch does nothing, the changes are applied by Set
+func (db *MemoryHashDB) CancelBatch(ctx context.Context, in *hashdb.CancelBatchRequest, opts ...grpc.CallOption) (*hashdb.CancelBatchResponse, error) {
+	return &hashdb.CancelBatchResponse{Result: successResult()}, nil
+}
+
+// ResetDB removes all the nodes and programs
+func (db *MemoryHashDB) ResetDB(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*hashdb.ResetDBResponse, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	db.nodes = map[[4]uint64]*memoryNode{}
+	db.programs = map[[4]uint64][]byte{}
+	db.latestRoot = [4]uint64{}
+	return &hashdb.ResetDBResponse{Result: successResult()}, nil
+}
+
+// SetValue sets the value of a key in the tree with the given root and returns the new root
+func (db *MemoryHashDB) SetValue(root []byte, key []byte, value *big.Int) ([]byte, error) {
+	v := [8]uint64{}
+	copy(v[:], scalar2fea(value))
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	newRoot, err := db.set(h4FromBytes(root), h4FromBytes(key), v, 0)
+	if err != nil {
+		return nil, err
+	}
+	return h4ToFilledByteSlice(newRoot[:]), nil
+}
+
+// GetValue returns the value of a key in the tree with the given root
+func (db *MemoryHashDB) GetValue(root []byte, key []byte) (*big.Int, error) {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+	value, err := db.get(h4FromBytes(root), h4FromBytes(key))
+	if err != nil {
+		return nil, err
+	}
+	return fea2scalar(value[:]), nil
+}
+
+func (db *MemoryHashDB) get(root, key [4]uint64) ([8]uint64, error) {
+	for level := 0; level < maxLevel && root != ([4]uint64{}); level++ {
+		node, found := db.nodes[root]
+		if !found {
+			return [8]uint64{}, fmt.Errorf("node %s not found", H4ToString(root[:]))
+		}
+		if node.leaf {
+			if node.rKey == remainingKey(key, level) {
+				return node.value, nil
+			}
+			break
+		}
+		root = node.children[keyBit(key, level)]
+	}
+	return [8]uint64{}, nil
+}
+
+// set sets the value of the key in the subtree at the level and returns its new root. A zero
+// value removes the key
+func (db *MemoryHashDB) set(root, key [4]uint64, value [8]uint64, level int) ([4]uint64, error) {
+	if root == ([4]uint64{}) {
+		if value == ([8]uint64{}) {
+			return root, nil
+		}
+		return db.putLeaf(key, value, level)
+	}
+	node, found := db.nodes[root]
+	if !found {
+		return [4]uint64{}, fmt.Errorf("node %s not found", H4ToString(root[:]))
+	}
+
+	if node.leaf {
+		if node.rKey == remainingKey(key, level) {
+			if value == ([8]uint64{}) {
+				return [4]uint64{}, nil
+			}
+			return db.putLeaf(key, value, level)
+		}
+		if value == ([8]uint64{}) {
+			return root, nil
+		}
+		return db.split(joinKey(key, node.rKey, level), node.value, key, value, level)
+	}
+
+	bit := keyBit(key, level)
+	child, err := db.set(node.children[bit], key, value, level+1)
+	if err != nil {
+		return [4]uint64{}, err
+	}
+	if child == node.children[bit] {
+		return root, nil
+	}
+	children := node.children
+	children[bit] = child
+
+	// a leaf without sibling moves up, so the tree doesn't depend on the order of the changes
+	for i := range children {
+		if children[i] != ([4]uint64{}) {
+			continue
+		}
+		other := children[1-i]
+		if other == ([4]uint64{}) {
+			return other, nil
+		}
+		otherNode, found := db.nodes[other]
+		if !found {
+			return [4]uint64{}, fmt.Errorf("node %s not found", H4ToString(other[:]))
+		}
+		if otherNode.leaf {
+			// the leaf shares with the key the path up to this level, the one to its child is 1-i
+			otherKey := joinKey(key, otherNode.rKey, level+1)
+			otherKey[level%4] ^= uint64(keyBit(otherKey, level)^(1-i)) << (level / 4) //nolint:gomnd
+			return db.putLeaf(otherKey, otherNode.value, level)
+		}
+	}
+	return db.putBranch(children)
+}
+
+// split creates the subtree at the level with two leaves whose keys share the path up to it
+func (db *MemoryHashDB) split(key1 [4]uint64, value1 [8]uint64, key2 [4]uint64, value2 [8]uint64, level int) ([4]uint64, error) {
+	bit1, bit2 := keyBit(key1, level), keyBit(key2, level)
+	children := [2][4]uint64{}
+	if bit1 == bit2 {
+		child, err := db.split(key1, value1, key2, value2, level+1)
+		if err != nil {
+			return [4]uint64{}, err
+		}
+		children[bit1] = child
+		return db.putBranch(children)
+	}
+
+	var err error
+	if children[bit1], err = db.putLeaf(key1, value1, level+1); err != nil {
+		return [4]uint64{}, err
+	}
+	if children[bit2], err = db.putLeaf(key2, value2, level+1); err != nil {
+		return [4]uint64{}, err
+	}
+	return db.putBranch(children)
+}
+
+// putLeaf stores a leaf at the level: H(remainingKey || H(value)) with capacity [1, 0, 0, 0]
+func (db *MemoryHashDB) putLeaf(key [4]uint64, value [8]uint64, level int) ([4]uint64, error) {
+	valueHash, err := poseidon.Hash(value, [4]uint64{})
+	if err != nil {
+		return [4]uint64{}, err
+	}
+	rKey := remainingKey(key, level)
+	hash, err := poseidon.Hash([8]uint64{rKey[0], rKey[1], rKey[2], rKey[3], valueHash[0], valueHash[1], valueHash[2], valueHash[3]}, [4]uint64{1, 0, 0, 0})
+	if err != nil {
+		return [4]uint64{}, err
+	}
+	db.nodes[hash] = &memoryNode{leaf: true, rKey: rKey, value: value}
+	return hash, nil
+}
+
+// putBranch stores an intermediate node: H(left || right) with capacity [0, 0, 0, 0]
+func (db *MemoryHashDB) putBranch(children [2][4]uint64) ([4]uint64, error) {
+	l, r := children[0], children[1]
+	hash, err := poseidon.Hash([8]uint64{l[0], l[1], l[2], l[3], r[0], r[1], r[2], r[3]}, [4]uint64{})
+	if err != nil {
+		return [4]uint64{}, err
+	}
+	db.nodes[hash] = &memoryNode{children: children}
+	return hash, nil
+}
+
+// keyBit returns the bit of the key that chooses the child at the level. The bits are taken
+// alternately from each of the 4 elements of the key
+func keyBit(key [4]uint64, level int) int {
+	return int((key[level%4] >> (level / 4)) & 1) //nolint:gomnd
+}
+
+// remainingKey removes from the key the bits used to reach the level
+func remainingKey(key [4]uint64, level int) [4]uint64 {
+	r := [4]uint64{}
+	for i := range key {
+		r[i] = key[i] >> (level / 4) //nolint:gomnd
+		if i < level%4 {
+			r[i] >>= 1
+		}
+	}
+	return r
+}
+
+// joinKey returns the key whose first bits are the ones used by the path of the key to reach the
+// level, followed by the remaining key
+func joinKey(key, rKey [4]uint64, level int) [4]uint64 {
+	r := [4]uint64{}
+	for i := range key {
+		usedBits := level / 4 //nolint:gomnd
+		if i < level%4 {
+			usedBits++
+		}
+		r[i] = rKey[i]<<usedBits | key[i]&(uint64(1)<<usedBits-1)
+	}
+	return r
+}
+
+func h4FromFea(fea *hashdb.Fea) [4]uint64 {
+	if fea == nil {
+		return [4]uint64{}
+	}
+	return [4]uint64{fea.Fe0, fea.Fe1, fea.Fe2, fea.Fe3}
+}
+
+func h4FromBytes(b []byte) [4]uint64 {
+	h4 := scalarToh4(new(big.Int).SetBytes(b))
+	return [4]uint64{h4[0], h4[1], h4[2], h4[3]}
+}
+
+func feaFromH4(h4 [4]uint64) *hashdb.Fea {
+	return &hashdb.Fea{Fe0: h4[0], Fe1: h4[1], Fe2: h4[2], Fe3: h4[3]}
+}
+
+func successResult() *hashdb.ResultCode {
+	return &hashdb.ResultCode{Code: hashdb.ResultCode_CODE_SUCCESS}
+}
@@ -0,0 +1,208 @@
+package merkletree
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	poseidon "github.com/iden3/go-iden3-crypto/goldenposeidon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryHashDBRawVectors(t *testing.T) {
+	var testVectors []struct {
+		Keys         []string `json:"keys"`
+		Values       []string `json:"values"`
+		ExpectedRoot string   `json:"expectedRoot"`
+	}
+	data, err := os.ReadFile("test/vectors/src/merkle-tree/smt-raw.json")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &testVectors))
+
+	for ti, tv := range testVectors {
+		t.Run(fmt.Sprintf("Test vector %d", ti), func(t *testing.T) {
+			db := NewMemoryHashDB()
+			root := common.Hash{}.Bytes()
+			for i := range tv.Keys {
+				key, ok := new(big.Int).SetString(tv.Keys[i], 10)
+				require.True(t, ok)
+				value, ok := new(big.Int).SetString(tv.Values[i], 10)
+				require.True(t, ok)
+				root, err = db.SetValue(root, ScalarToFilledByteSlice(key), value)
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tv.ExpectedRoot, common.BytesToHash(root).String())
+
+			for i := range tv.Keys {
+				key, _ := new(big.Int).SetString(tv.Keys[i], 10)
+				value, err := db.GetValue(root, ScalarToFilledByteSlice(key))
+				require.NoError(t, err)
+				// the last value set for a key wins
+				expected := ""
+				for j := range tv.Keys {
+					if tv.Keys[j] == tv.Keys[i] {
+						expected = tv.Values[j]
+					}
+				}
+				assert.Equal(t, expected, value.String())
+			}
+		})
+	}
+}
+
+func TestMemoryHashDBGenesisVectors(t *testing.T) {
+	var testVectors []struct {
+		Addresses []struct {
+			Address string `json:"address"`
+			Balance string `json:"balance"`
+			Nonce   string `json:"nonce"`
+		} `json:"addresses"`
+		ExpectedRoot string `json:"expectedRoot"`
+	}
+	data, err := os.ReadFile("test/vectors/src/merkle-tree/smt-genesis.json")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &testVectors))
+
+	ctx := context.Background()
+	for ti, tv := range testVectors {
+		t.Run(fmt.Sprintf("Test vector %d", ti), func(t *testing.T) {
+			tree := NewStateTree(NewMemoryHashDB())
+			root := common.Hash{}.Bytes()
+			for _, account := range tv.Addresses {
+				address := common.HexToAddress(account.Address)
+				balance, ok := new(big.Int).SetString(account.Balance, 10)
+				require.True(t, ok)
+				nonce, ok := new(big.Int).SetString(account.Nonce, 10)
+				require.True(t, ok)
+				root, _, err = tree.SetBalance(ctx, address, balance, root, "")
+				require.NoError(t, err)
+				root, _, err = tree.SetNonce(ctx, address, nonce, root, "")
+				require.NoError(t, err)
+			}
+			expectedRoot, ok := new(big.Int).SetString(tv.ExpectedRoot, 10)
+			require.True(t, ok)
+			assert.Equal(t, expectedRoot.String(), new(big.Int).SetBytes(root).String())
+
+			for _, account := range tv.Addresses {
+				balance, err := tree.GetBalance(ctx, common.HexToAddress(account.Address), root)
+				require.NoError(t, err)
+				assert.Equal(t, account.Balance, balance.String())
+			}
+		})
+	}
+}
+
+func TestMemoryHashDBRemove(t *testing.T) {
+	ctx := context.Background()
+	tree := NewStateTree(NewMemoryHashDB())
+	code := common.Hex2Bytes("6080604052")
+
+	// the tree is the same whatever the order of the changes
+	root1, _, err := tree.SetBalance(ctx, common.HexToAddress("0x1"), big.NewInt(1), common.Hash{}.Bytes(), "")
+	require.NoError(t, err)
+	root1, _, err = tree.SetCode(ctx, common.HexToAddress("0x2"), code, root1, "")
+	require.NoError(t, err)
+
+	root2, _, err := tree.SetCode(ctx, common.HexToAddress("0x2"), code, common.Hash{}.Bytes(), "")
+	require.NoError(t, err)
+	root2, _, err = tree.SetNonce(ctx, common.HexToAddress("0x3"), big.NewInt(5), root2, "")
+	require.NoError(t, err)
+	root2, _, err = tree.SetBalance(ctx, common.HexToAddress("0x1"), big.NewInt(1), root2, "")
+	require.NoError(t, err)
+	root2, _, err = tree.SetNonce(ctx, common.HexToAddress("0x3"), big.NewInt(0), root2, "")
+	require.NoError(t, err)
+	assert.Equal(t, root1, root2)
+
+	result, err := tree.GetCode(ctx, common.HexToAddress("0x2"), root2)
+	require.NoError(t, err)
+	assert.Equal(t, code, result)
+
+	// removing all the keys leaves an empty tree
+	root2, _, err = tree.SetBalance(ctx, common.HexToAddress("0x1"), big.NewInt(0), root2, "")
+	require.NoError(t, err)
+	root2, _, err = tree.SetStorageAt(ctx, common.HexToAddress("0x2"), big.NewInt(0), big.NewInt(0), root2, "")
+	require.NoError(t, err)
+	key, err := KeyContractCode(common.HexToAddress("0x2"))
+	require.NoError(t, err)
+	db := tree.grpcClient.(*MemoryHashDB)
+	root2, err = db.SetValue(root2, key, big.NewInt(0))
+	require.NoError(t, err)
+	key, err = KeyCodeLength(common.HexToAddress("0x2"))
+	require.NoError(t, err)
+	root2, err = db.SetValue(root2, key, big.NewInt(0))
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash{}.Bytes(), root2)
+}
+
+func TestMemoryHashDBReadTree(t *testing.T) {
+	ctx := context.Background()
+	tree := NewStateTree(NewMemoryHashDB())
+	code := common.Hex2Bytes("6080604052")
+
+	root, _, err := tree.SetBalance(ctx, common.HexToAddress("0x1"), big.NewInt(10), common.Hash{}.Bytes(), "")
+	require.NoError(t, err)
+	root, _, err = tree.SetNonce(ctx, common.HexToAddress("0x1"), big.NewInt(2), root, "")
+	require.NoError(t, err)
+	root, _, err = tree.SetCode(ctx, common.HexToAddress("0x2"), code, root, "")
+	require.NoError(t, err)
+
+	balanceKey, err := KeyEthAddrBalance(common.HexToAddress("0x1"))
+	require.NoError(t, err)
+	missingKey, err := KeyEthAddrNonce(common.HexToAddress("0x3"))
+	require.NoError(t, err)
+	nodes, err := tree.ReadTree(ctx, common.BytesToHash(root), []Key{Key(balanceKey), Key(missingKey)})
+	require.NoError(t, err)
+
+	// all the nodes are hashed like the prover does
+	db := map[[4]uint64][12]uint64{}
+	for hash, value := range nodes {
+		h, err := StringToh4(hash)
+		require.NoError(t, err)
+		require.Len(t, value, 12*16)
+		v := [12]uint64{}
+		for i := range v {
+			fe, ok := new(big.Int).SetString(value[i*16:(i+1)*16], 16)
+			require.True(t, ok)
+			v[i] = fe.Uint64()
+		}
+		expected, err := poseidon.Hash([8]uint64{v[0], v[1], v[2], v[3], v[4], v[5], v[6], v[7]}, [4]uint64{v[8], v[9], v[10], v[11]})
+		require.NoError(t, err)
+		require.Equal(t, expected, [4]uint64{h[0], h[1], h[2], h[3]})
+		db[expected] = v
+	}
+
+	// the nodes are enough to get the value of the keys from the root
+	getValue := func(key []byte) *big.Int {
+		k := h4FromBytes(key)
+		hash := h4FromBytes(root)
+		for level := 0; hash != ([4]uint64{}); level++ {
+			node, found := db[hash]
+			require.True(t, found)
+			if node[8] == 1 {
+				if [4]uint64{node[0], node[1], node[2], node[3]} != remainingKey(k, level) {
+					return big.NewInt(0)
+				}
+				value, found := db[[4]uint64{node[4], node[5], node[6], node[7]}]
+				require.True(t, found)
+				return fea2scalar(value[:8])
+			}
+			hash = [4]uint64{node[4*keyBit(k, level)], node[4*keyBit(k, level)+1], node[4*keyBit(k, level)+2], node[4*keyBit(k, level)+3]}
+		}
+		return big.NewInt(0)
+	}
+	assert.Equal(t, big.NewInt(10), getValue(balanceKey))
+	assert.Equal(t, big.NewInt(0), getValue(missingKey))
+
+	codeHash, err := tree.GetCodeHash(ctx, common.HexToAddress("0x2"), root)
+	require.NoError(t, err)
+	program, err := tree.GetProgram(ctx, codeHash)
+	require.NoError(t, err)
+	assert.Equal(t, code, program)
+	_, err = tree.GetProgram(ctx, common.Hash{1}.Bytes())
+	assert.Error(t, err)
+}
@@ -328,6 +328,56 @@ func (tree *StateTree) setProgram(ctx context.Context, key []uint64, data []byte
 	return err
 }
 
+// ReadTree returns the nodes of the tree with the given root read to get the values of the keys,
+// in the format of the db field of the executor and prover requests: indexed by their hash, with
+// their 12 field elements concatenated as 16 digits hex strings
+func (tree *StateTree) ReadTree(ctx context.Context, root common.Hash, keys []Key) (map[string]string, error) {
+	r := scalarToh4(root.Big())
+	request := &hashdb.ReadTreeRequest{
+		StateRoot: &hashdb.Fea{Fe0: r[0], Fe1: r[1], Fe2: r[2], Fe3: r[3]},
+		Keys:      make([]*hashdb.Fea, 0, len(keys)),
+	}
+	for _, key := range keys {
+		k := scalarToh4(new(big.Int).SetBytes(key[:]))
+		request.Keys = append(request.Keys, &hashdb.Fea{Fe0: k[0], Fe1: k[1], Fe2: k[2], Fe3: k[3]})
+	}
+
+	result, err := tree.grpcClient.ReadTree(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if result.Result != nil && result.Result.Code != hashdb.ResultCode_CODE_SUCCESS {
+		return nil, fmt.Errorf("failed to read the tree with root %s, result code: %s", root, result.Result.Code)
+	}
+
+	nodes := make(map[string]string, len(result.HashValue))
+	for _, node := range result.HashValue {
+		h, v := node.Hash, node.Value
+		if h == nil || v == nil {
+			continue
+		}
+		key := strings.TrimPrefix(H4ToString([]uint64{h.Fe0, h.Fe1, h.Fe2, h.Fe3}), "0x")
+		nodes[key] = fmt.Sprintf("%016x%016x%016x%016x%016x%016x%016x%016x%016x%016x%016x%016x",
+			v.Fe0, v.Fe1, v.Fe2, v.Fe3, v.Fe4, v.Fe5, v.Fe6, v.Fe7, v.Fe8, v.Fe9, v.Fe10, v.Fe11)
+	}
+	return nodes, nil
+}
+
+// GetProgram returns the bytecode stored with the given hash.
+func (tree *StateTree) GetProgram(ctx context.Context, hash []byte) ([]byte, error) {
+	k := scalarToh4(new(big.Int).SetBytes(hash))
+	result, err := tree.grpcClient.GetProgram(ctx, &hashdb.GetProgramRequest{
+		Key: &hashdb.Fea{Fe0: k[0], Fe1: k[1], Fe2: k[2], Fe3: k[3]},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Result != nil && result.Result.Code != hashdb.ResultCode_CODE_SUCCESS {
+		return nil, fmt.Errorf("failed to get the program %s, result code: %s", hex.EncodeToHex(hash), result.Result.Code)
+	}
+	return result.Data, nil
+}
+
 // Flush flushes all changes to the persistent storage.
 func (tree *StateTree) Flush(ctx context.Context, newStateRoot common.Hash, uuid string) error {
 	flushRequest := &hashdb.FlushRequest{BatchUuid: uuid, NewStateRoot: newStateRoot.String(), Persistence: hashdb.Persistence_PERSISTENCE_DATABASE}
@@ -14,6 +14,10 @@ type Config struct {
 	// IntervalToRefreshGasPrices is the time to wait to refresh the gas prices
 	IntervalToRefreshGasPrices types.Duration `mapstructure:"IntervalToRefreshGasPrices"`
 
+	// IntervalToRefreshSequencerHalt is the time it takes to sync the halt of the sequencer
+	// scheduled for maintenance from db to memory
+	IntervalToRefreshSequencerHalt types.Duration `mapstructure:"IntervalToRefreshSequencerHalt"`
+
 	// MaxTxBytesSize is the max size of a transaction in bytes
 	MaxTxBytesSize uint64 `mapstructure:"MaxTxBytesSize"`
 
@@ -47,6 +51,25 @@ type Config struct {
 	// TxFeeCap is the global transaction fee(price * gaslimit) cap for
 	// send-transaction variants. The unit is ether. 0 means no cap.
 	TxFeeCap float64 `mapstructure:"TxFeeCap"`
+
+	// PrivateMempool is the config for the private mempool mode
+	PrivateMempool PrivateMempoolCfg `mapstructure:"PrivateMempool"`
+}
+
+// PrivateMempoolCfg contains the configuration properties for the private mempool mode, which protects the txs from
+// front-running hiding them from the public RPC until they are stored in an L2 block
+type PrivateMempoolCfg struct {
+	// Enabled hides the pending txs from the RPC and makes the sequencer process the txs in order of arrival
+	// (first-come-first-served) instead of by gasPrice
+	Enabled bool `mapstructure:"Enabled"`
+
+	// ExposeTxHashes allows the RPC to expose the hashes (but not the contents) of the pending txs to the pending
+	// transaction filters and subscriptions
+	ExposeTxHashes bool `mapstructure:"ExposeTxHashes"`
+
+	// SlotDuration is the duration of the time slots used to order the txs by arrival. The txs are sorted by the slot in
+	// which they were received by the pool and then by their arrival time within the slot
+	SlotDuration types.Duration `mapstructure:"SlotDuration"`
 }
 
 // EffectiveGasPriceCfg contains the configuration properties for the effective gas price
@@ -83,4 +106,7 @@ type EffectiveGasPriceCfg struct {
 	// L2GasPriceSuggesterFactor is the factor to apply to L1 gas price to get the suggested L2 gas price used in the
 	// calculations when the effective gas price is disabled (testing/metrics purposes)
 	L2GasPriceSuggesterFactor float64 `mapstructure:"L2GasPriceSuggesterFactor"`
+
+	// L1CostModel is the model used to price the cost of posting the data of the txs to L1
+	L1CostModel L1CostModelCfg `mapstructure:"L1CostModel"`
 }
@@ -54,8 +54,14 @@ func (e *EffectiveGasPrice) GetTxAndL2GasPrice(txGasPrice *big.Int, l1GasPrice u
 	}
 }
 
+// GetL1CostModel returns the model used to price the cost of posting the data of the txs to L1
+// for the provided L1 blob base fee
+func (e *EffectiveGasPrice) GetL1CostModel(l1BlobBaseFee uint64) L1CostModelType {
+	return e.cfg.L1CostModel.Model(l1BlobBaseFee)
+}
+
 // CalculateBreakEvenGasPrice calculates the break even gas price for a transaction
-func (e *EffectiveGasPrice) CalculateBreakEvenGasPrice(rawTx []byte, txGasPrice *big.Int, txGasUsed uint64, l1GasPrice uint64) (*big.Int, error) {
+func (e *EffectiveGasPrice) CalculateBreakEvenGasPrice(rawTx []byte, txGasPrice *big.Int, txGasUsed uint64, l1GasPrice uint64, l1BlobBaseFee uint64) (*big.Int, error) {
 	const ethTransferGas = 21000
 
 	if l1GasPrice == 0 {
@@ -83,12 +89,8 @@ func (e *EffectiveGasPrice) CalculateBreakEvenGasPrice(rawTx []byte, txGasPrice
 	// Get L2 Min Gas Price
 	l2MinGasPrice := uint64(float64(l1GasPrice) * e.cfg.L1GasPriceFactor)
 
-	txZeroBytes := uint64(bytes.Count(rawTx, []byte{0}))
-	txNonZeroBytes := uint64(len(rawTx)) - txZeroBytes + state.EfficiencyPercentageByteLength
-
 	// Calculate BreakEvenGasPrice
-	totalTxPrice := (txGasUsed * l2MinGasPrice) +
-		((txNonZeroBytes*e.cfg.ByteGasCost)+(txZeroBytes*e.cfg.ZeroByteGasCost))*l1GasPrice
+	totalTxPrice := (txGasUsed * l2MinGasPrice) + e.l1DataCost(rawTx, l1GasPrice, l1BlobBaseFee)
 	breakEvenGasPrice := new(big.Int).SetUint64(uint64(float64(totalTxPrice/txGasUsed) * e.cfg.NetProfit))
 
 	if breakEvenGasPrice.Cmp(new(big.Int).SetUint64(0)) == 0 {
@@ -98,9 +100,29 @@ func (e *EffectiveGasPrice) CalculateBreakEvenGasPrice(rawTx []byte, txGasPrice
 	return breakEvenGasPrice, nil
 }
 
+// l1DataCost calculates the cost of posting the data of a transaction to L1 with the L1 cost model
+func (e *EffectiveGasPrice) l1DataCost(rawTx []byte, l1GasPrice uint64, l1BlobBaseFee uint64) uint64 {
+	txZeroBytes := uint64(bytes.Count(rawTx, []byte{0}))
+	txNonZeroBytes := uint64(len(rawTx)) - txZeroBytes + state.EfficiencyPercentageByteLength
+	calldataCost := ((txNonZeroBytes * e.cfg.ByteGasCost) + (txZeroBytes * e.cfg.ZeroByteGasCost)) * l1GasPrice
+
+	// Blobs price the zero bytes as the non zero ones
+	blobCost := float64(txZeroBytes+txNonZeroBytes) * e.cfg.L1CostModel.BlobBytePrice(l1BlobBaseFee)
+
+	switch e.GetL1CostModel(l1BlobBaseFee) {
+	case BlobL1CostModel:
+		return uint64(blobCost)
+	case MixL1CostModel:
+		weight := e.cfg.L1CostModel.BlobWeight
+		return uint64(weight*blobCost + (1-weight)*float64(calldataCost))
+	default:
+		return calldataCost
+	}
+}
+
 // CalculateEffectiveGasPrice calculates the final effective gas price for a tx
-func (e *EffectiveGasPrice) CalculateEffectiveGasPrice(rawTx []byte, txGasPrice *big.Int, txGasUsed uint64, l1GasPrice uint64, l2GasPrice uint64) (*big.Int, error) {
-	breakEvenGasPrice, err := e.CalculateBreakEvenGasPrice(rawTx, txGasPrice, txGasUsed, l1GasPrice)
+func (e *EffectiveGasPrice) CalculateEffectiveGasPrice(rawTx []byte, txGasPrice *big.Int, txGasUsed uint64, l1GasPrice uint64, l1BlobBaseFee uint64, l2GasPrice uint64) (*big.Int, error) {
+	breakEvenGasPrice, err := e.CalculateBreakEvenGasPrice(rawTx, txGasPrice, txGasUsed, l1GasPrice, l1BlobBaseFee)
 
 	if err != nil {
 		return nil, err
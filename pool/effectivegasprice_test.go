@@ -191,7 +191,7 @@ func TestCalculateBreakEvenGasPrice(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			actual, err := egp.CalculateBreakEvenGasPrice(tc.rawTx, tc.txGasPrice, tc.txGasUsed, tc.l1GasPrice)
+			actual, err := egp.CalculateBreakEvenGasPrice(tc.rawTx, tc.txGasPrice, tc.txGasUsed, tc.l1GasPrice, 0)
 			assert.Equal(t, tc.err, err)
 			if err == nil {
 				if actual.Cmp(new(big.Int).SetUint64(0)) != 0 {
@@ -204,6 +204,65 @@ func TestCalculateBreakEvenGasPrice(t *testing.T) {
 	}
 }
 
+func TestCalculateBreakEvenGasPriceL1CostModel(t *testing.T) {
+	testCases := []struct {
+		name          string
+		l1CostModel   L1CostModelCfg
+		l1BlobBaseFee uint64
+		expectedModel L1CostModelType
+		expectedValue *big.Int
+	}{
+		{
+			name:          "calldata",
+			l1CostModel:   L1CostModelCfg{Type: CalldataL1CostModel},
+			l1BlobBaseFee: 10,
+			expectedModel: CalldataL1CostModel,
+			expectedValue: new(big.Int).SetUint64(83),
+		},
+		{
+			name:          "blob, amortised between half a blob",
+			l1CostModel:   L1CostModelCfg{Type: BlobL1CostModel, BlobBytesPerBatch: 65536},
+			l1BlobBaseFee: 10,
+			expectedModel: BlobL1CostModel,
+			expectedValue: new(big.Int).SetUint64(26),
+		},
+		{
+			name:          "blob, amortised between a full blob",
+			l1CostModel:   L1CostModelCfg{Type: BlobL1CostModel},
+			l1BlobBaseFee: 10,
+			expectedModel: BlobL1CostModel,
+			expectedValue: new(big.Int).SetUint64(25),
+		},
+		{
+			name:          "blob with unknown blob base fee uses calldata",
+			l1CostModel:   L1CostModelCfg{Type: BlobL1CostModel, BlobBytesPerBatch: 65536},
+			expectedModel: CalldataL1CostModel,
+			expectedValue: new(big.Int).SetUint64(83),
+		},
+		{
+			name:          "mix",
+			l1CostModel:   L1CostModelCfg{Type: MixL1CostModel, BlobBytesPerBatch: 65536, BlobWeight: 0.5},
+			l1BlobBaseFee: 10,
+			expectedModel: MixL1CostModel,
+			expectedValue: new(big.Int).SetUint64(54),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := egpCfg
+			cfg.L1CostModel = tc.l1CostModel
+			egp := NewEffectiveGasPrice(cfg)
+
+			rawTx := []byte{1, 0, 2, 0, 3, 0, 4, 0, 5, 0}
+			actual, err := egp.CalculateBreakEvenGasPrice(rawTx, new(big.Int).SetUint64(1000), 200, 100, tc.l1BlobBaseFee)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedValue, actual)
+			assert.Equal(t, tc.expectedModel, egp.GetL1CostModel(tc.l1BlobBaseFee))
+		})
+	}
+}
+
 func TestEthTransferGasPrice(t *testing.T) {
 	testCases := []struct {
 		name                        string
@@ -251,7 +310,7 @@ func TestEthTransferGasPrice(t *testing.T) {
 			egpCfg.EthTransferL1GasPriceFactor = tc.EthTransferL1GasPriceFactor
 
 			egp := NewEffectiveGasPrice(egpCfg)
-			actual, err := egp.CalculateBreakEvenGasPrice(tc.rawTx, tc.txGasPrice, tc.txGasUsed, tc.l1GasPrice)
+			actual, err := egp.CalculateBreakEvenGasPrice(tc.rawTx, tc.txGasPrice, tc.txGasUsed, tc.l1GasPrice, 0)
 
 			assert.Equal(t, nil, err)
 			assert.Equal(t, tc.expectedValue, actual)
@@ -303,7 +362,7 @@ func TestCalculateEffectiveGasPrice(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			actual, err := egp.CalculateEffectiveGasPrice(tc.rawTx, tc.txGasPrice, tc.txGasUsed, tc.l1GasPrice, tc.l2GasPrice)
+			actual, err := egp.CalculateEffectiveGasPrice(tc.rawTx, tc.txGasPrice, tc.txGasUsed, tc.l1GasPrice, 0, tc.l2GasPrice)
 			assert.Equal(t, tc.err, err)
 			if err == nil {
 				if actual.Cmp(new(big.Int).SetUint64(0)) != 0 {
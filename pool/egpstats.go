@@ -0,0 +1,283 @@
+package pool
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// EGPStats contains the statistics of the effective gas price of a set of txs, calculated from their EGP logs
+type EGPStats struct {
+	// Txs is the number of txs
+	Txs uint64
+	// NoInfo is the number of txs without EGP log
+	NoInfo uint64
+	// Errors is the number of txs with errors calculating the effective gas price
+	Errors uint64
+	// Enabled is the number of txs processed with the effective gas price enabled
+	Enabled uint64
+	// Reprocessed is the number of txs reprocessed because of the deviation of the second effective gas price
+	Reprocessed uint64
+	// Suspicious is the number of reprocessed txs that use the balance or gas price opcodes
+	Suspicious uint64
+	// UsedFirst, UsedSecond and UsedUser are the number of txs whose final gas price is the first
+	// effective gas price (EGP1), the second one (EGP2) or the gas price signed by the user.
+	// UsedOther is the number of txs whose final gas price is none of them
+	UsedFirst  uint64
+	UsedSecond uint64
+	UsedUser   uint64
+	UsedOther  uint64
+	// Losses is the number of txs charged with a gas price lower than their effective gas price
+	Losses uint64
+	// Loss is the fee not charged to the txs with losses, in wei
+	Loss *big.Int
+	// Fee is the fee charged with the final gas price, in wei
+	Fee *big.Int
+	// EffectiveFee is the fee at the effective gas price, in wei
+	EffectiveFee *big.Int
+}
+
+// NewEGPStats creates the EGP statistics of a tx from its EGP log, that can be nil
+func NewEGPStats(egpLog *state.EffectiveGasPriceLog) EGPStats {
+	s := EGPStats{Txs: 1, Loss: big.NewInt(0), Fee: big.NewInt(0), EffectiveFee: big.NewInt(0)}
+	if egpLog == nil {
+		s.NoInfo = 1
+		return s
+	}
+
+	valueFinal, valueFirst, valueSecond, gasPrice := bigOrZero(egpLog.ValueFinal), bigOrZero(egpLog.ValueFirst), bigOrZero(egpLog.ValueSecond), bigOrZero(egpLog.GasPrice)
+	if egpLog.Error != "" {
+		s.Errors = 1
+	}
+	if egpLog.Enabled {
+		s.Enabled = 1
+	}
+	if egpLog.Reprocess {
+		s.Reprocessed = 1
+		if valueSecond.Cmp(gasPrice) == -1 && (egpLog.BalanceOC || egpLog.GasPriceOC) {
+			s.Suspicious = 1
+		}
+	}
+
+	switch {
+	case valueFinal.Cmp(valueFirst) == 0:
+		s.UsedFirst = 1
+	case valueFinal.Cmp(valueSecond) == 0:
+		s.UsedSecond = 1
+	case valueFinal.Cmp(gasPrice) == 0:
+		s.UsedUser = 1
+	default:
+		s.UsedOther = 1
+	}
+
+	gasUsed := new(big.Int).SetUint64(egpLog.GasUsedSecond)
+	if egpLog.GasUsedSecond == 0 {
+		gasUsed.SetUint64(egpLog.GasUsedFirst)
+	}
+	effectiveGasPrice := valueFirst
+	if egpLog.Reprocess {
+		effectiveGasPrice = valueSecond
+	}
+	s.Fee.Mul(valueFinal, gasUsed)
+	s.EffectiveFee.Mul(effectiveGasPrice, gasUsed)
+	if valueFinal.Cmp(gasPrice) == 0 && effectiveGasPrice.Cmp(valueFinal) == 1 {
+		s.Losses = 1
+		s.Loss.Mul(new(big.Int).Sub(effectiveGasPrice, valueFinal), gasUsed)
+	}
+	return s
+}
+
+// Add adds the statistics of other txs to the statistics
+func (s *EGPStats) Add(other EGPStats) {
+	s.merge(other, 1)
+}
+
+// Sub removes the statistics of txs previously added to the statistics
+func (s *EGPStats) Sub(other EGPStats) {
+	s.merge(other, -1)
+}
+
+func (s *EGPStats) merge(other EGPStats, sign int) {
+	counters := []struct{ dst, src *uint64 }{
+		{&s.Txs, &other.Txs}, {&s.NoInfo, &other.NoInfo}, {&s.Errors, &other.Errors}, {&s.Enabled, &other.Enabled},
+		{&s.Reprocessed, &other.Reprocessed}, {&s.Suspicious, &other.Suspicious}, {&s.UsedFirst, &other.UsedFirst},
+		{&s.UsedSecond, &other.UsedSecond}, {&s.UsedUser, &other.UsedUser}, {&s.UsedOther, &other.UsedOther}, {&s.Losses, &other.Losses},
+	}
+	for _, c := range counters {
+		if sign > 0 {
+			*c.dst += *c.src
+		} else {
+			*c.dst -= *c.src
+		}
+	}
+
+	amounts := []struct{ dst **big.Int }{{&s.Loss}, {&s.Fee}, {&s.EffectiveFee}}
+	otherAmounts := []*big.Int{other.Loss, other.Fee, other.EffectiveFee}
+	for i, a := range amounts {
+		if *a.dst == nil {
+			*a.dst = big.NewInt(0)
+		}
+		if sign > 0 {
+			(*a.dst).Add(*a.dst, bigOrZero(otherAmounts[i]))
+		} else {
+			(*a.dst).Sub(*a.dst, bigOrZero(otherAmounts[i]))
+		}
+	}
+}
+
+// Copy returns a deep copy of the statistics
+func (s EGPStats) Copy() EGPStats {
+	c := EGPStats{}
+	c.Add(s)
+	return c
+}
+
+// ProfitMargin returns the ratio between the fee charged and the fee at the effective gas price,
+// minus 1. It's negative when the fee charged doesn't cover the effective gas price, and 0 if
+// there is no fee at the effective gas price
+func (s EGPStats) ProfitMargin() float64 {
+	if s.EffectiveFee == nil || s.EffectiveFee.Sign() == 0 {
+		return 0
+	}
+	ratio, _ := new(big.Float).Quo(new(big.Float).SetInt(bigOrZero(s.Fee)), new(big.Float).SetInt(s.EffectiveFee)).Float64()
+	return ratio - 1
+}
+
+// Ratio returns the ratio between a number of txs and the txs with EGP log, 0 if there are none
+func (s EGPStats) Ratio(txs uint64) float64 {
+	withInfo := s.Txs - s.NoInfo
+	if withInfo == 0 {
+		return 0
+	}
+	return float64(txs) / float64(withInfo)
+}
+
+func bigOrZero(value *big.Int) *big.Int {
+	if value == nil {
+		return big.NewInt(0)
+	}
+	return value
+}
+
+type egpStatsEntry struct {
+	timestamp time.Time
+	stats     EGPStats
+}
+
+// EGPStatsWindow keeps the EGP statistics of the txs added during the last window of time
+type EGPStatsWindow struct {
+	window  time.Duration
+	entries []egpStatsEntry
+	stats   EGPStats
+	mux     sync.Mutex
+}
+
+// NewEGPStatsWindow creates an EGPStatsWindow for the provided window of time
+func NewEGPStatsWindow(window time.Duration) *EGPStatsWindow {
+	return &EGPStatsWindow{
+		window: window,
+		stats:  EGPStats{Loss: big.NewInt(0), Fee: big.NewInt(0), EffectiveFee: big.NewInt(0)},
+	}
+}
+
+// Add adds the EGP log of a tx processed at timestamp, and returns the statistics of the window
+func (w *EGPStatsWindow) Add(egpLog *state.EffectiveGasPriceLog, timestamp time.Time) EGPStats {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	entry := egpStatsEntry{timestamp: timestamp, stats: NewEGPStats(egpLog)}
+	w.entries = append(w.entries, entry)
+	w.stats.Add(entry.stats)
+	w.expire(timestamp)
+	return w.stats.Copy()
+}
+
+// Stats returns the statistics of the txs added during the window of time until now
+func (w *EGPStatsWindow) Stats(now time.Time) EGPStats {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	w.expire(now)
+	return w.stats.Copy()
+}
+
+func (w *EGPStatsWindow) expire(now time.Time) {
+	expired := 0
+	for _, entry := range w.entries {
+		if now.Sub(entry.timestamp) <= w.window {
+			break
+		}
+		w.stats.Sub(entry.stats)
+		expired++
+	}
+	w.entries = w.entries[expired:]
+}
+
+// SimulateEffectiveGasPrice recalculates the effective gas price of a processed tx with the
+// configuration of e, using the gas used, the gas prices and the opcodes recorded in its EGP log.
+// The gas used the first time is used as the second one when the tx wasn't executed twice
+func (e *EffectiveGasPrice) SimulateEffectiveGasPrice(tx types.Transaction, egpLog state.EffectiveGasPriceLog) (state.EffectiveGasPriceLog, error) {
+	rawTx, err := state.EncodeTransactionWithoutEffectivePercentage(tx)
+	if err != nil {
+		return state.EffectiveGasPriceLog{}, err
+	}
+
+	txGasPrice, l2GasPrice := e.GetTxAndL2GasPrice(tx.GasPrice(), egpLog.L1GasPrice, egpLog.L2GasPrice)
+	sim := state.EffectiveGasPriceLog{
+		Enabled:        e.IsEnabled(),
+		ValueFinal:     new(big.Int).Set(txGasPrice),
+		ValueFirst:     big.NewInt(0),
+		ValueSecond:    big.NewInt(0),
+		FinalDeviation: big.NewInt(0),
+		MaxDeviation:   big.NewInt(0),
+		GasUsedFirst:   egpLog.GasUsedFirst,
+		GasUsedSecond:  egpLog.GasUsedSecond,
+		GasPrice:       new(big.Int).Set(txGasPrice),
+		GasPriceOC:     egpLog.GasPriceOC,
+		BalanceOC:      egpLog.BalanceOC,
+		L1GasPrice:     egpLog.L1GasPrice,
+		L2GasPrice:     l2GasPrice,
+		L1BlobBaseFee:  egpLog.L1BlobBaseFee,
+		L1CostModel:    string(e.GetL1CostModel(egpLog.L1BlobBaseFee)),
+	}
+	if sim.GasUsedSecond == 0 {
+		sim.GasUsedSecond = sim.GasUsedFirst
+	}
+
+	egp, err := e.CalculateEffectiveGasPrice(rawTx, txGasPrice, sim.GasUsedFirst, sim.L1GasPrice, sim.L1BlobBaseFee, l2GasPrice)
+	if err != nil {
+		sim.Error = err.Error()
+		return sim, nil
+	}
+	sim.ValueFirst.Set(egp)
+	if egp.Cmp(txGasPrice) == -1 {
+		sim.ValueFinal.Set(egp)
+	}
+
+	// Same checks than the sequencer does after executing the tx
+	newEGP, err := e.CalculateEffectiveGasPrice(rawTx, txGasPrice, sim.GasUsedSecond, sim.L1GasPrice, sim.L1BlobBaseFee, l2GasPrice)
+	if err != nil {
+		sim.Error = err.Error()
+		return sim, nil
+	}
+	sim.ValueSecond.Set(newEGP)
+	sim.FinalDeviation.Abs(new(big.Int).Sub(sim.ValueFinal, newEGP))
+	sim.MaxDeviation.Div(new(big.Int).Mul(sim.ValueFinal, new(big.Int).SetUint64(e.GetFinalDeviation())), big.NewInt(100)) //nolint:gomnd
+	if sim.FinalDeviation.Cmp(sim.MaxDeviation) == 1 {
+		sim.Reprocess = true
+		if newEGP.Cmp(txGasPrice) == -1 && !sim.GasPriceOC && !sim.BalanceOC {
+			sim.ValueFinal.Set(newEGP)
+		} else {
+			sim.ValueFinal.Set(txGasPrice)
+		}
+	}
+
+	sim.Percentage, err = e.CalculateEffectiveGasPricePercentage(txGasPrice, sim.ValueFinal)
+	if err != nil {
+		sim.Error = err.Error()
+	}
+	return sim, nil
+}
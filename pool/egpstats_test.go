@@ -0,0 +1,140 @@
+package pool
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEGPStats(t *testing.T) {
+	testCases := []struct {
+		name     string
+		egpLog   *state.EffectiveGasPriceLog
+		expected EGPStats
+	}{
+		{
+			name:     "no egp log",
+			expected: EGPStats{Txs: 1, NoInfo: 1, Loss: big.NewInt(0), Fee: big.NewInt(0), EffectiveFee: big.NewInt(0)},
+		},
+		{
+			name: "first effective gas price used",
+			egpLog: &state.EffectiveGasPriceLog{
+				Enabled:      true,
+				ValueFinal:   big.NewInt(8),
+				ValueFirst:   big.NewInt(8),
+				ValueSecond:  big.NewInt(9),
+				GasPrice:     big.NewInt(10),
+				GasUsedFirst: 100, GasUsedSecond: 110,
+			},
+			expected: EGPStats{Txs: 1, Enabled: 1, UsedFirst: 1, Loss: big.NewInt(0), Fee: big.NewInt(880), EffectiveFee: big.NewInt(880)},
+		},
+		{
+			name: "reprocessed with opcodes, user gas price used with losses",
+			egpLog: &state.EffectiveGasPriceLog{
+				Enabled:      true,
+				Reprocess:    true,
+				BalanceOC:    true,
+				ValueFinal:   big.NewInt(10),
+				ValueFirst:   big.NewInt(5),
+				ValueSecond:  big.NewInt(8),
+				GasPrice:     big.NewInt(10),
+				GasUsedFirst: 100,
+			},
+			expected: EGPStats{Txs: 1, Enabled: 1, Reprocessed: 1, Suspicious: 1, UsedUser: 1, Loss: big.NewInt(0), Fee: big.NewInt(1000), EffectiveFee: big.NewInt(800)},
+		},
+		{
+			name: "effective gas price over the user gas price",
+			egpLog: &state.EffectiveGasPriceLog{
+				Error:        "failed",
+				ValueFinal:   big.NewInt(10),
+				ValueFirst:   big.NewInt(12),
+				ValueSecond:  big.NewInt(12),
+				GasPrice:     big.NewInt(10),
+				GasUsedFirst: 100, GasUsedSecond: 100,
+			},
+			expected: EGPStats{Txs: 1, Errors: 1, UsedUser: 1, Losses: 1, Loss: big.NewInt(200), Fee: big.NewInt(1000), EffectiveFee: big.NewInt(1200)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NewEGPStats(tc.egpLog))
+		})
+	}
+}
+
+func TestEGPStatsWindow(t *testing.T) {
+	now := time.Now()
+	w := NewEGPStatsWindow(time.Minute)
+
+	profitable := &state.EffectiveGasPriceLog{ValueFinal: big.NewInt(10), ValueFirst: big.NewInt(8), ValueSecond: big.NewInt(8), GasPrice: big.NewInt(10), GasUsedFirst: 100}
+	withLoss := &state.EffectiveGasPriceLog{Reprocess: true, ValueFinal: big.NewInt(10), ValueFirst: big.NewInt(8), ValueSecond: big.NewInt(20), GasPrice: big.NewInt(10), GasUsedFirst: 100}
+
+	stats := w.Add(profitable, now.Add(-2*time.Minute))
+	assert.Equal(t, uint64(1), stats.Txs)
+	assert.InDelta(t, 0.25, stats.ProfitMargin(), 0.0001)
+
+	// the first tx is out of the window
+	stats = w.Add(withLoss, now)
+	assert.Equal(t, uint64(1), stats.Txs)
+	assert.Equal(t, uint64(1), stats.Losses)
+	assert.Equal(t, big.NewInt(1000), stats.Loss)
+	assert.InDelta(t, -0.5, stats.ProfitMargin(), 0.0001)
+	assert.Equal(t, float64(1), stats.Ratio(stats.Reprocessed))
+
+	w.Add(nil, now)
+	stats = w.Stats(now)
+	assert.Equal(t, uint64(2), stats.Txs)
+	assert.Equal(t, float64(1), stats.Ratio(stats.UsedUser))
+
+	// the returned stats are a copy
+	stats.Loss.SetUint64(0)
+	assert.Equal(t, big.NewInt(1000), w.Stats(now).Loss)
+
+	stats = w.Stats(now.Add(2 * time.Minute))
+	assert.Equal(t, EGPStats{Loss: big.NewInt(0), Fee: big.NewInt(0), EffectiveFee: big.NewInt(0)}, stats)
+	assert.Equal(t, float64(0), stats.ProfitMargin())
+}
+
+func TestSimulateEffectiveGasPrice(t *testing.T) {
+	to := common.HexToAddress("0x1")
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, To: &to, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(10000000000), Data: []byte{}})
+	egpLog := state.EffectiveGasPriceLog{
+		L1GasPrice:   uint64(1000000000),
+		L2GasPrice:   uint64(10000000000),
+		GasUsedFirst: 21000,
+	}
+
+	egp := NewEffectiveGasPrice(egpCfg)
+	sim, err := egp.SimulateEffectiveGasPrice(*tx, egpLog)
+	require.NoError(t, err)
+	assert.True(t, sim.Enabled)
+	assert.Empty(t, sim.Error)
+	assert.Equal(t, uint64(21000), sim.GasUsedSecond)
+	assert.False(t, sim.Reprocess)
+	assert.Equal(t, sim.ValueFirst, sim.ValueSecond)
+	assert.Equal(t, -1, sim.ValueFirst.Cmp(tx.GasPrice()))
+	assert.Equal(t, sim.ValueFirst, sim.ValueFinal)
+	assert.Equal(t, string(CalldataL1CostModel), sim.L1CostModel)
+
+	// the second execution uses much more gas, so the tx is reprocessed with the second effective gas price
+	egpLog.GasUsedSecond = 10 * egpLog.GasUsedFirst
+	sim, err = egp.SimulateEffectiveGasPrice(*tx, egpLog)
+	require.NoError(t, err)
+	assert.True(t, sim.Reprocess)
+	assert.Equal(t, sim.ValueSecond, sim.ValueFinal)
+
+	// the user gas price is used if the tx uses the balance or gas price opcodes
+	egpLog.BalanceOC = true
+	sim, err = egp.SimulateEffectiveGasPrice(*tx, egpLog)
+	require.NoError(t, err)
+	assert.True(t, sim.Reprocess)
+	assert.Equal(t, tx.GasPrice(), sim.ValueFinal)
+	assert.Equal(t, uint8(255), sim.Percentage)
+}
@@ -76,4 +76,8 @@ var (
 
 	// ErrZeroL1GasPrice is returned if the L1 gas price is 0.
 	ErrZeroL1GasPrice = errors.New("L1 gas price 0")
+
+	// ErrSequencerHalted is returned if the sequencer is halted for maintenance and
+	// new transactions are not accepted until it's resumed.
+	ErrSequencerHalted = errors.New("sequencer halted for maintenance, transactions are not accepted until it's resumed")
 )
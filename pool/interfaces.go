@@ -16,7 +16,7 @@ type storage interface {
 	CountTransactionsByStatus(ctx context.Context, status ...TxStatus) (uint64, error)
 	CountTransactionsByFromAndStatus(ctx context.Context, from common.Address, status ...TxStatus) (uint64, error)
 	DeleteTransactionsByHashes(ctx context.Context, hashes []common.Hash) error
-	GetGasPrices(ctx context.Context) (uint64, uint64, error)
+	GetGasPrices(ctx context.Context) (GasPrices, error)
 	GetNonce(ctx context.Context, address common.Address) (uint64, error)
 	GetPendingTxHashesSince(ctx context.Context, since time.Time) ([]common.Hash, error)
 	GetTxsByFromAndNonce(ctx context.Context, from common.Address, nonce uint64) ([]Transaction, error)
@@ -24,6 +24,8 @@ type storage interface {
 	GetNonWIPPendingTxs(ctx context.Context) ([]Transaction, error)
 	IsTxPending(ctx context.Context, hash common.Hash) (bool, error)
 	SetGasPrices(ctx context.Context, l2GasPrice uint64, l1GasPrice uint64) error
+	SetGasPricesWithInputs(ctx context.Context, gasPrices GasPrices, inputs GasPriceInputs) error
+	GetGasPriceHistory(ctx context.Context, from, to time.Time, limit uint64) ([]GasPriceHistoryEntry, error)
 	DeleteGasPricesHistoryOlderThan(ctx context.Context, date time.Time) error
 	DeleteFailedTransactionsOlderThan(ctx context.Context, date time.Time) error
 	UpdateTxsStatus(ctx context.Context, updateInfo []TxStatusUpdateInfo) error
@@ -39,12 +41,14 @@ type storage interface {
 	GetAllAddressesBlocked(ctx context.Context) ([]common.Address, error)
 	MinL2GasPriceSince(ctx context.Context, timestamp time.Time) (uint64, error)
 	GetEarliestProcessedTx(ctx context.Context) (common.Hash, error)
+	ListenNewTxs(ctx context.Context, onNewTx func(hash common.Hash)) error
 }
 
 type stateInterface interface {
 	GetBalance(ctx context.Context, address common.Address, root common.Hash) (*big.Int, error)
 	GetLastL2Block(ctx context.Context, dbTx pgx.Tx) (*state.L2Block, error)
 	GetNonce(ctx context.Context, address common.Address, root common.Hash) (uint64, error)
+	GetSequencerHalt(ctx context.Context, dbTx pgx.Tx) (*state.SequencerHalt, error)
 	GetTransactionByHash(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*types.Transaction, error)
 	PreProcessTransaction(ctx context.Context, tx *types.Transaction, dbTx pgx.Tx) (*state.ProcessBatchResponse, error)
 }
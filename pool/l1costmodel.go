@@ -0,0 +1,72 @@
+package pool
+
+import (
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// L1CostModelType is the model used to price the cost of posting the L2 data to L1
+type L1CostModelType string
+
+const (
+	// CalldataL1CostModel prices the L2 data as calldata, using the L1 gas price
+	CalldataL1CostModel L1CostModelType = "calldata"
+	// BlobL1CostModel prices the L2 data as blob data, using the L1 blob base fee. The cost of a
+	// blob is amortised between the bytes of L2 data that a batch posts in it
+	BlobL1CostModel L1CostModelType = "blob"
+	// MixL1CostModel weights the calldata and the blob models
+	MixL1CostModel L1CostModelType = "mix"
+
+	// maxBlobDataBytes is the number of bytes of L2 data that fit in a blob, as only 31 bytes of
+	// each field element can be used
+	maxBlobDataBytes = params.BlobTxFieldElementsPerBlob * (params.BlobTxBytesPerFieldElement - 1)
+)
+
+// L1CostModelCfg contains the configuration of the model used to price the cost of posting the L2 data to L1
+type L1CostModelCfg struct {
+	// Type is the L1 cost model: calldata, blob or mix
+	Type L1CostModelType `mapstructure:"Type"`
+
+	// BlobBytesPerBatch is the number of bytes of L2 data between which the cost of a blob is amortised
+	// by the blob model. It's limited to the bytes that fit in a blob, which is also used if it's 0
+	BlobBytesPerBatch uint64 `mapstructure:"BlobBytesPerBatch"`
+
+	// BlobWeight is the weight of the blob model in the mix model, from 0 to 1
+	BlobWeight float64 `mapstructure:"BlobWeight"`
+}
+
+// Model returns the L1 cost model to use. The calldata model is used when the L1 blob base fee is
+// unknown (0), as it happens before the L1 supports blobs
+func (c L1CostModelCfg) Model(l1BlobBaseFee uint64) L1CostModelType {
+	if c.Type == "" || l1BlobBaseFee == 0 {
+		return CalldataL1CostModel
+	}
+	return c.Type
+}
+
+// UsesBlobBaseFee returns if the configured L1 cost model needs the L1 blob base fee
+func (c L1CostModelCfg) UsesBlobBaseFee() bool {
+	return c.Type == BlobL1CostModel || c.Type == MixL1CostModel
+}
+
+// BlobBytePrice returns the price of posting a byte of L2 data in a blob
+func (c L1CostModelCfg) BlobBytePrice(l1BlobBaseFee uint64) float64 {
+	bytesPerBatch := c.BlobBytesPerBatch
+	if bytesPerBatch == 0 || bytesPerBatch > maxBlobDataBytes {
+		bytesPerBatch = maxBlobDataBytes
+	}
+	return float64(l1BlobBaseFee) * params.BlobTxBlobGasPerBlob / float64(bytesPerBatch)
+}
+
+// L1GasPrice returns the L1 gas price that prices a non zero byte of calldata as the model prices a
+// byte of L2 data. It's the L1 gas price for the calldata model
+func (c L1CostModelCfg) L1GasPrice(l1GasPrice, l1BlobBaseFee uint64) uint64 {
+	blobGasPrice := c.BlobBytePrice(l1BlobBaseFee) / float64(params.TxDataNonZeroGasEIP2028)
+	switch c.Model(l1BlobBaseFee) {
+	case BlobL1CostModel:
+		return uint64(blobGasPrice)
+	case MixL1CostModel:
+		return uint64(c.BlobWeight*blobGasPrice + (1-c.BlobWeight)*float64(l1GasPrice))
+	default:
+		return l1GasPrice
+	}
+}
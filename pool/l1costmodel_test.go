@@ -0,0 +1,56 @@
+package pool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestL1CostModelL1GasPrice(t *testing.T) {
+	testCases := []struct {
+		name          string
+		cfg           L1CostModelCfg
+		l1BlobBaseFee uint64
+		expected      uint64
+	}{
+		{
+			name:          "calldata",
+			cfg:           L1CostModelCfg{Type: CalldataL1CostModel},
+			l1BlobBaseFee: 16,
+			expected:      100,
+		},
+		{
+			name:     "not set",
+			expected: 100,
+		},
+		{
+			name:          "blob",
+			cfg:           L1CostModelCfg{Type: BlobL1CostModel, BlobBytesPerBatch: 65536},
+			l1BlobBaseFee: 16,
+			expected:      2,
+		},
+		{
+			name:          "blob amortised between more bytes than fit in a blob",
+			cfg:           L1CostModelCfg{Type: BlobL1CostModel, BlobBytesPerBatch: 1000000},
+			l1BlobBaseFee: 126976,
+			expected:      8192,
+		},
+		{
+			name:     "blob with unknown blob base fee",
+			cfg:      L1CostModelCfg{Type: BlobL1CostModel, BlobBytesPerBatch: 65536},
+			expected: 100,
+		},
+		{
+			name:          "mix",
+			cfg:           L1CostModelCfg{Type: MixL1CostModel, BlobBytesPerBatch: 65536, BlobWeight: 0.5},
+			l1BlobBaseFee: 16,
+			expected:      51,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.cfg.L1GasPrice(100, tc.l1BlobBaseFee))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package pgpoolstorage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -449,29 +450,73 @@ func (p *PostgresPoolStorage) SetGasPrices(ctx context.Context, l2GasPrice, l1Ga
 	return nil
 }
 
-// GetGasPrices returns the latest l2 and l1 gas prices
-func (p *PostgresPoolStorage) GetGasPrices(ctx context.Context) (uint64, uint64, error) {
-	sql := "SELECT price, l1_price FROM pool.gas_price ORDER BY item_id DESC LIMIT 1"
+// SetGasPricesWithInputs sets the latest l2 and l1 gas prices, the l1 blob base fee and the inputs used to calculate them
+func (p *PostgresPoolStorage) SetGasPricesWithInputs(ctx context.Context, gasPrices pool.GasPrices, inputs pool.GasPriceInputs) error {
+	inputsJSON, err := json.Marshal(inputs)
+	if err != nil {
+		return err
+	}
+	sql := "INSERT INTO pool.gas_price (price, l1_price, l1_blob_base_fee, timestamp, inputs) VALUES ($1, $2, $3, $4, $5)"
+	if _, err := p.db.Exec(ctx, sql, gasPrices.L2GasPrice, gasPrices.L1GasPrice, gasPrices.L1BlobBaseFee, time.Now().UTC(), inputsJSON); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetGasPriceHistory returns the gas prices set between from and to, the most recent first, up to limit
+func (p *PostgresPoolStorage) GetGasPriceHistory(ctx context.Context, from, to time.Time, limit uint64) ([]pool.GasPriceHistoryEntry, error) {
+	sql := `SELECT price, COALESCE(l1_price, 0), COALESCE(l1_blob_base_fee, 0), inputs, timestamp
+		FROM pool.gas_price
+		WHERE timestamp >= $1 AND timestamp <= $2
+		ORDER BY item_id DESC
+		LIMIT $3`
+	rows, err := p.db.Query(ctx, sql, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []pool.GasPriceHistoryEntry{}
+	for rows.Next() {
+		var (
+			entry      pool.GasPriceHistoryEntry
+			inputsJSON []byte
+		)
+		if err := rows.Scan(&entry.L2GasPrice, &entry.L1GasPrice, &entry.L1BlobBaseFee, &inputsJSON, &entry.Timestamp); err != nil {
+			return nil, err
+		}
+		if len(inputsJSON) > 0 {
+			if err := json.Unmarshal(inputsJSON, &entry.Inputs); err != nil {
+				return nil, err
+			}
+		}
+		history = append(history, entry)
+	}
+	return history, rows.Err()
+}
+
+// GetGasPrices returns the latest l2 and l1 gas prices and l1 blob base fee
+func (p *PostgresPoolStorage) GetGasPrices(ctx context.Context) (pool.GasPrices, error) {
+	sql := "SELECT price, l1_price, COALESCE(l1_blob_base_fee, 0) FROM pool.gas_price ORDER BY item_id DESC LIMIT 1"
 	rows, err := p.db.Query(ctx, sql)
 	if errors.Is(err, pgx.ErrNoRows) {
-		return 0, 0, state.ErrNotFound
+		return pool.GasPrices{}, state.ErrNotFound
 	} else if err != nil {
-		return 0, 0, err
+		return pool.GasPrices{}, err
 	}
 
 	defer rows.Close()
 
-	l2GasPrice := uint64(0)
-	l1GasPrice := uint64(0)
+	gasPrices := pool.GasPrices{}
 
 	for rows.Next() {
-		err := rows.Scan(&l2GasPrice, &l1GasPrice)
+		err := rows.Scan(&gasPrices.L2GasPrice, &gasPrices.L1GasPrice, &gasPrices.L1BlobBaseFee)
 		if err != nil {
-			return 0, 0, err
+			return pool.GasPrices{}, err
 		}
 	}
 
-	return l2GasPrice, l1GasPrice, nil
+	return gasPrices, nil
 }
 
 // DeleteGasPricesHistoryOlderThan deletes all gas prices older than the given date except the last one
@@ -821,3 +866,28 @@ func (p *PostgresPoolStorage) GetEarliestProcessedTx(ctx context.Context) (commo
 
 	return common.HexToHash(txnHash), nil
 }
+
+// ListenNewTxs listens to the notifications of the pool database for the txs that become pending, calling onNewTx
+// with the hash of each one. It uses a dedicated connection and blocks until the context is done or the connection fails
+func (p *PostgresPoolStorage) ListenNewTxs(ctx context.Context, onNewTx func(hash common.Hash)) error {
+	conn, err := pgx.ConnectConfig(ctx, p.db.Config().ConnConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, "LISTEN pool_new_txs")
+	if err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if ctx.Err() != nil {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		onNewTx(common.HexToHash(notification.Payload))
+	}
+}
@@ -20,6 +20,10 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 )
 
+const (
+	listenNewTxsRetryInterval = time.Second
+)
+
 var (
 	// ErrNotFound indicates an object has not been found for the search criteria used
 	ErrNotFound = errors.New("object not found")
@@ -45,6 +49,8 @@ type Pool struct {
 	cfg                     Config
 	batchConstraintsCfg     state.BatchConstraintsCfg
 	blockedAddresses        sync.Map
+	sequencerHalt           *state.SequencerHalt
+	sequencerHaltMux        *sync.RWMutex
 	minSuggestedGasPrice    *big.Int
 	minSuggestedGasPriceMux *sync.RWMutex
 	eventLog                *event.EventLog
@@ -52,6 +58,7 @@ type Pool struct {
 	gasPrices               GasPrices
 	gasPricesMux            *sync.RWMutex
 	effectiveGasPrice       *EffectiveGasPrice
+	txsNotifier             *txsNotifier
 }
 
 type preExecutionResponse struct {
@@ -68,6 +75,37 @@ type preExecutionResponse struct {
 type GasPrices struct {
 	L2GasPrice uint64
 	L1GasPrice uint64
+	// L1BlobBaseFee is the L1 blob base fee, 0 when it's unknown
+	L1BlobBaseFee uint64
+}
+
+// GasPriceInputs contains the inputs used by the L2 gas pricer to calculate the gas prices
+type GasPriceInputs struct {
+	// Pricer is the type of the L2 gas pricer that calculated the gas prices
+	Pricer string `json:"pricer"`
+	// L1GasPriceSource is where the L1 gas price comes from
+	L1GasPriceSource string `json:"l1GasPriceSource"`
+	// L1CostModel is the model used to price the L1 data, empty for the pricers that don't use it
+	L1CostModel string `json:"l1CostModel,omitempty"`
+	// Factor is the factor applied to the L1 gas price to calculate the L2 gas price
+	Factor float64 `json:"factor"`
+	// Congestion is the ratio between the L2 demand and its target, used by the congestion gas pricer
+	Congestion float64 `json:"congestion,omitempty"`
+	// RawL2GasPrice is the L2 gas price before being clamped and truncated
+	RawL2GasPrice uint64 `json:"rawL2GasPrice"`
+	// Clamp is min or max when the raw L2 gas price was raised to the min gas price or lowered
+	// to the max gas price, and empty otherwise
+	Clamp string `json:"clamp,omitempty"`
+	// Truncated is true when the L2 gas price was truncated to its 3 most significant digits
+	Truncated bool `json:"truncated"`
+}
+
+// GasPriceHistoryEntry is an update of the gas prices with the inputs used to calculate them.
+// The inputs are empty for the updates stored without them
+type GasPriceHistoryEntry struct {
+	GasPrices
+	Inputs    GasPriceInputs
+	Timestamp time.Time
 }
 
 // NewPool creates and initializes an instance of Pool
@@ -81,12 +119,14 @@ func NewPool(cfg Config, batchConstraintsCfg state.BatchConstraintsCfg, s storag
 		state:                   st,
 		chainID:                 chainID,
 		blockedAddresses:        sync.Map{},
+		sequencerHaltMux:        new(sync.RWMutex),
 		minSuggestedGasPriceMux: new(sync.RWMutex),
 		minSuggestedGasPrice:    big.NewInt(int64(cfg.DefaultMinGasPriceAllowed)),
 		eventLog:                eventLog,
-		gasPrices:               GasPrices{0, 0},
+		gasPrices:               GasPrices{},
 		gasPricesMux:            new(sync.RWMutex),
 		effectiveGasPrice:       NewEffectiveGasPrice(cfg.EffectiveGasPrice),
+		txsNotifier:             newTxsNotifier(),
 	}
 	p.refreshGasPrices()
 	go func(cfg *Config, p *Pool) {
@@ -156,6 +196,35 @@ func (p *Pool) refreshBlockedAddresses() {
 	}
 }
 
+// StartRefreshingSequencerHaltPeriodically will make this instance of the pool
+// to check periodically(accordingly to the configuration) for updates regarding
+// the halt of the sequencer and update the in memory sequencer halt
+func (p *Pool) StartRefreshingSequencerHaltPeriodically() {
+	p.refreshSequencerHalt()
+	go func(p *Pool) {
+		for {
+			time.Sleep(p.cfg.IntervalToRefreshSequencerHalt.Duration)
+			p.refreshSequencerHalt()
+		}
+	}(p)
+}
+
+// refreshSequencerHalt refreshes the halt of the sequencer for the provided instance of pool
+func (p *Pool) refreshSequencerHalt() {
+	halt, err := p.state.GetSequencerHalt(context.Background(), nil)
+	if errors.Is(err, state.ErrNotFound) {
+		halt = nil
+	} else if err != nil {
+		// Keep using the last sequencer halt loaded
+		log.Errorf("failed to load sequencer halt, error: %v", err)
+		return
+	}
+
+	p.sequencerHaltMux.Lock()
+	p.sequencerHalt = halt
+	p.sequencerHaltMux.Unlock()
+}
+
 // StartPollingMinSuggestedGasPrice starts polling the minimum suggested gas price
 func (p *Pool) StartPollingMinSuggestedGasPrice(ctx context.Context) {
 	p.tryUpdateMinSuggestedGasPrice(p.cfg.DefaultMinGasPriceAllowed)
@@ -174,6 +243,10 @@ func (p *Pool) StartPollingMinSuggestedGasPrice(ctx context.Context) {
 
 // AddTx adds a transaction to the pool with the pending state
 func (p *Pool) AddTx(ctx context.Context, tx types.Transaction, ip string) error {
+	if err := p.checkSequencerHalted(); err != nil {
+		return err
+	}
+
 	poolTx := NewTransaction(tx, ip, false)
 	if err := p.validateTx(ctx, *poolTx); err != nil {
 		return err
@@ -254,7 +327,51 @@ func (p *Pool) StoreTx(ctx context.Context, tx types.Transaction, ip string, isW
 	poolTx.ZKCounters = preExecutionResponse.usedZKCounters
 	poolTx.ReservedZKCounters = preExecutionResponse.reservedZKCounters
 
-	return p.storage.AddTx(ctx, *poolTx)
+	err = p.storage.AddTx(ctx, *poolTx)
+	if err != nil {
+		return err
+	}
+
+	if !isWIP {
+		p.txsNotifier.notify()
+	}
+	return nil
+}
+
+// SubscribeNewTxs returns a channel that is notified when new pending txs are stored in the pool by this
+// instance, e.g. by the RPC running in the same process. If listenDB is true, the txs that become pending
+// in the pool database are notified too, including the ones stored by other processes. The notifications
+// are coalesced and the subscription ends when the context is done
+func (p *Pool) SubscribeNewTxs(ctx context.Context, listenDB bool) <-chan struct{} {
+	ch := p.txsNotifier.subscribe()
+	go func() {
+		if listenDB {
+			p.listenNewTxs(ctx, ch)
+		}
+		<-ctx.Done()
+		p.txsNotifier.unsubscribe(ch)
+	}()
+	return ch
+}
+
+// listenNewTxs forwards the notifications of the pool database to the channel until the context is done,
+// listening again if the connection fails
+func (p *Pool) listenNewTxs(ctx context.Context, ch chan struct{}) {
+	for {
+		err := p.storage.ListenNewTxs(ctx, func(common.Hash) { notify(ch) })
+		if ctx.Err() != nil {
+			return
+		}
+		log.Errorf("failed to listen to the new txs of the pool database, error: %v", err)
+		// Some txs may have not been notified while the connection was down
+		notify(ch)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenNewTxsRetryInterval):
+		}
+	}
 }
 
 // ValidateBreakEvenGasPrice validates the effective gas price
@@ -262,7 +379,7 @@ func (p *Pool) ValidateBreakEvenGasPrice(ctx context.Context, tx types.Transacti
 	// Get the tx gas price we will use in the egp calculation. If egp is disabled we will use a "simulated" tx gas price and l2 gas price
 	txGasPrice, l2GasPrice := p.effectiveGasPrice.GetTxAndL2GasPrice(tx.GasPrice(), gasPrices.L1GasPrice, gasPrices.L2GasPrice)
 
-	breakEvenGasPrice, err := p.effectiveGasPrice.CalculateBreakEvenGasPrice(tx.Data(), txGasPrice, preExecutionGasUsed, gasPrices.L1GasPrice)
+	breakEvenGasPrice, err := p.effectiveGasPrice.CalculateBreakEvenGasPrice(tx.Data(), txGasPrice, preExecutionGasUsed, gasPrices.L1GasPrice, gasPrices.L1BlobBaseFee)
 	if err != nil {
 		if p.cfg.EffectiveGasPrice.Enabled {
 			log.Errorf("error calculating BreakEvenGasPrice: %v", err)
@@ -292,8 +409,9 @@ func (p *Pool) ValidateBreakEvenGasPrice(ctx context.Context, tx types.Transacti
 		}
 	}
 
-	log.Infof("egp-log: txGasPrice(): %v, breakEvenGasPrice: %v, breakEvenGasPriceWithFactor: %v, gasUsed: %v, reject: %t, loss: %v, L1GasPrice: %d, L2GasPrice: %d, Enabled: %t, tx: %s",
-		txGasPrice, breakEvenGasPrice, breakEvenGasPriceWithFactor, preExecutionGasUsed, reject, loss, gasPrices.L1GasPrice, l2GasPrice, p.cfg.EffectiveGasPrice.Enabled, tx.Hash().String())
+	log.Infof("egp-log: txGasPrice(): %v, breakEvenGasPrice: %v, breakEvenGasPriceWithFactor: %v, gasUsed: %v, reject: %t, loss: %v, L1GasPrice: %d, L1BlobBaseFee: %d, L1CostModel: %s, L2GasPrice: %d, Enabled: %t, tx: %s",
+		txGasPrice, breakEvenGasPrice, breakEvenGasPriceWithFactor, preExecutionGasUsed, reject, loss, gasPrices.L1GasPrice, gasPrices.L1BlobBaseFee,
+		p.effectiveGasPrice.GetL1CostModel(gasPrices.L1BlobBaseFee), l2GasPrice, p.cfg.EffectiveGasPrice.Enabled, tx.Hash().String())
 
 	// Reject transaction if EffectiveGasPrice is enabled
 	if p.cfg.EffectiveGasPrice.Enabled && reject {
@@ -390,15 +508,26 @@ func (p *Pool) SetGasPrices(ctx context.Context, l2GasPrice uint64, l1GasPrice u
 	return p.storage.SetGasPrices(ctx, l2GasPrice, l1GasPrice)
 }
 
+// SetGasPricesWithInputs sets the current L2 Gas Price, L1 Gas Price and L1 blob base fee, storing in the gas price
+// history the inputs used to calculate them
+func (p *Pool) SetGasPricesWithInputs(ctx context.Context, gasPrices GasPrices, inputs GasPriceInputs) error {
+	return p.storage.SetGasPricesWithInputs(ctx, gasPrices, inputs)
+}
+
+// GetGasPriceHistory returns the updates of the gas prices between from and to, the most recent
+// first, up to limit updates
+func (p *Pool) GetGasPriceHistory(ctx context.Context, from, to time.Time, limit uint64) ([]GasPriceHistoryEntry, error) {
+	return p.storage.GetGasPriceHistory(ctx, from, to, limit)
+}
+
 // DeleteGasPricesHistoryOlderThan deletes gas prices older than a given date except the most recent one
 func (p *Pool) DeleteGasPricesHistoryOlderThan(ctx context.Context, date time.Time) error {
 	return p.storage.DeleteGasPricesHistoryOlderThan(ctx, date)
 }
 
-// GetGasPrices returns the current L2 Gas Price and L1 Gas Price
+// GetGasPrices returns the current L2 Gas Price, L1 Gas Price and L1 blob base fee
 func (p *Pool) GetGasPrices(ctx context.Context) (GasPrices, error) {
-	l2GasPrice, l1GasPrice, err := p.storage.GetGasPrices(ctx)
-	return GasPrices{L1GasPrice: l1GasPrice, L2GasPrice: l2GasPrice}, err
+	return p.storage.GetGasPrices(ctx)
 }
 
 // CountPendingTransactions get number of pending transactions
@@ -412,6 +541,19 @@ func (p *Pool) IsTxPending(ctx context.Context, hash common.Hash) (bool, error)
 	return p.storage.IsTxPending(ctx, hash)
 }
 
+// checkSequencerHalted returns ErrSequencerHalted with the reason of the halt if the sequencer is halted for maintenance,
+// according to the in memory sequencer halt
+func (p *Pool) checkSequencerHalted() error {
+	p.sequencerHaltMux.RLock()
+	halt := p.sequencerHalt
+	p.sequencerHaltMux.RUnlock()
+
+	if halt != nil && halt.Status == state.SequencerHaltHalted {
+		return fmt.Errorf("%w: %s", ErrSequencerHalted, halt.Reason)
+	}
+	return nil
+}
+
 func (p *Pool) validateTx(ctx context.Context, poolTx Transaction) error {
 	// Make sure the IP is valid.
 	if poolTx.IP != "" && !IsValidIP(poolTx.IP) {
@@ -682,6 +824,15 @@ func (p *Pool) GetL1AndL2GasPrice() (uint64, uint64) {
 	return gasPrices.L1GasPrice, gasPrices.L2GasPrice
 }
 
+// GetCurrentGasPrices returns the L1 gas price, L2 gas price and L1 blob base fee from memory struct.
+// They are read together, so all of them belong to the same update of the gas prices
+func (p *Pool) GetCurrentGasPrices() GasPrices {
+	p.gasPricesMux.RLock()
+	defer p.gasPricesMux.RUnlock()
+
+	return p.gasPrices
+}
+
 const (
 	txDataNonZeroGas      uint64 = 16
 	txGasContractCreation uint64 = 53000
@@ -690,8 +841,8 @@ const (
 )
 
 // CalculateEffectiveGasPrice calculates the final effective gas price for a tx
-func (p *Pool) CalculateEffectiveGasPrice(rawTx []byte, txGasPrice *big.Int, txGasUsed uint64, l1GasPrice uint64, l2GasPrice uint64) (*big.Int, error) {
-	return p.effectiveGasPrice.CalculateEffectiveGasPrice(rawTx, txGasPrice, txGasUsed, l1GasPrice, l2GasPrice)
+func (p *Pool) CalculateEffectiveGasPrice(rawTx []byte, txGasPrice *big.Int, txGasUsed uint64, l1GasPrice uint64, l1BlobBaseFee uint64, l2GasPrice uint64) (*big.Int, error) {
+	return p.effectiveGasPrice.CalculateEffectiveGasPrice(rawTx, txGasPrice, txGasUsed, l1GasPrice, l1BlobBaseFee, l2GasPrice)
 }
 
 // CalculateEffectiveGasPricePercentage calculates the gas price's effective percentage
@@ -704,6 +855,16 @@ func (p *Pool) EffectiveGasPriceEnabled() bool {
 	return p.effectiveGasPrice.IsEnabled()
 }
 
+// PrivateMempoolEnabled returns if the pending txs must be hidden from the RPC until they are stored in an L2 block
+func (p *Pool) PrivateMempoolEnabled() bool {
+	return p.cfg.PrivateMempool.Enabled
+}
+
+// PendingTxHashesExposed returns if the hashes of the pending txs can be exposed by the RPC
+func (p *Pool) PendingTxHashesExposed() bool {
+	return !p.cfg.PrivateMempool.Enabled || p.cfg.PrivateMempool.ExposeTxHashes
+}
+
 // IntrinsicGas computes the 'intrinsic gas' for a given transaction.
 func IntrinsicGas(tx types.Transaction) (uint64, error) {
 	// Set the starting gas for the raw transaction
@@ -65,6 +65,7 @@ var (
 		DefaultMinGasPriceAllowed:         1000000000,
 		IntervalToRefreshBlockedAddresses: cfgTypes.NewDuration(5 * time.Minute),
 		IntervalToRefreshGasPrices:        cfgTypes.NewDuration(5 * time.Second),
+		IntervalToRefreshSequencerHalt:    cfgTypes.NewDuration(time.Second),
 		AccountQueue:                      15,
 		GlobalQueue:                       20,
 		TxFeeCap:                          1,
@@ -322,6 +323,47 @@ func Test_AddTx_OversizedData(t *testing.T) {
 	require.EqualError(t, err, pool.ErrOversizedData.Error())
 }
 
+func Test_AddTx_SequencerHalted(t *testing.T) {
+	initOrResetDB(t)
+
+	stateSqlDB, err := db.NewSQLDB(stateDBCfg)
+	if err != nil {
+		panic(err)
+	}
+	defer stateSqlDB.Close() //nolint:gosec,errcheck
+
+	eventStorage, err := nileventstorage.NewNilEventStorage()
+	if err != nil {
+		log.Fatal(err)
+	}
+	eventLog := event.NewEventLog(event.Config{}, eventStorage)
+
+	st := newState(stateSqlDB, eventLog)
+
+	s, err := pgpoolstorage.NewPostgresPoolStorage(poolDBCfg)
+	require.NoError(t, err)
+
+	const chainID = 2576980377
+	p := pool.NewPool(cfg, bc, s, st, chainID, eventLog)
+
+	ctx := context.Background()
+	require.NoError(t, st.ScheduleSequencerHalt(ctx, 10, nil, "fork upgrade", nil))
+	_, err = st.SetSequencerHalted(ctx, nil)
+	require.NoError(t, err)
+	p.StartRefreshingSequencerHaltPeriodically()
+
+	to := common.HexToAddress(operations.DefaultSequencerAddress)
+	tx := ethTypes.NewTransaction(0, to, big.NewInt(0), gasLimit, big.NewInt(0), []byte{})
+	auth, err := operations.GetAuth(operations.DefaultSequencerPrivateKey, chainID)
+	require.NoError(t, err)
+	signedTx, err := auth.Signer(auth.From, tx)
+	require.NoError(t, err)
+
+	err = p.AddTx(ctx, *signedTx, ip)
+	require.ErrorIs(t, err, pool.ErrSequencerHalted)
+	require.ErrorContains(t, err, "fork upgrade")
+}
+
 func Test_AddPreEIP155Tx(t *testing.T) {
 	initOrResetDB(t)
 
@@ -759,7 +801,7 @@ func Test_SetAndGetGasPrice(t *testing.T) {
 
 	nBig, err := rand.Int(rand.Reader, big.NewInt(0).SetUint64(math.MaxUint64))
 	require.NoError(t, err)
-	expectedGasPrice := pool.GasPrices{nBig.Uint64(), nBig.Uint64()}
+	expectedGasPrice := pool.GasPrices{L2GasPrice: nBig.Uint64(), L1GasPrice: nBig.Uint64()}
 	ctx := context.Background()
 	err = p.SetGasPrices(ctx, expectedGasPrice.L2GasPrice, expectedGasPrice.L1GasPrice)
 	require.NoError(t, err)
@@ -819,6 +861,53 @@ func TestDeleteGasPricesHistoryOlderThan(t *testing.T) {
 	require.Equal(t, expectedL2GasPrice2, min)
 }
 
+func TestGetGasPriceHistory(t *testing.T) {
+	initOrResetDB(t)
+
+	s, err := pgpoolstorage.NewPostgresPoolStorage(poolDBCfg)
+	require.NoError(t, err)
+
+	eventStorage, err := nileventstorage.NewNilEventStorage()
+	require.NoError(t, err)
+	eventLog := event.NewEventLog(event.Config{}, eventStorage)
+
+	p := pool.NewPool(cfg, bc, s, nil, chainID.Uint64(), eventLog)
+
+	ctx := context.Background()
+	from := time.Now().UTC().Add(-time.Second)
+
+	err = p.SetGasPrices(ctx, 1, 2)
+	require.NoError(t, err)
+	inputs := pool.GasPriceInputs{
+		Pricer:           "follower",
+		L1GasPriceSource: "l1",
+		L1CostModel:      "mix",
+		Factor:           0.15,
+		RawL2GasPrice:    1503,
+		Clamp:            "max",
+		Truncated:        true,
+	}
+	err = p.SetGasPricesWithInputs(ctx, pool.GasPrices{L2GasPrice: 1500, L1GasPrice: 10020, L1BlobBaseFee: 7}, inputs)
+	require.NoError(t, err)
+
+	history, err := p.GetGasPriceHistory(ctx, from, time.Now().UTC().Add(time.Second), 10)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, pool.GasPrices{L2GasPrice: 1500, L1GasPrice: 10020, L1BlobBaseFee: 7}, history[0].GasPrices)
+	assert.Equal(t, inputs, history[0].Inputs)
+	assert.Equal(t, pool.GasPrices{L2GasPrice: 1, L1GasPrice: 2}, history[1].GasPrices)
+	assert.Equal(t, pool.GasPriceInputs{}, history[1].Inputs)
+
+	history, err = p.GetGasPriceHistory(ctx, from, time.Now().UTC().Add(time.Second), 1)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, uint64(1500), history[0].L2GasPrice)
+
+	history, err = p.GetGasPriceHistory(ctx, from.Add(-time.Hour), from, 10)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
 func TestGetPendingTxSince(t *testing.T) {
 	initOrResetDB(t)
 
@@ -2144,3 +2233,40 @@ func createSignedTx(t *testing.T, dataLen int, gasPrice *big.Int, gasLimit uint6
 	require.NoError(t, err)
 	return signedTx
 }
+
+func Test_ListenNewTxs(t *testing.T) {
+	initOrResetDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s, err := pgpoolstorage.NewPostgresPoolStorage(poolDBCfg)
+	require.NoError(t, err)
+
+	hashes := make(chan common.Hash, 1)
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- s.ListenNewTxs(ctx, func(hash common.Hash) { hashes <- hash })
+	}()
+	// wait for the listener to be connected
+	time.Sleep(time.Second)
+
+	tx := new(ethTypes.Transaction)
+	b, err := hex.DecodeHex("0xf86880843b9aca008252089400000000000000000000000000000000000000008080850133333355a03ee24709870c8dbc67884c9c8acb864c1aceaaa7332b9a3db0d7a5d7c68eb8e4a0302980b070f5e3ffca3dc27b07daf69d66ab27d4df648e0b3ed059cf23aa168d")
+	require.NoError(t, err)
+	require.NoError(t, tx.UnmarshalBinary(b))
+
+	// the wip txs aren't notified until they are pending again
+	require.NoError(t, s.AddTx(ctx, *pool.NewTransaction(*tx, ip, true)))
+	require.NoError(t, s.MarkWIPTxsAsPending(ctx))
+
+	select {
+	case hash := <-hashes:
+		assert.Equal(t, tx.Hash(), hash)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "new tx not notified")
+	}
+	assert.Len(t, hashes, 0)
+
+	cancel()
+	require.NoError(t, <-listenErr)
+}
@@ -0,0 +1,51 @@
+package pool
+
+import (
+	"sync"
+)
+
+// txsNotifier notifies its subscribers that new pending txs have been stored in the pool. The notifications
+// are coalesced, a subscriber that hasn't read the previous notification isn't notified again
+type txsNotifier struct {
+	mux         sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+func newTxsNotifier() *txsNotifier {
+	return &txsNotifier{
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+}
+
+func (n *txsNotifier) subscribe() chan struct{} {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+
+	ch := make(chan struct{}, 1)
+	n.subscribers[ch] = struct{}{}
+	return ch
+}
+
+func (n *txsNotifier) unsubscribe(ch chan struct{}) {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+
+	delete(n.subscribers, ch)
+}
+
+func (n *txsNotifier) notify() {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+
+	for ch := range n.subscribers {
+		notify(ch)
+	}
+}
+
+// notify sends a notification to the channel if it doesn't have one pending to be read
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
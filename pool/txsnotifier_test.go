@@ -0,0 +1,75 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxsNotifier(t *testing.T) {
+	n := newTxsNotifier()
+	ch1 := n.subscribe()
+	ch2 := n.subscribe()
+
+	// the notifications are coalesced
+	n.notify()
+	n.notify()
+	assert.Len(t, ch1, 1)
+	assert.Len(t, ch2, 1)
+	<-ch1
+
+	n.unsubscribe(ch2)
+	<-ch2
+	n.notify()
+	assert.Len(t, ch1, 1)
+	assert.Len(t, ch2, 0)
+}
+
+type listenerStorageMock struct {
+	storage
+	calls int
+}
+
+func (s *listenerStorageMock) ListenNewTxs(ctx context.Context, onNewTx func(hash common.Hash)) error {
+	s.calls++
+	if s.calls == 1 {
+		return errors.New("connection refused")
+	}
+	onNewTx(common.HexToHash("0x1"))
+	<-ctx.Done()
+	return nil
+}
+
+func TestSubscribeNewTxs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &listenerStorageMock{}
+	p := &Pool{storage: s, txsNotifier: newTxsNotifier()}
+
+	ch := p.SubscribeNewTxs(ctx, true)
+
+	// notified after the failed connection and after the new tx of the database
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ch:
+		case <-time.After(2 * listenNewTxsRetryInterval):
+			require.FailNow(t, "new txs not notified")
+		}
+	}
+
+	// the txs stored by the same instance are notified too
+	p.txsNotifier.notify()
+	<-ch
+
+	cancel()
+	require.Eventually(t, func() bool {
+		p.txsNotifier.mux.Lock()
+		defer p.txsNotifier.mux.Unlock()
+		return len(p.txsNotifier.subscribers) == 0
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 2, s.calls)
+}
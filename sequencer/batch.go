@@ -132,6 +132,12 @@ func (f *finalizer) initWIPBatch(ctx context.Context) {
 		if lastStateBatch.BatchNumber+1 == f.cfg.HaltOnBatchNumber {
 			f.Halt(ctx, fmt.Errorf("finalizer reached stop sequencer on batch number: %d", f.cfg.HaltOnBatchNumber), false)
 		}
+		if halt, reached := f.getScheduledHaltReached(lastStateBatch.BatchNumber + 1); reached {
+			if err := f.haltScheduled(ctx, halt, lastStateBatch.BatchNumber+1); err != nil {
+				log.Infof("stopping finalizer halted for maintenance, error: %v", err)
+				return
+			}
+		}
 		f.wipBatch = f.openNewWIPBatch(lastStateBatch.BatchNumber+1, lastStateBatch.StateRoot)
 		f.pipBatch = nil
 		f.sipBatch = nil
@@ -190,6 +196,9 @@ func (f *finalizer) finalizeWIPBatch(ctx context.Context, closeReason state.Clos
 
 	err := f.closeAndOpenNewWIPBatch(ctx, closeReason)
 	if err != nil {
+		if f.stopIfLeaseLost(err) || ctx.Err() != nil {
+			return
+		}
 		f.Halt(ctx, fmt.Errorf("failed to create new wip batch, error: %v", err), true)
 	}
 
@@ -206,10 +215,15 @@ func (f *finalizer) finalizeSIPBatch(ctx context.Context) error {
 		return fmt.Errorf("error creating db transaction to close sip batch %d, error: %v", f.sipBatch.batchNumber, err)
 	}
 
-	// Close sip batch (close in statedb)
-	err = f.closeSIPBatch(ctx, dbTx)
+	err = f.checkLeadership(ctx, dbTx)
 	if err != nil {
-		return fmt.Errorf("failed to close sip batch %d, error: %v", f.sipBatch.batchNumber, err)
+		err = fmt.Errorf("failed to check the sequencer lease to close sip batch %d, error: %w", f.sipBatch.batchNumber, err)
+	} else {
+		// Close sip batch (close in statedb)
+		err = f.closeSIPBatch(ctx, dbTx)
+		if err != nil {
+			err = fmt.Errorf("failed to close sip batch %d, error: %v", f.sipBatch.batchNumber, err)
+		}
 	}
 
 	if err != nil {
@@ -249,7 +263,7 @@ func (f *finalizer) closeAndOpenNewWIPBatch(ctx context.Context, closeReason sta
 
 		err := f.finalizeSIPBatch(ctx)
 		if err != nil {
-			return fmt.Errorf("error finalizing sip batch %d when processing forced batches, error: %v", f.sipBatch.batchNumber, err)
+			return fmt.Errorf("error finalizing sip batch %d when processing forced batches, error: %w", f.sipBatch.batchNumber, err)
 		}
 	} else {
 		lastStateRoot = f.wipBatch.imStateRoot
@@ -266,12 +280,33 @@ func (f *finalizer) closeAndOpenNewWIPBatch(ctx context.Context, closeReason sta
 		// We finalize the current sip batch
 		err := f.finalizeSIPBatch(ctx)
 		if err != nil {
-			return fmt.Errorf("error finalizing sip batch %d when halting on batch %d", f.sipBatch.batchNumber, f.cfg.HaltOnBatchNumber)
+			return fmt.Errorf("error finalizing sip batch %d when halting on batch %d, error: %w", f.sipBatch.batchNumber, f.cfg.HaltOnBatchNumber, err)
 		}
 
 		f.Halt(ctx, fmt.Errorf("finalizer reached stop sequencer on batch number: %d", f.cfg.HaltOnBatchNumber), false)
 	}
 
+	resumedFromHalt := false
+	if halt, reached := f.getScheduledHaltReached(lastBatchNumber + 1); reached {
+		// We finalize the current sip batch (if it has not been finalized to process forced batches) to halt with all the batches closed
+		if !processForcedBatches {
+			f.waitPendingL2Blocks()
+
+			if f.sipBatch != nil {
+				err := f.finalizeSIPBatch(ctx)
+				if err != nil {
+					return fmt.Errorf("error finalizing sip batch %d when halting for maintenance, error: %w", f.sipBatch.batchNumber, err)
+				}
+			}
+		}
+
+		err := f.haltScheduled(ctx, halt, lastBatchNumber+1)
+		if err != nil {
+			return err
+		}
+		resumedFromHalt = true
+	}
+
 	// Process forced batches
 	if processForcedBatches {
 		lastBatchNumber, lastStateRoot = f.processForcedBatches(ctx, lastBatchNumber, lastStateRoot)
@@ -279,8 +314,9 @@ func (f *finalizer) closeAndOpenNewWIPBatch(ctx context.Context, closeReason sta
 
 	f.wipBatch = f.openNewWIPBatch(lastBatchNumber+1, lastStateRoot)
 
-	if processForcedBatches {
-		// We need to init/reset the wip L2 block in case we have processed forced batches
+	if processForcedBatches || resumedFromHalt {
+		// We need to init/reset the wip L2 block in case we have processed forced batches, or after a maintenance halt
+		// so the new wip L2 block doesn't keep the timestamp it had before the halt
 		f.initWIPL2Block(ctx)
 	} else if f.wipL2Block != nil {
 		// If we are "reusing" the wip L2 block because it's empty we assign it to the new wip batch
@@ -673,6 +709,12 @@ func (f *finalizer) checkIfFinalizeBatch() (bool, state.ClosingReason) {
 		return true, state.ForcedBatchDeadlineClosingReason
 	}
 
+	// Scheduled halt for maintenance reached before the wip batch is closed (halt time reached or halt batch number already opened)
+	if _, reached := f.getScheduledHaltReached(f.wipBatch.batchNumber); reached && !(f.wipBatch.isEmpty() && f.wipL2Block.isEmpty()) {
+		log.Infof("closing batch %d, because of scheduled halt reached", f.wipBatch.batchNumber)
+		return true, state.ScheduledHaltClosingReason
+	}
+
 	// Batch timestamp resolution
 	if !f.wipBatch.isEmpty() && f.wipBatch.timestamp.Add(f.cfg.BatchMaxDeltaTimestamp.Duration).Before(time.Now()) {
 		log.Infof("closing batch %d, because of batch max delta timestamp reached", f.wipBatch.batchNumber)
@@ -20,9 +20,14 @@ type Config struct {
 	// TxLifetimeMax is the time a tx can be in the sequencer/worker memory
 	TxLifetimeMax types.Duration `mapstructure:"TxLifetimeMax"`
 
-	// LoadPoolTxsCheckInterval is the time the sequencer waits to check in there are new txs in the pool
+	// LoadPoolTxsCheckInterval is the time the sequencer waits to check in there are new txs in the pool. The new txs are loaded
+	// as soon as the pool notifies them, so the polling is only a fallback in case a notification is missed
 	LoadPoolTxsCheckInterval types.Duration `mapstructure:"LoadPoolTxsCheckInterval"`
 
+	// ListenPoolTxsNotifications enables listening to the notifications of the pool database to load the new txs as soon as they
+	// are stored by the RPCs running in other processes. The txs stored by the RPC running in the same process are always notified
+	ListenPoolTxsNotifications bool `mapstructure:"ListenPoolTxsNotifications"`
+
 	// StateConsistencyCheckInterval is the time the sequencer waits to check if a state inconsistency has happened
 	StateConsistencyCheckInterval types.Duration `mapstructure:"StateConsistencyCheckInterval"`
 
@@ -34,6 +39,30 @@ type Config struct {
 
 	// StreamServerCfg is the config for the stream server
 	StreamServer StreamServerCfg `mapstructure:"StreamServer"`
+
+	// HA is the config of the active/standby mode of the sequencer
+	HA HACfg `mapstructure:"HA"`
+}
+
+// HACfg contains the configuration properties of the active/standby mode of the sequencer
+type HACfg struct {
+	// Enabled is a flag to enable the active/standby mode. Several sequencers share the state database and only
+	// the one holding the sequencer lease produces blocks, while the others stay in standby to take over when it expires
+	Enabled bool `mapstructure:"Enabled"`
+
+	// NodeID identifies the sequencer as holder of the lease, it must be unique for each sequencer.
+	// If it's empty it's generated from the hostname and a random suffix
+	NodeID string `mapstructure:"NodeID"`
+
+	// LeaseDuration is the time the lease is held without renewing it. The active sequencer halts if it can't
+	// renew the lease before it expires
+	LeaseDuration types.Duration `mapstructure:"LeaseDuration"`
+
+	// RenewInterval is the time interval the active sequencer renews the lease, it must be lower than LeaseDuration
+	RenewInterval types.Duration `mapstructure:"RenewInterval"`
+
+	// StandbyCheckInterval is the time interval a sequencer in standby tries to acquire the lease
+	StandbyCheckInterval types.Duration `mapstructure:"StandbyCheckInterval"`
 }
 
 // StreamServerCfg contains the data streamer's configuration properties
@@ -58,6 +87,9 @@ type StreamServerCfg struct {
 	InactivityTimeout types.Duration `mapstructure:"InactivityTimeout"`
 	// InactivityCheckInterval is the time interval to check for datastream client connections that have reached the inactivity timeout to kill them
 	InactivityCheckInterval types.Duration `mapstructure:"InactivityCheckInterval"`
+	// VerifyBatches is the number of latest batches of the data stream file that are verified against the state at startup.
+	// If an entry doesn't match, the file is truncated and regenerated from the batch of that entry. 0 disables the check
+	VerifyBatches uint64 `mapstructure:"VerifyBatches"`
 }
 
 // FinalizerCfg contains the finalizer's configuration properties
@@ -100,6 +132,10 @@ type FinalizerCfg struct {
 	// The Sequencer will halt after it closes the batch equal to this number
 	HaltOnBatchNumber uint64 `mapstructure:"HaltOnBatchNumber"`
 
+	// ScheduledHaltCheckInterval is the time interval to check the halt of the sequencer scheduled through the admin API,
+	// and to check if the sequencer has been resumed once it's halted
+	ScheduledHaltCheckInterval types.Duration `mapstructure:"ScheduledHaltCheckInterval"`
+
 	// SequentialBatchSanityCheck indicates if the reprocess of a closed batch (sanity check) must be done in a
 	// sequential way (instead than in parallel)
 	SequentialBatchSanityCheck bool `mapstructure:"SequentialBatchSanityCheck"`
@@ -110,6 +146,25 @@ type FinalizerCfg struct {
 
 	// Metrics is the config for the sequencer metrics
 	Metrics MetricsCfg `mapstructure:"Metrics"`
+
+	// ForcedBatchesTracker is the config for the tracking of the forced batches pending to be included in a trusted batch
+	ForcedBatchesTracker ForcedBatchesTrackerCfg `mapstructure:"ForcedBatchesTracker"`
+}
+
+// ForcedBatchesTrackerCfg contains the configuration properties of the tracking of the forced batches pending to be
+// included in a trusted batch. Once the force batch timeout of the rollup contract has expired for a forced batch,
+// anyone can sequence it on L1 without the trusted sequencer
+type ForcedBatchesTrackerCfg struct {
+	// CheckInterval is the time interval to check the forced batches pending to be included
+	CheckInterval types.Duration `mapstructure:"CheckInterval"`
+
+	// WarningThresholdPct is the percentage of the force batch timeout that a forced batch can wait to be included
+	// before a warning event is logged
+	WarningThresholdPct uint32 `mapstructure:"WarningThresholdPct"`
+
+	// CriticalThresholdPct is the percentage of the force batch timeout that a forced batch can wait to be included
+	// before a critical event is logged
+	CriticalThresholdPct uint32 `mapstructure:"CriticalThresholdPct"`
 }
 
 // MetricsCfg contains the sequencer metrics configuration properties
@@ -119,4 +174,7 @@ type MetricsCfg struct {
 
 	// EnableLog is a flag to enable/disable metrics logs
 	EnableLog bool `mapstructure:"EnableLog"`
+
+	// EGPAnalyticsWindow is the interval of time of the txs used to calculate the effective gas price metrics
+	EGPAnalyticsWindow types.Duration `mapstructure:"EGPAnalyticsWindow"`
 }
@@ -3,6 +3,7 @@ package sequencer
 import (
 	"context"
 	"fmt"
+	"math/big"
 
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/datastream"
@@ -55,6 +56,8 @@ func (f *finalizer) DSSendL2Block(ctx context.Context, batchNumber uint64, block
 				l2Transaction.Index = uint64(txResponse.Logs[0].TxIndex)
 			}
 
+			l2Transaction.Receipt = state.GenerateReceipt(new(big.Int).SetUint64(blockResponse.BlockNumber), txResponse, uint(l2Transaction.Index), forkID)
+
 			l2Transactions = append(l2Transactions, l2Transaction)
 		}
 
@@ -15,11 +15,13 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
+	seqMetrics "github.com/0xPolygonHermez/zkevm-node/sequencer/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	stateMetrics "github.com/0xPolygonHermez/zkevm-node/state/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
 )
 
 const (
@@ -46,6 +48,9 @@ type finalizer struct {
 	wipL2Block       *L2Block
 	batchConstraints state.BatchConstraintsCfg
 	haltFinalizer    atomic.Bool
+	// scheduled halt
+	scheduledHalt    *state.SequencerHalt
+	scheduledHaltMux sync.Mutex
 	// stateroot sync
 	nextStateRootSync time.Time
 	// forced batches
@@ -53,6 +58,7 @@ type finalizer struct {
 	nextForcedBatchDeadline int64
 	nextForcedBatchesMux    *sync.Mutex
 	lastForcedBatchNum      uint64
+	forcedBatchTracker      *forcedBatchTracker
 	// L1InfoTree
 	lastL1InfoTreeValid bool
 	lastL1InfoTree      state.L1InfoTreeExitRootStorageEntry
@@ -82,10 +88,17 @@ type finalizer struct {
 	workerReadyTxsCond *timeoutCond
 	// interval metrics
 	metrics *intervalMetrics
+	// effective gas price statistics of the txs processed during the EGP analytics window
+	egpStats *pool.EGPStatsWindow
 	// stream server
 	streamServer      *datastreamer.StreamServer
 	dataToStream      chan interface{}
 	dataToStreamCount atomic.Int32
+	// sequencer lease, nil if the active/standby mode is disabled
+	leader *leaderElector
+	// leaseTerm is the term of the lease the finalizer stores data with and leaseLost is notified when it's lost
+	leaseTerm uint64
+	leaseLost chan error
 }
 
 // newFinalizer returns a new instance of Finalizer.
@@ -103,6 +116,7 @@ func newFinalizer(
 	streamServer *datastreamer.StreamServer,
 	workerReadyTxsCond *timeoutCond,
 	dataToStream chan interface{},
+	leader *leaderElector,
 ) *finalizer {
 	f := finalizer{
 		cfg:              cfg,
@@ -142,15 +156,27 @@ func newFinalizer(
 		// worker ready txs condition
 		workerReadyTxsCond: workerReadyTxsCond,
 		// metrics
-		metrics: newIntervalMetrics(cfg.Metrics.Interval.Duration),
+		metrics:  newIntervalMetrics(cfg.Metrics.Interval.Duration),
+		egpStats: pool.NewEGPStatsWindow(cfg.Metrics.EGPAnalyticsWindow.Duration),
 		// stream server
 		streamServer: streamServer,
 		dataToStream: dataToStream,
+		// sequencer lease
+		leader: leader,
 	}
 
+	if leader != nil {
+		f.leaseTerm = leader.term
+		f.leaseLost = leader.lost
+	}
+
+	f.forcedBatchTracker = newForcedBatchTracker(cfg.ForcedBatchesTracker, stateIntf, etherman, f.LogEvent)
+
 	f.l2BlockReorg.Store(false)
 	f.haltFinalizer.Store(false)
 
+	seqMetrics.Register()
+
 	return &f
 }
 
@@ -159,11 +185,18 @@ func (f *finalizer) Start(ctx context.Context) {
 	// Do sanity check for batches closed but pending to be checked
 	f.processBatchesPendingtoCheck(ctx)
 
+	// Get the halt scheduled for maintenance (if any) before initializing the wip batch
+	f.updateScheduledHalt(ctx)
+	go f.checkScheduledHalt(ctx)
+
 	// Update L1InfoRoot
 	go f.checkL1InfoTreeUpdate(ctx)
 
 	// Get the last batch if still wip or opens a new one
 	f.initWIPBatch(ctx)
+	if ctx.Err() != nil {
+		return
+	}
 
 	// Initializes the wip L2 block
 	f.initWIPL2Block(ctx)
@@ -180,6 +213,9 @@ func (f *finalizer) Start(ctx context.Context) {
 	// Foced batches checking
 	go f.checkForcedBatches(ctx)
 
+	// Forced batches inclusion tracking
+	go f.forcedBatchTracker.start(ctx)
+
 	// Processing transactions and finalizing batches
 	f.finalizeBatches(ctx)
 }
@@ -334,7 +370,11 @@ func (f *finalizer) checkL1InfoTreeUpdate(ctx context.Context) {
 		if skipFirstSleep {
 			skipFirstSleep = false
 		} else {
-			time.Sleep(f.cfg.L1InfoTreeCheckInterval.Duration)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(f.cfg.L1InfoTreeCheckInterval.Duration):
+			}
 		}
 
 		lastL1BlockNumber, err := f.etherman.GetLatestBlockNumber(ctx)
@@ -468,13 +508,33 @@ func (f *finalizer) finalizeBatches(ctx context.Context) {
 			f.wipL2Block.metrics.idleTime += time.Since(idleTime)
 		}
 
-		if f.haltFinalizer.Load() {
-			// There is a fatal error and we need to halt the finalizer and stop processing new txs
-			for {
-				time.Sleep(5 * time.Second) //nolint:gomnd
+		// If the scheduled halt has been reached with an empty wip batch we halt without closing it, since it hasn't been stored
+		// in the state yet. Otherwise the wip batch is closed first (checkIfFinalizeBatch)
+		if halt, reached := f.getScheduledHaltReached(f.wipBatch.batchNumber); reached && f.wipBatch.isEmpty() && f.wipL2Block.isEmpty() {
+			f.waitPendingL2Blocks()
+			if f.sipBatch != nil {
+				err := f.finalizeSIPBatch(ctx)
+				if err != nil && !f.stopIfLeaseLost(err) {
+					f.Halt(ctx, fmt.Errorf("failed to finalize sip batch %d before halting for maintenance, error: %v", f.sipBatch.batchNumber, err), true)
+				}
+			}
+			if !f.haltFinalizer.Load() {
+				if err := f.haltScheduled(ctx, halt, f.wipBatch.batchNumber); err == nil {
+					// The empty wip batch and wip L2 block are opened again, so the wip L2 block doesn't keep the timestamp
+					// it had before the halt
+					f.wipBatch = f.openNewWIPBatch(f.wipBatch.batchNumber, f.wipBatch.initialStateRoot)
+					f.initWIPL2Block(ctx)
+				}
 			}
 		}
 
+		if f.haltFinalizer.Load() {
+			// There is a fatal error, or the sequencer lease has been lost, and we need to halt the finalizer and stop processing new txs
+			<-ctx.Done()
+			log.Infof("stopping halted finalizer because of context, error: %v", ctx.Err())
+			return
+		}
+
 		// Check if we must finalize the batch due to a closing reason (resources exhausted, max txs, timestamp resolution, forced batches deadline)
 		if finalize, closeReason := f.checkIfFinalizeBatch(); finalize {
 			f.finalizeWIPBatch(ctx, closeReason)
@@ -514,18 +574,21 @@ func (f *finalizer) processTransaction(ctx context.Context, tx *TxTracker, first
 	// If it is the first time we process this tx then we calculate the EffectiveGasPrice
 	if firstTxProcess {
 		// Get L1 gas price and store in txTracker to make it consistent during the lifespan of the transaction
-		tx.L1GasPrice, tx.L2GasPrice = f.poolIntf.GetL1AndL2GasPrice()
+		gasPrices := f.poolIntf.GetCurrentGasPrices()
+		tx.L1GasPrice, tx.L2GasPrice, tx.L1BlobBaseFee = gasPrices.L1GasPrice, gasPrices.L2GasPrice, gasPrices.L1BlobBaseFee
 		// Get the tx and l2 gas price we will use in the egp calculation. If egp is disabled we will use a "simulated" tx gas price
 		txGasPrice, txL2GasPrice := f.effectiveGasPrice.GetTxAndL2GasPrice(tx.GasPrice, tx.L1GasPrice, tx.L2GasPrice)
 
 		// Save values for later logging
 		tx.EGPLog.L1GasPrice = tx.L1GasPrice
+		tx.EGPLog.L1BlobBaseFee = tx.L1BlobBaseFee
+		tx.EGPLog.L1CostModel = string(f.effectiveGasPrice.GetL1CostModel(tx.L1BlobBaseFee))
 		tx.EGPLog.L2GasPrice = txL2GasPrice
 		tx.EGPLog.GasUsedFirst = tx.UsedZKCounters.GasUsed
 		tx.EGPLog.GasPrice.Set(txGasPrice)
 
 		// Calculate EffectiveGasPrice
-		egp, err := f.effectiveGasPrice.CalculateEffectiveGasPrice(tx.RawTx, txGasPrice, tx.UsedZKCounters.GasUsed, tx.L1GasPrice, txL2GasPrice)
+		egp, err := f.effectiveGasPrice.CalculateEffectiveGasPrice(tx.RawTx, txGasPrice, tx.UsedZKCounters.GasUsed, tx.L1GasPrice, tx.L1BlobBaseFee, txL2GasPrice)
 		if err != nil {
 			if f.effectiveGasPrice.IsEnabled() {
 				return nil, err
@@ -651,7 +714,7 @@ func (f *finalizer) handleProcessTransactionResponse(ctx context.Context, tx *Tx
 		// Get the tx gas price we will use in the egp calculation. If egp is disabled we will use a "simulated" tx gas price
 		txGasPrice, txL2GasPrice := f.effectiveGasPrice.GetTxAndL2GasPrice(tx.GasPrice, tx.L1GasPrice, tx.L2GasPrice)
 
-		newEffectiveGasPrice, err := f.effectiveGasPrice.CalculateEffectiveGasPrice(tx.RawTx, txGasPrice, txResponse.GasUsed, tx.L1GasPrice, txL2GasPrice)
+		newEffectiveGasPrice, err := f.effectiveGasPrice.CalculateEffectiveGasPrice(tx.RawTx, txGasPrice, txResponse.GasUsed, tx.L1GasPrice, tx.L1BlobBaseFee, txL2GasPrice)
 		if err != nil {
 			if egpEnabled {
 				log.Errorf("failed to calculate effective gas price with new gasUsed for tx %s, error: %v", tx.HashStr, err.Error())
@@ -740,9 +803,12 @@ func (f *finalizer) handleProcessTransactionResponse(ctx context.Context, tx *Tx
 	tx.EGPLog.ValueFinal.Set(tx.EffectiveGasPrice)
 
 	// Log here the results of EGP calculation
-	log.Infof("egp-log: final: %d, first: %d, second: %d, percentage: %d, deviation: %d, maxDeviation: %d, gasUsed1: %d, gasUsed2: %d, gasPrice: %d, l1GasPrice: %d, l2GasPrice: %d, reprocess: %t, gasPriceOC: %t, balanceOC: %t, enabled: %t, txSize: %d, tx: %s, error: %s",
+	log.Infof("egp-log: final: %d, first: %d, second: %d, percentage: %d, deviation: %d, maxDeviation: %d, gasUsed1: %d, gasUsed2: %d, gasPrice: %d, l1GasPrice: %d, l1BlobBaseFee: %d, l1CostModel: %s, l2GasPrice: %d, reprocess: %t, gasPriceOC: %t, balanceOC: %t, enabled: %t, txSize: %d, tx: %s, error: %s",
 		tx.EGPLog.ValueFinal, tx.EGPLog.ValueFirst, tx.EGPLog.ValueSecond, tx.EGPLog.Percentage, tx.EGPLog.FinalDeviation, tx.EGPLog.MaxDeviation, tx.EGPLog.GasUsedFirst, tx.EGPLog.GasUsedSecond,
-		tx.EGPLog.GasPrice, tx.EGPLog.L1GasPrice, tx.EGPLog.L2GasPrice, tx.EGPLog.Reprocess, tx.EGPLog.GasPriceOC, tx.EGPLog.BalanceOC, egpEnabled, len(tx.RawTx), tx.HashStr, tx.EGPLog.Error)
+		tx.EGPLog.GasPrice, tx.EGPLog.L1GasPrice, tx.EGPLog.L1BlobBaseFee, tx.EGPLog.L1CostModel, tx.EGPLog.L2GasPrice, tx.EGPLog.Reprocess, tx.EGPLog.GasPriceOC, tx.EGPLog.BalanceOC, egpEnabled, len(tx.RawTx), tx.HashStr, tx.EGPLog.Error)
+
+	// Update EGP analytics
+	seqMetrics.EGPStats(f.egpStats.Add(&tx.EGPLog, time.Now()))
 
 	f.wipL2Block.addTx(tx)
 
@@ -924,6 +990,27 @@ func (f *finalizer) Halt(ctx context.Context, err error, isFatal bool) {
 	}
 }
 
+// checkLeadership checks in the db transaction that the sequencer still holds the lease when the active/standby
+// mode is enabled, locking the lease until the transaction ends so no other sequencer can take over meanwhile
+func (f *finalizer) checkLeadership(ctx context.Context, dbTx pgx.Tx) error {
+	if f.leader == nil {
+		return nil
+	}
+	return f.leader.checkLease(ctx, f.leaseTerm, dbTx)
+}
+
+// stopIfLeaseLost halts the finalizer if err is caused by the loss of the sequencer lease, notifying the sequencer
+// so it goes back to standby. It returns true if the lease has been lost
+func (f *finalizer) stopIfLeaseLost(err error) bool {
+	if f.leader == nil || !errors.Is(err, state.ErrSequencerLeaseLost) {
+		return false
+	}
+	f.haltFinalizer.Store(true)
+	log.Warnf("finalizer stopped, error: %v", err)
+	notifyLeaseLost(f.leaseLost, err)
+	return true
+}
+
 // LogEvent adds an event for runtime debugging
 func (f *finalizer) LogEvent(ctx context.Context, level event.Level, eventId event.EventID, description string, json interface{}) {
 	event := &event.Event{
@@ -117,7 +117,7 @@ func TestNewFinalizer(t *testing.T) {
 	poolMock.On("GetLastSentFlushID", context.Background()).Return(uint64(0), nil)
 
 	// arrange and act
-	f = newFinalizer(cfg, poolCfg, workerMock, poolMock, stateMock, ethermanMock, l2Coinbase, isSynced, bc, eventLog, nil, newTimeoutCond(&sync.Mutex{}), nil)
+	f = newFinalizer(cfg, poolCfg, workerMock, poolMock, stateMock, ethermanMock, l2Coinbase, isSynced, bc, eventLog, nil, newTimeoutCond(&sync.Mutex{}), nil, nil)
 
 	// assert
 	assert.NotNil(t, f)
@@ -2234,5 +2234,7 @@ func setupFinalizer(withWipBatch bool) *finalizer {
 		proverID:                   "",
 		lastPendingFlushID:         0,
 		pendingFlushIDCond:         sync.NewCond(new(sync.Mutex)),
+		egpStats:                   pool.NewEGPStatsWindow(cfg.Metrics.EGPAnalyticsWindow.Duration),
+		forcedBatchTracker:         newForcedBatchTracker(cfg.ForcedBatchesTracker, stateMock, nil, nil),
 	}
 }
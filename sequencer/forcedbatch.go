@@ -51,6 +51,8 @@ func (f *finalizer) processForcedBatches(ctx context.Context, lastBatchNumber ui
 
 		log.Infof("processed forced batch %d, batchNumber: %d, newStateRoot: %s, contextId: %s", forcedBatchToProcess.ForcedBatchNumber, lastBatchNumber, stateRoot.String(), contextId)
 
+		f.forcedBatchTracker.included(forcedBatchToProcess, now())
+
 		nextForcedBatchNumber += 1
 	}
 	f.nextForcedBatches = make([]state.ForcedBatch, 0)
@@ -74,6 +76,11 @@ func (f *finalizer) processForcedBatch(ctx context.Context, forcedBatch state.Fo
 		return lastBatchNumber, stateRoot, "", retError
 	}
 
+	err = f.checkLeadership(ctx, dbTx)
+	if err != nil {
+		return rollbackOnError(fmt.Errorf("failed to check the sequencer lease to process forced batch %d, error: %w", forcedBatch.ForcedBatchNumber, err))
+	}
+
 	// Get L1 block for the forced batch
 	fbL1Block, err := f.stateIntf.GetBlockByNumber(ctx, forcedBatch.BlockNumber, dbTx)
 	if err != nil {
@@ -232,7 +239,11 @@ func (f *finalizer) setNextForcedBatchDeadline() {
 
 func (f *finalizer) checkForcedBatches(ctx context.Context) {
 	for {
-		time.Sleep(f.cfg.ForcedBatchesCheckInterval.Duration)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(f.cfg.ForcedBatchesCheckInterval.Duration):
+		}
 
 		if f.lastForcedBatchNum == 0 {
 			lastTrustedForcedBatchNum, err := f.stateIntf.GetLastTrustedForcedBatchNumber(ctx, nil)
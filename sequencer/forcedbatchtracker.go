@@ -0,0 +1,236 @@
+package sequencer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	seqMetrics "github.com/0xPolygonHermez/zkevm-node/sequencer/metrics"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+const (
+	forcedBatchStageNone = iota
+	forcedBatchStageWarning
+	forcedBatchStageCritical
+	forcedBatchStageExpired
+)
+
+// pendingForcedBatch is a forced batch pending to be included in a trusted batch
+type pendingForcedBatch struct {
+	forcedBatch state.ForcedBatch
+	// stage is the latest threshold of the force batch timeout reached by the forced batch
+	stage int
+}
+
+// forcedBatchDelayInfo is the json of the events logged when a forced batch reaches a threshold of the force batch timeout
+type forcedBatchDelayInfo struct {
+	ForcedBatchNumber uint64    `json:"forcedBatchNumber"`
+	BlockNumber       uint64    `json:"blockNumber"`
+	ForcedAt          time.Time `json:"forcedAt"`
+	Deadline          time.Time `json:"deadline"`
+	WaitedSeconds     uint64    `json:"waitedSeconds"`
+}
+
+// forcedBatchTracker tracks the forced batches since they are stored on L1 until they are included in a trusted batch,
+// observing their time to inclusion and logging events when they reach the warning and critical thresholds of the force
+// batch timeout of the rollup contract, or the timeout itself, after which anyone can sequence them on L1
+type forcedBatchTracker struct {
+	cfg       ForcedBatchesTrackerCfg
+	stateIntf stateInterface
+	etherman  ethermanInterface
+	logEvent  func(ctx context.Context, level event.Level, eventId event.EventID, description string, json interface{})
+	timeout   time.Duration
+	// storedTimeout is the force batch timeout stored in the state, read by the RPC instead of reading it from L1
+	storedTimeout time.Duration
+	pending       map[uint64]*pendingForcedBatch
+	lastIncluded  uint64
+	mux           sync.Mutex
+}
+
+func newForcedBatchTracker(cfg ForcedBatchesTrackerCfg, stateIntf stateInterface, etherman ethermanInterface,
+	logEvent func(ctx context.Context, level event.Level, eventId event.EventID, description string, json interface{})) *forcedBatchTracker {
+	return &forcedBatchTracker{
+		cfg:       cfg,
+		stateIntf: stateIntf,
+		etherman:  etherman,
+		logEvent:  logEvent,
+		pending:   make(map[uint64]*pendingForcedBatch),
+	}
+}
+
+// start checks the pending forced batches every CheckInterval until the context is done
+func (t *forcedBatchTracker) start(ctx context.Context) {
+	if t.cfg.CheckInterval.Duration == 0 {
+		log.Infof("forced batches tracker disabled")
+		return
+	}
+
+	ticker := time.NewTicker(t.cfg.CheckInterval.Duration)
+	defer ticker.Stop()
+
+	for {
+		err := t.check(ctx, now())
+		if err != nil {
+			log.Errorf("failed to check forced batches pending to be included, error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check updates the forced batches pending to be included with the ones stored in the state, logging the events
+// of the thresholds reached and setting the metrics of the oldest one
+func (t *forcedBatchTracker) check(ctx context.Context, checkTime time.Time) error {
+	timeout, err := t.etherman.GetForceBatchTimeout()
+	if err != nil {
+		// Keep using the last force batch timeout read
+		log.Warnf("failed to get force batch timeout, error: %v", err)
+	} else {
+		t.timeout = timeout
+		if timeout != t.storedTimeout {
+			err = t.stateIntf.SetForceBatchTimeout(ctx, timeout, nil)
+			if err != nil {
+				log.Warnf("failed to store force batch timeout, error: %v", err)
+			} else {
+				t.storedTimeout = timeout
+			}
+		}
+	}
+
+	lastTrustedForcedBatchNumber, err := t.stateIntf.GetLastTrustedForcedBatchNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get last trusted forced batch number, error: %w", err)
+	}
+
+	// The force batch timeout runs since the forced batch is stored on L1, so the L1 block confirmations are not taken into account
+	lastBlock, err := t.stateIntf.GetLastBlock(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get last L1 block, error: %w", err)
+	}
+
+	forcedBatches, err := t.stateIntf.GetForcedBatchesSince(ctx, lastTrustedForcedBatchNumber, lastBlock.BlockNumber, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get forced batches since forced batch %d, error: %w", lastTrustedForcedBatchNumber, err)
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if lastTrustedForcedBatchNumber > t.lastIncluded {
+		t.lastIncluded = lastTrustedForcedBatchNumber
+	}
+
+	// Forced batches included by another sequencer or sequenced on L1 after the timeout
+	for forcedBatchNumber, pending := range t.pending {
+		if forcedBatchNumber <= t.lastIncluded {
+			seqMetrics.ForcedBatchIncluded(checkTime.Sub(pending.forcedBatch.ForcedAt))
+			delete(t.pending, forcedBatchNumber)
+		}
+	}
+
+	for _, forcedBatch := range forcedBatches {
+		if forcedBatch.ForcedBatchNumber <= t.lastIncluded {
+			continue
+		}
+		if _, found := t.pending[forcedBatch.ForcedBatchNumber]; !found {
+			t.pending[forcedBatch.ForcedBatchNumber] = &pendingForcedBatch{forcedBatch: *forcedBatch}
+		}
+	}
+
+	pendingForcedBatches := t.sortedPending()
+	for _, pending := range pendingForcedBatches {
+		t.checkStage(ctx, pending, checkTime)
+	}
+
+	if len(pendingForcedBatches) == 0 {
+		seqMetrics.ForcedBatchesPending(0, 0, 0)
+	} else {
+		oldest := pendingForcedBatches[0].forcedBatch
+		timeLeft := time.Duration(0)
+		if t.timeout > 0 {
+			timeLeft = oldest.ForcedAt.Add(t.timeout).Sub(checkTime)
+		}
+		seqMetrics.ForcedBatchesPending(len(pendingForcedBatches), checkTime.Sub(oldest.ForcedAt), timeLeft)
+	}
+
+	return nil
+}
+
+// checkStage logs the event of the latest threshold of the force batch timeout reached by the forced batch, if it
+// has not been logged yet
+func (t *forcedBatchTracker) checkStage(ctx context.Context, pending *pendingForcedBatch, checkTime time.Time) {
+	if t.timeout == 0 {
+		return
+	}
+
+	waited := checkTime.Sub(pending.forcedBatch.ForcedAt)
+	stage := forcedBatchStageNone
+	if waited >= t.timeout {
+		stage = forcedBatchStageExpired
+	} else if waited >= t.timeout*time.Duration(t.cfg.CriticalThresholdPct)/100 { //nolint:gomnd
+		stage = forcedBatchStageCritical
+	} else if waited >= t.timeout*time.Duration(t.cfg.WarningThresholdPct)/100 { //nolint:gomnd
+		stage = forcedBatchStageWarning
+	}
+
+	if stage <= pending.stage {
+		return
+	}
+	pending.stage = stage
+
+	info := forcedBatchDelayInfo{
+		ForcedBatchNumber: pending.forcedBatch.ForcedBatchNumber,
+		BlockNumber:       pending.forcedBatch.BlockNumber,
+		ForcedAt:          pending.forcedBatch.ForcedAt,
+		Deadline:          pending.forcedBatch.ForcedAt.Add(t.timeout),
+		WaitedSeconds:     uint64(waited.Seconds()),
+	}
+
+	switch stage {
+	case forcedBatchStageWarning:
+		log.Warnf("forced batch %d has waited %v to be included, force batch timeout expires at %v", info.ForcedBatchNumber, waited, info.Deadline)
+		t.logEvent(ctx, event.Level_Warning, event.EventID_ForcedBatchInclusionDelayed,
+			fmt.Sprintf("forced batch %d has waited more than %d%% of the force batch timeout to be included", info.ForcedBatchNumber, t.cfg.WarningThresholdPct), info)
+	case forcedBatchStageCritical:
+		log.Errorf("forced batch %d has waited %v to be included, force batch timeout expires at %v", info.ForcedBatchNumber, waited, info.Deadline)
+		t.logEvent(ctx, event.Level_Critical, event.EventID_ForcedBatchInclusionDelayed,
+			fmt.Sprintf("forced batch %d has waited more than %d%% of the force batch timeout to be included", info.ForcedBatchNumber, t.cfg.CriticalThresholdPct), info)
+	case forcedBatchStageExpired:
+		log.Errorf("force batch timeout of forced batch %d expired at %v before it was included", info.ForcedBatchNumber, info.Deadline)
+		t.logEvent(ctx, event.Level_Critical, event.EventID_ForcedBatchTimeoutExpired,
+			fmt.Sprintf("force batch timeout of forced batch %d expired before it was included", info.ForcedBatchNumber), info)
+	}
+}
+
+// included observes the time to inclusion of a forced batch that has been included in a trusted batch by the finalizer
+func (t *forcedBatchTracker) included(forcedBatch state.ForcedBatch, inclusionTime time.Time) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	seqMetrics.ForcedBatchIncluded(inclusionTime.Sub(forcedBatch.ForcedAt))
+	delete(t.pending, forcedBatch.ForcedBatchNumber)
+	if forcedBatch.ForcedBatchNumber > t.lastIncluded {
+		t.lastIncluded = forcedBatch.ForcedBatchNumber
+	}
+}
+
+// sortedPending returns the forced batches pending to be included sorted by forced batch number
+func (t *forcedBatchTracker) sortedPending() []*pendingForcedBatch {
+	pendingForcedBatches := make([]*pendingForcedBatch, 0, len(t.pending))
+	for _, pending := range t.pending {
+		pendingForcedBatches = append(pendingForcedBatches, pending)
+	}
+	sort.Slice(pendingForcedBatches, func(i, j int) bool {
+		return pendingForcedBatches[i].forcedBatch.ForcedBatchNumber < pendingForcedBatches[j].forcedBatch.ForcedBatchNumber
+	})
+	return pendingForcedBatches
+}
@@ -0,0 +1,140 @@
+package sequencer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var forcedBatchesTrackerCfg = ForcedBatchesTrackerCfg{
+	CheckInterval:        types.NewDuration(time.Minute),
+	WarningThresholdPct:  50,
+	CriticalThresholdPct: 80,
+}
+
+type loggedEvent struct {
+	level   event.Level
+	eventID event.EventID
+	json    interface{}
+}
+
+func newTestForcedBatchTracker(st *StateMock, em *EthermanMock) (*forcedBatchTracker, *[]loggedEvent) {
+	events := []loggedEvent{}
+	logEvent := func(ctx context.Context, level event.Level, eventId event.EventID, description string, json interface{}) {
+		events = append(events, loggedEvent{level: level, eventID: eventId, json: json})
+	}
+	return newForcedBatchTracker(forcedBatchesTrackerCfg, st, em, logEvent), &events
+}
+
+func TestForcedBatchTrackerCheck(t *testing.T) {
+	ctx := context.Background()
+	forcedAt := time.Unix(1000000, 0)
+	timeout := 100 * time.Minute
+
+	testCases := []struct {
+		name           string
+		waited         time.Duration
+		expectedEvents []loggedEvent
+	}{
+		{
+			name:   "below warning threshold",
+			waited: 49 * time.Minute,
+		},
+		{
+			name:           "warning threshold",
+			waited:         50 * time.Minute,
+			expectedEvents: []loggedEvent{{level: event.Level_Warning, eventID: event.EventID_ForcedBatchInclusionDelayed}},
+		},
+		{
+			name:           "critical threshold",
+			waited:         80 * time.Minute,
+			expectedEvents: []loggedEvent{{level: event.Level_Critical, eventID: event.EventID_ForcedBatchInclusionDelayed}},
+		},
+		{
+			name:           "timeout expired",
+			waited:         timeout,
+			expectedEvents: []loggedEvent{{level: event.Level_Critical, eventID: event.EventID_ForcedBatchTimeoutExpired}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			st := NewStateMock(t)
+			em := NewEthermanMock(t)
+			tracker, events := newTestForcedBatchTracker(st, em)
+
+			forcedBatch := &state.ForcedBatch{ForcedBatchNumber: 3, BlockNumber: 90, ForcedAt: forcedAt}
+			em.On("GetForceBatchTimeout").Return(timeout, nil)
+			// The force batch timeout is stored for the RPC only when it changes
+			st.On("SetForceBatchTimeout", ctx, timeout, nil).Return(nil).Once()
+			st.On("GetLastTrustedForcedBatchNumber", ctx, nil).Return(uint64(2), nil)
+			st.On("GetLastBlock", ctx, nil).Return(&state.Block{BlockNumber: 100}, nil)
+			st.On("GetForcedBatchesSince", ctx, uint64(2), uint64(100), nil).Return([]*state.ForcedBatch{forcedBatch}, nil)
+
+			// The event of each threshold is logged once
+			require.NoError(t, tracker.check(ctx, forcedAt.Add(tc.waited)))
+			require.NoError(t, tracker.check(ctx, forcedAt.Add(tc.waited)))
+
+			require.Len(t, *events, len(tc.expectedEvents))
+			for i, expected := range tc.expectedEvents {
+				assert.Equal(t, expected.level, (*events)[i].level)
+				assert.Equal(t, expected.eventID, (*events)[i].eventID)
+				info := (*events)[i].json.(forcedBatchDelayInfo)
+				assert.Equal(t, uint64(3), info.ForcedBatchNumber)
+				assert.Equal(t, forcedAt.Add(timeout), info.Deadline)
+			}
+			assert.Len(t, tracker.pending, 1)
+		})
+	}
+}
+
+func TestForcedBatchTrackerInclusion(t *testing.T) {
+	ctx := context.Background()
+	st := NewStateMock(t)
+	em := NewEthermanMock(t)
+	tracker, events := newTestForcedBatchTracker(st, em)
+
+	forcedAt := time.Unix(1000000, 0)
+	forcedBatches := []*state.ForcedBatch{
+		{ForcedBatchNumber: 1, BlockNumber: 90, ForcedAt: forcedAt},
+		{ForcedBatchNumber: 2, BlockNumber: 91, ForcedAt: forcedAt.Add(time.Minute)},
+		{ForcedBatchNumber: 3, BlockNumber: 92, ForcedAt: forcedAt.Add(2 * time.Minute)},
+	}
+
+	// The force batch timeout can't be read, so no thresholds are checked
+	em.On("GetForceBatchTimeout").Return(time.Duration(0), errors.New("error")).Once()
+	st.On("GetLastTrustedForcedBatchNumber", ctx, nil).Return(uint64(0), nil).Once()
+	st.On("GetLastBlock", ctx, nil).Return(&state.Block{BlockNumber: 100}, nil)
+	st.On("GetForcedBatchesSince", ctx, uint64(0), uint64(100), nil).Return(forcedBatches, nil).Once()
+	require.NoError(t, tracker.check(ctx, forcedAt.Add(time.Hour)))
+	assert.Len(t, tracker.pending, 3)
+	assert.Empty(t, *events)
+
+	// The forced batch 1 is included by the finalizer, but the state is not updated yet
+	tracker.included(*forcedBatches[0], forcedAt.Add(time.Hour))
+
+	em.On("GetForceBatchTimeout").Return(time.Hour, nil)
+	st.On("SetForceBatchTimeout", ctx, time.Hour, nil).Return(nil).Once()
+	st.On("GetLastTrustedForcedBatchNumber", ctx, nil).Return(uint64(0), nil).Once()
+	st.On("GetForcedBatchesSince", ctx, uint64(0), uint64(100), nil).Return(forcedBatches, nil).Once()
+	// Only the forced batch 2 has waited more than the warning threshold
+	require.NoError(t, tracker.check(ctx, forcedAt.Add(31*time.Minute)))
+	assert.Len(t, tracker.pending, 2)
+	require.Len(t, *events, 1)
+	assert.Equal(t, event.EventID_ForcedBatchInclusionDelayed, (*events)[0].eventID)
+	assert.Equal(t, uint64(2), (*events)[0].json.(forcedBatchDelayInfo).ForcedBatchNumber)
+
+	// The forced batch 2 is included by another sequencer
+	st.On("GetLastTrustedForcedBatchNumber", ctx, nil).Return(uint64(2), nil).Once()
+	st.On("GetForcedBatchesSince", ctx, uint64(2), uint64(100), nil).Return(forcedBatches[2:], nil).Once()
+	require.NoError(t, tracker.check(ctx, forcedAt.Add(time.Hour)))
+	require.Len(t, tracker.pending, 1)
+	assert.Contains(t, tracker.pending, uint64(3))
+}
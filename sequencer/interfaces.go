@@ -27,8 +27,9 @@ type txPool interface {
 	UpdateTxWIPStatus(ctx context.Context, hash common.Hash, isWIP bool) error
 	GetGasPrices(ctx context.Context) (pool.GasPrices, error)
 	GetDefaultMinGasPriceAllowed() uint64
-	GetL1AndL2GasPrice() (uint64, uint64)
+	GetCurrentGasPrices() pool.GasPrices
 	GetEarliestProcessedTx(ctx context.Context) (common.Hash, error)
+	SubscribeNewTxs(ctx context.Context, listenDB bool) <-chan struct{}
 }
 
 // ethermanInterface contains the methods required to interact with ethereum.
@@ -39,6 +40,7 @@ type ethermanInterface interface {
 	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
 	GetRollupInfoByBlockRange(ctx context.Context, fromBlock uint64, toBlock *uint64) ([]ethermanTypes.Block, map[common.Hash][]ethermanTypes.Order, error)
 	DepositCount(ctx context.Context, blockNumber *uint64) (*big.Int, error)
+	GetForceBatchTimeout() (time.Duration, error)
 }
 
 // stateInterface gathers the methods required to interact with the state.
@@ -71,6 +73,7 @@ type stateInterface interface {
 	GetDSBatches(ctx context.Context, firstBatchNumber, lastBatchNumber uint64, readWIPBatch bool, dbTx pgx.Tx) ([]*state.DSBatch, error)
 	GetDSL2Blocks(ctx context.Context, firstBatchNumber, lastBatchNumber uint64, dbTx pgx.Tx) ([]*state.DSL2Block, error)
 	GetDSL2Transactions(ctx context.Context, firstL2Block, lastL2Block uint64, dbTx pgx.Tx) ([]*state.DSL2Transaction, error)
+	GetDSL2Receipts(ctx context.Context, firstL2Block, lastL2Block uint64, dbTx pgx.Tx) ([]*types.Receipt, error)
 	GetStorageAt(ctx context.Context, address common.Address, position *big.Int, root common.Hash) (*big.Int, error)
 	StoreL2Block(ctx context.Context, batchNumber uint64, l2Block *state.ProcessBlockResponse, txsEGPLog []*state.EffectiveGasPriceLog, dbTx pgx.Tx) (common.Hash, error)
 	BuildChangeL2Block(deltaTimestamp uint32, l1InfoTreeIndex uint32) []byte
@@ -81,6 +84,13 @@ type stateInterface interface {
 	GetL1InfoRootLeafByIndex(ctx context.Context, l1InfoTreeIndex uint32, dbTx pgx.Tx) (state.L1InfoTreeExitRootStorageEntry, error)
 	GetLatestBatchGlobalExitRoot(ctx context.Context, dbTx pgx.Tx) (common.Hash, error)
 	GetNotCheckedBatches(ctx context.Context, dbTx pgx.Tx) ([]*state.Batch, error)
+	AcquireSequencerLease(ctx context.Context, holder string, duration time.Duration, dbTx pgx.Tx) (uint64, bool, error)
+	RenewSequencerLease(ctx context.Context, holder string, term uint64, duration time.Duration, dbTx pgx.Tx) (bool, error)
+	ReleaseSequencerLease(ctx context.Context, holder string, term uint64, dbTx pgx.Tx) error
+	CheckSequencerLease(ctx context.Context, holder string, term uint64, dbTx pgx.Tx) error
+	GetSequencerHalt(ctx context.Context, dbTx pgx.Tx) (*state.SequencerHalt, error)
+	SetSequencerHalted(ctx context.Context, dbTx pgx.Tx) (bool, error)
+	SetForceBatchTimeout(ctx context.Context, timeout time.Duration, dbTx pgx.Tx) error
 }
 
 type workerInterface interface {
@@ -167,7 +167,7 @@ func (f *finalizer) storePendingL2Blocks(ctx context.Context) {
 
 			err := f.storeL2Block(ctx, l2Block)
 
-			if err != nil {
+			if err != nil && !f.stopIfLeaseLost(err) {
 				// Dump L2Block info
 				f.dumpL2Block(l2Block)
 				f.Halt(ctx, fmt.Errorf("error storing L2 block %d [%d], error: %v", l2Block.batchResponse.BlockResponses[0].BlockNumber, l2Block.trackingNum, err), true)
@@ -397,6 +397,11 @@ func (f *finalizer) storeL2Block(ctx context.Context, l2Block *L2Block) error {
 		return retError
 	}
 
+	err = f.checkLeadership(ctx, dbTx)
+	if err != nil {
+		return rollbackOnError(fmt.Errorf("failed to check the sequencer lease to store L2 block %d [%d], error: %w", blockResponse.BlockNumber, l2Block.trackingNum, err))
+	}
+
 	if (f.sipBatch == nil) || (f.sipBatch.batchNumber != l2Block.batch.batchNumber) {
 		// We have l2 blocks to store from a new batch, therefore we insert this new batch in the statedb
 		// First we need to close the current sipBatch
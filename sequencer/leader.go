@@ -0,0 +1,132 @@
+package sequencer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// leaderElector elects the active sequencer among the sequencers that share the state database using a lease
+// stored in it. The sequencer that holds the lease produces blocks and renews it periodically, while the others
+// stay in standby trying to acquire it when it expires. Each time the lease is acquired its term is increased,
+// and the finalizer checks the term in the db transactions that write to the state, so a sequencer that has lost
+// the lease can't store blocks even if it hasn't noticed it yet
+type leaderElector struct {
+	cfg         HACfg
+	nodeID      string
+	stateIntf   stateInterface
+	term        uint64
+	lastRenewal time.Time
+	// lost receives the reason the lease of the current term has been lost, either detected when renewing it
+	// or when storing data. A new channel is created for every term
+	lost chan error
+}
+
+func newLeaderElector(cfg HACfg, stateIntf stateInterface) *leaderElector {
+	nodeID := cfg.NodeID
+	if nodeID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "sequencer"
+		}
+		nodeID = fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8])
+	}
+
+	return &leaderElector{
+		cfg:       cfg,
+		nodeID:    nodeID,
+		stateIntf: stateIntf,
+		lost:      make(chan error, 1),
+	}
+}
+
+// waitForLeadership blocks until the lease is acquired, calling onStandby each time it fails to acquire it.
+// It returns an error only if the context is done before
+func (l *leaderElector) waitForLeadership(ctx context.Context, onStandby func(ctx context.Context)) error {
+	for {
+		startTime := time.Now()
+		term, acquired, err := l.stateIntf.AcquireSequencerLease(ctx, l.nodeID, l.cfg.LeaseDuration.Duration, nil)
+		if err != nil {
+			log.Errorf("failed to acquire the sequencer lease, error: %v", err)
+		} else if acquired {
+			l.term = term
+			l.lastRenewal = startTime
+			l.lost = make(chan error, 1)
+			log.Infof("sequencer lease acquired by %s, term: %d", l.nodeID, l.term)
+			return nil
+		} else {
+			log.Debugf("sequencer %s in standby, the sequencer lease is held by another sequencer", l.nodeID)
+		}
+
+		onStandby(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(l.cfg.StandbyCheckInterval.Duration):
+		}
+	}
+}
+
+// renewLease renews the lease periodically until the context is done, releasing it then. If the lease has been
+// acquired by another sequencer or it can't be renewed before it expires, onLost is called and it stops renewing it
+func (l *leaderElector) renewLease(ctx context.Context, onLost func(err error)) {
+	ticker := time.NewTicker(l.cfg.RenewInterval.Duration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			err := l.stateIntf.ReleaseSequencerLease(context.Background(), l.nodeID, l.term, nil)
+			if err != nil {
+				log.Errorf("failed to release the sequencer lease, term: %d, error: %v", l.term, err)
+			} else {
+				log.Infof("sequencer lease released by %s, term: %d", l.nodeID, l.term)
+			}
+			return
+		case <-ticker.C:
+		}
+
+		startTime := time.Now()
+		renewed, err := l.stateIntf.RenewSequencerLease(ctx, l.nodeID, l.term, l.cfg.LeaseDuration.Duration, nil)
+		if err != nil {
+			log.Errorf("failed to renew the sequencer lease, term: %d, error: %v", l.term, err)
+			// The lease is considered lost once it could have expired, as another sequencer may have acquired it
+			if time.Since(l.lastRenewal) >= l.cfg.LeaseDuration.Duration {
+				onLost(fmt.Errorf("%w, term: %d, it hasn't been renewed since %v", state.ErrSequencerLeaseLost, l.term, l.lastRenewal))
+				return
+			}
+			continue
+		}
+		if !renewed {
+			onLost(fmt.Errorf("%w, term: %d, it has been acquired by another sequencer", state.ErrSequencerLeaseLost, l.term))
+			return
+		}
+		l.lastRenewal = startTime
+	}
+}
+
+// setLost notifies the lease of the current term has been lost
+func (l *leaderElector) setLost(err error) {
+	notifyLeaseLost(l.lost, err)
+}
+
+// notifyLeaseLost sends the reason the lease of a term has been lost to its channel, keeping only the first one
+func notifyLeaseLost(lost chan error, err error) {
+	select {
+	case lost <- err:
+	default:
+	}
+}
+
+// checkLease returns state.ErrSequencerLeaseLost if the lease isn't held anymore in the given term, locking it
+// until the db transaction ends
+func (l *leaderElector) checkLease(ctx context.Context, term uint64, dbTx pgx.Tx) error {
+	return l.stateIntf.CheckSequencerLease(ctx, l.nodeID, term, dbTx)
+}
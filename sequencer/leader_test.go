@@ -0,0 +1,150 @@
+package sequencer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var haCfg = HACfg{
+	Enabled:              true,
+	NodeID:               "seq1",
+	LeaseDuration:        types.NewDuration(50 * time.Millisecond),
+	RenewInterval:        types.NewDuration(5 * time.Millisecond),
+	StandbyCheckInterval: types.NewDuration(time.Millisecond),
+}
+
+func TestLeaderElectorWaitForLeadership(t *testing.T) {
+	ctx := context.Background()
+	st := NewStateMock(t)
+	l := newLeaderElector(haCfg, st)
+
+	// the lease is held by another sequencer until the second try
+	st.On("AcquireSequencerLease", ctx, "seq1", haCfg.LeaseDuration.Duration, nil).Return(uint64(0), false, nil).Once()
+	st.On("AcquireSequencerLease", ctx, "seq1", haCfg.LeaseDuration.Duration, nil).Return(uint64(3), true, nil).Once()
+
+	standbyCount := 0
+	err := l.waitForLeadership(ctx, func(context.Context) { standbyCount++ })
+	require.NoError(t, err)
+	assert.Equal(t, 1, standbyCount)
+	assert.Equal(t, uint64(3), l.term)
+
+	// the context is done while in standby
+	ctx, cancel := context.WithCancel(ctx)
+	st.On("AcquireSequencerLease", ctx, "seq1", haCfg.LeaseDuration.Duration, nil).Return(uint64(0), false, nil)
+	err = l.waitForLeadership(ctx, func(context.Context) { cancel() })
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// the node id is generated if it's not set
+	cfg := haCfg
+	cfg.NodeID = ""
+	assert.NotEmpty(t, newLeaderElector(cfg, st).nodeID)
+	assert.NotEqual(t, newLeaderElector(cfg, st).nodeID, newLeaderElector(cfg, st).nodeID)
+}
+
+func TestLeaderElectorRenewLease(t *testing.T) {
+	testCases := []struct {
+		name       string
+		renewed    bool
+		renewErr   error
+		expectLost bool
+	}{
+		{
+			name:       "lease acquired by another sequencer",
+			renewed:    false,
+			expectLost: true,
+		},
+		{
+			name:       "lease not renewed before it expires",
+			renewErr:   errors.New("connection refused"),
+			expectLost: true,
+		},
+		{
+			name:    "lease released when stopped",
+			renewed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			st := NewStateMock(t)
+			l := newLeaderElector(haCfg, st)
+			l.term = 3
+			l.lastRenewal = time.Now()
+
+			st.On("RenewSequencerLease", ctx, "seq1", uint64(3), haCfg.LeaseDuration.Duration, nil).Return(tc.renewed, tc.renewErr)
+			if !tc.expectLost {
+				st.On("ReleaseSequencerLease", mock.Anything, "seq1", uint64(3), nil).Return(nil).Once()
+			}
+
+			var lostErr error
+			done := make(chan struct{})
+			go func() {
+				l.renewLease(ctx, func(err error) { lostErr = err })
+				close(done)
+			}()
+
+			if tc.expectLost {
+				require.Eventually(t, func() bool {
+					select {
+					case <-done:
+						return true
+					default:
+						return false
+					}
+				}, time.Second, time.Millisecond)
+				assert.ErrorIs(t, lostErr, state.ErrSequencerLeaseLost)
+			} else {
+				time.Sleep(2 * haCfg.LeaseDuration.Duration)
+				cancel()
+				<-done
+				assert.NoError(t, lostErr)
+			}
+		})
+	}
+}
+
+func TestFinalizerCheckLeadership(t *testing.T) {
+	ctx := context.Background()
+	st := NewStateMock(t)
+	dbTx := NewDbTxMock(t)
+
+	// the lease isn't checked if the active/standby mode is disabled
+	f := &finalizer{stateIntf: st}
+	require.NoError(t, f.checkLeadership(ctx, dbTx))
+
+	f.leader = newLeaderElector(haCfg, st)
+	f.leaseTerm = 3
+	st.On("CheckSequencerLease", ctx, "seq1", uint64(3), dbTx).Return(nil).Once()
+	require.NoError(t, f.checkLeadership(ctx, dbTx))
+	st.On("CheckSequencerLease", ctx, "seq1", uint64(3), dbTx).Return(state.ErrSequencerLeaseLost).Once()
+	assert.ErrorIs(t, f.checkLeadership(ctx, dbTx), state.ErrSequencerLeaseLost)
+}
+
+func TestFinalizerStopIfLeaseLost(t *testing.T) {
+	st := NewStateMock(t)
+
+	// without active/standby mode the lease errors halt the finalizer as any other error
+	f := &finalizer{stateIntf: st}
+	assert.False(t, f.stopIfLeaseLost(state.ErrSequencerLeaseLost))
+
+	f.leader = newLeaderElector(haCfg, st)
+	f.leaseLost = f.leader.lost
+	assert.False(t, f.stopIfLeaseLost(errors.New("db error")))
+	assert.False(t, f.haltFinalizer.Load())
+
+	err := fmt.Errorf("failed to store L2 block, error: %w", state.ErrSequencerLeaseLost)
+	assert.True(t, f.stopIfLeaseLost(err))
+	assert.True(t, f.haltFinalizer.Load())
+	assert.ErrorIs(t, <-f.leader.lost, state.ErrSequencerLeaseLost)
+}
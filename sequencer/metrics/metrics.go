@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/metrics"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// Prefix for the metrics of the sequencer package.
+	Prefix = "sequencer_"
+	// EGPPrefix for the effective gas price metrics of the sequencer package.
+	EGPPrefix = Prefix + "egp_"
+	// EGPTxsName is the name of the metric that shows the txs processed during the EGP analytics window.
+	EGPTxsName = EGPPrefix + "txs"
+	// EGPReprocessedRatioName is the name of the metric that shows the ratio of reprocessed txs.
+	EGPReprocessedRatioName = EGPPrefix + "reprocessed_ratio"
+	// EGPSuspiciousName is the name of the metric that shows the reprocessed txs that use the balance or gas price opcodes.
+	EGPSuspiciousName = EGPPrefix + "suspicious_txs"
+	// EGPErrorsName is the name of the metric that shows the txs with errors calculating their effective gas price.
+	EGPErrorsName = EGPPrefix + "errors"
+	// EGPLossesName is the name of the metric that shows the txs charged below their effective gas price.
+	EGPLossesName = EGPPrefix + "losses"
+	// EGPLossName is the name of the metric that shows the fee not charged to the txs with losses.
+	EGPLossName = EGPPrefix + "loss"
+	// EGPUsedFirstRatioName is the name of the metric that shows the ratio of txs charged with the first effective gas price.
+	EGPUsedFirstRatioName = EGPPrefix + "used_first_ratio"
+	// EGPUsedSecondRatioName is the name of the metric that shows the ratio of txs charged with the second effective gas price.
+	EGPUsedSecondRatioName = EGPPrefix + "used_second_ratio"
+	// EGPUsedUserRatioName is the name of the metric that shows the ratio of txs charged with the user gas price.
+	EGPUsedUserRatioName = EGPPrefix + "used_user_ratio"
+	// EGPProfitMarginName is the name of the metric that shows the profit margin over the effective gas price.
+	EGPProfitMarginName = EGPPrefix + "profit_margin"
+	// ForcedBatchesPrefix for the forced batches metrics of the sequencer package.
+	ForcedBatchesPrefix = Prefix + "forced_batches_"
+	// ForcedBatchesPendingName is the name of the metric that shows the forced batches pending to be included in a trusted batch.
+	ForcedBatchesPendingName = ForcedBatchesPrefix + "pending"
+	// ForcedBatchesOldestPendingWaitName is the name of the metric that shows the time the oldest pending forced batch has been waiting.
+	ForcedBatchesOldestPendingWaitName = ForcedBatchesPrefix + "oldest_pending_wait_seconds"
+	// ForcedBatchesOldestPendingTimeLeftName is the name of the metric that shows the time left to the force batch timeout of the oldest pending forced batch.
+	ForcedBatchesOldestPendingTimeLeftName = ForcedBatchesPrefix + "oldest_pending_time_left_seconds"
+	// ForcedBatchesInclusionTimeName is the name of the metric that shows the time the forced batches waited to be included in a trusted batch.
+	ForcedBatchesInclusionTimeName = ForcedBatchesPrefix + "inclusion_time_seconds"
+)
+
+// Register the metrics for the sequencer package.
+func Register() {
+	gauges := []prometheus.GaugeOpts{
+		{
+			Name: EGPTxsName,
+			Help: "[SEQUENCER] txs processed during the EGP analytics window",
+		},
+		{
+			Name: EGPReprocessedRatioName,
+			Help: "[SEQUENCER] ratio of txs reprocessed because of the deviation of their second effective gas price, during the EGP analytics window",
+		},
+		{
+			Name: EGPSuspiciousName,
+			Help: "[SEQUENCER] reprocessed txs that use the balance or gas price opcodes, during the EGP analytics window",
+		},
+		{
+			Name: EGPErrorsName,
+			Help: "[SEQUENCER] txs with errors calculating their effective gas price, during the EGP analytics window",
+		},
+		{
+			Name: EGPLossesName,
+			Help: "[SEQUENCER] txs charged with a gas price lower than their effective gas price, during the EGP analytics window",
+		},
+		{
+			Name: EGPLossName,
+			Help: "[SEQUENCER] fee not charged to the txs with losses during the EGP analytics window, in wei",
+		},
+		{
+			Name: EGPUsedFirstRatioName,
+			Help: "[SEQUENCER] ratio of txs charged with the first effective gas price, during the EGP analytics window",
+		},
+		{
+			Name: EGPUsedSecondRatioName,
+			Help: "[SEQUENCER] ratio of txs charged with the second effective gas price, during the EGP analytics window",
+		},
+		{
+			Name: EGPUsedUserRatioName,
+			Help: "[SEQUENCER] ratio of txs charged with the gas price signed by the user, during the EGP analytics window",
+		},
+		{
+			Name: EGPProfitMarginName,
+			Help: "[SEQUENCER] ratio between the fees charged and the fees at the effective gas price minus 1, during the EGP analytics window",
+		},
+		{
+			Name: ForcedBatchesPendingName,
+			Help: "[SEQUENCER] forced batches pending to be included in a trusted batch",
+		},
+		{
+			Name: ForcedBatchesOldestPendingWaitName,
+			Help: "[SEQUENCER] time the oldest forced batch pending to be included in a trusted batch has been waiting, in seconds",
+		},
+		{
+			Name: ForcedBatchesOldestPendingTimeLeftName,
+			Help: "[SEQUENCER] time left to the force batch timeout of the oldest forced batch pending to be included in a trusted batch, in seconds",
+		},
+	}
+
+	histograms := []prometheus.HistogramOpts{
+		{
+			Name: ForcedBatchesInclusionTimeName,
+			Help: "[SEQUENCER] time from the forced batch on L1 to its inclusion in a trusted batch, in seconds",
+			// From 1 minute to ~11 days
+			Buckets: prometheus.ExponentialBuckets(60, 2, 15), //nolint:gomnd
+		},
+	}
+
+	metrics.RegisterGauges(gauges...)
+	metrics.RegisterHistograms(histograms...)
+}
+
+// EGPStats sets the gauges of the effective gas price statistics of the EGP analytics window.
+func EGPStats(stats pool.EGPStats) {
+	loss, _ := new(big.Float).SetInt(stats.Loss).Float64()
+	metrics.GaugeSet(EGPTxsName, float64(stats.Txs))
+	metrics.GaugeSet(EGPReprocessedRatioName, stats.Ratio(stats.Reprocessed))
+	metrics.GaugeSet(EGPSuspiciousName, float64(stats.Suspicious))
+	metrics.GaugeSet(EGPErrorsName, float64(stats.Errors))
+	metrics.GaugeSet(EGPLossesName, float64(stats.Losses))
+	metrics.GaugeSet(EGPLossName, loss)
+	metrics.GaugeSet(EGPUsedFirstRatioName, stats.Ratio(stats.UsedFirst))
+	metrics.GaugeSet(EGPUsedSecondRatioName, stats.Ratio(stats.UsedSecond))
+	metrics.GaugeSet(EGPUsedUserRatioName, stats.Ratio(stats.UsedUser))
+	metrics.GaugeSet(EGPProfitMarginName, stats.ProfitMargin())
+}
+
+// ForcedBatchesPending sets the gauges of the forced batches pending to be included in a trusted batch.
+func ForcedBatchesPending(pending int, oldestWait time.Duration, oldestTimeLeft time.Duration) {
+	metrics.GaugeSet(ForcedBatchesPendingName, float64(pending))
+	metrics.GaugeSet(ForcedBatchesOldestPendingWaitName, oldestWait.Seconds())
+	metrics.GaugeSet(ForcedBatchesOldestPendingTimeLeftName, oldestTimeLeft.Seconds())
+}
+
+// ForcedBatchIncluded observes the time a forced batch waited to be included in a trusted batch.
+func ForcedBatchIncluded(wait time.Duration) {
+	metrics.HistogramObserve(ForcedBatchesInclusionTimeName, wait.Seconds())
+}
@@ -12,6 +12,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	types "github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -50,6 +52,34 @@ func (_m *EthermanMock) DepositCount(ctx context.Context, blockNumber *uint64) (
 	return r0, r1
 }
 
+// GetForceBatchTimeout provides a mock function with given fields:
+func (_m *EthermanMock) GetForceBatchTimeout() (time.Duration, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForceBatchTimeout")
+	}
+
+	var r0 time.Duration
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (time.Duration, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLatestBatchNumber provides a mock function with given fields:
 func (_m *EthermanMock) GetLatestBatchNumber() (uint64, error) {
 	ret := _m.Called()
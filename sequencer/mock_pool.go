@@ -75,6 +75,24 @@ func (_m *PoolMock) DeleteTransactionsByHashes(ctx context.Context, hashes []com
 	return r0
 }
 
+// GetCurrentGasPrices provides a mock function with given fields:
+func (_m *PoolMock) GetCurrentGasPrices() pool.GasPrices {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCurrentGasPrices")
+	}
+
+	var r0 pool.GasPrices
+	if rf, ok := ret.Get(0).(func() pool.GasPrices); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(pool.GasPrices)
+	}
+
+	return r0
+}
+
 // GetDefaultMinGasPriceAllowed provides a mock function with given fields:
 func (_m *PoolMock) GetDefaultMinGasPriceAllowed() uint64 {
 	ret := _m.Called()
@@ -151,34 +169,6 @@ func (_m *PoolMock) GetGasPrices(ctx context.Context) (pool.GasPrices, error) {
 	return r0, r1
 }
 
-// GetL1AndL2GasPrice provides a mock function with given fields:
-func (_m *PoolMock) GetL1AndL2GasPrice() (uint64, uint64) {
-	ret := _m.Called()
-
-	if len(ret) == 0 {
-		panic("no return value specified for GetL1AndL2GasPrice")
-	}
-
-	var r0 uint64
-	var r1 uint64
-	if rf, ok := ret.Get(0).(func() (uint64, uint64)); ok {
-		return rf()
-	}
-	if rf, ok := ret.Get(0).(func() uint64); ok {
-		r0 = rf()
-	} else {
-		r0 = ret.Get(0).(uint64)
-	}
-
-	if rf, ok := ret.Get(1).(func() uint64); ok {
-		r1 = rf()
-	} else {
-		r1 = ret.Get(1).(uint64)
-	}
-
-	return r0, r1
-}
-
 // GetNonWIPPendingTxs provides a mock function with given fields: ctx
 func (_m *PoolMock) GetNonWIPPendingTxs(ctx context.Context) ([]pool.Transaction, error) {
 	ret := _m.Called(ctx)
@@ -266,6 +256,26 @@ func (_m *PoolMock) MarkWIPTxsAsPending(ctx context.Context) error {
 	return r0
 }
 
+// SubscribeNewTxs provides a mock function with given fields: ctx, listenDB
+func (_m *PoolMock) SubscribeNewTxs(ctx context.Context, listenDB bool) <-chan struct{} {
+	ret := _m.Called(ctx, listenDB)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeNewTxs")
+	}
+
+	var r0 <-chan struct{}
+	if rf, ok := ret.Get(0).(func(context.Context, bool) <-chan struct{}); ok {
+		r0 = rf(ctx, listenDB)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan struct{})
+		}
+	}
+
+	return r0
+}
+
 // UpdateTxStatus provides a mock function with given fields: ctx, hash, newStatus, isWIP, failedReason
 func (_m *PoolMock) UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus pool.TxStatus, isWIP bool, failedReason *string) error {
 	ret := _m.Called(ctx, hash, newStatus, isWIP, failedReason)
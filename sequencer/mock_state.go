@@ -13,6 +13,10 @@ import (
 	pgx "github.com/jackc/pgx/v4"
 
 	state "github.com/0xPolygonHermez/zkevm-node/state"
+
+	time "time"
+
+	types "github.com/ethereum/go-ethereum/core/types"
 )
 
 // StateMock is an autogenerated mock type for the stateInterface type
@@ -20,6 +24,41 @@ type StateMock struct {
 	mock.Mock
 }
 
+// AcquireSequencerLease provides a mock function with given fields: ctx, holder, duration, dbTx
+func (_m *StateMock) AcquireSequencerLease(ctx context.Context, holder string, duration time.Duration, dbTx pgx.Tx) (uint64, bool, error) {
+	ret := _m.Called(ctx, holder, duration, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcquireSequencerLease")
+	}
+
+	var r0 uint64
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration, pgx.Tx) (uint64, bool, error)); ok {
+		return rf(ctx, holder, duration, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration, pgx.Tx) uint64); ok {
+		r0 = rf(ctx, holder, duration, dbTx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration, pgx.Tx) bool); ok {
+		r1 = rf(ctx, holder, duration, dbTx)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, time.Duration, pgx.Tx) error); ok {
+		r2 = rf(ctx, holder, duration, dbTx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // BeginStateTransaction provides a mock function with given fields: ctx
 func (_m *StateMock) BeginStateTransaction(ctx context.Context) (pgx.Tx, error) {
 	ret := _m.Called(ctx)
@@ -70,6 +109,24 @@ func (_m *StateMock) BuildChangeL2Block(deltaTimestamp uint32, l1InfoTreeIndex u
 	return r0
 }
 
+// CheckSequencerLease provides a mock function with given fields: ctx, holder, term, dbTx
+func (_m *StateMock) CheckSequencerLease(ctx context.Context, holder string, term uint64, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, holder, term, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckSequencerLease")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, pgx.Tx) error); ok {
+		r0 = rf(ctx, holder, term, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CloseBatch provides a mock function with given fields: ctx, receipt, dbTx
 func (_m *StateMock) CloseBatch(ctx context.Context, receipt state.ProcessingReceipt, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, receipt, dbTx)
@@ -314,6 +371,36 @@ func (_m *StateMock) GetDSL2Blocks(ctx context.Context, firstBatchNumber uint64,
 	return r0, r1
 }
 
+// GetDSL2Receipts provides a mock function with given fields: ctx, firstL2Block, lastL2Block, dbTx
+func (_m *StateMock) GetDSL2Receipts(ctx context.Context, firstL2Block uint64, lastL2Block uint64, dbTx pgx.Tx) ([]*types.Receipt, error) {
+	ret := _m.Called(ctx, firstL2Block, lastL2Block, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDSL2Receipts")
+	}
+
+	var r0 []*types.Receipt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) ([]*types.Receipt, error)); ok {
+		return rf(ctx, firstL2Block, lastL2Block, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) []*types.Receipt); ok {
+		r0 = rf(ctx, firstL2Block, lastL2Block, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Receipt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, firstL2Block, lastL2Block, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDSL2Transactions provides a mock function with given fields: ctx, firstL2Block, lastL2Block, dbTx
 func (_m *StateMock) GetDSL2Transactions(ctx context.Context, firstL2Block uint64, lastL2Block uint64, dbTx pgx.Tx) ([]*state.DSL2Transaction, error) {
 	ret := _m.Called(ctx, firstL2Block, lastL2Block, dbTx)
@@ -820,6 +907,36 @@ func (_m *StateMock) GetNotCheckedBatches(ctx context.Context, dbTx pgx.Tx) ([]*
 	return r0, r1
 }
 
+// GetSequencerHalt provides a mock function with given fields: ctx, dbTx
+func (_m *StateMock) GetSequencerHalt(ctx context.Context, dbTx pgx.Tx) (*state.SequencerHalt, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSequencerHalt")
+	}
+
+	var r0 *state.SequencerHalt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (*state.SequencerHalt, error)); ok {
+		return rf(ctx, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) *state.SequencerHalt); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.SequencerHalt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStorageAt provides a mock function with given fields: ctx, address, position, root
 func (_m *StateMock) GetStorageAt(ctx context.Context, address common.Address, position *big.Int, root common.Hash) (*big.Int, error) {
 	ret := _m.Called(ctx, address, position, root)
@@ -1018,6 +1135,98 @@ func (_m *StateMock) ProcessBatchV2(ctx context.Context, request state.ProcessRe
 	return r0, r1, r2
 }
 
+// ReleaseSequencerLease provides a mock function with given fields: ctx, holder, term, dbTx
+func (_m *StateMock) ReleaseSequencerLease(ctx context.Context, holder string, term uint64, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, holder, term, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseSequencerLease")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, pgx.Tx) error); ok {
+		r0 = rf(ctx, holder, term, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RenewSequencerLease provides a mock function with given fields: ctx, holder, term, duration, dbTx
+func (_m *StateMock) RenewSequencerLease(ctx context.Context, holder string, term uint64, duration time.Duration, dbTx pgx.Tx) (bool, error) {
+	ret := _m.Called(ctx, holder, term, duration, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenewSequencerLease")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, time.Duration, pgx.Tx) (bool, error)); ok {
+		return rf(ctx, holder, term, duration, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, time.Duration, pgx.Tx) bool); ok {
+		r0 = rf(ctx, holder, term, duration, dbTx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint64, time.Duration, pgx.Tx) error); ok {
+		r1 = rf(ctx, holder, term, duration, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetForceBatchTimeout provides a mock function with given fields: ctx, timeout, dbTx
+func (_m *StateMock) SetForceBatchTimeout(ctx context.Context, timeout time.Duration, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, timeout, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetForceBatchTimeout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration, pgx.Tx) error); ok {
+		r0 = rf(ctx, timeout, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetSequencerHalted provides a mock function with given fields: ctx, dbTx
+func (_m *StateMock) SetSequencerHalted(ctx context.Context, dbTx pgx.Tx) (bool, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetSequencerHalted")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (bool, error)); ok {
+		return rf(ctx, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) bool); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // StoreL2Block provides a mock function with given fields: ctx, batchNumber, l2Block, txsEGPLog, dbTx
 func (_m *StateMock) StoreL2Block(ctx context.Context, batchNumber uint64, l2Block *state.ProcessBlockResponse, txsEGPLog []*state.EffectiveGasPriceLog, dbTx pgx.Tx) (common.Hash, error) {
 	ret := _m.Called(ctx, batchNumber, l2Block, txsEGPLog, dbTx)
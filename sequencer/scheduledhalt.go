@@ -0,0 +1,102 @@
+package sequencer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+// updateScheduledHalt reads from the state the halt of the sequencer scheduled through the admin API
+func (f *finalizer) updateScheduledHalt(ctx context.Context) {
+	halt, err := f.stateIntf.GetSequencerHalt(ctx, nil)
+	if errors.Is(err, state.ErrNotFound) {
+		halt = nil
+	} else if err != nil {
+		// Keep using the last scheduled halt read
+		log.Errorf("failed to get scheduled sequencer halt, error: %v", err)
+		return
+	}
+
+	f.scheduledHaltMux.Lock()
+	defer f.scheduledHaltMux.Unlock()
+	f.scheduledHalt = halt
+}
+
+// checkScheduledHalt updates the scheduled halt every ScheduledHaltCheckInterval until the context is done
+func (f *finalizer) checkScheduledHalt(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(f.cfg.ScheduledHaltCheckInterval.Duration):
+			f.updateScheduledHalt(ctx)
+		}
+	}
+}
+
+// getScheduledHaltReached returns the scheduled halt if it has been reached and the sequencer must halt before opening the batch nextBatchNumber
+func (f *finalizer) getScheduledHaltReached(nextBatchNumber uint64) (*state.SequencerHalt, bool) {
+	f.scheduledHaltMux.Lock()
+	halt := f.scheduledHalt
+	f.scheduledHaltMux.Unlock()
+
+	if halt == nil || !halt.IsReached(nextBatchNumber, now()) {
+		return nil, false
+	}
+	return halt, true
+}
+
+// haltScheduled halts the finalizer for maintenance before opening the batch nextBatchNumber, blocking until the halt is resumed
+// through the admin API. While the sequencer is halted the pool doesn't accept new txs. It returns the context error if the
+// context is done before the halt is resumed
+func (f *finalizer) haltScheduled(ctx context.Context, halt *state.SequencerHalt, nextBatchNumber uint64) error {
+	// The halt is kept scheduled until it's set as halted in the state, otherwise the pool would keep accepting new txs
+	for {
+		_, err := f.stateIntf.SetSequencerHalted(ctx, nil)
+		if err == nil {
+			break
+		}
+		log.Errorf("failed to set sequencer as halted, retrying in %v, error: %v", f.cfg.ScheduledHaltCheckInterval.Duration, err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(f.cfg.ScheduledHaltCheckInterval.Duration):
+		}
+	}
+
+	log.Warnf("finalizer halted for maintenance before opening batch %d, reason: %s", nextBatchNumber, halt.Reason)
+	f.LogEvent(ctx, event.Level_Warning, event.EventID_FinalizerHalt,
+		fmt.Sprintf("finalizer halted for maintenance before opening batch %d, reason: %s", nextBatchNumber, halt.Reason), nil)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(f.cfg.ScheduledHaltCheckInterval.Duration):
+		}
+
+		f.updateScheduledHalt(ctx)
+		if _, halted := f.getScheduledHaltReached(nextBatchNumber); !halted {
+			break
+		}
+		log.Infof("finalizer halted for maintenance, reason: %s", halt.Reason)
+	}
+
+	f.scheduledHaltMux.Lock()
+	resumeReason := ""
+	if f.scheduledHalt != nil {
+		resumeReason = f.scheduledHalt.Reason
+	}
+	f.scheduledHaltMux.Unlock()
+
+	log.Infof("finalizer resumed after maintenance halt, reason: %s", resumeReason)
+	f.LogEvent(ctx, event.Level_Info, event.EventID_FinalizerRestart,
+		fmt.Sprintf("finalizer resumed after maintenance halt before opening batch %d, reason: %s", nextBatchNumber, resumeReason), nil)
+	return nil
+}
@@ -0,0 +1,137 @@
+package sequencer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cfgTypes "github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinalizer_checkIfFinalizeBatchScheduledHalt(t *testing.T) {
+	now = testNow
+	defer func() {
+		now = time.Now
+	}()
+	pastTime := testNow().Add(-time.Minute)
+	futureTime := testNow().Add(time.Minute)
+
+	testCases := []struct {
+		name                string
+		halt                *state.SequencerHalt
+		emptyBatch          bool
+		expected            bool
+		expectedCloseReason state.ClosingReason
+	}{
+		{
+			name: "No halt scheduled",
+		},
+		{
+			name: "Halt batch number not reached",
+			halt: &state.SequencerHalt{BatchNumber: 2, Status: state.SequencerHaltScheduled},
+		},
+		{
+			name:                "Halt batch number already opened",
+			halt:                &state.SequencerHalt{BatchNumber: 1, Status: state.SequencerHaltScheduled},
+			expected:            true,
+			expectedCloseReason: state.ScheduledHaltClosingReason,
+		},
+		{
+			name: "Halt time not reached",
+			halt: &state.SequencerHalt{HaltAt: &futureTime, Status: state.SequencerHaltScheduled},
+		},
+		{
+			name:                "Halt time reached",
+			halt:                &state.SequencerHalt{HaltAt: &pastTime, Status: state.SequencerHaltScheduled},
+			expected:            true,
+			expectedCloseReason: state.ScheduledHaltClosingReason,
+		},
+		{
+			name:       "Halt time reached with empty batch",
+			halt:       &state.SequencerHalt{HaltAt: &pastTime, Status: state.SequencerHaltScheduled},
+			emptyBatch: true,
+		},
+		{
+			name: "Halt resumed",
+			halt: &state.SequencerHalt{HaltAt: &pastTime, Status: state.SequencerHaltResumed},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f = setupFinalizer(true)
+			f.cfg.BatchMaxDeltaTimestamp = cfgTypes.NewDuration(time.Hour)
+			f.wipBatch.timestamp = time.Now()
+			f.wipL2Block = &L2Block{}
+			if !tc.emptyBatch {
+				f.wipBatch.countOfL2Blocks = 1
+			}
+			f.scheduledHalt = tc.halt
+
+			actual, closeReason := f.checkIfFinalizeBatch()
+
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, tc.expectedCloseReason, closeReason)
+		})
+	}
+}
+
+func TestFinalizer_haltScheduled(t *testing.T) {
+	ctx := context.Background()
+	f = setupFinalizer(true)
+	f.cfg.ScheduledHaltCheckInterval = cfgTypes.NewDuration(time.Millisecond)
+
+	halt := &state.SequencerHalt{BatchNumber: 2, Status: state.SequencerHaltScheduled, Reason: "fork upgrade"}
+	f.scheduledHalt = halt
+
+	// setting the sequencer as halted is retried until it succeeds
+	stateMock.On("SetSequencerHalted", ctx, nil).Return(false, errors.New("db error")).Once()
+	stateMock.On("SetSequencerHalted", ctx, nil).Return(true, nil).Once()
+	stateMock.On("GetSequencerHalt", ctx, nil).Return(&state.SequencerHalt{BatchNumber: 2, Status: state.SequencerHaltHalted, Reason: "fork upgrade"}, nil).Twice()
+	stateMock.On("GetSequencerHalt", ctx, nil).Return(&state.SequencerHalt{BatchNumber: 2, Status: state.SequencerHaltResumed, Reason: "upgrade done"}, nil).Once()
+
+	done := make(chan struct{})
+	go func() {
+		assert.NoError(t, f.haltScheduled(ctx, halt, 2))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("finalizer not resumed")
+	}
+
+	stateMock.AssertExpectations(t)
+	_, reached := f.getScheduledHaltReached(2)
+	assert.False(t, reached)
+	assert.Equal(t, "upgrade done", f.scheduledHalt.Reason)
+}
+
+func TestFinalizer_haltScheduledContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	f = setupFinalizer(true)
+	f.cfg.ScheduledHaltCheckInterval = cfgTypes.NewDuration(time.Millisecond)
+
+	halt := &state.SequencerHalt{BatchNumber: 2, Status: state.SequencerHaltScheduled, Reason: "fork upgrade"}
+	f.scheduledHalt = halt
+
+	stateMock.On("SetSequencerHalted", ctx, nil).Return(true, nil).Once()
+	stateMock.On("GetSequencerHalt", ctx, nil).Return(&state.SequencerHalt{BatchNumber: 2, Status: state.SequencerHaltHalted, Reason: "fork upgrade"}, nil).Maybe()
+
+	done := make(chan error)
+	go func() {
+		done <- f.haltScheduled(ctx, halt, 2)
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("finalizer not stopped")
+	}
+}
@@ -38,11 +38,17 @@ type Sequencer struct {
 	streamServer *datastreamer.StreamServer
 	dataToStream chan interface{}
 
-	numberOfStateInconsistencies uint64
+	leader *leaderElector
+	// activeWG waits for the goroutines started while the sequencer is active
+	activeWG sync.WaitGroup
 }
 
 // New init sequencer
 func New(cfg Config, batchCfg state.BatchConfig, poolCfg pool.Config, txPool txPool, stateIntf stateInterface, etherman ethermanInterface, eventLog *event.EventLog) (*Sequencer, error) {
+	if cfg.HA.Enabled && cfg.HA.RenewInterval.Duration >= cfg.HA.LeaseDuration.Duration {
+		return nil, fmt.Errorf("the renew interval of the sequencer lease (%v) must be lower than its duration (%v)", cfg.HA.RenewInterval.Duration, cfg.HA.LeaseDuration.Duration)
+	}
+
 	sequencer := &Sequencer{
 		cfg:       cfg,
 		batchCfg:  batchCfg,
@@ -53,8 +59,6 @@ func New(cfg Config, batchCfg state.BatchConfig, poolCfg pool.Config, txPool txP
 		eventLog:  eventLog,
 	}
 
-	sequencer.dataToStream = make(chan interface{}, datastreamChannelBufferSize)
-
 	return sequencer, nil
 }
 
@@ -65,10 +69,7 @@ func (s *Sequencer) Start(ctx context.Context) {
 		time.Sleep(time.Second)
 	}
 
-	err := s.pool.MarkWIPTxsAsPending(ctx)
-	if err != nil {
-		log.Fatalf("failed to mark wip txs as pending, error: %v", err)
-	}
+	var err error
 
 	// Start stream server if enabled
 	if s.cfg.StreamServer.Enabled {
@@ -81,35 +82,110 @@ func (s *Sequencer) Start(ctx context.Context) {
 		if err != nil {
 			log.Fatalf("failed to start stream server, error: %v", err)
 		}
+	}
 
-		s.updateDataStreamerFile(ctx, s.cfg.StreamServer.ChainID)
+	if s.cfg.HA.Enabled {
+		s.leader = newLeaderElector(s.cfg.HA, s.stateIntf)
+	}
+
+	for {
+		// In active/standby mode wait in standby until the sequencer lease is acquired
+		if s.leader != nil {
+			err = s.leader.waitForLeadership(ctx, s.standby)
+			if err != nil {
+				log.Infof("sequencer stopped in standby, error: %v", err)
+				return
+			}
+			s.logEvent(ctx, event.Level_Notice, event.EventID_SequencerLeaseAcquired, fmt.Sprintf("sequencer %s is active, lease term: %d", s.leader.nodeID, s.leader.term))
+		}
+
+		// The lease is renewed until the sequencer has stopped loading txs from the pool, so the next active sequencer
+		// doesn't find txs marked as WIP by this one
+		leaseCtx, cancelLease := context.WithCancel(context.WithoutCancel(ctx))
+		var leaseWG sync.WaitGroup
+		if s.leader != nil {
+			leaseWG.Add(1)
+			go func() {
+				defer leaseWG.Done()
+				s.leader.renewLease(leaseCtx, s.leader.setLost)
+			}()
+		}
+
+		activeCtx, cancelActive := context.WithCancel(ctx)
+		s.startActive(activeCtx)
+		lostErr := s.waitForLeaseLost(ctx)
+
+		cancelActive()
+		s.activeWG.Wait()
+		cancelLease()
+		leaseWG.Wait()
+
+		if lostErr == nil {
+			return
+		}
+		s.logEvent(ctx, event.Level_Critical, event.EventID_SequencerLeaseLost, fmt.Sprintf("sequencer %s lost the lease, back to standby, error: %v", s.leader.nodeID, lostErr))
+		log.Warnf("sequencer %s lost the lease, back to standby, error: %v", s.leader.nodeID, lostErr)
+	}
+}
+
+// startActive starts the finalizer and the goroutines that feed it with the txs of the pool. They run until ctx is done
+func (s *Sequencer) startActive(ctx context.Context) {
+	// Txs marked as WIP by this sequencer before restarting, or by the previous active sequencer, are loaded again from the pool
+	err := s.pool.MarkWIPTxsAsPending(ctx)
+	if err != nil {
+		log.Fatalf("failed to mark wip txs as pending, error: %v", err)
 	}
 
 	if s.streamServer != nil {
-		go s.sendDataToStreamer(s.cfg.StreamServer.ChainID, s.cfg.StreamServer.Version)
+		s.updateDataStreamerFile(ctx, s.cfg.StreamServer.ChainID)
 	}
 
+	s.dataToStream = make(chan interface{}, datastreamChannelBufferSize)
 	s.workerReadyTxsCond = newTimeoutCond(&sync.Mutex{})
-	s.worker = NewWorker(s.stateIntf, s.batchCfg.Constraints, s.workerReadyTxsCond)
-	s.finalizer = newFinalizer(s.cfg.Finalizer, s.poolCfg, s.worker, s.pool, s.stateIntf, s.etherman, s.cfg.L2Coinbase, s.isSynced, s.batchCfg.Constraints, s.eventLog, s.streamServer, s.workerReadyTxsCond, s.dataToStream)
+	s.worker = NewWorker(s.stateIntf, s.batchCfg.Constraints, s.poolCfg.PrivateMempool.Enabled, s.poolCfg.PrivateMempool.SlotDuration.Duration, s.workerReadyTxsCond)
+	s.finalizer = newFinalizer(s.cfg.Finalizer, s.poolCfg, s.worker, s.pool, s.stateIntf, s.etherman, s.cfg.L2Coinbase, s.isSynced, s.batchCfg.Constraints, s.eventLog, s.streamServer, s.workerReadyTxsCond, s.dataToStream, s.leader)
 	go s.finalizer.Start(ctx)
 
-	go s.loadFromPool(ctx)
+	if s.streamServer != nil {
+		s.goActive(func() { s.sendDataToStreamer(ctx, s.cfg.StreamServer.ChainID, s.cfg.StreamServer.Version) })
+	}
 
-	go s.deleteOldPoolTxs(ctx)
+	s.goActive(func() { s.loadFromPool(ctx) })
 
-	go s.expireOldWorkerTxs(ctx)
+	s.goActive(func() { s.deleteOldPoolTxs(ctx) })
 
-	go s.checkStateInconsistency(ctx)
+	s.goActive(func() { s.expireOldWorkerTxs(ctx) })
 
-	// Wait until context is done
-	<-ctx.Done()
+	// It isn't waited for when leaving the active state, the finalizer is halted forever if an inconsistency is detected
+	go s.checkStateInconsistency(ctx, s.finalizer)
 }
 
-// checkStateInconsistency checks if state inconsistency happened
-func (s *Sequencer) checkStateInconsistency(ctx context.Context) {
-	var err error
-	s.numberOfStateInconsistencies, err = s.stateIntf.CountReorgs(ctx, nil)
+// goActive runs f in a goroutine that must end before the sequencer leaves the active state
+func (s *Sequencer) goActive(f func()) {
+	s.activeWG.Add(1)
+	go func() {
+		defer s.activeWG.Done()
+		f()
+	}()
+}
+
+// waitForLeaseLost blocks until the sequencer lease is lost, returning the reason, or until ctx is done, returning nil
+func (s *Sequencer) waitForLeaseLost(ctx context.Context) error {
+	if s.leader == nil {
+		<-ctx.Done()
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-s.leader.lost:
+		return err
+	}
+}
+
+// checkStateInconsistency checks if state inconsistency happened, halting f
+func (s *Sequencer) checkStateInconsistency(ctx context.Context, f *finalizer) {
+	numberOfStateInconsistencies, err := s.stateIntf.CountReorgs(ctx, nil)
 	if err != nil {
 		log.Error("failed to get initial number of reorgs, error: %v", err)
 	}
@@ -120,15 +196,51 @@ func (s *Sequencer) checkStateInconsistency(ctx context.Context) {
 			return
 		}
 
-		if stateInconsistenciesDetected != s.numberOfStateInconsistencies {
-			s.finalizer.Halt(ctx, fmt.Errorf("state inconsistency detected, halting finalizer"), false)
+		if stateInconsistenciesDetected != numberOfStateInconsistencies {
+			f.Halt(ctx, fmt.Errorf("state inconsistency detected, halting finalizer"), false)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.StateConsistencyCheckInterval.Duration):
 		}
+	}
+}
 
-		time.Sleep(s.cfg.StateConsistencyCheckInterval.Duration)
+// standby keeps the data stream file updated with the L2 blocks stored by the active sequencer while waiting
+// to acquire the sequencer lease
+func (s *Sequencer) standby(ctx context.Context) {
+	if s.streamServer == nil {
+		return
+	}
+	err := state.GenerateDataStreamFile(ctx, s.streamServer, s.stateIntf, true, nil, s.cfg.StreamServer.ChainID, s.cfg.StreamServer.UpgradeEtrogBatchNumber, s.cfg.StreamServer.Version)
+	if err != nil {
+		log.Errorf("failed to update data streamer file in standby, error: %v", err)
+	}
+}
+
+// logEvent adds an event of the sequencer
+func (s *Sequencer) logEvent(ctx context.Context, level event.Level, eventId event.EventID, description string) {
+	ev := &event.Event{
+		ReceivedAt:  time.Now(),
+		Source:      event.Source_Node,
+		Component:   event.Component_Sequencer,
+		Level:       level,
+		EventID:     eventId,
+		Description: description,
+	}
+	err := s.eventLog.LogEvent(ctx, ev)
+	if err != nil {
+		log.Errorf("error storing log event, error: %v", err)
 	}
 }
 
 func (s *Sequencer) updateDataStreamerFile(ctx context.Context, chainID uint64) {
+	if s.cfg.StreamServer.VerifyBatches > 0 {
+		s.verifyDataStreamerFile(ctx)
+	}
+
 	err := state.GenerateDataStreamFile(ctx, s.streamServer, s.stateIntf, true, nil, chainID, s.cfg.StreamServer.UpgradeEtrogBatchNumber, s.cfg.StreamServer.Version)
 	if err != nil {
 		log.Fatalf("failed to generate data streamer file, error: %v", err)
@@ -136,9 +248,39 @@ func (s *Sequencer) updateDataStreamerFile(ctx context.Context, chainID uint64)
 	log.Info("data streamer file updated")
 }
 
+// verifyDataStreamerFile checks the latest batches of the data stream file against the state, truncating the file
+// from the first batch that doesn't match so it's regenerated from that batch
+func (s *Sequencer) verifyDataStreamerFile(ctx context.Context) {
+	fromEntry, err := state.GetDSLatestBatchesEntryNumber(s.streamServer, s.cfg.StreamServer.VerifyBatches)
+	if err != nil {
+		log.Fatalf("failed to get the latest batches of the data streamer file, error: %v", err)
+	}
+
+	mismatch, err := state.VerifyDataStreamFile(ctx, s.streamServer, s.stateIntf, fromEntry)
+	if err != nil {
+		log.Fatalf("failed to verify data streamer file, error: %v", err)
+	}
+	if mismatch == nil {
+		log.Infof("data streamer file verified from entry %d", fromEntry)
+		return
+	}
+
+	s.logEvent(ctx, event.Level_Warning, event.EventID_DataStreamMismatch, fmt.Sprintf("data streamer file doesn't match the state at %s, truncating it from entry %d", mismatch, mismatch.TruncateEntryNumber))
+	log.Warnf("data streamer file doesn't match the state at %s, truncating it from entry %d", mismatch, mismatch.TruncateEntryNumber)
+
+	err = s.streamServer.TruncateFile(mismatch.TruncateEntryNumber)
+	if err != nil {
+		log.Fatalf("failed to truncate data streamer file from entry %d, error: %v", mismatch.TruncateEntryNumber, err)
+	}
+}
+
 func (s *Sequencer) deleteOldPoolTxs(ctx context.Context) {
 	for {
-		time.Sleep(s.cfg.DeletePoolTxsCheckInterval.Duration)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.DeletePoolTxsCheckInterval.Duration):
+		}
 
 		if s.finalizer.haltFinalizer.Load() {
 			return
@@ -177,7 +319,11 @@ func (s *Sequencer) deleteOldPoolTxs(ctx context.Context) {
 
 func (s *Sequencer) expireOldWorkerTxs(ctx context.Context) {
 	for {
-		time.Sleep(s.cfg.TxLifetimeCheckInterval.Duration)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.TxLifetimeCheckInterval.Duration):
+		}
 
 		if s.finalizer.haltFinalizer.Load() {
 			return
@@ -194,8 +340,9 @@ func (s *Sequencer) expireOldWorkerTxs(ctx context.Context) {
 	}
 }
 
-// loadFromPool keeps loading transactions from the pool
+// loadFromPool keeps loading transactions from the pool, waiting to be notified of new transactions when there are none
 func (s *Sequencer) loadFromPool(ctx context.Context) {
+	newTxs := s.pool.SubscribeNewTxs(ctx, s.cfg.ListenPoolTxsNotifications)
 	for {
 		if s.finalizer.haltFinalizer.Load() {
 			return
@@ -214,7 +361,12 @@ func (s *Sequencer) loadFromPool(ctx context.Context) {
 		}
 
 		if len(poolTransactions) == 0 {
-			time.Sleep(s.cfg.LoadPoolTxsCheckInterval.Duration)
+			select {
+			case <-ctx.Done():
+				return
+			case <-newTxs:
+			case <-time.After(s.cfg.LoadPoolTxsCheckInterval.Duration):
+			}
 		}
 	}
 }
@@ -224,6 +376,7 @@ func (s *Sequencer) addTxToWorker(ctx context.Context, tx pool.Transaction) erro
 	if err != nil {
 		return err
 	}
+	txTracker.PoolReceivedAt = tx.ReceivedAt
 	replacedTx, dropReason := s.worker.AddTxTracker(ctx, txTracker)
 	if dropReason != nil {
 		failedReason := dropReason.Error()
@@ -240,8 +393,8 @@ func (s *Sequencer) addTxToWorker(ctx context.Context, tx pool.Transaction) erro
 	}
 }
 
-// sendDataToStreamer sends data to the data stream server
-func (s *Sequencer) sendDataToStreamer(chainID uint64, version uint8) {
+// sendDataToStreamer sends data to the data stream server until ctx is done
+func (s *Sequencer) sendDataToStreamer(ctx context.Context, chainID uint64, version uint8) {
 	var err error
 	for {
 		// Read error from previous iteration
@@ -254,7 +407,12 @@ func (s *Sequencer) sendDataToStreamer(chainID uint64, version uint8) {
 		}
 
 		// Read data from channel
-		dataStream := <-s.dataToStream
+		var dataStream interface{}
+		select {
+		case <-ctx.Done():
+			return
+		case dataStream = <-s.dataToStream:
+		}
 
 		s.finalizer.DataToStreamChannelCountAdd(-1)
 
@@ -367,6 +525,20 @@ func (s *Sequencer) sendDataToStreamer(chainID uint64, version uint8) {
 						log.Errorf("failed to add l2tx stream entry for l2block %d, error: %v", l2Block.L2BlockNumber, err)
 						continue
 					}
+
+					if version >= state.DSVersion5 && l2Transaction.Receipt != nil {
+						marshalledL2Receipt, err := proto.Marshal(state.NewDSReceipt(l2Transaction.L2BlockNumber, l2Transaction.Index, l2Transaction.Receipt))
+						if err != nil {
+							log.Errorf("failed to marshal l2tx receipt for l2block %d, error: %v", l2Block.L2BlockNumber, err)
+							continue
+						}
+
+						_, err = s.streamServer.AddStreamEntry(datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_RECEIPT), marshalledL2Receipt)
+						if err != nil {
+							log.Errorf("failed to add l2tx receipt stream entry for l2block %d, error: %v", l2Block.L2BlockNumber, err)
+							continue
+						}
+					}
 				}
 
 				if version >= state.DSVersion4 {
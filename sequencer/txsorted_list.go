@@ -4,22 +4,27 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/log"
 )
 
-// txSortedList represents a list of tx sorted by gasPrice
+// txSortedList represents a list of tx sorted by gasPrice, or by time slot and arrival time to the pool if firstComeFirstServed is set
 type txSortedList struct {
-	list   map[string]*TxTracker
-	sorted []*TxTracker
-	mutex  sync.Mutex
+	list                 map[string]*TxTracker
+	sorted               []*TxTracker
+	firstComeFirstServed bool
+	slotDuration         time.Duration
+	mutex                sync.Mutex
 }
 
 // newTxSortedList creates and init an txSortedList
-func newTxSortedList() *txSortedList {
+func newTxSortedList(firstComeFirstServed bool, slotDuration time.Duration) *txSortedList {
 	return &txSortedList{
-		list:   make(map[string]*TxTracker),
-		sorted: []*TxTracker{},
+		list:                 make(map[string]*TxTracker),
+		sorted:               []*TxTracker{},
+		firstComeFirstServed: firstComeFirstServed,
+		slotDuration:         slotDuration,
 	}
 }
 
@@ -47,18 +52,18 @@ func (e *txSortedList) delete(tx *TxTracker) bool {
 			return e.isGreaterOrEqualThan(tx, e.list[e.sorted[i].HashStr])
 		})
 
-		// i is the index of the first tx that has equal (or lower) gasPrice than the tx. From here we need to go down in the list
+		// i is the index of the first tx that has equal (or lower) priority than the tx. From here we need to go down in the list
 		// looking for the sorted[i].HashStr equal to tx.HashStr to get the index of tx in the sorted slice.
-		// We need to go down until we find the tx or we have a tx with different (lower) gasPrice or we reach the end of the list
+		// We need to go down until we find the tx or we have a tx with different (lower) priority or we reach the end of the list
 		for {
 			if i == sLen {
 				log.Warnf("error deleting tx %s from txSortedList, we reach the end of the list", tx.HashStr)
 				return false
 			}
 
-			if (e.sorted[i].GasPrice.Cmp(tx.GasPrice)) != 0 {
-				// we have a tx with different (lower) GasPrice than the tx we are looking for, therefore we haven't found the tx
-				log.Warnf("error deleting tx %s from txSortedList, not found in the list of txs with same gasPrice: %s", tx.HashStr)
+			if e.compare(e.sorted[i], tx) != 0 {
+				// we have a tx with different (lower) priority than the tx we are looking for, therefore we haven't found the tx
+				log.Warnf("error deleting tx %s from txSortedList, not found in the list of txs with same priority", tx.HashStr)
 				return false
 			}
 
@@ -123,9 +128,38 @@ func (e *txSortedList) addSort(tx *TxTracker) {
 	log.Debugf("added tx %s with  gasPrice %d to txSortedList at index %d from total %d", tx.HashStr, tx.GasPrice, i, len(e.sorted))
 }
 
-// isGreaterThan returns true if the tx1 has greater gasPrice than tx2
+// compare returns 1 if tx1 has greater priority than tx2, -1 if it has lower priority and 0 if both have the same priority.
+// The priority is the gasPrice, or if firstComeFirstServed is set the time slot of the arrival to the pool and then the
+// arrival time within the slot (the earlier the greater)
+func (e *txSortedList) compare(tx1 *TxTracker, tx2 *TxTracker) int {
+	if e.firstComeFirstServed {
+		slot1, slot2 := e.slot(tx1), e.slot(tx2)
+		if slot1 < slot2 {
+			return 1
+		} else if slot1 > slot2 {
+			return -1
+		}
+		if tx1.PoolReceivedAt.Before(tx2.PoolReceivedAt) {
+			return 1
+		} else if tx1.PoolReceivedAt.After(tx2.PoolReceivedAt) {
+			return -1
+		}
+		return 0
+	}
+	return tx1.GasPrice.Cmp(tx2.GasPrice)
+}
+
+// slot returns the time slot in which the tx was received by the pool. If the slot duration is 0 each arrival time is a slot
+func (e *txSortedList) slot(tx *TxTracker) int64 {
+	if e.slotDuration <= 0 {
+		return tx.PoolReceivedAt.UnixNano()
+	}
+	return tx.PoolReceivedAt.UnixNano() / int64(e.slotDuration)
+}
+
+// isGreaterThan returns true if the tx1 has greater priority than tx2
 func (e *txSortedList) isGreaterThan(tx1 *TxTracker, tx2 *TxTracker) bool {
-	cmp := tx1.GasPrice.Cmp(tx2.GasPrice)
+	cmp := e.compare(tx1, tx2)
 	if cmp == 1 {
 		return true
 	} else {
@@ -133,9 +167,9 @@ func (e *txSortedList) isGreaterThan(tx1 *TxTracker, tx2 *TxTracker) bool {
 	}
 }
 
-// isGreaterOrEqualThan returns true if the tx1 has greater or equal gasPrice than tx2
+// isGreaterOrEqualThan returns true if the tx1 has greater or equal priority than tx2
 func (e *txSortedList) isGreaterOrEqualThan(tx1 *TxTracker, tx2 *TxTracker) bool {
-	cmp := tx1.GasPrice.Cmp(tx2.GasPrice)
+	cmp := e.compare(tx1, tx2)
 	if cmp >= 0 {
 		return true
 	} else {
@@ -25,7 +25,7 @@ func randomBigInt() *big.Int {
 }
 
 func TestTxSortedList(t *testing.T) {
-	el := newTxSortedList()
+	el := newTxSortedList(false, 0)
 	nItems := 100
 
 	for i := 0; i < nItems; i++ {
@@ -46,7 +46,7 @@ func TestTxSortedList(t *testing.T) {
 }
 
 func TestTxSortedListDelete(t *testing.T) {
-	el := newTxSortedList()
+	el := newTxSortedList(false, 0)
 
 	el.add(&TxTracker{HashStr: "0x01", GasPrice: new(big.Int).SetInt64(10)})
 	el.add(&TxTracker{HashStr: "0x02", GasPrice: new(big.Int).SetInt64(20)})
@@ -87,8 +87,66 @@ func TestTxSortedListDelete(t *testing.T) {
 	}
 }
 
+func TestTxSortedListFirstComeFirstServed(t *testing.T) {
+	el := newTxSortedList(true, 0)
+
+	receivedAt := time.Unix(1000000, 0)
+	el.add(&TxTracker{HashStr: "0x01", GasPrice: new(big.Int).SetInt64(10), PoolReceivedAt: receivedAt.Add(2 * time.Second)})
+	el.add(&TxTracker{HashStr: "0x02", GasPrice: new(big.Int).SetInt64(100), PoolReceivedAt: receivedAt.Add(3 * time.Second)})
+	el.add(&TxTracker{HashStr: "0x03", GasPrice: new(big.Int).SetInt64(1), PoolReceivedAt: receivedAt})
+	el.add(&TxTracker{HashStr: "0x04", GasPrice: new(big.Int).SetInt64(50), PoolReceivedAt: receivedAt.Add(2 * time.Second)})
+	el.add(&TxTracker{HashStr: "0x05", GasPrice: new(big.Int).SetInt64(20), PoolReceivedAt: receivedAt.Add(time.Second)})
+
+	// The txs are sorted by arrival to the pool regardless of their gasPrice
+	sort := []string{"0x03", "0x05", "0x01", "0x04", "0x02"}
+
+	for index, tx := range el.sorted {
+		if sort[index] != tx.HashStr {
+			t.Fatalf("Sort error. Expected %s, Actual %s", sort[index], tx.HashStr)
+		}
+	}
+
+	if !el.delete(&TxTracker{HashStr: "0x04"}) {
+		t.Fatal("Delete error. 0x04 tx was not deleted")
+	}
+
+	sort = []string{"0x03", "0x05", "0x01", "0x02"}
+
+	for index, tx := range el.sorted {
+		if sort[index] != tx.HashStr {
+			t.Fatalf("Sort error. Expected %s, Actual %s", sort[index], tx.HashStr)
+		}
+	}
+}
+
+func TestTxSortedListSlots(t *testing.T) {
+	el := newTxSortedList(true, 2*time.Second)
+
+	receivedAt := time.Unix(1000000, 0)
+	el.add(&TxTracker{HashStr: "0x01", GasPrice: new(big.Int).SetInt64(10), PoolReceivedAt: receivedAt.Add(2500 * time.Millisecond)})
+	el.add(&TxTracker{HashStr: "0x02", GasPrice: new(big.Int).SetInt64(100), PoolReceivedAt: receivedAt.Add(1500 * time.Millisecond)})
+	el.add(&TxTracker{HashStr: "0x03", GasPrice: new(big.Int).SetInt64(1), PoolReceivedAt: receivedAt.Add(500 * time.Millisecond)})
+	el.add(&TxTracker{HashStr: "0x04", GasPrice: new(big.Int).SetInt64(50), PoolReceivedAt: receivedAt.Add(2 * time.Second)})
+
+	if el.slot(el.list["0x02"]) != el.slot(el.list["0x03"]) || el.slot(el.list["0x01"]) != el.slot(el.list["0x04"]) {
+		t.Fatal("Slot error. Txs received in the same slot have different slots")
+	}
+	if el.slot(el.list["0x03"]) >= el.slot(el.list["0x04"]) {
+		t.Fatal("Slot error. Txs received in a later slot must have a greater slot")
+	}
+
+	// The txs are sorted by slot and then by arrival within the slot
+	sort := []string{"0x03", "0x02", "0x04", "0x01"}
+
+	for index, tx := range el.sorted {
+		if sort[index] != tx.HashStr {
+			t.Fatalf("Sort error. Expected %s, Actual %s", sort[index], tx.HashStr)
+		}
+	}
+}
+
 func TestTxSortedListBench(t *testing.T) {
-	el := newTxSortedList()
+	el := newTxSortedList(false, 0)
 
 	start := time.Now()
 	for i := 0; i < 10000; i++ {
@@ -24,6 +24,7 @@ type TxTracker struct {
 	ReservedZKCounters state.ZKCounters
 	RawTx              []byte
 	ReceivedAt         time.Time // To check if it has been in the txSortedList for too long
+	PoolReceivedAt     time.Time // Time the tx was received by the pool, to sort the txs by arrival in private mempool mode
 	IP                 string    // IP of the tx sender
 	FailedReason       *string   // FailedReason is the reason why the tx failed, if it failed
 	EffectiveGasPrice  *big.Int
@@ -31,6 +32,7 @@ type TxTracker struct {
 	IsLastExecution    bool
 	EGPLog             state.EffectiveGasPriceLog
 	L1GasPrice         uint64
+	L1BlobBaseFee      uint64
 	L2GasPrice         uint64
 }
 
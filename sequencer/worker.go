@@ -29,11 +29,11 @@ type Worker struct {
 }
 
 // NewWorker creates an init a worker
-func NewWorker(state stateInterface, constraints state.BatchConstraintsCfg, readyTxsCond *timeoutCond) *Worker {
+func NewWorker(state stateInterface, constraints state.BatchConstraintsCfg, firstComeFirstServed bool, slotDuration time.Duration, readyTxsCond *timeoutCond) *Worker {
 	w := Worker{
 		pool:             make(map[string]*addrQueue),
 		workerMutex:      new(sync.Mutex),
-		txSortedList:     newTxSortedList(),
+		txSortedList:     newTxSortedList(firstComeFirstServed, slotDuration),
 		pendingToStore:   []*TxTracker{},
 		state:            state,
 		batchConstraints: constraints,
@@ -284,6 +284,6 @@ func TestWorkerGetBestTx(t *testing.T) {
 }
 
 func initWorker(stateMock *StateMock, rcMax state.BatchConstraintsCfg) *Worker {
-	worker := NewWorker(stateMock, rcMax, newTimeoutCond(&sync.Mutex{}))
+	worker := NewWorker(stateMock, rcMax, false, 0, newTimeoutCond(&sync.Mutex{}))
 	return worker
 }
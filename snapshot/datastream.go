@@ -0,0 +1,397 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-data-streamer/datastreamer"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/datastream"
+	"github.com/syndtr/goleveldb/leveldb"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// ErrInvalidStreamFile indicates the file is not a data stream file
+	ErrInvalidStreamFile = errors.New("invalid data stream file")
+	// ErrStopWalk can be returned by the WalkStream callback to stop walking the stream
+	ErrStopWalk = errors.New("stop walking the stream")
+	// ErrStreamBaseMismatch indicates an incremental data stream part can't be applied to the existing file
+	ErrStreamBaseMismatch = errors.New("data stream file doesn't match the base snapshot")
+)
+
+// OpenStream opens a data stream file that isn't in use by a running stream server, so its header
+// and entries are accessed through the datastreamer package. The datastreamer package doesn't allow
+// to close a stream server, so the file is opened through a link in a temporary folder where its
+// bookmarks DB is created, leaving the bookmarks DB of the file untouched. The returned function
+// removes the temporary folder
+func OpenStream(fileName string) (*datastreamer.StreamServer, func(), error) {
+	absFileName, err := filepath.Abs(fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	tmpDir, err := os.MkdirTemp("", "datastream")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmpDir) }
+	link := filepath.Join(tmpDir, "datastream.bin")
+	if err = os.Symlink(absFileName, link); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	server, err := datastreamer.NewServer(0, 0, 0, state.StreamTypeSequencer, link, 0, 0, 0, nil)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidStreamFile, err)
+	}
+	return server, cleanup, nil
+}
+
+// ReadStreamHeader reads the committed header of a data stream file that isn't in use by a running stream server
+func ReadStreamHeader(fileName string) (datastreamer.HeaderEntry, error) {
+	server, cleanup, err := OpenStream(fileName)
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	defer cleanup()
+	return server.GetHeader(), nil
+}
+
+// CopyStream copies the data stream file src, that can be in use by the stream server, into dst.
+// The stream server only appends data after the committed length and updates the header once the
+// data is written, so copying the header page first gives a copy that contains all the committed
+// data. The copy is padded to a whole number of data pages as required by the stream server
+func CopyStream(src, dst string) (datastreamer.HeaderEntry, error) {
+	in, err := os.Open(src) //nolint:gosec
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	defer in.Close() //nolint:errcheck
+
+	page := make([]byte, datastreamer.PageHeaderSize)
+	_, err = io.ReadFull(in, page)
+	if err != nil {
+		return datastreamer.HeaderEntry{}, fmt.Errorf("%w: %v", ErrInvalidStreamFile, err)
+	}
+
+	out, err := os.Create(dst) //nolint:gosec
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	defer out.Close() //nolint:errcheck
+
+	_, err = out.Write(page)
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	n, err := io.Copy(out, in)
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	err = padStreamFile(out, datastreamer.PageHeaderSize+uint64(n))
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	err = out.Sync()
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	return ReadStreamHeader(dst)
+}
+
+// CreateStreamPart stores in part the header page of the data stream file streamFile and its committed
+// data from offset fromLength onwards, so fromLength = 0 produces a full copy and the TotalLength of
+// a previous copy produces a delta that can be applied with ApplyStreamPart. The stream file must not
+// be in use by a running stream server
+func CreateStreamPart(streamFile, part string, fromLength uint64) (datastreamer.HeaderEntry, error) {
+	header, err := ReadStreamHeader(streamFile)
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	if fromLength < datastreamer.PageHeaderSize {
+		fromLength = datastreamer.PageHeaderSize
+	}
+	if fromLength > header.TotalLength {
+		return datastreamer.HeaderEntry{}, fmt.Errorf("%w: base length %d is greater than current length %d", ErrStreamBaseMismatch, fromLength, header.TotalLength)
+	}
+
+	in, err := os.Open(streamFile) //nolint:gosec
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	defer in.Close() //nolint:errcheck
+
+	out, err := os.Create(part) //nolint:gosec
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	defer out.Close() //nolint:errcheck
+
+	_, err = io.CopyN(out, in, datastreamer.PageHeaderSize)
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	_, err = in.Seek(int64(fromLength), io.SeekStart)
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	_, err = io.CopyN(out, in, int64(header.TotalLength-fromLength))
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	return header, out.Sync()
+}
+
+// StreamDataChecksum returns the hex encoded sha256 of the data of the stream file up to length, that
+// are its committed entries if length is the TotalLength of its header. The header page is not included,
+// it changes every time entries are added, so the checksum of the data of a stream is also the checksum
+// of the beginning of the data of the streams built on top of it
+func StreamDataChecksum(fileName string, length uint64) (string, error) {
+	in, err := os.Open(fileName) //nolint:gosec
+	if err != nil {
+		return "", err
+	}
+	defer in.Close() //nolint:errcheck
+
+	h := sha256.New()
+	if length > datastreamer.PageHeaderSize {
+		_, err = in.Seek(datastreamer.PageHeaderSize, io.SeekStart)
+		if err != nil {
+			return "", err
+		}
+		_, err = io.CopyN(h, in, int64(length-datastreamer.PageHeaderSize))
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ApplyStreamPart writes a part created by CreateStreamPart into the data stream file dst.
+// offset is the position where the data of the part starts and baseSHA256 the checksum of the
+// data of dst before offset the part was built on. The header page of the part is written last,
+// so an interrupted restore leaves the previous committed header
+func ApplyStreamPart(dst, part string, offset uint64, baseSHA256 string) (datastreamer.HeaderEntry, error) {
+	if offset < datastreamer.PageHeaderSize {
+		offset = datastreamer.PageHeaderSize
+	}
+	var current datastreamer.HeaderEntry
+	if offset > datastreamer.PageHeaderSize {
+		var err error
+		current, err = ReadStreamHeader(dst)
+		if err != nil {
+			return datastreamer.HeaderEntry{}, err
+		}
+		if current.TotalLength != offset {
+			return datastreamer.HeaderEntry{}, fmt.Errorf("%w: current length %d, part offset %d", ErrStreamBaseMismatch, current.TotalLength, offset)
+		}
+		sum, err := StreamDataChecksum(dst, offset)
+		if err != nil {
+			return datastreamer.HeaderEntry{}, err
+		}
+		if sum != baseSHA256 {
+			return datastreamer.HeaderEntry{}, fmt.Errorf("%w: the data before offset %d doesn't match the base of the part", ErrStreamBaseMismatch, offset)
+		}
+	}
+
+	in, err := os.Open(part) //nolint:gosec
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	defer in.Close() //nolint:errcheck
+
+	page := make([]byte, datastreamer.PageHeaderSize)
+	_, err = io.ReadFull(in, page)
+	if err != nil {
+		return datastreamer.HeaderEntry{}, fmt.Errorf("%w: %v", ErrInvalidStreamFile, err)
+	}
+
+	out, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE, 0644) //nolint:gosec
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	defer out.Close() //nolint:errcheck
+
+	_, err = out.Seek(int64(offset), io.SeekStart)
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	n, err := io.Copy(out, in)
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	err = padStreamFile(out, offset+uint64(n))
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	_, err = out.WriteAt(page, 0)
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	err = out.Sync()
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+
+	header, err := ReadStreamHeader(dst)
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	if header.TotalLength != offset+uint64(n) {
+		return datastreamer.HeaderEntry{}, fmt.Errorf("%w: part length doesn't match its header", ErrInvalidStreamFile)
+	}
+	if offset > datastreamer.PageHeaderSize && header.SystemID != current.SystemID {
+		return datastreamer.HeaderEntry{}, fmt.Errorf("%w: part of system %d, current system %d", ErrStreamBaseMismatch, header.SystemID, current.SystemID)
+	}
+	return header, nil
+}
+
+// padStreamFile truncates the data stream file to a whole number of data pages large enough to hold length bytes
+func padStreamFile(f *os.File, length uint64) error {
+	dataSize := length - datastreamer.PageHeaderSize
+	pages := (dataSize + datastreamer.PageDataSize - 1) / datastreamer.PageDataSize
+	if pages == 0 {
+		pages = 1
+	}
+	return f.Truncate(int64(datastreamer.PageHeaderSize + pages*datastreamer.PageDataSize))
+}
+
+// BookmarkDBName returns the name of the bookmarks DB used by the stream server for a stream file
+func BookmarkDBName(fileName string) string {
+	if strings.IndexRune(fileName, '.') == -1 {
+		fileName += ".bin"
+	}
+	return fileName[0:strings.IndexRune(fileName, '.')] + ".db"
+}
+
+// RebuildStreamBookmarks recreates the bookmarks DB of a data stream file by walking
+// all its entries. Any existing bookmarks DB is removed
+func RebuildStreamBookmarks(fileName string) (uint64, error) {
+	header, err := ReadStreamHeader(fileName)
+	if err != nil {
+		return 0, err
+	}
+	dbName := BookmarkDBName(fileName)
+	err = os.RemoveAll(dbName)
+	if err != nil {
+		return 0, err
+	}
+	db, err := leveldb.OpenFile(dbName, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close() //nolint:errcheck
+
+	var count uint64
+	err = WalkStream(fileName, header, func(e datastreamer.FileEntry, _ uint64) error {
+		if e.Type != datastreamer.EtBookmark {
+			return nil
+		}
+		count++
+		return db.Put(e.Data, binary.BigEndian.AppendUint64(nil, e.Number), nil)
+	})
+	return count, err
+}
+
+// WalkStream calls f for every committed data entry of the stream file, along with the
+// position of the entry in the file. The walk stops without error if f returns ErrStopWalk
+func WalkStream(fileName string, header datastreamer.HeaderEntry, f func(e datastreamer.FileEntry, pos uint64) error) error {
+	in, err := os.Open(fileName) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	defer in.Close() //nolint:errcheck
+
+	pos := uint64(datastreamer.PageHeaderSize)
+	fixed := make([]byte, datastreamer.FixedSizeFileEntry)
+	var entries uint64
+	for pos < header.TotalLength && entries < header.TotalEntries {
+		_, err = in.ReadAt(fixed[:1], int64(pos))
+		if err != nil {
+			return err
+		}
+		if fixed[0] == datastreamer.PtPadding {
+			// Rest of the page is padding, skip to the next page
+			pos = datastreamer.PageHeaderSize + ((pos-datastreamer.PageHeaderSize)/datastreamer.PageDataSize+1)*datastreamer.PageDataSize
+			continue
+		}
+		_, err = in.ReadAt(fixed, int64(pos))
+		if err != nil {
+			return err
+		}
+		length := binary.BigEndian.Uint32(fixed[1:5])
+		if fixed[0] != datastreamer.PtData || length < datastreamer.FixedSizeFileEntry {
+			return fmt.Errorf("%w: bad entry at position %d", ErrInvalidStreamFile, pos)
+		}
+		raw := make([]byte, length)
+		_, err = in.ReadAt(raw, int64(pos))
+		if err != nil {
+			return err
+		}
+		e, err := datastreamer.DecodeBinaryToFileEntry(raw)
+		if err != nil {
+			return err
+		}
+		if e.Number != entries {
+			return fmt.Errorf("%w: expected entry %d at position %d, found %d", ErrInvalidStreamFile, entries, pos, e.Number)
+		}
+		err = f(e, pos)
+		if errors.Is(err, ErrStopWalk) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		entries++
+		pos += uint64(length)
+	}
+	return nil
+}
+
+// TruncateStreamAfter removes from the stream file every entry that belongs to a batch
+// greater than batchNumber or to an L2 block greater than l2BlockNumber. It is used to cut
+// the stream at the same point as the state DB dump. The bookmarks DB is not updated, so it
+// must be rebuilt if the file is going to be used by a stream server
+func TruncateStreamAfter(fileName string, batchNumber, l2BlockNumber uint64) (datastreamer.HeaderEntry, error) {
+	server, cleanup, err := OpenStream(fileName)
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	defer cleanup()
+
+	header := server.GetHeader()
+	cut := header.TotalEntries
+	err = WalkStream(fileName, header, func(e datastreamer.FileEntry, _ uint64) error {
+		if e.Type != datastreamer.EtBookmark {
+			return nil
+		}
+		bookMark := &datastream.BookMark{}
+		if err := proto.Unmarshal(e.Data, bookMark); err != nil {
+			return err
+		}
+		if (bookMark.Type == datastream.BookmarkType_BOOKMARK_TYPE_BATCH && bookMark.Value > batchNumber) ||
+			(bookMark.Type == datastream.BookmarkType_BOOKMARK_TYPE_L2_BLOCK && bookMark.Value > l2BlockNumber) {
+			cut = e.Number
+			return ErrStopWalk
+		}
+		return nil
+	})
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	if cut == header.TotalEntries {
+		return header, nil
+	}
+	err = server.TruncateFile(cut)
+	if err != nil {
+		return datastreamer.HeaderEntry{}, err
+	}
+	return server.GetHeader(), nil
+}
@@ -0,0 +1,186 @@
+package snapshot
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-data-streamer/datastreamer"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/datastream"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestStreamServer(t *testing.T, fileName string) *datastreamer.StreamServer {
+	server, err := datastreamer.NewServer(0, state.DSVersion4, 1001, state.StreamTypeSequencer, fileName, time.Second, time.Minute, time.Minute, nil)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	return server
+}
+
+func addTestBatch(t *testing.T, server *datastreamer.StreamServer, batchNumber uint64, l2Blocks ...uint64) {
+	require.NoError(t, server.StartAtomicOp())
+	addBookMark(t, server, datastream.BookmarkType_BOOKMARK_TYPE_BATCH, batchNumber)
+	addEntry(t, server, datastream.EntryType_ENTRY_TYPE_BATCH_START, &datastream.BatchStart{Number: batchNumber})
+	for _, l2Block := range l2Blocks {
+		addBookMark(t, server, datastream.BookmarkType_BOOKMARK_TYPE_L2_BLOCK, l2Block)
+		addEntry(t, server, datastream.EntryType_ENTRY_TYPE_L2_BLOCK, &datastream.L2Block{Number: l2Block, BatchNumber: batchNumber})
+		addEntry(t, server, datastream.EntryType_ENTRY_TYPE_L2_BLOCK_END, &datastream.L2BlockEnd{Number: l2Block})
+	}
+	addEntry(t, server, datastream.EntryType_ENTRY_TYPE_BATCH_END, &datastream.BatchEnd{Number: batchNumber})
+	require.NoError(t, server.CommitAtomicOp())
+}
+
+func addBookMark(t *testing.T, server *datastreamer.StreamServer, bookmarkType datastream.BookmarkType, value uint64) {
+	data, err := proto.Marshal(&datastream.BookMark{Type: bookmarkType, Value: value})
+	require.NoError(t, err)
+	_, err = server.AddStreamBookmark(data)
+	require.NoError(t, err)
+}
+
+func addEntry(t *testing.T, server *datastreamer.StreamServer, entryType datastream.EntryType, m proto.Message) {
+	data, err := proto.Marshal(m)
+	require.NoError(t, err)
+	_, err = server.AddStreamEntry(datastreamer.EntryType(entryType), data)
+	require.NoError(t, err)
+}
+
+func countEntries(t *testing.T, fileName string) uint64 {
+	header, err := ReadStreamHeader(fileName)
+	require.NoError(t, err)
+	var count uint64
+	require.NoError(t, WalkStream(fileName, header, func(e datastreamer.FileEntry, _ uint64) error {
+		count++
+		return nil
+	}))
+	return count
+}
+
+func TestCopyAndApplyStream(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "stream.bin")
+	dst := filepath.Join(dir, "restored.bin")
+	server := newTestStreamServer(t, src)
+
+	addTestBatch(t, server, 1, 1, 2)
+	copied := filepath.Join(dir, "copy.bin")
+	_, err := CopyStream(src, copied)
+	require.NoError(t, err)
+	full := filepath.Join(dir, "full.part")
+	fullHeader, err := CreateStreamPart(copied, full, 0)
+	require.NoError(t, err)
+	require.Equal(t, server.GetHeader().TotalEntries, fullHeader.TotalEntries)
+	require.Equal(t, uint64(1001), fullHeader.SystemID)
+
+	addTestBatch(t, server, 2, 3)
+	copied = filepath.Join(dir, "copy2.bin")
+	_, err = CopyStream(src, copied)
+	require.NoError(t, err)
+	delta := filepath.Join(dir, "delta.part")
+	deltaHeader, err := CreateStreamPart(copied, delta, fullHeader.TotalLength)
+	require.NoError(t, err)
+	require.Equal(t, server.GetHeader().TotalEntries, deltaHeader.TotalEntries)
+
+	baseSum, err := StreamDataChecksum(full, fullHeader.TotalLength)
+	require.NoError(t, err)
+	// The data of the base is the beginning of the data of the new stream
+	sum, err := StreamDataChecksum(copied, fullHeader.TotalLength)
+	require.NoError(t, err)
+	require.Equal(t, baseSum, sum)
+
+	_, err = ApplyStreamPart(dst, full, 0, "")
+	require.NoError(t, err)
+	_, err = ApplyStreamPart(dst, delta, fullHeader.TotalLength, baseSum)
+	require.NoError(t, err)
+	// The delta can only be applied on top of its base
+	_, err = ApplyStreamPart(dst, delta, fullHeader.TotalLength, baseSum)
+	require.ErrorIs(t, err, ErrStreamBaseMismatch)
+
+	restored, err := ReadStreamHeader(dst)
+	require.NoError(t, err)
+	require.Equal(t, deltaHeader, restored)
+	require.Equal(t, restored.TotalEntries, countEntries(t, dst))
+
+	bookmarks, err := RebuildStreamBookmarks(dst)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), bookmarks)
+
+	// The restored file must be usable by the stream server
+	restoredServer, err := datastreamer.NewServer(0, state.DSVersion4, 1001, state.StreamTypeSequencer, dst, time.Second, time.Minute, time.Minute, nil)
+	require.NoError(t, err)
+	key, err := proto.Marshal(&datastream.BookMark{Type: datastream.BookmarkType_BOOKMARK_TYPE_L2_BLOCK, Value: 3})
+	require.NoError(t, err)
+	entry, err := restoredServer.GetFirstEventAfterBookmark(key)
+	require.NoError(t, err)
+	l2Block := &datastream.L2Block{}
+	require.NoError(t, proto.Unmarshal(entry.Data, l2Block))
+	require.Equal(t, uint64(3), l2Block.Number)
+}
+
+func TestApplyStreamPartOnDifferentBase(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "stream.bin")
+	server := newTestStreamServer(t, src)
+	addTestBatch(t, server, 1, 1, 2)
+	base := filepath.Join(dir, "base.bin")
+	baseHeader, err := CopyStream(src, base)
+	require.NoError(t, err)
+	baseSum, err := StreamDataChecksum(base, baseHeader.TotalLength)
+	require.NoError(t, err)
+	addTestBatch(t, server, 2, 3)
+	copied := filepath.Join(dir, "copy.bin")
+	_, err = CopyStream(src, copied)
+	require.NoError(t, err)
+	delta := filepath.Join(dir, "delta.part")
+	_, err = CreateStreamPart(copied, delta, baseHeader.TotalLength)
+	require.NoError(t, err)
+
+	// A stream of the same length with different entries
+	other := filepath.Join(dir, "other.bin")
+	otherServer := newTestStreamServer(t, other)
+	addTestBatch(t, otherServer, 7, 8, 9)
+	require.Equal(t, baseHeader.TotalLength, otherServer.GetHeader().TotalLength)
+	dst := filepath.Join(dir, "restored.bin")
+	_, err = CopyStream(other, dst)
+	require.NoError(t, err)
+
+	_, err = ApplyStreamPart(dst, delta, baseHeader.TotalLength, baseSum)
+	require.ErrorIs(t, err, ErrStreamBaseMismatch)
+	// The stream is left untouched
+	header, err := ReadStreamHeader(dst)
+	require.NoError(t, err)
+	require.Equal(t, baseHeader.TotalEntries, header.TotalEntries)
+}
+
+func TestTruncateStreamAfter(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "stream.bin")
+	server := newTestStreamServer(t, src)
+	addTestBatch(t, server, 1, 1, 2)
+	addTestBatch(t, server, 2, 3, 4)
+
+	testCases := []struct {
+		name          string
+		batchNumber   uint64
+		l2BlockNumber uint64
+		// 1 bookmark + 2 entries per batch and 1 bookmark + 2 entries per L2 block
+		expectedEntries uint64
+	}{
+		{name: "nothing to truncate", batchNumber: 2, l2BlockNumber: 4, expectedEntries: 18},
+		{name: "cut in the middle of a batch", batchNumber: 2, l2BlockNumber: 3, expectedEntries: 14},
+		{name: "cut at the end of a batch", batchNumber: 1, l2BlockNumber: 2, expectedEntries: 9},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "stream.bin")
+			_, err := CopyStream(src, file)
+			require.NoError(t, err)
+
+			header, err := TruncateStreamAfter(file, tc.batchNumber, tc.l2BlockNumber)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedEntries, header.TotalEntries)
+			require.Equal(t, tc.expectedEntries, countEntries(t, file))
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package snapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ErrVerifiedStateRootMismatch indicates the verified state root of the snapshot doesn't match the one in L1
+	ErrVerifiedStateRootMismatch = errors.New("snapshot verified state root doesn't match the rollup contract")
+	// ErrSnapshotAheadOfL1 indicates the snapshot contains a verified batch that is not verified in L1
+	ErrSnapshotAheadOfL1 = errors.New("snapshot verified batch is not verified in the rollup contract")
+	// ErrInvalidFileName indicates a manifest references a file outside of its folder
+	ErrInvalidFileName = errors.New("invalid snapshot file name")
+)
+
+// L1Verifier gathers the methods required to cross-check a snapshot against the rollup contract
+type L1Verifier interface {
+	GetLatestVerifiedBatchNum() (uint64, error)
+	GetBatchStateRoot(batchNumber uint64) (common.Hash, error)
+}
+
+// CheckVerifiedStateRoot checks the last verified batch of the snapshot has been verified in L1
+// with the same state root
+func (m *Manifest) CheckVerifiedStateRoot(l1 L1Verifier) error {
+	if m.VerifiedBatchNumber == 0 {
+		log.Warn("snapshot doesn't contain verified batches, skipping L1 state root check")
+		return nil
+	}
+	lastVerified, err := l1.GetLatestVerifiedBatchNum()
+	if err != nil {
+		return err
+	}
+	if lastVerified < m.VerifiedBatchNumber {
+		return fmt.Errorf("%w: batch %d, last verified batch in L1 %d", ErrSnapshotAheadOfL1, m.VerifiedBatchNumber, lastVerified)
+	}
+	stateRoot, err := l1.GetBatchStateRoot(m.VerifiedBatchNumber)
+	if err != nil {
+		return err
+	}
+	if stateRoot != m.VerifiedStateRoot {
+		return fmt.Errorf("%w: batch %d, snapshot %s, L1 %s", ErrVerifiedStateRootMismatch, m.VerifiedBatchNumber, m.VerifiedStateRoot, stateRoot)
+	}
+	return nil
+}
+
+// Download fetches the manifest located in manifestURL, its base manifests and all their
+// parts into dir. The parts are resolved relative to the manifest URL and their checksums
+// are verified while downloading. Files already present in dir with the expected checksum
+// are not downloaded again. It returns the local path of the manifest
+func Download(ctx context.Context, client *http.Client, manifestURL, dir string) (string, error) {
+	u, err := url.Parse(manifestURL)
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(dir, 0750) //nolint:gosec
+	if err != nil {
+		return "", err
+	}
+
+	manifestFile := path.Base(u.Path)
+	expectedSum := ""
+	for manifestFile != "" {
+		if path.Base(manifestFile) != manifestFile {
+			return "", fmt.Errorf("%w: %s", ErrInvalidFileName, manifestFile)
+		}
+		fileURL := u.ResolveReference(&url.URL{Path: manifestFile})
+		log.Infof("downloading snapshot manifest %s", fileURL)
+		err = downloadFile(ctx, client, fileURL.String(), filepath.Join(dir, manifestFile), expectedSum, -1)
+		if err != nil {
+			return "", err
+		}
+		m, err := LoadManifest(filepath.Join(dir, manifestFile))
+		if err != nil {
+			return "", err
+		}
+		for _, p := range m.Parts {
+			if path.Base(p.File) != p.File {
+				return "", fmt.Errorf("%w: %s", ErrInvalidFileName, p.File)
+			}
+			partURL := u.ResolveReference(&url.URL{Path: p.File})
+			log.Infof("downloading snapshot part %s (%d bytes)", partURL, p.Size)
+			err = downloadFile(ctx, client, partURL.String(), filepath.Join(dir, p.File), p.SHA256, p.Size)
+			if err != nil {
+				return "", err
+			}
+		}
+		manifestFile, expectedSum = "", ""
+		if m.Base != nil {
+			manifestFile, expectedSum = m.Base.File, m.Base.SHA256
+		}
+	}
+	return filepath.Join(dir, path.Base(u.Path)), nil
+}
+
+// downloadFile downloads fileURL into dst checking its checksum and size if they are provided
+func downloadFile(ctx context.Context, client *http.Client, fileURL, dst, expectedSum string, expectedSize int64) error {
+	if expectedSum != "" {
+		sum, size, err := FileChecksum(dst)
+		if err == nil && sum == expectedSum && (expectedSize < 0 || size == expectedSize) {
+			log.Debugf("%s already downloaded", dst)
+			return nil
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close() //nolint:errcheck
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("error downloading %s: %s", fileURL, res.Status)
+	}
+
+	tmp := dst + ".download"
+	out, err := os.Create(tmp) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(out, h), res.Body)
+	closeErr := out.Close()
+	if err != nil {
+		return err
+	} else if closeErr != nil {
+		return closeErr
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	if (expectedSum != "" && sum != expectedSum) || (expectedSize >= 0 && size != expectedSize) {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("%w: %s", ErrChecksumMismatch, fileURL)
+	}
+	return os.Rename(tmp, dst)
+}
@@ -0,0 +1,93 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownload(t *testing.T) {
+	srcDir := t.TempDir()
+	full := writeTestManifest(t, srcDir, 10, "", map[string]string{"full.part": "full"})
+	incremental := writeTestManifest(t, srcDir, 20, full, map[string]string{"delta.part": "delta"})
+	server := httptest.NewServer(http.FileServer(http.Dir(srcDir)))
+	defer server.Close()
+
+	dstDir := filepath.Join(t.TempDir(), "bootstrap")
+	manifestPath, err := Download(context.Background(), server.Client(), server.URL+"/"+incremental, dstDir)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dstDir, incremental), manifestPath)
+	chain, err := LoadChain(manifestPath)
+	require.NoError(t, err)
+	require.Len(t, chain, 2)
+
+	// Downloading again only replaces the files that don't match the manifest
+	require.NoError(t, os.WriteFile(filepath.Join(dstDir, "delta.part"), []byte("DELTA"), 0600))
+	_, err = Download(context.Background(), server.Client(), server.URL+"/"+incremental, dstDir)
+	require.NoError(t, err)
+	_, err = LoadChain(manifestPath)
+	require.NoError(t, err)
+
+	// A corrupted part in the server is detected
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "full.part"), []byte("FULL"), 0600))
+	_, err = Download(context.Background(), server.Client(), server.URL+"/"+incremental, t.TempDir())
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+
+	_, err = Download(context.Background(), server.Client(), server.URL+"/missing_manifest.json", t.TempDir())
+	require.Error(t, err)
+}
+
+type l1VerifierMock struct {
+	lastVerified uint64
+	stateRoots   map[uint64]common.Hash
+}
+
+func (m *l1VerifierMock) GetLatestVerifiedBatchNum() (uint64, error) {
+	return m.lastVerified, nil
+}
+
+func (m *l1VerifierMock) GetBatchStateRoot(batchNumber uint64) (common.Hash, error) {
+	root, ok := m.stateRoots[batchNumber]
+	if !ok {
+		return common.Hash{}, errors.New("not found")
+	}
+	return root, nil
+}
+
+func TestCheckVerifiedStateRoot(t *testing.T) {
+	l1 := &l1VerifierMock{
+		lastVerified: 10,
+		stateRoots:   map[uint64]common.Hash{5: common.HexToHash("0x05"), 10: common.HexToHash("0x0a")},
+	}
+	testCases := []struct {
+		name          string
+		batchNumber   uint64
+		stateRoot     common.Hash
+		expectedError error
+	}{
+		{name: "no verified batches", batchNumber: 0},
+		{name: "matching state root", batchNumber: 5, stateRoot: common.HexToHash("0x05")},
+		{name: "different state root", batchNumber: 10, stateRoot: common.HexToHash("0x05"), expectedError: ErrVerifiedStateRootMismatch},
+		{name: "batch not verified in L1", batchNumber: 11, stateRoot: common.HexToHash("0x0b"), expectedError: ErrSnapshotAheadOfL1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewManifest("v0.0.0", "abcdef")
+			m.VerifiedBatchNumber = tc.batchNumber
+			m.VerifiedStateRoot = tc.stateRoot
+			err := m.CheckVerifiedStateRoot(l1)
+			if tc.expectedError == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// ManifestVersion is the version of the manifest format written by this node
+	ManifestVersion = 1
+	// ManifestFileName is the suffix used for the manifest files
+	ManifestFileName = "manifest.json"
+)
+
+// PartKind identifies the content of a snapshot part
+type PartKind string
+
+const (
+	// PartStateDB is a pg_dump of the state database
+	PartStateDB PartKind = "statedb"
+	// PartHashDB is a pg_dump of the hash database (merkle tree)
+	PartHashDB PartKind = "hashdb"
+	// PartPoolDB is a pg_dump of the pool database
+	PartPoolDB PartKind = "pooldb"
+	// PartDataStream is a (full or delta) copy of the data stream file
+	PartDataStream PartKind = "datastream"
+)
+
+var (
+	// ErrChecksumMismatch indicates the checksum of a part doesn't match the one in the manifest
+	ErrChecksumMismatch = errors.New("snapshot part checksum mismatch")
+	// ErrUnsupportedManifestVersion indicates the manifest was written with an unknown format
+	ErrUnsupportedManifestVersion = errors.New("unsupported snapshot manifest version")
+	// ErrBaseManifestMismatch indicates the base manifest of an incremental snapshot is not the expected one
+	ErrBaseManifestMismatch = errors.New("base snapshot manifest mismatch")
+	// ErrPartNotFound indicates the manifest doesn't contain a part of the requested kind
+	ErrPartNotFound = errors.New("snapshot part not found")
+	// ErrRestoredStateMismatch indicates the restored state DB doesn't match the cut recorded in the manifest
+	ErrRestoredStateMismatch = errors.New("restored stateDB doesn't match the snapshot manifest")
+)
+
+// Part is a file that belongs to a snapshot
+type Part struct {
+	// Kind of content stored in the file
+	Kind PartKind `json:"kind"`
+	// File name, relative to the manifest directory
+	File string `json:"file"`
+	// Size of the file in bytes
+	Size int64 `json:"size"`
+	// SHA256 is the hex encoded sha256 of the file
+	SHA256 string `json:"sha256"`
+	// Offset in the destination file where the data of the part starts. Only used by incremental data stream parts
+	Offset uint64 `json:"offset,omitempty"`
+	// BaseSHA256 is the hex encoded sha256 of the data stream data before Offset the part must be applied on.
+	// Only used by incremental data stream parts
+	BaseSHA256 string `json:"baseSHA256,omitempty"`
+}
+
+// BaseRef references the manifest an incremental data stream part was built on
+type BaseRef struct {
+	// File name of the base manifest, relative to the manifest directory
+	File string `json:"file"`
+	// SHA256 is the hex encoded sha256 of the base manifest file
+	SHA256 string `json:"sha256"`
+}
+
+// DataStreamInfo describes the data stream file captured in the snapshot
+type DataStreamInfo struct {
+	// TotalLength is the number of bytes used in the stream file
+	TotalLength uint64 `json:"totalLength"`
+	// TotalEntries is the number of entries in the stream file
+	TotalEntries uint64 `json:"totalEntries"`
+	// SHA256 is the hex encoded sha256 of the data of the stream file, see StreamDataChecksum
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest describes a consistent cut of the node state
+type Manifest struct {
+	Version     int       `json:"version"`
+	CreatedAt   time.Time `json:"createdAt"`
+	NodeVersion string    `json:"nodeVersion"`
+	GitRev      string    `json:"gitRev"`
+	ChainID     uint64    `json:"chainID"`
+	// BatchNumber is the last batch stored in the state DB at the cut
+	BatchNumber uint64 `json:"batchNumber"`
+	// L2BlockNumber is the last L2 block stored in the state DB at the cut
+	L2BlockNumber uint64 `json:"l2BlockNumber"`
+	// L2BlockStateRoot is the state root of the last L2 block at the cut
+	L2BlockStateRoot common.Hash `json:"l2BlockStateRoot"`
+	// L1BlockNumber is the last L1 block synced at the cut
+	L1BlockNumber uint64 `json:"l1BlockNumber"`
+	// VerifiedBatchNumber is the last verified batch at the cut
+	VerifiedBatchNumber uint64 `json:"verifiedBatchNumber"`
+	// VerifiedStateRoot is the state root of the last verified batch at the cut
+	VerifiedStateRoot common.Hash `json:"verifiedStateRoot"`
+	// Base is set when the data stream part of the snapshot is incremental, the databases are always dumped in full
+	Base *BaseRef `json:"base,omitempty"`
+	// DataStream is set when the data stream file is included in the snapshot
+	DataStream *DataStreamInfo `json:"dataStream,omitempty"`
+	Parts      []Part          `json:"parts"`
+}
+
+// NewManifest creates an empty manifest for the current node version
+func NewManifest(nodeVersion, gitRev string) *Manifest {
+	return &Manifest{
+		Version:     ManifestVersion,
+		CreatedAt:   time.Now().UTC(),
+		NodeVersion: nodeVersion,
+		GitRev:      gitRev,
+		Parts:       []Part{},
+	}
+}
+
+// IsIncremental returns true if the snapshot depends on a base snapshot for its data stream
+func (m *Manifest) IsIncremental() bool {
+	return m.Base != nil
+}
+
+// AddPart computes the checksum of a file located in dir and adds it to the manifest
+func (m *Manifest) AddPart(kind PartKind, dir, file string, offset uint64) error {
+	sum, size, err := FileChecksum(filepath.Join(dir, file))
+	if err != nil {
+		return err
+	}
+	m.Parts = append(m.Parts, Part{
+		Kind:   kind,
+		File:   file,
+		Size:   size,
+		SHA256: sum,
+		Offset: offset,
+	})
+	return nil
+}
+
+// AddStreamDeltaPart adds an incremental data stream part located in dir, whose data starts at offset
+// and must be applied on a data stream whose data before offset has the checksum baseSHA256
+func (m *Manifest) AddStreamDeltaPart(dir, file string, offset uint64, baseSHA256 string) error {
+	if err := m.AddPart(PartDataStream, dir, file, offset); err != nil {
+		return err
+	}
+	m.Parts[len(m.Parts)-1].BaseSHA256 = baseSHA256
+	return nil
+}
+
+// GetPart returns the part of the given kind
+func (m *Manifest) GetPart(kind PartKind) (Part, error) {
+	for _, p := range m.Parts {
+		if p.Kind == kind {
+			return p, nil
+		}
+	}
+	return Part{}, ErrPartNotFound
+}
+
+// CheckRestoredCut checks the cut read from the restored state DB, including its last verified batch,
+// matches the one recorded in the manifest
+func (m *Manifest) CheckRestoredCut(restored *Manifest) error {
+	if restored.BatchNumber != m.BatchNumber || restored.L2BlockNumber != m.L2BlockNumber ||
+		restored.L2BlockStateRoot != m.L2BlockStateRoot || restored.L1BlockNumber != m.L1BlockNumber {
+		return fmt.Errorf("%w: restored batch %d, L2 block %d, L1 block %d, manifest batch %d, L2 block %d, L1 block %d", ErrRestoredStateMismatch,
+			restored.BatchNumber, restored.L2BlockNumber, restored.L1BlockNumber, m.BatchNumber, m.L2BlockNumber, m.L1BlockNumber)
+	}
+	if restored.VerifiedBatchNumber != m.VerifiedBatchNumber || restored.VerifiedStateRoot != m.VerifiedStateRoot {
+		return fmt.Errorf("%w: restored verified batch %d state root %s, manifest verified batch %d state root %s", ErrRestoredStateMismatch,
+			restored.VerifiedBatchNumber, restored.VerifiedStateRoot, m.VerifiedBatchNumber, m.VerifiedStateRoot)
+	}
+	return nil
+}
+
+// Save writes the manifest into dir and returns the file name used
+func (m *Manifest) Save(dir string) (string, error) {
+	fileName := fmt.Sprintf("snapshot_%d_%d_%s", m.BatchNumber, m.CreatedAt.Unix(), ManifestFileName)
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(filepath.Join(dir, fileName), data, 0644) //nolint:gosec
+	if err != nil {
+		return "", err
+	}
+	return fileName, nil
+}
+
+// LoadManifest reads a manifest from a file
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	return ParseManifest(data)
+}
+
+// ParseManifest decodes a manifest and checks its version
+func ParseManifest(data []byte) (*Manifest, error) {
+	var m Manifest
+	err := json.Unmarshal(data, &m)
+	if err != nil {
+		return nil, err
+	}
+	if m.Version != ManifestVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedManifestVersion, m.Version)
+	}
+	return &m, nil
+}
+
+// Verify checks the size and checksum of every part of the manifest located in dir
+func (m *Manifest) Verify(dir string) error {
+	for _, p := range m.Parts {
+		sum, size, err := FileChecksum(filepath.Join(dir, p.File))
+		if err != nil {
+			return fmt.Errorf("error checking part %s: %w", p.File, err)
+		}
+		if size != p.Size || sum != p.SHA256 {
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, p.File)
+		}
+	}
+	return nil
+}
+
+// LoadChain loads the manifest located in path and all its base manifests, verifying
+// every part and link of the chain. The result is ordered from the oldest (full) snapshot
+// to the one located in path
+func LoadChain(path string) ([]*Manifest, error) {
+	dir := filepath.Dir(path)
+	m, err := LoadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	chain := []*Manifest{m}
+	for m.Base != nil {
+		basePath := filepath.Join(dir, m.Base.File)
+		sum, _, err := FileChecksum(basePath)
+		if err != nil {
+			return nil, err
+		}
+		if sum != m.Base.SHA256 {
+			return nil, fmt.Errorf("%w: %s", ErrBaseManifestMismatch, m.Base.File)
+		}
+		base, err := LoadManifest(basePath)
+		if err != nil {
+			return nil, err
+		}
+		if base.BatchNumber > m.BatchNumber || base.ChainID != m.ChainID {
+			return nil, fmt.Errorf("%w: %s is not an ancestor", ErrBaseManifestMismatch, m.Base.File)
+		}
+		chain = append([]*Manifest{base}, chain...)
+		m = base
+	}
+	for _, m := range chain {
+		if err := m.Verify(dir); err != nil {
+			return nil, err
+		}
+	}
+	return chain, nil
+}
+
+// FileChecksum returns the hex encoded sha256 and the size of a file
+func FileChecksum(path string) (string, int64, error) {
+	f, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close() //nolint:errcheck
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
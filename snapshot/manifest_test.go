@@ -0,0 +1,104 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestManifest(t *testing.T, dir string, batchNumber uint64, base string, parts map[string]string) string {
+	m := NewManifest("v0.0.0", "abcdef")
+	m.ChainID = 1001
+	m.BatchNumber = batchNumber
+	if base != "" {
+		sum, _, err := FileChecksum(filepath.Join(dir, base))
+		require.NoError(t, err)
+		m.Base = &BaseRef{File: base, SHA256: sum}
+	}
+	for file, content := range parts {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0600))
+		require.NoError(t, m.AddPart(PartDataStream, dir, file, 0))
+	}
+	fileName, err := m.Save(dir)
+	require.NoError(t, err)
+	return fileName
+}
+
+func TestLoadChain(t *testing.T) {
+	dir := t.TempDir()
+	full := writeTestManifest(t, dir, 10, "", map[string]string{"full.part": "full"})
+	incremental := writeTestManifest(t, dir, 20, full, map[string]string{"delta.part": "delta"})
+
+	chain, err := LoadChain(filepath.Join(dir, incremental))
+	require.NoError(t, err)
+	require.Len(t, chain, 2)
+	require.False(t, chain[0].IsIncremental())
+	require.Equal(t, uint64(10), chain[0].BatchNumber)
+	require.True(t, chain[1].IsIncremental())
+	require.Equal(t, uint64(20), chain[1].BatchNumber)
+
+	part, err := chain[1].GetPart(PartDataStream)
+	require.NoError(t, err)
+	require.Equal(t, int64(len("delta")), part.Size)
+	_, err = chain[1].GetPart(PartStateDB)
+	require.ErrorIs(t, err, ErrPartNotFound)
+
+	// A modified part must be detected
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "full.part"), []byte("FULL"), 0600))
+	_, err = LoadChain(filepath.Join(dir, incremental))
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+
+	// A modified base manifest must be detected
+	m, err := LoadManifest(filepath.Join(dir, full))
+	require.NoError(t, err)
+	m.L1BlockNumber = 1
+	_, err = m.Save(dir)
+	require.NoError(t, err)
+	_, err = LoadChain(filepath.Join(dir, incremental))
+	require.ErrorIs(t, err, ErrBaseManifestMismatch)
+}
+
+func TestParseManifestVersion(t *testing.T) {
+	_, err := ParseManifest([]byte(`{"version": 99}`))
+	require.ErrorIs(t, err, ErrUnsupportedManifestVersion)
+
+	m, err := ParseManifest([]byte(`{"version": 1, "batchNumber": 5}`))
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), m.BatchNumber)
+}
+
+func TestCheckRestoredCut(t *testing.T) {
+	m := NewManifest("v0.0.0", "abcdef")
+	m.BatchNumber = 10
+	m.L2BlockNumber = 20
+	m.L2BlockStateRoot = common.HexToHash("0x14")
+	m.L1BlockNumber = 100
+	m.VerifiedBatchNumber = 8
+	m.VerifiedStateRoot = common.HexToHash("0x08")
+
+	testCases := []struct {
+		name          string
+		update        func(restored *Manifest)
+		expectedError error
+	}{
+		{name: "matching cut", update: func(restored *Manifest) {}},
+		{name: "different L2 block state root", update: func(restored *Manifest) { restored.L2BlockStateRoot = common.HexToHash("0x15") }, expectedError: ErrRestoredStateMismatch},
+		{name: "different verified batch", update: func(restored *Manifest) { restored.VerifiedBatchNumber = 9 }, expectedError: ErrRestoredStateMismatch},
+		{name: "different verified state root", update: func(restored *Manifest) { restored.VerifiedStateRoot = common.HexToHash("0x09") }, expectedError: ErrRestoredStateMismatch},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			restored := *m
+			tc.update(&restored)
+			err := m.CheckRestoredCut(&restored)
+			if tc.expectedError == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tc.expectedError)
+			}
+		})
+	}
+}
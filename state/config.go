@@ -51,6 +51,76 @@ type Config struct {
 	// AvoidForkIDInMemory is a configuration that forces the ForkID information to be loaded
 	// from the DB every time it's needed
 	AvoidForkIDInMemory bool
+
+	// Pruning is the configuration of the history kept by the node
+	Pruning PruningConfig `mapstructure:"Pruning"`
+
+	// TraceIndex is the configuration of the index of traces used by trace_filter
+	TraceIndex TraceIndexConfig `mapstructure:"TraceIndex"`
+
+	// TraceCache is the configuration of the cache of the traces generated by the debug endpoints
+	TraceCache TraceCacheConfig `mapstructure:"TraceCache"`
+
+	// WitnessCache is the configuration of the cache of the batch witnesses
+	WitnessCache WitnessCacheConfig `mapstructure:"WitnessCache"`
+}
+
+// WitnessCacheConfig represents the configuration of the cache of the witnesses of closed batches,
+// so they are generated executing the batch only once
+type WitnessCacheConfig struct {
+	// Enabled makes the witnesses be cached by batch number
+	Enabled bool `mapstructure:"Enabled"`
+	// MaxEntries is the max number of witnesses kept in memory, the least recently used ones are evicted
+	MaxEntries int `mapstructure:"MaxEntries"`
+}
+
+// TraceCacheConfig represents the configuration of the trace cache. Only the traces generated by
+// a custom tracer, like the callTracer, are cached
+type TraceCacheConfig struct {
+	// Enabled makes the traces be cached by transaction hash, tracer and tracer config
+	Enabled bool `mapstructure:"Enabled"`
+	// MaxEntries is the max number of traces kept in memory, the least recently used ones are evicted
+	MaxEntries int `mapstructure:"MaxEntries"`
+	// MaxTraceSize is the max size in bytes of a cached trace, bigger traces are not cached
+	MaxTraceSize uint64 `mapstructure:"MaxTraceSize"`
+	// Persist stores the traces in the state DB too, so they survive restarts and are shared
+	// by all the instances using the same DB
+	Persist bool `mapstructure:"Persist"`
+}
+
+// TraceIndexConfig represents the configuration of the trace index
+type TraceIndexConfig struct {
+	// Enabled makes the synchronizer execute again the transactions of every new L2 block to store
+	// their flat call traces, indexed by from and to address
+	Enabled bool `mapstructure:"Enabled"`
+	// Interval is the time to wait between indexing runs once all the L2 blocks are indexed
+	Interval types.Duration `mapstructure:"Interval"`
+	// MaxL2BlocksPerRun is the max number of L2 blocks indexed in a single run
+	MaxL2BlocksPerRun uint64 `mapstructure:"MaxL2BlocksPerRun"`
+}
+
+// PruningMode defines how much history is kept by the node
+type PruningMode string
+
+const (
+	// PruningModeArchive keeps all the receipts, logs and transactions
+	PruningModeArchive PruningMode = "archive"
+	// PruningModeFull only keeps the receipts, logs and transactions of the last verified batches
+	PruningModeFull PruningMode = "full"
+)
+
+// PruningConfig represents the configuration of the history pruning
+type PruningConfig struct {
+	// Mode is the pruning mode: archive or full. A full node can't regenerate the data stream
+	// file from the pruned batches
+	Mode PruningMode `mapstructure:"Mode"`
+	// KeepVerifiedBatches is the number of verified batches whose history is kept in full mode.
+	// The receipts, logs and transaction bodies of the L2 blocks of older batches are pruned
+	KeepVerifiedBatches uint64 `mapstructure:"KeepVerifiedBatches"`
+	// Interval is the time to wait between pruning runs
+	Interval types.Duration `mapstructure:"Interval"`
+	// MaxL2BlocksPerRun is the max number of L2 blocks pruned in a single DB transaction
+	MaxL2BlocksPerRun uint64 `mapstructure:"MaxL2BlocksPerRun"`
 }
 
 // BatchConfig represents the configuration of the batch constraints
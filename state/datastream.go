@@ -1,6 +1,7 @@
 package state
 
 import (
+	"bytes"
 	"context"
 	"math/big"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/state/datastream"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/iden3/go-iden3-crypto/keccak256"
 	"github.com/jackc/pgx/v4"
 	"google.golang.org/protobuf/proto"
@@ -27,6 +29,8 @@ const (
 	DSVersion3 uint8 = 3
 	// DSVersion4 is the second protobuf version, includes l2BlockEnd
 	DSVersion4 uint8 = 4
+	// DSVersion5 is the third protobuf version, includes the receipt of each transaction
+	DSVersion5 uint8 = 5
 )
 
 // DSBatch represents a data stream batch
@@ -76,6 +80,7 @@ type DSL2Transaction struct {
 	StateRoot                   common.Hash
 	EncodedLength               uint32
 	Encoded                     []byte
+	Receipt                     *types.Receipt
 }
 
 // DSState gathers the methods required to interact with the data stream state.
@@ -84,6 +89,7 @@ type DSState interface {
 	GetDSBatches(ctx context.Context, firstBatchNumber, lastBatchNumber uint64, readWIPBatch bool, dbTx pgx.Tx) ([]*DSBatch, error)
 	GetDSL2Blocks(ctx context.Context, firstBatchNumber, lastBatchNumber uint64, dbTx pgx.Tx) ([]*DSL2Block, error)
 	GetDSL2Transactions(ctx context.Context, firstL2Block, lastL2Block uint64, dbTx pgx.Tx) ([]*DSL2Transaction, error)
+	GetDSL2Receipts(ctx context.Context, firstL2Block, lastL2Block uint64, dbTx pgx.Tx) ([]*types.Receipt, error)
 	GetStorageAt(ctx context.Context, address common.Address, position *big.Int, root common.Hash) (*big.Int, error)
 	GetVirtualBatchParentHash(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (common.Hash, error)
 	GetForcedBatchParentHash(ctx context.Context, forcedBatchNumber uint64, dbTx pgx.Tx) (common.Hash, error)
@@ -306,15 +312,26 @@ func GenerateDataStreamFile(ctx context.Context, streamServer *datastreamer.Stre
 			previousTimestamp = l2Block.Timestamp
 			lastAddedL2BlockNumber = currentL2BlockNumber
 
-		case datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_TRANSACTION):
-			log.Info("Latest entry type is Transaction")
+		case datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_TRANSACTION), datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_RECEIPT):
+			var currentL2BlockNumber uint64
+			if latestEntry.Type == datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_TRANSACTION) {
+				log.Info("Latest entry type is Transaction")
 
-			transaction := &datastream.Transaction{}
-			if err := proto.Unmarshal(latestEntry.Data, transaction); err != nil {
-				return err
+				transaction := &datastream.Transaction{}
+				if err := proto.Unmarshal(latestEntry.Data, transaction); err != nil {
+					return err
+				}
+				currentL2BlockNumber = transaction.L2BlockNumber
+			} else {
+				log.Info("Latest entry type is Receipt")
+
+				receipt := &datastream.Receipt{}
+				if err := proto.Unmarshal(latestEntry.Data, receipt); err != nil {
+					return err
+				}
+				currentL2BlockNumber = receipt.L2BlockNumber
 			}
 
-			currentL2BlockNumber := transaction.L2BlockNumber
 			lastAddedL2BlockNumber = currentL2BlockNumber
 
 			// Get current batch number
@@ -424,6 +441,15 @@ func GenerateDataStreamFile(ctx context.Context, streamServer *datastreamer.Stre
 				log.Errorf("Error getting L2 transactions for blocks starting at %d: %s", l2Blocks[0].L2BlockNumber, err.Error())
 				return err
 			}
+
+			if version >= DSVersion5 {
+				l2Receipts, err := stateDB.GetDSL2Receipts(ctx, l2Blocks[0].L2BlockNumber, l2Blocks[len(l2Blocks)-1].L2BlockNumber, nil)
+				if err != nil {
+					log.Errorf("Error getting L2 receipts for blocks starting at %d: %s", l2Blocks[0].L2BlockNumber, err.Error())
+					return err
+				}
+				setDSL2TransactionsReceipts(l2Txs, l2Receipts)
+			}
 		}
 
 		// Generate full batches
@@ -454,10 +480,7 @@ func GenerateDataStreamFile(ctx context.Context, streamServer *datastreamer.Stre
 
 			missingBatchBookMark := true
 			if b == 0 {
-				_, err = streamServer.GetBookmark(marshalledBookMark)
-				if err == nil {
-					missingBatchBookMark = false
-				}
+				missingBatchBookMark = !dsBookmarkExists(streamServer, marshalledBookMark)
 			}
 
 			if missingBatchBookMark {
@@ -617,8 +640,7 @@ func GenerateDataStreamFile(ctx context.Context, streamServer *datastreamer.Stre
 					}
 
 					// Check if l2 block was already added
-					_, err = streamServer.GetBookmark(marshalledBookMark)
-					if err == nil {
+					if dsBookmarkExists(streamServer, marshalledBookMark) {
 						continue
 					}
 
@@ -680,6 +702,19 @@ func GenerateDataStreamFile(ctx context.Context, streamServer *datastreamer.Stre
 						if err != nil {
 							return err
 						}
+
+						// The receipt is missing if it has been pruned
+						if version >= DSVersion5 && tx.Receipt != nil {
+							marshalledReceipt, err := proto.Marshal(NewDSReceipt(tx.L2BlockNumber, tx.Index, tx.Receipt))
+							if err != nil {
+								return err
+							}
+
+							_, err = streamServer.AddStreamEntry(datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_RECEIPT), marshalledReceipt)
+							if err != nil {
+								return err
+							}
+						}
 					}
 
 					currentGER = l2Block.GlobalExitRoot
@@ -731,6 +766,67 @@ func GenerateDataStreamFile(ctx context.Context, streamServer *datastreamer.Stre
 	return err
 }
 
+// NewDSReceipt builds the data stream receipt entry of the tx with the given index in the L2 block
+func NewDSReceipt(l2BlockNumber, txIndex uint64, receipt *types.Receipt) *datastream.Receipt {
+	dsReceipt := &datastream.Receipt{
+		L2BlockNumber:     l2BlockNumber,
+		Index:             txIndex,
+		TxHash:            receipt.TxHash.Bytes(),
+		Status:            receipt.Status,
+		CumulativeGasUsed: receipt.CumulativeGasUsed,
+		GasUsed:           receipt.GasUsed,
+		ContractAddress:   receipt.ContractAddress.Bytes(),
+		Logs:              make([]*datastream.Log, 0, len(receipt.Logs)),
+	}
+
+	if receipt.EffectiveGasPrice != nil {
+		dsReceipt.EffectiveGasPrice = receipt.EffectiveGasPrice.Bytes()
+	}
+
+	for _, l := range receipt.Logs {
+		dsLog := &datastream.Log{
+			Address: l.Address.Bytes(),
+			Topics:  make([][]byte, 0, len(l.Topics)),
+			Data:    l.Data,
+			Index:   uint64(l.Index),
+		}
+		for _, topic := range l.Topics {
+			dsLog.Topics = append(dsLog.Topics, topic.Bytes())
+		}
+		dsReceipt.Logs = append(dsReceipt.Logs, dsLog)
+	}
+
+	return dsReceipt
+}
+
+// setDSL2TransactionsReceipts sets to each tx its receipt, matching them by L2 block number and tx index
+func setDSL2TransactionsReceipts(l2Txs []*DSL2Transaction, receipts []*types.Receipt) {
+	type txKey struct {
+		l2BlockNumber uint64
+		index         uint64
+	}
+
+	receiptsByTx := make(map[txKey]*types.Receipt, len(receipts))
+	for _, receipt := range receipts {
+		receiptsByTx[txKey{l2BlockNumber: receipt.BlockNumber.Uint64(), index: uint64(receipt.TransactionIndex)}] = receipt
+	}
+
+	for _, l2Tx := range l2Txs {
+		l2Tx.Receipt = receiptsByTx[txKey{l2BlockNumber: l2Tx.L2BlockNumber, index: l2Tx.Index}]
+	}
+}
+
+// dsBookmarkExists returns if the bookmark is in the data stream file. The bookmarks are kept when the file is
+// truncated, so the entry they point to must be the bookmark itself
+func dsBookmarkExists(streamServer *datastreamer.StreamServer, marshalledBookMark []byte) bool {
+	entryNumber, err := streamServer.GetBookmark(marshalledBookMark)
+	if err != nil || entryNumber >= streamServer.GetHeader().TotalEntries {
+		return false
+	}
+	entry, err := streamServer.GetEntry(entryNumber)
+	return err == nil && entry.Type == EntryTypeBookMark && bytes.Equal(entry.Data, marshalledBookMark)
+}
+
 // GetSystemSCPosition computes the position of the intermediate state root for the system smart contract
 func GetSystemSCPosition(blockNumber uint64) []byte {
 	v1 := big.NewInt(0).SetUint64(blockNumber).Bytes()
@@ -0,0 +1,72 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/state/datastream"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewDSReceipt(t *testing.T) {
+	receipt := &types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 42000,
+		GasUsed:           21000,
+		TxHash:            common.HexToHash("0x1"),
+		ContractAddress:   common.HexToAddress("0x2"),
+		EffectiveGasPrice: big.NewInt(1000000000),
+		Logs: []*types.Log{
+			{
+				Address: common.HexToAddress("0x3"),
+				Topics:  []common.Hash{common.HexToHash("0x4"), common.HexToHash("0x5")},
+				Data:    []byte{0x6},
+				Index:   7,
+			},
+		},
+	}
+
+	dsReceipt := NewDSReceipt(10, 1, receipt)
+	marshalled, err := proto.Marshal(dsReceipt)
+	require.NoError(t, err)
+
+	// a receipt entry can be decoded back
+	decoded := &datastream.Receipt{}
+	require.NoError(t, proto.Unmarshal(marshalled, decoded))
+	assert.Equal(t, uint64(10), decoded.L2BlockNumber)
+	assert.Equal(t, uint64(1), decoded.Index)
+	assert.Equal(t, receipt.TxHash, common.BytesToHash(decoded.TxHash))
+	assert.Equal(t, receipt.Status, decoded.Status)
+	assert.Equal(t, receipt.CumulativeGasUsed, decoded.CumulativeGasUsed)
+	assert.Equal(t, receipt.GasUsed, decoded.GasUsed)
+	assert.Equal(t, receipt.ContractAddress, common.BytesToAddress(decoded.ContractAddress))
+	assert.Equal(t, receipt.EffectiveGasPrice, new(big.Int).SetBytes(decoded.EffectiveGasPrice))
+	require.Len(t, decoded.Logs, 1)
+	assert.Equal(t, receipt.Logs[0].Address, common.BytesToAddress(decoded.Logs[0].Address))
+	assert.Equal(t, [][]byte{receipt.Logs[0].Topics[0].Bytes(), receipt.Logs[0].Topics[1].Bytes()}, decoded.Logs[0].Topics)
+	assert.Equal(t, receipt.Logs[0].Data, decoded.Logs[0].Data)
+	assert.Equal(t, uint64(7), decoded.Logs[0].Index)
+}
+
+func TestSetDSL2TransactionsReceipts(t *testing.T) {
+	l2Txs := []*DSL2Transaction{
+		{L2BlockNumber: 1, Index: 0},
+		{L2BlockNumber: 2, Index: 0},
+		{L2BlockNumber: 2, Index: 1},
+	}
+	receipts := []*types.Receipt{
+		{BlockNumber: big.NewInt(2), TransactionIndex: 1, TxHash: common.HexToHash("0x21")},
+		{BlockNumber: big.NewInt(1), TransactionIndex: 0, TxHash: common.HexToHash("0x10")},
+	}
+
+	setDSL2TransactionsReceipts(l2Txs, receipts)
+
+	assert.Equal(t, common.HexToHash("0x10"), l2Txs[0].Receipt.TxHash)
+	// the receipt of a pruned tx is missing
+	assert.Nil(t, l2Txs[1].Receipt)
+	assert.Equal(t, common.HexToHash("0x21"), l2Txs[2].Receipt.TxHash)
+}
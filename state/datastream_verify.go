@@ -0,0 +1,363 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-data-streamer/datastreamer"
+	"github.com/0xPolygonHermez/zkevm-node/state/datastream"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/protobuf/proto"
+)
+
+// DSMismatch describes the first entry of a data stream file that doesn't match the state
+type DSMismatch struct {
+	// EntryNumber is the number of the entry that doesn't match the state
+	EntryNumber uint64
+	// BatchNumber is the batch the entry belongs to
+	BatchNumber uint64
+	// TruncateEntryNumber is the entry the file must be truncated from to regenerate it, the bookmark of the batch
+	TruncateEntryNumber uint64
+	// Reason describes the mismatch
+	Reason string
+}
+
+func (m *DSMismatch) String() string {
+	return fmt.Sprintf("entry %d of batch %d: %s", m.EntryNumber, m.BatchNumber, m.Reason)
+}
+
+// dsVerifier keeps the data of the state for the batch and L2 block of the data stream entry being verified
+type dsVerifier struct {
+	stateDB        DSState
+	genesisL2Block *DSL2Block
+
+	batch              *DSBatch
+	batchEntryNumber   uint64
+	batchEnded         bool
+	l2Blocks           []*DSL2Block
+	l2BlockIndex       int
+	l2Block            *DSL2Block
+	l2BlockEntryFound  bool
+	l2Txs              map[uint64][]*DSL2Transaction
+	l2TxIndex          int
+	lastL2BlockNumber  uint64
+	lastL2BlockStarted bool
+}
+
+// VerifyDataStreamFile compares the entries of the data stream file with the state, starting from the first batch
+// bookmark at or after the given entry. The L2 block hashes and state roots, the transactions and the batch ends
+// are checked, along with the order of the batches and the L2 blocks. It returns the first entry that doesn't
+// match the state, or nil if all of them match. The entries after the last one that is found in the state aren't
+// a mismatch, as they are added when the file is generated
+func VerifyDataStreamFile(ctx context.Context, streamServer *datastreamer.StreamServer, stateDB DSState, fromEntryNumber uint64) (*DSMismatch, error) {
+	v := &dsVerifier{stateDB: stateDB}
+
+	header := streamServer.GetHeader()
+	for entryNumber := fromEntryNumber; entryNumber < header.TotalEntries; entryNumber++ {
+		entry, err := streamServer.GetEntry(entryNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get data stream entry %d: %w", entryNumber, err)
+		}
+
+		// Skip the entries of the batch the verification starts in the middle of
+		if v.batch == nil && !isDSBatchBookmark(entry) {
+			continue
+		}
+
+		reason, err := v.verifyEntry(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			return v.mismatch(entry.Number, reason), nil
+		}
+	}
+
+	if v.batch != nil {
+		if reason := v.endL2Block(); reason != "" {
+			return v.mismatch(header.TotalEntries-1, reason), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GetDSLatestBatchesEntryNumber returns the entry number of the bookmark of the first of the given number of
+// latest batches of the data stream file, or 0 if the file has fewer batches
+func GetDSLatestBatchesEntryNumber(streamServer *datastreamer.StreamServer, batches uint64) (uint64, error) {
+	var found uint64 = 0
+	for entryNumber := streamServer.GetHeader().TotalEntries; entryNumber > 0 && found < batches; entryNumber-- {
+		entry, err := streamServer.GetEntry(entryNumber - 1)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get data stream entry %d: %w", entryNumber-1, err)
+		}
+		if isDSBatchBookmark(entry) {
+			found++
+			if found == batches {
+				return entry.Number, nil
+			}
+		}
+	}
+	return 0, nil
+}
+
+func isDSBatchBookmark(entry datastreamer.FileEntry) bool {
+	if entry.Type != EntryTypeBookMark {
+		return false
+	}
+	bookMark := &datastream.BookMark{}
+	if err := proto.Unmarshal(entry.Data, bookMark); err != nil {
+		return false
+	}
+	return bookMark.Type == datastream.BookmarkType_BOOKMARK_TYPE_BATCH
+}
+
+func (v *dsVerifier) mismatch(entryNumber uint64, reason string) *DSMismatch {
+	m := &DSMismatch{
+		EntryNumber:         entryNumber,
+		TruncateEntryNumber: v.batchEntryNumber,
+		Reason:              reason,
+	}
+	if v.batch != nil {
+		m.BatchNumber = v.batch.BatchNumber
+	}
+	return m
+}
+
+// verifyEntry checks a data stream entry against the state, returning the reason of the mismatch if it doesn't match
+func (v *dsVerifier) verifyEntry(ctx context.Context, entry datastreamer.FileEntry) (string, error) {
+	switch entry.Type {
+	case EntryTypeBookMark:
+		bookMark := &datastream.BookMark{}
+		if err := proto.Unmarshal(entry.Data, bookMark); err != nil {
+			return fmt.Sprintf("failed to unmarshal bookmark: %v", err), nil
+		}
+
+		switch bookMark.Type {
+		case datastream.BookmarkType_BOOKMARK_TYPE_BATCH:
+			return v.startBatch(ctx, entry.Number, bookMark.Value)
+		case datastream.BookmarkType_BOOKMARK_TYPE_L2_BLOCK:
+			return v.startL2Block(bookMark.Value), nil
+		default:
+			return fmt.Sprintf("unexpected bookmark type %v", bookMark.Type), nil
+		}
+
+	case datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_BATCH_START):
+		batchStart := &datastream.BatchStart{}
+		if err := proto.Unmarshal(entry.Data, batchStart); err != nil {
+			return fmt.Sprintf("failed to unmarshal batch start: %v", err), nil
+		}
+		if batchStart.Number != v.batch.BatchNumber {
+			return fmt.Sprintf("batch start of batch %d, expected batch %d", batchStart.Number, v.batch.BatchNumber), nil
+		}
+
+	case datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_L2_BLOCK):
+		l2Block := &datastream.L2Block{}
+		if err := proto.Unmarshal(entry.Data, l2Block); err != nil {
+			return fmt.Sprintf("failed to unmarshal L2 block: %v", err), nil
+		}
+		if v.l2Block == nil || v.l2BlockEntryFound || l2Block.Number != v.l2Block.L2BlockNumber {
+			return fmt.Sprintf("unexpected L2 block %d", l2Block.Number), nil
+		}
+		if l2Block.BatchNumber != v.l2Block.BatchNumber {
+			return fmt.Sprintf("L2 block %d has batch number %d, state has %d", l2Block.Number, l2Block.BatchNumber, v.l2Block.BatchNumber), nil
+		}
+		if common.BytesToHash(l2Block.Hash) != v.l2Block.BlockHash {
+			return fmt.Sprintf("L2 block %d has hash %s, state has %s", l2Block.Number, common.BytesToHash(l2Block.Hash), v.l2Block.BlockHash), nil
+		}
+		if common.BytesToHash(l2Block.StateRoot) != v.l2Block.StateRoot {
+			return fmt.Sprintf("L2 block %d has state root %s, state has %s", l2Block.Number, common.BytesToHash(l2Block.StateRoot), v.l2Block.StateRoot), nil
+		}
+		v.l2BlockEntryFound = true
+
+	case datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_TRANSACTION):
+		tx := &datastream.Transaction{}
+		if err := proto.Unmarshal(entry.Data, tx); err != nil {
+			return fmt.Sprintf("failed to unmarshal transaction: %v", err), nil
+		}
+		if !v.l2BlockEntryFound || tx.L2BlockNumber != v.l2Block.L2BlockNumber {
+			return fmt.Sprintf("unexpected transaction of L2 block %d", tx.L2BlockNumber), nil
+		}
+		l2Txs := v.l2Txs[tx.L2BlockNumber]
+		if v.l2TxIndex >= len(l2Txs) {
+			return fmt.Sprintf("L2 block %d has more transactions than the %d of the state", tx.L2BlockNumber, len(l2Txs)), nil
+		}
+		if !bytes.Equal(tx.Encoded, l2Txs[v.l2TxIndex].Encoded) {
+			return fmt.Sprintf("transaction %d of L2 block %d doesn't match the state", v.l2TxIndex, tx.L2BlockNumber), nil
+		}
+		v.l2TxIndex++
+
+	case datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_RECEIPT):
+		receipt := &datastream.Receipt{}
+		if err := proto.Unmarshal(entry.Data, receipt); err != nil {
+			return fmt.Sprintf("failed to unmarshal receipt: %v", err), nil
+		}
+		if !v.l2BlockEntryFound || receipt.L2BlockNumber != v.l2Block.L2BlockNumber {
+			return fmt.Sprintf("unexpected receipt of L2 block %d", receipt.L2BlockNumber), nil
+		}
+
+	case datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_L2_BLOCK_END):
+		l2BlockEnd := &datastream.L2BlockEnd{}
+		if err := proto.Unmarshal(entry.Data, l2BlockEnd); err != nil {
+			return fmt.Sprintf("failed to unmarshal L2 block end: %v", err), nil
+		}
+		if !v.l2BlockEntryFound || l2BlockEnd.Number != v.l2Block.L2BlockNumber {
+			return fmt.Sprintf("unexpected L2 block end of L2 block %d", l2BlockEnd.Number), nil
+		}
+		return v.endL2Block(), nil
+
+	case datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_UPDATE_GER):
+		updateGER := &datastream.UpdateGER{}
+		if err := proto.Unmarshal(entry.Data, updateGER); err != nil {
+			return fmt.Sprintf("failed to unmarshal update GER: %v", err), nil
+		}
+		if updateGER.BatchNumber != v.batch.BatchNumber {
+			return fmt.Sprintf("update GER of batch %d, expected batch %d", updateGER.BatchNumber, v.batch.BatchNumber), nil
+		}
+
+	case datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_BATCH_END):
+		batchEnd := &datastream.BatchEnd{}
+		if err := proto.Unmarshal(entry.Data, batchEnd); err != nil {
+			return fmt.Sprintf("failed to unmarshal batch end: %v", err), nil
+		}
+		return v.endBatch(batchEnd), nil
+
+	default:
+		return fmt.Sprintf("unexpected entry type %d", entry.Type), nil
+	}
+
+	return "", nil
+}
+
+// startBatch checks the bookmark of a new batch and loads the batch from the state
+func (v *dsVerifier) startBatch(ctx context.Context, entryNumber uint64, batchNumber uint64) (string, error) {
+	if v.batch != nil {
+		if reason := v.endL2Block(); reason != "" {
+			return reason, nil
+		}
+		if !v.batchEnded {
+			return fmt.Sprintf("batch %d has no batch end", v.batch.BatchNumber), nil
+		}
+	}
+
+	prevBatch := v.batch
+	v.batch = &DSBatch{Batch: Batch{BatchNumber: batchNumber}}
+	v.batchEntryNumber = entryNumber
+	v.batchEnded = false
+	v.l2Blocks = nil
+	v.l2BlockIndex = 0
+	v.l2Block = nil
+	v.l2Txs = nil
+
+	if prevBatch != nil && batchNumber != prevBatch.BatchNumber+1 {
+		return fmt.Sprintf("bookmark of batch %d, expected batch %d", batchNumber, prevBatch.BatchNumber+1), nil
+	}
+
+	if v.genesisL2Block == nil {
+		var err error
+		v.genesisL2Block, err = v.stateDB.GetDSGenesisBlock(ctx, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to get genesis block: %w", err)
+		}
+	}
+
+	// The genesis batch of the data stream only has the genesis block
+	if batchNumber == v.genesisL2Block.BatchNumber {
+		v.batch.StateRoot = v.genesisL2Block.StateRoot
+		v.l2Blocks = []*DSL2Block{v.genesisL2Block}
+		return "", nil
+	}
+
+	batches, err := v.stateDB.GetDSBatches(ctx, batchNumber, batchNumber, true, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get batch %d: %w", batchNumber, err)
+	}
+	if len(batches) == 0 {
+		return fmt.Sprintf("batch %d not found in the state", batchNumber), nil
+	}
+	v.batch = batches[0]
+
+	v.l2Blocks, err = v.stateDB.GetDSL2Blocks(ctx, batchNumber, batchNumber, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get L2 blocks of batch %d: %w", batchNumber, err)
+	}
+
+	v.l2Txs = make(map[uint64][]*DSL2Transaction)
+	if len(v.l2Blocks) > 0 {
+		l2Txs, err := v.stateDB.GetDSL2Transactions(ctx, v.l2Blocks[0].L2BlockNumber, v.l2Blocks[len(v.l2Blocks)-1].L2BlockNumber, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to get L2 transactions of batch %d: %w", batchNumber, err)
+		}
+		for _, l2Tx := range l2Txs {
+			v.l2Txs[l2Tx.L2BlockNumber] = append(v.l2Txs[l2Tx.L2BlockNumber], l2Tx)
+		}
+	}
+
+	return "", nil
+}
+
+// startL2Block checks the bookmark of a new L2 block against the next L2 block of the batch in the state
+func (v *dsVerifier) startL2Block(l2BlockNumber uint64) string {
+	if reason := v.endL2Block(); reason != "" {
+		return reason
+	}
+	if v.batchEnded {
+		return fmt.Sprintf("bookmark of L2 block %d after the batch end", l2BlockNumber)
+	}
+	if v.lastL2BlockStarted && l2BlockNumber != v.lastL2BlockNumber+1 {
+		return fmt.Sprintf("bookmark of L2 block %d, expected L2 block %d", l2BlockNumber, v.lastL2BlockNumber+1)
+	}
+	if v.l2BlockIndex >= len(v.l2Blocks) || v.l2Blocks[v.l2BlockIndex].L2BlockNumber != l2BlockNumber {
+		return fmt.Sprintf("L2 block %d not found in batch %d of the state", l2BlockNumber, v.batch.BatchNumber)
+	}
+
+	v.l2Block = v.l2Blocks[v.l2BlockIndex]
+	v.l2BlockIndex++
+	v.l2BlockEntryFound = false
+	v.l2TxIndex = 0
+	v.lastL2BlockNumber = l2BlockNumber
+	v.lastL2BlockStarted = true
+	return ""
+}
+
+// endL2Block checks that the current L2 block has all the transactions of the state
+func (v *dsVerifier) endL2Block() string {
+	if v.l2Block == nil {
+		return ""
+	}
+	l2Block, l2BlockEntryFound := v.l2Block, v.l2BlockEntryFound
+	v.l2Block, v.l2BlockEntryFound = nil, false
+
+	if !l2BlockEntryFound {
+		return fmt.Sprintf("L2 block %d not found after its bookmark", l2Block.L2BlockNumber)
+	}
+	if l2Txs := v.l2Txs[l2Block.L2BlockNumber]; v.l2TxIndex != len(l2Txs) {
+		return fmt.Sprintf("L2 block %d has %d transactions, state has %d", l2Block.L2BlockNumber, v.l2TxIndex, len(l2Txs))
+	}
+	return ""
+}
+
+// endBatch checks the batch end against the state
+func (v *dsVerifier) endBatch(batchEnd *datastream.BatchEnd) string {
+	if reason := v.endL2Block(); reason != "" {
+		return reason
+	}
+	if v.batchEnded || batchEnd.Number != v.batch.BatchNumber {
+		return fmt.Sprintf("unexpected batch end of batch %d", batchEnd.Number)
+	}
+	if v.batch.WIP {
+		return fmt.Sprintf("batch end of batch %d that is still open in the state", batchEnd.Number)
+	}
+	if v.l2BlockIndex != len(v.l2Blocks) {
+		return fmt.Sprintf("batch %d has %d L2 blocks, state has %d", batchEnd.Number, v.l2BlockIndex, len(v.l2Blocks))
+	}
+	if common.BytesToHash(batchEnd.StateRoot) != v.batch.StateRoot {
+		return fmt.Sprintf("batch end has state root %s, state has %s", common.BytesToHash(batchEnd.StateRoot), v.batch.StateRoot)
+	}
+	if common.BytesToHash(batchEnd.LocalExitRoot) != v.batch.LocalExitRoot {
+		return fmt.Sprintf("batch end has local exit root %s, state has %s", common.BytesToHash(batchEnd.LocalExitRoot), v.batch.LocalExitRoot)
+	}
+	v.batchEnded = true
+	return ""
+}
@@ -0,0 +1,241 @@
+package state
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-data-streamer/datastreamer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDSState is a DSState that keeps the batches, L2 blocks and transactions in memory
+type fakeDSState struct {
+	genesis  *DSL2Block
+	batches  []*DSBatch
+	l2Blocks []*DSL2Block
+	l2Txs    []*DSL2Transaction
+}
+
+func (f *fakeDSState) GetDSGenesisBlock(ctx context.Context, dbTx pgx.Tx) (*DSL2Block, error) {
+	return f.genesis, nil
+}
+
+func (f *fakeDSState) GetDSBatches(ctx context.Context, firstBatchNumber, lastBatchNumber uint64, readWIPBatch bool, dbTx pgx.Tx) ([]*DSBatch, error) {
+	batches := []*DSBatch{}
+	for _, b := range f.batches {
+		if b.BatchNumber >= firstBatchNumber && b.BatchNumber <= lastBatchNumber && (readWIPBatch || !b.WIP) {
+			batches = append(batches, b)
+		}
+	}
+	return batches, nil
+}
+
+func (f *fakeDSState) GetDSL2Blocks(ctx context.Context, firstBatchNumber, lastBatchNumber uint64, dbTx pgx.Tx) ([]*DSL2Block, error) {
+	l2Blocks := []*DSL2Block{}
+	for _, b := range f.l2Blocks {
+		if b.BatchNumber >= firstBatchNumber && b.BatchNumber <= lastBatchNumber {
+			l2Blocks = append(l2Blocks, b)
+		}
+	}
+	return l2Blocks, nil
+}
+
+func (f *fakeDSState) GetDSL2Transactions(ctx context.Context, firstL2Block, lastL2Block uint64, dbTx pgx.Tx) ([]*DSL2Transaction, error) {
+	l2Txs := []*DSL2Transaction{}
+	for _, tx := range f.l2Txs {
+		if tx.L2BlockNumber >= firstL2Block && tx.L2BlockNumber <= lastL2Block {
+			l2Txs = append(l2Txs, tx)
+		}
+	}
+	return l2Txs, nil
+}
+
+func (f *fakeDSState) GetDSL2Receipts(ctx context.Context, firstL2Block, lastL2Block uint64, dbTx pgx.Tx) ([]*types.Receipt, error) {
+	return nil, nil
+}
+
+func (f *fakeDSState) GetStorageAt(ctx context.Context, address common.Address, position *big.Int, root common.Hash) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (f *fakeDSState) GetVirtualBatchParentHash(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (common.Hash, error) {
+	return common.Hash{}, nil
+}
+
+func (f *fakeDSState) GetForcedBatchParentHash(ctx context.Context, forcedBatchNumber uint64, dbTx pgx.Tx) (common.Hash, error) {
+	return common.Hash{}, nil
+}
+
+func (f *fakeDSState) GetL1InfoRootLeafByIndex(ctx context.Context, l1InfoTreeIndex uint32, dbTx pgx.Tx) (L1InfoTreeExitRootStorageEntry, error) {
+	return L1InfoTreeExitRootStorageEntry{}, nil
+}
+
+// newFakeDSState returns a state with 3 batches of 2 L2 blocks with a transaction each, the last batch is WIP
+func newFakeDSState() *fakeDSState {
+	f := &fakeDSState{
+		genesis: &DSL2Block{BlockHash: common.HexToHash("0x100"), StateRoot: common.HexToHash("0x200")},
+	}
+	for batchNumber := uint64(1); batchNumber <= 3; batchNumber++ {
+		f.batches = append(f.batches, &DSBatch{
+			Batch: Batch{
+				BatchNumber:   batchNumber,
+				StateRoot:     common.BigToHash(big.NewInt(int64(0x300 + batchNumber))),
+				LocalExitRoot: common.BigToHash(big.NewInt(int64(0x400 + batchNumber))),
+				Timestamp:     time.Unix(int64(batchNumber), 0),
+				WIP:           batchNumber == 3,
+			},
+			ForkID: FORKID_DRAGONFRUIT,
+		})
+		for i := uint64(0); i < 2; i++ {
+			l2BlockNumber := batchNumber*2 - 1 + i
+			f.l2Blocks = append(f.l2Blocks, &DSL2Block{
+				BatchNumber:   batchNumber,
+				L2BlockNumber: l2BlockNumber,
+				Timestamp:     l2BlockNumber,
+				ForkID:        FORKID_DRAGONFRUIT,
+				BlockHash:     common.BigToHash(big.NewInt(int64(0x500 + l2BlockNumber))),
+				StateRoot:     common.BigToHash(big.NewInt(int64(0x600 + l2BlockNumber))),
+			})
+			f.l2Txs = append(f.l2Txs, &DSL2Transaction{
+				L2BlockNumber: l2BlockNumber,
+				Encoded:       []byte{byte(l2BlockNumber)},
+			})
+		}
+	}
+	return f
+}
+
+func newTestDSServer(t *testing.T) *datastreamer.StreamServer {
+	streamServer, err := datastreamer.NewServer(0, DSVersion4, 1, StreamTypeSequencer, filepath.Join(t.TempDir(), "datastream.bin"),
+		5*time.Second, time.Minute, 5*time.Second, nil)
+	require.NoError(t, err)
+	// Port 0 listens on a free port, the server must be started to add entries
+	require.NoError(t, streamServer.Start())
+	return streamServer
+}
+
+func TestVerifyDataStreamFile(t *testing.T) {
+	ctx := context.Background()
+
+	// The entries of the genesis batch are 0-5, the ones of batch 1 are 6-16, the ones of batch 2 are 17-27 and the
+	// ones of the WIP batch 3, that has no batch end, are 28-37
+	testCases := []struct {
+		name             string
+		update           func(f *fakeDSState)
+		fromEntryNumber  uint64
+		expectedMismatch *DSMismatch
+	}{
+		{
+			name:   "matching file",
+			update: func(f *fakeDSState) {},
+		},
+		{
+			name:             "genesis block hash",
+			update:           func(f *fakeDSState) { f.genesis.BlockHash = common.HexToHash("0x1") },
+			expectedMismatch: &DSMismatch{EntryNumber: 3, BatchNumber: 0, TruncateEntryNumber: 0},
+		},
+		{
+			name:             "L2 block hash",
+			update:           func(f *fakeDSState) { f.l2Blocks[3].BlockHash = common.HexToHash("0x1") },
+			expectedMismatch: &DSMismatch{EntryNumber: 24, BatchNumber: 2, TruncateEntryNumber: 17},
+		},
+		{
+			name:             "L2 block state root",
+			update:           func(f *fakeDSState) { f.l2Blocks[0].StateRoot = common.HexToHash("0x1") },
+			expectedMismatch: &DSMismatch{EntryNumber: 9, BatchNumber: 1, TruncateEntryNumber: 6},
+		},
+		{
+			name:             "transaction",
+			update:           func(f *fakeDSState) { f.l2Txs[4].Encoded = []byte{0x1} },
+			expectedMismatch: &DSMismatch{EntryNumber: 32, BatchNumber: 3, TruncateEntryNumber: 28},
+		},
+		{
+			name: "missing transaction",
+			update: func(f *fakeDSState) {
+				f.l2Txs = append(f.l2Txs, &DSL2Transaction{L2BlockNumber: 3, Encoded: []byte{0x1}})
+			},
+			expectedMismatch: &DSMismatch{EntryNumber: 22, BatchNumber: 2, TruncateEntryNumber: 17},
+		},
+		{
+			name:             "batch end local exit root",
+			update:           func(f *fakeDSState) { f.batches[1].LocalExitRoot = common.HexToHash("0x1") },
+			expectedMismatch: &DSMismatch{EntryNumber: 27, BatchNumber: 2, TruncateEntryNumber: 17},
+		},
+		{
+			name: "batch not found",
+			update: func(f *fakeDSState) {
+				f.batches = f.batches[:1]
+				f.l2Blocks = f.l2Blocks[:2]
+			},
+			expectedMismatch: &DSMismatch{EntryNumber: 17, BatchNumber: 2, TruncateEntryNumber: 17},
+		},
+		{
+			name:            "verified from the middle of a batch",
+			update:          func(f *fakeDSState) { f.l2Blocks[0].StateRoot = common.HexToHash("0x1") },
+			fromEntryNumber: 7,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stateDB := newFakeDSState()
+			streamServer := newTestDSServer(t)
+			require.NoError(t, GenerateDataStreamFile(ctx, streamServer, stateDB, true, nil, 1, 0, DSVersion4))
+			require.Equal(t, uint64(38), streamServer.GetHeader().TotalEntries)
+
+			tc.update(stateDB)
+			mismatch, err := VerifyDataStreamFile(ctx, streamServer, stateDB, tc.fromEntryNumber)
+			require.NoError(t, err)
+			if tc.expectedMismatch == nil {
+				assert.Nil(t, mismatch)
+				return
+			}
+			require.NotNil(t, mismatch)
+			assert.Equal(t, tc.expectedMismatch.EntryNumber, mismatch.EntryNumber)
+			assert.Equal(t, tc.expectedMismatch.BatchNumber, mismatch.BatchNumber)
+			assert.Equal(t, tc.expectedMismatch.TruncateEntryNumber, mismatch.TruncateEntryNumber)
+			assert.NotEmpty(t, mismatch.Reason)
+		})
+	}
+}
+
+func TestVerifyDataStreamFileRegenerate(t *testing.T) {
+	ctx := context.Background()
+	stateDB := newFakeDSState()
+	streamServer := newTestDSServer(t)
+	require.NoError(t, GenerateDataStreamFile(ctx, streamServer, stateDB, true, nil, 1, 0, DSVersion4))
+
+	stateDB.l2Blocks[3].BlockHash = common.HexToHash("0x1")
+	mismatch, err := VerifyDataStreamFile(ctx, streamServer, stateDB, 0)
+	require.NoError(t, err)
+	require.NotNil(t, mismatch)
+
+	// The bookmarks of the truncated entries are kept, but the file is regenerated from the truncated batch
+	require.NoError(t, streamServer.TruncateFile(mismatch.TruncateEntryNumber))
+	require.NoError(t, GenerateDataStreamFile(ctx, streamServer, stateDB, true, nil, 1, 0, DSVersion4))
+	assert.Equal(t, uint64(38), streamServer.GetHeader().TotalEntries)
+
+	mismatch, err = VerifyDataStreamFile(ctx, streamServer, stateDB, 0)
+	require.NoError(t, err)
+	assert.Nil(t, mismatch)
+}
+
+func TestGetDSLatestBatchesEntryNumber(t *testing.T) {
+	streamServer := newTestDSServer(t)
+	require.NoError(t, GenerateDataStreamFile(context.Background(), streamServer, newFakeDSState(), true, nil, 1, 0, DSVersion4))
+
+	entryNumber, err := GetDSLatestBatchesEntryNumber(streamServer, 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(17), entryNumber)
+
+	entryNumber, err = GetDSLatestBatchesEntryNumber(streamServer, 10)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), entryNumber)
+}
@@ -3,6 +3,7 @@ This file provide functions to work with ETROG batches:
 - EncodeBatchV2 (equivalent to EncodeTransactions)
 - DecodeBatchV2 (equivalent to DecodeTxs)
 - DecodeForcedBatchV2
+- EncodeForcedBatchV2
 
 Also provide a builder class to create batches (BatchV2Encoder):
  This method doesnt check anything, so is more flexible but you need to know what you are doing
@@ -138,6 +139,24 @@ func EncodeBatchV2(batch *BatchRawV2) ([]byte, error) {
 	return encoder.GetResult(), nil
 }
 
+// EncodeForcedBatchV2 encodes a forced batch V2 (Etrog) into a byte slice.
+// The transactions are encoded without changeL2Block, as it's forbidden in the forced batches
+func EncodeForcedBatchV2(forcedBatch *ForcedBatchRawV2) ([]byte, error) {
+	if forcedBatch == nil {
+		return nil, fmt.Errorf("forced batch is nil: %w", ErrInvalidBatchV2)
+	}
+	if len(forcedBatch.Transactions) == 0 {
+		return nil, fmt.Errorf("a forced batch need minimum a transaction: %w", ErrInvalidBatchV2)
+	}
+
+	encoder := NewBatchV2Encoder()
+	err := encoder.AddTransactions(forcedBatch.Transactions)
+	if err != nil {
+		return nil, fmt.Errorf("can't encode tx: %w", err)
+	}
+	return encoder.GetResult(), nil
+}
+
 // BatchV2Encoder is a builder of the batchl2data used by EncodeBatchV2
 type BatchV2Encoder struct {
 	batchData []byte
@@ -245,6 +245,23 @@ func TestDecodeForcedBatchV2WithRegularBatch(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestDecodeEncodeForcedBatchV2(t *testing.T) {
+	batchL2Data, err := hex.DecodeString(codedRLP2Txs1)
+	require.NoError(t, err)
+	decodedBatch, err := DecodeForcedBatchV2(batchL2Data)
+	require.NoError(t, err)
+	encoded, err := EncodeForcedBatchV2(decodedBatch)
+	require.NoError(t, err)
+	require.Equal(t, batchL2Data, encoded)
+}
+
+func TestEncodeEmptyForcedBatchV2Fails(t *testing.T) {
+	_, err := EncodeForcedBatchV2(nil)
+	require.ErrorIs(t, err, ErrInvalidBatchV2)
+	_, err = EncodeForcedBatchV2(&ForcedBatchRawV2{})
+	require.ErrorIs(t, err, ErrInvalidBatchV2)
+}
+
 func TestEncodeBatchV2WithTxInBinary(t *testing.T) {
 	block1 := L2BlockRaw{
 		ChangeL2BlockHeader: ChangeL2BlockHeader{
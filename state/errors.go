@@ -16,6 +16,8 @@ var (
 	ErrStateNotSynchronized = errors.New("state not synchronized")
 	// ErrNotFound indicates an object has not been found for the search criteria used
 	ErrNotFound = errors.New("object not found")
+	// ErrHistoryPruned indicates the requested object belongs to a part of the history that has been pruned
+	ErrHistoryPruned = errors.New("history pruned")
 	// ErrNilDBTransaction indicates the db transaction has not been properly initialized
 	ErrNilDBTransaction = errors.New("database transaction not properly initialized")
 	// ErrAlreadyInitializedDBTransaction indicates the db transaction was already initialized
@@ -67,6 +69,12 @@ var (
 	// ErrMaxNativeBlockHashBlockRangeLimitExceeded returned when the range between block number range
 	// to filter native block hashes is bigger than the configured limit
 	ErrMaxNativeBlockHashBlockRangeLimitExceeded = errors.New("native block hashes are limited to a %v block range")
+	// ErrMaxTraceFilterBlockRangeLimitExceeded returned when the range between block number range
+	// to filter traces is bigger than the configured limit
+	ErrMaxTraceFilterBlockRangeLimitExceeded = errors.New("traces are limited to a %v block range")
+	// ErrSequencerLeaseLost returned when the sequencer lease is not held anymore by the holder and term
+	// that are trying to use it, because it has expired or it has been acquired by another sequencer
+	ErrSequencerLeaseLost = errors.New("sequencer lease lost")
 )
 
 // ConstructErrorFromRevert extracts the reverted reason from the provided returnValue
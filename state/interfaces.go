@@ -2,6 +2,7 @@ package state
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -75,6 +76,7 @@ type storage interface {
 	GetL2BlockTransactionCountByHash(ctx context.Context, blockHash common.Hash, dbTx pgx.Tx) (uint64, error)
 	GetL2BlockTransactionCountByNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (uint64, error)
 	GetTransactionEGPLogByHash(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*EffectiveGasPriceLog, error)
+	GetTransactionsEGPLogByL2BlockRange(ctx context.Context, fromBlockNumber, toBlockNumber uint64, dbTx pgx.Tx) ([]TransactionEGPLog, error)
 	AddL2Block(ctx context.Context, batchNumber uint64, l2Block *L2Block, receipts []*types.Receipt, txsL2Hash []common.Hash, txsEGPData []StoreTxEGPData, imStateRoots []common.Hash, dbTx pgx.Tx) error
 	GetLastVirtualizedL2BlockNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
 	GetLastConsolidatedL2BlockNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
@@ -131,6 +133,7 @@ type storage interface {
 	GetDSBatches(ctx context.Context, firstBatchNumber, lastBatchNumber uint64, readWIPBatch bool, dbTx pgx.Tx) ([]*DSBatch, error)
 	GetDSL2Blocks(ctx context.Context, firstBatchNumber, lastBatchNumber uint64, dbTx pgx.Tx) ([]*DSL2Block, error)
 	GetDSL2Transactions(ctx context.Context, firstL2Block, lastL2Block uint64, dbTx pgx.Tx) ([]*DSL2Transaction, error)
+	GetDSL2Receipts(ctx context.Context, firstL2Block, lastL2Block uint64, dbTx pgx.Tx) ([]*types.Receipt, error)
 	OpenBatchInStorage(ctx context.Context, batchContext ProcessingContext, dbTx pgx.Tx) error
 	OpenWIPBatchInStorage(ctx context.Context, batch Batch, dbTx pgx.Tx) error
 	GetWIPBatchInStorage(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*Batch, error)
@@ -167,6 +170,24 @@ type storage interface {
 	GetLatestL1InfoTreeRecursiveRoot(ctx context.Context, maxBlockNumber uint64, dbTx pgx.Tx) (L1InfoTreeRecursiveExitRootStorageEntry, error)
 	GetL1InfoRecursiveRootLeafByIndex(ctx context.Context, l1InfoTreeIndex uint32, dbTx pgx.Tx) (L1InfoTreeExitRootStorageEntry, error)
 
+	GetFirstKeptL2BlockNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
+	PruneHistory(ctx context.Context, toBatchNumber uint64, maxL2Blocks uint64, dbTx pgx.Tx) (uint64, error)
+	AddL2BlockTraces(ctx context.Context, l2BlockNumber uint64, l2BlockHash common.Hash, traces []IndexedTrace, dbTx pgx.Tx) error
+	GetLastIndexedTraceL2BlockNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
+	GetTraces(ctx context.Context, filter TraceFilter, dbTx pgx.Tx) ([]json.RawMessage, error)
+	AddCachedTrace(ctx context.Context, cacheKey string, l2BlockNumber uint64, l2BlockHash common.Hash, trace []byte, dbTx pgx.Tx) error
+	GetCachedTrace(ctx context.Context, cacheKey string, l2BlockHash common.Hash, dbTx pgx.Tx) ([]byte, error)
+	AcquireSequencerLease(ctx context.Context, holder string, duration time.Duration, dbTx pgx.Tx) (uint64, bool, error)
+	RenewSequencerLease(ctx context.Context, holder string, term uint64, duration time.Duration, dbTx pgx.Tx) (bool, error)
+	ReleaseSequencerLease(ctx context.Context, holder string, term uint64, dbTx pgx.Tx) error
+	CheckSequencerLease(ctx context.Context, holder string, term uint64, dbTx pgx.Tx) error
+	ScheduleSequencerHalt(ctx context.Context, batchNumber uint64, haltAt *time.Time, reason string, dbTx pgx.Tx) error
+	SetSequencerHalted(ctx context.Context, dbTx pgx.Tx) (bool, error)
+	ResumeSequencer(ctx context.Context, reason string, dbTx pgx.Tx) (bool, error)
+	GetSequencerHalt(ctx context.Context, dbTx pgx.Tx) (*SequencerHalt, error)
+	SetForceBatchTimeout(ctx context.Context, timeout time.Duration, dbTx pgx.Tx) error
+	GetForceBatchTimeout(ctx context.Context, dbTx pgx.Tx) (time.Duration, error)
+
 	storeblobsequences
 	storeblobinner
 }
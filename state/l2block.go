@@ -103,6 +103,10 @@ type L2Block struct {
 
 	ReceivedAt   time.Time
 	ReceivedFrom interface{}
+
+	// PrunedTxHashes are the hashes of the transactions of the block whose bodies have been pruned,
+	// which are not included in the transactions of the block
+	PrunedTxHashes []common.Hash
 }
 
 // GlobalExitRoot returns the header GlobalExitRoot
@@ -17,6 +17,19 @@ const (
 	ExecutorProcessingTimeName = Prefix + "executor_processing_time"
 	// CallerLabelName is the name of the label for the caller.
 	CallerLabelName = "caller"
+	// TraceCacheHitsName is the name of the metric that counts the traces served from the trace cache.
+	TraceCacheHitsName = Prefix + "trace_cache_hits"
+	// TraceCacheMissesName is the name of the metric that counts the traces not found in the trace cache.
+	TraceCacheMissesName = Prefix + "trace_cache_misses"
+	// TraceCacheEntriesName is the name of the metric that shows the number of traces kept in memory by the trace cache.
+	TraceCacheEntriesName = Prefix + "trace_cache_entries"
+	// TraceCacheSourceLabelName is the name of the label for the storage a cached trace is read from.
+	TraceCacheSourceLabelName = "source"
+
+	// TraceCacheMemorySource is used when the cached trace is read from memory
+	TraceCacheMemorySource = "memory"
+	// TraceCacheDBSource is used when the cached trace is read from the state DB
+	TraceCacheDBSource = "db"
 
 	// SequencerCallerLabel is used when sequencer is calling the function
 	SequencerCallerLabel CallerLabel = "sequencer"
@@ -38,7 +51,34 @@ func Register() {
 		},
 	}
 
+	counterVecs := []metrics.CounterVecOpts{
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: TraceCacheHitsName,
+				Help: "[STATE] number of traces served from the trace cache",
+			},
+			Labels: []string{TraceCacheSourceLabelName},
+		},
+	}
+
+	counters := []prometheus.CounterOpts{
+		{
+			Name: TraceCacheMissesName,
+			Help: "[STATE] number of traces not found in the trace cache",
+		},
+	}
+
+	gauges := []prometheus.GaugeOpts{
+		{
+			Name: TraceCacheEntriesName,
+			Help: "[STATE] number of traces kept in memory by the trace cache",
+		},
+	}
+
 	metrics.RegisterHistogramVecs(histogramVecs...)
+	metrics.RegisterCounterVecs(counterVecs...)
+	metrics.RegisterCounters(counters...)
+	metrics.RegisterGauges(gauges...)
 }
 
 // ExecutorProcessingTime observes the last processing time of the executor in the histogram vector by the provided elapsed time
@@ -47,3 +87,18 @@ func ExecutorProcessingTime(caller string, lastExecutionTime time.Duration) {
 	execTimeInSeconds := float64(lastExecutionTime) / float64(time.Second)
 	metrics.HistogramVecObserve(ExecutorProcessingTimeName, caller, execTimeInSeconds)
 }
+
+// TraceCacheHit increments the number of traces served from the trace cache for the given source.
+func TraceCacheHit(source string) {
+	metrics.CounterVecInc(TraceCacheHitsName, source)
+}
+
+// TraceCacheMiss increments the number of traces not found in the trace cache.
+func TraceCacheMiss() {
+	metrics.CounterInc(TraceCacheMissesName)
+}
+
+// TraceCacheEntries sets the number of traces kept in memory by the trace cache.
+func TraceCacheEntries(entries int) {
+	metrics.GaugeSet(TraceCacheEntriesName, float64(entries))
+}
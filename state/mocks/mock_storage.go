@@ -7,6 +7,8 @@ import (
 
 	common "github.com/ethereum/go-ethereum/common"
 
+	json "encoding/json"
+
 	mock "github.com/stretchr/testify/mock"
 
 	pgconn "github.com/jackc/pgconn"
@@ -33,6 +35,72 @@ func (_m *StorageMock) EXPECT() *StorageMock_Expecter {
 	return &StorageMock_Expecter{mock: &_m.Mock}
 }
 
+// AcquireSequencerLease provides a mock function with given fields: ctx, holder, duration, dbTx
+func (_m *StorageMock) AcquireSequencerLease(ctx context.Context, holder string, duration time.Duration, dbTx pgx.Tx) (uint64, bool, error) {
+	ret := _m.Called(ctx, holder, duration, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcquireSequencerLease")
+	}
+
+	var r0 uint64
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration, pgx.Tx) (uint64, bool, error)); ok {
+		return rf(ctx, holder, duration, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration, pgx.Tx) uint64); ok {
+		r0 = rf(ctx, holder, duration, dbTx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration, pgx.Tx) bool); ok {
+		r1 = rf(ctx, holder, duration, dbTx)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, time.Duration, pgx.Tx) error); ok {
+		r2 = rf(ctx, holder, duration, dbTx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// StorageMock_AcquireSequencerLease_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AcquireSequencerLease'
+type StorageMock_AcquireSequencerLease_Call struct {
+	*mock.Call
+}
+
+// AcquireSequencerLease is a helper method to define mock.On call
+//   - ctx context.Context
+//   - holder string
+//   - duration time.Duration
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) AcquireSequencerLease(ctx interface{}, holder interface{}, duration interface{}, dbTx interface{}) *StorageMock_AcquireSequencerLease_Call {
+	return &StorageMock_AcquireSequencerLease_Call{Call: _e.mock.On("AcquireSequencerLease", ctx, holder, duration, dbTx)}
+}
+
+func (_c *StorageMock_AcquireSequencerLease_Call) Run(run func(ctx context.Context, holder string, duration time.Duration, dbTx pgx.Tx)) *StorageMock_AcquireSequencerLease_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Duration), args[3].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_AcquireSequencerLease_Call) Return(_a0 uint64, _a1 bool, _a2 error) *StorageMock_AcquireSequencerLease_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *StorageMock_AcquireSequencerLease_Call) RunAndReturn(run func(context.Context, string, time.Duration, pgx.Tx) (uint64, bool, error)) *StorageMock_AcquireSequencerLease_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // AddAccumulatedInputHash provides a mock function with given fields: ctx, batchNum, accInputHash, dbTx
 func (_m *StorageMock) AddAccumulatedInputHash(ctx context.Context, batchNum uint64, accInputHash common.Hash, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, batchNum, accInputHash, dbTx)
@@ -274,6 +342,57 @@ func (_c *StorageMock_AddBlock_Call) RunAndReturn(run func(context.Context, *sta
 	return _c
 }
 
+// AddCachedTrace provides a mock function with given fields: ctx, cacheKey, l2BlockNumber, l2BlockHash, trace, dbTx
+func (_m *StorageMock) AddCachedTrace(ctx context.Context, cacheKey string, l2BlockNumber uint64, l2BlockHash common.Hash, trace []byte, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, cacheKey, l2BlockNumber, l2BlockHash, trace, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddCachedTrace")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, common.Hash, []byte, pgx.Tx) error); ok {
+		r0 = rf(ctx, cacheKey, l2BlockNumber, l2BlockHash, trace, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StorageMock_AddCachedTrace_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddCachedTrace'
+type StorageMock_AddCachedTrace_Call struct {
+	*mock.Call
+}
+
+// AddCachedTrace is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cacheKey string
+//   - l2BlockNumber uint64
+//   - l2BlockHash common.Hash
+//   - trace []byte
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) AddCachedTrace(ctx interface{}, cacheKey interface{}, l2BlockNumber interface{}, l2BlockHash interface{}, trace interface{}, dbTx interface{}) *StorageMock_AddCachedTrace_Call {
+	return &StorageMock_AddCachedTrace_Call{Call: _e.mock.On("AddCachedTrace", ctx, cacheKey, l2BlockNumber, l2BlockHash, trace, dbTx)}
+}
+
+func (_c *StorageMock_AddCachedTrace_Call) Run(run func(ctx context.Context, cacheKey string, l2BlockNumber uint64, l2BlockHash common.Hash, trace []byte, dbTx pgx.Tx)) *StorageMock_AddCachedTrace_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(uint64), args[3].(common.Hash), args[4].([]byte), args[5].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_AddCachedTrace_Call) Return(_a0 error) *StorageMock_AddCachedTrace_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StorageMock_AddCachedTrace_Call) RunAndReturn(run func(context.Context, string, uint64, common.Hash, []byte, pgx.Tx) error) *StorageMock_AddCachedTrace_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // AddForcedBatch provides a mock function with given fields: ctx, forcedBatch, tx
 func (_m *StorageMock) AddForcedBatch(ctx context.Context, forcedBatch *state.ForcedBatch, tx pgx.Tx) error {
 	ret := _m.Called(ctx, forcedBatch, tx)
@@ -615,6 +734,56 @@ func (_c *StorageMock_AddL2Block_Call) RunAndReturn(run func(context.Context, ui
 	return _c
 }
 
+// AddL2BlockTraces provides a mock function with given fields: ctx, l2BlockNumber, l2BlockHash, traces, dbTx
+func (_m *StorageMock) AddL2BlockTraces(ctx context.Context, l2BlockNumber uint64, l2BlockHash common.Hash, traces []state.IndexedTrace, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, l2BlockNumber, l2BlockHash, traces, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddL2BlockTraces")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, common.Hash, []state.IndexedTrace, pgx.Tx) error); ok {
+		r0 = rf(ctx, l2BlockNumber, l2BlockHash, traces, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StorageMock_AddL2BlockTraces_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddL2BlockTraces'
+type StorageMock_AddL2BlockTraces_Call struct {
+	*mock.Call
+}
+
+// AddL2BlockTraces is a helper method to define mock.On call
+//   - ctx context.Context
+//   - l2BlockNumber uint64
+//   - l2BlockHash common.Hash
+//   - traces []state.IndexedTrace
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) AddL2BlockTraces(ctx interface{}, l2BlockNumber interface{}, l2BlockHash interface{}, traces interface{}, dbTx interface{}) *StorageMock_AddL2BlockTraces_Call {
+	return &StorageMock_AddL2BlockTraces_Call{Call: _e.mock.On("AddL2BlockTraces", ctx, l2BlockNumber, l2BlockHash, traces, dbTx)}
+}
+
+func (_c *StorageMock_AddL2BlockTraces_Call) Run(run func(ctx context.Context, l2BlockNumber uint64, l2BlockHash common.Hash, traces []state.IndexedTrace, dbTx pgx.Tx)) *StorageMock_AddL2BlockTraces_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(common.Hash), args[3].([]state.IndexedTrace), args[4].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_AddL2BlockTraces_Call) Return(_a0 error) *StorageMock_AddL2BlockTraces_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StorageMock_AddL2BlockTraces_Call) RunAndReturn(run func(context.Context, uint64, common.Hash, []state.IndexedTrace, pgx.Tx) error) *StorageMock_AddL2BlockTraces_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // AddLog provides a mock function with given fields: ctx, l, dbTx
 func (_m *StorageMock) AddLog(ctx context.Context, l *types.Log, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, l, dbTx)
@@ -1078,6 +1247,55 @@ func (_c *StorageMock_CheckProofContainsCompleteSequences_Call) RunAndReturn(run
 	return _c
 }
 
+// CheckSequencerLease provides a mock function with given fields: ctx, holder, term, dbTx
+func (_m *StorageMock) CheckSequencerLease(ctx context.Context, holder string, term uint64, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, holder, term, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckSequencerLease")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, pgx.Tx) error); ok {
+		r0 = rf(ctx, holder, term, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StorageMock_CheckSequencerLease_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckSequencerLease'
+type StorageMock_CheckSequencerLease_Call struct {
+	*mock.Call
+}
+
+// CheckSequencerLease is a helper method to define mock.On call
+//   - ctx context.Context
+//   - holder string
+//   - term uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) CheckSequencerLease(ctx interface{}, holder interface{}, term interface{}, dbTx interface{}) *StorageMock_CheckSequencerLease_Call {
+	return &StorageMock_CheckSequencerLease_Call{Call: _e.mock.On("CheckSequencerLease", ctx, holder, term, dbTx)}
+}
+
+func (_c *StorageMock_CheckSequencerLease_Call) Run(run func(ctx context.Context, holder string, term uint64, dbTx pgx.Tx)) *StorageMock_CheckSequencerLease_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(uint64), args[3].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_CheckSequencerLease_Call) Return(_a0 error) *StorageMock_CheckSequencerLease_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StorageMock_CheckSequencerLease_Call) RunAndReturn(run func(context.Context, string, uint64, pgx.Tx) error) *StorageMock_CheckSequencerLease_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CleanupBatchProofs provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StorageMock) CleanupBatchProofs(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -2172,6 +2390,67 @@ func (_c *StorageMock_GetBlockNumVirtualBatchByBatchNum_Call) RunAndReturn(run f
 	return _c
 }
 
+// GetCachedTrace provides a mock function with given fields: ctx, cacheKey, l2BlockHash, dbTx
+func (_m *StorageMock) GetCachedTrace(ctx context.Context, cacheKey string, l2BlockHash common.Hash, dbTx pgx.Tx) ([]byte, error) {
+	ret := _m.Called(ctx, cacheKey, l2BlockHash, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCachedTrace")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, common.Hash, pgx.Tx) ([]byte, error)); ok {
+		return rf(ctx, cacheKey, l2BlockHash, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, common.Hash, pgx.Tx) []byte); ok {
+		r0 = rf(ctx, cacheKey, l2BlockHash, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, common.Hash, pgx.Tx) error); ok {
+		r1 = rf(ctx, cacheKey, l2BlockHash, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetCachedTrace_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCachedTrace'
+type StorageMock_GetCachedTrace_Call struct {
+	*mock.Call
+}
+
+// GetCachedTrace is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cacheKey string
+//   - l2BlockHash common.Hash
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetCachedTrace(ctx interface{}, cacheKey interface{}, l2BlockHash interface{}, dbTx interface{}) *StorageMock_GetCachedTrace_Call {
+	return &StorageMock_GetCachedTrace_Call{Call: _e.mock.On("GetCachedTrace", ctx, cacheKey, l2BlockHash, dbTx)}
+}
+
+func (_c *StorageMock_GetCachedTrace_Call) Run(run func(ctx context.Context, cacheKey string, l2BlockHash common.Hash, dbTx pgx.Tx)) *StorageMock_GetCachedTrace_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(common.Hash), args[3].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetCachedTrace_Call) Return(_a0 []byte, _a1 error) *StorageMock_GetCachedTrace_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetCachedTrace_Call) RunAndReturn(run func(context.Context, string, common.Hash, pgx.Tx) ([]byte, error)) *StorageMock_GetCachedTrace_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetDSBatches provides a mock function with given fields: ctx, firstBatchNumber, lastBatchNumber, readWIPBatch, dbTx
 func (_m *StorageMock) GetDSBatches(ctx context.Context, firstBatchNumber uint64, lastBatchNumber uint64, readWIPBatch bool, dbTx pgx.Tx) ([]*state.DSBatch, error) {
 	ret := _m.Called(ctx, firstBatchNumber, lastBatchNumber, readWIPBatch, dbTx)
@@ -2354,6 +2633,67 @@ func (_c *StorageMock_GetDSL2Blocks_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// GetDSL2Receipts provides a mock function with given fields: ctx, firstL2Block, lastL2Block, dbTx
+func (_m *StorageMock) GetDSL2Receipts(ctx context.Context, firstL2Block uint64, lastL2Block uint64, dbTx pgx.Tx) ([]*types.Receipt, error) {
+	ret := _m.Called(ctx, firstL2Block, lastL2Block, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDSL2Receipts")
+	}
+
+	var r0 []*types.Receipt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) ([]*types.Receipt, error)); ok {
+		return rf(ctx, firstL2Block, lastL2Block, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) []*types.Receipt); ok {
+		r0 = rf(ctx, firstL2Block, lastL2Block, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Receipt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, firstL2Block, lastL2Block, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetDSL2Receipts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDSL2Receipts'
+type StorageMock_GetDSL2Receipts_Call struct {
+	*mock.Call
+}
+
+// GetDSL2Receipts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - firstL2Block uint64
+//   - lastL2Block uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetDSL2Receipts(ctx interface{}, firstL2Block interface{}, lastL2Block interface{}, dbTx interface{}) *StorageMock_GetDSL2Receipts_Call {
+	return &StorageMock_GetDSL2Receipts_Call{Call: _e.mock.On("GetDSL2Receipts", ctx, firstL2Block, lastL2Block, dbTx)}
+}
+
+func (_c *StorageMock_GetDSL2Receipts_Call) Run(run func(ctx context.Context, firstL2Block uint64, lastL2Block uint64, dbTx pgx.Tx)) *StorageMock_GetDSL2Receipts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(uint64), args[3].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetDSL2Receipts_Call) Return(_a0 []*types.Receipt, _a1 error) *StorageMock_GetDSL2Receipts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetDSL2Receipts_Call) RunAndReturn(run func(context.Context, uint64, uint64, pgx.Tx) ([]*types.Receipt, error)) *StorageMock_GetDSL2Receipts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetDSL2Transactions provides a mock function with given fields: ctx, firstL2Block, lastL2Block, dbTx
 func (_m *StorageMock) GetDSL2Transactions(ctx context.Context, firstL2Block uint64, lastL2Block uint64, dbTx pgx.Tx) ([]*state.DSL2Transaction, error) {
 	ret := _m.Called(ctx, firstL2Block, lastL2Block, dbTx)
@@ -2544,27 +2884,27 @@ func (_c *StorageMock_GetExitRootByGlobalExitRoot_Call) RunAndReturn(run func(co
 	return _c
 }
 
-// GetFirstL2BlockNumberForBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
-func (_m *StorageMock) GetFirstL2BlockNumberForBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (uint64, error) {
-	ret := _m.Called(ctx, batchNumber, dbTx)
+// GetFirstKeptL2BlockNumber provides a mock function with given fields: ctx, dbTx
+func (_m *StorageMock) GetFirstKeptL2BlockNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	ret := _m.Called(ctx, dbTx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetFirstL2BlockNumberForBatchNumber")
+		panic("no return value specified for GetFirstKeptL2BlockNumber")
 	}
 
 	var r0 uint64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (uint64, error)); ok {
-		return rf(ctx, batchNumber, dbTx)
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (uint64, error)); ok {
+		return rf(ctx, dbTx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) uint64); ok {
-		r0 = rf(ctx, batchNumber, dbTx)
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) uint64); ok {
+		r0 = rf(ctx, dbTx)
 	} else {
 		r0 = ret.Get(0).(uint64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
-		r1 = rf(ctx, batchNumber, dbTx)
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2572,40 +2912,97 @@ func (_m *StorageMock) GetFirstL2BlockNumberForBatchNumber(ctx context.Context,
 	return r0, r1
 }
 
-// StorageMock_GetFirstL2BlockNumberForBatchNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFirstL2BlockNumberForBatchNumber'
-type StorageMock_GetFirstL2BlockNumberForBatchNumber_Call struct {
+// StorageMock_GetFirstKeptL2BlockNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFirstKeptL2BlockNumber'
+type StorageMock_GetFirstKeptL2BlockNumber_Call struct {
 	*mock.Call
 }
 
-// GetFirstL2BlockNumberForBatchNumber is a helper method to define mock.On call
+// GetFirstKeptL2BlockNumber is a helper method to define mock.On call
 //   - ctx context.Context
-//   - batchNumber uint64
 //   - dbTx pgx.Tx
-func (_e *StorageMock_Expecter) GetFirstL2BlockNumberForBatchNumber(ctx interface{}, batchNumber interface{}, dbTx interface{}) *StorageMock_GetFirstL2BlockNumberForBatchNumber_Call {
-	return &StorageMock_GetFirstL2BlockNumberForBatchNumber_Call{Call: _e.mock.On("GetFirstL2BlockNumberForBatchNumber", ctx, batchNumber, dbTx)}
+func (_e *StorageMock_Expecter) GetFirstKeptL2BlockNumber(ctx interface{}, dbTx interface{}) *StorageMock_GetFirstKeptL2BlockNumber_Call {
+	return &StorageMock_GetFirstKeptL2BlockNumber_Call{Call: _e.mock.On("GetFirstKeptL2BlockNumber", ctx, dbTx)}
 }
 
-func (_c *StorageMock_GetFirstL2BlockNumberForBatchNumber_Call) Run(run func(ctx context.Context, batchNumber uint64, dbTx pgx.Tx)) *StorageMock_GetFirstL2BlockNumberForBatchNumber_Call {
+func (_c *StorageMock_GetFirstKeptL2BlockNumber_Call) Run(run func(ctx context.Context, dbTx pgx.Tx)) *StorageMock_GetFirstKeptL2BlockNumber_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uint64), args[2].(pgx.Tx))
+		run(args[0].(context.Context), args[1].(pgx.Tx))
 	})
 	return _c
 }
 
-func (_c *StorageMock_GetFirstL2BlockNumberForBatchNumber_Call) Return(_a0 uint64, _a1 error) *StorageMock_GetFirstL2BlockNumberForBatchNumber_Call {
+func (_c *StorageMock_GetFirstKeptL2BlockNumber_Call) Return(_a0 uint64, _a1 error) *StorageMock_GetFirstKeptL2BlockNumber_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *StorageMock_GetFirstL2BlockNumberForBatchNumber_Call) RunAndReturn(run func(context.Context, uint64, pgx.Tx) (uint64, error)) *StorageMock_GetFirstL2BlockNumberForBatchNumber_Call {
+func (_c *StorageMock_GetFirstKeptL2BlockNumber_Call) RunAndReturn(run func(context.Context, pgx.Tx) (uint64, error)) *StorageMock_GetFirstKeptL2BlockNumber_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetFirstUncheckedBlock provides a mock function with given fields: ctx, fromBlockNumber, dbTx
-func (_m *StorageMock) GetFirstUncheckedBlock(ctx context.Context, fromBlockNumber uint64, dbTx pgx.Tx) (*state.Block, error) {
-	ret := _m.Called(ctx, fromBlockNumber, dbTx)
-
+// GetFirstL2BlockNumberForBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
+func (_m *StorageMock) GetFirstL2BlockNumberForBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (uint64, error) {
+	ret := _m.Called(ctx, batchNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFirstL2BlockNumberForBatchNumber")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (uint64, error)); ok {
+		return rf(ctx, batchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) uint64); ok {
+		r0 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetFirstL2BlockNumberForBatchNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFirstL2BlockNumberForBatchNumber'
+type StorageMock_GetFirstL2BlockNumberForBatchNumber_Call struct {
+	*mock.Call
+}
+
+// GetFirstL2BlockNumberForBatchNumber is a helper method to define mock.On call
+//   - ctx context.Context
+//   - batchNumber uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetFirstL2BlockNumberForBatchNumber(ctx interface{}, batchNumber interface{}, dbTx interface{}) *StorageMock_GetFirstL2BlockNumberForBatchNumber_Call {
+	return &StorageMock_GetFirstL2BlockNumberForBatchNumber_Call{Call: _e.mock.On("GetFirstL2BlockNumberForBatchNumber", ctx, batchNumber, dbTx)}
+}
+
+func (_c *StorageMock_GetFirstL2BlockNumberForBatchNumber_Call) Run(run func(ctx context.Context, batchNumber uint64, dbTx pgx.Tx)) *StorageMock_GetFirstL2BlockNumberForBatchNumber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetFirstL2BlockNumberForBatchNumber_Call) Return(_a0 uint64, _a1 error) *StorageMock_GetFirstL2BlockNumberForBatchNumber_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetFirstL2BlockNumberForBatchNumber_Call) RunAndReturn(run func(context.Context, uint64, pgx.Tx) (uint64, error)) *StorageMock_GetFirstL2BlockNumberForBatchNumber_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetFirstUncheckedBlock provides a mock function with given fields: ctx, fromBlockNumber, dbTx
+func (_m *StorageMock) GetFirstUncheckedBlock(ctx context.Context, fromBlockNumber uint64, dbTx pgx.Tx) (*state.Block, error) {
+	ret := _m.Called(ctx, fromBlockNumber, dbTx)
+
 	if len(ret) == 0 {
 		panic("no return value specified for GetFirstUncheckedBlock")
 	}
@@ -2662,6 +3059,63 @@ func (_c *StorageMock_GetFirstUncheckedBlock_Call) RunAndReturn(run func(context
 	return _c
 }
 
+// GetForceBatchTimeout provides a mock function with given fields: ctx, dbTx
+func (_m *StorageMock) GetForceBatchTimeout(ctx context.Context, dbTx pgx.Tx) (time.Duration, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForceBatchTimeout")
+	}
+
+	var r0 time.Duration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (time.Duration, error)); ok {
+		return rf(ctx, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) time.Duration); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetForceBatchTimeout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetForceBatchTimeout'
+type StorageMock_GetForceBatchTimeout_Call struct {
+	*mock.Call
+}
+
+// GetForceBatchTimeout is a helper method to define mock.On call
+//   - ctx context.Context
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetForceBatchTimeout(ctx interface{}, dbTx interface{}) *StorageMock_GetForceBatchTimeout_Call {
+	return &StorageMock_GetForceBatchTimeout_Call{Call: _e.mock.On("GetForceBatchTimeout", ctx, dbTx)}
+}
+
+func (_c *StorageMock_GetForceBatchTimeout_Call) Run(run func(ctx context.Context, dbTx pgx.Tx)) *StorageMock_GetForceBatchTimeout_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetForceBatchTimeout_Call) Return(_a0 time.Duration, _a1 error) *StorageMock_GetForceBatchTimeout_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetForceBatchTimeout_Call) RunAndReturn(run func(context.Context, pgx.Tx) (time.Duration, error)) *StorageMock_GetForceBatchTimeout_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetForcedBatch provides a mock function with given fields: ctx, forcedBatchNumber, dbTx
 func (_m *StorageMock) GetForcedBatch(ctx context.Context, forcedBatchNumber uint64, dbTx pgx.Tx) (*state.ForcedBatch, error) {
 	ret := _m.Called(ctx, forcedBatchNumber, dbTx)
@@ -4217,6 +4671,63 @@ func (_c *StorageMock_GetLastConsolidatedL2BlockNumber_Call) RunAndReturn(run fu
 	return _c
 }
 
+// GetLastIndexedTraceL2BlockNumber provides a mock function with given fields: ctx, dbTx
+func (_m *StorageMock) GetLastIndexedTraceL2BlockNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastIndexedTraceL2BlockNumber")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (uint64, error)); ok {
+		return rf(ctx, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) uint64); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetLastIndexedTraceL2BlockNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLastIndexedTraceL2BlockNumber'
+type StorageMock_GetLastIndexedTraceL2BlockNumber_Call struct {
+	*mock.Call
+}
+
+// GetLastIndexedTraceL2BlockNumber is a helper method to define mock.On call
+//   - ctx context.Context
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetLastIndexedTraceL2BlockNumber(ctx interface{}, dbTx interface{}) *StorageMock_GetLastIndexedTraceL2BlockNumber_Call {
+	return &StorageMock_GetLastIndexedTraceL2BlockNumber_Call{Call: _e.mock.On("GetLastIndexedTraceL2BlockNumber", ctx, dbTx)}
+}
+
+func (_c *StorageMock_GetLastIndexedTraceL2BlockNumber_Call) Run(run func(ctx context.Context, dbTx pgx.Tx)) *StorageMock_GetLastIndexedTraceL2BlockNumber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetLastIndexedTraceL2BlockNumber_Call) Return(_a0 uint64, _a1 error) *StorageMock_GetLastIndexedTraceL2BlockNumber_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetLastIndexedTraceL2BlockNumber_Call) RunAndReturn(run func(context.Context, pgx.Tx) (uint64, error)) *StorageMock_GetLastIndexedTraceL2BlockNumber_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetLastL2Block provides a mock function with given fields: ctx, dbTx
 func (_m *StorageMock) GetLastL2Block(ctx context.Context, dbTx pgx.Tx) (*state.L2Block, error) {
 	ret := _m.Called(ctx, dbTx)
@@ -6255,6 +6766,65 @@ func (_c *StorageMock_GetReorgedTransactions_Call) RunAndReturn(run func(context
 	return _c
 }
 
+// GetSequencerHalt provides a mock function with given fields: ctx, dbTx
+func (_m *StorageMock) GetSequencerHalt(ctx context.Context, dbTx pgx.Tx) (*state.SequencerHalt, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSequencerHalt")
+	}
+
+	var r0 *state.SequencerHalt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (*state.SequencerHalt, error)); ok {
+		return rf(ctx, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) *state.SequencerHalt); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.SequencerHalt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetSequencerHalt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSequencerHalt'
+type StorageMock_GetSequencerHalt_Call struct {
+	*mock.Call
+}
+
+// GetSequencerHalt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetSequencerHalt(ctx interface{}, dbTx interface{}) *StorageMock_GetSequencerHalt_Call {
+	return &StorageMock_GetSequencerHalt_Call{Call: _e.mock.On("GetSequencerHalt", ctx, dbTx)}
+}
+
+func (_c *StorageMock_GetSequencerHalt_Call) Run(run func(ctx context.Context, dbTx pgx.Tx)) *StorageMock_GetSequencerHalt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetSequencerHalt_Call) Return(_a0 *state.SequencerHalt, _a1 error) *StorageMock_GetSequencerHalt_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetSequencerHalt_Call) RunAndReturn(run func(context.Context, pgx.Tx) (*state.SequencerHalt, error)) *StorageMock_GetSequencerHalt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetSequences provides a mock function with given fields: ctx, lastVerifiedBatchNumber, dbTx
 func (_m *StorageMock) GetSequences(ctx context.Context, lastVerifiedBatchNumber uint64, dbTx pgx.Tx) ([]state.Sequence, error) {
 	ret := _m.Called(ctx, lastVerifiedBatchNumber, dbTx)
@@ -6489,6 +7059,66 @@ func (_c *StorageMock_GetTimeForLatestBatchVirtualization_Call) RunAndReturn(run
 	return _c
 }
 
+// GetTraces provides a mock function with given fields: ctx, filter, dbTx
+func (_m *StorageMock) GetTraces(ctx context.Context, filter state.TraceFilter, dbTx pgx.Tx) ([]json.RawMessage, error) {
+	ret := _m.Called(ctx, filter, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTraces")
+	}
+
+	var r0 []json.RawMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, state.TraceFilter, pgx.Tx) ([]json.RawMessage, error)); ok {
+		return rf(ctx, filter, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, state.TraceFilter, pgx.Tx) []json.RawMessage); ok {
+		r0 = rf(ctx, filter, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]json.RawMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, state.TraceFilter, pgx.Tx) error); ok {
+		r1 = rf(ctx, filter, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetTraces_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTraces'
+type StorageMock_GetTraces_Call struct {
+	*mock.Call
+}
+
+// GetTraces is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter state.TraceFilter
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetTraces(ctx interface{}, filter interface{}, dbTx interface{}) *StorageMock_GetTraces_Call {
+	return &StorageMock_GetTraces_Call{Call: _e.mock.On("GetTraces", ctx, filter, dbTx)}
+}
+
+func (_c *StorageMock_GetTraces_Call) Run(run func(ctx context.Context, filter state.TraceFilter, dbTx pgx.Tx)) *StorageMock_GetTraces_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(state.TraceFilter), args[2].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetTraces_Call) Return(_a0 []json.RawMessage, _a1 error) *StorageMock_GetTraces_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetTraces_Call) RunAndReturn(run func(context.Context, state.TraceFilter, pgx.Tx) ([]json.RawMessage, error)) *StorageMock_GetTraces_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetTransactionByHash provides a mock function with given fields: ctx, transactionHash, dbTx
 func (_m *StorageMock) GetTransactionByHash(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*types.Transaction, error) {
 	ret := _m.Called(ctx, transactionHash, dbTx)
@@ -6920,29 +7550,29 @@ func (_c *StorageMock_GetTransactionsByBatchNumber_Call) RunAndReturn(run func(c
 	return _c
 }
 
-// GetTxsByBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
-func (_m *StorageMock) GetTxsByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]*types.Transaction, error) {
-	ret := _m.Called(ctx, batchNumber, dbTx)
+// GetTransactionsEGPLogByL2BlockRange provides a mock function with given fields: ctx, fromBlockNumber, toBlockNumber, dbTx
+func (_m *StorageMock) GetTransactionsEGPLogByL2BlockRange(ctx context.Context, fromBlockNumber uint64, toBlockNumber uint64, dbTx pgx.Tx) ([]state.TransactionEGPLog, error) {
+	ret := _m.Called(ctx, fromBlockNumber, toBlockNumber, dbTx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTxsByBatchNumber")
+		panic("no return value specified for GetTransactionsEGPLogByL2BlockRange")
 	}
 
-	var r0 []*types.Transaction
+	var r0 []state.TransactionEGPLog
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) ([]*types.Transaction, error)); ok {
-		return rf(ctx, batchNumber, dbTx)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) ([]state.TransactionEGPLog, error)); ok {
+		return rf(ctx, fromBlockNumber, toBlockNumber, dbTx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) []*types.Transaction); ok {
-		r0 = rf(ctx, batchNumber, dbTx)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) []state.TransactionEGPLog); ok {
+		r0 = rf(ctx, fromBlockNumber, toBlockNumber, dbTx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*types.Transaction)
+			r0 = ret.Get(0).([]state.TransactionEGPLog)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
-		r1 = rf(ctx, batchNumber, dbTx)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, fromBlockNumber, toBlockNumber, dbTx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -6950,16 +7580,77 @@ func (_m *StorageMock) GetTxsByBatchNumber(ctx context.Context, batchNumber uint
 	return r0, r1
 }
 
-// StorageMock_GetTxsByBatchNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxsByBatchNumber'
-type StorageMock_GetTxsByBatchNumber_Call struct {
+// StorageMock_GetTransactionsEGPLogByL2BlockRange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransactionsEGPLogByL2BlockRange'
+type StorageMock_GetTransactionsEGPLogByL2BlockRange_Call struct {
 	*mock.Call
 }
 
-// GetTxsByBatchNumber is a helper method to define mock.On call
+// GetTransactionsEGPLogByL2BlockRange is a helper method to define mock.On call
 //   - ctx context.Context
-//   - batchNumber uint64
+//   - fromBlockNumber uint64
+//   - toBlockNumber uint64
 //   - dbTx pgx.Tx
-func (_e *StorageMock_Expecter) GetTxsByBatchNumber(ctx interface{}, batchNumber interface{}, dbTx interface{}) *StorageMock_GetTxsByBatchNumber_Call {
+func (_e *StorageMock_Expecter) GetTransactionsEGPLogByL2BlockRange(ctx interface{}, fromBlockNumber interface{}, toBlockNumber interface{}, dbTx interface{}) *StorageMock_GetTransactionsEGPLogByL2BlockRange_Call {
+	return &StorageMock_GetTransactionsEGPLogByL2BlockRange_Call{Call: _e.mock.On("GetTransactionsEGPLogByL2BlockRange", ctx, fromBlockNumber, toBlockNumber, dbTx)}
+}
+
+func (_c *StorageMock_GetTransactionsEGPLogByL2BlockRange_Call) Run(run func(ctx context.Context, fromBlockNumber uint64, toBlockNumber uint64, dbTx pgx.Tx)) *StorageMock_GetTransactionsEGPLogByL2BlockRange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(uint64), args[3].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetTransactionsEGPLogByL2BlockRange_Call) Return(_a0 []state.TransactionEGPLog, _a1 error) *StorageMock_GetTransactionsEGPLogByL2BlockRange_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetTransactionsEGPLogByL2BlockRange_Call) RunAndReturn(run func(context.Context, uint64, uint64, pgx.Tx) ([]state.TransactionEGPLog, error)) *StorageMock_GetTransactionsEGPLogByL2BlockRange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTxsByBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
+func (_m *StorageMock) GetTxsByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]*types.Transaction, error) {
+	ret := _m.Called(ctx, batchNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTxsByBatchNumber")
+	}
+
+	var r0 []*types.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) ([]*types.Transaction, error)); ok {
+		return rf(ctx, batchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) []*types.Transaction); ok {
+		r0 = rf(ctx, batchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetTxsByBatchNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxsByBatchNumber'
+type StorageMock_GetTxsByBatchNumber_Call struct {
+	*mock.Call
+}
+
+// GetTxsByBatchNumber is a helper method to define mock.On call
+//   - ctx context.Context
+//   - batchNumber uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetTxsByBatchNumber(ctx interface{}, batchNumber interface{}, dbTx interface{}) *StorageMock_GetTxsByBatchNumber_Call {
 	return &StorageMock_GetTxsByBatchNumber_Call{Call: _e.mock.On("GetTxsByBatchNumber", ctx, batchNumber, dbTx)}
 }
 
@@ -8145,6 +8836,65 @@ func (_c *StorageMock_OpenWIPBatchInStorage_Call) RunAndReturn(run func(context.
 	return _c
 }
 
+// PruneHistory provides a mock function with given fields: ctx, toBatchNumber, maxL2Blocks, dbTx
+func (_m *StorageMock) PruneHistory(ctx context.Context, toBatchNumber uint64, maxL2Blocks uint64, dbTx pgx.Tx) (uint64, error) {
+	ret := _m.Called(ctx, toBatchNumber, maxL2Blocks, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PruneHistory")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) (uint64, error)); ok {
+		return rf(ctx, toBatchNumber, maxL2Blocks, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) uint64); ok {
+		r0 = rf(ctx, toBatchNumber, maxL2Blocks, dbTx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, toBatchNumber, maxL2Blocks, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_PruneHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PruneHistory'
+type StorageMock_PruneHistory_Call struct {
+	*mock.Call
+}
+
+// PruneHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - toBatchNumber uint64
+//   - maxL2Blocks uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) PruneHistory(ctx interface{}, toBatchNumber interface{}, maxL2Blocks interface{}, dbTx interface{}) *StorageMock_PruneHistory_Call {
+	return &StorageMock_PruneHistory_Call{Call: _e.mock.On("PruneHistory", ctx, toBatchNumber, maxL2Blocks, dbTx)}
+}
+
+func (_c *StorageMock_PruneHistory_Call) Run(run func(ctx context.Context, toBatchNumber uint64, maxL2Blocks uint64, dbTx pgx.Tx)) *StorageMock_PruneHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(uint64), args[3].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_PruneHistory_Call) Return(_a0 uint64, _a1 error) *StorageMock_PruneHistory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_PruneHistory_Call) RunAndReturn(run func(context.Context, uint64, uint64, pgx.Tx) (uint64, error)) *StorageMock_PruneHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Query provides a mock function with given fields: ctx, sql, args
 func (_m *StorageMock) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
 	var _ca []interface{}
@@ -8275,6 +9025,115 @@ func (_c *StorageMock_QueryRow_Call) RunAndReturn(run func(context.Context, stri
 	return _c
 }
 
+// ReleaseSequencerLease provides a mock function with given fields: ctx, holder, term, dbTx
+func (_m *StorageMock) ReleaseSequencerLease(ctx context.Context, holder string, term uint64, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, holder, term, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseSequencerLease")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, pgx.Tx) error); ok {
+		r0 = rf(ctx, holder, term, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StorageMock_ReleaseSequencerLease_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseSequencerLease'
+type StorageMock_ReleaseSequencerLease_Call struct {
+	*mock.Call
+}
+
+// ReleaseSequencerLease is a helper method to define mock.On call
+//   - ctx context.Context
+//   - holder string
+//   - term uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) ReleaseSequencerLease(ctx interface{}, holder interface{}, term interface{}, dbTx interface{}) *StorageMock_ReleaseSequencerLease_Call {
+	return &StorageMock_ReleaseSequencerLease_Call{Call: _e.mock.On("ReleaseSequencerLease", ctx, holder, term, dbTx)}
+}
+
+func (_c *StorageMock_ReleaseSequencerLease_Call) Run(run func(ctx context.Context, holder string, term uint64, dbTx pgx.Tx)) *StorageMock_ReleaseSequencerLease_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(uint64), args[3].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_ReleaseSequencerLease_Call) Return(_a0 error) *StorageMock_ReleaseSequencerLease_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StorageMock_ReleaseSequencerLease_Call) RunAndReturn(run func(context.Context, string, uint64, pgx.Tx) error) *StorageMock_ReleaseSequencerLease_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RenewSequencerLease provides a mock function with given fields: ctx, holder, term, duration, dbTx
+func (_m *StorageMock) RenewSequencerLease(ctx context.Context, holder string, term uint64, duration time.Duration, dbTx pgx.Tx) (bool, error) {
+	ret := _m.Called(ctx, holder, term, duration, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenewSequencerLease")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, time.Duration, pgx.Tx) (bool, error)); ok {
+		return rf(ctx, holder, term, duration, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, time.Duration, pgx.Tx) bool); ok {
+		r0 = rf(ctx, holder, term, duration, dbTx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint64, time.Duration, pgx.Tx) error); ok {
+		r1 = rf(ctx, holder, term, duration, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_RenewSequencerLease_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RenewSequencerLease'
+type StorageMock_RenewSequencerLease_Call struct {
+	*mock.Call
+}
+
+// RenewSequencerLease is a helper method to define mock.On call
+//   - ctx context.Context
+//   - holder string
+//   - term uint64
+//   - duration time.Duration
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) RenewSequencerLease(ctx interface{}, holder interface{}, term interface{}, duration interface{}, dbTx interface{}) *StorageMock_RenewSequencerLease_Call {
+	return &StorageMock_RenewSequencerLease_Call{Call: _e.mock.On("RenewSequencerLease", ctx, holder, term, duration, dbTx)}
+}
+
+func (_c *StorageMock_RenewSequencerLease_Call) Run(run func(ctx context.Context, holder string, term uint64, duration time.Duration, dbTx pgx.Tx)) *StorageMock_RenewSequencerLease_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(uint64), args[3].(time.Duration), args[4].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_RenewSequencerLease_Call) Return(_a0 bool, _a1 error) *StorageMock_RenewSequencerLease_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_RenewSequencerLease_Call) RunAndReturn(run func(context.Context, string, uint64, time.Duration, pgx.Tx) (bool, error)) *StorageMock_RenewSequencerLease_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ResetForkID provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StorageMock) ResetForkID(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -8419,6 +9278,162 @@ func (_c *StorageMock_ResetTrustedState_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
+// ResumeSequencer provides a mock function with given fields: ctx, reason, dbTx
+func (_m *StorageMock) ResumeSequencer(ctx context.Context, reason string, dbTx pgx.Tx) (bool, error) {
+	ret := _m.Called(ctx, reason, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResumeSequencer")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, pgx.Tx) (bool, error)); ok {
+		return rf(ctx, reason, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, pgx.Tx) bool); ok {
+		r0 = rf(ctx, reason, dbTx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, pgx.Tx) error); ok {
+		r1 = rf(ctx, reason, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_ResumeSequencer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResumeSequencer'
+type StorageMock_ResumeSequencer_Call struct {
+	*mock.Call
+}
+
+// ResumeSequencer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - reason string
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) ResumeSequencer(ctx interface{}, reason interface{}, dbTx interface{}) *StorageMock_ResumeSequencer_Call {
+	return &StorageMock_ResumeSequencer_Call{Call: _e.mock.On("ResumeSequencer", ctx, reason, dbTx)}
+}
+
+func (_c *StorageMock_ResumeSequencer_Call) Run(run func(ctx context.Context, reason string, dbTx pgx.Tx)) *StorageMock_ResumeSequencer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_ResumeSequencer_Call) Return(_a0 bool, _a1 error) *StorageMock_ResumeSequencer_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_ResumeSequencer_Call) RunAndReturn(run func(context.Context, string, pgx.Tx) (bool, error)) *StorageMock_ResumeSequencer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ScheduleSequencerHalt provides a mock function with given fields: ctx, batchNumber, haltAt, reason, dbTx
+func (_m *StorageMock) ScheduleSequencerHalt(ctx context.Context, batchNumber uint64, haltAt *time.Time, reason string, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, batchNumber, haltAt, reason, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScheduleSequencerHalt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *time.Time, string, pgx.Tx) error); ok {
+		r0 = rf(ctx, batchNumber, haltAt, reason, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StorageMock_ScheduleSequencerHalt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ScheduleSequencerHalt'
+type StorageMock_ScheduleSequencerHalt_Call struct {
+	*mock.Call
+}
+
+// ScheduleSequencerHalt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - batchNumber uint64
+//   - haltAt *time.Time
+//   - reason string
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) ScheduleSequencerHalt(ctx interface{}, batchNumber interface{}, haltAt interface{}, reason interface{}, dbTx interface{}) *StorageMock_ScheduleSequencerHalt_Call {
+	return &StorageMock_ScheduleSequencerHalt_Call{Call: _e.mock.On("ScheduleSequencerHalt", ctx, batchNumber, haltAt, reason, dbTx)}
+}
+
+func (_c *StorageMock_ScheduleSequencerHalt_Call) Run(run func(ctx context.Context, batchNumber uint64, haltAt *time.Time, reason string, dbTx pgx.Tx)) *StorageMock_ScheduleSequencerHalt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(*time.Time), args[3].(string), args[4].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_ScheduleSequencerHalt_Call) Return(_a0 error) *StorageMock_ScheduleSequencerHalt_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StorageMock_ScheduleSequencerHalt_Call) RunAndReturn(run func(context.Context, uint64, *time.Time, string, pgx.Tx) error) *StorageMock_ScheduleSequencerHalt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetForceBatchTimeout provides a mock function with given fields: ctx, timeout, dbTx
+func (_m *StorageMock) SetForceBatchTimeout(ctx context.Context, timeout time.Duration, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, timeout, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetForceBatchTimeout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration, pgx.Tx) error); ok {
+		r0 = rf(ctx, timeout, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StorageMock_SetForceBatchTimeout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetForceBatchTimeout'
+type StorageMock_SetForceBatchTimeout_Call struct {
+	*mock.Call
+}
+
+// SetForceBatchTimeout is a helper method to define mock.On call
+//   - ctx context.Context
+//   - timeout time.Duration
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) SetForceBatchTimeout(ctx interface{}, timeout interface{}, dbTx interface{}) *StorageMock_SetForceBatchTimeout_Call {
+	return &StorageMock_SetForceBatchTimeout_Call{Call: _e.mock.On("SetForceBatchTimeout", ctx, timeout, dbTx)}
+}
+
+func (_c *StorageMock_SetForceBatchTimeout_Call) Run(run func(ctx context.Context, timeout time.Duration, dbTx pgx.Tx)) *StorageMock_SetForceBatchTimeout_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Duration), args[2].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_SetForceBatchTimeout_Call) Return(_a0 error) *StorageMock_SetForceBatchTimeout_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StorageMock_SetForceBatchTimeout_Call) RunAndReturn(run func(context.Context, time.Duration, pgx.Tx) error) *StorageMock_SetForceBatchTimeout_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetInitSyncBatch provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StorageMock) SetInitSyncBatch(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -8516,6 +9531,63 @@ func (_c *StorageMock_SetLastBatchInfoSeenOnEthereum_Call) RunAndReturn(run func
 	return _c
 }
 
+// SetSequencerHalted provides a mock function with given fields: ctx, dbTx
+func (_m *StorageMock) SetSequencerHalted(ctx context.Context, dbTx pgx.Tx) (bool, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetSequencerHalted")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (bool, error)); ok {
+		return rf(ctx, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) bool); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_SetSequencerHalted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetSequencerHalted'
+type StorageMock_SetSequencerHalted_Call struct {
+	*mock.Call
+}
+
+// SetSequencerHalted is a helper method to define mock.On call
+//   - ctx context.Context
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) SetSequencerHalted(ctx interface{}, dbTx interface{}) *StorageMock_SetSequencerHalted_Call {
+	return &StorageMock_SetSequencerHalted_Call{Call: _e.mock.On("SetSequencerHalted", ctx, dbTx)}
+}
+
+func (_c *StorageMock_SetSequencerHalted_Call) Run(run func(ctx context.Context, dbTx pgx.Tx)) *StorageMock_SetSequencerHalted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_SetSequencerHalted_Call) Return(_a0 bool, _a1 error) *StorageMock_SetSequencerHalted_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_SetSequencerHalted_Call) RunAndReturn(run func(context.Context, pgx.Tx) (bool, error)) *StorageMock_SetSequencerHalted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // StoreGenesisBatch provides a mock function with given fields: ctx, batch, closingReason, dbTx
 func (_m *StorageMock) StoreGenesisBatch(ctx context.Context, batch state.Batch, closingReason string, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, batch, closingReason, dbTx)
@@ -2,10 +2,12 @@ package pgstatestorage
 
 import (
 	"context"
+	"math/big"
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/jackc/pgx/v4"
 )
 
@@ -135,7 +137,7 @@ func scanDSL2Transaction(row pgx.Row) (*state.DSL2Transaction, error) {
 	); err != nil {
 		return nil, err
 	}
-	tx, err := state.DecodeTx(string(encoded))
+	tx, err := decodeStoredTx(string(encoded))
 	if err != nil {
 		return nil, err
 	}
@@ -153,6 +155,95 @@ func scanDSL2Transaction(row pgx.Row) (*state.DSL2Transaction, error) {
 	return &l2Transaction, nil
 }
 
+// GetDSL2Receipts returns the receipts and logs of the txs of the L2 blocks in the range, ordered by L2 block number and tx index
+func (p *PostgresStorage) GetDSL2Receipts(ctx context.Context, firstL2Block, lastL2Block uint64, dbTx pgx.Tx) ([]*types.Receipt, error) {
+	const l2ReceiptSQL = `SELECT r.block_num, r.tx_index, r.tx_hash, r.type, r.status, r.cumulative_gas_used, r.gas_used, r.contract_address, r.effective_gas_price
+					 FROM state.receipt r
+					 WHERE r.block_num BETWEEN $1 AND $2
+					 ORDER BY r.block_num ASC, r.tx_index ASC`
+
+	const l2LogSQL = `SELECT r.block_num, b.block_hash, l.tx_hash, r.tx_index, l.log_index, l.address, l.data, l.topic0, l.topic1, l.topic2, l.topic3
+					 FROM state.log l
+					 INNER JOIN state.receipt r ON r.tx_hash = l.tx_hash
+					 INNER JOIN state.l2block b ON b.block_num = r.block_num
+					 WHERE r.block_num BETWEEN $1 AND $2
+					 ORDER BY r.block_num ASC, r.tx_index ASC, l.log_index ASC`
+
+	e := p.getExecQuerier(dbTx)
+	rows, err := e.Query(ctx, l2ReceiptSQL, firstL2Block, lastL2Block)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var receipts []*types.Receipt
+	receiptsByTxHash := make(map[common.Hash]*types.Receipt)
+
+	for rows.Next() {
+		receipt, err := scanDSL2Receipt(rows)
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, receipt)
+		receiptsByTxHash[receipt.TxHash] = receipt
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	logRows, err := e.Query(ctx, l2LogSQL, firstL2Block, lastL2Block)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := scanLogs(logRows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, l := range logs {
+		if receipt, ok := receiptsByTxHash[l.TxHash]; ok {
+			receipt.Logs = append(receipt.Logs, l)
+		}
+	}
+
+	return receipts, nil
+}
+
+func scanDSL2Receipt(row pgx.Row) (*types.Receipt, error) {
+	receipt := types.Receipt{}
+	var (
+		blockNumber       uint64
+		txHash            string
+		contractAddress   *string
+		effectiveGasPrice *uint64
+	)
+
+	if err := row.Scan(
+		&blockNumber,
+		&receipt.TransactionIndex,
+		&txHash,
+		&receipt.Type,
+		&receipt.Status,
+		&receipt.CumulativeGasUsed,
+		&receipt.GasUsed,
+		&contractAddress,
+		&effectiveGasPrice,
+	); err != nil {
+		return nil, err
+	}
+
+	receipt.BlockNumber = new(big.Int).SetUint64(blockNumber)
+	receipt.TxHash = common.HexToHash(txHash)
+	if contractAddress != nil {
+		receipt.ContractAddress = common.HexToAddress(*contractAddress)
+	}
+	if effectiveGasPrice != nil {
+		receipt.EffectiveGasPrice = new(big.Int).SetUint64(*effectiveGasPrice)
+	}
+	receipt.Logs = []*types.Log{}
+	return &receipt, nil
+}
+
 // GetDSBatches returns the DS batches
 func (p *PostgresStorage) GetDSBatches(ctx context.Context, firstBatchNumber, lastBatchNumber uint64, readWIPBatch bool, dbTx pgx.Tx) ([]*state.DSBatch, error) {
 	var getBatchByNumberSQL = `
@@ -0,0 +1,36 @@
+package pgstatestorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/jackc/pgx/v4"
+)
+
+// SetForceBatchTimeout stores the force batch timeout of the rollup contract
+func (p *PostgresStorage) SetForceBatchTimeout(ctx context.Context, timeout time.Duration, dbTx pgx.Tx) error {
+	const setSQL = `
+		INSERT INTO state.force_batch_timeout (id, timeout, updated_at) VALUES (1, $1, NOW())
+		ON CONFLICT (id) DO UPDATE SET timeout = EXCLUDED.timeout, updated_at = EXCLUDED.updated_at`
+
+	e := p.getExecQuerier(dbTx)
+	_, err := e.Exec(ctx, setSQL, int64(timeout.Seconds()))
+	return err
+}
+
+// GetForceBatchTimeout returns the stored force batch timeout of the rollup contract
+func (p *PostgresStorage) GetForceBatchTimeout(ctx context.Context, dbTx pgx.Tx) (time.Duration, error) {
+	const getSQL = "SELECT timeout FROM state.force_batch_timeout WHERE id = 1"
+
+	var timeout int64
+	e := p.getExecQuerier(dbTx)
+	err := e.QueryRow(ctx, getSQL).Scan(&timeout)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, state.ErrNotFound
+	} else if err != nil {
+		return 0, err
+	}
+	return time.Duration(timeout) * time.Second, nil
+}
@@ -26,14 +26,12 @@ func (p *PostgresStorage) GetL2BlockByNumber(ctx context.Context, blockNumber ui
 		return nil, err
 	}
 
-	transactions, err := p.GetTxsByBlockNumber(ctx, header.Number.Uint64(), dbTx)
-	if errors.Is(err, pgx.ErrNoRows) {
-		transactions = []*types.Transaction{}
-	} else if err != nil {
+	transactions, prunedTxHashes, err := p.getL2BlockTxs(ctx, header.Number.Uint64(), dbTx)
+	if err != nil {
 		return nil, err
 	}
 
-	block := buildBlock(header, transactions, uncles, receivedAt)
+	block := buildBlock(header, transactions, prunedTxHashes, uncles, receivedAt)
 	return block, nil
 }
 
@@ -79,14 +77,12 @@ func (p *PostgresStorage) GetL2BlocksByBatchNumber(ctx context.Context, batchNum
 
 	l2Blocks := make([]state.L2Block, 0, len(rows.RawValues()))
 	for _, l2BlockInfo := range l2BlockInfos {
-		transactions, err := p.GetTxsByBlockNumber(ctx, l2BlockInfo.header.Number.Uint64(), dbTx)
-		if errors.Is(err, pgx.ErrNoRows) {
-			transactions = []*types.Transaction{}
-		} else if err != nil {
+		transactions, prunedTxHashes, err := p.getL2BlockTxs(ctx, l2BlockInfo.header.Number.Uint64(), dbTx)
+		if err != nil {
 			return nil, err
 		}
 
-		block := buildBlock(l2BlockInfo.header, transactions, l2BlockInfo.uncles, l2BlockInfo.receivedAt)
+		block := buildBlock(l2BlockInfo.header, transactions, prunedTxHashes, l2BlockInfo.uncles, l2BlockInfo.receivedAt)
 		l2Blocks = append(l2Blocks, *block)
 	}
 
@@ -104,14 +100,12 @@ func (p *PostgresStorage) GetLastL2BlockByBatchNumber(ctx context.Context, batch
 		return nil, err
 	}
 
-	transactions, err := p.GetTxsByBlockNumber(ctx, header.Number.Uint64(), dbTx)
-	if errors.Is(err, pgx.ErrNoRows) {
-		transactions = []*types.Transaction{}
-	} else if err != nil {
+	transactions, prunedTxHashes, err := p.getL2BlockTxs(ctx, header.Number.Uint64(), dbTx)
+	if err != nil {
 		return nil, err
 	}
 
-	block := buildBlock(header, transactions, uncles, receivedAt)
+	block := buildBlock(header, transactions, prunedTxHashes, uncles, receivedAt)
 	return block, nil
 }
 
@@ -382,14 +376,12 @@ func (p *PostgresStorage) GetLastL2Block(ctx context.Context, dbTx pgx.Tx) (*sta
 		return nil, err
 	}
 
-	transactions, err := p.GetTxsByBlockNumber(ctx, header.Number.Uint64(), dbTx)
-	if errors.Is(err, pgx.ErrNoRows) {
-		transactions = []*types.Transaction{}
-	} else if err != nil {
+	transactions, prunedTxHashes, err := p.getL2BlockTxs(ctx, header.Number.Uint64(), dbTx)
+	if err != nil {
 		return nil, err
 	}
 
-	block := buildBlock(header, transactions, uncles, receivedAt)
+	block := buildBlock(header, transactions, prunedTxHashes, uncles, receivedAt)
 	return block, nil
 }
 
@@ -404,14 +396,12 @@ func (p *PostgresStorage) GetL2BlockByHash(ctx context.Context, hash common.Hash
 		return nil, err
 	}
 
-	transactions, err := p.GetTxsByBlockNumber(ctx, header.Number.Uint64(), dbTx)
-	if errors.Is(err, pgx.ErrNoRows) {
-		transactions = []*types.Transaction{}
-	} else if err != nil {
+	transactions, prunedTxHashes, err := p.getL2BlockTxs(ctx, header.Number.Uint64(), dbTx)
+	if err != nil {
 		return nil, err
 	}
 
-	block := buildBlock(header, transactions, uncles, receivedAt)
+	block := buildBlock(header, transactions, prunedTxHashes, uncles, receivedAt)
 	return block, nil
 }
 
@@ -534,9 +524,10 @@ func (p *PostgresStorage) IsL2BlockVirtualized(ctx context.Context, blockNumber
 	return isVirtualized, nil
 }
 
-func buildBlock(header *state.L2Header, transactions []*types.Transaction, uncles []*state.L2Header, receivedAt time.Time) *state.L2Block {
+func buildBlock(header *state.L2Header, transactions []*types.Transaction, prunedTxHashes []common.Hash, uncles []*state.L2Header, receivedAt time.Time) *state.L2Block {
 	l2Block := state.NewL2BlockWithHeader(header).WithBody(transactions, uncles)
 	l2Block.ReceivedAt = receivedAt
+	l2Block.PrunedTxHashes = prunedTxHashes
 
 	return l2Block
 }
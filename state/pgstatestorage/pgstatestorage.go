@@ -130,6 +130,10 @@ func (p *PostgresStorage) GetLogsByBlockNumber(ctx context.Context, blockNumber
        WHERE b.block_num = $1
        ORDER BY r.tx_index ASC, l.log_index ASC`
 
+	if err := p.checkL2BlockNotPruned(ctx, blockNumber, dbTx); err != nil {
+		return nil, err
+	}
+
 	q := p.getExecQuerier(dbTx)
 	rows, err := q.Query(ctx, query, blockNumber)
 	if err != nil {
@@ -208,6 +212,14 @@ func (p *PostgresStorage) GetLogs(ctx context.Context, fromBlock uint64, toBlock
 	var queryToCount string
 	var queryToSelect string
 	if blockHash != nil {
+		header, err := p.GetL2BlockHeaderByHash(ctx, *blockHash, dbTx)
+		if err == nil {
+			err = p.checkL2BlockNotPruned(ctx, header.Number.Uint64(), dbTx)
+		}
+		if err != nil && !errors.Is(err, state.ErrNotFound) {
+			return nil, err
+		}
+
 		args = append(args, blockHash.String())
 		queryToCount = queryToCountLogsByBlockHash
 		queryToSelect = queryToSelectLogsByBlockHash
@@ -221,6 +233,10 @@ func (p *PostgresStorage) GetLogs(ctx context.Context, fromBlock uint64, toBlock
 			return nil, state.ErrMaxLogsBlockRangeLimitExceeded
 		}
 
+		if err := p.checkL2BlockNotPruned(ctx, fromBlock, dbTx); err != nil {
+			return nil, err
+		}
+
 		args = append(args, fromBlock, toBlock)
 		queryToCount = queryToCountLogsByBlockNumbers
 		queryToSelect = queryToSelectLogsByBlockNumbers
@@ -1766,3 +1766,179 @@ func TestGetUncheckedBlocks(t *testing.T) {
 	require.Equal(t, uint64(blockNumber+1), blocks[0].BlockNumber)
 	require.Equal(t, uint64(blockNumber+3), blocks[1].BlockNumber)
 }
+
+func TestPruneHistory(t *testing.T) {
+	initOrResetDB()
+	setup()
+	ctx := context.Background()
+
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Commit(ctx)) }()
+	require.NoError(t, testState.AddBlock(ctx, block, dbTx))
+
+	txs := make([]*types.Transaction, 0, 2)
+	for batchNumber := uint64(1); batchNumber <= 2; batchNumber++ {
+		_, err = dbTx.Exec(ctx, "INSERT INTO state.batch (batch_num, wip) VALUES ($1, FALSE)", batchNumber)
+		require.NoError(t, err)
+
+		tx := types.NewTx(&types.LegacyTx{Nonce: batchNumber, Value: new(big.Int), GasPrice: big.NewInt(0)})
+		receipt := &types.Receipt{
+			Type:              tx.Type(),
+			PostState:         state.ZeroHash.Bytes(),
+			EffectiveGasPrice: big.NewInt(0),
+			BlockNumber:       new(big.Int).SetUint64(batchNumber),
+			TxHash:            tx.Hash(),
+			Status:            types.ReceiptStatusSuccessful,
+			Logs:              []*types.Log{{TxHash: tx.Hash()}},
+		}
+		header := state.NewL2Header(&types.Header{
+			Number:     new(big.Int).SetUint64(batchNumber),
+			ParentHash: state.ZeroHash,
+			Coinbase:   state.ZeroAddress,
+			Root:       state.ZeroHash,
+			GasUsed:    1,
+			GasLimit:   10,
+			Time:       uint64(time.Now().Unix()),
+		})
+		l2Block := state.NewL2Block(header, []*types.Transaction{tx}, []*state.L2Header{}, []*types.Receipt{receipt}, trie.NewStackTrie(nil))
+		receipt.BlockHash = l2Block.Hash()
+		storeTxsEGPData := []state.StoreTxEGPData{{EGPLog: nil, EffectivePercentage: state.MaxEffectivePercentage}}
+		err = pgStateStorage.AddL2Block(ctx, batchNumber, l2Block, []*types.Receipt{receipt}, []common.Hash{tx.Hash()}, storeTxsEGPData, []common.Hash{state.ZeroHash}, dbTx)
+		require.NoError(t, err)
+		txs = append(txs, tx)
+	}
+
+	// Nothing is pruned beyond the last L2 block of the batch
+	pruned, err := pgStateStorage.PruneHistory(ctx, 1, 10, dbTx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), pruned)
+	pruned, err = pgStateStorage.PruneHistory(ctx, 1, 10, dbTx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), pruned)
+	firstKept, err := pgStateStorage.GetFirstKeptL2BlockNumber(ctx, dbTx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), firstKept)
+
+	_, err = pgStateStorage.GetTransactionByHash(ctx, txs[0].Hash(), dbTx)
+	require.ErrorIs(t, err, state.ErrHistoryPruned)
+	_, err = pgStateStorage.GetTransactionReceipt(ctx, txs[0].Hash(), dbTx)
+	require.ErrorIs(t, err, state.ErrHistoryPruned)
+	_, err = pgStateStorage.GetLogsByBlockNumber(ctx, 1, dbTx)
+	require.ErrorIs(t, err, state.ErrHistoryPruned)
+	_, err = pgStateStorage.GetLogs(ctx, 1, 2, nil, nil, nil, nil, dbTx)
+	require.ErrorIs(t, err, state.ErrHistoryPruned)
+	_, err = pgStateStorage.GetTxsByBlockNumber(ctx, 1, dbTx)
+	require.ErrorIs(t, err, state.ErrHistoryPruned)
+	_, err = pgStateStorage.GetTraces(ctx, state.TraceFilter{FromBlock: 1, ToBlock: 2}, dbTx)
+	require.ErrorIs(t, err, state.ErrHistoryPruned)
+
+	// The header and the transaction hashes of the pruned L2 blocks are kept
+	l2Block, err := pgStateStorage.GetL2BlockByNumber(ctx, 1, dbTx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), l2Block.NumberU64())
+	require.Empty(t, l2Block.Transactions())
+	require.Equal(t, []common.Hash{txs[0].Hash()}, l2Block.PrunedTxHashes)
+
+	// The history of the second batch is kept
+	_, err = pgStateStorage.GetTransactionByHash(ctx, txs[1].Hash(), dbTx)
+	require.NoError(t, err)
+	receipt, err := pgStateStorage.GetTransactionReceipt(ctx, txs[1].Hash(), dbTx)
+	require.NoError(t, err)
+	require.Len(t, receipt.Logs, 1)
+	logs, err := pgStateStorage.GetLogs(ctx, 2, 2, nil, nil, nil, nil, dbTx)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	l2Block, err = pgStateStorage.GetL2BlockByNumber(ctx, 2, dbTx)
+	require.NoError(t, err)
+	require.Len(t, l2Block.Transactions(), 1)
+	require.Empty(t, l2Block.PrunedTxHashes)
+
+	_, err = pgStateStorage.GetTransactionByHash(ctx, common.HexToHash("0x1234"), dbTx)
+	require.ErrorIs(t, err, state.ErrNotFound)
+}
+
+func TestSequencerLease(t *testing.T) {
+	initOrResetDB()
+	ctx := context.Background()
+	const duration = time.Minute
+
+	// the first sequencer acquires the free lease
+	term, acquired, err := testState.AcquireSequencerLease(ctx, "seq1", duration, nil)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	assert.Equal(t, uint64(1), term)
+	require.NoError(t, testState.CheckSequencerLease(ctx, "seq1", term, nil))
+
+	// the second sequencer can't acquire it until it's released
+	_, acquired, err = testState.AcquireSequencerLease(ctx, "seq2", duration, nil)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+	assert.ErrorIs(t, testState.CheckSequencerLease(ctx, "seq2", term, nil), state.ErrSequencerLeaseLost)
+
+	renewed, err := testState.RenewSequencerLease(ctx, "seq1", term, duration, nil)
+	require.NoError(t, err)
+	assert.True(t, renewed)
+	require.NoError(t, testState.ReleaseSequencerLease(ctx, "seq1", term, nil))
+	assert.ErrorIs(t, testState.CheckSequencerLease(ctx, "seq1", term, nil), state.ErrSequencerLeaseLost)
+
+	newTerm, acquired, err := testState.AcquireSequencerLease(ctx, "seq2", duration, nil)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	assert.Equal(t, term+1, newTerm)
+	require.NoError(t, testState.CheckSequencerLease(ctx, "seq2", newTerm, nil))
+
+	// the previous holder is fenced
+	renewed, err = testState.RenewSequencerLease(ctx, "seq1", term, duration, nil)
+	require.NoError(t, err)
+	assert.False(t, renewed)
+	assert.ErrorIs(t, testState.CheckSequencerLease(ctx, "seq1", term, nil), state.ErrSequencerLeaseLost)
+}
+
+func TestSequencerHalt(t *testing.T) {
+	initOrResetDB()
+	ctx := context.Background()
+
+	_, err := testState.GetSequencerHalt(ctx, nil)
+	require.ErrorIs(t, err, state.ErrNotFound)
+
+	// there is no halt scheduled to be reached or resumed
+	halted, err := testState.SetSequencerHalted(ctx, nil)
+	require.NoError(t, err)
+	assert.False(t, halted)
+	resumed, err := testState.ResumeSequencer(ctx, "upgrade done", nil)
+	require.NoError(t, err)
+	assert.False(t, resumed)
+
+	haltAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	require.NoError(t, testState.ScheduleSequencerHalt(ctx, 10, &haltAt, "fork upgrade", nil))
+	halt, err := testState.GetSequencerHalt(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), halt.BatchNumber)
+	assert.True(t, haltAt.Equal(*halt.HaltAt))
+	assert.Equal(t, state.SequencerHaltScheduled, halt.Status)
+	assert.Equal(t, "fork upgrade", halt.Reason)
+
+	halted, err = testState.SetSequencerHalted(ctx, nil)
+	require.NoError(t, err)
+	assert.True(t, halted)
+	halt, err = testState.GetSequencerHalt(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, state.SequencerHaltHalted, halt.Status)
+
+	resumed, err = testState.ResumeSequencer(ctx, "upgrade done", nil)
+	require.NoError(t, err)
+	assert.True(t, resumed)
+	halt, err = testState.GetSequencerHalt(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, state.SequencerHaltResumed, halt.Status)
+	assert.Equal(t, "upgrade done", halt.Reason)
+
+	// a new halt replaces the resumed one
+	require.NoError(t, testState.ScheduleSequencerHalt(ctx, 20, nil, "db maintenance", nil))
+	halt, err = testState.GetSequencerHalt(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20), halt.BatchNumber)
+	assert.Nil(t, halt.HaltAt)
+	assert.Equal(t, state.SequencerHaltScheduled, halt.Status)
+}
@@ -0,0 +1,153 @@
+package pgstatestorage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+// GetFirstKeptL2BlockNumber returns the first L2 block whose receipts, logs and transaction bodies
+// have not been pruned
+func (p *PostgresStorage) GetFirstKeptL2BlockNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	const getFirstKeptL2BlockNumberSQL = "SELECT first_kept_l2_block_num FROM state.pruned_history LIMIT 1"
+
+	var firstKept uint64
+	q := p.getExecQuerier(dbTx)
+	err := q.QueryRow(ctx, getFirstKeptL2BlockNumberSQL).Scan(&firstKept)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return firstKept, nil
+}
+
+// PruneHistory deletes the receipts (including the intermediate state roots), logs, traces and cached traces of the L2 blocks
+// that belong to batches up to toBatchNumber, and clears the body of their transactions. The hash and the index of
+// the transactions are kept, so the L2 blocks can still be read with the hashes of their transactions, and queries
+// by hash can return ErrHistoryPruned instead of ErrNotFound. At most maxL2Blocks are pruned. It returns the number
+// of L2 blocks pruned
+func (p *PostgresStorage) PruneHistory(ctx context.Context, toBatchNumber uint64, maxL2Blocks uint64, dbTx pgx.Tx) (uint64, error) {
+	const getLastL2BlockNumSQL = "SELECT MAX(block_num) FROM state.l2block WHERE batch_num <= $1"
+	const storeTxIndexesSQL = "UPDATE state.transaction t SET tx_index = r.tx_index FROM state.receipt r WHERE r.tx_hash = t.hash AND t.l2_block_num BETWEEN $1 AND $2"
+	const deleteLogsSQL = "DELETE FROM state.log WHERE tx_hash IN (SELECT hash FROM state.transaction WHERE l2_block_num BETWEEN $1 AND $2)"
+	const deleteReceiptsSQL = "DELETE FROM state.receipt WHERE block_num BETWEEN $1 AND $2"
+	const deleteTracesSQL = "DELETE FROM state.trace WHERE l2_block_num BETWEEN $1 AND $2"
+	const deleteCachedTracesSQL = "DELETE FROM state.trace_cache WHERE l2_block_num BETWEEN $1 AND $2"
+	const clearTxsSQL = "UPDATE state.transaction SET encoded = '', decoded = NULL, egp_log = NULL WHERE l2_block_num BETWEEN $1 AND $2"
+	const updatePrunedHistorySQL = "UPDATE state.pruned_history SET first_kept_l2_block_num = $1"
+
+	if dbTx == nil {
+		return 0, state.ErrDBTxNil
+	}
+
+	fromL2Block, err := p.GetFirstKeptL2BlockNumber(ctx, dbTx)
+	if err != nil {
+		return 0, err
+	}
+	var toL2Block *uint64
+	err = dbTx.QueryRow(ctx, getLastL2BlockNumSQL, toBatchNumber).Scan(&toL2Block)
+	if err != nil {
+		return 0, err
+	}
+	if toL2Block == nil || *toL2Block < fromL2Block {
+		return 0, nil
+	}
+	to := *toL2Block
+	if maxL2Blocks > 0 && to-fromL2Block+1 > maxL2Blocks {
+		to = fromL2Block + maxL2Blocks - 1
+	}
+
+	for _, sql := range []string{storeTxIndexesSQL, deleteLogsSQL, deleteReceiptsSQL, deleteTracesSQL, deleteCachedTracesSQL, clearTxsSQL} {
+		if _, err := dbTx.Exec(ctx, sql, fromL2Block, to); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := dbTx.Exec(ctx, updatePrunedHistorySQL, to+1); err != nil {
+		return 0, err
+	}
+	return to - fromL2Block + 1, nil
+}
+
+// checkL2BlockNotPruned returns ErrHistoryPruned if the history of the L2 block has been pruned
+func (p *PostgresStorage) checkL2BlockNotPruned(ctx context.Context, l2BlockNumber uint64, dbTx pgx.Tx) error {
+	firstKept, err := p.GetFirstKeptL2BlockNumber(ctx, dbTx)
+	if err != nil {
+		return err
+	}
+	if l2BlockNumber < firstKept {
+		return state.ErrHistoryPruned
+	}
+	return nil
+}
+
+// checkTxNotPruned returns ErrHistoryPruned if the transaction exists and its body has been pruned
+func (p *PostgresStorage) checkTxNotPruned(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) error {
+	const isTxPrunedSQL = "SELECT encoded = '' FROM state.transaction WHERE hash = $1"
+
+	var pruned bool
+	q := p.getExecQuerier(dbTx)
+	err := q.QueryRow(ctx, isTxPrunedSQL, transactionHash.String()).Scan(&pruned)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if pruned {
+		return state.ErrHistoryPruned
+	}
+	return nil
+}
+
+// decodeStoredTx decodes a transaction read from the state, returning ErrHistoryPruned if its body has been pruned
+func decodeStoredTx(encoded string) (*types.Transaction, error) {
+	if encoded == "" {
+		return nil, state.ErrHistoryPruned
+	}
+	return state.DecodeTx(encoded)
+}
+
+// getL2BlockTxs returns the transactions of the L2 block ordered by their index, or the hashes of the transactions
+// whose bodies have been pruned, so the header and the transaction hashes of a pruned L2 block can still be read
+func (p *PostgresStorage) getL2BlockTxs(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]*types.Transaction, []common.Hash, error) {
+	const getL2BlockTxsSQL = `
+		SELECT t.hash, t.encoded
+		  FROM state.transaction t
+		  LEFT JOIN state.receipt r
+		    ON r.tx_hash = t.hash
+		 WHERE t.l2_block_num = $1
+		 ORDER BY COALESCE(r.tx_index, t.tx_index) ASC`
+
+	q := p.getExecQuerier(dbTx)
+	rows, err := q.Query(ctx, getL2BlockTxsSQL, blockNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	txs := []*types.Transaction{}
+	var prunedTxHashes []common.Hash
+	for rows.Next() {
+		var hash, encoded string
+		if err := rows.Scan(&hash, &encoded); err != nil {
+			return nil, nil, err
+		}
+		if encoded == "" {
+			prunedTxHashes = append(prunedTxHashes, common.HexToHash(hash))
+			continue
+		}
+		tx, err := state.DecodeTx(encoded)
+		if err != nil {
+			return nil, nil, err
+		}
+		txs = append(txs, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return txs, prunedTxHashes, nil
+}
@@ -0,0 +1,71 @@
+package pgstatestorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/jackc/pgx/v4"
+)
+
+// sequencerHaltName is the name of the halt scheduled for the sequencer
+const sequencerHaltName = "sequencer"
+
+// ScheduleSequencerHalt schedules a halt of the sequencer at the batch number or the time given, replacing the
+// previous one if any
+func (p *PostgresStorage) ScheduleSequencerHalt(ctx context.Context, batchNumber uint64, haltAt *time.Time, reason string, dbTx pgx.Tx) error {
+	const scheduleSQL = `
+		INSERT INTO state.sequencer_halt (name, batch_num, halt_at, status, reason, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (name) DO UPDATE SET batch_num = EXCLUDED.batch_num, halt_at = EXCLUDED.halt_at, status = EXCLUDED.status,
+		reason = EXCLUDED.reason, updated_at = EXCLUDED.updated_at`
+
+	e := p.getExecQuerier(dbTx)
+	_, err := e.Exec(ctx, scheduleSQL, sequencerHaltName, batchNumber, haltAt, string(state.SequencerHaltScheduled), reason)
+	return err
+}
+
+// SetSequencerHalted sets the scheduled halt of the sequencer as halted, returning false if there is no halt scheduled
+func (p *PostgresStorage) SetSequencerHalted(ctx context.Context, dbTx pgx.Tx) (bool, error) {
+	const haltedSQL = "UPDATE state.sequencer_halt SET status = $2, updated_at = NOW() WHERE name = $1 AND status = $3"
+
+	e := p.getExecQuerier(dbTx)
+	commandTag, err := e.Exec(ctx, haltedSQL, sequencerHaltName, string(state.SequencerHaltHalted), string(state.SequencerHaltScheduled))
+	if err != nil {
+		return false, err
+	}
+	return commandTag.RowsAffected() > 0, nil
+}
+
+// ResumeSequencer resumes the sequencer if it's halted, or cancels the halt if it's still scheduled, storing the reason
+// of the resume. It returns false if there is no halt scheduled or halted
+func (p *PostgresStorage) ResumeSequencer(ctx context.Context, reason string, dbTx pgx.Tx) (bool, error) {
+	const resumeSQL = "UPDATE state.sequencer_halt SET status = $2, reason = $3, updated_at = NOW() WHERE name = $1 AND status <> $2"
+
+	e := p.getExecQuerier(dbTx)
+	commandTag, err := e.Exec(ctx, resumeSQL, sequencerHaltName, string(state.SequencerHaltResumed), reason)
+	if err != nil {
+		return false, err
+	}
+	return commandTag.RowsAffected() > 0, nil
+}
+
+// GetSequencerHalt returns the last halt scheduled for the sequencer, or state.ErrNotFound if no halt has ever been scheduled
+func (p *PostgresStorage) GetSequencerHalt(ctx context.Context, dbTx pgx.Tx) (*state.SequencerHalt, error) {
+	const getSQL = "SELECT batch_num, halt_at, status, reason, updated_at FROM state.sequencer_halt WHERE name = $1"
+
+	var (
+		halt   state.SequencerHalt
+		status string
+	)
+	e := p.getExecQuerier(dbTx)
+	err := e.QueryRow(ctx, getSQL, sequencerHaltName).Scan(&halt.BatchNumber, &halt.HaltAt, &status, &halt.Reason, &halt.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, state.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	halt.Status = state.SequencerHaltStatus(status)
+	return &halt, nil
+}
@@ -0,0 +1,83 @@
+package pgstatestorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/jackc/pgx/v4"
+)
+
+// sequencerLeaseName is the name of the lease the sequencers compete for
+const sequencerLeaseName = "sequencer"
+
+// AcquireSequencerLease acquires the sequencer lease for the holder if it's free, it has expired or it's
+// already held by the same holder. Each time the lease is acquired its term is increased, so the previous
+// term can't be used anymore. The expiration time is calculated with the clock of the database
+func (p *PostgresStorage) AcquireSequencerLease(ctx context.Context, holder string, duration time.Duration, dbTx pgx.Tx) (uint64, bool, error) {
+	const acquireSQL = `
+		INSERT INTO state.sequencer_lease (name, holder, term, expires_at)
+		VALUES ($1, $2, 1, NOW() + $3::BIGINT * INTERVAL '1 millisecond')
+		ON CONFLICT (name) DO UPDATE SET holder = EXCLUDED.holder, term = state.sequencer_lease.term + 1, expires_at = EXCLUDED.expires_at
+		WHERE state.sequencer_lease.expires_at <= NOW() OR state.sequencer_lease.holder = EXCLUDED.holder
+		RETURNING term`
+
+	var term uint64
+	e := p.getExecQuerier(dbTx)
+	err := e.QueryRow(ctx, acquireSQL, sequencerLeaseName, holder, duration.Milliseconds()).Scan(&term)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+	return term, true, nil
+}
+
+// RenewSequencerLease extends the expiration of the sequencer lease if it's still held by the holder in the same term
+func (p *PostgresStorage) RenewSequencerLease(ctx context.Context, holder string, term uint64, duration time.Duration, dbTx pgx.Tx) (bool, error) {
+	const renewSQL = `
+		UPDATE state.sequencer_lease SET expires_at = NOW() + $4::BIGINT * INTERVAL '1 millisecond'
+		WHERE name = $1 AND holder = $2 AND term = $3`
+
+	e := p.getExecQuerier(dbTx)
+	commandTag, err := e.Exec(ctx, renewSQL, sequencerLeaseName, holder, term, duration.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	return commandTag.RowsAffected() > 0, nil
+}
+
+// ReleaseSequencerLease expires the sequencer lease if it's still held by the holder in the same term, so
+// another sequencer can acquire it without waiting for the expiration
+func (p *PostgresStorage) ReleaseSequencerLease(ctx context.Context, holder string, term uint64, dbTx pgx.Tx) error {
+	const releaseSQL = "UPDATE state.sequencer_lease SET expires_at = NOW() WHERE name = $1 AND holder = $2 AND term = $3"
+
+	e := p.getExecQuerier(dbTx)
+	_, err := e.Exec(ctx, releaseSQL, sequencerLeaseName, holder, term)
+	return err
+}
+
+// CheckSequencerLease returns state.ErrSequencerLeaseLost if the sequencer lease isn't held by the holder in the
+// same term or it has expired. When it's called in a db transaction the lease is locked until the transaction
+// ends, so it can't be acquired by another sequencer while the transaction is writing to the state
+func (p *PostgresStorage) CheckSequencerLease(ctx context.Context, holder string, term uint64, dbTx pgx.Tx) error {
+	const checkSQL = "SELECT holder, term, expires_at > NOW() FROM state.sequencer_lease WHERE name = $1 FOR SHARE"
+
+	var (
+		leaseHolder string
+		leaseTerm   uint64
+		valid       bool
+	)
+	e := p.getExecQuerier(dbTx)
+	err := e.QueryRow(ctx, checkSQL, sequencerLeaseName).Scan(&leaseHolder, &leaseTerm, &valid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return state.ErrSequencerLeaseLost
+	} else if err != nil {
+		return err
+	}
+	if leaseHolder != holder || leaseTerm != term || !valid {
+		return state.ErrSequencerLeaseLost
+	}
+	return nil
+}
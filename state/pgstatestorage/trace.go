@@ -0,0 +1,144 @@
+package pgstatestorage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+// AddL2BlockTraces stores the flat call traces of the transactions of a L2 block and marks it as indexed.
+// The traces are not stored if the L2 block has been replaced by another one with a different hash
+func (p *PostgresStorage) AddL2BlockTraces(ctx context.Context, l2BlockNumber uint64, l2BlockHash common.Hash, traces []state.IndexedTrace, dbTx pgx.Tx) error {
+	const getL2BlockHashSQL = "SELECT block_hash FROM state.l2block WHERE block_num = $1 FOR SHARE"
+	const addTraceSQL = `
+		INSERT INTO state.trace (l2_block_num, tx_index, trace_index, tx_hash, from_addr, to_addr, trace)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (l2_block_num, tx_index, trace_index) DO NOTHING`
+	const addIndexedL2BlockSQL = "INSERT INTO state.trace_indexed_l2block (l2_block_num) VALUES ($1) ON CONFLICT (l2_block_num) DO NOTHING"
+
+	e := p.getExecQuerier(dbTx)
+	var blockHash string
+	err := e.QueryRow(ctx, getL2BlockHashSQL, l2BlockNumber).Scan(&blockHash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return state.ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	if common.HexToHash(blockHash) != l2BlockHash {
+		return fmt.Errorf("L2 block %d has been reorged while tracing it", l2BlockNumber)
+	}
+
+	for _, t := range traces {
+		var from, to *string
+		if t.From != nil {
+			s := t.From.String()
+			from = &s
+		}
+		if t.To != nil {
+			s := t.To.String()
+			to = &s
+		}
+		_, err := e.Exec(ctx, addTraceSQL, t.L2BlockNumber, t.TxIndex, t.TraceIndex, t.TxHash.String(), from, to, string(t.Trace))
+		if err != nil {
+			return err
+		}
+	}
+	_, err = e.Exec(ctx, addIndexedL2BlockSQL, l2BlockNumber)
+	return err
+}
+
+// GetLastIndexedTraceL2BlockNumber returns the last L2 block whose traces are stored
+func (p *PostgresStorage) GetLastIndexedTraceL2BlockNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	const getLastIndexedSQL = "SELECT MAX(l2_block_num) FROM state.trace_indexed_l2block"
+
+	var lastIndexed *uint64
+	q := p.getExecQuerier(dbTx)
+	err := q.QueryRow(ctx, getLastIndexedSQL).Scan(&lastIndexed)
+	if err != nil {
+		return 0, err
+	}
+	if lastIndexed == nil {
+		return 0, state.ErrNotFound
+	}
+	return *lastIndexed, nil
+}
+
+// GetTraces returns the stored traces matching the filter, sorted by block, transaction and position.
+// It returns ErrHistoryPruned if the range starts in a pruned L2 block
+func (p *PostgresStorage) GetTraces(ctx context.Context, filter state.TraceFilter, dbTx pgx.Tx) ([]json.RawMessage, error) {
+	const getTracesSQL = `
+		SELECT trace
+		  FROM state.trace
+		 WHERE l2_block_num BETWEEN $1 AND $2
+		   AND (cardinality($3::varchar[]) = 0 OR from_addr = ANY($3))
+		   AND (cardinality($4::varchar[]) = 0 OR to_addr = ANY($4))
+		 ORDER BY l2_block_num, tx_index, trace_index
+		OFFSET $5 LIMIT $6`
+
+	fromAddresses := make([]string, 0, len(filter.FromAddresses))
+	for _, a := range filter.FromAddresses {
+		fromAddresses = append(fromAddresses, a.String())
+	}
+	toAddresses := make([]string, 0, len(filter.ToAddresses))
+	for _, a := range filter.ToAddresses {
+		toAddresses = append(toAddresses, a.String())
+	}
+
+	q := p.getExecQuerier(dbTx)
+	rows, err := q.Query(ctx, getTracesSQL, filter.FromBlock, filter.ToBlock, fromAddresses, toAddresses, filter.After, filter.Count)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	traces := []json.RawMessage{}
+	for rows.Next() {
+		var trace string
+		if err := rows.Scan(&trace); err != nil {
+			return nil, err
+		}
+		traces = append(traces, json.RawMessage(trace))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// The pruned history is checked after reading the traces, so a range pruned while they were
+	// being read returns ErrHistoryPruned instead of an empty result
+	if err := p.checkL2BlockNotPruned(ctx, filter.FromBlock, dbTx); err != nil {
+		return nil, err
+	}
+	return traces, nil
+}
+
+// AddCachedTrace stores a trace generated by the debug endpoints for the transaction of the L2 block with the given hash
+func (p *PostgresStorage) AddCachedTrace(ctx context.Context, cacheKey string, l2BlockNumber uint64, l2BlockHash common.Hash, trace []byte, dbTx pgx.Tx) error {
+	const addCachedTraceSQL = `
+		INSERT INTO state.trace_cache (cache_key, l2_block_num, block_hash, trace)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (cache_key) DO UPDATE SET l2_block_num = EXCLUDED.l2_block_num, block_hash = EXCLUDED.block_hash, trace = EXCLUDED.trace, created_at = NOW()`
+
+	e := p.getExecQuerier(dbTx)
+	_, err := e.Exec(ctx, addCachedTraceSQL, cacheKey, l2BlockNumber, l2BlockHash.String(), trace)
+	return err
+}
+
+// GetCachedTrace returns the stored trace for the cache key. It returns ErrNotFound if there is no trace
+// or it was generated for a L2 block with a different hash
+func (p *PostgresStorage) GetCachedTrace(ctx context.Context, cacheKey string, l2BlockHash common.Hash, dbTx pgx.Tx) ([]byte, error) {
+	const getCachedTraceSQL = "SELECT trace FROM state.trace_cache WHERE cache_key = $1 AND block_hash = $2"
+
+	var trace []byte
+	q := p.getExecQuerier(dbTx)
+	err := q.QueryRow(ctx, getCachedTraceSQL, cacheKey, l2BlockHash.String()).Scan(&trace)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, state.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return trace, nil
+}
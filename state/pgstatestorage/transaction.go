@@ -174,7 +174,7 @@ func (p *PostgresStorage) GetTransactionsByBatchNumber(ctx context.Context, batc
 	}
 
 	for i := 0; i < len(encodedTxs); i++ {
-		tx, err := state.DecodeTx(encodedTxs[i])
+		tx, err := decodeStoredTx(encodedTxs[i])
 		if err != nil {
 			return nil, nil, err
 		}
@@ -224,7 +224,7 @@ func (p *PostgresStorage) GetTransactionByHash(ctx context.Context, transactionH
 		return nil, err
 	}
 
-	tx, err := state.DecodeTx(encoded)
+	tx, err := decodeStoredTx(encoded)
 	if err != nil {
 		return nil, err
 	}
@@ -246,7 +246,7 @@ func (p *PostgresStorage) GetTransactionByL2Hash(ctx context.Context, l2TxHash c
 		return nil, err
 	}
 
-	tx, err := state.DecodeTx(encoded)
+	tx, err := decodeStoredTx(encoded)
 	if err != nil {
 		return nil, err
 	}
@@ -299,6 +299,9 @@ func (p *PostgresStorage) GetTransactionReceipt(ctx context.Context, transaction
 		)
 
 	if errors.Is(err, pgx.ErrNoRows) {
+		if err := p.checkTxNotPruned(ctx, transactionHash, dbTx); err != nil {
+			return nil, err
+		}
 		return nil, state.ErrNotFound
 	} else if err != nil {
 		return nil, err
@@ -344,7 +347,7 @@ func (p *PostgresStorage) GetTransactionByL2BlockHashAndIndex(ctx context.Contex
 		return nil, err
 	}
 
-	tx, err := state.DecodeTx(encoded)
+	tx, err := decodeStoredTx(encoded)
 	if err != nil {
 		return nil, err
 	}
@@ -366,7 +369,7 @@ func (p *PostgresStorage) GetTransactionByL2BlockNumberAndIndex(ctx context.Cont
 		return nil, err
 	}
 
-	tx, err := state.DecodeTx(encoded)
+	tx, err := decodeStoredTx(encoded)
 	if err != nil {
 		return nil, err
 	}
@@ -450,41 +453,15 @@ func scanLogs(rows pgx.Rows) ([]*types.Log, error) {
 	return logs, nil
 }
 
-// GetTxsByBlockNumber returns all the txs in a given block
+// GetTxsByBlockNumber returns all the txs in a given block, or ErrHistoryPruned if their bodies have been pruned
 func (p *PostgresStorage) GetTxsByBlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]*types.Transaction, error) {
-	const getTxsByBlockNumSQL = `SELECT t.encoded 
-	   FROM state.transaction t
-	   JOIN state.receipt r
-	     ON t.hash = r.tx_hash
-	  WHERE t.l2_block_num = $1
-	    AND r.block_num = $1
-	  ORDER by r.tx_index ASC`
-
-	q := p.getExecQuerier(dbTx)
-	rows, err := q.Query(ctx, getTxsByBlockNumSQL, blockNumber)
-
-	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, state.ErrNotFound
-	} else if err != nil {
+	txs, prunedTxHashes, err := p.getL2BlockTxs(ctx, blockNumber, dbTx)
+	if err != nil {
 		return nil, err
 	}
-
-	defer rows.Close()
-
-	txs := make([]*types.Transaction, 0, len(rows.RawValues()))
-	var encoded string
-	for rows.Next() {
-		if err = rows.Scan(&encoded); err != nil {
-			return nil, err
-		}
-
-		tx, err := state.DecodeTx(encoded)
-		if err != nil {
-			return nil, err
-		}
-		txs = append(txs, tx)
+	if len(prunedTxHashes) > 0 {
+		return nil, state.ErrHistoryPruned
 	}
-
 	return txs, nil
 }
 
@@ -516,7 +493,7 @@ func (p *PostgresStorage) GetTxsByBatchNumber(ctx context.Context, batchNumber u
 			return nil, err
 		}
 
-		tx, err := state.DecodeTx(encoded)
+		tx, err := decodeStoredTx(encoded)
 		if err != nil {
 			return nil, err
 		}
@@ -636,6 +613,50 @@ func (p *PostgresStorage) GetTransactionEGPLogByHash(ctx context.Context, transa
 	return &egpLog, nil
 }
 
+// GetTransactionsEGPLogByL2BlockRange gets the txs and their EGP logs of the L2 blocks in the provided range, both included
+func (p *PostgresStorage) GetTransactionsEGPLogByL2BlockRange(ctx context.Context, fromBlockNumber, toBlockNumber uint64, dbTx pgx.Tx) ([]state.TransactionEGPLog, error) {
+	const getTransactionsEGPLogSQL = `SELECT t.l2_block_num, t.encoded, t.egp_log
+	   FROM state.transaction t
+	   JOIN state.receipt r
+	     ON t.hash = r.tx_hash
+	  WHERE t.l2_block_num BETWEEN $1 AND $2
+	  ORDER BY t.l2_block_num ASC, r.tx_index ASC`
+
+	q := p.getExecQuerier(dbTx)
+	rows, err := q.Query(ctx, getTransactionsEGPLogSQL, fromBlockNumber, toBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	txs := make([]state.TransactionEGPLog, 0, len(rows.RawValues()))
+	for rows.Next() {
+		var (
+			txEGPLog   state.TransactionEGPLog
+			encoded    string
+			egpLogData []byte
+		)
+		if err = rows.Scan(&txEGPLog.L2BlockNumber, &encoded, &egpLogData); err != nil {
+			return nil, err
+		}
+
+		txEGPLog.Tx, err = decodeStoredTx(encoded)
+		if err != nil {
+			return nil, err
+		}
+
+		if egpLogData != nil {
+			txEGPLog.EGPLog = &state.EffectiveGasPriceLog{}
+			if err = json.Unmarshal(egpLogData, txEGPLog.EGPLog); err != nil {
+				return nil, err
+			}
+		}
+		txs = append(txs, txEGPLog)
+	}
+
+	return txs, nil
+}
+
 // GetL2TxHashByTxHash gets the L2 Hash from the tx found by the provided tx hash
 func (p *PostgresStorage) GetL2TxHashByTxHash(ctx context.Context, hash common.Hash, dbTx pgx.Tx) (*common.Hash, error) {
 	const getTransactionByHashSQL = "SELECT transaction.l2_hash FROM state.transaction WHERE hash = $1"
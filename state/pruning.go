@@ -0,0 +1,77 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// StartHistoryPruning periodically prunes the history of the L2 blocks older than the configured
+// number of verified batches. It returns immediately if the node runs in archive mode
+func (s *State) StartHistoryPruning(ctx context.Context) {
+	switch s.cfg.Pruning.Mode {
+	case PruningModeFull:
+	case PruningModeArchive, "":
+		log.Infof("running in %s mode, history pruning disabled", PruningModeArchive)
+		return
+	default:
+		log.Errorf("unknown pruning mode %s, history pruning disabled", s.cfg.Pruning.Mode)
+		return
+	}
+	log.Infof("history pruning enabled, keeping the history of the last %d verified batches", s.cfg.Pruning.KeepVerifiedBatches)
+	for {
+		for {
+			pruned, err := s.PruneHistory(ctx)
+			if err != nil {
+				log.Errorf("error pruning history: %v", err)
+				break
+			}
+			if pruned == 0 {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.Pruning.Interval.Duration):
+		}
+	}
+}
+
+// PruneHistory prunes the receipts, logs and transaction bodies of up to MaxL2BlocksPerRun L2 blocks
+// that belong to batches older than the last KeepVerifiedBatches verified batches. It returns the
+// number of L2 blocks pruned
+func (s *State) PruneHistory(ctx context.Context) (uint64, error) {
+	lastVerifiedBatch, err := s.GetLastVerifiedBatch(ctx, nil)
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if lastVerifiedBatch.BatchNumber <= s.cfg.Pruning.KeepVerifiedBatches {
+		return 0, nil
+	}
+	toBatchNumber := lastVerifiedBatch.BatchNumber - s.cfg.Pruning.KeepVerifiedBatches
+
+	dbTx, err := s.BeginStateTransaction(ctx)
+	if err != nil {
+		return 0, err
+	}
+	pruned, err := s.storage.PruneHistory(ctx, toBatchNumber, s.cfg.Pruning.MaxL2BlocksPerRun, dbTx)
+	if err != nil {
+		if rollbackErr := dbTx.Rollback(ctx); rollbackErr != nil {
+			log.Errorf("error rolling back history pruning: %v", rollbackErr)
+		}
+		return 0, err
+	}
+	if err := dbTx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	if pruned > 0 {
+		log.Infof("pruned history of %d L2 blocks up to batch %d", pruned, toBatchNumber)
+	}
+	return pruned, nil
+}
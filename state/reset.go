@@ -20,6 +20,12 @@ func (s *State) Reset(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) erro
 		return err
 	}
 	s.ResetL1InfoTree()
+	if s.traceCache != nil {
+		s.traceCache.clear()
+	}
+	if s.witnessCache != nil {
+		s.witnessCache.clear()
+	}
 	return nil
 }
 
@@ -10,4 +10,32 @@ type Config struct {
 	// WaitOnResourceExhaustion is the time to wait before retrying a transaction because of resource exhaustion
 	WaitOnResourceExhaustion types.Duration `mapstructure:"WaitOnResourceExhaustion"`
 	MaxGRPCMessageSize       int            `mapstructure:"MaxGRPCMessageSize"`
+
+	// Endpoints is the list of executors used instead of URI. The requests of each role are balanced
+	// between the healthy executors serving it, sending each one to the executor with the least
+	// requests in flight. All the executors must share the same state DB
+	Endpoints []EndpointConfig `mapstructure:"Endpoints"`
+	// HealthCheckInterval is the time between the health checks of the Endpoints
+	HealthCheckInterval types.Duration `mapstructure:"HealthCheckInterval"`
+	// CircuitBreakerFailures is the number of consecutive failed requests that stops sending
+	// requests to an executor of the Endpoints
+	CircuitBreakerFailures int `mapstructure:"CircuitBreakerFailures"`
+	// CircuitBreakerTimeout is the time to wait before sending requests again to an executor
+	// stopped by the circuit breaker
+	CircuitBreakerTimeout types.Duration `mapstructure:"CircuitBreakerTimeout"`
+
+	// InProcess executes the batches in the node with the reference executor instead of using the
+	// executor of the prover. The state tree is kept in memory, so the node refuses to start if the
+	// state DB is not empty. Only meant for tests and light deployments, the ZK counters are estimations
+	InProcess bool `mapstructure:"InProcess"`
+}
+
+// EndpointConfig represents the configuration of an executor of the pool
+type EndpointConfig struct {
+	// URI is the address of the executor
+	URI string `mapstructure:"URI"`
+	// Roles are the components whose requests are sent to the executor: sequencer, rpc and/or synchronizer.
+	// An executor that only serves the sequencer is never used by the other roles, and the ones shared
+	// with the sequencer are avoided by the other roles while processing a sequencer request
+	Roles []Role `mapstructure:"Roles"`
 }
@@ -0,0 +1,269 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Role is the component sending requests to the executors
+type Role string
+
+const (
+	// RoleSequencer is the role of the requests of the sequencer
+	RoleSequencer Role = "sequencer"
+	// RoleRPC is the role of the requests of the JSON RPC
+	RoleRPC Role = "rpc"
+	// RoleSynchronizer is the role of the requests of the synchronizer
+	RoleSynchronizer Role = "synchronizer"
+)
+
+// ErrNoExecutorAvailable is returned when all the executors that can serve a request are unhealthy
+var ErrNoExecutorAvailable = errors.New("no executor available")
+
+type roleContextKey struct{}
+
+// WithRole returns a copy of the context whose executor requests are sent with the given role
+func WithRole(ctx context.Context, role Role) context.Context {
+	return context.WithValue(ctx, roleContextKey{}, role)
+}
+
+// RoleFromContext returns the role set in the context, or the default role if it's not set
+func RoleFromContext(ctx context.Context, defaultRole Role) Role {
+	if role, ok := ctx.Value(roleContextKey{}).(Role); ok {
+		return role
+	}
+	return defaultRole
+}
+
+// poolEndpoint is an executor of the pool
+type poolEndpoint struct {
+	uri    string
+	roles  map[Role]bool
+	conn   grpc.ClientConnInterface
+	client ExecutorServiceClient
+
+	inFlight          atomic.Int64
+	sequencerInFlight atomic.Int64
+
+	mutex     sync.Mutex
+	healthy   bool
+	failures  int
+	openUntil time.Time
+}
+
+// available returns true if the endpoint is healthy and its circuit is not open
+func (e *poolEndpoint) available(now time.Time) bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.healthy && !now.Before(e.openUntil)
+}
+
+// ClientPool balances the executor requests between several executors grouped by role, skipping
+// the ones that fail the health checks or whose circuit breaker is open
+type ClientPool struct {
+	cfg       Config
+	endpoints []*poolEndpoint
+}
+
+// NewClientPool creates a pool with the executors of the Endpoints config and starts checking their
+// health until the context is done. Unlike NewExecutorClient, it doesn't wait for the executors to be up
+func NewClientPool(ctx context.Context, c Config) (*ClientPool, error) {
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(c.MaxGRPCMessageSize)),
+	}
+	conns := make([]grpc.ClientConnInterface, 0, len(c.Endpoints))
+	for _, endpointCfg := range c.Endpoints {
+		conn, err := grpc.NewClient(endpointCfg.URI, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create grpc connection to executor %s: %w", endpointCfg.URI, err)
+		}
+		conn.Connect()
+		conns = append(conns, conn)
+	}
+	p, err := newClientPool(c, conns)
+	if err != nil {
+		return nil, err
+	}
+	p.checkHealth(ctx)
+	go p.startHealthChecks(ctx)
+	return p, nil
+}
+
+func newClientPool(c Config, conns []grpc.ClientConnInterface) (*ClientPool, error) {
+	if len(c.Endpoints) == 0 {
+		return nil, fmt.Errorf("no executor endpoints configured")
+	}
+	if c.HealthCheckInterval.Duration <= 0 {
+		return nil, fmt.Errorf("invalid executor health check interval %v", c.HealthCheckInterval.Duration)
+	}
+	p := &ClientPool{cfg: c}
+	for i, endpointCfg := range c.Endpoints {
+		if len(endpointCfg.Roles) == 0 {
+			return nil, fmt.Errorf("no roles configured for executor %s", endpointCfg.URI)
+		}
+		roles := map[Role]bool{}
+		for _, role := range endpointCfg.Roles {
+			if role != RoleSequencer && role != RoleRPC && role != RoleSynchronizer {
+				return nil, fmt.Errorf("invalid role %s for executor %s", role, endpointCfg.URI)
+			}
+			roles[role] = true
+		}
+		p.endpoints = append(p.endpoints, &poolEndpoint{
+			uri:     endpointCfg.URI,
+			roles:   roles,
+			conn:    conns[i],
+			client:  NewExecutorServiceClient(conns[i]),
+			healthy: true,
+		})
+	}
+	return p, nil
+}
+
+// Client returns an executor client that sends the requests with the role set in their context
+// by WithRole, or with the default role if it's not set
+func (p *ClientPool) Client(defaultRole Role) ExecutorServiceClient {
+	return NewExecutorServiceClient(&poolClientConn{pool: p, defaultRole: defaultRole})
+}
+
+func (p *ClientPool) startHealthChecks(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.cfg.HealthCheckInterval.Duration):
+			p.checkHealth(ctx)
+		}
+	}
+}
+
+// checkHealth asks every executor for its flush status, marking the ones that don't answer as unhealthy
+func (p *ClientPool) checkHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, e := range p.endpoints {
+		wg.Add(1)
+		go func(e *poolEndpoint) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, p.cfg.HealthCheckInterval.Duration)
+			defer cancel()
+			_, err := e.client.GetFlushStatus(checkCtx, &emptypb.Empty{})
+
+			e.mutex.Lock()
+			defer e.mutex.Unlock()
+			if err != nil && e.healthy {
+				log.Warnf("executor %s is unhealthy: %v", e.uri, err)
+			} else if err == nil && !e.healthy {
+				log.Infof("executor %s is healthy again", e.uri)
+			}
+			e.healthy = err == nil
+		}(e)
+	}
+	wg.Wait()
+}
+
+// pick returns the available executor with the least requests in flight for the role, skipping
+// the excluded one. If no executor of the role is available, the sequencer fails over to any
+// executor and the other roles to the executors not serving the sequencer
+func (p *ClientPool) pick(role Role, excluded *poolEndpoint) (*poolEndpoint, error) {
+	now := time.Now()
+	var best, bestBusy, fallback *poolEndpoint
+	for _, e := range p.endpoints {
+		if e == excluded || !e.available(now) {
+			continue
+		}
+		if !e.roles[role] {
+			if (role == RoleSequencer || !e.roles[RoleSequencer]) && (fallback == nil || e.inFlight.Load() < fallback.inFlight.Load()) {
+				fallback = e
+			}
+			continue
+		}
+		// the sequencer has priority on the executors shared with it
+		if role != RoleSequencer && e.sequencerInFlight.Load() > 0 {
+			if bestBusy == nil || e.inFlight.Load() < bestBusy.inFlight.Load() {
+				bestBusy = e
+			}
+			continue
+		}
+		if best == nil || e.inFlight.Load() < best.inFlight.Load() {
+			best = e
+		}
+	}
+	switch {
+	case best != nil:
+		return best, nil
+	case bestBusy != nil:
+		return bestBusy, nil
+	case fallback != nil:
+		return fallback, nil
+	}
+	return nil, fmt.Errorf("%w for role %s", ErrNoExecutorAvailable, role)
+}
+
+// invoke sends the request to the executor, opening its circuit if it fails too many times in a row
+func (p *ClientPool) invoke(ctx context.Context, e *poolEndpoint, role Role, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	e.inFlight.Add(1)
+	defer e.inFlight.Add(-1)
+	if role == RoleSequencer {
+		e.sequencerInFlight.Add(1)
+		defer e.sequencerInFlight.Add(-1)
+	}
+
+	err := e.conn.Invoke(ctx, method, args, reply, opts...)
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if status.Code(err) != codes.Unavailable {
+		e.failures = 0
+		return err
+	}
+	e.failures++
+	if p.cfg.CircuitBreakerFailures > 0 && e.failures >= p.cfg.CircuitBreakerFailures {
+		log.Warnf("executor %s failed %d requests in a row, not using it for %v: %v", e.uri, e.failures, p.cfg.CircuitBreakerTimeout.Duration, err)
+		e.openUntil = time.Now().Add(p.cfg.CircuitBreakerTimeout.Duration)
+	}
+	return err
+}
+
+// poolClientConn routes the requests of a role to the executors of the pool
+type poolClientConn struct {
+	pool        *ClientPool
+	defaultRole Role
+}
+
+// Invoke sends the request to the best executor for its role. If the executor can't be reached,
+// the request is sent again to another one
+func (c *poolClientConn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	role := RoleFromContext(ctx, c.defaultRole)
+	e, err := c.pool.pick(role, nil)
+	if err != nil {
+		return err
+	}
+	err = c.pool.invoke(ctx, e, role, method, args, reply, opts...)
+	if status.Code(err) == codes.Unavailable {
+		if other, pickErr := c.pool.pick(role, e); pickErr == nil {
+			log.Warnf("executor %s unavailable, sending %s request to executor %s", e.uri, role, other.uri)
+			err = c.pool.invoke(ctx, other, role, method, args, reply, opts...)
+		}
+	}
+	return err
+}
+
+// NewStream creates a stream with the best executor for the role of the context
+func (c *poolClientConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	e, err := c.pool.pick(RoleFromContext(ctx, c.defaultRole), nil)
+	if err != nil {
+		return nil, err
+	}
+	return e.conn.NewStream(ctx, desc, method, opts...)
+}
@@ -0,0 +1,171 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeConn struct {
+	mutex    sync.Mutex
+	err      error
+	requests int
+	// block makes the requests wait until it's closed
+	block chan struct{}
+}
+
+func (c *fakeConn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	c.mutex.Lock()
+	c.requests++
+	err, block := c.err, c.block
+	c.mutex.Unlock()
+	if block != nil {
+		<-block
+	}
+	return err
+}
+
+func (c *fakeConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *fakeConn) requestCount() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.requests
+}
+
+func newTestClientPool(t *testing.T, roles ...[]Role) (*ClientPool, []*fakeConn) {
+	cfg := Config{
+		HealthCheckInterval:    types.NewDuration(time.Second),
+		CircuitBreakerFailures: 2,
+		CircuitBreakerTimeout:  types.NewDuration(time.Hour),
+	}
+	conns := []grpc.ClientConnInterface{}
+	fakeConns := []*fakeConn{}
+	for _, r := range roles {
+		cfg.Endpoints = append(cfg.Endpoints, EndpointConfig{URI: "executor", Roles: r})
+		conn := &fakeConn{}
+		conns = append(conns, conn)
+		fakeConns = append(fakeConns, conn)
+	}
+	p, err := newClientPool(cfg, conns)
+	require.NoError(t, err)
+	return p, fakeConns
+}
+
+func TestClientPoolRoles(t *testing.T) {
+	p, conns := newTestClientPool(t, []Role{RoleSequencer}, []Role{RoleRPC, RoleSynchronizer})
+	ctx := context.Background()
+
+	_, err := p.Client(RoleRPC).GetFlushStatus(ctx, nil)
+	require.NoError(t, err)
+	_, err = p.Client(RoleRPC).GetFlushStatus(WithRole(ctx, RoleSynchronizer), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, conns[0].requestCount())
+	assert.Equal(t, 2, conns[1].requestCount())
+
+	_, err = p.Client(RoleRPC).GetFlushStatus(WithRole(ctx, RoleSequencer), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, conns[0].requestCount())
+
+	// the sequencer fails over to the other executors, but the other roles never use its dedicated executor
+	p.endpoints[0].healthy = false
+	_, err = p.Client(RoleSequencer).GetFlushStatus(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, conns[1].requestCount())
+
+	p.endpoints[0].healthy = true
+	p.endpoints[1].healthy = false
+	_, err = p.Client(RoleRPC).GetFlushStatus(ctx, nil)
+	require.ErrorIs(t, err, ErrNoExecutorAvailable)
+}
+
+func TestClientPoolLeastInFlight(t *testing.T) {
+	p, conns := newTestClientPool(t, []Role{RoleRPC, RoleSequencer}, []Role{RoleRPC})
+	ctx := context.Background()
+
+	// a sequencer request is in flight in the shared executor
+	conns[0].block = make(chan struct{})
+	done := make(chan error)
+	go func() {
+		_, err := p.Client(RoleSequencer).GetFlushStatus(ctx, nil)
+		done <- err
+	}()
+	require.Eventually(t, func() bool { return p.endpoints[0].inFlight.Load() == 1 }, time.Second, time.Millisecond)
+
+	// the RPC requests go to the other executor
+	conns[1].block = make(chan struct{})
+	go func() {
+		_, err := p.Client(RoleRPC).GetFlushStatus(ctx, nil)
+		done <- err
+	}()
+	require.Eventually(t, func() bool { return p.endpoints[1].inFlight.Load() == 1 }, time.Second, time.Millisecond)
+
+	// both executors have a request in flight, but the sequencer has priority on the shared one
+	e, err := p.pick(RoleRPC, nil)
+	require.NoError(t, err)
+	assert.Equal(t, p.endpoints[1], e)
+
+	close(conns[0].block)
+	close(conns[1].block)
+	require.NoError(t, <-done)
+	require.NoError(t, <-done)
+
+	e, err = p.pick(RoleRPC, nil)
+	require.NoError(t, err)
+	assert.Equal(t, p.endpoints[0], e)
+}
+
+func TestClientPoolCircuitBreaker(t *testing.T) {
+	p, conns := newTestClientPool(t, []Role{RoleRPC}, []Role{RoleRPC})
+	ctx := context.Background()
+	conns[0].err = status.Error(codes.Unavailable, "connection refused")
+
+	// the request is sent again to the other executor
+	_, err := p.Client(RoleRPC).GetFlushStatus(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, conns[0].requestCount())
+	assert.Equal(t, 1, conns[1].requestCount())
+
+	// the second failure opens the circuit
+	p.endpoints[1].inFlight.Add(1)
+	_, err = p.Client(RoleRPC).GetFlushStatus(ctx, nil)
+	require.NoError(t, err)
+	p.endpoints[1].inFlight.Add(-1)
+	assert.Equal(t, 2, conns[0].requestCount())
+	assert.Equal(t, 2, conns[1].requestCount())
+	assert.False(t, p.endpoints[0].available(time.Now()))
+
+	_, err = p.Client(RoleRPC).GetFlushStatus(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, conns[0].requestCount())
+
+	// the circuit lets requests through again after the timeout
+	assert.True(t, p.endpoints[0].available(time.Now().Add(2*time.Hour)))
+}
+
+func TestNewClientPoolInvalidConfig(t *testing.T) {
+	cfg := Config{
+		HealthCheckInterval: types.NewDuration(time.Second),
+		Endpoints:           []EndpointConfig{{URI: "executor", Roles: []Role{"prover"}}},
+	}
+	_, err := newClientPool(cfg, []grpc.ClientConnInterface{&fakeConn{}})
+	require.Error(t, err)
+
+	cfg.Endpoints[0].Roles = nil
+	_, err = newClientPool(cfg, []grpc.ClientConnInterface{&fakeConn{}})
+	require.Error(t, err)
+
+	_, err = newClientPool(Config{HealthCheckInterval: types.NewDuration(time.Second)}, nil)
+	require.Error(t, err)
+}
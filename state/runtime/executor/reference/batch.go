@@ -0,0 +1,581 @@
+package reference
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/fakevm"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/instrumentation/tracers"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/instrumentation/tracers/native"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// txGasLimit is the max gas limit of a tx
+	txGasLimit = 30000000
+	// pushZeroEIP enables the PUSH0 opcode, supported since the etrog fork
+	pushZeroEIP = 3855
+)
+
+var (
+	// systemAddress is the address of the system contract storing the L2 block number and
+	// timestamp, the state root of every L2 block and the block info root of the last one
+	systemAddress = common.HexToAddress("0x000000000000000000000000000000005ca1ab1e")
+	// gerManagerAddress is the address of the global exit root manager of L2
+	gerManagerAddress = common.HexToAddress("0xa40D5f56745a118D0906a34E69aEC8C0Db1cB8fA")
+
+	blockNumberSlot   = common.BigToHash(big.NewInt(0))
+	stateRootsSlot    = common.BigToHash(big.NewInt(1))
+	timestampSlot     = common.BigToHash(big.NewInt(2))
+	blockInfoRootSlot = common.BigToHash(big.NewInt(3))
+
+	globalExitRootMapSlot = common.BigToHash(big.NewInt(0))
+	localExitRootSlot     = common.BigToHash(big.NewInt(1))
+)
+
+// executorError is an error reported in the Error of the response instead of as a call error
+type executorError executor.ExecutorError
+
+func (e executorError) Error() string {
+	return executor.ExecutorError(e).String()
+}
+
+// mappingSlot returns the storage slot of the key of a solidity mapping
+func mappingSlot(key common.Hash, slot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(key.Bytes(), slot.Bytes())
+}
+
+// chainConfig returns the config of the zkEVM, which supports the opcodes and the gas costs of
+// the Berlin fork
+func chainConfig(chainID uint64) *params.ChainConfig {
+	return &params.ChainConfig{
+		ChainID:             new(big.Int).SetUint64(chainID),
+		HomesteadBlock:      big.NewInt(0),
+		EIP150Block:         big.NewInt(0),
+		EIP155Block:         big.NewInt(0),
+		EIP158Block:         big.NewInt(0),
+		ByzantiumBlock:      big.NewInt(0),
+		ConstantinopleBlock: big.NewInt(0),
+		PetersburgBlock:     big.NewInt(0),
+		IstanbulBlock:       big.NewInt(0),
+		BerlinBlock:         big.NewInt(0),
+	}
+}
+
+// batchProcessor executes a batch, writing its changes in the state tree
+type batchProcessor struct {
+	ctx         context.Context
+	tree        *merkletree.StateTree
+	req         *executor.ProcessBatchRequestV2
+	chainConfig *params.ChainConfig
+	coinbase    common.Address
+	forced      bool
+
+	db       *stateDB
+	response *executor.ProcessBatchResponseV2
+	counters native.ZKCounters
+}
+
+func newBatchProcessor(ctx context.Context, tree *merkletree.StateTree, req *executor.ProcessBatchRequestV2) *batchProcessor {
+	return &batchProcessor{
+		ctx:         ctx,
+		tree:        tree,
+		req:         req,
+		chainConfig: chainConfig(req.ChainId),
+		coinbase:    common.HexToAddress(req.Coinbase),
+		forced:      common.BytesToHash(req.ForcedBlockhashL1) != common.Hash{},
+		db:          newStateDB(ctx, tree, common.BytesToHash(req.OldStateRoot)),
+	}
+}
+
+func (p *batchProcessor) process() (*executor.ProcessBatchResponseV2, error) {
+	if p.req.ForkId < state.FORKID_ETROG {
+		return &executor.ProcessBatchResponseV2{Error: executor.ExecutorError_EXECUTOR_ERROR_UNSUPPORTED_FORK_ID}, nil
+	}
+	p.response = &executor.ProcessBatchResponseV2{
+		OldStateRoot:    p.req.OldStateRoot,
+		NewAccInputHash: p.accInputHash().Bytes(),
+		NewBatchNum:     p.req.OldBatchNum + 1,
+		ForkId:          p.req.ForkId,
+		Error:           executor.ExecutorError_EXECUTOR_ERROR_NO_ERROR,
+		ErrorRom:        executor.RomError_ROM_ERROR_NO_ERROR,
+	}
+
+	blocks, err := p.decodeBlocks()
+	if err != nil {
+		p.invalidateBatch(executor.RomError_ROM_ERROR_INVALID_RLP)
+	}
+	for i, block := range blocks {
+		romErr, err := p.processBlock(i, block)
+		var execErr executorError
+		if errors.As(err, &execErr) {
+			return &executor.ProcessBatchResponseV2{Error: executor.ExecutorError(execErr)}, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if romErr != executor.RomError_ROM_ERROR_NO_ERROR {
+			p.invalidateBatch(romErr)
+			break
+		}
+	}
+
+	localExitRoot := p.db.GetState(gerManagerAddress, localExitRootSlot)
+	if p.db.err != nil {
+		return nil, p.db.err
+	}
+	p.response.NewStateRoot = p.db.root.Bytes()
+	p.response.NewLocalExitRoot = localExitRoot.Bytes()
+	p.setCounters()
+	p.response.ReadWriteAddresses = map[string]*executor.InfoReadWriteV2{}
+	for address, a := range p.db.accounts {
+		p.response.ReadWriteAddresses[address.String()] = &executor.InfoReadWriteV2{
+			Nonce:   new(big.Int).SetUint64(a.nonce).String(),
+			Balance: a.balance.String(),
+		}
+	}
+	return p.response, nil
+}
+
+// decodeBlocks returns the L2 blocks of the batch. The forced batches have a single block with
+// all the txs
+func (p *batchProcessor) decodeBlocks() ([]state.L2BlockRaw, error) {
+	if !p.forced {
+		batch, err := state.DecodeBatchV2(p.req.BatchL2Data)
+		if err != nil {
+			return nil, err
+		}
+		return batch.Blocks, nil
+	}
+	batch, err := state.DecodeForcedBatchV2(p.req.BatchL2Data)
+	if err != nil {
+		return nil, err
+	}
+	return []state.L2BlockRaw{{Transactions: batch.Transactions}}, nil
+}
+
+// invalidateBatch discards all the changes of the batch
+func (p *batchProcessor) invalidateBatch(romErr executor.RomError) {
+	p.response.InvalidBatch = 1
+	p.response.ErrorRom = romErr
+	p.response.BlockResponses = nil
+	p.response.GasUsed = 0
+	p.db = newStateDB(p.ctx, p.tree, common.BytesToHash(p.req.OldStateRoot))
+	p.counters = native.ZKCounters{}
+}
+
+// accInputHash returns the accumulated input hash of the batch
+func (p *batchProcessor) accInputHash() common.Hash {
+	timestampLimit := make([]byte, 8) //nolint:gomnd
+	binary.BigEndian.PutUint64(timestampLimit, p.req.TimestampLimit)
+	return crypto.Keccak256Hash(
+		common.BytesToHash(p.req.OldAccInputHash).Bytes(),
+		crypto.Keccak256(p.req.BatchL2Data),
+		common.BytesToHash(p.req.L1InfoRoot).Bytes(),
+		timestampLimit,
+		p.coinbase.Bytes(),
+		common.BytesToHash(p.req.ForcedBlockhashL1).Bytes(),
+	)
+}
+
+// processBlock executes a L2 block. The block changes the block number, timestamp and global exit
+// root of the state before its txs are executed, unless it's the first one and the request skips it
+func (p *batchProcessor) processBlock(index int, block state.L2BlockRaw) (executor.RomError, error) {
+	parentHash := p.db.root
+	blockNumber := p.db.GetState(systemAddress, blockNumberSlot).Big().Uint64()
+	timestamp := p.db.GetState(systemAddress, timestampSlot).Big().Uint64()
+	var ger, blockHashL1 common.Hash
+
+	if index > 0 || p.req.SkipFirstChangeL2Block == 0 {
+		if p.forced {
+			if p.req.TimestampLimit > timestamp {
+				timestamp = p.req.TimestampLimit
+			}
+			ger, blockHashL1 = common.BytesToHash(p.req.L1InfoRoot), common.BytesToHash(p.req.ForcedBlockhashL1)
+		} else {
+			timestamp += uint64(block.DeltaTimestamp)
+			if timestamp > p.req.TimestampLimit {
+				return executor.RomError_ROM_ERROR_INVALID_TX_CHANGE_L2_BLOCK_LIMIT_TIMESTAMP, nil
+			}
+			if block.IndexL1InfoTree != 0 {
+				l1Data, found := p.req.L1InfoTreeData[block.IndexL1InfoTree]
+				if !found {
+					return executor.RomError_ROM_ERROR_NO_ERROR, executorError(executor.ExecutorError_EXECUTOR_ERROR_INVALID_L1_INFO_TREE_INDEX)
+				}
+				if timestamp < l1Data.MinTimestamp {
+					return executor.RomError_ROM_ERROR_INVALID_TX_CHANGE_L2_BLOCK_MIN_TIMESTAMP, nil
+				}
+				ger, blockHashL1 = common.BytesToHash(l1Data.GlobalExitRoot), common.BytesToHash(l1Data.BlockHashL1)
+			}
+		}
+
+		p.db.SetState(systemAddress, mappingSlot(common.BigToHash(new(big.Int).SetUint64(blockNumber)), stateRootsSlot), parentHash)
+		blockNumber++
+		p.db.SetState(systemAddress, blockNumberSlot, common.BigToHash(new(big.Int).SetUint64(blockNumber)))
+		p.db.SetState(systemAddress, timestampSlot, common.BigToHash(new(big.Int).SetUint64(timestamp)))
+		if ger != (common.Hash{}) {
+			gerSlot := mappingSlot(ger, globalExitRootMapSlot)
+			if p.db.GetState(gerManagerAddress, gerSlot) == (common.Hash{}) {
+				p.db.SetState(gerManagerAddress, gerSlot, blockHashL1)
+			}
+		}
+		if _, err := p.db.commit(); err != nil {
+			return executor.RomError_ROM_ERROR_NO_ERROR, err
+		}
+	}
+
+	blockResponse := &executor.ProcessBlockResponseV2{
+		ParentHash:  parentHash.Bytes(),
+		Coinbase:    p.coinbase.String(),
+		GasLimit:    state.MaxL2BlockGasLimit,
+		BlockNumber: blockNumber,
+		Timestamp:   timestamp,
+		Ger:         ger.Bytes(),
+		BlockHashL1: blockHashL1.Bytes(),
+		Error:       executor.RomError_ROM_ERROR_NO_ERROR,
+	}
+	blockInfo := newBlockInfoTree()
+	blockInfo.setHeader(parentHash, p.coinbase, blockNumber, timestamp, ger, blockHashL1)
+
+	blockCtx := fakevm.BlockContext{
+		CanTransfer: func(db fakevm.FakeDB, address common.Address, amount *big.Int) bool {
+			return db.GetBalance(address).Cmp(amount) >= 0
+		},
+		Transfer: func(db fakevm.FakeDB, sender, recipient common.Address, amount *big.Int) {
+			db.SubBalance(sender, amount)
+			db.AddBalance(recipient, amount)
+		},
+		GetHash: func(n uint64) common.Hash {
+			return p.db.GetState(systemAddress, mappingSlot(common.BigToHash(new(big.Int).SetUint64(n)), stateRootsSlot))
+		},
+		Coinbase:    p.coinbase,
+		GasLimit:    state.MaxL2BlockGasLimit,
+		BlockNumber: new(big.Int).SetUint64(blockNumber),
+		Time:        timestamp,
+		Difficulty:  big.NewInt(0),
+	}
+
+	var includedTxs uint64
+	for _, tx := range block.Transactions {
+		txResponse, logs, err := p.processTx(blockCtx, tx, blockResponse.GasUsed)
+		if err != nil {
+			return executor.RomError_ROM_ERROR_NO_ERROR, err
+		}
+		txResponse.BlockNumber = blockNumber
+		txIndex := uint32(len(blockResponse.Responses))
+		for _, log := range logs {
+			txResponse.Logs = append(txResponse.Logs, &executor.LogV2{
+				Address:     log.Address.String(),
+				Topics:      topicsToBytes(log.Topics),
+				Data:        log.Data,
+				BlockNumber: blockNumber,
+				TxHash:      txResponse.TxHash,
+				TxHashL2:    txResponse.TxHashL2,
+				TxIndex:     txIndex,
+				Index:       uint32(len(blockResponse.Logs)),
+			})
+			blockResponse.Logs = append(blockResponse.Logs, txResponse.Logs[len(txResponse.Logs)-1])
+		}
+		blockResponse.Responses = append(blockResponse.Responses, txResponse)
+
+		if state.IsStateRootChanged(txResponse.Error) {
+			blockResponse.GasUsed = txResponse.CumulativeGasUsed
+			blockInfo.addTx(includedTxs, common.BytesToHash(txResponse.TxHashL2), uint64(txResponse.Status), txResponse.CumulativeGasUsed,
+				logs, uint64(len(blockResponse.Logs)-len(logs)), tx.EfficiencyPercentage)
+			includedTxs++
+		}
+	}
+
+	blockInfo.setGasUsed(blockResponse.GasUsed)
+	blockInfoRoot, err := blockInfo.getRoot()
+	if err != nil {
+		return executor.RomError_ROM_ERROR_NO_ERROR, err
+	}
+	if p.req.SkipWriteBlockInfoRoot == 0 {
+		p.db.SetState(systemAddress, blockInfoRootSlot, blockInfoRoot)
+	}
+	blockHash, err := p.db.commit()
+	if err != nil {
+		return executor.RomError_ROM_ERROR_NO_ERROR, err
+	}
+	blockResponse.BlockInfoRoot = blockInfoRoot.Bytes()
+	blockResponse.BlockHash = blockHash.Bytes()
+	for _, txResponse := range blockResponse.Responses {
+		txResponse.BlockHash = blockHash.Bytes()
+	}
+	for _, log := range blockResponse.Logs {
+		log.BlockHash = blockHash.Bytes()
+	}
+
+	p.response.BlockResponses = append(p.response.BlockResponses, blockResponse)
+	p.response.GasUsed += blockResponse.GasUsed
+	return executor.RomError_ROM_ERROR_NO_ERROR, nil
+}
+
+// processTx executes a tx. The txs that fail the intrinsic checks don't change the state
+func (p *batchProcessor) processTx(blockCtx fakevm.BlockContext, raw state.L2TxRaw, cumulativeGasUsed uint64) (*executor.ProcessTransactionResponseV2, []*types.Log, error) {
+	tx := raw.Tx
+	rlpTx, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	// the effective gas price is the share of the gas price given by the percentage, in 256ths
+	effectiveGasPrice := new(big.Int).Mul(tx.GasPrice(), big.NewInt(int64(raw.EfficiencyPercentage)+1))
+	effectiveGasPrice.Div(effectiveGasPrice, big.NewInt(256)) //nolint:gomnd
+	response := &executor.ProcessTransactionResponseV2{
+		TxHash:              tx.Hash().Bytes(),
+		RlpTx:               rlpTx,
+		Type:                uint32(tx.Type()),
+		StateRoot:           p.db.root.Bytes(),
+		EffectiveGasPrice:   effectiveGasPrice.String(),
+		EffectivePercentage: uint32(raw.EfficiencyPercentage),
+		Error:               executor.RomError_ROM_ERROR_NO_ERROR,
+	}
+
+	var sender common.Address
+	if p.req.From != "" {
+		sender = common.HexToAddress(p.req.From)
+	} else if sender, err = state.GetSender(tx); err != nil {
+		response.Error = executor.RomError_ROM_ERROR_INTRINSIC_INVALID_SIGNATURE
+		return response, nil, nil
+	}
+	response.TxHashL2 = l2TxHash(&tx, sender).Bytes()
+	intrinsicGas := intrinsicGas(&tx)
+	response.Error = p.checkIntrinsic(&tx, sender, intrinsicGas, effectiveGasPrice, cumulativeGasUsed)
+	if p.db.err != nil {
+		return nil, nil, p.db.err
+	}
+	if response.Error != executor.RomError_ROM_ERROR_NO_ERROR {
+		return response, nil, nil
+	}
+
+	tracer, err := native.NewZKCounterTracer(&tracers.Context{}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	evm := fakevm.NewFakeEVM(blockCtx, fakevm.TxContext{Origin: sender, GasPrice: tx.GasPrice()}, p.db, p.chainConfig,
+		fakevm.Config{Debug: true, Tracer: tracer, ExtraEips: []int{pushZeroEIP}})
+	rules := p.chainConfig.Rules(blockCtx.BlockNumber, false, blockCtx.Time)
+	p.db.Prepare(rules, sender, p.coinbase, tx.To(), fakevm.ActivePrecompiles(rules), nil)
+
+	gasFee := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), effectiveGasPrice)
+	p.db.SubBalance(sender, gasFee)
+	var (
+		ret     []byte
+		gasLeft uint64
+		vmErr   error
+	)
+	if tx.To() == nil {
+		var address common.Address
+		ret, address, gasLeft, vmErr = evm.Create(fakevm.AccountRef(sender), tx.Data(), tx.Gas()-intrinsicGas, tx.Value())
+		response.CreateAddress = address.String()
+	} else {
+		p.db.SetNonce(sender, p.db.GetNonce(sender)+1)
+		ret, gasLeft, vmErr = evm.Call(fakevm.AccountRef(sender), *tx.To(), tx.Data(), tx.Gas()-intrinsicGas, tx.Value())
+	}
+
+	gasUsed := tx.Gas() - gasLeft
+	refund := p.db.GetRefund()
+	if refund > gasUsed/2 { //nolint:gomnd
+		refund = gasUsed / 2 //nolint:gomnd
+	}
+	gasLeft += refund
+	gasUsed -= refund
+	p.db.AddBalance(sender, new(big.Int).Mul(new(big.Int).SetUint64(gasLeft), effectiveGasPrice))
+	p.db.AddBalance(p.coinbase, new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), effectiveGasPrice))
+
+	logs := p.db.logs
+	root, err := p.db.commit()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := p.addCounters(tracer, response); err != nil {
+		return nil, nil, err
+	}
+
+	response.ReturnValue = ret
+	response.GasLeft = gasLeft
+	response.GasUsed = gasUsed
+	response.CumulativeGasUsed = cumulativeGasUsed + gasUsed
+	response.GasRefunded = refund
+	response.Error = romError(vmErr)
+	response.StateRoot = root.Bytes()
+	if vmErr == nil {
+		response.Status = 1
+	}
+	return response, logs, nil
+}
+
+// checkIntrinsic returns the error of the tx if it can't be executed
+func (p *batchProcessor) checkIntrinsic(tx *types.Transaction, sender common.Address, intrinsicGas uint64, effectiveGasPrice *big.Int, cumulativeGasUsed uint64) executor.RomError {
+	if tx.Protected() && tx.ChainId().Uint64() != p.req.ChainId {
+		return executor.RomError_ROM_ERROR_INTRINSIC_INVALID_CHAIN_ID
+	}
+	if tx.Gas() < intrinsicGas || tx.Gas() > txGasLimit {
+		return executor.RomError_ROM_ERROR_INTRINSIC_INVALID_GAS_LIMIT
+	}
+	if cumulativeGasUsed+tx.Gas() > state.MaxL2BlockGasLimit {
+		return executor.RomError_ROM_ERROR_INTRINSIC_INVALID_BATCH_GAS_LIMIT
+	}
+	if p.db.GetCodeSize(sender) > 0 {
+		return executor.RomError_ROM_ERROR_INTRINSIC_INVALID_SENDER_CODE
+	}
+	if tx.Nonce() != p.db.GetNonce(sender) {
+		return executor.RomError_ROM_ERROR_INTRINSIC_INVALID_NONCE
+	}
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), effectiveGasPrice)
+	cost.Add(cost, tx.Value())
+	if p.db.GetBalance(sender).Cmp(cost) < 0 {
+		return executor.RomError_ROM_ERROR_INTRINSIC_INVALID_BALANCE
+	}
+	return executor.RomError_ROM_ERROR_NO_ERROR
+}
+
+// addCounters adds the counters estimated by the tracer to the ones of the batch, and flags the
+// opcodes of the tx that make its effective gas price be recalculated
+func (p *batchProcessor) addCounters(tracer tracers.Tracer, response *executor.ProcessTransactionResponseV2) error {
+	result, err := tracer.GetResult()
+	if err != nil {
+		return err
+	}
+	var counters struct {
+		Counters native.ZKCounters `json:"counters"`
+		Opcodes  []struct {
+			OpCode string `json:"opcode"`
+		} `json:"opcodes"`
+	}
+	if err := json.Unmarshal(result, &counters); err != nil {
+		return err
+	}
+	p.counters.Steps += counters.Counters.Steps
+	p.counters.KeccakHashes += counters.Counters.KeccakHashes
+	p.counters.PoseidonHashes += counters.Counters.PoseidonHashes
+	p.counters.PoseidonPaddings += counters.Counters.PoseidonPaddings
+	p.counters.MemAligns += counters.Counters.MemAligns
+	p.counters.Arithmetics += counters.Counters.Arithmetics
+	p.counters.Binaries += counters.Counters.Binaries
+	p.counters.Sha256Hashes += counters.Counters.Sha256Hashes
+	for _, opcode := range counters.Opcodes {
+		switch opcode.OpCode {
+		case fakevm.GASPRICE.String():
+			response.HasGaspriceOpcode = 1
+		case fakevm.BALANCE.String():
+			response.HasBalanceOpcode = 1
+		}
+	}
+	return nil
+}
+
+// setCounters sets the counters of the batch in the response. The executor reserves the same
+// counters it uses
+func (p *batchProcessor) setCounters() {
+	p.response.CntSteps = uint32(p.counters.Steps)
+	p.response.CntKeccakHashes = uint32(p.counters.KeccakHashes)
+	p.response.CntPoseidonHashes = uint32(p.counters.PoseidonHashes)
+	p.response.CntPoseidonPaddings = uint32(p.counters.PoseidonPaddings)
+	p.response.CntMemAligns = uint32(p.counters.MemAligns)
+	p.response.CntArithmetics = uint32(p.counters.Arithmetics)
+	p.response.CntBinaries = uint32(p.counters.Binaries)
+	p.response.CntSha256Hashes = uint32(p.counters.Sha256Hashes)
+	p.response.CntReserveSteps = p.response.CntSteps
+	p.response.CntReserveKeccakHashes = p.response.CntKeccakHashes
+	p.response.CntReservePoseidonHashes = p.response.CntPoseidonHashes
+	p.response.CntReservePoseidonPaddings = p.response.CntPoseidonPaddings
+	p.response.CntReserveMemAligns = p.response.CntMemAligns
+	p.response.CntReserveArithmetics = p.response.CntArithmetics
+	p.response.CntReserveBinaries = p.response.CntBinaries
+	p.response.CntReserveSha256Hashes = p.response.CntSha256Hashes
+}
+
+// intrinsicGas returns the gas consumed by a tx before executing any opcode
+func intrinsicGas(tx *types.Transaction) uint64 {
+	gas := params.TxGas
+	if tx.To() == nil {
+		gas = params.TxGasContractCreation
+	}
+	for _, b := range tx.Data() {
+		if b == 0 {
+			gas += params.TxDataZeroGas
+		} else {
+			gas += params.TxDataNonZeroGasEIP2028
+		}
+	}
+	return gas
+}
+
+// l2TxHash returns the L2 hash of a tx: the linear poseidon hash of its type (1 if it's protected
+// by EIP155), nonce, gas price, gas, recipient (or 1 if it's a deployment), value, data length,
+// data, chain ID (only if it's protected) and sender, each field encoded with a fixed size
+func l2TxHash(tx *types.Transaction, sender common.Address) common.Hash {
+	input := "00"
+	if tx.Protected() {
+		input = "01"
+	}
+	input += fmt.Sprintf("%016x%064x%016x", tx.Nonce(), tx.GasPrice(), tx.Gas())
+	if tx.To() == nil {
+		input += "01"
+	} else {
+		input += "00" + hex.EncodeToString(tx.To().Bytes())
+	}
+	input += fmt.Sprintf("%064x%06x", tx.Value(), len(tx.Data())) + hex.EncodeToString(tx.Data())
+	if tx.Protected() {
+		input += fmt.Sprintf("%016x", tx.ChainId())
+	}
+	input += hex.EncodeToString(sender.Bytes())
+
+	hash, err := merkletree.HashContractBytecode(common.FromHex(input))
+	if err != nil {
+		return common.Hash{}
+	}
+	return common.HexToHash(merkletree.H4ToString(hash))
+}
+
+// romError returns the ROM error of a fakevm error
+func romError(err error) executor.RomError {
+	var (
+		stackUnderflow *fakevm.ErrStackUnderflow
+		stackOverflow  *fakevm.ErrStackOverflow
+		invalidOpCode  *fakevm.ErrInvalidOpCode
+	)
+	switch {
+	case err == nil:
+		return executor.RomError_ROM_ERROR_NO_ERROR
+	case errors.Is(err, fakevm.ErrExecutionReverted):
+		return executor.RomError_ROM_ERROR_EXECUTION_REVERTED
+	case errors.Is(err, fakevm.ErrInvalidJump):
+		return executor.RomError_ROM_ERROR_INVALID_JUMP
+	case errors.Is(err, fakevm.ErrWriteProtection):
+		return executor.RomError_ROM_ERROR_INVALID_STATIC
+	case errors.Is(err, fakevm.ErrMaxCodeSizeExceeded):
+		return executor.RomError_ROM_ERROR_MAX_CODE_SIZE_EXCEEDED
+	case errors.Is(err, fakevm.ErrContractAddressCollision):
+		return executor.RomError_ROM_ERROR_CONTRACT_ADDRESS_COLLISION
+	case errors.Is(err, fakevm.ErrInvalidCode):
+		return executor.RomError_ROM_ERROR_INVALID_BYTECODE_STARTS_EF
+	case errors.As(err, &stackUnderflow):
+		return executor.RomError_ROM_ERROR_STACK_UNDERFLOW
+	case errors.As(err, &stackOverflow):
+		return executor.RomError_ROM_ERROR_STACK_OVERFLOW
+	case errors.As(err, &invalidOpCode):
+		return executor.RomError_ROM_ERROR_INVALID_OPCODE
+	}
+	return executor.RomError_ROM_ERROR_OUT_OF_GAS
+}
+
+func topicsToBytes(topics []common.Hash) [][]byte {
+	result := make([][]byte, 0, len(topics))
+	for _, topic := range topics {
+		result = append(result, topic.Bytes())
+	}
+	return result
+}
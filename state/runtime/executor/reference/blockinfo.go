@@ -0,0 +1,114 @@
+package reference
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// indexes of the params of the header of a L2 block in the block info tree
+const (
+	blockHeaderParamBlockHash = iota
+	blockHeaderParamCoinbase
+	blockHeaderParamNumber
+	blockHeaderParamGasLimit
+	blockHeaderParamTimestamp
+	blockHeaderParamGER
+	blockHeaderParamBlockHashL1
+	blockHeaderParamGasUsed
+)
+
+// blockInfoGasLimit is the gas limit set in the header of every L2 block in the block info tree.
+// It's not the gas limit enforced by the sequencer, which is state.MaxL2BlockGasLimit
+const blockInfoGasLimit = uint64(math.MaxUint64)
+
+// blockInfoTree is the tree of a L2 block that stores its header and the L2 hash, status,
+// cumulative gas used, logs and effective percentage of its txs. Its root is stored in the
+// system contract at the end of the block
+type blockInfoTree struct {
+	db   *merkletree.MemoryHashDB
+	root []byte
+	err  error
+}
+
+func newBlockInfoTree() *blockInfoTree {
+	return &blockInfoTree{
+		db:   merkletree.NewMemoryHashDB(),
+		root: common.Hash{}.Bytes(),
+	}
+}
+
+func (t *blockInfoTree) set(key []byte, err error, value *big.Int) {
+	if t.err != nil {
+		return
+	}
+	if err != nil {
+		t.err = err
+		return
+	}
+	t.root, t.err = t.db.SetValue(t.root, key, value)
+}
+
+func (t *blockInfoTree) setHeaderParam(param int64, value *big.Int) {
+	key, err := merkletree.KeyBlockHeaderParams(big.NewInt(param))
+	t.set(key, err, value)
+}
+
+// setHeader sets the params of the header known when the block starts
+func (t *blockInfoTree) setHeader(parentHash common.Hash, coinbase common.Address, number, timestamp uint64, ger, blockHashL1 common.Hash) {
+	t.setHeaderParam(blockHeaderParamBlockHash, parentHash.Big())
+	t.setHeaderParam(blockHeaderParamCoinbase, new(big.Int).SetBytes(coinbase.Bytes()))
+	t.setHeaderParam(blockHeaderParamNumber, new(big.Int).SetUint64(number))
+	t.setHeaderParam(blockHeaderParamGasLimit, new(big.Int).SetUint64(blockInfoGasLimit))
+	t.setHeaderParam(blockHeaderParamTimestamp, new(big.Int).SetUint64(timestamp))
+	t.setHeaderParam(blockHeaderParamGER, ger.Big())
+	t.setHeaderParam(blockHeaderParamBlockHashL1, blockHashL1.Big())
+}
+
+// setGasUsed sets the gas used by the block once all its txs are executed
+func (t *blockInfoTree) setGasUsed(gasUsed uint64) {
+	t.setHeaderParam(blockHeaderParamGasUsed, new(big.Int).SetUint64(gasUsed))
+}
+
+// addTx adds a tx of the block. The logs are indexed from firstLogIndex, their index in the block
+func (t *blockInfoTree) addTx(txIndex uint64, l2TxHash common.Hash, status uint64, cumulativeGasUsed uint64, logs []*types.Log, firstLogIndex uint64, effectivePercentage uint8) {
+	index := new(big.Int).SetUint64(txIndex)
+	key, err := merkletree.KeyTxHash(index)
+	t.set(key, err, l2TxHash.Big())
+	key, err = merkletree.KeyTxStatus(index)
+	t.set(key, err, new(big.Int).SetUint64(status))
+	key, err = merkletree.KeyTxCumulativeGasUsed(index)
+	t.set(key, err, new(big.Int).SetUint64(cumulativeGasUsed))
+	for i, log := range logs {
+		hash, err := hashLog(log)
+		if err != nil {
+			t.set(nil, err, nil)
+			return
+		}
+		key, err = merkletree.KeyTxLogs(index, new(big.Int).SetUint64(firstLogIndex+uint64(i)))
+		t.set(key, err, hash.Big())
+	}
+	key, err = merkletree.KeyTxEffectivePercentage(index)
+	t.set(key, err, new(big.Int).SetUint64(uint64(effectivePercentage)))
+}
+
+// getRoot returns the root of the tree, or the first error found building it
+func (t *blockInfoTree) getRoot() (common.Hash, error) {
+	return common.BytesToHash(t.root), t.err
+}
+
+// hashLog returns the linear poseidon hash of the data of a log followed by its topics
+func hashLog(log *types.Log) (common.Hash, error) {
+	data := append([]byte{}, log.Data...)
+	for _, topic := range log.Topics {
+		data = append(data, topic.Bytes()...)
+	}
+	hash, err := merkletree.HashContractBytecode(data)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.HexToHash(merkletree.H4ToString(hash)), nil
+}
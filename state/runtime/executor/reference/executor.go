@@ -0,0 +1,115 @@
+// Package reference implements the executor service in process, executing the batches with the
+// fakevm on top of a state tree. It's meant for tests and light deployments that can't run the
+// prover: the accounts, storage and block info root are updated like the prover does for the
+// supported opcodes, but the ZK counters are estimations and the batches are never proven.
+package reference
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree/hashdb"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	// feHexLength is the length of a field element of a node of the db field of the requests
+	feHexLength = 16
+	// nodeHexLength is the length of the 12 field elements of a node of the db field of the requests
+	nodeHexLength = 12 * feHexLength
+)
+
+// Executor is an in process implementation of executor.ExecutorServiceClient. Only the batches
+// of the etrog fork and later ones are supported, using ProcessBatchV2
+type Executor struct {
+	db   hashdb.HashDBServiceClient
+	tree *merkletree.StateTree
+}
+
+// NewExecutor creates an executor that reads and writes the state in the given HashDB. The
+// state tree of the node must use the same HashDB, usually a merkletree.MemoryHashDB
+func NewExecutor(db hashdb.HashDBServiceClient) *Executor {
+	return &Executor{db: db, tree: merkletree.NewStateTree(db)}
+}
+
+// ProcessBatch is not supported, the batches before the etrog fork can't be executed
+func (e *Executor) ProcessBatch(ctx context.Context, in *executor.ProcessBatchRequest, opts ...grpc.CallOption) (*executor.ProcessBatchResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "ProcessBatch is not supported by the reference executor")
+}
+
+// ProcessBatchV2 executes a batch of the etrog fork. The nodes and bytecodes of the db and
+// contracts_bytecode fields of the request are loaded in the HashDB before executing it
+func (e *Executor) ProcessBatchV2(ctx context.Context, in *executor.ProcessBatchRequestV2, opts ...grpc.CallOption) (*executor.ProcessBatchResponseV2, error) {
+	err := e.loadDB(ctx, in.Db, in.ContractsBytecode)
+	if err != nil {
+		return nil, err
+	}
+	return newBatchProcessor(ctx, e.tree, in).process()
+}
+
+// loadDB loads in the HashDB the nodes, with their 12 field elements concatenated as 16 digits
+// hex strings, and the hex encoded bytecodes of a request
+func (e *Executor) loadDB(ctx context.Context, db map[string]string, contractsBytecode map[string]string) error {
+	if len(db) > 0 {
+		request := &hashdb.LoadDBRequest{InputDb: make(map[string]*hashdb.FeList, len(db))}
+		for key, value := range db {
+			if len(value) != nodeHexLength {
+				return fmt.Errorf("node %s has length %d, expected %d", key, len(value), nodeHexLength)
+			}
+			fe := make([]uint64, 0, nodeHexLength/feHexLength)
+			for i := 0; i < len(value); i += feHexLength {
+				v, err := strconv.ParseUint(value[i:i+feHexLength], hex.Base, 64) //nolint:gomnd
+				if err != nil {
+					return fmt.Errorf("node %s: %w", key, err)
+				}
+				fe = append(fe, v)
+			}
+			request.InputDb[key] = &hashdb.FeList{Fe: fe}
+		}
+		if _, err := e.db.LoadDB(ctx, request); err != nil {
+			return err
+		}
+	}
+	if len(contractsBytecode) > 0 {
+		request := &hashdb.LoadProgramDBRequest{InputProgramDb: make(map[string][]byte, len(contractsBytecode))}
+		for key, value := range contractsBytecode {
+			code, err := hex.DecodeHex(value)
+			if err != nil {
+				return fmt.Errorf("bytecode %s: %w", key, err)
+			}
+			request.InputProgramDb[key] = code
+		}
+		if _, err := e.db.LoadProgramDB(ctx, request); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProcessBatchV3 is not supported
+func (e *Executor) ProcessBatchV3(ctx context.Context, in *executor.ProcessBatchRequestV3, opts ...grpc.CallOption) (*executor.ProcessBatchResponseV3, error) {
+	return nil, status.Error(codes.Unimplemented, "ProcessBatchV3 is not supported by the reference executor")
+}
+
+// ProcessBlobInnerV3 is not supported
+func (e *Executor) ProcessBlobInnerV3(ctx context.Context, in *executor.ProcessBlobInnerRequestV3, opts ...grpc.CallOption) (*executor.ProcessBlobInnerResponseV3, error) {
+	return nil, status.Error(codes.Unimplemented, "ProcessBlobInnerV3 is not supported by the reference executor")
+}
+
+// ProcessStatelessBatchV2 is not supported
+func (e *Executor) ProcessStatelessBatchV2(ctx context.Context, in *executor.ProcessStatelessBatchRequestV2, opts ...grpc.CallOption) (*executor.ProcessBatchResponseV2, error) {
+	return nil, status.Error(codes.Unimplemented, "ProcessStatelessBatchV2 is not supported by the reference executor")
+}
+
+// GetFlushStatus returns an empty status, the state is written to the HashDB as soon as a batch
+// is executed
+func (e *Executor) GetFlushStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*executor.GetFlushStatusResponse, error) {
+	return &executor.GetFlushStatusResponse{}, nil
+}
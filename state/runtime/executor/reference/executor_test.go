@@ -0,0 +1,181 @@
+package reference
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/0xPolygonHermez/zkevm-node/test/vectors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testsFolder = "../../../../test/vectors/src/etrog/"
+
+func TestProcessBatchV2Vectors(t *testing.T) {
+	ctx := context.Background()
+	files, err := os.ReadDir(testsFolder)
+	require.NoError(t, err)
+
+	for _, file := range files {
+		testCases, err := vectors.LoadStateTransitionTestCasesEtrog(testsFolder + file.Name())
+		require.NoError(t, err)
+
+		for i, testCase := range testCases {
+			t.Run(fmt.Sprintf("%s %d %s", file.Name(), i, testCase.Description), func(t *testing.T) {
+				db := merkletree.NewMemoryHashDB()
+				tree := merkletree.NewStateTree(db)
+				oldRoot := setGenesis(t, tree, testCase.Genesis)
+				require.Equal(t, testCase.ExpectedOldStateRoot, common.BytesToHash(oldRoot).String())
+
+				timestampLimit, ok := new(big.Int).SetString(testCase.TimestampLimit, 10)
+				require.True(t, ok)
+				response, err := NewExecutor(db).ProcessBatchV2(ctx, &executor.ProcessBatchRequestV2{
+					OldStateRoot:         oldRoot,
+					OldAccInputHash:      common.HexToHash(testCase.OldAccInputHash).Bytes(),
+					OldBatchNum:          uint64(i),
+					ChainId:              1000,
+					ForkId:               testCase.ForkID,
+					BatchL2Data:          common.FromHex(testCase.BatchL2Data),
+					L1InfoRoot:           common.HexToHash(testCase.L1InfoRoot).Bytes(),
+					TimestampLimit:       timestampLimit.Uint64(),
+					Coinbase:             testCase.SequencerAddress,
+					SkipVerifyL1InfoRoot: 1,
+				})
+				require.NoError(t, err)
+				require.Equal(t, executor.ExecutorError_EXECUTOR_ERROR_NO_ERROR, response.Error)
+				newRoot := response.NewStateRoot
+
+				for address, leaf := range testCase.ExpectedNewLeafs {
+					balance, err := tree.GetBalance(ctx, common.HexToAddress(address), newRoot)
+					require.NoError(t, err)
+					assert.Equal(t, leaf.Balance.String(), balance.String(), "balance of %s", address)
+					nonce, err := tree.GetNonce(ctx, common.HexToAddress(address), newRoot)
+					require.NoError(t, err)
+					assert.Equal(t, leaf.Nonce, nonce.String(), "nonce of %s", address)
+					for position, value := range leaf.Storage {
+						stored, err := tree.GetStorageAt(ctx, common.HexToAddress(address), common.HexToHash(position).Big(), newRoot)
+						require.NoError(t, err)
+						assert.Equal(t, common.HexToHash(value).Big().String(), stored.String(), "storage %s of %s", position, address)
+					}
+				}
+
+				assert.Equal(t, testCase.ExpectedNewStateRoot, common.BytesToHash(newRoot).String())
+			})
+		}
+	}
+}
+
+func TestProcessBatchV2InvalidBatch(t *testing.T) {
+	db := merkletree.NewMemoryHashDB()
+	oldRoot := setGenesis(t, merkletree.NewStateTree(db), nil)
+
+	response, err := NewExecutor(db).ProcessBatchV2(context.Background(), &executor.ProcessBatchRequestV2{
+		OldStateRoot:   oldRoot,
+		ChainId:        1000,
+		ForkId:         state.FORKID_ETROG,
+		BatchL2Data:    []byte{0x0b, 0x01},
+		TimestampLimit: 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), response.InvalidBatch)
+	assert.Equal(t, executor.RomError_ROM_ERROR_INVALID_RLP, response.ErrorRom)
+	assert.Equal(t, oldRoot, response.NewStateRoot)
+	assert.Empty(t, response.BlockResponses)
+}
+
+func TestProcessBatchV2UnsupportedForkID(t *testing.T) {
+	response, err := NewExecutor(merkletree.NewMemoryHashDB()).ProcessBatchV2(context.Background(), &executor.ProcessBatchRequestV2{
+		ForkId: state.FORKID_DRAGONFRUIT,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, executor.ExecutorError_EXECUTOR_ERROR_UNSUPPORTED_FORK_ID, response.Error)
+}
+
+func TestProcessBatchV2Create(t *testing.T) {
+	ctx := context.Background()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	db := merkletree.NewMemoryHashDB()
+	tree := merkletree.NewStateTree(db)
+	oldRoot, _, err := tree.SetBalance(ctx, sender, big.NewInt(1e18), common.Hash{}.Bytes(), "")
+	require.NoError(t, err)
+
+	// SSTORE(0, 42) and LOG0 with empty data, deploying a contract without code
+	initCode := common.FromHex("0x602a60005560006000a000")
+	tx, err := types.SignTx(types.NewTx(&types.LegacyTx{Gas: 100000, GasPrice: big.NewInt(1), Data: initCode}), types.NewEIP155Signer(big.NewInt(1000)), key)
+	require.NoError(t, err)
+	batchL2Data, err := state.EncodeBatchV2(&state.BatchRawV2{Blocks: []state.L2BlockRaw{{
+		ChangeL2BlockHeader: state.ChangeL2BlockHeader{DeltaTimestamp: 1},
+		Transactions:        []state.L2TxRaw{{Tx: *tx, EfficiencyPercentage: state.MaxEffectivePercentage}},
+	}}})
+	require.NoError(t, err)
+
+	response, err := NewExecutor(db).ProcessBatchV2(ctx, &executor.ProcessBatchRequestV2{
+		OldStateRoot:   oldRoot,
+		ChainId:        1000,
+		ForkId:         state.FORKID_ETROG,
+		BatchL2Data:    batchL2Data,
+		TimestampLimit: 1,
+		Coinbase:       common.HexToAddress("0x1").String(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, executor.RomError_ROM_ERROR_NO_ERROR, response.ErrorRom)
+	require.Len(t, response.BlockResponses, 1)
+	require.Len(t, response.BlockResponses[0].Responses, 1)
+	txResponse := response.BlockResponses[0].Responses[0]
+	assert.Equal(t, executor.RomError_ROM_ERROR_NO_ERROR, txResponse.Error)
+	assert.Equal(t, uint32(1), txResponse.Status)
+	assert.Len(t, txResponse.Logs, 1)
+	assert.Equal(t, response.GasUsed, txResponse.GasUsed)
+
+	contract := crypto.CreateAddress(sender, 0)
+	assert.Equal(t, contract.String(), txResponse.CreateAddress)
+	value, err := tree.GetStorageAt(ctx, contract, big.NewInt(0), response.NewStateRoot)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), value.Int64())
+	nonce, err := tree.GetNonce(ctx, sender, response.NewStateRoot)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), nonce.Uint64())
+	fees, err := tree.GetBalance(ctx, common.HexToAddress("0x1"), response.NewStateRoot)
+	require.NoError(t, err)
+	assert.Equal(t, txResponse.GasUsed, fees.Uint64())
+	assert.NotZero(t, response.CntSteps)
+}
+
+func setGenesis(t *testing.T, tree *merkletree.StateTree, genesis []vectors.GenesisEntityEtrog) []byte {
+	ctx := context.Background()
+	root := common.Hash{}.Bytes()
+	var err error
+	for _, entity := range genesis {
+		address := common.HexToAddress(entity.Address)
+		if entity.Balance.String() != "0" {
+			root, _, err = tree.SetBalance(ctx, address, &entity.Balance.Int, root, "")
+			require.NoError(t, err)
+		}
+		if entity.Nonce != "" && entity.Nonce != "0" {
+			nonce, ok := new(big.Int).SetString(entity.Nonce, 10)
+			require.True(t, ok)
+			root, _, err = tree.SetNonce(ctx, address, nonce, root, "")
+			require.NoError(t, err)
+		}
+		if entity.IsSmartContract && entity.Bytecode != nil && *entity.Bytecode != "0x" {
+			root, _, err = tree.SetCode(ctx, address, common.FromHex(*entity.Bytecode), root, "")
+			require.NoError(t, err)
+		}
+		for position, value := range entity.Storage {
+			root, _, err = tree.SetStorageAt(ctx, address, common.HexToHash(position).Big(), common.HexToHash(value).Big(), root, "")
+			require.NoError(t, err)
+		}
+	}
+	return root
+}
@@ -0,0 +1,411 @@
+package reference
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// emptyCodeHash is the keccak hash of an empty bytecode
+var emptyCodeHash = crypto.Keccak256Hash(nil)
+
+// account is the state of an account, along with its state in the last committed root
+type account struct {
+	balance *big.Int
+	nonce   uint64
+	code    []byte
+
+	committedBalance *big.Int
+	committedNonce   uint64
+	codeChanged      bool
+
+	storage          map[common.Hash]common.Hash
+	committedStorage map[common.Hash]common.Hash
+}
+
+// stateDB implements fakevm.FakeDB on top of the state tree. The changes are kept in memory
+// until commit writes them to the tree, and can be reverted to a snapshot with a journal of
+// undo functions like the go-ethereum state does
+type stateDB struct {
+	ctx  context.Context
+	tree *merkletree.StateTree
+	root common.Hash
+
+	accounts map[common.Address]*account
+	journal  []func()
+	refund   uint64
+	logs     []*types.Log
+
+	accessAddresses map[common.Address]bool
+	accessSlots     map[common.Address]map[common.Hash]bool
+	transient       map[common.Address]map[common.Hash]common.Hash
+
+	// err is the first error reading the tree, the state is invalid once it's set
+	err error
+}
+
+func newStateDB(ctx context.Context, tree *merkletree.StateTree, root common.Hash) *stateDB {
+	s := &stateDB{
+		ctx:      ctx,
+		tree:     tree,
+		root:     root,
+		accounts: map[common.Address]*account{},
+	}
+	s.resetTx()
+	return s
+}
+
+// resetTx clears the state that only lives during a tx
+func (s *stateDB) resetTx() {
+	s.journal = nil
+	s.refund = 0
+	s.logs = nil
+	s.accessAddresses = map[common.Address]bool{}
+	s.accessSlots = map[common.Address]map[common.Hash]bool{}
+	s.transient = map[common.Address]map[common.Hash]common.Hash{}
+}
+
+func (s *stateDB) setErr(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// getAccount returns the account, reading it from the tree the first time it's accessed
+func (s *stateDB) getAccount(address common.Address) *account {
+	if a, found := s.accounts[address]; found {
+		return a
+	}
+	a := &account{
+		balance:          big.NewInt(0),
+		committedBalance: big.NewInt(0),
+		storage:          map[common.Hash]common.Hash{},
+		committedStorage: map[common.Hash]common.Hash{},
+	}
+	balance, err := s.tree.GetBalance(s.ctx, address, s.root.Bytes())
+	if err != nil {
+		s.setErr(err)
+	} else {
+		a.balance.Set(balance)
+		a.committedBalance.Set(balance)
+	}
+	nonce, err := s.tree.GetNonce(s.ctx, address, s.root.Bytes())
+	if err != nil {
+		s.setErr(err)
+	} else {
+		a.nonce = nonce.Uint64()
+		a.committedNonce = a.nonce
+	}
+	code, err := s.tree.GetCode(s.ctx, address, s.root.Bytes())
+	if err != nil {
+		s.setErr(err)
+	} else {
+		a.code = code
+	}
+	s.accounts[address] = a
+	return a
+}
+
+// commit writes the changes to the tree and returns the new root
+func (s *stateDB) commit() (common.Hash, error) {
+	if s.err != nil {
+		return common.Hash{}, s.err
+	}
+	addresses := make([]common.Address, 0, len(s.accounts))
+	for address := range s.accounts {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool { return bytes.Compare(addresses[i][:], addresses[j][:]) < 0 })
+
+	root := s.root.Bytes()
+	var err error
+	for _, address := range addresses {
+		a := s.accounts[address]
+		if a.balance.Cmp(a.committedBalance) != 0 {
+			if root, _, err = s.tree.SetBalance(s.ctx, address, a.balance, root, ""); err != nil {
+				return common.Hash{}, err
+			}
+			a.committedBalance = new(big.Int).Set(a.balance)
+		}
+		if a.nonce != a.committedNonce {
+			if root, _, err = s.tree.SetNonce(s.ctx, address, new(big.Int).SetUint64(a.nonce), root, ""); err != nil {
+				return common.Hash{}, err
+			}
+			a.committedNonce = a.nonce
+		}
+		if a.codeChanged {
+			if root, _, err = s.tree.SetCode(s.ctx, address, a.code, root, ""); err != nil {
+				return common.Hash{}, err
+			}
+			a.codeChanged = false
+		}
+		slots := make([]common.Hash, 0, len(a.storage))
+		for slot, value := range a.storage {
+			if value != a.committedStorage[slot] {
+				slots = append(slots, slot)
+			}
+		}
+		sort.Slice(slots, func(i, j int) bool { return bytes.Compare(slots[i][:], slots[j][:]) < 0 })
+		for _, slot := range slots {
+			if root, _, err = s.tree.SetStorageAt(s.ctx, address, slot.Big(), a.storage[slot].Big(), root, ""); err != nil {
+				return common.Hash{}, err
+			}
+			a.committedStorage[slot] = a.storage[slot]
+		}
+	}
+	s.root = common.BytesToHash(root)
+	s.resetTx()
+	return s.root, nil
+}
+
+// SetStateRoot is not supported, the root only changes on commit
+func (s *stateDB) SetStateRoot(stateRoot []byte) {}
+
+// CreateAccount does nothing, the accounts exist as soon as any of their leaves is set
+func (s *stateDB) CreateAccount(common.Address) {}
+
+// SubBalance subtracts amount from the balance of the account
+func (s *stateDB) SubBalance(address common.Address, amount *big.Int) {
+	s.setBalance(address, new(big.Int).Sub(s.GetBalance(address), amount))
+}
+
+// AddBalance adds amount to the balance of the account
+func (s *stateDB) AddBalance(address common.Address, amount *big.Int) {
+	s.setBalance(address, new(big.Int).Add(s.GetBalance(address), amount))
+}
+
+func (s *stateDB) setBalance(address common.Address, balance *big.Int) {
+	a := s.getAccount(address)
+	previous := a.balance
+	s.journal = append(s.journal, func() { a.balance = previous })
+	a.balance = balance
+}
+
+// GetBalance returns the balance of the account
+func (s *stateDB) GetBalance(address common.Address) *big.Int {
+	return new(big.Int).Set(s.getAccount(address).balance)
+}
+
+// GetNonce returns the nonce of the account
+func (s *stateDB) GetNonce(address common.Address) uint64 {
+	return s.getAccount(address).nonce
+}
+
+// SetNonce sets the nonce of the account
+func (s *stateDB) SetNonce(address common.Address, nonce uint64) {
+	a := s.getAccount(address)
+	previous := a.nonce
+	s.journal = append(s.journal, func() { a.nonce = previous })
+	a.nonce = nonce
+}
+
+// GetCodeHash returns the keccak hash of the code of the account
+func (s *stateDB) GetCodeHash(address common.Address) common.Hash {
+	a := s.getAccount(address)
+	if len(a.code) == 0 {
+		if s.Empty(address) {
+			return common.Hash{}
+		}
+		return emptyCodeHash
+	}
+	return crypto.Keccak256Hash(a.code)
+}
+
+// GetCode returns the code of the account
+func (s *stateDB) GetCode(address common.Address) []byte {
+	return s.getAccount(address).code
+}
+
+// SetCode sets the code of the account
+func (s *stateDB) SetCode(address common.Address, code []byte) {
+	a := s.getAccount(address)
+	previousCode, previousChanged := a.code, a.codeChanged
+	s.journal = append(s.journal, func() { a.code, a.codeChanged = previousCode, previousChanged })
+	a.code, a.codeChanged = code, true
+}
+
+// GetCodeSize returns the size of the code of the account
+func (s *stateDB) GetCodeSize(address common.Address) int {
+	return len(s.getAccount(address).code)
+}
+
+// AddRefund adds gas to the refund counter
+func (s *stateDB) AddRefund(gas uint64) {
+	previous := s.refund
+	s.journal = append(s.journal, func() { s.refund = previous })
+	s.refund += gas
+}
+
+// SubRefund removes gas from the refund counter
+func (s *stateDB) SubRefund(gas uint64) {
+	previous := s.refund
+	s.journal = append(s.journal, func() { s.refund = previous })
+	if gas > s.refund {
+		s.refund = 0
+		return
+	}
+	s.refund -= gas
+}
+
+// GetRefund returns the refund counter
+func (s *stateDB) GetRefund() uint64 {
+	return s.refund
+}
+
+// GetCommittedState returns the value of the storage slot at the beginning of the tx
+func (s *stateDB) GetCommittedState(address common.Address, slot common.Hash) common.Hash {
+	a := s.getAccount(address)
+	if value, found := a.committedStorage[slot]; found {
+		return value
+	}
+	value, err := s.tree.GetStorageAt(s.ctx, address, slot.Big(), s.root.Bytes())
+	if err != nil {
+		s.setErr(err)
+		return common.Hash{}
+	}
+	a.committedStorage[slot] = common.BigToHash(value)
+	return a.committedStorage[slot]
+}
+
+// GetState returns the current value of the storage slot
+func (s *stateDB) GetState(address common.Address, slot common.Hash) common.Hash {
+	a := s.getAccount(address)
+	if value, found := a.storage[slot]; found {
+		return value
+	}
+	return s.GetCommittedState(address, slot)
+}
+
+// SetState sets the value of the storage slot
+func (s *stateDB) SetState(address common.Address, slot common.Hash, value common.Hash) {
+	a := s.getAccount(address)
+	previous, found := a.storage[slot]
+	s.journal = append(s.journal, func() {
+		if found {
+			a.storage[slot] = previous
+		} else {
+			delete(a.storage, slot)
+		}
+	})
+	a.storage[slot] = value
+}
+
+// GetTransientState returns the value of the transient storage slot
+func (s *stateDB) GetTransientState(address common.Address, slot common.Hash) common.Hash {
+	return s.transient[address][slot]
+}
+
+// SetTransientState sets the value of the transient storage slot
+func (s *stateDB) SetTransientState(address common.Address, slot, value common.Hash) {
+	if s.transient[address] == nil {
+		s.transient[address] = map[common.Hash]common.Hash{}
+	}
+	previous := s.transient[address][slot]
+	s.journal = append(s.journal, func() { s.transient[address][slot] = previous })
+	s.transient[address][slot] = value
+}
+
+// Suicide behaves like SENDALL, as the zkEVM does: the opcode has already moved the balance to
+// the beneficiary, so the balance is cleared but the code and the storage are kept
+func (s *stateDB) Suicide(address common.Address) bool {
+	s.setBalance(address, big.NewInt(0))
+	return true
+}
+
+// HasSuicided returns false, the accounts are never destroyed
+func (s *stateDB) HasSuicided(common.Address) bool {
+	return false
+}
+
+// Exist reports whether the account has a balance, a nonce or code
+func (s *stateDB) Exist(address common.Address) bool {
+	return !s.Empty(address)
+}
+
+// Empty reports whether the account has no balance, nonce nor code
+func (s *stateDB) Empty(address common.Address) bool {
+	a := s.getAccount(address)
+	return a.balance.Sign() == 0 && a.nonce == 0 && len(a.code) == 0
+}
+
+// AddressInAccessList reports whether the address is in the access list
+func (s *stateDB) AddressInAccessList(address common.Address) bool {
+	return s.accessAddresses[address]
+}
+
+// SlotInAccessList reports whether the address and the slot are in the access list
+func (s *stateDB) SlotInAccessList(address common.Address, slot common.Hash) (addressOk bool, slotOk bool) {
+	return s.accessAddresses[address], s.accessSlots[address][slot]
+}
+
+// AddAddressToAccessList adds the address to the access list
+func (s *stateDB) AddAddressToAccessList(address common.Address) {
+	if s.accessAddresses[address] {
+		return
+	}
+	s.journal = append(s.journal, func() { delete(s.accessAddresses, address) })
+	s.accessAddresses[address] = true
+}
+
+// AddSlotToAccessList adds the address and the slot to the access list
+func (s *stateDB) AddSlotToAccessList(address common.Address, slot common.Hash) {
+	s.AddAddressToAccessList(address)
+	if s.accessSlots[address] == nil {
+		s.accessSlots[address] = map[common.Hash]bool{}
+	}
+	if s.accessSlots[address][slot] {
+		return
+	}
+	s.journal = append(s.journal, func() { delete(s.accessSlots[address], slot) })
+	s.accessSlots[address][slot] = true
+}
+
+// Prepare sets up the access list of a tx
+func (s *stateDB) Prepare(rules params.Rules, sender, coinbase common.Address, dest *common.Address, precompiles []common.Address, txAccesses types.AccessList) {
+	if !rules.IsBerlin {
+		return
+	}
+	s.AddAddressToAccessList(sender)
+	if dest != nil {
+		s.AddAddressToAccessList(*dest)
+	}
+	for _, address := range precompiles {
+		s.AddAddressToAccessList(address)
+	}
+	for _, access := range txAccesses {
+		s.AddAddressToAccessList(access.Address)
+		for _, slot := range access.StorageKeys {
+			s.AddSlotToAccessList(access.Address, slot)
+		}
+	}
+}
+
+// RevertToSnapshot undoes the changes made after the snapshot was taken
+func (s *stateDB) RevertToSnapshot(snapshot int) {
+	for i := len(s.journal) - 1; i >= snapshot; i-- {
+		s.journal[i]()
+	}
+	s.journal = s.journal[:snapshot]
+}
+
+// Snapshot returns an identifier of the current state
+func (s *stateDB) Snapshot() int {
+	return len(s.journal)
+}
+
+// AddLog adds a log to the current tx
+func (s *stateDB) AddLog(log *types.Log) {
+	logs := s.logs
+	s.journal = append(s.journal, func() { s.logs = logs })
+	s.logs = append(s.logs, log)
+}
+
+// AddPreimage does nothing, the preimages are not recorded
+func (s *stateDB) AddPreimage(common.Hash, []byte) {}
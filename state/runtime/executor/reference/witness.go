@@ -0,0 +1,18 @@
+package reference
+
+import (
+	"context"
+
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+)
+
+// ExecuteBatchWitness executes the batch of a witness with a reference executor over an empty
+// in memory HashDB seeded only with the nodes and bytecodes of the witness, so the execution
+// fails if the witness doesn't contain all the state read or written by the batch. The nodes
+// and bytecodes are checked against their hashes, so the new state root of the response proves
+// the state transition from the old state root of the witness
+func ExecuteBatchWitness(ctx context.Context, witness *state.BatchWitness) (*executor.ProcessBatchResponseV2, error) {
+	return state.ExecuteBatchWitness(ctx, NewExecutor(merkletree.NewMemoryHashDB()), witness)
+}
@@ -0,0 +1,105 @@
+package reference
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree/hashdb"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// keysRecorder records the keys read and written in the HashDB
+type keysRecorder struct {
+	*merkletree.MemoryHashDB
+	keys []merkletree.Key
+}
+
+func (r *keysRecorder) Get(ctx context.Context, in *hashdb.GetRequest, opts ...grpc.CallOption) (*hashdb.GetResponse, error) {
+	r.record(in.Key)
+	return r.MemoryHashDB.Get(ctx, in, opts...)
+}
+
+func (r *keysRecorder) Set(ctx context.Context, in *hashdb.SetRequest, opts ...grpc.CallOption) (*hashdb.SetResponse, error) {
+	r.record(in.Key)
+	return r.MemoryHashDB.Set(ctx, in, opts...)
+}
+
+func (r *keysRecorder) record(fea *hashdb.Fea) {
+	key := merkletree.Key{}
+	copy(key[:], common.FromHex(merkletree.H4ToString([]uint64{fea.Fe0, fea.Fe1, fea.Fe2, fea.Fe3})))
+	r.keys = append(r.keys, key)
+}
+
+func TestExecuteBatchWitness(t *testing.T) {
+	ctx := context.Background()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	db := &keysRecorder{MemoryHashDB: merkletree.NewMemoryHashDB()}
+	tree := merkletree.NewStateTree(db)
+	oldRoot, _, err := tree.SetBalance(ctx, sender, big.NewInt(1e18), common.Hash{}.Bytes(), "")
+	require.NoError(t, err)
+	oldRoot, _, err = tree.SetBalance(ctx, common.HexToAddress("0x2"), big.NewInt(1), oldRoot, "")
+	require.NoError(t, err)
+
+	tx, err := types.SignTx(types.NewTx(&types.LegacyTx{To: &common.Address{0x3}, Gas: 21000, GasPrice: big.NewInt(1), Value: big.NewInt(10)}), types.NewEIP155Signer(big.NewInt(1000)), key)
+	require.NoError(t, err)
+	batchL2Data, err := state.EncodeBatchV2(&state.BatchRawV2{Blocks: []state.L2BlockRaw{{
+		ChangeL2BlockHeader: state.ChangeL2BlockHeader{DeltaTimestamp: 1},
+		Transactions:        []state.L2TxRaw{{Tx: *tx, EfficiencyPercentage: state.MaxEffectivePercentage}},
+	}}})
+	require.NoError(t, err)
+	witness := &state.BatchWitness{
+		BatchNumber:    1,
+		ChainID:        1000,
+		ForkID:         state.FORKID_ETROG,
+		OldStateRoot:   common.BytesToHash(oldRoot),
+		Coinbase:       common.HexToAddress("0x1"),
+		BatchL2Data:    batchL2Data,
+		TimestampLimit: 1,
+	}
+
+	// the batch is executed with the full state to get the keys it uses
+	db.keys = nil
+	response, err := NewExecutor(db).ProcessBatchV2(ctx, &executor.ProcessBatchRequestV2{
+		OldStateRoot:   oldRoot,
+		ChainId:        witness.ChainID,
+		ForkId:         witness.ForkID,
+		BatchL2Data:    batchL2Data,
+		TimestampLimit: witness.TimestampLimit,
+		Coinbase:       witness.Coinbase.String(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, executor.RomError_ROM_ERROR_NO_ERROR, response.ErrorRom)
+	witness.NewStateRoot = common.BytesToHash(response.NewStateRoot)
+	witness.Db, err = tree.ReadTree(ctx, witness.OldStateRoot, db.keys)
+	require.NoError(t, err)
+
+	// the witness is enough to get the same state root
+	res, err := ExecuteBatchWitness(ctx, witness)
+	require.NoError(t, err)
+	assert.Equal(t, witness.NewStateRoot, common.BytesToHash(res.NewStateRoot))
+
+	// the execution fails when a node is missing
+	root := strings.TrimPrefix(witness.OldStateRoot.String(), "0x")
+	node, found := witness.Db[root]
+	require.True(t, found)
+	delete(witness.Db, root)
+	_, err = ExecuteBatchWitness(ctx, witness)
+	assert.ErrorContains(t, err, "not found")
+
+	// or doesn't match its hash
+	witness.Db[root] = "ffffffff00000000" + node[16:]
+	_, err = ExecuteBatchWitness(ctx, witness)
+	assert.ErrorContains(t, err, "doesn't match its hash")
+}
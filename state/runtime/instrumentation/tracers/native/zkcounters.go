@@ -0,0 +1,459 @@
+package native
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"sort"
+	"sync/atomic"
+
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/fakevm"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/instrumentation/tracers"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("zkCounterTracer", NewZKCounterTracer, false)
+}
+
+// ZKCounters are the ZK counters attributed by the zkCounterTracer. The counters attributed to the
+// intrinsic cost, the call frames and the opcodes are estimations, they are not reported by the executor
+type ZKCounters struct {
+	Steps            uint64 `json:"steps"`
+	KeccakHashes     uint64 `json:"keccakHashes"`
+	PoseidonHashes   uint64 `json:"poseidonHashes"`
+	PoseidonPaddings uint64 `json:"poseidonPaddings"`
+	MemAligns        uint64 `json:"memAligns"`
+	Arithmetics      uint64 `json:"arithmetics"`
+	Binaries         uint64 `json:"binaries"`
+	Sha256Hashes     uint64 `json:"sha256Hashes"`
+}
+
+func (c *ZKCounters) add(other ZKCounters) {
+	c.Steps += other.Steps
+	c.KeccakHashes += other.KeccakHashes
+	c.PoseidonHashes += other.PoseidonHashes
+	c.PoseidonPaddings += other.PoseidonPaddings
+	c.MemAligns += other.MemAligns
+	c.Arithmetics += other.Arithmetics
+	c.Binaries += other.Binaries
+	c.Sha256Hashes += other.Sha256Hashes
+}
+
+// scale distributes the measured counters proportionally to the share of c in the estimated counters
+func (c ZKCounters) scale(measured, estimated ZKCounters) ZKCounters {
+	f := func(v, m, e uint64) uint64 {
+		if e == 0 {
+			return v
+		}
+		return uint64(math.Round(float64(v) * float64(m) / float64(e)))
+	}
+	return ZKCounters{
+		Steps:            f(c.Steps, measured.Steps, estimated.Steps),
+		KeccakHashes:     f(c.KeccakHashes, measured.KeccakHashes, estimated.KeccakHashes),
+		PoseidonHashes:   f(c.PoseidonHashes, measured.PoseidonHashes, estimated.PoseidonHashes),
+		PoseidonPaddings: f(c.PoseidonPaddings, measured.PoseidonPaddings, estimated.PoseidonPaddings),
+		MemAligns:        f(c.MemAligns, measured.MemAligns, estimated.MemAligns),
+		Arithmetics:      f(c.Arithmetics, measured.Arithmetics, estimated.Arithmetics),
+		Binaries:         f(c.Binaries, measured.Binaries, estimated.Binaries),
+		Sha256Hashes:     f(c.Sha256Hashes, measured.Sha256Hashes, estimated.Sha256Hashes),
+	}
+}
+
+type zkCounterTracerConfig struct {
+	// Counters are the counters measured by the executor for the transaction. When set, the
+	// attributed counters are scaled so they add up to them
+	Counters *ZKCounters `json:"counters"`
+}
+
+type zkCounterFrame struct {
+	Type          string            `json:"type"`
+	From          common.Address    `json:"from"`
+	To            common.Address    `json:"to"`
+	Counters      ZKCounters        `json:"counters"`
+	TotalCounters ZKCounters        `json:"totalCounters"`
+	Calls         []*zkCounterFrame `json:"calls,omitempty"`
+}
+
+type zkCounterOpcode struct {
+	OpCode   string     `json:"opcode"`
+	Count    uint64     `json:"count"`
+	Counters ZKCounters `json:"counters"`
+}
+
+// zkCounterAttributionEstimated labels the counters attributed to the intrinsic cost, the call frames
+// and the opcodes as estimations. The executor trace steps (executor.TransactionStepV2) don't include
+// the counters consumed by each step, so they can't be measured
+const zkCounterAttributionEstimated = "estimated"
+
+type zkCounterResult struct {
+	// Estimated is true when the total counters were not measured by the executor
+	Estimated bool `json:"estimated"`
+	// Attribution labels how the counters of the intrinsic cost, the call frames and the opcodes
+	// were obtained, it's always zkCounterAttributionEstimated
+	Attribution string            `json:"attribution"`
+	Counters    ZKCounters        `json:"counters"`
+	Intrinsic   ZKCounters        `json:"intrinsic"`
+	Calls       *zkCounterFrame   `json:"calls"`
+	Opcodes     []zkCounterOpcode `json:"opcodes"`
+}
+
+// zkCounterTracer attributes the ZK counters consumed by a transaction to its call frames
+// and opcodes. The executor only reports the counters of the whole batch, its trace steps
+// don't include them, so the tracer estimates the cost of every step with a model of the ROM
+// and, if the measured counters are provided in the config, distributes them proportionally
+// to the estimations. The result labels the attribution as estimated.
+//
+// Example:
+//
+//	> debug.traceTransaction("0x...", {tracer: "zkCounterTracer"})
+//	{
+//	  "estimated": true,
+//	  "attribution": "estimated",
+//	  "counters": {"steps": 12030, "keccakHashes": 5, ...},
+//	  "intrinsic": {"steps": 2400, ...},
+//	  "calls": {"type": "CALL", "from": "0x...", "to": "0x...", "counters": {...}, "totalCounters": {...}, "calls": [...]},
+//	  "opcodes": [{"opcode": "SSTORE", "count": 2, "counters": {...}}, ...]
+//	}
+type zkCounterTracer struct {
+	noopTracer
+	config            zkCounterTracerConfig
+	intrinsic         ZKCounters
+	root              *zkCounterFrame
+	frames            []*zkCounterFrame
+	opcodes           map[fakevm.OpCode]*zkCounterOpcode
+	activePrecompiles []common.Address
+	interrupt         uint32 // Atomic flag to signal execution interruption
+	reason            error  // Textual reason for the interruption
+}
+
+// NewZKCounterTracer returns a native go tracer which attributes the ZK counters
+// of a tx to its call frames and opcodes, and implements vm.EVMLogger.
+func NewZKCounterTracer(ctx *tracers.Context, cfg json.RawMessage) (tracers.Tracer, error) {
+	var config zkCounterTracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	return &zkCounterTracer{
+		config:  config,
+		opcodes: make(map[fakevm.OpCode]*zkCounterOpcode),
+	}, nil
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *zkCounterTracer) CaptureStart(env *fakevm.FakeEVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	rules := env.ChainConfig().Rules(env.Context.BlockNumber, env.Context.Random != nil, env.Context.Time)
+	t.activePrecompiles = fakevm.ActivePrecompiles(rules)
+
+	typ := fakevm.CALL
+	if create {
+		typ = fakevm.CREATE
+	}
+	t.root = &zkCounterFrame{Type: typ.String(), From: from, To: to}
+	t.frames = []*zkCounterFrame{t.root}
+	t.intrinsic = intrinsicZKCounters(len(input))
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *zkCounterTracer) CaptureState(pc uint64, op fakevm.OpCode, gas, cost uint64, scope *fakevm.ScopeContext, rData []byte, depth int, err error) {
+	t.captureStep(op, scope)
+}
+
+// CaptureFault implements the EVMLogger interface to trace an execution fault.
+func (t *zkCounterTracer) CaptureFault(pc uint64, op fakevm.OpCode, gas, cost uint64, scope *fakevm.ScopeContext, depth int, err error) {
+	t.captureStep(op, scope)
+}
+
+func (t *zkCounterTracer) captureStep(op fakevm.OpCode, scope *fakevm.ScopeContext) {
+	if atomic.LoadUint32(&t.interrupt) > 0 || len(t.frames) == 0 {
+		return
+	}
+	counters := opcodeZKCounters(op, scope)
+	t.frames[len(t.frames)-1].Counters.add(counters)
+
+	stats, ok := t.opcodes[op]
+	if !ok {
+		stats = &zkCounterOpcode{OpCode: op.String()}
+		t.opcodes[op] = stats
+	}
+	stats.Count++
+	stats.Counters.add(counters)
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *zkCounterTracer) CaptureEnter(typ fakevm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if atomic.LoadUint32(&t.interrupt) > 0 || len(t.frames) == 0 {
+		return
+	}
+	frame := &zkCounterFrame{Type: typ.String(), From: from, To: to}
+	if t.isPrecompiled(to) {
+		frame.Counters = precompileZKCounters(to, len(input))
+	}
+	parent := t.frames[len(t.frames)-1]
+	parent.Calls = append(parent.Calls, frame)
+	t.frames = append(t.frames, frame)
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *zkCounterTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	// the root frame is only removed when the whole tx ends
+	if len(t.frames) > 1 {
+		t.frames = t.frames[:len(t.frames)-1]
+	}
+}
+
+// GetResult returns the json-encoded counters attributed to the call frames and
+// opcodes, and any error arising from the encoding or forceful termination (via `Stop`).
+func (t *zkCounterTracer) GetResult() (json.RawMessage, error) {
+	if t.root == nil {
+		return json.RawMessage(`{}`), t.reason
+	}
+
+	estimated := t.intrinsic
+	estimated.add(sumZKCounterFrame(t.root))
+
+	result := zkCounterResult{
+		Estimated:   t.config.Counters == nil,
+		Attribution: zkCounterAttributionEstimated,
+		Counters:    estimated,
+		Intrinsic:   t.intrinsic,
+		Calls:       t.root,
+		Opcodes:     make([]zkCounterOpcode, 0, len(t.opcodes)),
+	}
+	for _, stats := range t.opcodes {
+		result.Opcodes = append(result.Opcodes, *stats)
+	}
+
+	if t.config.Counters != nil {
+		measured := *t.config.Counters
+		result.Counters = measured
+		result.Intrinsic = t.intrinsic.scale(measured, estimated)
+		scaleZKCounterFrame(t.root, measured, estimated)
+		for i := range result.Opcodes {
+			result.Opcodes[i].Counters = result.Opcodes[i].Counters.scale(measured, estimated)
+		}
+	}
+
+	sort.Slice(result.Opcodes, func(i, j int) bool {
+		if result.Opcodes[i].Counters.Steps != result.Opcodes[j].Counters.Steps {
+			return result.Opcodes[i].Counters.Steps > result.Opcodes[j].Counters.Steps
+		}
+		return result.Opcodes[i].OpCode < result.Opcodes[j].OpCode
+	})
+
+	res, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *zkCounterTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+// isPrecompiled returns whether the addr is a precompile.
+func (t *zkCounterTracer) isPrecompiled(addr common.Address) bool {
+	for _, p := range t.activePrecompiles {
+		if p == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// sumZKCounterFrame sets the total counters of the frame and its sub calls and returns them
+func sumZKCounterFrame(frame *zkCounterFrame) ZKCounters {
+	total := frame.Counters
+	for _, call := range frame.Calls {
+		total.add(sumZKCounterFrame(call))
+	}
+	frame.TotalCounters = total
+	return total
+}
+
+func scaleZKCounterFrame(frame *zkCounterFrame, measured, estimated ZKCounters) {
+	frame.Counters = frame.Counters.scale(measured, estimated)
+	frame.TotalCounters = frame.TotalCounters.scale(measured, estimated)
+	for _, call := range frame.Calls {
+		scaleZKCounterFrame(call, measured, estimated)
+	}
+}
+
+// The costs below are a heuristic approximation of the counters consumed by the ROM to process
+// a transaction, they are not taken from the executor and can differ from the counters it
+// consumes for a given opcode. They are only meant to find out the parts of a transaction that
+// consume the most counters.
+const (
+	zkStepsPerOpcode        = 20
+	zkStepsPerWord          = 10
+	zkStepsPerStorageAccess = 400
+	zkStepsPerCall          = 800
+	zkStepsPerCreate        = 1500
+	zkStepsIntrinsic        = 2500
+
+	// zkPoseidonsPerTreeRead is the number of poseidon hashes needed to read a leaf of the state tree
+	zkPoseidonsPerTreeRead = 32
+	// zkPoseidonsPerTreeWrite is the number of poseidon hashes needed to update a leaf of the state tree
+	zkPoseidonsPerTreeWrite = 2 * zkPoseidonsPerTreeRead
+
+	// zkKeccakBytesPerHash is the number of bytes hashed by a keccak counter
+	zkKeccakBytesPerHash = 136
+	// zkSha256BytesPerHash is the number of bytes hashed by a sha256 counter
+	zkSha256BytesPerHash = 64
+	// zkPoseidonBytesPerPadding is the number of bytes of bytecode hashed by a poseidon padding
+	zkPoseidonBytesPerPadding = 56
+)
+
+func words(size uint64) uint64 {
+	return (size + 31) / 32
+}
+
+// stackSize returns the stack item at position n from the top as a size, saturated to 32 bits
+func stackSize(scope *fakevm.ScopeContext, n int) uint64 {
+	if scope == nil || scope.Stack == nil || len(scope.Stack.Data()) <= n {
+		return 0
+	}
+	v := scope.Stack.Back(n)
+	if !v.IsUint64() || v.Uint64() > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return v.Uint64()
+}
+
+// memoryCopyZKCounters are the counters needed to copy size bytes from or to the memory
+func memoryCopyZKCounters(size uint64) ZKCounters {
+	w := words(size)
+	return ZKCounters{
+		Steps:     zkStepsPerOpcode + zkStepsPerWord*w,
+		MemAligns: w + 1,
+		Binaries:  w,
+	}
+}
+
+// intrinsicZKCounters are the counters needed to decode the transaction, check its signature
+// and update the sender and coinbase accounts, before any opcode is executed
+func intrinsicZKCounters(inputSize int) ZKCounters {
+	size := uint64(inputSize)
+	return ZKCounters{
+		Steps:            zkStepsIntrinsic + zkStepsPerWord*words(size),
+		KeccakHashes:     (size+200)/zkKeccakBytesPerHash + 1,
+		PoseidonHashes:   4 * zkPoseidonsPerTreeWrite,
+		PoseidonPaddings: 1,
+		Arithmetics:      1100,
+		Binaries:         1000 + words(size),
+	}
+}
+
+// precompileZKCounters are the counters needed to run a precompiled contract
+func precompileZKCounters(addr common.Address, inputSize int) ZKCounters {
+	size := uint64(inputSize)
+	switch addr {
+	case common.BytesToAddress([]byte{1}): // ecrecover
+		return ZKCounters{Steps: 6400, Arithmetics: 1100, Binaries: 1000}
+	case common.BytesToAddress([]byte{2}): // sha256
+		return ZKCounters{Steps: 100 + zkStepsPerWord*words(size), Sha256Hashes: size/zkSha256BytesPerHash + 1, Binaries: words(size)}
+	case common.BytesToAddress([]byte{4}): // identity
+		return memoryCopyZKCounters(size)
+	case common.BytesToAddress([]byte{5}): // modexp
+		return ZKCounters{Steps: 5000 + 100*words(size), Arithmetics: 200 * words(size), Binaries: 100 * words(size)}
+	case common.BytesToAddress([]byte{6}): // ecAdd
+		return ZKCounters{Steps: 1000, Arithmetics: 50, Binaries: 50}
+	case common.BytesToAddress([]byte{7}): // ecMul
+		return ZKCounters{Steps: 30000, Arithmetics: 2000, Binaries: 1500}
+	case common.BytesToAddress([]byte{8}): // ecPairing
+		pairs := size / 192
+		return ZKCounters{Steps: 50000 + 150000*pairs, Arithmetics: 10000 + 50000*pairs, Binaries: 5000 + 20000*pairs}
+	default:
+		return ZKCounters{Steps: zkStepsPerCall}
+	}
+}
+
+// opcodeZKCounters are the counters needed to run an opcode
+func opcodeZKCounters(op fakevm.OpCode, scope *fakevm.ScopeContext) ZKCounters {
+	switch {
+	case op >= fakevm.PUSH0 && op <= fakevm.PUSH32, op >= fakevm.DUP1 && op <= fakevm.SWAP16:
+		return ZKCounters{Steps: zkStepsPerOpcode / 2}
+	case op >= fakevm.LOG0 && op <= fakevm.LOG4:
+		c := memoryCopyZKCounters(stackSize(scope, 1))
+		c.Steps += zkStepsPerOpcode * uint64(op-fakevm.LOG0+1)
+		return c
+	}
+
+	switch op {
+	case fakevm.ADD, fakevm.SUB, fakevm.LT, fakevm.GT, fakevm.SLT, fakevm.SGT, fakevm.EQ, fakevm.ISZERO,
+		fakevm.AND, fakevm.OR, fakevm.XOR, fakevm.NOT, fakevm.BYTE, fakevm.SIGNEXTEND:
+		return ZKCounters{Steps: zkStepsPerOpcode, Binaries: 2}
+	case fakevm.SHL, fakevm.SHR, fakevm.SAR:
+		return ZKCounters{Steps: 2 * zkStepsPerOpcode, Binaries: 4, Arithmetics: 1}
+	case fakevm.MUL, fakevm.DIV, fakevm.SDIV, fakevm.MOD, fakevm.SMOD:
+		return ZKCounters{Steps: 3 * zkStepsPerOpcode, Arithmetics: 1, Binaries: 3}
+	case fakevm.ADDMOD, fakevm.MULMOD:
+		return ZKCounters{Steps: 5 * zkStepsPerOpcode, Arithmetics: 2, Binaries: 5}
+	case fakevm.EXP:
+		exponentBits := uint64(0)
+		if scope != nil && scope.Stack != nil && len(scope.Stack.Data()) > 1 {
+			exponentBits = uint64(scope.Stack.Back(1).BitLen())
+		}
+		return ZKCounters{Steps: 5*zkStepsPerOpcode + 3*zkStepsPerOpcode*exponentBits, Arithmetics: 2 * exponentBits, Binaries: 2 * exponentBits}
+	case fakevm.KECCAK256:
+		size := stackSize(scope, 1)
+		c := memoryCopyZKCounters(size)
+		c.Steps += 5 * zkStepsPerOpcode
+		c.KeccakHashes = size/zkKeccakBytesPerHash + 1
+		return c
+	case fakevm.MLOAD, fakevm.MSTORE, fakevm.MSTORE8:
+		return ZKCounters{Steps: 2 * zkStepsPerOpcode, MemAligns: 1, Binaries: 1}
+	case fakevm.CALLDATALOAD:
+		return ZKCounters{Steps: 2 * zkStepsPerOpcode, MemAligns: 1}
+	case fakevm.CALLDATACOPY, fakevm.CODECOPY, fakevm.RETURNDATACOPY:
+		return memoryCopyZKCounters(stackSize(scope, 2))
+	case fakevm.EXTCODECOPY:
+		size := stackSize(scope, 3)
+		c := memoryCopyZKCounters(size)
+		c.Steps += zkStepsPerStorageAccess
+		c.PoseidonHashes = 2 * zkPoseidonsPerTreeRead
+		c.PoseidonPaddings = size/zkPoseidonBytesPerPadding + 1
+		return c
+	case fakevm.BALANCE, fakevm.EXTCODESIZE, fakevm.EXTCODEHASH, fakevm.SELFBALANCE:
+		return ZKCounters{Steps: zkStepsPerStorageAccess, PoseidonHashes: zkPoseidonsPerTreeRead, Binaries: 2}
+	case fakevm.SLOAD:
+		return ZKCounters{Steps: zkStepsPerStorageAccess, PoseidonHashes: zkPoseidonsPerTreeRead + 1, Binaries: 2}
+	case fakevm.SSTORE:
+		return ZKCounters{Steps: 2 * zkStepsPerStorageAccess, PoseidonHashes: zkPoseidonsPerTreeWrite + 1, Binaries: 10}
+	case fakevm.CALL, fakevm.CALLCODE:
+		c := memoryCopyZKCounters(stackSize(scope, 4))
+		c.Steps += zkStepsPerCall
+		c.PoseidonHashes = 3 * zkPoseidonsPerTreeRead
+		c.Binaries += 20
+		return c
+	case fakevm.DELEGATECALL, fakevm.STATICCALL:
+		c := memoryCopyZKCounters(stackSize(scope, 3))
+		c.Steps += zkStepsPerCall
+		c.PoseidonHashes = 2 * zkPoseidonsPerTreeRead
+		c.Binaries += 20
+		return c
+	case fakevm.CREATE, fakevm.CREATE2:
+		size := stackSize(scope, 2)
+		c := memoryCopyZKCounters(size)
+		c.Steps += zkStepsPerCreate
+		c.PoseidonHashes = 3 * zkPoseidonsPerTreeWrite
+		c.PoseidonPaddings = size/zkPoseidonBytesPerPadding + 1
+		c.KeccakHashes = 1
+		if op == fakevm.CREATE2 {
+			c.KeccakHashes += size/zkKeccakBytesPerHash + 1
+		}
+		return c
+	case fakevm.RETURN, fakevm.REVERT:
+		return memoryCopyZKCounters(stackSize(scope, 1))
+	case fakevm.SELFDESTRUCT:
+		return ZKCounters{Steps: zkStepsPerCall, PoseidonHashes: 2 * zkPoseidonsPerTreeWrite}
+	default:
+		return ZKCounters{Steps: zkStepsPerOpcode}
+	}
+}
@@ -0,0 +1,37 @@
+package state
+
+import "time"
+
+// SequencerHaltStatus is the status of a halt of the sequencer scheduled for maintenance
+type SequencerHaltStatus string
+
+const (
+	// SequencerHaltScheduled is the status of a halt that the sequencer has not reached yet
+	SequencerHaltScheduled SequencerHaltStatus = "scheduled"
+	// SequencerHaltHalted is the status of a halt reached by the sequencer, which is halted until it's resumed
+	SequencerHaltHalted SequencerHaltStatus = "halted"
+	// SequencerHaltResumed is the status of a halt that has been cancelled before it was reached, or resumed after it
+	SequencerHaltResumed SequencerHaltStatus = "resumed"
+)
+
+// SequencerHalt is a halt of the sequencer scheduled for maintenance. The sequencer halts once it closes the batch
+// previous to BatchNumber or when HaltAt is reached, whatever happens first (a zero BatchNumber or a nil HaltAt
+// are not taken into account)
+type SequencerHalt struct {
+	BatchNumber uint64
+	HaltAt      *time.Time
+	Status      SequencerHaltStatus
+	// Reason is the reason of the halt, or the reason of the resume once it's resumed
+	Reason    string
+	UpdatedAt time.Time
+}
+
+// IsReached returns if the sequencer must halt (or keep halted) before opening the batch nextBatchNumber at the given time
+func (h *SequencerHalt) IsReached(nextBatchNumber uint64, now time.Time) bool {
+	if h.Status == SequencerHaltHalted {
+		return true
+	} else if h.Status != SequencerHaltScheduled {
+		return false
+	}
+	return (h.BatchNumber != 0 && nextBatchNumber >= h.BatchNumber) || (h.HaltAt != nil && !now.Before(*h.HaltAt))
+}
@@ -37,6 +37,8 @@ type State struct {
 	eventLog            *event.EventLog
 	l1InfoTree          *l1infotree.L1InfoTree
 	l1InfoTreeRecursive *l1infotree.L1InfoTreeRecursive
+	traceCache          *traceCache
+	witnessCache        *witnessCache
 
 	newL2BlockEvents        chan NewL2BlockEvent
 	newL2BlockEventHandlers []NewL2BlockEventHandler
@@ -61,6 +63,17 @@ func NewState(cfg Config, storage storage, executorClient executor.ExecutorServi
 		l1InfoTreeRecursive:     mtr,
 	}
 
+	if cfg.WitnessCache.Enabled {
+		state.witnessCache = newWitnessCache(cfg.WitnessCache.MaxEntries)
+	}
+
+	if cfg.TraceCache.Enabled {
+		state.traceCache = newTraceCache(cfg.TraceCache.MaxEntries)
+		if eventLog != nil {
+			eventLog.AddEventHandler(event.EventID_L2BlockReorg, state.evictTraceCacheOnL2BlockReorg)
+		}
+	}
+
 	return state
 }
 
@@ -27,8 +27,16 @@ import (
 	"github.com/jackc/pgx/v4"
 )
 
-// DebugTransaction re-executes a tx to generate its trace
+// DebugTransaction re-executes a tx to generate its trace. If the trace cache is enabled, the traces
+// generated by custom tracers are cached and only the TraceResult is returned for them
 func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Hash, traceConfig TraceConfig, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
+	if s.traceCache != nil && !traceConfig.IsDefaultTracer() {
+		return s.cachedDebugTransaction(ctx, transactionHash, traceConfig, dbTx)
+	}
+	return s.debugTransaction(ctx, transactionHash, traceConfig, dbTx)
+}
+
+func (s *State) debugTransaction(ctx context.Context, transactionHash common.Hash, traceConfig TraceConfig, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
 	var err error
 
 	// gets the transaction
@@ -381,7 +389,6 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 	}
 
 	// select and prepare tracer
-	var tracer tracers.Tracer
 	tracerContext := &tracers.Context{
 		BlockHash:   receipt.BlockHash,
 		BlockNumber: receipt.BlockNumber,
@@ -389,6 +396,136 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 		TxHash:      transactionHash,
 	}
 
+	traceResult, err := s.traceExecutionResult(result, *receipt, tracerContext, gasPrice, batch.StateRoot.Bytes(), traceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	result.TraceResult = traceResult
+
+	return result, nil
+}
+
+// DebugUnsignedTransaction executes an unsigned tx on top of the state of the given L2 block, or
+// the last one if nil, to generate its trace. If the zkCounterTracer is used and its config doesn't
+// include the counters, the counters measured by the executor are added to it
+func (s *State) DebugUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, traceConfig TraceConfig, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
+	var l2Block *L2Block
+	var err error
+	if l2BlockNumber == nil {
+		l2Block, err = s.GetLastL2Block(ctx, dbTx)
+	} else {
+		l2Block, err = s.GetL2BlockByNumber(ctx, *l2BlockNumber, dbTx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	blockNumber := l2Block.NumberU64()
+
+	countersMeasured := true
+	response, err := s.internalProcessUnsignedTransaction(ctx, tx, senderAddress, &blockNumber, false, true, dbTx)
+	if executor.IsROMOutOfCountersError(executor.RomErrorCode(err)) {
+		// the tx doesn't fit in the counters, so it's executed again without checking them
+		// to be able to trace it
+		countersMeasured = false
+		response, err = s.internalProcessUnsignedTransaction(ctx, tx, senderAddress, &blockNumber, true, true, dbTx)
+	}
+	if err != nil && (response == nil || len(response.BlockResponses) == 0 || len(response.BlockResponses[0].TransactionResponses) == 0) {
+		return nil, err
+	}
+
+	r := response.BlockResponses[0].TransactionResponses[0]
+	// The execution errors of the tx are returned along with the response so the tx can be traced,
+	// they are reported in the result. Any other error is returned
+	if err != nil && !errors.Is(err, r.RomError) {
+		return nil, err
+	}
+	result := &runtime.ExecutionResult{
+		CreateAddress: r.CreateAddress,
+		GasLeft:       r.GasLeft,
+		GasUsed:       r.GasUsed,
+		ReturnValue:   r.ReturnValue,
+		StateRoot:     r.StateRoot.Bytes(),
+		FullTrace:     r.FullTrace,
+		Err:           r.RomError,
+	}
+
+	context := instrumentation.Context{
+		From:         senderAddress.String(),
+		Input:        tx.Data(),
+		Gas:          tx.Gas(),
+		Value:        tx.Value(),
+		Output:       result.ReturnValue,
+		GasPrice:     tx.GasPrice().String(),
+		OldStateRoot: l2Block.Root(),
+		GasUsed:      result.GasUsed,
+	}
+	if tx.To() == nil {
+		context.Type = "CREATE"
+		context.To = result.CreateAddress.Hex()
+	} else {
+		context.Type = "CALL"
+		context.To = tx.To().Hex()
+	}
+	result.FullTrace.Context = context
+
+	if traceConfig.IsZKCounterTracer() && countersMeasured {
+		traceConfig.TracerConfig, err = addZKCountersToTracerConfig(traceConfig.TracerConfig, response.UsedZkCounters)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	receipt := types.Receipt{Status: types.ReceiptStatusSuccessful, GasUsed: result.GasUsed}
+	if result.Failed() {
+		receipt.Status = types.ReceiptStatusFailed
+	}
+	tracerContext := &tracers.Context{
+		BlockNumber: l2Block.Number(),
+	}
+
+	traceResult, err := s.traceExecutionResult(result, receipt, tracerContext, tx.GasPrice(), l2Block.Root().Bytes(), traceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	result.TraceResult = traceResult
+
+	return result, nil
+}
+
+// addZKCountersToTracerConfig sets the counters of the zkCounterTracer config, unless they are already set
+func addZKCountersToTracerConfig(tracerConfig json.RawMessage, counters ZKCounters) (json.RawMessage, error) {
+	config := map[string]json.RawMessage{}
+	if len(tracerConfig) > 0 {
+		if err := json.Unmarshal(tracerConfig, &config); err != nil {
+			return nil, err
+		}
+	}
+	if _, found := config["counters"]; found {
+		return tracerConfig, nil
+	}
+	measured, err := json.Marshal(native.ZKCounters{
+		Steps:            uint64(counters.Steps),
+		KeccakHashes:     uint64(counters.KeccakHashes),
+		PoseidonHashes:   uint64(counters.PoseidonHashes),
+		PoseidonPaddings: uint64(counters.PoseidonPaddings),
+		MemAligns:        uint64(counters.MemAligns),
+		Arithmetics:      uint64(counters.Arithmetics),
+		Binaries:         uint64(counters.Binaries),
+		Sha256Hashes:     uint64(counters.Sha256Hashes_V2),
+	})
+	if err != nil {
+		return nil, err
+	}
+	config["counters"] = measured
+	return json.Marshal(config)
+}
+
+// traceExecutionResult parses the full trace of an execution result using the tracer of the trace config
+func (s *State) traceExecutionResult(result *runtime.ExecutionResult, receipt types.Receipt, tracerContext *tracers.Context, gasPrice *big.Int, stateRoot []byte, traceConfig TraceConfig) (json.RawMessage, error) {
+	var tracer tracers.Tracer
+	var err error
 	if traceConfig.IsDefaultTracer() {
 		structLoggerCfg := structlogger.Config{
 			EnableMemory:     traceConfig.EnableMemory,
@@ -397,12 +534,7 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 			EnableReturnData: traceConfig.EnableReturnData,
 		}
 		tracer := structlogger.NewStructLogger(structLoggerCfg)
-		traceResult, err := tracer.ParseTrace(result, *receipt)
-		if err != nil {
-			return nil, err
-		}
-		result.TraceResult = traceResult
-		return result, nil
+		return tracer.ParseTrace(result, receipt)
 	} else if traceConfig.Is4ByteTracer() {
 		tracer, err = native.NewFourByteTracer(tracerContext, traceConfig.TracerConfig)
 		if err != nil {
@@ -415,6 +547,12 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 			log.Errorf("debug transaction: failed to create callTracer, err: %v", err)
 			return nil, fmt.Errorf("failed to create callTracer, err: %v", err)
 		}
+	} else if traceConfig.IsFlatCallTracer() {
+		tracer, err = native.NewFlatCallTracer(tracerContext, traceConfig.TracerConfig)
+		if err != nil {
+			log.Errorf("debug transaction: failed to create flatCallTracer, err: %v", err)
+			return nil, fmt.Errorf("failed to create flatCallTracer, err: %v", err)
+		}
 	} else if traceConfig.IsNoopTracer() {
 		tracer, err = native.NewNoopTracer(tracerContext, traceConfig.TracerConfig)
 		if err != nil {
@@ -427,6 +565,12 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 			log.Errorf("debug transaction: failed to create prestateTracer, err: %v", err)
 			return nil, fmt.Errorf("failed to create prestateTracer, err: %v", err)
 		}
+	} else if traceConfig.IsZKCounterTracer() {
+		tracer, err = native.NewZKCounterTracer(tracerContext, traceConfig.TracerConfig)
+		if err != nil {
+			log.Errorf("debug transaction: failed to create zkCounterTracer, err: %v", err)
+			return nil, fmt.Errorf("failed to create zkCounterTracer, err: %v", err)
+		}
 	} else if traceConfig.IsJSCustomTracer() {
 		tracer, err = js.NewJsTracer(*traceConfig.Tracer, tracerContext, traceConfig.TracerConfig)
 		if err != nil {
@@ -437,7 +581,7 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 		return nil, fmt.Errorf("invalid tracer: %v, err: %v", traceConfig.Tracer, err)
 	}
 
-	fakeDB := &FakeDB{State: s, stateRoot: batch.StateRoot.Bytes()}
+	fakeDB := &FakeDB{State: s, stateRoot: stateRoot}
 	evm := fakevm.NewFakeEVM(fakevm.BlockContext{BlockNumber: big.NewInt(1)}, fakevm.TxContext{GasPrice: gasPrice}, fakeDB, params.TestChainConfig, fakevm.Config{Debug: true, Tracer: tracer})
 
 	traceResult, err := s.buildTrace(evm, result, tracer)
@@ -446,9 +590,7 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 		return nil, fmt.Errorf("failed parse the trace using the tracer: %v", err)
 	}
 
-	result.TraceResult = traceResult
-
-	return result, nil
+	return traceResult, nil
 }
 
 // ParseTheTraceUsingTheTracer parses the given trace with the given tracer.
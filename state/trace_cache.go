@@ -0,0 +1,172 @@
+package state
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state/metrics"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jackc/pgx/v4"
+)
+
+// traceCache keeps in memory the most recently used traces. Each trace is stored along with the
+// hash of the L2 block of the transaction, so a trace generated before a reorg is never returned
+type traceCache struct {
+	maxEntries int
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type traceCacheEntry struct {
+	key         string
+	l2BlockHash common.Hash
+	trace       json.RawMessage
+}
+
+func newTraceCache(maxEntries int) *traceCache {
+	return &traceCache{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+	}
+}
+
+// get returns the trace for the key if it was generated for the L2 block with the given hash
+func (c *traceCache) get(key string, l2BlockHash common.Hash) (json.RawMessage, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, found := c.entries[key]
+	if !found {
+		return nil, false
+	}
+	entry := element.Value.(*traceCacheEntry)
+	if entry.l2BlockHash != l2BlockHash {
+		c.remove(element)
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return entry.trace, true
+}
+
+// add stores the trace for the key, evicting the least recently used trace if the cache is full
+func (c *traceCache) add(key string, l2BlockHash common.Hash, trace json.RawMessage) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, found := c.entries[key]; found {
+		element.Value = &traceCacheEntry{key: key, l2BlockHash: l2BlockHash, trace: trace}
+		c.lru.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&traceCacheEntry{key: key, l2BlockHash: l2BlockHash, trace: trace})
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+	metrics.TraceCacheEntries(c.lru.Len())
+}
+
+// clear removes all the traces
+func (c *traceCache) clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries = map[string]*list.Element{}
+	c.lru.Init()
+	metrics.TraceCacheEntries(0)
+}
+
+// len returns the number of traces in the cache
+func (c *traceCache) len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.lru.Len()
+}
+
+func (c *traceCache) remove(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.entries, element.Value.(*traceCacheEntry).key)
+	metrics.TraceCacheEntries(c.lru.Len())
+}
+
+// traceCacheKey returns the key of the trace of a tx generated with the trace config
+func traceCacheKey(transactionHash common.Hash, traceConfig TraceConfig) (string, error) {
+	// the tracer config is compacted so the same config with a different format has the same key
+	if len(traceConfig.TracerConfig) > 0 {
+		compacted := new(bytes.Buffer)
+		if err := json.Compact(compacted, traceConfig.TracerConfig); err != nil {
+			return "", err
+		}
+		traceConfig.TracerConfig = compacted.Bytes()
+	}
+	b, err := json.Marshal(traceConfig)
+	if err != nil {
+		return "", err
+	}
+	return transactionHash.String() + ":" + crypto.Keccak256Hash(b).String(), nil
+}
+
+// evictTraceCacheOnL2BlockReorg clears the in memory trace cache when the sequencer of this node
+// reorgs a L2 block
+func (s *State) evictTraceCacheOnL2BlockReorg(_ context.Context, _ *event.Event) {
+	log.Infof("clearing the trace cache after a L2 block reorg")
+	s.traceCache.clear()
+}
+
+// cachedDebugTransaction returns the cached trace of the tx, generating and caching it if not found.
+// The traces read from the cache are returned in the TraceResult of an otherwise empty execution result
+func (s *State) cachedDebugTransaction(ctx context.Context, transactionHash common.Hash, traceConfig TraceConfig, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
+	receipt, err := s.GetTransactionReceipt(ctx, transactionHash, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	key, err := traceCacheKey(transactionHash, traceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if trace, found := s.traceCache.get(key, receipt.BlockHash); found {
+		metrics.TraceCacheHit(metrics.TraceCacheMemorySource)
+		return &runtime.ExecutionResult{TraceResult: trace}, nil
+	}
+	if s.cfg.TraceCache.Persist {
+		trace, err := s.GetCachedTrace(ctx, key, receipt.BlockHash, nil)
+		if err == nil {
+			metrics.TraceCacheHit(metrics.TraceCacheDBSource)
+			s.traceCache.add(key, receipt.BlockHash, trace)
+			return &runtime.ExecutionResult{TraceResult: trace}, nil
+		} else if !errors.Is(err, ErrNotFound) {
+			log.Errorf("error getting cached trace of tx %v: %v", transactionHash.String(), err)
+		}
+	}
+	metrics.TraceCacheMiss()
+
+	result, err := s.debugTransaction(ctx, transactionHash, traceConfig, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	if s.cfg.TraceCache.MaxTraceSize > 0 && uint64(len(result.TraceResult)) > s.cfg.TraceCache.MaxTraceSize {
+		return result, nil
+	}
+
+	s.traceCache.add(key, receipt.BlockHash, result.TraceResult)
+	if s.cfg.TraceCache.Persist {
+		// the cache is accessed out of the db tx, so a failure doesn't abort it
+		err := s.AddCachedTrace(ctx, key, receipt.BlockNumber.Uint64(), receipt.BlockHash, result.TraceResult, nil)
+		if err != nil {
+			log.Errorf("error storing cached trace of tx %v: %v", transactionHash.String(), err)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package state
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceCache(t *testing.T) {
+	c := newTraceCache(2)
+	blockHash := common.HexToHash("0x1")
+
+	c.add("a", blockHash, json.RawMessage(`"a"`))
+	c.add("b", blockHash, json.RawMessage(`"b"`))
+	trace, found := c.get("a", blockHash)
+	require.True(t, found)
+	assert.Equal(t, json.RawMessage(`"a"`), trace)
+
+	// b is the least recently used trace
+	c.add("c", blockHash, json.RawMessage(`"c"`))
+	_, found = c.get("b", blockHash)
+	assert.False(t, found)
+	_, found = c.get("c", blockHash)
+	assert.True(t, found)
+	assert.Equal(t, 2, c.len())
+
+	// the L2 block of the tx has been reorged
+	_, found = c.get("a", common.HexToHash("0x2"))
+	assert.False(t, found)
+	assert.Equal(t, 1, c.len())
+
+	c.clear()
+	_, found = c.get("c", blockHash)
+	assert.False(t, found)
+	assert.Equal(t, 0, c.len())
+}
+
+func TestTraceCacheKey(t *testing.T) {
+	txHash := common.HexToHash("0x1")
+	tracer := "callTracer"
+
+	key, err := traceCacheKey(txHash, TraceConfig{Tracer: &tracer, TracerConfig: json.RawMessage(`{"onlyTopCall": true}`)})
+	require.NoError(t, err)
+	sameKey, err := traceCacheKey(txHash, TraceConfig{Tracer: &tracer, TracerConfig: json.RawMessage(`{"onlyTopCall":true}`)})
+	require.NoError(t, err)
+	assert.Equal(t, key, sameKey)
+
+	otherKey, err := traceCacheKey(txHash, TraceConfig{Tracer: &tracer})
+	require.NoError(t, err)
+	assert.NotEqual(t, key, otherKey)
+
+	otherTracer := "flatCallTracer"
+	otherKey, err = traceCacheKey(txHash, TraceConfig{Tracer: &otherTracer, TracerConfig: json.RawMessage(`{"onlyTopCall":true}`)})
+	require.NoError(t, err)
+	assert.NotEqual(t, key, otherKey)
+
+	_, err = traceCacheKey(txHash, TraceConfig{Tracer: &tracer, TracerConfig: json.RawMessage(`{`)})
+	require.Error(t, err)
+}
@@ -0,0 +1,203 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// FlatCallTracer is the tracer that generates parity style traces
+	FlatCallTracer = "flatCallTracer"
+	// flatCallTracerConfig makes the flat call tracer use the parity error messages
+	flatCallTracerConfig = `{"convertParityErrors":true}`
+)
+
+// IndexedTrace is a flat call trace stored to be filtered by address
+type IndexedTrace struct {
+	L2BlockNumber uint64
+	TxIndex       uint64
+	// TraceIndex is the position of the trace in the traces of the transaction
+	TraceIndex uint64
+	TxHash     common.Hash
+	// From is the caller, or the self destructed contract
+	From *common.Address
+	// To is the callee, the created contract or the refund address of a self destruct
+	To    *common.Address
+	Trace json.RawMessage
+}
+
+// TraceFilter filters the indexed traces. A trace matches if it's in the block range and its
+// from and to addresses are included in FromAddresses and ToAddresses. An empty list matches any address
+type TraceFilter struct {
+	FromBlock     uint64
+	ToBlock       uint64
+	FromAddresses []common.Address
+	ToAddresses   []common.Address
+	// After is the number of matching traces skipped
+	After uint64
+	// Count is the max number of traces returned, all if nil
+	Count *uint64
+}
+
+// flatTrace contains the fields of a flat call trace used to index it
+type flatTrace struct {
+	Type   string `json:"type"`
+	Action struct {
+		From          *common.Address `json:"from"`
+		To            *common.Address `json:"to"`
+		Address       *common.Address `json:"address"`
+		RefundAddress *common.Address `json:"refundAddress"`
+	} `json:"action"`
+	Result *struct {
+		Address *common.Address `json:"address"`
+	} `json:"result"`
+}
+
+// FlatTraceTransaction re-executes a tx to generate its parity style flat call traces
+func (s *State) FlatTraceTransaction(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) ([]json.RawMessage, error) {
+	return s.flatTraceTransaction(ctx, transactionHash, s.DebugTransaction, dbTx)
+}
+
+func (s *State) flatTraceTransaction(ctx context.Context, transactionHash common.Hash, debugTransaction func(context.Context, common.Hash, TraceConfig, pgx.Tx) (*runtime.ExecutionResult, error), dbTx pgx.Tx) ([]json.RawMessage, error) {
+	tracer := FlatCallTracer
+	result, err := debugTransaction(ctx, transactionHash, TraceConfig{
+		Tracer:       &tracer,
+		TracerConfig: json.RawMessage(flatCallTracerConfig),
+	}, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	traces := []json.RawMessage{}
+	if err := json.Unmarshal(result.TraceResult, &traces); err != nil {
+		return nil, err
+	}
+	return traces, nil
+}
+
+// StartTraceIndexer periodically stores the flat call traces of the new L2 blocks, so they can be
+// filtered by address without executing the transactions again. It returns immediately if the
+// trace index is disabled
+func (s *State) StartTraceIndexer(ctx context.Context) {
+	if !s.cfg.TraceIndex.Enabled {
+		return
+	}
+	log.Info("trace index enabled")
+	for {
+		for {
+			indexed, err := s.IndexTraces(ctx)
+			if err != nil {
+				log.Errorf("error indexing traces: %v", err)
+				break
+			}
+			if indexed == 0 {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.TraceIndex.Interval.Duration):
+		}
+	}
+}
+
+// IndexTraces stores the flat call traces of up to MaxL2BlocksPerRun L2 blocks following the last
+// one indexed. It returns the number of L2 blocks indexed
+func (s *State) IndexTraces(ctx context.Context) (uint64, error) {
+	lastL2Block, err := s.GetLastL2BlockNumber(ctx, nil)
+	if errors.Is(err, ErrStateNotSynchronized) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	fromL2Block := uint64(1)
+	lastIndexed, err := s.GetLastIndexedTraceL2BlockNumber(ctx, nil)
+	if err == nil {
+		fromL2Block = lastIndexed + 1
+	} else if !errors.Is(err, ErrNotFound) {
+		return 0, err
+	}
+	// The transactions of the pruned L2 blocks can't be executed again
+	firstKept, err := s.GetFirstKeptL2BlockNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	if firstKept > fromL2Block {
+		fromL2Block = firstKept
+	}
+
+	indexed := uint64(0)
+	for blockNumber := fromL2Block; blockNumber <= lastL2Block; blockNumber++ {
+		if s.cfg.TraceIndex.MaxL2BlocksPerRun > 0 && indexed >= s.cfg.TraceIndex.MaxL2BlocksPerRun {
+			break
+		}
+		if err := s.indexL2BlockTraces(ctx, blockNumber); err != nil {
+			return indexed, err
+		}
+		indexed++
+	}
+	if indexed > 0 {
+		log.Debugf("indexed traces of L2 blocks %d to %d", fromL2Block, fromL2Block+indexed-1)
+	}
+	return indexed, nil
+}
+
+func (s *State) indexL2BlockTraces(ctx context.Context, blockNumber uint64) error {
+	l2Block, err := s.GetL2BlockByNumber(ctx, blockNumber, nil)
+	if err != nil {
+		return err
+	}
+	traces := []IndexedTrace{}
+	for txIndex, tx := range l2Block.Transactions() {
+		// the trace cache is skipped, the indexed traces are already stored
+		txTraces, err := s.flatTraceTransaction(ctx, tx.Hash(), s.debugTransaction, nil)
+		if err != nil {
+			return err
+		}
+		for traceIndex, trace := range txTraces {
+			var t flatTrace
+			if err := json.Unmarshal(trace, &t); err != nil {
+				return err
+			}
+			indexedTrace := IndexedTrace{
+				L2BlockNumber: blockNumber,
+				TxIndex:       uint64(txIndex),
+				TraceIndex:    uint64(traceIndex),
+				TxHash:        tx.Hash(),
+				From:          t.Action.From,
+				To:            t.Action.To,
+				Trace:         trace,
+			}
+			switch t.Type {
+			case "create":
+				if t.Result != nil {
+					indexedTrace.To = t.Result.Address
+				}
+			case "suicide":
+				indexedTrace.From = t.Action.Address
+				indexedTrace.To = t.Action.RefundAddress
+			}
+			traces = append(traces, indexedTrace)
+		}
+	}
+
+	dbTx, err := s.BeginStateTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.AddL2BlockTraces(ctx, blockNumber, l2Block.Hash(), traces, dbTx); err != nil {
+		if rollbackErr := dbTx.Rollback(ctx); rollbackErr != nil {
+			log.Errorf("error rolling back traces of L2 block %d: %v", blockNumber, rollbackErr)
+		}
+		return err
+	}
+	return dbTx.Commit(ctx)
+}
@@ -297,7 +297,7 @@ func (s *State) StoreL2Block(ctx context.Context, batchNumber uint64, l2Block *P
 
 // PreProcessUnsignedTransaction processes the unsigned transaction in order to calculate its zkCounters
 func (s *State) PreProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, sender common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
-	response, err := s.internalProcessUnsignedTransaction(ctx, tx, sender, l2BlockNumber, false, dbTx)
+	response, err := s.internalProcessUnsignedTransaction(ctx, tx, sender, l2BlockNumber, false, false, dbTx)
 	if err != nil {
 		return response, err
 	}
@@ -312,7 +312,7 @@ func (s *State) PreProcessTransaction(ctx context.Context, tx *types.Transaction
 		return nil, err
 	}
 
-	response, err := s.internalProcessUnsignedTransaction(ctx, tx, sender, nil, false, dbTx)
+	response, err := s.internalProcessUnsignedTransaction(ctx, tx, sender, nil, false, false, dbTx)
 	if err != nil {
 		return response, err
 	}
@@ -323,7 +323,7 @@ func (s *State) PreProcessTransaction(ctx context.Context, tx *types.Transaction
 // ProcessUnsignedTransaction processes the given unsigned transaction.
 func (s *State) ProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
 	result := new(runtime.ExecutionResult)
-	response, err := s.internalProcessUnsignedTransaction(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, dbTx)
+	response, err := s.internalProcessUnsignedTransaction(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, false, dbTx)
 	if err != nil {
 		return nil, err
 	}
@@ -345,7 +345,8 @@ func (s *State) ProcessUnsignedTransaction(ctx context.Context, tx *types.Transa
 }
 
 // internalProcessUnsignedTransaction processes the given unsigned transaction.
-func (s *State) internalProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
+// If fullTrace is set, the executor returns the full trace of the transaction.
+func (s *State) internalProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, fullTrace bool, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
 	var l2Block *L2Block
 	var err error
 	if l2BlockNumber == nil {
@@ -364,15 +365,15 @@ func (s *State) internalProcessUnsignedTransaction(ctx context.Context, tx *type
 
 	forkID := s.GetForkIDByBatchNumber(batch.BatchNumber)
 	if forkID < FORKID_ETROG {
-		return s.internalProcessUnsignedTransactionV1(ctx, tx, senderAddress, *batch, *l2Block, forkID, noZKEVMCounters, dbTx)
+		return s.internalProcessUnsignedTransactionV1(ctx, tx, senderAddress, *batch, *l2Block, forkID, noZKEVMCounters, fullTrace, dbTx)
 	} else {
-		return s.internalProcessUnsignedTransactionV2(ctx, tx, senderAddress, *batch, *l2Block, forkID, noZKEVMCounters, dbTx)
+		return s.internalProcessUnsignedTransactionV2(ctx, tx, senderAddress, *batch, *l2Block, forkID, noZKEVMCounters, fullTrace, dbTx)
 	}
 }
 
 // internalProcessUnsignedTransactionV1 processes the given unsigned transaction.
 // pre ETROG
-func (s *State) internalProcessUnsignedTransactionV1(ctx context.Context, tx *types.Transaction, senderAddress common.Address, batch Batch, l2Block L2Block, forkID uint64, noZKEVMCounters bool, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
+func (s *State) internalProcessUnsignedTransactionV1(ctx context.Context, tx *types.Transaction, senderAddress common.Address, batch Batch, l2Block L2Block, forkID uint64, noZKEVMCounters bool, fullTrace bool, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
 	var attempts = 1
 
 	if s.executorClient == nil {
@@ -424,6 +425,19 @@ func (s *State) internalProcessUnsignedTransactionV1(ctx context.Context, tx *ty
 	if noZKEVMCounters {
 		processBatchRequestV1.NoCounters = cTrue
 	}
+	if fullTrace {
+		txHash, err := unsignedTransactionHash(batchL2Data, forkID)
+		if err != nil {
+			return nil, err
+		}
+		processBatchRequestV1.TraceConfig = &executor.TraceConfig{
+			TxHashToGenerateFullTrace: txHash.Bytes(),
+			DisableStorage:            cFalse,
+			DisableStack:              cFalse,
+			EnableMemory:              cTrue,
+			EnableReturnData:          cTrue,
+		}
+	}
 	log.Debugf("internalProcessUnsignedTransactionV1[processBatchRequestV1.From]: %v", processBatchRequestV1.From)
 	log.Debugf("internalProcessUnsignedTransactionV1[processBatchRequestV1.OldBatchNum]: %v", processBatchRequestV1.OldBatchNum)
 	log.Debugf("internalProcessUnsignedTransactionV1[processBatchRequestV1.OldStateRoot]: %v", hex.EncodeToHex(processBatchRequestV1.OldStateRoot))
@@ -500,7 +514,7 @@ func (s *State) internalProcessUnsignedTransactionV1(ctx context.Context, tx *ty
 
 // internalProcessUnsignedTransactionV2 processes the given unsigned transaction.
 // post ETROG
-func (s *State) internalProcessUnsignedTransactionV2(ctx context.Context, tx *types.Transaction, senderAddress common.Address, batch Batch, l2Block L2Block, forkID uint64, noZKEVMCounters bool, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
+func (s *State) internalProcessUnsignedTransactionV2(ctx context.Context, tx *types.Transaction, senderAddress common.Address, batch Batch, l2Block L2Block, forkID uint64, noZKEVMCounters bool, fullTrace bool, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
 	var attempts = 1
 
 	if s.executorClient == nil {
@@ -548,6 +562,19 @@ func (s *State) internalProcessUnsignedTransactionV2(ctx context.Context, tx *ty
 	if noZKEVMCounters {
 		processBatchRequestV2.NoCounters = cTrue
 	}
+	if fullTrace {
+		txHash, err := unsignedTransactionHash(batchL2Data, forkID)
+		if err != nil {
+			return nil, err
+		}
+		processBatchRequestV2.TraceConfig = &executor.TraceConfigV2{
+			TxHashToGenerateFullTrace: txHash.Bytes(),
+			DisableStorage:            cFalse,
+			DisableStack:              cFalse,
+			EnableMemory:              cTrue,
+			EnableReturnData:          cTrue,
+		}
+	}
 
 	log.Debugf("internalProcessUnsignedTransactionV2[processBatchRequestV2.OldBatchNum]: %v", processBatchRequestV2.OldBatchNum)
 	log.Debugf("internalProcessUnsignedTransactionV2[processBatchRequestV2.OldStateRoot]: %v", hex.EncodeToHex(processBatchRequestV2.OldStateRoot))
@@ -636,6 +663,19 @@ func (s *State) internalProcessUnsignedTransactionV2(ctx context.Context, tx *ty
 	return response, nil
 }
 
+// unsignedTransactionHash returns the hash the executor computes for an encoded unsigned transaction,
+// which includes the fake signature added by EncodeUnsignedTransaction
+func unsignedTransactionHash(batchL2Data []byte, forkID uint64) (common.Hash, error) {
+	txs, _, _, err := DecodeTxs(batchL2Data, forkID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(txs) != 1 {
+		return common.Hash{}, ErrInvalidData
+	}
+	return txs[0].Hash(), nil
+}
+
 // isContractCreation checks if the tx is a contract creation
 func (s *State) isContractCreation(tx *types.Transaction) bool {
 	return tx.To() == nil && len(tx.Data()) > 0
@@ -163,6 +163,16 @@ type EffectiveGasPriceLog struct {
 	L1GasPrice     uint64
 	L2GasPrice     uint64
 	Error          string
+	// L1BlobBaseFee and L1CostModel are the L1 blob base fee and the model used to price the L1 data
+	L1BlobBaseFee uint64 `json:",omitempty"`
+	L1CostModel   string `json:",omitempty"`
+}
+
+// TransactionEGPLog contains a stored tx and its EGP log, nil if the tx was stored without it
+type TransactionEGPLog struct {
+	L2BlockNumber uint64
+	Tx            *types.Transaction
+	EGPLog        *EffectiveGasPriceLog
 }
 
 // StoreTxEGPData contains the data related to the effective gas price that needs to be stored when storing a tx
@@ -341,6 +351,11 @@ func (t *TraceConfig) IsCallTracer() bool {
 	return t.Tracer != nil && *t.Tracer == "callTracer"
 }
 
+// IsFlatCallTracer returns true when should use flatCallTracer
+func (t *TraceConfig) IsFlatCallTracer() bool {
+	return t.Tracer != nil && *t.Tracer == "flatCallTracer"
+}
+
 // IsNoopTracer returns true when should use noopTracer
 func (t *TraceConfig) IsNoopTracer() bool {
 	return t.Tracer != nil && *t.Tracer == "noopTracer"
@@ -351,6 +366,11 @@ func (t *TraceConfig) IsPrestateTracer() bool {
 	return t.Tracer != nil && *t.Tracer == "prestateTracer"
 }
 
+// IsZKCounterTracer returns true when should use zkCounterTracer
+func (t *TraceConfig) IsZKCounterTracer() bool {
+	return t.Tracer != nil && *t.Tracer == "zkCounterTracer"
+}
+
 // IsJSCustomTracer returns true when should use js custom tracer
 func (t *TraceConfig) IsJSCustomTracer() bool {
 	return t.Tracer != nil && strings.Contains(*t.Tracer, "result") && strings.Contains(*t.Tracer, "fault")
@@ -0,0 +1,191 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// BatchWitness contains everything needed to execute a closed batch without the state: the
+// inputs of the batch and the partial state tree it reads and writes, made of the nodes of the
+// SMT in the paths to the keys used by the batch and the bytecodes of the contracts it loads.
+//
+// The partial tree uses the format of the db and contracts_bytecode fields of the executor and
+// prover requests, so both can execute the batch with an empty HashDB. See ExecuteBatchWitness
+// for why the witness of ProcessStatelessBatchV2 isn't used
+type BatchWitness struct {
+	BatchNumber       uint64
+	ChainID           uint64
+	ForkID            uint64
+	OldStateRoot      common.Hash
+	NewStateRoot      common.Hash
+	OldAccInputHash   common.Hash
+	Coinbase          common.Address
+	BatchL2Data       []byte
+	L1InfoRoot        common.Hash
+	L1InfoTreeData    map[uint32]L1DataV2
+	TimestampLimit    uint64
+	ForcedBlockHashL1 common.Hash
+	// Db contains the nodes of the partial tree indexed by their hash, with their 12 field
+	// elements concatenated as 16 digits hex strings
+	Db map[string]string
+	// ContractsBytecode contains the bytecodes of the contracts indexed by their hash
+	ContractsBytecode map[string]string
+}
+
+// GetBatchWitness generates the witness of a closed batch, executing it again to get the keys
+// of the state tree and the contracts it uses. If the witness cache is enabled the batch is only
+// executed the first time
+func (s *State) GetBatchWitness(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*BatchWitness, error) {
+	if s.tree == nil {
+		return nil, ErrStateTreeNil
+	}
+	if batchNumber == 0 {
+		return nil, fmt.Errorf("the genesis batch can't be executed")
+	}
+
+	closed, err := s.IsBatchClosed(ctx, batchNumber, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return nil, fmt.Errorf("batch %d is not closed", batchNumber)
+	}
+	forkID := s.GetForkIDByBatchNumber(batchNumber)
+	if forkID < FORKID_ETROG {
+		return nil, fmt.Errorf("batch %d of fork %d can't be executed statelessly", batchNumber, forkID)
+	}
+
+	batch, err := s.GetBatchByNumber(ctx, batchNumber, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	previousBatch, err := s.GetBatchByNumber(ctx, batchNumber-1, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	if s.witnessCache != nil {
+		if witness, found := s.witnessCache.get(batchNumber, previousBatch.StateRoot, batch.StateRoot); found {
+			return witness, nil
+		}
+	}
+
+	witness := &BatchWitness{
+		BatchNumber:       batchNumber,
+		ChainID:           s.cfg.ChainID,
+		ForkID:            forkID,
+		OldStateRoot:      previousBatch.StateRoot,
+		NewStateRoot:      batch.StateRoot,
+		OldAccInputHash:   previousBatch.AccInputHash,
+		Coinbase:          batch.Coinbase,
+		BatchL2Data:       batch.BatchL2Data,
+		TimestampLimit:    uint64(batch.Timestamp.Unix()),
+		ContractsBytecode: map[string]string{},
+	}
+	if batch.ForcedBatchNum != nil {
+		witness.ForcedBlockHashL1, err = s.GetForcedBatchParentHash(ctx, *batch.ForcedBatchNum, dbTx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		witness.L1InfoTreeData, witness.L1InfoRoot, _, err = s.GetL1InfoTreeDataFromBatchL2Data(ctx, batch.BatchL2Data, dbTx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	request := witness.processBatchRequest()
+	request.GetKeys = cTrue
+	response, err := s.executorClient.ProcessBatchV2(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != executor.ExecutorError_EXECUTOR_ERROR_NO_ERROR {
+		return nil, executor.ExecutorErr(response.Error)
+	}
+	if newStateRoot := common.BytesToHash(response.NewStateRoot); newStateRoot != batch.StateRoot {
+		return nil, fmt.Errorf("new state root mismatch executing batch %d, expected: %s, got: %s", batchNumber, batch.StateRoot, newStateRoot)
+	}
+
+	witness.Db, err = s.tree.ReadTree(ctx, witness.OldStateRoot, convertToKeys(response.SmtKeys))
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range response.ProgramKeys {
+		code, err := s.tree.GetProgram(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		witness.ContractsBytecode[hex.EncodeToString(common.BytesToHash(key).Bytes())] = hex.EncodeToString(code)
+	}
+
+	log.Debugf("witness of batch %d generated with %d nodes and %d contracts", batchNumber, len(witness.Db), len(witness.ContractsBytecode))
+	if s.witnessCache != nil {
+		s.witnessCache.add(witness)
+	}
+	return witness, nil
+}
+
+// ExecuteBatchWitness executes a batch with the executor sending it the state of its witness.
+// The merkle tree isn't updated. The executor must not have the state of the node, otherwise the
+// nodes missing in the witness are read from its HashDB: reference.ExecuteBatchWitness executes
+// it over an empty HashDB to verify the witness
+func ExecuteBatchWitness(ctx context.Context, client executor.ExecutorServiceClient, witness *BatchWitness) (*executor.ProcessBatchResponseV2, error) {
+	// ProcessStatelessBatchV2 can't be used because its witness can't be built from the state of
+	// the node: it encodes each leaf of the partial tree with the address, type and storage
+	// position its key is derived from, and the merkle tree only stores the hashes of the keys.
+	// It also takes the batch in the data stream format instead of the BatchL2Data, and the
+	// reference executor used to verify the witnesses doesn't implement it. The Db and
+	// ContractsBytecode fields of ProcessBatchV2 take the nodes and the bytecodes read from the
+	// merkle tree as they are
+	request := witness.processBatchRequest()
+	request.Db = witness.Db
+	request.ContractsBytecode = witness.ContractsBytecode
+
+	response, err := client.ProcessBatchV2(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != executor.ExecutorError_EXECUTOR_ERROR_NO_ERROR {
+		return response, executor.ExecutorErr(response.Error)
+	}
+	return response, nil
+}
+
+// processBatchRequest returns the request to execute the batch of the witness without updating
+// the merkle tree
+func (w *BatchWitness) processBatchRequest() *executor.ProcessBatchRequestV2 {
+	request := &executor.ProcessBatchRequestV2{
+		OldBatchNum:          w.BatchNumber - 1,
+		Coinbase:             w.Coinbase.String(),
+		BatchL2Data:          w.BatchL2Data,
+		OldStateRoot:         w.OldStateRoot.Bytes(),
+		L1InfoRoot:           w.L1InfoRoot.Bytes(),
+		OldAccInputHash:      w.OldAccInputHash.Bytes(),
+		TimestampLimit:       w.TimestampLimit,
+		UpdateMerkleTree:     cFalse,
+		ChainId:              w.ChainID,
+		ForkId:               w.ForkID,
+		ContextId:            uuid.NewString(),
+		SkipVerifyL1InfoRoot: cTrue,
+	}
+	if w.ForcedBlockHashL1 != (common.Hash{}) {
+		request.ForcedBlockhashL1 = w.ForcedBlockHashL1.Bytes()
+	} else {
+		request.L1InfoTreeData = make(map[uint32]*executor.L1DataV2, len(w.L1InfoTreeData))
+		for i, v := range w.L1InfoTreeData {
+			request.L1InfoTreeData[i] = &executor.L1DataV2{
+				GlobalExitRoot: v.GlobalExitRoot.Bytes(),
+				BlockHashL1:    v.BlockHashL1.Bytes(),
+				MinTimestamp:   v.MinTimestamp,
+			}
+		}
+	}
+	return request
+}
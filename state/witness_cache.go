@@ -0,0 +1,83 @@
+package state
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// witnessCache keeps in memory the most recently used batch witnesses. A witness is only returned
+// if the state roots of the batch haven't changed since it was generated, so a witness generated
+// before a reorg is never returned
+type witnessCache struct {
+	maxEntries int
+
+	mutex   sync.Mutex
+	entries map[uint64]*list.Element
+	lru     *list.List
+}
+
+func newWitnessCache(maxEntries int) *witnessCache {
+	return &witnessCache{
+		maxEntries: maxEntries,
+		entries:    map[uint64]*list.Element{},
+		lru:        list.New(),
+	}
+}
+
+// get returns the witness of the batch if it was generated for the given old and new state roots
+func (c *witnessCache) get(batchNumber uint64, oldStateRoot, newStateRoot common.Hash) (*BatchWitness, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, found := c.entries[batchNumber]
+	if !found {
+		return nil, false
+	}
+	witness := element.Value.(*BatchWitness)
+	if witness.OldStateRoot != oldStateRoot || witness.NewStateRoot != newStateRoot {
+		c.remove(element)
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return witness, true
+}
+
+// add stores the witness of its batch, evicting the least recently used witness if the cache is full
+func (c *witnessCache) add(witness *BatchWitness) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, found := c.entries[witness.BatchNumber]; found {
+		element.Value = witness
+		c.lru.MoveToFront(element)
+		return
+	}
+	c.entries[witness.BatchNumber] = c.lru.PushFront(witness)
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+// clear removes all the witnesses
+func (c *witnessCache) clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries = map[uint64]*list.Element{}
+	c.lru.Init()
+}
+
+// len returns the number of witnesses in the cache
+func (c *witnessCache) len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.lru.Len()
+}
+
+func (c *witnessCache) remove(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.entries, element.Value.(*BatchWitness).BatchNumber)
+}
@@ -0,0 +1,39 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWitnessCache(t *testing.T) {
+	c := newWitnessCache(2)
+	oldStateRoot := common.HexToHash("0x1")
+	newStateRoot := common.HexToHash("0x2")
+
+	c.add(&BatchWitness{BatchNumber: 1, OldStateRoot: oldStateRoot, NewStateRoot: newStateRoot})
+	c.add(&BatchWitness{BatchNumber: 2, OldStateRoot: oldStateRoot, NewStateRoot: newStateRoot})
+	witness, found := c.get(1, oldStateRoot, newStateRoot)
+	require.True(t, found)
+	assert.Equal(t, uint64(1), witness.BatchNumber)
+
+	// 2 is the least recently used witness
+	c.add(&BatchWitness{BatchNumber: 3, OldStateRoot: oldStateRoot, NewStateRoot: newStateRoot})
+	_, found = c.get(2, oldStateRoot, newStateRoot)
+	assert.False(t, found)
+	_, found = c.get(3, oldStateRoot, newStateRoot)
+	assert.True(t, found)
+	assert.Equal(t, 2, c.len())
+
+	// the batch has been reorged
+	_, found = c.get(1, oldStateRoot, common.HexToHash("0x3"))
+	assert.False(t, found)
+	assert.Equal(t, 1, c.len())
+
+	c.clear()
+	_, found = c.get(3, oldStateRoot, newStateRoot)
+	assert.False(t, found)
+	assert.Equal(t, 0, c.len())
+}
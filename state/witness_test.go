@@ -0,0 +1,92 @@
+package state_test
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/mocks"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBatchWitness(t *testing.T) {
+	stateCfg := state.Config{
+		ChainID: 1000,
+		ForkIDIntervals: []state.ForkIDInterval{{
+			FromBatchNumber: 0,
+			ToBatchNumber:   math.MaxUint64,
+			ForkId:          state.FORKID_ETROG,
+		}},
+	}
+	ctx := context.Background()
+	tree := merkletree.NewStateTree(merkletree.NewMemoryHashDB())
+	code := common.Hex2Bytes("6080604052")
+	contract := common.HexToAddress("0x2")
+
+	oldRoot, _, err := tree.SetBalance(ctx, addr1, big.NewInt(1000), common.Hash{}.Bytes(), "")
+	require.NoError(t, err)
+	oldRoot, _, err = tree.SetCode(ctx, contract, code, oldRoot, "")
+	require.NoError(t, err)
+	newRoot, _, err := tree.SetBalance(ctx, addr1, big.NewInt(900), oldRoot, "")
+	require.NoError(t, err)
+
+	balanceKey, err := merkletree.KeyEthAddrBalance(addr1)
+	require.NoError(t, err)
+	codeHash, err := tree.GetCodeHash(ctx, contract, oldRoot)
+	require.NoError(t, err)
+
+	mockStorage := mocks.NewStorageMock(t)
+	mockExecutor := mocks.NewExecutorServiceClientMock(t)
+	testState := state.NewState(stateCfg, mockStorage, mockExecutor, tree, nil, nil, nil)
+
+	batch := &state.Batch{BatchNumber: 2, StateRoot: common.BytesToHash(newRoot), Coinbase: addr1, Timestamp: time1}
+	previousBatch := &state.Batch{BatchNumber: 1, StateRoot: common.BytesToHash(oldRoot), AccInputHash: hash1}
+	mockStorage.EXPECT().IsBatchClosed(ctx, uint64(2), nil).Return(true, nil)
+	mockStorage.EXPECT().GetForkIDByBatchNumber(uint64(2)).Return(uint64(state.FORKID_ETROG))
+	mockStorage.EXPECT().GetBatchByNumber(ctx, uint64(2), nil).Return(batch, nil)
+	mockStorage.EXPECT().GetBatchByNumber(ctx, uint64(1), nil).Return(previousBatch, nil)
+
+	// the batch is executed again to get the keys it uses
+	mockExecutor.EXPECT().ProcessBatchV2(ctx, mock.MatchedBy(func(req *executor.ProcessBatchRequestV2) bool {
+		return req.GetKeys == 1 && req.UpdateMerkleTree == 0 && common.BytesToHash(req.OldStateRoot) == common.BytesToHash(oldRoot) &&
+			req.OldBatchNum == 1 && req.ChainId == 1000 && req.TimestampLimit == uint64(time1.Unix())
+	})).Return(&executor.ProcessBatchResponseV2{
+		Error:        executor.ExecutorError_EXECUTOR_ERROR_NO_ERROR,
+		NewStateRoot: newRoot,
+		SmtKeys:      [][]byte{balanceKey},
+		ProgramKeys:  [][]byte{codeHash},
+	}, nil).Once()
+
+	witness, err := testState.GetBatchWitness(ctx, 2, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), witness.BatchNumber)
+	assert.Equal(t, uint64(1000), witness.ChainID)
+	assert.Equal(t, common.BytesToHash(oldRoot), witness.OldStateRoot)
+	assert.Equal(t, common.BytesToHash(newRoot), witness.NewStateRoot)
+	assert.Equal(t, hash1, witness.OldAccInputHash)
+	assert.Contains(t, witness.Db, strings.TrimPrefix(common.BytesToHash(oldRoot).String(), "0x"))
+	assert.Equal(t, map[string]string{hex.EncodeToString(codeHash): hex.EncodeToString(code)}, witness.ContractsBytecode)
+
+	// the witness is executed without updating the merkle tree
+	mockExecutor.EXPECT().ProcessBatchV2(ctx, mock.MatchedBy(func(req *executor.ProcessBatchRequestV2) bool {
+		return req.GetKeys == 0 && req.UpdateMerkleTree == 0 && len(req.Db) == len(witness.Db) && len(req.ContractsBytecode) == 1
+	})).Return(&executor.ProcessBatchResponseV2{Error: executor.ExecutorError_EXECUTOR_ERROR_NO_ERROR, NewStateRoot: newRoot}, nil).Once()
+
+	res, err := state.ExecuteBatchWitness(ctx, mockExecutor, witness)
+	require.NoError(t, err)
+	assert.Equal(t, newRoot, res.NewStateRoot)
+
+	// the witness isn't generated when the batch doesn't get the stored state root
+	mockExecutor.EXPECT().ProcessBatchV2(ctx, mock.Anything).Return(&executor.ProcessBatchResponseV2{Error: executor.ExecutorError_EXECUTOR_ERROR_NO_ERROR, NewStateRoot: oldRoot}, nil).Once()
+	_, err = testState.GetBatchWitness(ctx, 2, nil)
+	assert.ErrorContains(t, err, "new state root mismatch")
+}
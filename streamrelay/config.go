@@ -0,0 +1,25 @@
+package streamrelay
+
+import (
+	"github.com/0xPolygonHermez/zkevm-data-streamer/log"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+)
+
+// Config represents the configuration of the data stream relay
+type Config struct {
+	// Upstream is the address (host:port) of the data stream server to relay. It can be the stream server of the
+	// sequencer or another relay
+	Upstream string `mapstructure:"Upstream"`
+	// Port to listen on
+	Port uint16 `mapstructure:"Port"`
+	// Filename of the local binary data file
+	Filename string `mapstructure:"Filename"`
+	// Log is the log configuration
+	Log log.Config `mapstructure:"Log"`
+	// WriteTimeout is the TCP write timeout when sending data to a datastream client
+	WriteTimeout types.Duration `mapstructure:"WriteTimeout"`
+	// InactivityTimeout is the timeout to kill an inactive datastream client connection
+	InactivityTimeout types.Duration `mapstructure:"InactivityTimeout"`
+	// InactivityCheckInterval is the time interval to check for datastream client connections that have reached the inactivity timeout to kill them
+	InactivityCheckInterval types.Duration `mapstructure:"InactivityCheckInterval"`
+}
@@ -0,0 +1,17 @@
+package streamrelay
+
+import (
+	"github.com/0xPolygonHermez/zkevm-data-streamer/datastreamer"
+)
+
+// Consumer interfaces required by the package.
+
+// streamClient contains the methods required to read the upstream data stream.
+type streamClient interface {
+	Start() error
+	SetProcessEntryFunc(f datastreamer.ProcessEntryFunc)
+	ExecCommandGetHeader() (datastreamer.HeaderEntry, error)
+	ExecCommandGetEntry(fromEntry uint64) (datastreamer.FileEntry, error)
+	ExecCommandStart(fromEntry uint64) error
+	ExecCommandStartBookmark(fromBookmark []byte) error
+}
@@ -0,0 +1,270 @@
+package streamrelay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-data-streamer/datastreamer"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+const (
+	// entriesBufferSize is the number of entries received from upstream that can be pending to be relayed
+	entriesBufferSize = 1024
+	// maxEntriesPerAtomicOp is the maximum number of entries relayed in the same atomic operation
+	maxEntriesPerAtomicOp = 1000
+)
+
+var (
+	// ErrStreamDiverged is returned when an entry received from upstream isn't the next one of the local stream file
+	ErrStreamDiverged = errors.New("local stream file diverges from upstream")
+)
+
+// StreamRelay relays the data stream of an upstream stream server. It keeps a local copy of the upstream stream
+// file that is served to its own clients with the same entry numbers and bookmarks, so a relay can be the
+// upstream of another relay
+type StreamRelay struct {
+	cfg       Config
+	client    streamClient
+	server    *datastreamer.StreamServer
+	entries   chan datastreamer.FileEntry
+	nextEntry uint64
+}
+
+// New creates a data stream relay
+func New(cfg Config) (*StreamRelay, error) {
+	if cfg.Upstream == "" {
+		return nil, errors.New("upstream data stream server address is not set")
+	}
+	if cfg.Filename == "" {
+		return nil, errors.New("data stream file name is not set")
+	}
+
+	client, err := datastreamer.NewClient(cfg.Upstream, state.StreamTypeSequencer)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStreamRelay(cfg, client), nil
+}
+
+func newStreamRelay(cfg Config, client streamClient) *StreamRelay {
+	r := &StreamRelay{
+		cfg:     cfg,
+		client:  client,
+		entries: make(chan datastreamer.FileEntry, entriesBufferSize),
+	}
+	client.SetProcessEntryFunc(r.receiveEntry)
+	return r
+}
+
+// Start connects to the upstream server, resumes the local stream file from its latest bookmark and relays the
+// upstream entries until the context is done
+func (r *StreamRelay) Start(ctx context.Context) error {
+	// The client waits until it's connected, and it reconnects by itself when the connection is lost,
+	// streaming again from the next entry to receive
+	err := r.client.Start()
+	if err != nil {
+		return fmt.Errorf("failed to start upstream data stream client: %w", err)
+	}
+
+	header, err := r.client.ExecCommandGetHeader()
+	if err != nil {
+		return fmt.Errorf("failed to get upstream data stream header: %w", err)
+	}
+
+	// The local stream has the same version and chain id as the upstream one
+	r.server, err = datastreamer.NewServer(r.cfg.Port, header.Version, header.SystemID, state.StreamTypeSequencer, r.cfg.Filename,
+		r.cfg.WriteTimeout.Duration, r.cfg.InactivityTimeout.Duration, r.cfg.InactivityCheckInterval.Duration, &r.cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to create data stream server: %w", err)
+	}
+
+	bookmark, err := r.resume()
+	if err != nil {
+		return err
+	}
+
+	err = r.server.Start()
+	if err != nil {
+		return fmt.Errorf("failed to start data stream server: %w", err)
+	}
+
+	log.Infof("relaying data stream from %s, upstream total entries: %d, local total entries: %d", r.cfg.Upstream, header.TotalEntries, r.nextEntry)
+
+	if bookmark != nil {
+		err = r.client.ExecCommandStartBookmark(bookmark)
+	} else {
+		err = r.client.ExecCommandStart(r.nextEntry)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to start streaming from upstream at entry %d: %w", r.nextEntry, err)
+	}
+
+	return r.relay(ctx)
+}
+
+// resume truncates the local stream file at its latest bookmark that matches the upstream one, that is streamed
+// again from upstream along with the entries after it. This discards the entries of a partially relayed L2 block
+// or batch, and the ones after the fork point if upstream truncated its stream file while the relay was stopped
+func (r *StreamRelay) resume() ([]byte, error) {
+	totalEntries := r.server.GetHeader().TotalEntries
+	for entryNum := totalEntries; entryNum > 0; entryNum-- {
+		entry, err := r.server.GetEntry(entryNum - 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get entry %d of the local stream file: %w", entryNum-1, err)
+		}
+		if entry.Type != datastreamer.EtBookmark {
+			continue
+		}
+
+		upstreamEntry, err := r.client.ExecCommandGetEntry(entry.Number)
+		if errors.Is(err, datastreamer.ErrEntryNotFound) {
+			log.Warnf("bookmark entry %d of the local stream file not found upstream", entry.Number)
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get entry %d from upstream: %w", entry.Number, err)
+		}
+		if upstreamEntry.Type != entry.Type || !bytes.Equal(upstreamEntry.Data, entry.Data) {
+			log.Warnf("bookmark entry %d of the local stream file doesn't match the upstream one", entry.Number)
+			continue
+		}
+
+		err = r.truncate(entry.Number, totalEntries)
+		if err != nil {
+			return nil, err
+		}
+		return entry.Data, nil
+	}
+
+	// There isn't any bookmark to resume from, the stream is relayed from the first entry
+	return nil, r.truncate(0, totalEntries)
+}
+
+// truncate removes the entries of the local stream file from the entry number
+func (r *StreamRelay) truncate(entryNum uint64, totalEntries uint64) error {
+	if entryNum < totalEntries {
+		log.Infof("truncating local stream file from entry %d, total entries: %d", entryNum, totalEntries)
+		err := r.server.TruncateFile(entryNum)
+		if err != nil {
+			return fmt.Errorf("failed to truncate local stream file from entry %d: %w", entryNum, err)
+		}
+	}
+	r.nextEntry = entryNum
+	return nil
+}
+
+// receiveEntry is called by the client for each entry streamed from upstream
+func (r *StreamRelay) receiveEntry(entry *datastreamer.FileEntry, _ *datastreamer.StreamClient, _ *datastreamer.StreamServer) error {
+	r.entries <- *entry
+	return nil
+}
+
+// relay adds the entries received from upstream to the local stream file until the context is done
+func (r *StreamRelay) relay(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case entry := <-r.entries:
+			err := r.addEntries(entry)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// addEntries adds the entry and the ones already received after it in the same atomic operation, so the entries
+// that upstream commits together are usually committed together too. The entries already relayed are received again
+// after a reconnection, or when upstream truncates its stream file and adds new entries. They are compared with the
+// local ones, and the local stream file is truncated at the first one that doesn't match, so it's relayed again
+// along with the ones after it
+func (r *StreamRelay) addEntries(entry datastreamer.FileEntry) error {
+	atomicOp := false
+	startEntry := r.nextEntry
+	for count := 1; ; count++ {
+		if entry.Number < r.nextEntry {
+			// the entries of the atomic operation are committed first, so the entry can be compared and truncated
+			if atomicOp {
+				err := r.server.CommitAtomicOp()
+				if err != nil {
+					return err
+				}
+				atomicOp = false
+			}
+			err := r.checkRelayedEntry(entry)
+			if err != nil {
+				return err
+			}
+		}
+
+		if entry.Number >= r.nextEntry {
+			if !atomicOp {
+				err := r.server.StartAtomicOp()
+				if err != nil {
+					return err
+				}
+				atomicOp = true
+				startEntry = r.nextEntry
+			}
+			err := r.addEntry(entry)
+			if err != nil {
+				r.nextEntry = startEntry
+				if rollbackErr := r.server.RollbackAtomicOp(); rollbackErr != nil {
+					log.Errorf("failed to rollback atomic op, error: %v", rollbackErr)
+				}
+				return err
+			}
+		}
+
+		// relay is the only reader of the channel, so the next entry is read without blocking
+		if count == maxEntriesPerAtomicOp || len(r.entries) == 0 {
+			break
+		}
+		entry = <-r.entries
+	}
+
+	if !atomicOp {
+		return nil
+	}
+	return r.server.CommitAtomicOp()
+}
+
+// checkRelayedEntry compares an entry received again from upstream with the local one. If they don't match upstream
+// has truncated its stream file, and the local one is truncated at the entry too
+func (r *StreamRelay) checkRelayedEntry(entry datastreamer.FileEntry) error {
+	localEntry, err := r.server.GetEntry(entry.Number)
+	if err != nil {
+		return fmt.Errorf("failed to get entry %d of the local stream file: %w", entry.Number, err)
+	}
+	if localEntry.Type == entry.Type && bytes.Equal(localEntry.Data, entry.Data) {
+		return nil
+	}
+
+	log.Warnf("entry %d received again from upstream doesn't match the local one, upstream has truncated its stream file", entry.Number)
+	return r.truncate(entry.Number, r.server.GetHeader().TotalEntries)
+}
+
+// addEntry adds the next entry received from upstream to the local stream file
+func (r *StreamRelay) addEntry(entry datastreamer.FileEntry) error {
+	if entry.Number != r.nextEntry {
+		return fmt.Errorf("%w, entry %d received from upstream but expected entry %d", ErrStreamDiverged, entry.Number, r.nextEntry)
+	}
+
+	var err error
+	if entry.Type == datastreamer.EtBookmark {
+		_, err = r.server.AddStreamBookmark(entry.Data)
+	} else {
+		_, err = r.server.AddStreamEntry(entry.Type, entry.Data)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add entry %d to the local stream file: %w", entry.Number, err)
+	}
+
+	r.nextEntry++
+	return nil
+}
@@ -0,0 +1,275 @@
+package streamrelay
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-data-streamer/datastreamer"
+	dslog "github.com/0xPolygonHermez/zkevm-data-streamer/log"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	entryTypeData datastreamer.EntryType = 2
+	chainID       uint64                 = 1440
+	version       uint8                  = 4
+)
+
+var logCfg = dslog.Config{
+	Environment: dslog.EnvironmentDevelopment,
+	Level:       "error",
+	Outputs:     []string{"stderr"},
+}
+
+type testEntry struct {
+	bookmark bool
+	data     string
+}
+
+func freePort(t *testing.T) uint16 {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	return uint16(ln.Addr().(*net.TCPAddr).Port)
+}
+
+func newTestConfig(t *testing.T, upstream string) Config {
+	return Config{
+		Upstream:                upstream,
+		Port:                    freePort(t),
+		Filename:                filepath.Join(t.TempDir(), "relay.bin"),
+		Log:                     logCfg,
+		WriteTimeout:            types.NewDuration(5 * time.Second),
+		InactivityTimeout:       types.NewDuration(time.Minute),
+		InactivityCheckInterval: types.NewDuration(5 * time.Second),
+	}
+}
+
+func newTestServer(t *testing.T, port uint16, entries ...testEntry) *datastreamer.StreamServer {
+	s, err := datastreamer.NewServer(port, version, chainID, state.StreamTypeSequencer, filepath.Join(t.TempDir(), "stream.bin"),
+		5*time.Second, time.Minute, 5*time.Second, nil)
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	addTestEntries(t, s, entries...)
+	return s
+}
+
+func addTestEntries(t *testing.T, s *datastreamer.StreamServer, entries ...testEntry) {
+	require.NoError(t, s.StartAtomicOp())
+	for _, e := range entries {
+		var err error
+		if e.bookmark {
+			_, err = s.AddStreamBookmark([]byte(e.data))
+		} else {
+			_, err = s.AddStreamEntry(entryTypeData, []byte(e.data))
+		}
+		require.NoError(t, err)
+	}
+	require.NoError(t, s.CommitAtomicOp())
+}
+
+func requireSameEntries(t *testing.T, expected, actual *datastreamer.StreamServer) {
+	require.Equal(t, expected.GetHeader().TotalEntries, actual.GetHeader().TotalEntries)
+	for i := uint64(0); i < expected.GetHeader().TotalEntries; i++ {
+		expectedEntry, err := expected.GetEntry(i)
+		require.NoError(t, err)
+		actualEntry, err := actual.GetEntry(i)
+		require.NoError(t, err)
+		assert.Equal(t, expectedEntry.Type, actualEntry.Type)
+		assert.Equal(t, expectedEntry.Data, actualEntry.Data)
+	}
+}
+
+func TestStreamRelayResume(t *testing.T) {
+	b2 := testEntry{bookmark: true, data: "b2"}
+	localEntries := []testEntry{{bookmark: true, data: "b1"}, {data: "d1"}, {data: "d2"}, b2, {data: "d3"}}
+
+	testCases := []struct {
+		name                 string
+		entries              []testEntry
+		upstreamEntries      map[uint64]datastreamer.FileEntry
+		expectedBookmark     []byte
+		expectedTotalEntries uint64
+	}{
+		{
+			name:                 "truncated at the latest bookmark",
+			entries:              localEntries,
+			upstreamEntries:      map[uint64]datastreamer.FileEntry{3: {Number: 3, Type: datastreamer.EtBookmark, Data: []byte("b2")}},
+			expectedBookmark:     []byte("b2"),
+			expectedTotalEntries: 3,
+		},
+		{
+			name:                 "without bookmarks",
+			entries:              []testEntry{{data: "d1"}},
+			expectedTotalEntries: 0,
+		},
+		{
+			name:    "bookmark with other data upstream",
+			entries: localEntries,
+			upstreamEntries: map[uint64]datastreamer.FileEntry{
+				3: {Number: 3, Type: entryTypeData, Data: []byte("d3")},
+				0: {Number: 0, Type: datastreamer.EtBookmark, Data: []byte("b1")},
+			},
+			expectedBookmark:     []byte("b1"),
+			expectedTotalEntries: 0,
+		},
+		{
+			name:                 "bookmarks not found upstream",
+			entries:              localEntries,
+			upstreamEntries:      map[uint64]datastreamer.FileEntry{},
+			expectedTotalEntries: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewStreamClientMock(t)
+			client.On("SetProcessEntryFunc", mock.Anything).Once()
+			if tc.upstreamEntries != nil {
+				// the bookmarks are checked from the latest one until one matches
+				for _, entryNum := range []uint64{3, 0} {
+					upstreamEntry, found := tc.upstreamEntries[entryNum]
+					if found {
+						client.On("ExecCommandGetEntry", entryNum).Return(upstreamEntry, nil).Once()
+					} else {
+						client.On("ExecCommandGetEntry", entryNum).Return(datastreamer.FileEntry{}, datastreamer.ErrEntryNotFound).Once()
+					}
+					if found && upstreamEntry.Type == datastreamer.EtBookmark {
+						break
+					}
+				}
+			}
+
+			r := newStreamRelay(newTestConfig(t, "upstream"), client)
+			r.server = newTestServer(t, freePort(t), tc.entries...)
+
+			bookmark, err := r.resume()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedBookmark, bookmark)
+			assert.Equal(t, tc.expectedTotalEntries, r.server.GetHeader().TotalEntries)
+			assert.Equal(t, tc.expectedTotalEntries, r.nextEntry)
+		})
+	}
+}
+
+func TestStreamRelayAddEntries(t *testing.T) {
+	client := NewStreamClientMock(t)
+	client.On("SetProcessEntryFunc", mock.Anything).Once()
+	r := newStreamRelay(newTestConfig(t, "upstream"), client)
+	r.server = newTestServer(t, freePort(t), testEntry{bookmark: true, data: "b1"}, testEntry{data: "d1"})
+	r.nextEntry = 2
+
+	// the entries received together are added in the same atomic operation, ignoring the ones already relayed
+	r.entries <- datastreamer.FileEntry{Number: 2, Type: entryTypeData, Data: []byte("d2")}
+	r.entries <- datastreamer.FileEntry{Number: 3, Type: datastreamer.EtBookmark, Data: []byte("b2")}
+	err := r.addEntries(datastreamer.FileEntry{Number: 1, Type: entryTypeData, Data: []byte("d1")})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), r.server.GetHeader().TotalEntries)
+	assert.Equal(t, uint64(4), r.nextEntry)
+	entryNum, err := r.server.GetBookmark([]byte("b2"))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), entryNum)
+
+	// the local stream file is truncated at the first entry received again that doesn't match the local one
+	r.entries <- datastreamer.FileEntry{Number: 2, Type: entryTypeData, Data: []byte("d2")}
+	r.entries <- datastreamer.FileEntry{Number: 3, Type: entryTypeData, Data: []byte("d3")}
+	err = r.addEntries(datastreamer.FileEntry{Number: 4, Type: entryTypeData, Data: []byte("d4")})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), r.server.GetHeader().TotalEntries)
+	assert.Equal(t, uint64(4), r.nextEntry)
+	entry, err := r.server.GetEntry(3)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("d3"), entry.Data)
+
+	// the atomic operation is rolled back if an entry is missing
+	r.entries <- datastreamer.FileEntry{Number: 6, Type: entryTypeData, Data: []byte("d5")}
+	err = r.addEntries(datastreamer.FileEntry{Number: 4, Type: entryTypeData, Data: []byte("d4")})
+	assert.ErrorIs(t, err, ErrStreamDiverged)
+	assert.Equal(t, uint64(4), r.server.GetHeader().TotalEntries)
+	assert.Equal(t, uint64(4), r.nextEntry)
+}
+
+func waitForTotalEntries(t *testing.T, port uint16, totalEntries uint64) {
+	client, err := datastreamer.NewClient(net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port))), state.StreamTypeSequencer)
+	require.NoError(t, err)
+	require.NoError(t, client.Start())
+	require.Eventually(t, func() bool {
+		header, err := client.ExecCommandGetHeader()
+		return err == nil && header.TotalEntries == totalEntries
+	}, 20*time.Second, 50*time.Millisecond)
+}
+
+func TestStreamRelayChained(t *testing.T) {
+	// the servers and clients of the data stream library have data races between their own goroutines
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	upstreamPort := freePort(t)
+	upstream := newTestServer(t, upstreamPort, testEntry{bookmark: true, data: "b1"}, testEntry{data: "d1"})
+
+	relay1, err := New(newTestConfig(t, net.JoinHostPort("127.0.0.1", strconv.Itoa(int(upstreamPort)))))
+	require.NoError(t, err)
+	relay1Errs := make(chan error, 1)
+	go func() { relay1Errs <- relay1.Start(ctx) }()
+
+	// a relay can relay the stream of another relay
+	relay2, err := New(newTestConfig(t, net.JoinHostPort("127.0.0.1", strconv.Itoa(int(relay1.cfg.Port)))))
+	require.NoError(t, err)
+	relay2Errs := make(chan error, 1)
+	go func() { relay2Errs <- relay2.Start(ctx) }()
+
+	waitForTotalEntries(t, relay2.cfg.Port, 2)
+	addTestEntries(t, upstream, testEntry{bookmark: true, data: "b2"}, testEntry{data: "d2"}, testEntry{data: "d3"})
+	waitForTotalEntries(t, relay2.cfg.Port, 5)
+
+	cancel()
+	require.NoError(t, <-relay1Errs)
+	require.NoError(t, <-relay2Errs)
+
+	requireSameEntries(t, upstream, relay1.server)
+	requireSameEntries(t, upstream, relay2.server)
+	assert.Equal(t, version, relay2.server.GetHeader().Version)
+	assert.Equal(t, chainID, relay2.server.GetHeader().SystemID)
+}
+
+func TestStreamRelayUpstreamTruncated(t *testing.T) {
+	// the servers and clients of the data stream library have data races between their own goroutines
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	upstreamPort := freePort(t)
+	upstream := newTestServer(t, upstreamPort, testEntry{bookmark: true, data: "b1"}, testEntry{data: "d1"},
+		testEntry{bookmark: true, data: "b2"}, testEntry{data: "d2"}, testEntry{data: "d3"})
+
+	relay, err := New(newTestConfig(t, net.JoinHostPort("127.0.0.1", strconv.Itoa(int(upstreamPort)))))
+	require.NoError(t, err)
+	relayErrs := make(chan error, 1)
+	go func() { relayErrs <- relay.Start(ctx) }()
+	waitForTotalEntries(t, relay.cfg.Port, 5)
+
+	// upstream truncates its stream file in the middle of the L2 block and adds other entries
+	require.NoError(t, upstream.TruncateFile(3))
+	addTestEntries(t, upstream, testEntry{data: "d4"}, testEntry{bookmark: true, data: "b3"}, testEntry{data: "d5"})
+	require.Eventually(t, func() bool {
+		entry, err := relay.server.GetEntry(3)
+		return err == nil && string(entry.Data) == "d4" && relay.server.GetHeader().TotalEntries == 6
+	}, 20*time.Second, 50*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-relayErrs)
+	requireSameEntries(t, upstream, relay.server)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/db"
 	"github.com/0xPolygonHermez/zkevm-node/synchronizer/l2_sync"
 )
 
@@ -33,6 +34,30 @@ type Config struct {
 	L1ParallelSynchronization L1ParallelSynchronizationConfig
 	// L2Synchronization Configuration for L2 synchronization
 	L2Synchronization l2_sync.Config `mapstructure:"L2Synchronization"`
+	// L1RecordFile if it is set, the data read from L1 by the synchronizer is recorded into this file,
+	// so the synchronization can be replayed later without access to L1 (see synchronizer/l1_replay)
+	L1RecordFile string `mapstructure:"L1RecordFile"`
+	// MultiRollup Configuration to synchronize other rollups of the same rollup manager in this instance
+	MultiRollup MultiRollupConfig `mapstructure:"MultiRollup"`
+}
+
+// MultiRollupConfig Configuration to synchronize several rollups attached to the same rollup manager.
+// The L1 logs of all the rollups are read once and shared by their synchronizers
+type MultiRollupConfig struct {
+	// Rollups are the rollups synchronized in addition to the one of the network config
+	Rollups []RollupConfig `mapstructure:"Rollups"`
+	// MaxCachedL1Blocks is the number of finalized L1 blocks whose logs are kept in memory to be shared by the rollups
+	MaxCachedL1Blocks uint64 `mapstructure:"MaxCachedL1Blocks"`
+}
+
+// RollupConfig Configuration of an additional rollup to synchronize
+type RollupConfig struct {
+	// NetworkConfigFile is the path of the network config (genesis) file of the rollup
+	NetworkConfigFile string `mapstructure:"NetworkConfigFile"`
+	// TrustedSequencerURL is the rpc url to sync the trusted state of the rollup. If it is empty, it's read from the smc
+	TrustedSequencerURL string `mapstructure:"TrustedSequencerURL"`
+	// StateDB is the state database of the rollup, it must be different from the one of the other rollups
+	StateDB db.Config `mapstructure:"StateDB"`
 }
 
 // L1BlockCheckConfig Configuration for L1 Block Checker
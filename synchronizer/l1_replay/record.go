@@ -0,0 +1,116 @@
+package l1_replay
+
+import (
+	"encoding/gob"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const logPrefix = "l1replay:"
+
+// Method identifies the etherman method that produced a record
+type Method string
+
+const (
+	// MethodRollupInfo is GetRollupInfoByBlockRange
+	MethodRollupInfo Method = "GetRollupInfoByBlockRange"
+	// MethodRollupInfoPreviousRollupGenesis is GetRollupInfoByBlockRangePreviousRollupGenesis
+	MethodRollupInfoPreviousRollupGenesis Method = "GetRollupInfoByBlockRangePreviousRollupGenesis"
+	// MethodHeader is HeaderByNumber and EthBlockByNumber, only the header of the block is recorded
+	MethodHeader Method = "HeaderByNumber"
+	// MethodFinalizedBlockNumber is GetFinalizedBlockNumber
+	MethodFinalizedBlockNumber Method = "GetFinalizedBlockNumber"
+	// MethodTrustedSequencerURL is GetTrustedSequencerURL
+	MethodTrustedSequencerURL Method = "GetTrustedSequencerURL"
+	// MethodVerifyGenBlockNumber is VerifyGenBlockNumber
+	MethodVerifyGenBlockNumber Method = "VerifyGenBlockNumber"
+	// MethodLatestVerifiedBatchNum is GetLatestVerifiedBatchNum
+	MethodLatestVerifiedBatchNum Method = "GetLatestVerifiedBatchNum"
+	// MethodLatestBatchNumber is GetLatestBatchNumber
+	MethodLatestBatchNumber Method = "GetLatestBatchNumber"
+	// MethodL1BlockUpgradeLxLy is GetL1BlockUpgradeLxLy
+	MethodL1BlockUpgradeLxLy Method = "GetL1BlockUpgradeLxLy"
+	// MethodForks is GetForks
+	MethodForks Method = "GetForks"
+)
+
+// Record is the result of a call to L1. Only the fields used by the method are set
+type Record struct {
+	Method Method
+	// FromBlock and ToBlock are the range requested to GetRollupInfoByBlockRange
+	FromBlock uint64
+	ToBlock   *uint64
+	// Number is the block number requested to HeaderByNumber, nil for the latest block.
+	// Negative values are the rpc tags (safe, finalized, ...)
+	Number *big.Int
+	Blocks []etherman.Block
+	Order  map[common.Hash][]etherman.Order
+	Header *ethTypes.Header
+	Uint   uint64
+	Bool   bool
+	String string
+	Forks  []state.ForkIDInterval
+	// NotFound is set if the method returned etherman.ErrNotFound
+	NotFound bool
+}
+
+// Writer appends records to a file. It can be shared by several recorders
+type Writer struct {
+	mutex   sync.Mutex
+	file    *os.File
+	encoder *gob.Encoder
+}
+
+// NewWriter creates a writer that appends the records to the file located in path.
+// The file is truncated if it already exists
+func NewWriter(path string) (*Writer, error) {
+	file, err := os.Create(path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{file: file, encoder: gob.NewEncoder(file)}, nil
+}
+
+// Write appends a record to the file
+func (w *Writer) Write(r *Record) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.encoder.Encode(r)
+}
+
+// Close closes the file
+func (w *Writer) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.file.Close()
+}
+
+// ReadRecords reads all the records of a file written by a Writer, in the order they were written.
+// A truncated last record, written while the node was stopped, is ignored
+func ReadRecords(path string) ([]Record, error) {
+	file, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+	decoder := gob.NewDecoder(file)
+	records := []Record{}
+	for {
+		var r Record
+		err := decoder.Decode(&r)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return records, nil
+		} else if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+}
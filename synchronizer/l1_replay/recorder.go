@@ -0,0 +1,155 @@
+package l1_replay
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/synchronizer/common/syncinterfaces"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// EthermanInterface is the etherman recorded and replayed
+type EthermanInterface interface {
+	syncinterfaces.EthermanFullInterface
+	GetForks(ctx context.Context, genBlockNumber uint64, lastL1BlockSynced uint64) ([]state.ForkIDInterval, error)
+}
+
+// Recorder is an etherman that writes the results returned by another etherman, so they
+// can be replayed later by ReplayEtherman. Failed calls are not recorded
+type Recorder struct {
+	etherman EthermanInterface
+	writer   *Writer
+}
+
+// NewRecorder creates a Recorder that records the calls to etherman into writer
+func NewRecorder(etherman EthermanInterface, writer *Writer) *Recorder {
+	return &Recorder{etherman: etherman, writer: writer}
+}
+
+func (r *Recorder) write(record *Record) {
+	if err := r.writer.Write(record); err != nil {
+		log.Errorf("%s error recording %s. Error: %v", logPrefix, record.Method, err)
+	}
+}
+
+// HeaderByNumber returns the header of a block
+func (r *Recorder) HeaderByNumber(ctx context.Context, number *big.Int) (*ethTypes.Header, error) {
+	header, err := r.etherman.HeaderByNumber(ctx, number)
+	if err == nil && header != nil {
+		r.write(&Record{Method: MethodHeader, Number: number, Header: header})
+	}
+	return header, err
+}
+
+// EthBlockByNumber returns a block. Only its header is recorded
+func (r *Recorder) EthBlockByNumber(ctx context.Context, blockNumber uint64) (*ethTypes.Block, error) {
+	block, err := r.etherman.EthBlockByNumber(ctx, blockNumber)
+	if err == nil && block != nil {
+		r.write(&Record{Method: MethodHeader, Number: new(big.Int).SetUint64(blockNumber), Header: block.Header()})
+	}
+	return block, err
+}
+
+// GetRollupInfoByBlockRange returns the rollup info of a range of blocks. The headers of the blocks
+// returned are recorded too, the replay needs them to build the chain
+func (r *Recorder) GetRollupInfoByBlockRange(ctx context.Context, fromBlock uint64, toBlock *uint64) ([]etherman.Block, map[common.Hash][]etherman.Order, error) {
+	blocks, order, err := r.etherman.GetRollupInfoByBlockRange(ctx, fromBlock, toBlock)
+	if err == nil {
+		r.writeRollupInfo(ctx, MethodRollupInfo, fromBlock, toBlock, blocks, order)
+	}
+	return blocks, order, err
+}
+
+// GetRollupInfoByBlockRangePreviousRollupGenesis returns the rollup info of a range of blocks previous to the rollup genesis
+func (r *Recorder) GetRollupInfoByBlockRangePreviousRollupGenesis(ctx context.Context, fromBlock uint64, toBlock *uint64) ([]etherman.Block, map[common.Hash][]etherman.Order, error) {
+	blocks, order, err := r.etherman.GetRollupInfoByBlockRangePreviousRollupGenesis(ctx, fromBlock, toBlock)
+	if err == nil {
+		r.writeRollupInfo(ctx, MethodRollupInfoPreviousRollupGenesis, fromBlock, toBlock, blocks, order)
+	}
+	return blocks, order, err
+}
+
+func (r *Recorder) writeRollupInfo(ctx context.Context, method Method, fromBlock uint64, toBlock *uint64, blocks []etherman.Block, order map[common.Hash][]etherman.Order) {
+	r.write(&Record{Method: method, FromBlock: fromBlock, ToBlock: toBlock, Blocks: blocks, Order: order})
+	for i := range blocks {
+		header, err := r.etherman.HeaderByNumber(ctx, new(big.Int).SetUint64(blocks[i].BlockNumber))
+		if err != nil {
+			log.Warnf("%s error getting the header of block %d. Error: %v", logPrefix, blocks[i].BlockNumber, err)
+			continue
+		}
+		// The block can be reorged between both calls, the header of the new block is not useful
+		if header.Hash() != blocks[i].BlockHash {
+			log.Warnf("%s block %d has been reorged while recording it", logPrefix, blocks[i].BlockNumber)
+			continue
+		}
+		r.write(&Record{Method: MethodHeader, Number: header.Number, Header: header})
+	}
+}
+
+// GetFinalizedBlockNumber returns the number of the finalized block
+func (r *Recorder) GetFinalizedBlockNumber(ctx context.Context) (uint64, error) {
+	blockNumber, err := r.etherman.GetFinalizedBlockNumber(ctx)
+	if err == nil {
+		r.write(&Record{Method: MethodFinalizedBlockNumber, Uint: blockNumber})
+	}
+	return blockNumber, err
+}
+
+// GetTrustedSequencerURL returns the url of the trusted sequencer
+func (r *Recorder) GetTrustedSequencerURL() (string, error) {
+	url, err := r.etherman.GetTrustedSequencerURL()
+	if err == nil {
+		r.write(&Record{Method: MethodTrustedSequencerURL, String: url})
+	}
+	return url, err
+}
+
+// VerifyGenBlockNumber checks the genesis block number
+func (r *Recorder) VerifyGenBlockNumber(ctx context.Context, genBlockNumber uint64) (bool, error) {
+	valid, err := r.etherman.VerifyGenBlockNumber(ctx, genBlockNumber)
+	if err == nil {
+		r.write(&Record{Method: MethodVerifyGenBlockNumber, Uint: genBlockNumber, Bool: valid})
+	}
+	return valid, err
+}
+
+// GetLatestVerifiedBatchNum returns the last verified batch
+func (r *Recorder) GetLatestVerifiedBatchNum() (uint64, error) {
+	batchNumber, err := r.etherman.GetLatestVerifiedBatchNum()
+	if err == nil {
+		r.write(&Record{Method: MethodLatestVerifiedBatchNum, Uint: batchNumber})
+	}
+	return batchNumber, err
+}
+
+// GetLatestBatchNumber returns the last sequenced batch
+func (r *Recorder) GetLatestBatchNumber() (uint64, error) {
+	batchNumber, err := r.etherman.GetLatestBatchNumber()
+	if err == nil {
+		r.write(&Record{Method: MethodLatestBatchNumber, Uint: batchNumber})
+	}
+	return batchNumber, err
+}
+
+// GetL1BlockUpgradeLxLy returns the L1 block where the rollup was upgraded to LxLy
+func (r *Recorder) GetL1BlockUpgradeLxLy(ctx context.Context, genesisBlock uint64) (uint64, error) {
+	blockNumber, err := r.etherman.GetL1BlockUpgradeLxLy(ctx, genesisBlock)
+	if err == nil || errors.Is(err, etherman.ErrNotFound) {
+		r.write(&Record{Method: MethodL1BlockUpgradeLxLy, FromBlock: genesisBlock, Uint: blockNumber, NotFound: err != nil})
+	}
+	return blockNumber, err
+}
+
+// GetForks returns the fork ids of the rollup
+func (r *Recorder) GetForks(ctx context.Context, genBlockNumber uint64, lastL1BlockSynced uint64) ([]state.ForkIDInterval, error) {
+	forks, err := r.etherman.GetForks(ctx, genBlockNumber, lastL1BlockSynced)
+	if err == nil {
+		r.write(&Record{Method: MethodForks, FromBlock: genBlockNumber, Uint: lastL1BlockSynced, Forks: forks})
+	}
+	return forks, err
+}
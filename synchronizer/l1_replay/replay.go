@@ -0,0 +1,377 @@
+package l1_replay
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Reorg is a L1 reorg injected by the replay
+type Reorg struct {
+	// BlockNumber is the first block replaced by the reorg
+	BlockNumber uint64
+	// TriggerBlock is the block that triggers the reorg: it happens once the rollup info up
+	// to this block has been returned. It must be greater or equal than BlockNumber
+	TriggerBlock uint64
+	// DropEvents removes the rollup events of the replaced blocks from the new chain.
+	// Otherwise the new blocks contain the same events with different hashes
+	DropEvents bool
+}
+
+// chain is the view of L1 returned by the replay at some point
+type chain struct {
+	blocks          map[uint64]etherman.Block
+	orders          map[uint64][]etherman.Order
+	preRollupBlocks map[uint64]etherman.Block
+	preRollupOrders map[uint64][]etherman.Order
+	headers         map[uint64]*ethTypes.Header
+	// tags are the block numbers returned for the latest, safe and finalized blocks
+	tags map[int64]uint64
+	// lastReadBlock is the highest block read by the synchronizer while this chain was recorded.
+	// The replay moves to the next chain once it's read
+	lastReadBlock uint64
+}
+
+func newChain() *chain {
+	return &chain{
+		blocks:          map[uint64]etherman.Block{},
+		orders:          map[uint64][]etherman.Order{},
+		preRollupBlocks: map[uint64]etherman.Block{},
+		preRollupOrders: map[uint64][]etherman.Order{},
+		headers:         map[uint64]*ethTypes.Header{},
+		tags:            map[int64]uint64{},
+	}
+}
+
+// copy returns a copy of the chain without the blocks greater or equal than fromBlock
+func (c *chain) copy(fromBlock uint64) *chain {
+	res := newChain()
+	for n, b := range c.blocks {
+		if n < fromBlock {
+			res.blocks[n] = b
+			res.orders[n] = c.orders[n]
+		}
+	}
+	for n, b := range c.preRollupBlocks {
+		if n < fromBlock {
+			res.preRollupBlocks[n] = b
+			res.preRollupOrders[n] = c.preRollupOrders[n]
+		}
+	}
+	for n, h := range c.headers {
+		if n < fromBlock {
+			res.headers[n] = h
+		}
+	}
+	for tag, n := range c.tags {
+		res.tags[tag] = n
+	}
+	return res
+}
+
+// hash returns the hash of a block of the chain and false if the chain doesn't contain it
+func (c *chain) hash(blockNumber uint64) (common.Hash, bool) {
+	if h, ok := c.headers[blockNumber]; ok {
+		return h.Hash(), true
+	}
+	if b, ok := c.blocks[blockNumber]; ok {
+		return b.BlockHash, true
+	}
+	if b, ok := c.preRollupBlocks[blockNumber]; ok {
+		return b.BlockHash, true
+	}
+	return common.Hash{}, false
+}
+
+// ReplayEtherman is an etherman that returns the L1 data recorded by a Recorder, so the
+// synchronization of a real run can be reproduced without access to L1. The rollup info is
+// returned per block, so it doesn't depend on the ranges requested. L1 reorgs seen while
+// recording are replayed once the synchronizer has read the blocks that were reorged and
+// additional reorgs can be injected at any block
+type ReplayEtherman struct {
+	mutex   sync.Mutex
+	chains  []*chain
+	current int
+	reorgs  []Reorg
+	values  map[Method]Record
+}
+
+// LoadReplayEtherman creates a ReplayEtherman from the records of a file written by a Recorder
+func LoadReplayEtherman(path string, reorgs ...Reorg) (*ReplayEtherman, error) {
+	records, err := ReadRecords(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayEtherman(records, reorgs...)
+}
+
+// NewReplayEtherman creates a ReplayEtherman from records, injecting reorgs
+func NewReplayEtherman(records []Record, reorgs ...Reorg) (*ReplayEtherman, error) {
+	for _, reorg := range reorgs {
+		if reorg.TriggerBlock < reorg.BlockNumber {
+			return nil, fmt.Errorf("reorg of block %d can't be triggered by the previous block %d", reorg.BlockNumber, reorg.TriggerBlock)
+		}
+	}
+	r := &ReplayEtherman{
+		chains: []*chain{newChain()},
+		reorgs: append([]Reorg{}, reorgs...),
+		values: map[Method]Record{},
+	}
+	sort.Slice(r.reorgs, func(i, j int) bool { return r.reorgs[i].TriggerBlock < r.reorgs[j].TriggerBlock })
+	for i := range records {
+		r.load(&records[i])
+	}
+	log.Infof("%s loaded %d records, %d L1 reorgs recorded, %d L1 reorgs injected", logPrefix, len(records), len(r.chains)-1, len(r.reorgs))
+	return r, nil
+}
+
+// load adds a record to the last chain, creating a new one if the record contradicts it
+func (r *ReplayEtherman) load(record *Record) {
+	switch record.Method {
+	case MethodRollupInfo, MethodRollupInfoPreviousRollupGenesis:
+		for _, b := range record.Blocks {
+			r.checkReorgRecorded(b.BlockNumber, b.BlockHash)
+		}
+		c := r.chains[len(r.chains)-1]
+		blocks, orders := c.blocks, c.orders
+		if record.Method == MethodRollupInfoPreviousRollupGenesis {
+			blocks, orders = c.preRollupBlocks, c.preRollupOrders
+		}
+		for _, b := range record.Blocks {
+			blocks[b.BlockNumber] = b
+			orders[b.BlockNumber] = record.Order[b.BlockHash]
+		}
+		if record.ToBlock != nil && *record.ToBlock > c.lastReadBlock {
+			c.lastReadBlock = *record.ToBlock
+		}
+	case MethodHeader:
+		blockNumber := record.Header.Number.Uint64()
+		r.checkReorgRecorded(blockNumber, record.Header.Hash())
+		c := r.chains[len(r.chains)-1]
+		c.headers[blockNumber] = record.Header
+		if record.Number == nil {
+			c.tags[int64(rpc.LatestBlockNumber)] = blockNumber
+		} else if record.Number.Sign() < 0 {
+			c.tags[record.Number.Int64()] = blockNumber
+		}
+	case MethodFinalizedBlockNumber:
+		r.chains[len(r.chains)-1].tags[int64(rpc.FinalizedBlockNumber)] = record.Uint
+	default:
+		r.values[record.Method] = *record
+	}
+}
+
+// checkReorgRecorded starts a new chain if the block recorded is different from the one of the last chain
+func (r *ReplayEtherman) checkReorgRecorded(blockNumber uint64, hash common.Hash) {
+	c := r.chains[len(r.chains)-1]
+	if h, ok := c.hash(blockNumber); ok && h != hash {
+		log.Debugf("%s L1 reorg of block %d recorded", logPrefix, blockNumber)
+		r.chains = append(r.chains, c.copy(blockNumber))
+	}
+}
+
+func (r *ReplayEtherman) chain() *chain {
+	return r.chains[r.current]
+}
+
+// blockRead moves to the next chain or injects the reorgs triggered by reading up to toBlock
+func (r *ReplayEtherman) blockRead(toBlock uint64) {
+	if r.current < len(r.chains)-1 && toBlock >= r.chain().lastReadBlock {
+		r.current++
+		log.Infof("%s replaying the L1 reorg recorded after block %d", logPrefix, toBlock)
+	}
+	for len(r.reorgs) > 0 && toBlock >= r.reorgs[0].TriggerBlock {
+		r.injectReorg(r.reorgs[0])
+		r.reorgs = r.reorgs[1:]
+	}
+}
+
+// injectReorg replaces the blocks of all the chains from reorg.BlockNumber by new blocks
+func (r *ReplayEtherman) injectReorg(reorg Reorg) {
+	log.Infof("%s injecting L1 reorg from block %d", logPrefix, reorg.BlockNumber)
+	for _, c := range r.chains[r.current:] {
+		numbers := []uint64{}
+		for n := range c.headers {
+			if n >= reorg.BlockNumber {
+				numbers = append(numbers, n)
+			}
+		}
+		sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+		// newHashes maps the hashes of the replaced headers to the new ones to link their children
+		newHashes := map[common.Hash]common.Hash{}
+		for _, n := range numbers {
+			oldHash := c.headers[n].Hash()
+			header := ethTypes.CopyHeader(c.headers[n])
+			header.Extra = append(header.Extra, []byte(fmt.Sprintf("reorg%d", reorg.BlockNumber))...)
+			if parentHash, ok := newHashes[header.ParentHash]; ok {
+				header.ParentHash = parentHash
+			}
+			c.headers[n] = header
+			newHashes[oldHash] = header.Hash()
+		}
+		for _, blocks := range []map[uint64]etherman.Block{c.blocks, c.preRollupBlocks} {
+			for n, b := range blocks {
+				if n < reorg.BlockNumber {
+					continue
+				}
+				if reorg.DropEvents {
+					delete(blocks, n)
+					continue
+				}
+				if h, ok := c.headers[n]; ok {
+					b.BlockHash = h.Hash()
+					b.ParentHash = h.ParentHash
+				} else {
+					b.BlockHash = crypto.Keccak256Hash(b.BlockHash.Bytes(), []byte(fmt.Sprintf("reorg%d", reorg.BlockNumber)))
+				}
+				blocks[n] = b
+			}
+		}
+	}
+}
+
+func (r *ReplayEtherman) rollupInfo(blocks map[uint64]etherman.Block, orders map[uint64][]etherman.Order, fromBlock uint64, toBlock *uint64) ([]etherman.Block, map[common.Hash][]etherman.Order) {
+	numbers := []uint64{}
+	for n := range blocks {
+		if n >= fromBlock && (toBlock == nil || n <= *toBlock) {
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	res := []etherman.Block{}
+	order := map[common.Hash][]etherman.Order{}
+	for _, n := range numbers {
+		res = append(res, blocks[n])
+		order[blocks[n].BlockHash] = orders[n]
+	}
+	return res, order
+}
+
+// GetRollupInfoByBlockRange returns the rollup info of the blocks recorded in the range
+func (r *ReplayEtherman) GetRollupInfoByBlockRange(ctx context.Context, fromBlock uint64, toBlock *uint64) ([]etherman.Block, map[common.Hash][]etherman.Order, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	blocks, order := r.rollupInfo(r.chain().blocks, r.chain().orders, fromBlock, toBlock)
+	if toBlock != nil {
+		r.blockRead(*toBlock)
+	}
+	return blocks, order, nil
+}
+
+// GetRollupInfoByBlockRangePreviousRollupGenesis returns the rollup info previous to the rollup genesis of the blocks recorded in the range
+func (r *ReplayEtherman) GetRollupInfoByBlockRangePreviousRollupGenesis(ctx context.Context, fromBlock uint64, toBlock *uint64) ([]etherman.Block, map[common.Hash][]etherman.Order, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	blocks, order := r.rollupInfo(r.chain().preRollupBlocks, r.chain().preRollupOrders, fromBlock, toBlock)
+	if toBlock != nil {
+		r.blockRead(*toBlock)
+	}
+	return blocks, order, nil
+}
+
+// HeaderByNumber returns a recorded header. The latest, safe and finalized tags return the
+// last header recorded for them
+func (r *ReplayEtherman) HeaderByNumber(ctx context.Context, number *big.Int) (*ethTypes.Header, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	c := r.chain()
+	var blockNumber uint64
+	if number == nil || number.Sign() < 0 {
+		tag := int64(rpc.LatestBlockNumber)
+		if number != nil {
+			tag = number.Int64()
+		}
+		n, ok := c.tags[tag]
+		if !ok {
+			// The latest block is used for the tags that were not recorded
+			n, ok = c.tags[int64(rpc.LatestBlockNumber)]
+		}
+		if !ok {
+			return nil, ethereum.NotFound
+		}
+		blockNumber = n
+	} else {
+		blockNumber = number.Uint64()
+	}
+	header, ok := c.headers[blockNumber]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return ethTypes.CopyHeader(header), nil
+}
+
+// EthBlockByNumber returns a block with the recorded header and no transactions
+func (r *ReplayEtherman) EthBlockByNumber(ctx context.Context, blockNumber uint64) (*ethTypes.Block, error) {
+	header, err := r.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return nil, etherman.ErrNotFound
+	}
+	return ethTypes.NewBlockWithHeader(header), nil
+}
+
+// GetFinalizedBlockNumber returns the last finalized block recorded
+func (r *ReplayEtherman) GetFinalizedBlockNumber(ctx context.Context) (uint64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if n, ok := r.chain().tags[int64(rpc.FinalizedBlockNumber)]; ok {
+		return n, nil
+	}
+	return r.chain().tags[int64(rpc.LatestBlockNumber)], nil
+}
+
+func (r *ReplayEtherman) value(method Method) (Record, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	record, ok := r.values[method]
+	if !ok || record.NotFound {
+		return Record{}, etherman.ErrNotFound
+	}
+	return record, nil
+}
+
+// GetTrustedSequencerURL returns the last url recorded
+func (r *ReplayEtherman) GetTrustedSequencerURL() (string, error) {
+	record, err := r.value(MethodTrustedSequencerURL)
+	return record.String, err
+}
+
+// VerifyGenBlockNumber returns the last result recorded
+func (r *ReplayEtherman) VerifyGenBlockNumber(ctx context.Context, genBlockNumber uint64) (bool, error) {
+	record, err := r.value(MethodVerifyGenBlockNumber)
+	return record.Bool && record.Uint == genBlockNumber, err
+}
+
+// GetLatestVerifiedBatchNum returns the last verified batch recorded
+func (r *ReplayEtherman) GetLatestVerifiedBatchNum() (uint64, error) {
+	record, err := r.value(MethodLatestVerifiedBatchNum)
+	return record.Uint, err
+}
+
+// GetLatestBatchNumber returns the last sequenced batch recorded
+func (r *ReplayEtherman) GetLatestBatchNumber() (uint64, error) {
+	record, err := r.value(MethodLatestBatchNumber)
+	return record.Uint, err
+}
+
+// GetL1BlockUpgradeLxLy returns the LxLy upgrade block recorded
+func (r *ReplayEtherman) GetL1BlockUpgradeLxLy(ctx context.Context, genesisBlock uint64) (uint64, error) {
+	record, err := r.value(MethodL1BlockUpgradeLxLy)
+	return record.Uint, err
+}
+
+// GetForks returns the last fork ids recorded
+func (r *ReplayEtherman) GetForks(ctx context.Context, genBlockNumber uint64, lastL1BlockSynced uint64) ([]state.ForkIDInterval, error) {
+	record, err := r.value(MethodForks)
+	return record.Forks, err
+}
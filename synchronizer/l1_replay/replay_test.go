@@ -0,0 +1,195 @@
+package l1_replay
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	mock_syncinterfaces "github.com/0xPolygonHermez/zkevm-node/synchronizer/common/syncinterfaces/mocks"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type ethermanWithForks struct {
+	*mock_syncinterfaces.EthermanFullInterface
+}
+
+func (e *ethermanWithForks) GetForks(ctx context.Context, genBlockNumber uint64, lastL1BlockSynced uint64) ([]state.ForkIDInterval, error) {
+	return []state.ForkIDInterval{{FromBatchNumber: 0, ToBatchNumber: 100, ForkId: 9}}, nil
+}
+
+func newHeader(number uint64, parentHash common.Hash) *ethTypes.Header {
+	return &ethTypes.Header{Number: new(big.Int).SetUint64(number), ParentHash: parentHash, Difficulty: big.NewInt(0), Time: number * 12}
+}
+
+func newBlock(header *ethTypes.Header, batchNumber uint64) etherman.Block {
+	return etherman.Block{
+		BlockNumber:     header.Number.Uint64(),
+		BlockHash:       header.Hash(),
+		ParentHash:      header.ParentHash,
+		VerifiedBatches: []etherman.VerifiedBatch{{BlockNumber: header.Number.Uint64(), BatchNumber: batchNumber}},
+		ReceivedAt:      time.Unix(int64(header.Time), 0).UTC(),
+	}
+}
+
+// recordChain records the rollup info of 3 blocks with events: 10, 20 and 30
+func recordChain(t *testing.T, path string) []*ethTypes.Header {
+	ctx := context.Background()
+	h10 := newHeader(10, common.Hash{})
+	h20 := newHeader(20, h10.Hash())
+	h30 := newHeader(30, h20.Hash())
+	latest := newHeader(40, h30.Hash())
+	headers := map[uint64]*ethTypes.Header{10: h10, 20: h20, 30: h30, 40: latest}
+	blocks := []etherman.Block{newBlock(h10, 1), newBlock(h20, 2), newBlock(h30, 3)}
+	order := map[common.Hash][]etherman.Order{}
+	for i, b := range blocks {
+		order[b.BlockHash] = []etherman.Order{{Name: etherman.TrustedVerifyBatchOrder, Pos: i}}
+	}
+
+	m := mock_syncinterfaces.NewEthermanFullInterface(t)
+	m.EXPECT().HeaderByNumber(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, number *big.Int) (*ethTypes.Header, error) {
+		if number == nil {
+			return latest, nil
+		}
+		return headers[number.Uint64()], nil
+	})
+	m.EXPECT().GetRollupInfoByBlockRange(mock.Anything, uint64(10), mock.Anything).Return(blocks[:2], order, nil).Once()
+	m.EXPECT().GetRollupInfoByBlockRange(mock.Anything, uint64(20), mock.Anything).Return(blocks[1:], order, nil).Once()
+	m.EXPECT().GetLatestBatchNumber().Return(3, nil).Once()
+	m.EXPECT().GetL1BlockUpgradeLxLy(mock.Anything, uint64(10)).Return(0, etherman.ErrNotFound).Once()
+
+	writer, err := NewWriter(path)
+	require.NoError(t, err)
+	recorder := NewRecorder(&ethermanWithForks{m}, writer)
+	_, err = recorder.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	toBlock := uint64(25)
+	_, _, err = recorder.GetRollupInfoByBlockRange(ctx, 10, &toBlock)
+	require.NoError(t, err)
+	toBlock = 40
+	_, _, err = recorder.GetRollupInfoByBlockRange(ctx, 20, &toBlock)
+	require.NoError(t, err)
+	_, err = recorder.GetLatestBatchNumber()
+	require.NoError(t, err)
+	_, err = recorder.GetL1BlockUpgradeLxLy(ctx, 10)
+	require.ErrorIs(t, err, etherman.ErrNotFound)
+	_, err = recorder.GetForks(ctx, 10, 40)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return []*ethTypes.Header{h10, h20, h30, latest}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "l1.rec")
+	headers := recordChain(t, path)
+
+	replay, err := LoadReplayEtherman(path)
+	require.NoError(t, err)
+
+	header, err := replay.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, headers[3].Hash(), header.Hash())
+	// Tags not recorded return the latest block
+	header, err = replay.HeaderByNumber(ctx, big.NewInt(int64(rpc.SafeBlockNumber)))
+	require.NoError(t, err)
+	require.Equal(t, uint64(40), header.Number.Uint64())
+
+	// The rollup info doesn't depend on the ranges recorded
+	toBlock := uint64(40)
+	blocks, order, err := replay.GetRollupInfoByBlockRange(ctx, 0, &toBlock)
+	require.NoError(t, err)
+	require.Len(t, blocks, 3)
+	for i, b := range blocks {
+		require.Equal(t, headers[i].Hash(), b.BlockHash)
+		require.Equal(t, i, order[b.BlockHash][0].Pos)
+	}
+	block, err := replay.EthBlockByNumber(ctx, 20)
+	require.NoError(t, err)
+	require.Equal(t, headers[1].Hash(), block.Hash())
+	_, err = replay.EthBlockByNumber(ctx, 21)
+	require.ErrorIs(t, err, etherman.ErrNotFound)
+
+	batchNumber, err := replay.GetLatestBatchNumber()
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), batchNumber)
+	_, err = replay.GetL1BlockUpgradeLxLy(ctx, 10)
+	require.ErrorIs(t, err, etherman.ErrNotFound)
+	forks, err := replay.GetForks(ctx, 10, 40)
+	require.NoError(t, err)
+	require.Equal(t, uint64(9), forks[0].ForkId)
+	_, err = replay.GetTrustedSequencerURL()
+	require.ErrorIs(t, err, etherman.ErrNotFound)
+}
+
+func TestReplayInjectedReorg(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "l1.rec")
+	headers := recordChain(t, path)
+
+	replay, err := LoadReplayEtherman(path, Reorg{BlockNumber: 20, TriggerBlock: 30})
+	require.NoError(t, err)
+
+	toBlock := uint64(25)
+	blocks, _, err := replay.GetRollupInfoByBlockRange(ctx, 10, &toBlock)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+	require.Equal(t, headers[1].Hash(), blocks[1].BlockHash)
+
+	// Reading block 30 triggers the reorg once the rollup info is returned
+	toBlock = 40
+	blocks, _, err = replay.GetRollupInfoByBlockRange(ctx, 20, &toBlock)
+	require.NoError(t, err)
+	require.Equal(t, headers[1].Hash(), blocks[0].BlockHash)
+
+	blocks, order, err := replay.GetRollupInfoByBlockRange(ctx, 10, &toBlock)
+	require.NoError(t, err)
+	require.Len(t, blocks, 3)
+	require.Equal(t, headers[0].Hash(), blocks[0].BlockHash)
+	require.NotEqual(t, headers[1].Hash(), blocks[1].BlockHash)
+	require.Equal(t, headers[0].Hash(), blocks[1].ParentHash)
+	require.Equal(t, blocks[1].BlockHash, blocks[2].ParentHash)
+	require.Len(t, order[blocks[2].BlockHash], 1)
+
+	// The headers are consistent with the new blocks
+	block, err := replay.EthBlockByNumber(ctx, 30)
+	require.NoError(t, err)
+	require.Equal(t, blocks[2].BlockHash, block.Hash())
+	header, err := replay.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, headers[3].Hash(), header.Hash())
+}
+
+func TestReplayRecordedReorg(t *testing.T) {
+	ctx := context.Background()
+	h10 := newHeader(10, common.Hash{})
+	h20 := newHeader(20, h10.Hash())
+	h20b := newHeader(20, h10.Hash())
+	h20b.Extra = []byte("reorged")
+	toBlock := uint64(20)
+	records := []Record{
+		{Method: MethodRollupInfo, FromBlock: 10, ToBlock: &toBlock, Blocks: []etherman.Block{newBlock(h10, 1), newBlock(h20, 2)}},
+		{Method: MethodHeader, Number: h20.Number, Header: h20},
+		{Method: MethodHeader, Number: h20b.Number, Header: h20b},
+		{Method: MethodRollupInfo, FromBlock: 10, ToBlock: &toBlock, Blocks: []etherman.Block{newBlock(h10, 1), newBlock(h20b, 2)}},
+	}
+	replay, err := NewReplayEtherman(records)
+	require.NoError(t, err)
+
+	blocks, _, err := replay.GetRollupInfoByBlockRange(ctx, 10, &toBlock)
+	require.NoError(t, err)
+	require.Equal(t, h20.Hash(), blocks[1].BlockHash)
+	blocks, _, err = replay.GetRollupInfoByBlockRange(ctx, 10, &toBlock)
+	require.NoError(t, err)
+	require.Equal(t, h20b.Hash(), blocks[1].BlockHash)
+	header, err := replay.HeaderByNumber(ctx, big.NewInt(20))
+	require.NoError(t, err)
+	require.Equal(t, h20b.Hash(), header.Hash())
+}
@@ -72,6 +72,18 @@ var (
 		Usage:    "Print data as a JSON stream",
 		Required: false,
 	}
+	fromBatchFlag = cli.Uint64Flag{
+		Name:     "from-batch",
+		Aliases:  []string{"fb"},
+		Usage:    "Batch `NUMBER` to start from",
+		Required: false,
+	}
+	repairFlag = cli.BoolFlag{
+		Name:     "repair",
+		Aliases:  []string{"r"},
+		Usage:    "Truncate the file at the first mismatch and regenerate it from there",
+		Required: false,
+	}
 )
 
 type batch struct {
@@ -196,6 +208,17 @@ func main() {
 				&entryFlag,
 			},
 		},
+		{
+			Name:    "verify",
+			Aliases: []string{},
+			Usage:   "Verifies the stream file against the state",
+			Action:  verify,
+			Flags: []cli.Flag{
+				&configFileFlag,
+				&fromBatchFlag,
+				&repairFlag,
+			},
+		},
 		{
 			Name:    "dump-batch",
 			Aliases: []string{},
@@ -491,7 +514,7 @@ func decodeL2Block(cliCtx *cli.Context) error {
 	}
 
 	i := uint64(2) //nolint:gomnd
-	for secondEntry.Type == datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_TRANSACTION) {
+	for secondEntry.Type == datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_TRANSACTION) || secondEntry.Type == datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_RECEIPT) {
 		printEntry(secondEntry, shouldPrintJson)
 		entry, err := client.ExecCommandGetEntry(firstEntry.Number + i)
 		if err != nil {
@@ -583,7 +606,7 @@ func decodeL2BlockOffline(cliCtx *cli.Context) error {
 
 	i := uint64(2) //nolint:gomnd
 
-	for secondEntry.Type == datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_TRANSACTION) {
+	for secondEntry.Type == datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_TRANSACTION) || secondEntry.Type == datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_RECEIPT) {
 		printEntry(secondEntry, shouldPrintJson)
 		secondEntry, err = streamServer.GetEntry(firstEntry.Number + i)
 		if err != nil {
@@ -631,6 +654,105 @@ func truncate(cliCtx *cli.Context) error {
 	return nil
 }
 
+func verify(cliCtx *cli.Context) error {
+	c, err := config.Load(cliCtx)
+	if err != nil {
+		log.Error(err)
+		os.Exit(1)
+	}
+
+	log.Init(c.Log)
+
+	streamServer, err := initializeStreamServer(c)
+	if err != nil {
+		log.Error(err)
+		os.Exit(1)
+	}
+
+	// Connect to the database
+	stateSqlDB, err := db.NewSQLDB(c.StateDB)
+	if err != nil {
+		log.Error(err)
+		os.Exit(1)
+	}
+	defer stateSqlDB.Close()
+	stateDBStorage := pgstatestorage.NewPostgresStorage(state.Config{}, stateSqlDB)
+	log.Debug("Connected to the database")
+
+	var stateTree *merkletree.StateTree
+
+	// The merkle tree is only needed to regenerate the file for Fork ID <= Etrog
+	if cliCtx.Bool("repair") && c.MerkleTree.MaxThreads > 0 {
+		mtDBServerConfig := merkletree.Config{URI: c.MerkleTree.URI}
+		var mtDBCancel context.CancelFunc
+		mtDBServiceClient, mtDBClientConn, mtDBCancel := merkletree.NewMTDBServiceClient(cliCtx.Context, mtDBServerConfig)
+		defer func() {
+			mtDBCancel()
+			mtDBClientConn.Close()
+		}()
+		stateTree = merkletree.NewStateTree(mtDBServiceClient)
+		log.Debug("Connected to the merkle tree")
+	}
+
+	stateDB := state.NewState(state.Config{}, stateDBStorage, nil, stateTree, nil, nil, nil)
+
+	var fromEntry uint64 = 0
+	if cliCtx.IsSet("from-batch") {
+		bookMark := &datastream.BookMark{
+			Type:  datastream.BookmarkType_BOOKMARK_TYPE_BATCH,
+			Value: cliCtx.Uint64("from-batch"),
+		}
+
+		marshalledBookMark, err := proto.Marshal(bookMark)
+		if err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+
+		fromEntry, err = streamServer.GetBookmark(marshalledBookMark)
+		if err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+	}
+
+	mismatch, err := state.VerifyDataStreamFile(cliCtx.Context, streamServer, stateDB, fromEntry)
+	if err != nil {
+		log.Error(err)
+		os.Exit(1)
+	}
+
+	if mismatch == nil {
+		printColored(color.FgGreen, fmt.Sprintf("File matches the state from entry %d\n", fromEntry))
+		return nil
+	}
+
+	printColored(color.FgRed, fmt.Sprintf("Mismatch found at %s\n", mismatch))
+
+	if !cliCtx.Bool("repair") {
+		printColored(color.FgHiWhite, fmt.Sprintf("Truncate the file from entry %d to regenerate it\n", mismatch.TruncateEntryNumber))
+		return nil
+	}
+
+	err = streamServer.TruncateFile(mismatch.TruncateEntryNumber)
+	if err != nil {
+		log.Error(err)
+		os.Exit(1)
+	}
+
+	printColored(color.FgGreen, fmt.Sprintf("File truncated from entry %d\n", mismatch.TruncateEntryNumber))
+
+	err = state.GenerateDataStreamFile(cliCtx.Context, streamServer, stateDB, false, nil, c.Offline.ChainID, c.Offline.UpgradeEtrogBatchNumber, c.Offline.Version)
+	if err != nil {
+		log.Error(err)
+		os.Exit(1)
+	}
+
+	printColored(color.FgGreen, "File regenerated\n")
+
+	return nil
+}
+
 func decodeBatch(cliCtx *cli.Context) error {
 	var batchData = []byte{}
 	c, err := config.Load(cliCtx)
@@ -1084,6 +1206,30 @@ func printEntry(entry datastreamer.FileEntry, shouldPrintJson bool) {
 		if updateGer.Debug != nil && updateGer.Debug.Message != "" {
 			simpleEntry["Debug"] = updateGer.Debug
 		}
+
+	case datastreamer.EntryType(datastream.EntryType_ENTRY_TYPE_RECEIPT):
+		receipt := &datastream.Receipt{}
+		err := proto.Unmarshal(entry.Data, receipt)
+		if err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+
+		simpleEntry["Entry Type"] = "L2 Tx Receipt"
+		simpleEntry["Entry Number"] = fmt.Sprintf("%d", entry.Number)
+		simpleEntry["L2 Block Number"] = fmt.Sprintf("%d", receipt.L2BlockNumber)
+		simpleEntry["Index"] = fmt.Sprintf("%d", receipt.Index)
+		simpleEntry["Tx Hash"] = fmt.Sprintf("%s", common.BytesToHash(receipt.TxHash))
+		simpleEntry["Status"] = fmt.Sprintf("%d", receipt.Status)
+		simpleEntry["Cumulative Gas Used"] = fmt.Sprintf("%d", receipt.CumulativeGasUsed)
+		simpleEntry["Gas Used"] = fmt.Sprintf("%d", receipt.GasUsed)
+		simpleEntry["Contract Address"] = fmt.Sprintf("%s", common.BytesToAddress(receipt.ContractAddress))
+		simpleEntry["Effec. Gas Price"] = new(big.Int).SetBytes(receipt.EffectiveGasPrice).String()
+		simpleEntry["Logs"] = fmt.Sprintf("%d", len(receipt.Logs))
+
+		if receipt.Debug != nil && receipt.Debug.Message != "" {
+			simpleEntry["Debug"] = receipt.Debug
+		}
 	}
 
 	// why bother
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/etrogpolygonrollupmanager"
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor/reference"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/urfave/cli/v2"
+)
+
+const filePermissions = 0644
+
+var (
+	rpcFlag = cli.StringFlag{
+		Name:  "rpc",
+		Usage: "JSON RPC `URL` of the node the witness is requested to",
+		Value: "http://localhost:8123",
+	}
+	batchFlag = cli.Uint64Flag{
+		Name:  "batch",
+		Usage: "Batch `NUMBER`",
+	}
+	witnessFlag = cli.StringFlag{
+		Name:  "witness",
+		Usage: "Witness `FILE`, used instead of requesting it to the node",
+	}
+	outputFlag = cli.StringFlag{
+		Name:     "output",
+		Aliases:  []string{"o"},
+		Usage:    "Output `FILE`",
+		Required: true,
+	}
+	stateRootFlag = cli.StringFlag{
+		Name:  "stateRoot",
+		Usage: "Expected new state `ROOT`, used instead of reading the verified one from L1",
+	}
+	l1Flag = cli.StringFlag{
+		Name:  "l1",
+		Usage: "`URL` of the L1 node the verified state root is read from",
+	}
+	rollupManagerFlag = cli.StringFlag{
+		Name:  "rollupManager",
+		Usage: "`ADDRESS` of the rollup manager contract",
+	}
+	rollupFlag = cli.StringFlag{
+		Name:  "rollup",
+		Usage: "`ADDRESS` of the rollup contract",
+	}
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "WitnessTool"
+	app.Usage = "Gets the witness of a batch and verifies it executing the batch statelessly"
+	app.Commands = []*cli.Command{
+		{
+			Name:   "get",
+			Usage:  "get the witness of a batch from a node and write it to a file",
+			Action: get,
+			Flags:  []cli.Flag{&rpcFlag, &batchFlag, &outputFlag},
+		},
+		{
+			Name:   "verify",
+			Usage:  "execute a batch reading the state only from its witness and check the new state root against the one verified on L1",
+			Action: verify,
+			Flags:  []cli.Flag{&rpcFlag, &batchFlag, &witnessFlag, &stateRootFlag, &l1Flag, &rollupManagerFlag, &rollupFlag},
+		},
+	}
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Errorf("\nError: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func get(cliCtx *cli.Context) error {
+	witness, err := getWitness(cliCtx)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(witness, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(cliCtx.String(outputFlag.Name), data, filePermissions)
+	if err != nil {
+		return err
+	}
+	log.Infof("witness of batch %d written to %s: %d nodes, %d contracts", witness.BatchNumber, cliCtx.String(outputFlag.Name), len(witness.Db), len(witness.ContractsBytecode))
+	return nil
+}
+
+func verify(cliCtx *cli.Context) error {
+	witness, err := getWitness(cliCtx)
+	if err != nil {
+		return err
+	}
+	ctx := cliCtx.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// the new state root of the witness comes from the same node, so it's not trusted
+	var expectedStateRoot common.Hash
+	if root := cliCtx.String(stateRootFlag.Name); root != "" {
+		expectedStateRoot = common.HexToHash(root)
+	} else if cliCtx.String(l1Flag.Name) != "" {
+		expectedStateRoot, err = getL1StateRoot(ctx, cliCtx, uint64(witness.BatchNumber))
+		if err != nil {
+			return err
+		}
+	} else {
+		return fmt.Errorf("the expected state root is required, set the L1 flags to read the verified one or the state root flag")
+	}
+
+	response, err := reference.ExecuteBatchWitness(ctx, witness.ToState())
+	if err != nil {
+		return fmt.Errorf("failed to execute batch %d: %w", witness.BatchNumber, err)
+	}
+	if response.ErrorRom != executor.RomError_ROM_ERROR_NO_ERROR {
+		log.Warnf("batch %d executed with rom error %s", witness.BatchNumber, response.ErrorRom)
+	}
+
+	newStateRoot := common.BytesToHash(response.NewStateRoot)
+	if newStateRoot != expectedStateRoot {
+		return fmt.Errorf("new state root mismatch for batch %d, expected: %s, got: %s", witness.BatchNumber, expectedStateRoot, newStateRoot)
+	}
+	log.Infof("batch %d verified, new state root: %s", witness.BatchNumber, newStateRoot)
+	return nil
+}
+
+// getL1StateRoot reads the state root of a batch verified on L1 from the rollup manager
+func getL1StateRoot(ctx context.Context, cliCtx *cli.Context, batchNumber uint64) (common.Hash, error) {
+	if cliCtx.String(rollupManagerFlag.Name) == "" || cliCtx.String(rollupFlag.Name) == "" {
+		return common.Hash{}, fmt.Errorf("the rollup manager and rollup addresses are required to read the state root from L1")
+	}
+	ethClient, err := ethclient.DialContext(ctx, cliCtx.String(l1Flag.Name))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	defer ethClient.Close()
+	rollupManager, err := etrogpolygonrollupmanager.NewEtrogpolygonrollupmanager(common.HexToAddress(cliCtx.String(rollupManagerFlag.Name)), ethClient)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	rollupID, err := rollupManager.RollupAddressToID(opts, common.HexToAddress(cliCtx.String(rollupFlag.Name)))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	stateRoot, err := rollupManager.GetRollupBatchNumToStateRoot(opts, rollupID, batchNumber)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if stateRoot == [32]byte{} {
+		return common.Hash{}, fmt.Errorf("batch %d is not verified on L1", batchNumber)
+	}
+	return common.Hash(stateRoot), nil
+}
+
+// getWitness reads the witness from the file of the flag if it's set, or requests it to the node
+func getWitness(cliCtx *cli.Context) (*types.BatchWitness, error) {
+	var witness types.BatchWitness
+	if path := cliCtx.String(witnessFlag.Name); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &witness); err != nil {
+			return nil, err
+		}
+		return &witness, nil
+	}
+
+	if !cliCtx.IsSet(batchFlag.Name) {
+		return nil, fmt.Errorf("the batch number or the witness file is required")
+	}
+	batchNumber := cliCtx.Uint64(batchFlag.Name)
+	response, err := client.JSONRPCCall(cliCtx.String(rpcFlag.Name), "zkevm_getBatchWitness", hex.EncodeUint64(batchNumber))
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != nil {
+		return nil, fmt.Errorf("failed to get the witness of batch %d: %d - %s", batchNumber, response.Error.Code, response.Error.Message)
+	}
+	if string(response.Result) == "null" {
+		return nil, fmt.Errorf("batch %d not found", batchNumber)
+	}
+	if err := json.Unmarshal(response.Result, &witness); err != nil {
+		return nil, err
+	}
+	return &witness, nil
+}